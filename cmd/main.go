@@ -1,11 +1,30 @@
 package main
 
 import (
+	"context"
 	"go-api/controller"
 	"go-api/db"
 	_ "go-api/docs" // Importar a documentação Swagger
+	"go-api/internal/mail"
+	"go-api/internal/oidc"
+	"go-api/internal/password"
+	"go-api/internal/payment"
+	"go-api/internal/secretbox"
+	"go-api/internal/storage"
+	"go-api/internal/tax"
+	"go-api/internal/throttle"
+	"go-api/internal/util"
+	"go-api/middleware"
+	"go-api/model"
 	"go-api/repository"
 	"go-api/usecase"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
@@ -27,18 +46,78 @@ import (
 // @host localhost:8000
 // @BasePath /
 
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @description Token JWT obtido em /login, ou access token OAuth emitido em /oauth/token, no formato "Bearer <token>"
+
+// @securityDefinitions.apikey APIKeyAuth
+// @in header
+// @name X-API-Key
+// @description Chave de API criada em /api-keys, limitada aos seus escopos; também aceita como "Bearer <chave>"
+
 // @tag.name products
 // @tag.description Operações relacionadas a produtos
 
+// @tag.name categories
+// @tag.description Operações da taxonomia de categorias de produtos
+
+// @tag.name variants
+// @tag.description Tipos de opção (tamanho, cor) e variantes de produtos
+
+// @tag.name images
+// @tag.description Upload, ordem e imagem principal das imagens de produtos
+
+// @tag.name pricing
+// @tag.description Listas de preços e taxas de câmbio
+
+// @tag.name trash
+// @tag.description Lixeira de produtos e usuários excluídos: restauração e exclusão definitiva
+
+// @tag.name audit
+// @tag.description Log de auditoria das alterações em usuários, produtos e categorias
+
+// @tag.name cart
+// @tag.description Carrinho de compras do usuário autenticado ou da sessão anônima
+
+// @tag.name orders
+// @tag.description Checkout e pedidos, com o ciclo de status pending, paid, shipped, delivered, cancelled e refunded
+
+// @tag.name promotions
+// @tag.description Cupons de desconto e promoções automáticas, com regras de elegibilidade, limites de uso e acúmulo
+
+// @tag.name taxes
+// @tag.description Categorias fiscais dos produtos e tabelas de alíquotas por país e estado
+
 // @tag.name users
 // @tag.description Operações relacionadas a usuários
 
+// @tag.name auth
+// @tag.description Recuperação de acesso, verificação de email, autenticação em dois fatores e bloqueio de logins após falhas repetidas
+
 // @tag.name health
 // @tag.description Endpoints de verificação de saúde da API
 
 func main() {
 	server := gin.Default()
 
+	// JWT_SECRET assina os tokens de acesso, que carregam o papel do usuário; é obrigatória e precisa de 32 bytes ou mais
+	if err := util.SetSecretKey(os.Getenv("JWT_SECRET")); err != nil {
+		panic("JWT_SECRET: " + err.Error())
+	}
+
+	// TRUSTED_PROXIES (ex.: 10.0.0.0/8,172.16.0.0/12) lista os proxies cujo
+	// X-Forwarded-For é aceito; sem ele o IP registrado é o da conexão
+	var trustedProxies []string
+	if proxies := os.Getenv("TRUSTED_PROXIES"); proxies != "" {
+		trustedProxies = strings.Split(proxies, ",")
+	}
+	if err := server.SetTrustedProxies(trustedProxies); err != nil {
+		panic(err)
+	}
+	// Identifica cada requisição (X-Request-ID, IP, user agent, usuário) para a auditoria
+	server.Use(middleware.RequestContext())
+
 	// Usar a nova configuração
 	dbConfig := db.NewConfig()
 	dbConnection, err := db.ConnectDB(dbConfig)
@@ -46,16 +125,251 @@ func main() {
 		panic(err)
 	}
 
+	// Armazenamento de arquivos (imagens)
+	storageConfig := storage.NewConfig()
+	blobStore, err := storage.New(storageConfig)
+	if err != nil {
+		panic(err)
+	}
+
+	// Provedor de pagamentos
+	paymentGateway, err := payment.New(payment.NewConfig())
+	if err != nil {
+		panic(err)
+	}
+
+	// Envio de emails (MAIL_DRIVER: smtp, file ou memory)
+	mailer, err := mail.New(mail.NewConfig())
+	if err != nil {
+		panic(err)
+	}
+
+	// THROTTLE_STORE (memory ou postgres) guarda as falhas de login; com várias instâncias da API, use postgres
+	throttleStore, err := throttle.New(throttle.NewConfig(), dbConnection)
+	if err != nil {
+		panic(err)
+	}
+
+	// PASSWORD_HASH_ALGORITHM (argon2id ou bcrypt) e PASSWORD_ARGON2_MEMORY, _ITERATIONS, _PARALLELISM ou PASSWORD_BCRYPT_COST
+	// definem os novos hashes; os antigos continuam valendo e são refeitos no login
+	passwordHasher, err := password.New(password.NewConfig())
+	if err != nil {
+		panic(err)
+	}
+
+	// Tabela de alíquotas (TAX_RATES_FILE, padrão db/tax_rates.json)
+	taxTable, err := tax.LoadFile(tax.NewConfig().RatesFile)
+	if err != nil {
+		panic(err)
+	}
+
 	// Product
 	ProductRepository := repository.NewProductRepository(dbConnection)
-	ProductUsecase := usecase.NewProductUsecase(ProductRepository)
+	PricingRepository := repository.NewPricingRepository(dbConnection)
+	VariantRepository := repository.NewVariantRepository(dbConnection)
+	ImageRepository := repository.NewImageRepository(dbConnection)
+	ProductUsecase := usecase.NewProductUsecase(ProductRepository, PricingRepository, VariantRepository, ImageRepository, blobStore)
 	ProductController := controller.NewProductController(ProductUsecase)
 
+	// Import
+	ImportUsecase := usecase.NewImportUsecase(ProductRepository)
+	ImportController := controller.NewImportController(ImportUsecase)
+
+	// Image
+	ImageUsecase := usecase.NewImageUsecase(ImageRepository, ProductRepository, blobStore, usecase.DefaultImageLimits)
+	ImageController := controller.NewImageController(ImageUsecase, usecase.DefaultImageLimits.MaxBytes)
+
+	// Variant
+	VariantUsecase := usecase.NewVariantUsecase(VariantRepository, ProductRepository)
+	VariantController := controller.NewVariantController(VariantUsecase)
+
+	// Pricing
+	PricingUsecase := usecase.NewPricingUsecase(PricingRepository, ProductRepository)
+	PricingController := controller.NewPricingController(PricingUsecase)
+
+	// Category
+	CategoryRepository := repository.NewCategoryRepository(dbConnection)
+	CategoryUsecase := usecase.NewCategoryUsecase(CategoryRepository, ProductRepository)
+	CategoryController := controller.NewCategoryController(CategoryUsecase)
+
+	// Promotion
+	PromotionRepository := repository.NewPromotionRepository(dbConnection)
+	PromotionUsecase := usecase.NewPromotionUsecase(PromotionRepository, ProductRepository, CategoryRepository)
+	PromotionController := controller.NewPromotionController(PromotionUsecase)
+
+	// Tax
+	TaxUsecase := usecase.NewTaxUsecase(taxTable, ProductRepository)
+	TaxController := controller.NewTaxController(TaxUsecase)
+
+	// Cart
+	CartRepository := repository.NewCartRepository(dbConnection)
+	CartUsecase := usecase.NewCartUsecase(CartRepository, ProductRepository, VariantRepository, PromotionUsecase, TaxUsecase)
+	CartController := controller.NewCartController(CartUsecase)
+
+	// Order
+	OrderRepository := repository.NewOrderRepository(dbConnection)
+	OrderUsecase := usecase.NewOrderUsecase(OrderRepository, CartRepository, ProductRepository, VariantRepository, PromotionUsecase, TaxUsecase)
+	OrderController := controller.NewOrderController(OrderUsecase)
+
+	// Payment
+	PaymentRepository := repository.NewPaymentRepository(dbConnection)
+	PaymentUsecase := usecase.NewPaymentUsecase(PaymentRepository, OrderUsecase, paymentGateway)
+	PaymentController := controller.NewPaymentController(PaymentUsecase)
+
+	// Login throttle
+	// LOGIN_LOCKOUT_FAILURES e LOGIN_LOCKOUT_DURATION (ex.: 30m) ajustam o bloqueio das contas após falhas de login
+	loginThrottlePolicy := usecase.DefaultLoginThrottlePolicy
+	if failures, err := strconv.Atoi(os.Getenv("LOGIN_LOCKOUT_FAILURES")); err == nil && failures > 0 {
+		loginThrottlePolicy.Account.LockoutFailures = failures
+	}
+	if duration, err := time.ParseDuration(os.Getenv("LOGIN_LOCKOUT_DURATION")); err == nil && duration > 0 {
+		loginThrottlePolicy.Account.LockoutDuration = duration
+	}
+	AuditRepository := repository.NewAuditRepository(dbConnection)
+	LoginThrottleUsecase := usecase.NewLoginThrottleUsecase(throttleStore, AuditRepository, loginThrottlePolicy)
+	LoginThrottleController := controller.NewLoginThrottleController(LoginThrottleUsecase)
+
 	// User
 	UserRepository := repository.NewUserRepository(dbConnection)
-	UserUsecase := usecase.NewUserUsecase(UserRepository)
+	// USER_EMAIL_REUSE_AFTER (ex.: 720h) libera o email de usuários na lixeira após o período
+	userPolicy := usecase.DefaultUserPolicy
+	if reuseAfter, err := time.ParseDuration(os.Getenv("USER_EMAIL_REUSE_AFTER")); err == nil {
+		userPolicy.EmailReuseAfter = reuseAfter
+	}
+	// PASSWORD_MIN_LENGTH muda o tamanho mínimo das senhas; PASSWORD_REJECT_COMMON=false aceita senhas comuns
+	// e PASSWORD_REJECT_PERSONAL=false aceita senhas com o email ou o nome do usuário
+	passwordPolicy := password.DefaultPolicy
+	if minLength, err := strconv.Atoi(os.Getenv("PASSWORD_MIN_LENGTH")); err == nil && minLength > 0 {
+		passwordPolicy.MinLength = minLength
+	}
+	passwordPolicy.RejectCommon = os.Getenv("PASSWORD_REJECT_COMMON") != "false"
+	passwordPolicy.RejectPersonal = os.Getenv("PASSWORD_REJECT_PERSONAL") != "false"
+	userPolicy.Password = passwordPolicy
+	// USER_HIDE_TAKEN_EMAILS=true responde igual a todo cadastro válido e avisa por email o dono de um email já cadastrado
+	userPolicy.HideTakenEmails = os.Getenv("USER_HIDE_TAKEN_EMAILS") == "true"
+	// EMAIL_VERIFICATION_SECRET liga a verificação de email e assina os links; EMAIL_VERIFICATION_URL recebe o token,
+	// EMAIL_VERIFICATION_TTL (ex.: 24h) é a validade do link e EMAIL_VERIFICATION_REQUIRED=true bloqueia o login sem verificação
+	if secret := os.Getenv("EMAIL_VERIFICATION_SECRET"); secret != "" {
+		verification := usecase.DefaultEmailVerification
+		verification.Secret = []byte(secret)
+		verification.ResendLimiter = throttle.NewLimiter(throttleStore, usecase.DefaultVerificationResendLimit)
+		if verifyURL := os.Getenv("EMAIL_VERIFICATION_URL"); verifyURL != "" {
+			verification.URL = verifyURL
+		}
+		if ttl, err := time.ParseDuration(os.Getenv("EMAIL_VERIFICATION_TTL")); err == nil && ttl > 0 {
+			verification.TTL = ttl
+		}
+		if interval, err := time.ParseDuration(os.Getenv("EMAIL_VERIFICATION_RESEND_INTERVAL")); err == nil {
+			verification.ResendInterval = interval
+		}
+		verification.AllowUnverifiedLogin = os.Getenv("EMAIL_VERIFICATION_REQUIRED") != "true"
+		userPolicy.Verification = &verification
+	}
+	// MFA_REQUIRED_ROLES (ex.: admin) lista os papéis que só acessam as rotas protegidas com autenticação em dois fatores
+	var mfaRequiredRoles []string
+	for _, role := range strings.Split(os.Getenv("MFA_REQUIRED_ROLES"), ",") {
+		if role = strings.TrimSpace(role); role != "" {
+			mfaRequiredRoles = append(mfaRequiredRoles, role)
+		}
+	}
+	userPolicy.MFARequiredRoles = mfaRequiredRoles
+	UserUsecase := usecase.NewUserUsecase(UserRepository, passwordHasher, userPolicy, CartUsecase, LoginThrottleUsecase)
 	UserController := controller.NewUserController(UserUsecase)
 
+	// MFA
+	// MFA_ENCRYPTION_KEY cifra os segredos TOTP no banco e é obrigatória; só com APP_ENV=development a API
+	// sobe sem ela, usando uma chave de desenvolvimento pública. MFA_ISSUER é o nome exibido nos aplicativos autenticadores
+	mfaKey := os.Getenv("MFA_ENCRYPTION_KEY")
+	if mfaKey == "" {
+		if os.Getenv("APP_ENV") != "development" {
+			panic("MFA_ENCRYPTION_KEY is required; set APP_ENV=development to use the development key")
+		}
+		log.Print("WARNING: MFA_ENCRYPTION_KEY is not set; TOTP secrets are encrypted with the public development key")
+		mfaKey = "dev-mfa-encryption-key"
+	}
+	mfaBox, err := secretbox.New(mfaKey)
+	if err != nil {
+		panic(err)
+	}
+	mfaPolicy := usecase.DefaultMFAPolicy
+	if issuer := os.Getenv("MFA_ISSUER"); issuer != "" {
+		mfaPolicy.Issuer = issuer
+	}
+	MFARepository := repository.NewMFARepository(dbConnection)
+	MFAUsecase := usecase.NewMFAUsecase(MFARepository, UserRepository, mfaBox, mfaPolicy, CartUsecase, LoginThrottleUsecase)
+	MFAController := controller.NewMFAController(MFAUsecase)
+
+	// OIDC: OIDC_PROVIDERS (ex.: corp,google) lista os provedores de identidade, cada um configurado por
+	// OIDC_<NOME>_ISSUER, _CLIENT_ID, _CLIENT_SECRET, _REDIRECT_URL e _SCOPES; OIDC_<NOME>_LINK_BY_EMAIL=true
+	// vincula no primeiro login a conta com o mesmo email verificado e OIDC_STATE_TTL (ex.: 10m) é o prazo do login
+	oidcPolicy := usecase.DefaultOIDCPolicy
+	oidcPolicy.MFARequiredRoles = mfaRequiredRoles
+	if ttl, err := time.ParseDuration(os.Getenv("OIDC_STATE_TTL")); err == nil && ttl > 0 {
+		oidcPolicy.StateTTL = ttl
+	}
+	oidcProviders := map[string]usecase.OIDCProvider{}
+	for _, name := range strings.Split(os.Getenv("OIDC_PROVIDERS"), ",") {
+		if name = strings.TrimSpace(name); name == "" {
+			continue
+		}
+		provider, err := oidc.New(oidc.NewConfig(name))
+		if err != nil {
+			panic(err)
+		}
+		oidcProviders[name] = provider
+		if os.Getenv("OIDC_"+strings.ToUpper(name)+"_LINK_BY_EMAIL") == "true" {
+			oidcPolicy.LinkByEmail = append(oidcPolicy.LinkByEmail, name)
+		}
+	}
+	OIDCRepository := repository.NewOIDCRepository(dbConnection)
+	OIDCUsecase := usecase.NewOIDCUsecase(OIDCRepository, UserRepository, MFARepository, oidcProviders, oidcPolicy, CartUsecase)
+	OIDCController := controller.NewOIDCController(OIDCUsecase)
+
+	// API keys: integrações chamam as rotas de produtos e pedidos sem passar pelo /login
+	APIKeyRepository := repository.NewAPIKeyRepository(dbConnection)
+	APIKeyUsecase := usecase.NewAPIKeyUsecase(APIKeyRepository, UserRepository)
+	APIKeyController := controller.NewAPIKeyController(APIKeyUsecase)
+
+	// Servidor OAuth2: OAUTH_CODE_TTL (ex.: 1m) é o prazo para trocar o código de autorização e
+	// OAUTH_ACCESS_TOKEN_TTL (ex.: 1h) a validade dos access tokens emitidos aos clientes
+	oauthPolicy := usecase.DefaultOAuthPolicy
+	if ttl, err := time.ParseDuration(os.Getenv("OAUTH_CODE_TTL")); err == nil && ttl > 0 {
+		oauthPolicy.CodeTTL = ttl
+	}
+	if ttl, err := time.ParseDuration(os.Getenv("OAUTH_ACCESS_TOKEN_TTL")); err == nil && ttl > 0 {
+		oauthPolicy.AccessTokenTTL = ttl
+	}
+	OAuthRepository := repository.NewOAuthRepository(dbConnection)
+	OAuthUsecase := usecase.NewOAuthUsecase(OAuthRepository, UserRepository, UserUsecase, oauthPolicy)
+	OAuthController := controller.NewOAuthController(OAuthUsecase)
+
+	// Outbox: os emails gravados junto com as mudanças são entregues em segundo plano
+	OutboxRepository := repository.NewOutboxRepository(dbConnection)
+	OutboxUsecase := usecase.NewOutboxUsecase(OutboxRepository, mailer, usecase.DefaultOutboxPolicy)
+	outboxInterval := 10 * time.Second
+	if interval, err := time.ParseDuration(os.Getenv("MAIL_OUTBOX_INTERVAL")); err == nil && interval > 0 {
+		outboxInterval = interval
+	}
+	go usecase.RunOutbox(context.Background(), OutboxUsecase, outboxInterval)
+
+	// Password reset
+	// PASSWORD_RESET_URL é a página do front-end que recebe o token; PASSWORD_RESET_TTL (ex.: 1h) a validade do link
+	passwordResetPolicy := usecase.DefaultPasswordResetPolicy
+	if resetURL := os.Getenv("PASSWORD_RESET_URL"); resetURL != "" {
+		passwordResetPolicy.ResetURL = resetURL
+	}
+	if ttl, err := time.ParseDuration(os.Getenv("PASSWORD_RESET_TTL")); err == nil && ttl > 0 {
+		passwordResetPolicy.TokenTTL = ttl
+	}
+	passwordResetPolicy.Password = passwordPolicy
+	PasswordResetRepository := repository.NewPasswordResetRepository(dbConnection)
+	PasswordResetUsecase := usecase.NewPasswordResetUsecase(PasswordResetRepository, UserRepository, passwordHasher, passwordResetPolicy, throttleStore)
+	PasswordResetController := controller.NewPasswordResetController(PasswordResetUsecase)
+
+	// Audit
+	AuditUsecase := usecase.NewAuditUsecase(AuditRepository)
+	AuditController := controller.NewAuditController(AuditUsecase)
+
 	// Swagger documentation endpoint
 	server.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
@@ -71,20 +385,183 @@ func main() {
 		ctx.JSON(200, gin.H{"message": "Pong"})
 	})
 
+	// O armazenamento local é servido pela própria API no caminho de STORAGE_BASE_URL
+	if localStore, ok := blobStore.(*storage.LocalStore); ok {
+		baseURL, err := url.Parse(storageConfig.BaseURL)
+		if err != nil {
+			panic(err)
+		}
+		server.GET(baseURL.Path+"/*key", gin.WrapH(http.StripPrefix(baseURL.Path, localStore)))
+	}
+
 	// Product routes
 	server.GET("/products", ProductController.GetProducts)
-	server.POST("/product", ProductController.CreateProduct)
 	server.GET("/products/:productId", ProductController.GetProductById)
+	server.GET("/products/:productId/prices", ProductController.GetProductPrices)
+
+	// Category routes
+	server.GET("/categories", CategoryController.GetCategories)
+	server.GET("/categories/:categoryId", CategoryController.GetCategoryByID)
+	server.GET("/products/:productId/categories", CategoryController.GetProductCategories)
+
+	// Variant routes
+	server.GET("/option-types", VariantController.GetOptionTypes)
+	server.GET("/products/:productId/variants", VariantController.GetVariants)
+
+	// Image routes
+	server.GET("/products/:productId/images", ImageController.GetProductImages)
+
+	// Pricing routes
+	server.GET("/price-lists", PricingController.GetPriceLists)
+	server.GET("/price-lists/:priceListId/items", PricingController.GetPriceListItems)
+	server.GET("/exchange-rates", PricingController.GetExchangeRates)
+
+	// Tax routes
+	server.GET("/tax/categories", TaxController.GetTaxCategories)
+	server.GET("/tax/rates", TaxController.GetTaxRates)
+
+	// Admin routes
+	admin := server.Group("/", middleware.AuthRequired(nil, nil), middleware.RequireRole(model.RoleAdmin), middleware.RequireMFA(mfaRequiredRoles...))
+	admin.GET("/users", UserController.GetUsers)
+	admin.DELETE("/users/:userId", UserController.DeleteUser)
+	admin.POST("/option-type", VariantController.CreateOptionType)
+	admin.POST("/option-types/:optionTypeId/values", VariantController.AddOptionValue)
+	admin.POST("/price-list", PricingController.CreatePriceList)
+	admin.PUT("/price-lists/:priceListId/items/:productId", PricingController.SetPriceListItem)
+	admin.DELETE("/price-lists/:priceListId/items/:productId", PricingController.DeletePriceListItem)
+	admin.PUT("/exchange-rates", PricingController.SetExchangeRates)
+	admin.POST("/exchange-rates/import", PricingController.ImportExchangeRates)
+	admin.GET("/users/export", UserController.ExportUsers)
+
+	admin.POST("/api-keys", APIKeyController.CreateAPIKey)
+	admin.GET("/api-keys", APIKeyController.GetAPIKeys)
+	admin.POST("/api-keys/:keyId/rotate", APIKeyController.RotateAPIKey)
+	admin.DELETE("/api-keys/:keyId", APIKeyController.RevokeAPIKey)
+	admin.POST("/oauth/clients", OAuthController.CreateClient)
+	admin.GET("/oauth/clients", OAuthController.GetClients)
+	admin.DELETE("/oauth/clients/:clientId", OAuthController.RevokeClient)
+
+	// Trash routes
+	admin.GET("/trash/products", ProductController.GetDeletedProducts)
+	admin.POST("/trash/products/:productId/restore", ProductController.RestoreProduct)
+	admin.DELETE("/trash/products/:productId", ProductController.PurgeProduct)
+	admin.GET("/trash/users", UserController.GetDeletedUsers)
+	admin.POST("/trash/users/:userId/restore", UserController.RestoreUser)
+	admin.DELETE("/trash/users/:userId", UserController.PurgeUser)
+
+	// Audit routes
+	admin.GET("/audit", AuditController.GetAuditEvents)
+	admin.GET("/audit/verify", AuditController.VerifyAuditChain)
+	admin.DELETE("/auth/lockouts", LoginThrottleController.Unlock)
+
+	// Order routes
+	admin.GET("/orders/:orderId/payments", PaymentController.GetOrderPayments)
+	admin.POST("/orders/:orderId/refund", PaymentController.RefundOrder)
+
+	// Integration routes: admins com JWT, ou chaves de API e access tokens OAuth de
+	// admins e contas de serviço; cada rota exige da credencial o escopo correspondente
+	integration := server.Group("/", middleware.AuthRequired(APIKeyUsecase, OAuthUsecase), middleware.RequireRole(model.RoleAdmin, model.RoleService), middleware.RequireMFA(mfaRequiredRoles...))
+	productsRead := middleware.RequireScope(model.ScopeProductsRead)
+	productsWrite := middleware.RequireScope(model.ScopeProductsWrite)
+	ordersRead := middleware.RequireScope(model.ScopeOrdersRead)
+	ordersWrite := middleware.RequireScope(model.ScopeOrdersWrite)
+	integration.POST("/product", productsWrite, ProductController.CreateProduct)
+	integration.GET("/products/export", productsRead, ProductController.ExportProducts)
+	integration.POST("/products/import", productsWrite, ImportController.ImportProducts)
+	integration.GET("/products/import/:jobId", productsRead, ImportController.GetImportJob)
+	integration.DELETE("/products/:productId", productsWrite, ProductController.DeleteProduct)
+	integration.POST("/products/:productId/prices", productsWrite, ProductController.ScheduleProductPrice)
+	integration.POST("/products/:productId/variants", productsWrite, VariantController.CreateVariant)
+	integration.PUT("/products/:productId/variants/:variantId", productsWrite, VariantController.UpdateVariant)
+	integration.DELETE("/products/:productId/variants/:variantId", productsWrite, VariantController.DeleteVariant)
+	integration.POST("/products/:productId/images", productsWrite, ImageController.UploadProductImage)
+	integration.PUT("/products/:productId/images/order", productsWrite, ImageController.ReorderProductImages)
+	integration.POST("/products/:productId/images/:imageId/primary", productsWrite, ImageController.SetPrimaryImage)
+	integration.DELETE("/products/:productId/images/:imageId", productsWrite, ImageController.DeleteProductImage)
+	integration.PUT("/products/:productId/tax-category", productsWrite, TaxController.SetProductTaxCategory)
+	integration.PUT("/products/:productId/categories", productsWrite, CategoryController.SetProductCategories)
+	integration.POST("/category", productsWrite, CategoryController.CreateCategory)
+	integration.PUT("/categories/:categoryId", productsWrite, CategoryController.UpdateCategory)
+	integration.POST("/categories/:categoryId/move", productsWrite, CategoryController.MoveCategory)
+	integration.DELETE("/categories/:categoryId", productsWrite, CategoryController.DeleteCategory)
+	integration.GET("/orders", ordersRead, OrderController.GetOrders)
+	integration.GET("/orders/:orderId", ordersRead, OrderController.GetOrder)
+	integration.POST("/orders/:orderId/status", ordersWrite, OrderController.TransitionOrder)
+
+	// Promotion routes
+	admin.GET("/promotions", PromotionController.GetPromotions)
+	admin.POST("/promotions", PromotionController.CreatePromotion)
+	admin.GET("/promotions/:promotionId", PromotionController.GetPromotion)
+	admin.PUT("/promotions/:promotionId/active", PromotionController.SetPromotionActive)
+
+	// Cart routes: o usuário autenticado usa o próprio carrinho, visitantes o do X-Cart-Token
+	cart := server.Group("/cart", middleware.AuthOptional(nil, nil))
+	cart.GET("", CartController.GetCart)
+	cart.POST("/items", CartController.AddCartItem)
+	cart.PUT("/items/:itemId", CartController.UpdateCartItem)
+	cart.DELETE("/items/:itemId", CartController.RemoveCartItem)
+	cart.GET("/pricing", CartController.GetCartPricing)
+
+	// Checkout routes: o cliente autenticado compra e acompanha os próprios pedidos
+	customer := server.Group("/", middleware.AuthRequired(nil, nil))
+	customer.POST("/checkout", OrderController.Checkout)
+	customer.GET("/me/identities", OIDCController.GetIdentities)
+	customer.GET("/me/oauth/consents", OAuthController.GetConsents)
+	customer.DELETE("/me/oauth/consents/:clientId", OAuthController.RevokeConsent)
+
+	// Delegated routes: também aceitam access tokens de aplicações autorizadas pelo cliente,
+	// limitados aos escopos concedidos
+	delegated := server.Group("/me/orders", middleware.AuthRequired(nil, OAuthUsecase))
+	delegated.GET("", ordersRead, OrderController.GetMyOrders)
+	delegated.GET("/:orderId", ordersRead, OrderController.GetMyOrder)
+	delegated.POST("/:orderId/cancel", ordersWrite, OrderController.CancelMyOrder)
+	delegated.POST("/:orderId/pay", ordersWrite, PaymentController.PayOrder)
+
+	// Webhook do provedor de pagamentos: autenticado pela assinatura do corpo
+	server.POST("/payments/webhook", PaymentController.HandlePaymentWebhook)
 
 	// User routes
 	server.POST("/user", UserController.CreateUser)
-	server.GET("/users/:userId", UserController.GetUserByID)
-	server.PUT("/users/:userId", UserController.UpdateUser)
-	server.DELETE("/users/:userId", UserController.DeleteUser)
-	server.GET("/users", UserController.GetUsers)
+
+	// Account routes: o usuário vê e altera a própria conta e admins qualquer uma
+	account := server.Group("/users", middleware.AuthRequired(nil, nil), middleware.RequireMFA(mfaRequiredRoles...))
+	account.GET("/:userId", UserController.GetUserByID)
+	account.PUT("/:userId", UserController.UpdateUser)
 
 	// Login route
 	server.POST("/login", UserController.Login)
 
+	// Password reset routes
+	server.POST("/auth/password/forgot", PasswordResetController.ForgotPassword)
+	server.POST("/auth/password/reset", PasswordResetController.ResetPassword)
+	server.GET("/auth/email/verify", UserController.VerifyEmail)
+	server.POST("/auth/email/resend", UserController.ResendVerification)
+
+	// Two-factor routes: o desafio do login é trocado pelo token em /auth/mfa/verify
+	server.POST("/auth/mfa/verify", MFAController.Verify)
+	mfa := server.Group("/auth/mfa", middleware.AuthRequired(nil, nil))
+	mfa.POST("/enroll", MFAController.Enroll)
+	mfa.POST("/confirm", MFAController.Confirm)
+	mfa.POST("/recovery-codes", MFAController.RegenerateRecoveryCodes)
+	mfa.DELETE("", MFAController.Disable)
+
+	// OIDC routes: o callback também vincula a identidade quando o login foi iniciado por /link, com o mesmo token
+	server.GET("/auth/oidc/:provider/authorize", OIDCController.Authorize)
+	oidcCallback := server.Group("/auth/oidc/:provider/callback", middleware.AuthOptional(nil, nil))
+	oidcCallback.GET("", OIDCController.Callback)
+	oidcCallback.POST("", OIDCController.Callback)
+	oidcLink := server.Group("/auth/oidc/:provider/link", middleware.AuthRequired(nil, nil))
+	oidcLink.POST("", OIDCController.Link)
+	oidcLink.DELETE("", OIDCController.Unlink)
+
+	// OAuth routes: a página de consentimento valida o pedido em GET /oauth/authorize e o responde em
+	// POST, com o token do usuário ou email e senha; os clientes se autenticam nos demais endpoints
+	oauthAuthorize := server.Group("/oauth/authorize", middleware.AuthOptional(nil, nil))
+	oauthAuthorize.GET("", OAuthController.GetAuthorization)
+	oauthAuthorize.POST("", OAuthController.Authorize)
+	server.POST("/oauth/token", OAuthController.Token)
+	server.POST("/oauth/introspect", OAuthController.Introspect)
+	server.POST("/oauth/revoke", OAuthController.Revoke)
+
 	server.Run(":8000")
 }
@@ -0,0 +1,163 @@
+package controller
+
+import (
+	"errors"
+	"go-api/dto"
+	"go-api/usecase"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// APIKeyController handles HTTP requests for the API keys
+type APIKeyController struct {
+	apiKeyUsecase usecase.APIKeyUsecase
+}
+
+// NewAPIKeyController creates a new APIKeyController
+func NewAPIKeyController(usecase usecase.APIKeyUsecase) *APIKeyController {
+	return &APIKeyController{
+		apiKeyUsecase: usecase,
+	}
+}
+
+// CreateAPIKey godoc
+// @Summary Create an API key
+// @Description Create a key acting as an admin or a service account, limited to its scopes and, optionally, to some IPs and until a date. The key is returned only once; send it in the X-API-Key header or as a bearer token
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param apiKey body dto.CreateAPIKeyRequest true "Owner and limits of the key"
+// @Success 201 {object} dto.APIKeySecretResponse "Key created"
+// @Failure 400 {object} model.Response "Bad request - Invalid scope, allowed IP or expiry, or owner not an admin or service account"
+// @Failure 401 {object} model.Response "Missing or invalid token"
+// @Failure 403 {object} model.Response "Admin role required"
+// @Failure 404 {object} model.Response "User not found"
+// @Failure 500 {object} model.Response "Internal server error"
+// @Router /api-keys [post]
+func (ac *APIKeyController) CreateAPIKey(ctx *gin.Context) {
+	var req dto.CreateAPIKeyRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	response, err := ac.apiKeyUsecase.CreateAPIKey(ctx.Request.Context(), req)
+	if err != nil {
+		ctx.JSON(apiKeyErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, response)
+}
+
+// GetAPIKeys godoc
+// @Summary List API keys
+// @Description List the API keys, revoked ones included, with their last use; never their secrets
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Param user_id query int false "Only the keys of this user" minimum(1)
+// @Success 200 {array} dto.APIKeyResponse "API keys"
+// @Failure 400 {object} model.Response "Bad request - Invalid user_id"
+// @Failure 401 {object} model.Response "Missing or invalid token"
+// @Failure 403 {object} model.Response "Admin role required"
+// @Failure 500 {object} model.Response "Internal server error"
+// @Router /api-keys [get]
+func (ac *APIKeyController) GetAPIKeys(ctx *gin.Context) {
+	var userID int
+	if value := ctx.Query("user_id"); value != "" {
+		var err error
+		if userID, err = strconv.Atoi(value); err != nil || userID <= 0 {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "user_id must be a positive integer"})
+			return
+		}
+	}
+
+	keys, err := ac.apiKeyUsecase.GetAPIKeys(userID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, keys)
+}
+
+// RotateAPIKey godoc
+// @Summary Rotate an API key
+// @Description Give the key a new secret, keeping its owner, scopes, allowed IPs and expiry. The old secret stops working at once; the new key is returned only once
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Param keyId path int true "API key ID" minimum(1)
+// @Success 200 {object} dto.APIKeySecretResponse "Key rotated"
+// @Failure 400 {object} model.Response "Bad request - Invalid ID format"
+// @Failure 401 {object} model.Response "Missing or invalid token"
+// @Failure 403 {object} model.Response "Admin role required"
+// @Failure 404 {object} model.Response "API key not found"
+// @Failure 409 {object} model.Response "API key revoked"
+// @Failure 500 {object} model.Response "Internal server error"
+// @Router /api-keys/{keyId}/rotate [post]
+func (ac *APIKeyController) RotateAPIKey(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("keyId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid API key ID"})
+		return
+	}
+
+	response, err := ac.apiKeyUsecase.RotateAPIKey(ctx.Request.Context(), id)
+	if err != nil {
+		ctx.JSON(apiKeyErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// RevokeAPIKey godoc
+// @Summary Revoke an API key
+// @Description Turn the key off for good. It stays listed, with the time it was revoked
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Param keyId path int true "API key ID" minimum(1)
+// @Success 204 "Key revoked"
+// @Failure 400 {object} model.Response "Bad request - Invalid ID format"
+// @Failure 401 {object} model.Response "Missing or invalid token"
+// @Failure 403 {object} model.Response "Admin role required"
+// @Failure 404 {object} model.Response "API key not found"
+// @Failure 409 {object} model.Response "API key already revoked"
+// @Failure 500 {object} model.Response "Internal server error"
+// @Router /api-keys/{keyId} [delete]
+func (ac *APIKeyController) RevokeAPIKey(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("keyId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid API key ID"})
+		return
+	}
+
+	if err := ac.apiKeyUsecase.RevokeAPIKey(ctx.Request.Context(), id); err != nil {
+		ctx.JSON(apiKeyErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// --- Helper Functions ---
+
+func apiKeyErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, usecase.ErrInvalidAPIKeyScope), errors.Is(err, usecase.ErrInvalidAllowedIP),
+		errors.Is(err, usecase.ErrInvalidAPIKeyExpiry), errors.Is(err, usecase.ErrInvalidAPIKeyOwner):
+		return http.StatusBadRequest
+	case errors.Is(err, usecase.ErrAPIKeyNotFound), errors.Is(err, usecase.ErrUserNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, usecase.ErrAPIKeyAlreadyRevoked):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
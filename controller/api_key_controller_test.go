@@ -0,0 +1,166 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"go-api/dto"
+	"go-api/usecase"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateAPIKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name   string
+		body   string
+		err    error
+		status int
+	}{
+		{"Success", `{"user_id":9,"name":"Armazém","scopes":["products:read"]}`, nil, http.StatusCreated},
+		{"Missing Scopes", `{"user_id":9,"name":"Armazém"}`, nil, http.StatusBadRequest},
+		{"Invalid Scope", `{"user_id":9,"name":"Armazém","scopes":["users:write"]}`, usecase.ErrInvalidAPIKeyScope, http.StatusBadRequest},
+		{"Unknown User", `{"user_id":9,"name":"Armazém","scopes":["products:read"]}`, usecase.ErrUserNotFound, http.StatusNotFound},
+		{"Internal Error", `{"user_id":9,"name":"Armazém","scopes":["products:read"]}`, errors.New("db down"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUsecase := &MockAPIKeyUsecase{
+				CreateAPIKeyFunc: func(ctx context.Context, request dto.CreateAPIKeyRequest) (*dto.APIKeySecretResponse, error) {
+					assert.Equal(t, 9, request.UserID)
+					if tt.err != nil {
+						return nil, tt.err
+					}
+					return &dto.APIKeySecretResponse{Key: "gak_3f9a1c0b7d2e_secret", APIKey: dto.APIKeyResponse{ID: 4, Prefix: "gak_3f9a1c0b7d2e"}}, nil
+				},
+			}
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest(http.MethodPost, "/api-keys", strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			NewAPIKeyController(mockUsecase).CreateAPIKey(c)
+
+			assert.Equal(t, tt.status, w.Code)
+			if tt.status == http.StatusCreated {
+				var response dto.APIKeySecretResponse
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Equal(t, "gak_3f9a1c0b7d2e_secret", response.Key)
+				assert.Equal(t, 4, response.APIKey.ID)
+			}
+		})
+	}
+}
+
+func TestGetAPIKeys(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Filtered By User", func(t *testing.T) {
+		mockUsecase := &MockAPIKeyUsecase{
+			GetAPIKeysFunc: func(userID int) ([]dto.APIKeyResponse, error) {
+				assert.Equal(t, 9, userID)
+				return []dto.APIKeyResponse{{ID: 4, UserID: 9, Prefix: "gak_3f9a1c0b7d2e"}}, nil
+			},
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodGet, "/api-keys?user_id=9", nil)
+
+		NewAPIKeyController(mockUsecase).GetAPIKeys(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response []dto.APIKeyResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Len(t, response, 1)
+	})
+
+	t.Run("Invalid User ID", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodGet, "/api-keys?user_id=abc", nil)
+
+		NewAPIKeyController(&MockAPIKeyUsecase{}).GetAPIKeys(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestRotateAPIKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name   string
+		id     string
+		err    error
+		status int
+	}{
+		{"Success", "4", nil, http.StatusOK},
+		{"Invalid ID", "abc", nil, http.StatusBadRequest},
+		{"Not Found", "4", usecase.ErrAPIKeyNotFound, http.StatusNotFound},
+		{"Revoked", "4", usecase.ErrAPIKeyAlreadyRevoked, http.StatusConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUsecase := &MockAPIKeyUsecase{
+				RotateAPIKeyFunc: func(ctx context.Context, id int) (*dto.APIKeySecretResponse, error) {
+					assert.Equal(t, 4, id)
+					if tt.err != nil {
+						return nil, tt.err
+					}
+					return &dto.APIKeySecretResponse{Key: "gak_0123456789ab_secret"}, nil
+				},
+			}
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest(http.MethodPost, "/api-keys/"+tt.id+"/rotate", nil)
+			c.Params = gin.Params{{Key: "keyId", Value: tt.id}}
+
+			NewAPIKeyController(mockUsecase).RotateAPIKey(c)
+
+			assert.Equal(t, tt.status, w.Code)
+		})
+	}
+}
+
+func TestRevokeAPIKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{"Success", nil, http.StatusNoContent},
+		{"Not Found", usecase.ErrAPIKeyNotFound, http.StatusNotFound},
+		{"Already Revoked", usecase.ErrAPIKeyAlreadyRevoked, http.StatusConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUsecase := &MockAPIKeyUsecase{
+				RevokeAPIKeyFunc: func(ctx context.Context, id int) error {
+					assert.Equal(t, 4, id)
+					return tt.err
+				},
+			}
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest(http.MethodDelete, "/api-keys/4", nil)
+			c.Params = gin.Params{{Key: "keyId", Value: "4"}}
+
+			NewAPIKeyController(mockUsecase).RevokeAPIKey(c)
+
+			assert.Equal(t, tt.status, c.Writer.Status())
+		})
+	}
+}
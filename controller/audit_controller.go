@@ -0,0 +1,124 @@
+package controller
+
+import (
+	"errors"
+	"go-api/model"
+	"go-api/usecase"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AuditController handles HTTP requests for the audit log
+type AuditController struct {
+	auditUsecase usecase.AuditUsecase
+}
+
+// NewAuditController creates a new AuditController
+func NewAuditController(usecase usecase.AuditUsecase) *AuditController {
+	return &AuditController{
+		auditUsecase: usecase,
+	}
+}
+
+// GetAuditEvents godoc
+// @Summary List audit events
+// @Description Get the changes made to users and products, newest first. Page backwards with before_id set to the last id received
+// @Tags audit
+// @Produce json
+// @Security BearerAuth
+// @Param actor_id query int false "User who made the change"
+// @Param entity_type query string false "Entity type" Enums(user, product, product_import)
+// @Param entity_id query string false "Entity ID, with entity_type"
+// @Param from query string false "Start of the time range, inclusive (RFC 3339)"
+// @Param to query string false "End of the time range, exclusive (RFC 3339)"
+// @Param before_id query int false "Only events older than this one"
+// @Param limit query int false "Page size, default 100, at most 1000"
+// @Success 200 {array} model.AuditEvent "Audit events"
+// @Failure 400 {object} model.Response "Bad request - Invalid filter"
+// @Failure 401 {object} model.Response "Unauthorized"
+// @Failure 403 {object} model.Response "Forbidden"
+// @Failure 500 {object} model.Response "Internal server error"
+// @Router /audit [get]
+func (ac *AuditController) GetAuditEvents(ctx *gin.Context) {
+	filter, err := auditFilterFromQuery(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	events, err := ac.auditUsecase.GetAuditEvents(filter)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, events)
+}
+
+// VerifyAuditChain godoc
+// @Summary Verify the audit log
+// @Description Recompute the hash chain of the whole audit log and report the first event that was edited or follows a removed one. Keep last_hash elsewhere to also detect events removed from the end
+// @Tags audit
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} model.AuditVerification "Verification result"
+// @Failure 401 {object} model.Response "Unauthorized"
+// @Failure 403 {object} model.Response "Forbidden"
+// @Failure 500 {object} model.Response "Internal server error"
+// @Router /audit/verify [get]
+func (ac *AuditController) VerifyAuditChain(ctx *gin.Context) {
+	result, err := ac.auditUsecase.VerifyChain(ctx.Request.Context())
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+// --- Helper Functions ---
+
+// auditFilterFromQuery reads the filters of GET /audit
+func auditFilterFromQuery(ctx *gin.Context) (model.AuditFilter, error) {
+	filter := model.AuditFilter{
+		EntityType: ctx.Query("entity_type"),
+		EntityID:   ctx.Query("entity_id"),
+	}
+	if filter.EntityID != "" && filter.EntityType == "" {
+		return model.AuditFilter{}, errors.New("entity_id requires entity_type")
+	}
+
+	var err error
+	if actorID := ctx.Query("actor_id"); actorID != "" {
+		if filter.ActorID, err = strconv.Atoi(actorID); err != nil || filter.ActorID <= 0 {
+			return model.AuditFilter{}, errors.New("actor_id must be a positive integer")
+		}
+	}
+	if beforeID := ctx.Query("before_id"); beforeID != "" {
+		if filter.BeforeID, err = strconv.ParseInt(beforeID, 10, 64); err != nil || filter.BeforeID <= 0 {
+			return model.AuditFilter{}, errors.New("before_id must be a positive integer")
+		}
+	}
+	if limit := ctx.Query("limit"); limit != "" {
+		if filter.Limit, err = strconv.Atoi(limit); err != nil || filter.Limit <= 0 {
+			return model.AuditFilter{}, errors.New("limit must be a positive integer")
+		}
+	}
+	if from := ctx.Query("from"); from != "" {
+		if filter.From, err = time.Parse(time.RFC3339, from); err != nil {
+			return model.AuditFilter{}, errors.New("from must be an RFC 3339 timestamp")
+		}
+	}
+	if to := ctx.Query("to"); to != "" {
+		if filter.To, err = time.Parse(time.RFC3339, to); err != nil {
+			return model.AuditFilter{}, errors.New("to must be an RFC 3339 timestamp")
+		}
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.To.After(filter.From) {
+		return model.AuditFilter{}, errors.New("to must be after from")
+	}
+	return filter, nil
+}
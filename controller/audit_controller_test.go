@@ -0,0 +1,80 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"go-api/model"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetAuditEvents(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Filters", func(t *testing.T) {
+		var received model.AuditFilter
+		mockUsecase := &MockAuditUsecase{
+			GetAuditEventsFunc: func(filter model.AuditFilter) ([]model.AuditEvent, error) {
+				received = filter
+				return []model.AuditEvent{{ID: 41, Action: model.AuditActionDelete, EntityType: model.AuditEntityProduct, EntityID: "3", Changes: json.RawMessage(`{}`)}}, nil
+			},
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodGet, "/audit?actor_id=7&entity_type=product&entity_id=3&from=2026-03-01T00:00:00Z&to=2026-04-01T00:00:00Z&before_id=42&limit=10", nil)
+
+		NewAuditController(mockUsecase).GetAuditEvents(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, model.AuditFilter{
+			ActorID:    7,
+			EntityType: model.AuditEntityProduct,
+			EntityID:   "3",
+			From:       time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+			To:         time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC),
+			BeforeID:   42,
+			Limit:      10,
+		}, received)
+		var events []model.AuditEvent
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &events))
+		assert.Len(t, events, 1)
+	})
+
+	t.Run("Invalid Filters", func(t *testing.T) {
+		for _, query := range []string{"actor_id=abc", "entity_id=3", "from=yesterday", "from=2026-04-01T00:00:00Z&to=2026-03-01T00:00:00Z", "limit=0"} {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest(http.MethodGet, "/audit?"+query, nil)
+
+			NewAuditController(&MockAuditUsecase{}).GetAuditEvents(c)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code, query)
+		}
+	})
+}
+
+func TestVerifyAuditChain(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	brokenAt := int64(12)
+	mockUsecase := &MockAuditUsecase{
+		VerifyChainFunc: func(ctx context.Context) (model.AuditVerification, error) {
+			return model.AuditVerification{Valid: false, Checked: 12, BrokenAt: &brokenAt}, nil
+		},
+	}
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodGet, "/audit/verify", nil)
+
+	NewAuditController(mockUsecase).VerifyAuditChain(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"valid": false, "checked": 12, "broken_at": 12}`, w.Body.String())
+}
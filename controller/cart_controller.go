@@ -0,0 +1,288 @@
+package controller
+
+import (
+	"errors"
+	"go-api/dto"
+	"go-api/middleware"
+	"go-api/model"
+	"go-api/usecase"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CartTokenHeader carries the token of an anonymous cart, in requests and in
+// the response that creates the cart
+const CartTokenHeader = "X-Cart-Token"
+
+// CartController handles HTTP requests for the shopping cart
+type CartController struct {
+	cartUsecase usecase.CartUsecase
+}
+
+// NewCartController creates a new CartController
+func NewCartController(usecase usecase.CartUsecase) *CartController {
+	return &CartController{
+		cartUsecase: usecase,
+	}
+}
+
+// GetCart godoc
+// @Summary Get the cart
+// @Description Get the cart of the authenticated user or, without a token, of the anonymous session in X-Cart-Token, priced at the current prices
+// @Tags cart
+// @Produce json
+// @Param X-Cart-Token header string false "Token of the anonymous cart"
+// @Success 200 {object} dto.CartResponse "Cart"
+// @Failure 401 {object} model.Response "Invalid token"
+// @Failure 500 {object} model.Response "Internal server error"
+// @Router /cart [get]
+func (cc *CartController) GetCart(ctx *gin.Context) {
+	cart, err := cc.cartUsecase.GetCart(cartOwner(ctx))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, toCartResponse(*cart))
+}
+
+// AddCartItem godoc
+// @Summary Add an item to the cart
+// @Description Add a product, or one of its variants, to the cart. Adding an item already in the cart adds up the quantities and refreshes its price. Anonymous requests without X-Cart-Token get a new cart whose token is returned in the X-Cart-Token header
+// @Tags cart
+// @Accept json
+// @Produce json
+// @Param X-Cart-Token header string false "Token of the anonymous cart"
+// @Param item body dto.AddCartItemRequest true "Item to add"
+// @Success 200 {object} dto.CartResponse "Cart after the change"
+// @Failure 400 {object} model.Response "Bad request - Invalid input data or variant"
+// @Failure 401 {object} model.Response "Invalid token"
+// @Failure 404 {object} model.Response "Product or variant not found"
+// @Failure 409 {object} model.Response "Not enough stock"
+// @Failure 500 {object} model.Response "Internal server error"
+// @Router /cart/items [post]
+func (cc *CartController) AddCartItem(ctx *gin.Context) {
+	var req dto.AddCartItemRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	cart, err := cc.cartUsecase.AddItem(cartOwner(ctx), model.CartItemInput{
+		ProductID: req.ProductID,
+		VariantID: req.VariantID,
+		Quantity:  req.Quantity,
+	})
+	if err != nil {
+		ctx.JSON(cartErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	if cart.Token != "" {
+		ctx.Header(CartTokenHeader, cart.Token)
+	}
+	ctx.JSON(http.StatusOK, toCartResponse(*cart))
+}
+
+// UpdateCartItem godoc
+// @Summary Change the quantity of a cart item
+// @Description Replace the quantity of an item of the cart; its added price is kept
+// @Tags cart
+// @Accept json
+// @Produce json
+// @Param X-Cart-Token header string false "Token of the anonymous cart"
+// @Param itemId path int true "Cart item ID" minimum(1)
+// @Param item body dto.UpdateCartItemRequest true "New quantity"
+// @Success 200 {object} dto.CartResponse "Cart after the change"
+// @Failure 400 {object} model.Response "Bad request - Invalid input data"
+// @Failure 401 {object} model.Response "Invalid token"
+// @Failure 404 {object} model.Response "Item not found in the cart"
+// @Failure 409 {object} model.Response "Not enough stock"
+// @Failure 500 {object} model.Response "Internal server error"
+// @Router /cart/items/{itemId} [put]
+func (cc *CartController) UpdateCartItem(ctx *gin.Context) {
+	itemId, err := strconv.Atoi(ctx.Param("itemId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid cart item ID"})
+		return
+	}
+
+	var req dto.UpdateCartItemRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	cart, err := cc.cartUsecase.UpdateItemQuantity(cartOwner(ctx), itemId, req.Quantity)
+	if err != nil {
+		ctx.JSON(cartErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, toCartResponse(*cart))
+}
+
+// RemoveCartItem godoc
+// @Summary Remove an item from the cart
+// @Description Remove an item from the cart
+// @Tags cart
+// @Produce json
+// @Param X-Cart-Token header string false "Token of the anonymous cart"
+// @Param itemId path int true "Cart item ID" minimum(1)
+// @Success 200 {object} dto.CartResponse "Cart after the change"
+// @Failure 400 {object} model.Response "Bad request - Invalid ID format"
+// @Failure 401 {object} model.Response "Invalid token"
+// @Failure 404 {object} model.Response "Item not found in the cart"
+// @Failure 500 {object} model.Response "Internal server error"
+// @Router /cart/items/{itemId} [delete]
+func (cc *CartController) RemoveCartItem(ctx *gin.Context) {
+	itemId, err := strconv.Atoi(ctx.Param("itemId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid cart item ID"})
+		return
+	}
+
+	cart, err := cc.cartUsecase.RemoveItem(cartOwner(ctx), itemId)
+	if err != nil {
+		ctx.JSON(cartErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, toCartResponse(*cart))
+}
+
+// --- Helper Functions ---
+
+// GetCartPricing godoc
+// @Summary Price the cart with promotions and taxes
+// @Description Apply the automatic promotions and the given discount codes to the available items of the cart, without redeeming them. The breakdown shows the discount of each promotion on each item and why each rejected code was not applied. With a destination country, the discounted items are taxed and the taxes itemized per line
+// @Tags cart
+// @Produce json
+// @Param X-Cart-Token header string false "Token of the anonymous cart"
+// @Param code query []string false "Discount codes to try" collectionFormat(multi)
+// @Param country query string false "ISO 3166-1 alpha-2 country of the destination, to tax the items"
+// @Param state query string false "State of the destination"
+// @Success 200 {object} dto.CartPricingResponse "Price breakdown"
+// @Failure 400 {object} model.Response "Items in more than one currency, invalid destination or destination without tax rates"
+// @Failure 401 {object} model.Response "Invalid token"
+// @Failure 500 {object} model.Response "Internal server error"
+// @Router /cart/pricing [get]
+func (cc *CartController) GetCartPricing(ctx *gin.Context) {
+	var destination *model.TaxDestination
+	if country := ctx.Query("country"); country != "" {
+		destination = &model.TaxDestination{Country: country, State: ctx.Query("state")}
+	}
+
+	pricing, err := cc.cartUsecase.PriceCart(cartOwner(ctx), ctx.QueryArray("code"), destination)
+	if err != nil {
+		ctx.JSON(cartErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, toCartPricingResponse(*pricing))
+}
+
+// cartOwner identifies the cart of the request: the authenticated user, set
+// by middleware.AuthOptional, or the anonymous session token
+func cartOwner(ctx *gin.Context) model.CartOwner {
+	if userID := ctx.GetInt(middleware.ContextUserID); userID != 0 {
+		return model.CartOwner{UserID: userID}
+	}
+	return model.CartOwner{Token: ctx.GetHeader(CartTokenHeader)}
+}
+
+func cartErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, usecase.ErrProductNotFound), errors.Is(err, usecase.ErrVariantNotFound),
+		errors.Is(err, usecase.ErrCartItemNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, usecase.ErrInsufficientStock):
+		return http.StatusConflict
+	case errors.Is(err, usecase.ErrInvalidQuantity), errors.Is(err, usecase.ErrVariantRequired),
+		errors.Is(err, usecase.ErrMixedCurrencies), errors.Is(err, usecase.ErrInvalidTaxDestination),
+		errors.Is(err, usecase.ErrTaxRegionNotFound):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func toCartResponse(cart model.Cart) dto.CartResponse {
+	items := make([]dto.CartItemResponse, 0, len(cart.Items))
+	for _, item := range cart.Items {
+		items = append(items, dto.CartItemResponse{
+			ID:           item.ID,
+			ProductID:    item.ProductID,
+			VariantID:    item.VariantID,
+			ProductName:  item.ProductName,
+			SKU:          item.SKU,
+			Quantity:     item.Quantity,
+			UnitPrice:    toMoneyResponse(item.UnitPrice),
+			AddedPrice:   toMoneyResponse(item.AddedPrice),
+			PriceChanged: item.PriceChanged,
+			Subtotal:     toMoneyResponse(item.Subtotal),
+			Stock:        item.Stock,
+			Available:    item.Available,
+		})
+	}
+	subtotals := make([]dto.MoneyResponse, 0, len(cart.Subtotals))
+	for _, subtotal := range cart.Subtotals {
+		subtotals = append(subtotals, toMoneyResponse(subtotal))
+	}
+
+	response := dto.CartResponse{
+		ID:        cart.ID,
+		Token:     cart.Token,
+		Items:     items,
+		Subtotals: subtotals,
+	}
+	if !cart.UpdatedAt.IsZero() {
+		response.UpdatedAt = &cart.UpdatedAt
+	}
+	return response
+}
+
+func toCartPricingResponse(pricing model.PricingResult) dto.CartPricingResponse {
+	response := dto.CartPricingResponse{
+		Lines:    make([]dto.PricedLineResponse, 0, len(pricing.Lines)),
+		Applied:  make([]dto.AppliedPromotionResponse, 0, len(pricing.Applied)),
+		Rejected: make([]dto.RejectedCodeResponse, 0, len(pricing.Rejected)),
+	}
+	for _, line := range pricing.Lines {
+		adjustments := make([]dto.AdjustmentResponse, 0, len(line.Adjustments))
+		for _, adjustment := range line.Adjustments {
+			adjustments = append(adjustments, dto.AdjustmentResponse{
+				PromotionID: adjustment.PromotionID,
+				Code:        adjustment.Code,
+				Name:        adjustment.Name,
+				Amount:      toMoneyResponse(adjustment.Amount),
+			})
+		}
+		response.Lines = append(response.Lines, dto.PricedLineResponse{
+			ProductID:   line.ProductID,
+			VariantID:   line.VariantID,
+			Quantity:    line.Quantity,
+			Subtotal:    toMoneyResponse(line.Subtotal),
+			Discount:    toMoneyResponse(line.Discount),
+			Total:       toMoneyResponse(line.Total),
+			Adjustments: adjustments,
+		})
+	}
+	for _, applied := range pricing.Applied {
+		response.Applied = append(response.Applied, toAppliedPromotionResponse(applied))
+	}
+	for _, rejected := range pricing.Rejected {
+		response.Rejected = append(response.Rejected, dto.RejectedCodeResponse{Code: rejected.Code, Reason: rejected.Reason})
+	}
+	if pricing.Subtotal.Currency != "" {
+		subtotal, discount, total := toMoneyResponse(pricing.Subtotal), toMoneyResponse(pricing.Discount), toMoneyResponse(pricing.Total)
+		response.Subtotal, response.Discount, response.Total = &subtotal, &discount, &total
+	}
+	if pricing.Tax != nil {
+		tax := toTaxBreakdownResponse(*pricing.Tax)
+		response.Tax = &tax
+	}
+	return response
+}
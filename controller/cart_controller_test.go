@@ -0,0 +1,182 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go-api/dto"
+	"go-api/middleware"
+	"go-api/model"
+	"go-api/usecase"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetCart(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Authenticated User", func(t *testing.T) {
+		mockUsecase := &MockCartUsecase{
+			GetCartFunc: func(owner model.CartOwner) (*model.Cart, error) {
+				assert.Equal(t, model.CartOwner{UserID: 7}, owner)
+				return &model.Cart{
+					ID: 4,
+					Items: []model.CartItem{{
+						ID: 1, ProductID: 1, ProductName: "Camiseta", Quantity: 2, Available: true, PriceChanged: true,
+						UnitPrice:  model.Money{Amount: 3990, Currency: "BRL"},
+						AddedPrice: model.Money{Amount: 4990, Currency: "BRL"},
+						Subtotal:   model.Money{Amount: 7980, Currency: "BRL"},
+					}},
+					Subtotals: []model.Money{{Amount: 7980, Currency: "BRL"}},
+				}, nil
+			},
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodGet, "/cart", nil)
+		c.Request.Header.Set(CartTokenHeader, "ignored-when-authenticated")
+		c.Set(middleware.ContextUserID, 7)
+
+		NewCartController(mockUsecase).GetCart(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response dto.CartResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.True(t, response.Items[0].PriceChanged)
+		assert.Equal(t, dto.MoneyResponse{Amount: "49.90", Currency: "BRL"}, response.Items[0].AddedPrice)
+		assert.Equal(t, []dto.MoneyResponse{{Amount: "79.80", Currency: "BRL"}}, response.Subtotals)
+	})
+}
+
+func TestAddCartItem(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("New Anonymous Cart Returns Token", func(t *testing.T) {
+		mockUsecase := &MockCartUsecase{
+			AddItemFunc: func(owner model.CartOwner, input model.CartItemInput) (*model.Cart, error) {
+				assert.Equal(t, model.CartOwner{}, owner)
+				assert.Equal(t, 2, *input.VariantID)
+				return &model.Cart{ID: 4, Token: "new-token"}, nil
+			},
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodPost, "/cart/items", bytes.NewBufferString(`{"product_id": 1, "variant_id": 2, "quantity": 1}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		NewCartController(mockUsecase).AddCartItem(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "new-token", w.Header().Get(CartTokenHeader))
+	})
+
+	t.Run("Not Enough Stock", func(t *testing.T) {
+		mockUsecase := &MockCartUsecase{
+			AddItemFunc: func(owner model.CartOwner, input model.CartItemInput) (*model.Cart, error) {
+				assert.Equal(t, model.CartOwner{Token: "anon-token"}, owner)
+				return nil, fmt.Errorf("%w: 3 available", usecase.ErrInsufficientStock)
+			},
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodPost, "/cart/items", bytes.NewBufferString(`{"product_id": 1, "variant_id": 2, "quantity": 5}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Request.Header.Set(CartTokenHeader, "anon-token")
+
+		NewCartController(mockUsecase).AddCartItem(c)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Empty(t, w.Header().Get(CartTokenHeader))
+	})
+
+	t.Run("Invalid Quantity", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodPost, "/cart/items", bytes.NewBufferString(`{"product_id": 1, "quantity": 0}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		NewCartController(&MockCartUsecase{}).AddCartItem(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestRemoveCartItem(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Item Not Found", func(t *testing.T) {
+		mockUsecase := &MockCartUsecase{
+			RemoveItemFunc: func(owner model.CartOwner, itemID int) (*model.Cart, error) {
+				assert.Equal(t, 9, itemID)
+				return nil, usecase.ErrCartItemNotFound
+			},
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodDelete, "/cart/items/9", nil)
+		c.Params = gin.Params{{Key: "itemId", Value: "9"}}
+
+		NewCartController(mockUsecase).RemoveCartItem(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestGetCartPricing(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Breakdown With Rejected Codes", func(t *testing.T) {
+		mockUsecase := &MockCartUsecase{
+			PriceCartFunc: func(owner model.CartOwner, codes []string, destination *model.TaxDestination) (*model.PricingResult, error) {
+				assert.Equal(t, model.CartOwner{Token: "anon-token"}, owner)
+				assert.Equal(t, []string{"BEMVINDO10", "VERAO"}, codes)
+				discount := model.Money{Amount: 998, Currency: "BRL"}
+				return &model.PricingResult{
+					Lines: []model.PricedLine{{
+						ProductID: 1, Quantity: 2,
+						Subtotal: model.Money{Amount: 9980, Currency: "BRL"}, Discount: discount, Total: model.Money{Amount: 8982, Currency: "BRL"},
+						Adjustments: []model.Adjustment{{PromotionID: 3, Code: "BEMVINDO10", Name: "Boas-vindas", Amount: discount}},
+					}},
+					Applied:  []model.AppliedPromotion{{PromotionID: 3, Code: "BEMVINDO10", Name: "Boas-vindas", Discount: discount}},
+					Rejected: []model.RejectedCode{{Code: "VERAO", Reason: "the code has expired"}},
+					Subtotal: model.Money{Amount: 9980, Currency: "BRL"},
+					Discount: discount,
+					Total:    model.Money{Amount: 8982, Currency: "BRL"},
+				}, nil
+			},
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodGet, "/cart/pricing?code=BEMVINDO10&code=VERAO", nil)
+		c.Request.Header.Set(CartTokenHeader, "anon-token")
+
+		NewCartController(mockUsecase).GetCartPricing(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response dto.CartPricingResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, dto.MoneyResponse{Amount: "9.98", Currency: "BRL"}, response.Lines[0].Adjustments[0].Amount)
+		assert.Equal(t, "the code has expired", response.Rejected[0].Reason)
+		assert.Equal(t, dto.MoneyResponse{Amount: "89.82", Currency: "BRL"}, *response.Total)
+	})
+
+	t.Run("Empty Cart", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodGet, "/cart/pricing", nil)
+
+		NewCartController(&MockCartUsecase{}).GetCartPricing(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"lines": [], "applied": [], "rejected": []}`, w.Body.String())
+	})
+}
@@ -0,0 +1,327 @@
+package controller
+
+import (
+	"errors"
+	"go-api/dto"
+	"go-api/model"
+	"go-api/usecase"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CategoryController handles HTTP requests for the category taxonomy
+type CategoryController struct {
+	categoryUsecase usecase.CategoryUsecase
+}
+
+// NewCategoryController creates a new CategoryController
+func NewCategoryController(usecase usecase.CategoryUsecase) *CategoryController {
+	return &CategoryController{
+		categoryUsecase: usecase,
+	}
+}
+
+// GetCategories godoc
+// @Summary Get the category tree
+// @Description Get every category nested under its parent
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Success 200 {array} dto.CategoryResponse "Category tree"
+// @Failure 500 {object} model.Response "Internal server error"
+// @Router /categories [get]
+func (cc *CategoryController) GetCategories(ctx *gin.Context) {
+	categories, err := cc.categoryUsecase.GetCategoryTree()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, toCategoryResponses(categories))
+}
+
+// GetCategoryByID godoc
+// @Summary Get category by ID
+// @Description Get a category together with its subtree
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Param categoryId path int true "Category ID" minimum(1)
+// @Success 200 {object} dto.CategoryResponse "Category found"
+// @Failure 400 {object} model.Response "Bad request - Invalid ID format"
+// @Failure 404 {object} model.Response "Category not found"
+// @Failure 500 {object} model.Response "Internal server error"
+// @Router /categories/{categoryId} [get]
+func (cc *CategoryController) GetCategoryByID(ctx *gin.Context) {
+	categoryId, err := strconv.Atoi(ctx.Param("categoryId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category ID"})
+		return
+	}
+
+	category, err := cc.categoryUsecase.GetCategoryByID(categoryId)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if category == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": usecase.ErrCategoryNotFound.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, toCategoryResponse(*category))
+}
+
+// CreateCategory godoc
+// @Summary Create a new category
+// @Description Create a root category or a subcategory of an existing one
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param category body dto.CreateCategoryRequest true "Category information"
+// @Success 201 {object} dto.CategoryResponse "Category created successfully"
+// @Failure 400 {object} model.Response "Bad request - Invalid input data"
+// @Failure 401 {object} model.Response "Missing or invalid token"
+// @Failure 403 {object} model.Response "Admin role required, or API key or access token without the scope of the route"
+// @Failure 404 {object} model.Response "Parent category not found"
+// @Failure 409 {object} model.Response "Slug already used by a sibling"
+// @Failure 500 {object} model.Response "Internal server error"
+// @Router /category [post]
+func (cc *CategoryController) CreateCategory(ctx *gin.Context) {
+	var req dto.CreateCategoryRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	category, err := cc.categoryUsecase.CreateCategory(ctx.Request.Context(), model.Category{
+		ParentID: req.ParentID,
+		Name:     req.Name,
+		Slug:     req.Slug,
+	})
+	if err != nil {
+		ctx.JSON(categoryErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, toCategoryResponse(category))
+}
+
+// UpdateCategory godoc
+// @Summary Update a category
+// @Description Rename a category. Changing the slug updates the path of the whole subtree
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param categoryId path int true "Category ID" minimum(1)
+// @Param category body dto.UpdateCategoryRequest true "Category information"
+// @Success 200 {object} dto.CategoryResponse "Category updated successfully"
+// @Failure 400 {object} model.Response "Bad request - Invalid input data"
+// @Failure 401 {object} model.Response "Missing or invalid token"
+// @Failure 403 {object} model.Response "Admin role required, or API key or access token without the scope of the route"
+// @Failure 404 {object} model.Response "Category not found"
+// @Failure 409 {object} model.Response "Slug already used by a sibling, or category changed by another request"
+// @Failure 500 {object} model.Response "Internal server error"
+// @Router /categories/{categoryId} [put]
+func (cc *CategoryController) UpdateCategory(ctx *gin.Context) {
+	categoryId, err := strconv.Atoi(ctx.Param("categoryId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category ID"})
+		return
+	}
+
+	var req dto.UpdateCategoryRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	category, err := cc.categoryUsecase.UpdateCategory(ctx.Request.Context(), categoryId, model.Category{Name: req.Name, Slug: req.Slug})
+	if err != nil {
+		ctx.JSON(categoryErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, toCategoryResponse(*category))
+}
+
+// MoveCategory godoc
+// @Summary Move a category subtree
+// @Description Move a category, with all its descendants, under another parent or to the root
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param categoryId path int true "Category ID" minimum(1)
+// @Param move body dto.MoveCategoryRequest true "New parent"
+// @Success 200 {object} dto.CategoryResponse "Category moved successfully"
+// @Failure 400 {object} model.Response "Bad request - Invalid input data or cyclic move"
+// @Failure 401 {object} model.Response "Missing or invalid token"
+// @Failure 403 {object} model.Response "Admin role required, or API key or access token without the scope of the route"
+// @Failure 404 {object} model.Response "Category not found"
+// @Failure 409 {object} model.Response "Slug already used under the new parent, or category or parent changed by another request"
+// @Failure 500 {object} model.Response "Internal server error"
+// @Router /categories/{categoryId}/move [post]
+func (cc *CategoryController) MoveCategory(ctx *gin.Context) {
+	categoryId, err := strconv.Atoi(ctx.Param("categoryId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category ID"})
+		return
+	}
+
+	var req dto.MoveCategoryRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	category, err := cc.categoryUsecase.MoveCategory(ctx.Request.Context(), categoryId, req.ParentID)
+	if err != nil {
+		ctx.JSON(categoryErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, toCategoryResponse(*category))
+}
+
+// DeleteCategory godoc
+// @Summary Delete a category
+// @Description Delete a category without subcategories. Products are only unassigned from it
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param categoryId path int true "Category ID" minimum(1)
+// @Success 204 "Category deleted successfully"
+// @Failure 400 {object} model.Response "Bad request - Invalid ID format"
+// @Failure 401 {object} model.Response "Missing or invalid token"
+// @Failure 403 {object} model.Response "Admin role required, or API key or access token without the scope of the route"
+// @Failure 404 {object} model.Response "Category not found"
+// @Failure 409 {object} model.Response "Category has subcategories"
+// @Failure 500 {object} model.Response "Internal server error"
+// @Router /categories/{categoryId} [delete]
+func (cc *CategoryController) DeleteCategory(ctx *gin.Context) {
+	categoryId, err := strconv.Atoi(ctx.Param("categoryId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category ID"})
+		return
+	}
+
+	err = cc.categoryUsecase.DeleteCategory(ctx.Request.Context(), categoryId)
+	if err != nil {
+		ctx.JSON(categoryErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// GetProductCategories godoc
+// @Summary List the categories of a product
+// @Description Get the categories a product is assigned to
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Param productId path int true "Product ID" minimum(1)
+// @Success 200 {array} dto.CategoryResponse "Product categories"
+// @Failure 400 {object} model.Response "Bad request - Invalid ID format"
+// @Failure 404 {object} model.Response "Product not found"
+// @Failure 500 {object} model.Response "Internal server error"
+// @Router /products/{productId}/categories [get]
+func (cc *CategoryController) GetProductCategories(ctx *gin.Context) {
+	productId, err := strconv.Atoi(ctx.Param("productId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return
+	}
+
+	categories, err := cc.categoryUsecase.GetProductCategories(productId)
+	if err != nil {
+		ctx.JSON(categoryErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, toCategoryResponses(categories))
+}
+
+// SetProductCategories godoc
+// @Summary Assign categories to a product
+// @Description Replace the categories a product is assigned to
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param productId path int true "Product ID" minimum(1)
+// @Param categories body dto.SetProductCategoriesRequest true "Category IDs"
+// @Success 200 {array} dto.CategoryResponse "Product categories"
+// @Failure 400 {object} model.Response "Bad request - Invalid input data"
+// @Failure 401 {object} model.Response "Missing or invalid token"
+// @Failure 403 {object} model.Response "Admin role required, or API key or access token without the scope of the route"
+// @Failure 404 {object} model.Response "Product or category not found"
+// @Failure 500 {object} model.Response "Internal server error"
+// @Router /products/{productId}/categories [put]
+func (cc *CategoryController) SetProductCategories(ctx *gin.Context) {
+	productId, err := strconv.Atoi(ctx.Param("productId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return
+	}
+
+	var req dto.SetProductCategoriesRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	categories, err := cc.categoryUsecase.SetProductCategories(ctx.Request.Context(), productId, req.CategoryIDs)
+	if err != nil {
+		ctx.JSON(categoryErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, toCategoryResponses(categories))
+}
+
+// --- Helper Functions ---
+
+func categoryErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, usecase.ErrCategoryNotFound), errors.Is(err, usecase.ErrProductNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, usecase.ErrCategorySlugTaken), errors.Is(err, usecase.ErrCategoryHasChildren),
+		errors.Is(err, usecase.ErrCategoryChanged):
+		return http.StatusConflict
+	case errors.Is(err, usecase.ErrInvalidCategorySlug), errors.Is(err, usecase.ErrCategoryCycle):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func toCategoryResponse(category model.Category) dto.CategoryResponse {
+	return dto.CategoryResponse{
+		ID:       category.ID,
+		ParentID: category.ParentID,
+		Name:     category.Name,
+		Slug:     category.Slug,
+		Path:     category.Path,
+		Children: toCategoryResponses(category.Children),
+	}
+}
+
+func toCategoryResponses(categories []model.Category) []dto.CategoryResponse {
+	responses := make([]dto.CategoryResponse, 0, len(categories))
+	for _, category := range categories {
+		responses = append(responses, toCategoryResponse(category))
+	}
+	return responses
+}
@@ -0,0 +1,161 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"go-api/dto"
+	"go-api/model"
+	"go-api/usecase"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetCategories(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Success", func(t *testing.T) {
+		parentID := 1
+		mockUsecase := &MockCategoryUsecase{
+			GetCategoryTreeFunc: func() ([]model.Category, error) {
+				return []model.Category{
+					{ID: 1, Name: "Roupas", Slug: "roupas", Path: "roupas", Children: []model.Category{
+						{ID: 2, ParentID: &parentID, Name: "Camisetas", Slug: "camisetas", Path: "roupas/camisetas"},
+					}},
+				}, nil
+			},
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodGet, "/categories", nil)
+
+		categoryController := NewCategoryController(mockUsecase)
+		categoryController.GetCategories(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response []dto.CategoryResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Len(t, response, 1)
+		assert.Equal(t, "roupas/camisetas", response[0].Children[0].Path)
+	})
+}
+
+func TestCreateCategory(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Success", func(t *testing.T) {
+		mockUsecase := &MockCategoryUsecase{
+			CreateCategoryFunc: func(ctx context.Context, category model.Category) (model.Category, error) {
+				category.ID = 1
+				category.Slug = "roupas"
+				category.Path = "roupas"
+				return category, nil
+			},
+		}
+
+		jsonBody, _ := json.Marshal(dto.CreateCategoryRequest{Name: "Roupas"})
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodPost, "/category", bytes.NewBuffer(jsonBody))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		categoryController := NewCategoryController(mockUsecase)
+		categoryController.CreateCategory(c)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		var response dto.CategoryResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "roupas", response.Slug)
+	})
+
+	t.Run("Slug Conflict", func(t *testing.T) {
+		mockUsecase := &MockCategoryUsecase{
+			CreateCategoryFunc: func(ctx context.Context, category model.Category) (model.Category, error) {
+				return model.Category{}, usecase.ErrCategorySlugTaken
+			},
+		}
+
+		jsonBody, _ := json.Marshal(dto.CreateCategoryRequest{Name: "Roupas"})
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodPost, "/category", bytes.NewBuffer(jsonBody))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		categoryController := NewCategoryController(mockUsecase)
+		categoryController.CreateCategory(c)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+}
+
+func TestMoveCategory(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Cycle", func(t *testing.T) {
+		mockUsecase := &MockCategoryUsecase{
+			MoveCategoryFunc: func(ctx context.Context, id int, parentID *int) (*model.Category, error) {
+				return nil, usecase.ErrCategoryCycle
+			},
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodPost, "/categories/1/move", bytes.NewBufferString(`{"parent_id": 2}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Params = gin.Params{{Key: "categoryId", Value: "1"}}
+
+		categoryController := NewCategoryController(mockUsecase)
+		categoryController.MoveCategory(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Changed Concurrently", func(t *testing.T) {
+		mockUsecase := &MockCategoryUsecase{
+			MoveCategoryFunc: func(ctx context.Context, id int, parentID *int) (*model.Category, error) {
+				return nil, usecase.ErrCategoryChanged
+			},
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodPost, "/categories/1/move", bytes.NewBufferString(`{"parent_id": 2}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Params = gin.Params{{Key: "categoryId", Value: "1"}}
+
+		categoryController := NewCategoryController(mockUsecase)
+		categoryController.MoveCategory(c)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+}
+
+func TestSetProductCategories(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Product Not Found", func(t *testing.T) {
+		mockUsecase := &MockCategoryUsecase{
+			SetProductCategoriesFunc: func(ctx context.Context, productID int, categoryIDs []int) ([]model.Category, error) {
+				return nil, usecase.ErrProductNotFound
+			},
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodPut, "/products/9/categories", bytes.NewBufferString(`{"category_ids": [1]}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Params = gin.Params{{Key: "productId", Value: "9"}}
+
+		categoryController := NewCategoryController(mockUsecase)
+		categoryController.SetProductCategories(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
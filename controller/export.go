@@ -0,0 +1,156 @@
+package controller
+
+import (
+	"compress/gzip"
+	"fmt"
+	"go-api/internal/export"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// exportStream writes an export to the response as the rows arrive. The
+// response starts with the first row, so an error before it can still be
+// answered with a JSON error
+type exportStream struct {
+	ctx      *gin.Context
+	format   string
+	filename string
+	columns  []string
+
+	writer     export.Writer
+	compressor *gzip.Writer
+}
+
+// newExportStream picks the format from the format query parameter, then
+// from the Accept header, defaulting to CSV. It answers the request itself
+// and returns nil when the format is not supported
+func newExportStream(ctx *gin.Context, name string, columns []string) *exportStream {
+	var format string
+	var err error
+	if requested := ctx.Query("format"); requested != "" {
+		if format, err = export.ParseFormat(requested); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "format must be csv, ndjson or xlsx"})
+			return nil
+		}
+	} else if format, err = export.FormatFromAccept(ctx.GetHeader("Accept"), export.FormatCSV); err != nil {
+		ctx.JSON(http.StatusNotAcceptable, gin.H{"error": "export is available as text/csv, application/x-ndjson or " + export.ContentType(export.FormatXLSX)})
+		return nil
+	}
+
+	return &exportStream{
+		ctx:      ctx,
+		format:   format,
+		filename: fmt.Sprintf("%s-%s.%s", name, time.Now().UTC().Format("20060102-150405"), format),
+		columns:  columns,
+	}
+}
+
+// WriteRow writes a row, starting the response on the first one
+func (s *exportStream) WriteRow(values ...interface{}) error {
+	if s.writer == nil {
+		if err := s.start(); err != nil {
+			return err
+		}
+	}
+	return s.writer.WriteRow(values...)
+}
+
+// Finish completes the response. When err happened after the response
+// started the connection is dropped instead, so the client cannot mistake a
+// truncated file for a complete one
+func (s *exportStream) Finish(err error) {
+	if err != nil {
+		if s.writer == nil {
+			s.ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		s.ctx.Error(err)
+		s.abort()
+		return
+	}
+
+	if s.writer == nil {
+		// Nothing matched: the export still has its header
+		if err := s.start(); err != nil {
+			s.ctx.Error(err)
+			s.abort()
+			return
+		}
+	}
+	if err := s.writer.Close(); err != nil {
+		s.ctx.Error(err)
+		s.abort()
+		return
+	}
+	if s.compressor != nil {
+		if err := s.compressor.Close(); err != nil {
+			s.ctx.Error(err)
+			s.abort()
+		}
+	}
+}
+
+func (s *exportStream) start() error {
+	header := s.ctx.Writer.Header()
+	header.Set("Content-Type", export.ContentType(s.format))
+	header.Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, s.filename))
+	header.Set("Cache-Control", "no-store")
+	header.Set("X-Content-Type-Options", "nosniff")
+
+	var out io.Writer = s.ctx.Writer
+	// XLSX is already a zip archive, compressing it again gains nothing
+	if s.format != export.FormatXLSX {
+		header.Add("Vary", "Accept-Encoding")
+		if acceptsGzip(s.ctx) {
+			header.Set("Content-Encoding", "gzip")
+			s.compressor = gzip.NewWriter(s.ctx.Writer)
+			out = s.compressor
+		}
+	}
+	s.ctx.Status(http.StatusOK)
+
+	writer, err := export.NewWriter(s.format, out, s.columns)
+	if err != nil {
+		return err
+	}
+	s.writer = writer
+	return nil
+}
+
+// abort closes the connection without finishing the chunked response; it
+// does nothing on connections that cannot be hijacked (e.g. HTTP/2)
+func (s *exportStream) abort() {
+	s.ctx.Abort()
+	// gin's writer panics when the connection cannot be hijacked, the
+	// response controller of the underlying writer reports it instead
+	var underlying http.ResponseWriter = s.ctx.Writer
+	if unwrapper, ok := underlying.(interface{ Unwrap() http.ResponseWriter }); ok {
+		underlying = unwrapper.Unwrap()
+	}
+	conn, _, err := http.NewResponseController(underlying).Hijack()
+	if err != nil {
+		return
+	}
+	conn.Close()
+}
+
+// --- Helper Functions ---
+
+// acceptsGzip reports whether the client accepts a gzip encoded export, via
+// Accept-Encoding or the gzip query parameter
+func acceptsGzip(ctx *gin.Context) bool {
+	if ctx.Query("gzip") == "true" {
+		return true
+	}
+	for _, part := range strings.Split(ctx.GetHeader("Accept-Encoding"), ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if strings.EqualFold(strings.TrimSpace(coding), "gzip") && strings.ReplaceAll(params, " ", "") != "q=0" {
+			return true
+		}
+	}
+	return false
+}
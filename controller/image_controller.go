@@ -0,0 +1,289 @@
+package controller
+
+import (
+	"errors"
+	"go-api/dto"
+	"go-api/model"
+	"go-api/usecase"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// multipartOverhead is allowed on top of the image size for the multipart envelope
+const multipartOverhead = 1 << 20
+
+// ImageController handles HTTP requests for product images
+type ImageController struct {
+	imageUsecase usecase.ImageUsecase
+	maxBytes     int64
+}
+
+// NewImageController creates a new ImageController; maxBytes caps the size of an uploaded file
+func NewImageController(usecase usecase.ImageUsecase, maxBytes int64) *ImageController {
+	return &ImageController{
+		imageUsecase: usecase,
+		maxBytes:     maxBytes,
+	}
+}
+
+// GetProductImages godoc
+// @Summary List the images of a product
+// @Description Get the images of a product ordered by position, with thumbnail URLs
+// @Tags images
+// @Accept json
+// @Produce json
+// @Param productId path int true "Product ID" minimum(1)
+// @Success 200 {array} dto.ProductImageResponse "Product images"
+// @Failure 400 {object} model.Response "Bad request - Invalid ID format"
+// @Failure 404 {object} model.Response "Product not found"
+// @Failure 500 {object} model.Response "Internal server error"
+// @Router /products/{productId}/images [get]
+func (ic *ImageController) GetProductImages(ctx *gin.Context) {
+	productId, err := strconv.Atoi(ctx.Param("productId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return
+	}
+
+	images, err := ic.imageUsecase.GetImages(productId)
+	if err != nil {
+		ctx.JSON(imageErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, toProductImageResponses(images))
+}
+
+// UploadProductImage godoc
+// @Summary Upload a product image
+// @Description Upload a JPEG, PNG or GIF image as the multipart field "file". The type is sniffed from the content; small, medium and large JPEG thumbnails are generated. The first image of a product becomes its primary image
+// @Tags images
+// @Accept mpfd
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param productId path int true "Product ID" minimum(1)
+// @Param file formData file true "Image file"
+// @Success 201 {object} dto.ProductImageResponse "Image uploaded successfully"
+// @Failure 400 {object} model.Response "Bad request - Missing file"
+// @Failure 401 {object} model.Response "Missing or invalid token"
+// @Failure 403 {object} model.Response "Admin role required, or API key or access token without the scope of the route"
+// @Failure 404 {object} model.Response "Product not found"
+// @Failure 413 {object} model.Response "File too large"
+// @Failure 415 {object} model.Response "Not a JPEG, PNG or GIF image"
+// @Failure 422 {object} model.Response "Image dimensions out of bounds"
+// @Failure 500 {object} model.Response "Internal server error"
+// @Router /products/{productId}/images [post]
+func (ic *ImageController) UploadProductImage(ctx *gin.Context) {
+	productId, err := strconv.Atoi(ctx.Param("productId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return
+	}
+
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, ic.maxBytes+multipartOverhead)
+	fileHeader, err := ctx.FormFile("file")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			ctx.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": usecase.ErrImageTooLarge.Error()})
+			return
+		}
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Missing image file"})
+		return
+	}
+	if fileHeader.Size > ic.maxBytes {
+		ctx.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": usecase.ErrImageTooLarge.Error()})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, ic.maxBytes+1))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	image, err := ic.imageUsecase.UploadImage(productId, data)
+	if err != nil {
+		ctx.JSON(imageErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, toProductImageResponse(*image))
+}
+
+// SetPrimaryImage godoc
+// @Summary Set the primary image of a product
+// @Description Make the image the primary image of its product
+// @Tags images
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param productId path int true "Product ID" minimum(1)
+// @Param imageId path int true "Image ID" minimum(1)
+// @Success 200 {array} dto.ProductImageResponse "Product images"
+// @Failure 400 {object} model.Response "Bad request - Invalid ID format"
+// @Failure 401 {object} model.Response "Missing or invalid token"
+// @Failure 403 {object} model.Response "Admin role required, or API key or access token without the scope of the route"
+// @Failure 404 {object} model.Response "Product or image not found"
+// @Failure 500 {object} model.Response "Internal server error"
+// @Router /products/{productId}/images/{imageId}/primary [post]
+func (ic *ImageController) SetPrimaryImage(ctx *gin.Context) {
+	productId, imageId, ok := imageParams(ctx)
+	if !ok {
+		return
+	}
+
+	images, err := ic.imageUsecase.SetPrimaryImage(productId, imageId)
+	if err != nil {
+		ctx.JSON(imageErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, toProductImageResponses(images))
+}
+
+// ReorderProductImages godoc
+// @Summary Reorder the images of a product
+// @Description Set the display order of the images; every image of the product must be listed once
+// @Tags images
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param productId path int true "Product ID" minimum(1)
+// @Param order body dto.ReorderImagesRequest true "Image IDs in the new order"
+// @Success 200 {array} dto.ProductImageResponse "Product images"
+// @Failure 400 {object} model.Response "Bad request - Invalid order"
+// @Failure 401 {object} model.Response "Missing or invalid token"
+// @Failure 403 {object} model.Response "Admin role required, or API key or access token without the scope of the route"
+// @Failure 404 {object} model.Response "Product not found"
+// @Failure 500 {object} model.Response "Internal server error"
+// @Router /products/{productId}/images/order [put]
+func (ic *ImageController) ReorderProductImages(ctx *gin.Context) {
+	productId, err := strconv.Atoi(ctx.Param("productId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return
+	}
+
+	var req dto.ReorderImagesRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	images, err := ic.imageUsecase.ReorderImages(productId, req.ImageIDs)
+	if err != nil {
+		ctx.JSON(imageErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, toProductImageResponses(images))
+}
+
+// DeleteProductImage godoc
+// @Summary Delete a product image
+// @Description Delete an image and its thumbnails; if it was the primary image the next one takes its place
+// @Tags images
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param productId path int true "Product ID" minimum(1)
+// @Param imageId path int true "Image ID" minimum(1)
+// @Success 204 "Image deleted successfully"
+// @Failure 400 {object} model.Response "Bad request - Invalid ID format"
+// @Failure 401 {object} model.Response "Missing or invalid token"
+// @Failure 403 {object} model.Response "Admin role required, or API key or access token without the scope of the route"
+// @Failure 404 {object} model.Response "Product or image not found"
+// @Failure 500 {object} model.Response "Internal server error"
+// @Router /products/{productId}/images/{imageId} [delete]
+func (ic *ImageController) DeleteProductImage(ctx *gin.Context) {
+	productId, imageId, ok := imageParams(ctx)
+	if !ok {
+		return
+	}
+
+	if err := ic.imageUsecase.DeleteImage(productId, imageId); err != nil {
+		ctx.JSON(imageErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// --- Helper Functions ---
+
+func imageParams(ctx *gin.Context) (int, int, bool) {
+	productId, err := strconv.Atoi(ctx.Param("productId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return 0, 0, false
+	}
+	imageId, err := strconv.Atoi(ctx.Param("imageId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid image ID"})
+		return 0, 0, false
+	}
+	return productId, imageId, true
+}
+
+func imageErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, usecase.ErrProductNotFound), errors.Is(err, usecase.ErrImageNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, usecase.ErrImageTooLarge):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, usecase.ErrUnsupportedImageType):
+		return http.StatusUnsupportedMediaType
+	case errors.Is(err, usecase.ErrInvalidImageDimensions):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, usecase.ErrInvalidImageOrder):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func toProductImageResponse(image model.ProductImage) dto.ProductImageResponse {
+	thumbnails := make(map[string]dto.ImageThumbnailResponse, len(image.Thumbnails))
+	for _, thumbnail := range image.Thumbnails {
+		thumbnails[thumbnail.Name] = dto.ImageThumbnailResponse{
+			URL:    thumbnail.URL,
+			Width:  thumbnail.Width,
+			Height: thumbnail.Height,
+		}
+	}
+	return dto.ProductImageResponse{
+		ID:          image.ID,
+		URL:         image.URL,
+		ContentType: image.ContentType,
+		Width:       image.Width,
+		Height:      image.Height,
+		Size:        image.Size,
+		Position:    image.Position,
+		Primary:     image.Primary,
+		Thumbnails:  thumbnails,
+		CreatedAt:   image.CreatedAt,
+	}
+}
+
+func toProductImageResponses(images []model.ProductImage) []dto.ProductImageResponse {
+	responses := make([]dto.ProductImageResponse, 0, len(images))
+	for _, image := range images {
+		responses = append(responses, toProductImageResponse(image))
+	}
+	return responses
+}
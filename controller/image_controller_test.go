@@ -0,0 +1,162 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"go-api/dto"
+	"go-api/model"
+	"go-api/usecase"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func multipartImage(t *testing.T, content []byte) (*bytes.Buffer, string) {
+	return multipartFile(t, "photo.png", content)
+}
+
+func multipartFile(t *testing.T, filename string, content []byte) (*bytes.Buffer, string) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile("file", filename)
+	assert.NoError(t, err)
+	part.Write(content)
+	assert.NoError(t, writer.Close())
+	return body, writer.FormDataContentType()
+}
+
+func TestUploadProductImage(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Success", func(t *testing.T) {
+		mockUsecase := &MockImageUsecase{
+			UploadImageFunc: func(productID int, data []byte) (*model.ProductImage, error) {
+				assert.Equal(t, 1, productID)
+				assert.Equal(t, []byte("image bytes"), data)
+				return &model.ProductImage{ID: 5, ProductID: productID, URL: "http://localhost:8000/media/products/1/a/original.png", Primary: true,
+					Thumbnails: []model.ImageThumbnail{{Name: "small", Width: 150, Height: 100, URL: "http://localhost:8000/media/products/1/a/small.jpg"}}}, nil
+			},
+		}
+
+		body, contentType := multipartImage(t, []byte("image bytes"))
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Params = gin.Params{{Key: "productId", Value: "1"}}
+		c.Request, _ = http.NewRequest(http.MethodPost, "/products/1/images", body)
+		c.Request.Header.Set("Content-Type", contentType)
+
+		imageController := NewImageController(mockUsecase, usecase.DefaultImageLimits.MaxBytes)
+		imageController.UploadProductImage(c)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		var response dto.ProductImageResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.True(t, response.Primary)
+		assert.Equal(t, "http://localhost:8000/media/products/1/a/small.jpg", response.Thumbnails["small"].URL)
+	})
+
+	t.Run("File Too Large", func(t *testing.T) {
+		mockUsecase := &MockImageUsecase{
+			UploadImageFunc: func(productID int, data []byte) (*model.ProductImage, error) {
+				t.Fatal("usecase should not be called")
+				return nil, nil
+			},
+		}
+
+		body, contentType := multipartImage(t, bytes.Repeat([]byte("x"), 2048))
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Params = gin.Params{{Key: "productId", Value: "1"}}
+		c.Request, _ = http.NewRequest(http.MethodPost, "/products/1/images", body)
+		c.Request.Header.Set("Content-Type", contentType)
+
+		imageController := NewImageController(mockUsecase, 1024)
+		imageController.UploadProductImage(c)
+
+		assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	})
+
+	t.Run("Missing File", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Params = gin.Params{{Key: "productId", Value: "1"}}
+		c.Request, _ = http.NewRequest(http.MethodPost, "/products/1/images", bytes.NewBufferString(`{}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		imageController := NewImageController(&MockImageUsecase{}, 1024)
+		imageController.UploadProductImage(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Unsupported Type", func(t *testing.T) {
+		mockUsecase := &MockImageUsecase{
+			UploadImageFunc: func(productID int, data []byte) (*model.ProductImage, error) {
+				return nil, usecase.ErrUnsupportedImageType
+			},
+		}
+
+		body, contentType := multipartImage(t, []byte("<html></html>"))
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Params = gin.Params{{Key: "productId", Value: "1"}}
+		c.Request, _ = http.NewRequest(http.MethodPost, "/products/1/images", body)
+		c.Request.Header.Set("Content-Type", contentType)
+
+		imageController := NewImageController(mockUsecase, 1024)
+		imageController.UploadProductImage(c)
+
+		assert.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+	})
+}
+
+func TestReorderProductImages(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Invalid Order", func(t *testing.T) {
+		mockUsecase := &MockImageUsecase{
+			ReorderImagesFunc: func(productID int, imageIDs []int) ([]model.ProductImage, error) {
+				assert.Equal(t, []int{3, 1}, imageIDs)
+				return nil, usecase.ErrInvalidImageOrder
+			},
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Params = gin.Params{{Key: "productId", Value: "1"}}
+		c.Request, _ = http.NewRequest(http.MethodPut, "/products/1/images/order", bytes.NewBufferString(`{"image_ids": [3, 1]}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		imageController := NewImageController(mockUsecase, 1024)
+		imageController.ReorderProductImages(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestDeleteProductImage(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Not Found", func(t *testing.T) {
+		mockUsecase := &MockImageUsecase{
+			DeleteImageFunc: func(productID, imageID int) error {
+				return usecase.ErrImageNotFound
+			},
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Params = gin.Params{{Key: "productId", Value: "1"}, {Key: "imageId", Value: "9"}}
+		c.Request, _ = http.NewRequest(http.MethodDelete, "/products/1/images/9", nil)
+
+		imageController := NewImageController(mockUsecase, 1024)
+		imageController.DeleteProductImage(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
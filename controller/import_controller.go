@@ -0,0 +1,314 @@
+package controller
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-api/dto"
+	"go-api/model"
+	"go-api/usecase"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// Formats accepted by the product import
+const (
+	importFormatCSV    = "csv"
+	importFormatNDJSON = "ndjson"
+)
+
+// ImportController handles HTTP requests for bulk product imports
+type ImportController struct {
+	importUsecase usecase.ImportUsecase
+}
+
+// NewImportController creates a new ImportController
+func NewImportController(usecase usecase.ImportUsecase) *ImportController {
+	return &ImportController{
+		importUsecase: usecase,
+	}
+}
+
+// ImportProducts godoc
+// @Summary Import products in bulk
+// @Description Start a background import of a CSV (columns name, price, currency, sku) or NDJSON (one CreateProductRequest per line) file, sent as the multipart field "file" or as the raw body. Each row is validated like POST /product and upserted by SKU, or by name when it has none. Poll the returned job for progress and the per-row error report
+// @Tags products
+// @Accept multipart/form-data,text/csv,application/x-ndjson
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param file formData file false "CSV or NDJSON file"
+// @Param format query string false "csv or ndjson, detected from the Content-Type or file extension when omitted"
+// @Param dry_run query bool false "Validate and match the rows without writing anything"
+// @Success 202 {object} dto.ImportJobResponse "Import started"
+// @Failure 400 {object} model.Response "Bad request - Empty file or invalid dry_run"
+// @Failure 401 {object} model.Response "Missing or invalid token"
+// @Failure 403 {object} model.Response "Admin role required, or API key or access token without the scope of the route"
+// @Failure 413 {object} model.Response "File too large"
+// @Failure 415 {object} model.Response "Unknown file format"
+// @Failure 500 {object} model.Response "Internal server error"
+// @Router /products/import [post]
+func (ic *ImportController) ImportProducts(ctx *gin.Context) {
+	dryRun := false
+	if value := ctx.Query("dry_run"); value != "" {
+		var err error
+		if dryRun, err = strconv.ParseBool(value); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid dry_run, use true or false"})
+			return
+		}
+	}
+
+	var reader io.Reader = ctx.Request.Body
+	filename := ""
+	if fileHeader, err := ctx.FormFile("file"); err == nil {
+		file, err := fileHeader.Open()
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		defer file.Close()
+		reader = file
+		filename = fileHeader.Filename
+	}
+
+	format := importFormat(ctx, filename)
+	var newDecoder func(io.Reader) (usecase.RowDecoder, error)
+	switch format {
+	case importFormatCSV:
+		newDecoder = newCSVProductDecoder
+	case importFormatNDJSON:
+		newDecoder = newNDJSONProductDecoder
+	default:
+		ctx.JSON(http.StatusUnsupportedMediaType, gin.H{"error": "Unknown import format, use format=csv or format=ndjson"})
+		return
+	}
+
+	job, err := ic.importUsecase.StartProductImport(ctx.Request.Context(), reader, usecase.ImportOptions{
+		Format:     format,
+		DryRun:     dryRun,
+		NewDecoder: newDecoder,
+	})
+	if err != nil {
+		ctx.JSON(importErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Header("Location", "/products/import/"+job.ID)
+	ctx.JSON(http.StatusAccepted, toImportJobResponse(job))
+}
+
+// GetImportJob godoc
+// @Summary Get the progress of a product import
+// @Description Get the status, progress, counters and per-row error report of an import job
+// @Tags products
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param jobId path string true "Import job ID"
+// @Success 200 {object} dto.ImportJobResponse "Import job"
+// @Failure 401 {object} model.Response "Missing or invalid token"
+// @Failure 403 {object} model.Response "Admin role required, or API key or access token without the scope of the route"
+// @Failure 404 {object} model.Response "Import job not found"
+// @Router /products/import/{jobId} [get]
+func (ic *ImportController) GetImportJob(ctx *gin.Context) {
+	job, err := ic.importUsecase.GetImportJob(ctx.Param("jobId"))
+	if err != nil {
+		ctx.JSON(importErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, toImportJobResponse(job))
+}
+
+// --- Helper Functions ---
+
+// importFormat takes the format query parameter, then the Content-Type of a
+// raw body, then the extension of an uploaded file
+func importFormat(ctx *gin.Context, filename string) string {
+	if format := strings.ToLower(ctx.Query("format")); format != "" {
+		return format
+	}
+	if filename != "" {
+		switch strings.ToLower(filepath.Ext(filename)) {
+		case ".csv":
+			return importFormatCSV
+		case ".ndjson", ".jsonl":
+			return importFormatNDJSON
+		}
+		return ""
+	}
+	mediaType, _, _ := mime.ParseMediaType(ctx.GetHeader("Content-Type"))
+	switch mediaType {
+	case "text/csv":
+		return importFormatCSV
+	case "application/x-ndjson", "application/ndjson", "application/jsonl", "application/x-jsonlines":
+		return importFormatNDJSON
+	}
+	return ""
+}
+
+func importErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, usecase.ErrImportJobNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, usecase.ErrImportTooLarge):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, usecase.ErrEmptyImport):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// importRow validates a decoded row with the rules of POST /product
+func importRow(line int, req dto.CreateProductRequest) usecase.ImportRow {
+	row := usecase.ImportRow{Line: line}
+	if err := binding.Validator.ValidateStruct(&req); err != nil {
+		row.Err = err
+		return row
+	}
+	row.Product, row.Err = toProductModel(req)
+	return row
+}
+
+// csvProductDecoder reads products from a CSV file with a header row
+type csvProductDecoder struct {
+	reader  *csv.Reader
+	columns map[string]int
+}
+
+// csvProductColumns maps the accepted header names to the request fields
+var csvProductColumns = map[string]string{
+	"name":         "name",
+	"product_name": "name",
+	"price":        "price",
+	"currency":     "currency",
+	"sku":          "sku",
+}
+
+func newCSVProductDecoder(r io.Reader) (usecase.RowDecoder, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("reading the CSV header: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		field, ok := csvProductColumns[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown CSV column %q, use name, price, currency and sku", name)
+		}
+		if _, repeated := columns[field]; repeated {
+			return nil, fmt.Errorf("repeated CSV column %q", name)
+		}
+		columns[field] = i
+	}
+	for _, required := range []string{"name", "price"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("missing CSV column %q", required)
+		}
+	}
+	return &csvProductDecoder{reader: reader, columns: columns}, nil
+}
+
+func (d *csvProductDecoder) Next() (usecase.ImportRow, error) {
+	record, err := d.reader.Read()
+	if err == io.EOF {
+		return usecase.ImportRow{}, io.EOF
+	}
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return usecase.ImportRow{Line: parseErr.StartLine, Err: parseErr.Err}, nil
+	}
+	if err != nil {
+		return usecase.ImportRow{}, err
+	}
+
+	line, _ := d.reader.FieldPos(0)
+	field := func(name string) string {
+		if i, ok := d.columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+	return importRow(line, dto.CreateProductRequest{
+		Name:     field("name"),
+		Price:    json.Number(field("price")),
+		Currency: field("currency"),
+		SKU:      field("sku"),
+	}), nil
+}
+
+// ndjsonProductDecoder reads one CreateProductRequest JSON object per line
+type ndjsonProductDecoder struct {
+	reader *bufio.Reader
+	line   int
+}
+
+func newNDJSONProductDecoder(r io.Reader) (usecase.RowDecoder, error) {
+	return &ndjsonProductDecoder{reader: bufio.NewReader(r)}, nil
+}
+
+func (d *ndjsonProductDecoder) Next() (usecase.ImportRow, error) {
+	for {
+		data, err := d.reader.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return usecase.ImportRow{}, err
+		}
+		if len(data) == 0 && err == io.EOF {
+			return usecase.ImportRow{}, io.EOF
+		}
+		d.line++
+
+		data = bytes.TrimSpace(data)
+		if len(data) == 0 {
+			continue
+		}
+		var req dto.CreateProductRequest
+		if err := json.Unmarshal(data, &req); err != nil {
+			return usecase.ImportRow{Line: d.line, Err: err}, nil
+		}
+		return importRow(d.line, req), nil
+	}
+}
+
+func toImportJobResponse(job model.ImportJob) dto.ImportJobResponse {
+	rowErrors := make([]dto.ImportRowErrorResponse, 0, len(job.Errors))
+	for _, rowErr := range job.Errors {
+		rowErrors = append(rowErrors, dto.ImportRowErrorResponse{Line: rowErr.Line, Message: rowErr.Message})
+	}
+	progress := 0
+	if job.TotalBytes > 0 {
+		progress = int(job.ReadBytes * 100 / job.TotalBytes)
+	}
+	return dto.ImportJobResponse{
+		ID:         job.ID,
+		Status:     job.Status,
+		Format:     job.Format,
+		DryRun:     job.DryRun,
+		Progress:   progress,
+		Rows:       job.Rows,
+		Created:    job.Created,
+		Updated:    job.Updated,
+		Failed:     job.Failed,
+		Errors:     rowErrors,
+		Error:      job.Error,
+		CreatedAt:  job.CreatedAt,
+		FinishedAt: job.FinishedAt,
+	}
+}
@@ -0,0 +1,212 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"go-api/dto"
+	"go-api/model"
+	"go-api/usecase"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func decodeAll(t *testing.T, decoder usecase.RowDecoder) []usecase.ImportRow {
+	var rows []usecase.ImportRow
+	for {
+		row, err := decoder.Next()
+		if err == io.EOF {
+			return rows
+		}
+		require.NoError(t, err)
+		rows = append(rows, row)
+	}
+}
+
+func TestCSVProductDecoder(t *testing.T) {
+	t.Run("Validates Rows Like CreateProduct", func(t *testing.T) {
+		file := "\ufeffName,Price,Currency,SKU\n" +
+			"Camiseta,49.90,,cam-01\n" +
+			"\n" +
+			"Caneca,abc,BRL,\n" +
+			",10,BRL,\n" +
+			"Boné,\"29.90\",USDX,\n" +
+			"Meia,-1,BRL,\n"
+
+		decoder, err := newCSVProductDecoder(strings.NewReader(file))
+		require.NoError(t, err)
+		rows := decodeAll(t, decoder)
+
+		require.Len(t, rows, 5)
+		assert.NoError(t, rows[0].Err)
+		assert.Equal(t, 2, rows[0].Line)
+		assert.Equal(t, model.Product{Name: "Camiseta", SKU: "cam-01", Price: model.Money{Amount: 4990, Currency: "BRL"}}, rows[0].Product)
+		assert.Equal(t, 4, rows[1].Line)
+		assert.ErrorIs(t, rows[1].Err, model.ErrInvalidAmount)
+		assert.ErrorContains(t, rows[2].Err, "'Name' failed on the 'required' tag")
+		assert.ErrorContains(t, rows[3].Err, "'Currency' failed on the 'len' tag")
+		assert.ErrorContains(t, rows[4].Err, "price must not be negative")
+	})
+
+	t.Run("Header Errors", func(t *testing.T) {
+		_, err := newCSVProductDecoder(strings.NewReader("name\nCamiseta\n"))
+		assert.EqualError(t, err, `missing CSV column "price"`)
+
+		_, err = newCSVProductDecoder(strings.NewReader("name,price,color\n"))
+		assert.ErrorContains(t, err, `unknown CSV column "color"`)
+	})
+}
+
+func TestNDJSONProductDecoder(t *testing.T) {
+	file := `{"name": "Camiseta", "price": 49.9, "sku": "CAM-01"}
+
+{"name": "Caneca", "price": "19.90", "currency": "USD"}
+{"name": "Boné"}
+not json`
+
+	decoder, err := newNDJSONProductDecoder(strings.NewReader(file))
+	require.NoError(t, err)
+	rows := decodeAll(t, decoder)
+
+	require.Len(t, rows, 4)
+	assert.Equal(t, model.Money{Amount: 4990, Currency: "BRL"}, rows[0].Product.Price)
+	assert.Equal(t, 3, rows[1].Line)
+	assert.Equal(t, model.Money{Amount: 1990, Currency: "USD"}, rows[1].Product.Price)
+	assert.ErrorContains(t, rows[2].Err, "'Price' failed on the 'required' tag")
+	assert.Equal(t, 5, rows[3].Line)
+	assert.Error(t, rows[3].Err)
+}
+
+func TestImportProducts(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Starts Job From Raw Body", func(t *testing.T) {
+		mockUsecase := &MockImportUsecase{
+			StartProductImportFunc: func(ctx context.Context, body io.Reader, opts usecase.ImportOptions) (model.ImportJob, error) {
+				data, _ := io.ReadAll(body)
+				assert.Equal(t, "name,price\nCamiseta,49.90\n", string(data))
+				assert.Equal(t, "csv", opts.Format)
+				assert.True(t, opts.DryRun)
+				return model.ImportJob{ID: "abc", Status: model.ImportStatusPending, Format: opts.Format, DryRun: true, TotalBytes: 10}, nil
+			},
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodPost, "/products/import?dry_run=true", bytes.NewBufferString("name,price\nCamiseta,49.90\n"))
+		c.Request.Header.Set("Content-Type", "text/csv; charset=utf-8")
+
+		importController := NewImportController(mockUsecase)
+		importController.ImportProducts(c)
+
+		assert.Equal(t, http.StatusAccepted, w.Code)
+		assert.Equal(t, "/products/import/abc", w.Header().Get("Location"))
+		var response dto.ImportJobResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "abc", response.ID)
+		assert.Empty(t, response.Errors)
+	})
+
+	t.Run("Unknown Format", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodPost, "/products/import", bytes.NewBufferString("<xml/>"))
+		c.Request.Header.Set("Content-Type", "application/xml")
+
+		importController := NewImportController(&MockImportUsecase{})
+		importController.ImportProducts(c)
+
+		assert.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+	})
+
+	t.Run("Format From File Extension", func(t *testing.T) {
+		var format string
+		mockUsecase := &MockImportUsecase{
+			StartProductImportFunc: func(ctx context.Context, body io.Reader, opts usecase.ImportOptions) (model.ImportJob, error) {
+				format = opts.Format
+				return model.ImportJob{ID: "abc"}, nil
+			},
+		}
+
+		body, contentType := multipartFile(t, "catalog.jsonl", []byte(`{"name": "Camiseta", "price": 1}`))
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodPost, "/products/import", body)
+		c.Request.Header.Set("Content-Type", contentType)
+
+		importController := NewImportController(mockUsecase)
+		importController.ImportProducts(c)
+
+		assert.Equal(t, http.StatusAccepted, w.Code)
+		assert.Equal(t, "ndjson", format)
+	})
+
+	t.Run("File Too Large", func(t *testing.T) {
+		mockUsecase := &MockImportUsecase{
+			StartProductImportFunc: func(ctx context.Context, body io.Reader, opts usecase.ImportOptions) (model.ImportJob, error) {
+				return model.ImportJob{}, usecase.ErrImportTooLarge
+			},
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodPost, "/products/import?format=csv", bytes.NewBufferString("x"))
+
+		importController := NewImportController(mockUsecase)
+		importController.ImportProducts(c)
+
+		assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	})
+}
+
+func TestGetImportJob(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Reports Progress", func(t *testing.T) {
+		mockUsecase := &MockImportUsecase{
+			GetImportJobFunc: func(id string) (model.ImportJob, error) {
+				return model.ImportJob{ID: id, Status: model.ImportStatusRunning, TotalBytes: 200, ReadBytes: 50, Rows: 10, Failed: 1,
+					Errors: []model.ImportRowError{{Line: 3, Message: "duplicate of line 2"}}}, nil
+			},
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Params = gin.Params{{Key: "jobId", Value: "abc"}}
+		c.Request, _ = http.NewRequest(http.MethodGet, "/products/import/abc", nil)
+
+		importController := NewImportController(mockUsecase)
+		importController.GetImportJob(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response dto.ImportJobResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, 25, response.Progress)
+		assert.Equal(t, []dto.ImportRowErrorResponse{{Line: 3, Message: "duplicate of line 2"}}, response.Errors)
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		mockUsecase := &MockImportUsecase{
+			GetImportJobFunc: func(id string) (model.ImportJob, error) {
+				return model.ImportJob{}, usecase.ErrImportJobNotFound
+			},
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Params = gin.Params{{Key: "jobId", Value: "nope"}}
+		c.Request, _ = http.NewRequest(http.MethodGet, "/products/import/nope", nil)
+
+		importController := NewImportController(mockUsecase)
+		importController.GetImportJob(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
@@ -0,0 +1,59 @@
+package controller
+
+import (
+	"errors"
+	"go-api/usecase"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LoginThrottleController handles HTTP requests for the lockouts of failed logins
+type LoginThrottleController struct {
+	loginThrottleUsecase usecase.LoginThrottleUsecase
+}
+
+// NewLoginThrottleController creates a new LoginThrottleController
+func NewLoginThrottleController(usecase usecase.LoginThrottleUsecase) *LoginThrottleController {
+	return &LoginThrottleController{
+		loginThrottleUsecase: usecase,
+	}
+}
+
+// Unlock godoc
+// @Summary Clear a login lockout
+// @Description Lift the lockout, or the wait, of an account and/or an IP and forget their failed logins. The unlock is recorded in the audit log
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Param email query string false "Email of the account"
+// @Param ip query string false "IP address"
+// @Success 204 "Lockout cleared"
+// @Failure 400 {object} model.Response "Bad request - Neither email nor ip given"
+// @Failure 401 {object} model.Response "Missing or invalid token"
+// @Failure 403 {object} model.Response "Admin role required"
+// @Failure 500 {object} model.Response "Internal server error"
+// @Router /auth/lockouts [delete]
+func (lc *LoginThrottleController) Unlock(ctx *gin.Context) {
+	err := lc.loginThrottleUsecase.Unlock(ctx.Request.Context(), ctx.Query("email"), ctx.Query("ip"))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, usecase.ErrNothingToUnlock) {
+			status = http.StatusBadRequest
+		}
+		ctx.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// --- Helper Functions ---
+
+// setRetryAfter tells the client, in whole seconds rounded up, when to try again
+func setRetryAfter(ctx *gin.Context, wait time.Duration) {
+	ctx.Header("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+}
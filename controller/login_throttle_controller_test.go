@@ -0,0 +1,46 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"go-api/usecase"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnlockLogin(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{"Unlocked", nil, http.StatusNoContent},
+		{"No Target", usecase.ErrNothingToUnlock, http.StatusBadRequest},
+		{"Internal Error", errors.New("db down"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUsecase := &MockLoginThrottleUsecase{
+				UnlockFunc: func(ctx context.Context, email, ip string) error {
+					assert.Equal(t, "ana@example.com", email)
+					assert.Equal(t, "203.0.113.9", ip)
+					return tt.err
+				},
+			}
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest(http.MethodDelete, "/auth/lockouts?email=ana@example.com&ip=203.0.113.9", nil)
+
+			NewLoginThrottleController(mockUsecase).Unlock(c)
+
+			assert.Equal(t, tt.status, c.Writer.Status())
+		})
+	}
+}
@@ -0,0 +1,196 @@
+package controller
+
+import (
+	"errors"
+	"go-api/dto"
+	"go-api/middleware"
+	"go-api/usecase"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MFAController handles HTTP requests for two-factor authentication
+type MFAController struct {
+	mfaUsecase usecase.MFAUsecase
+}
+
+// NewMFAController creates a new MFAController
+func NewMFAController(usecase usecase.MFAUsecase) *MFAController {
+	return &MFAController{
+		mfaUsecase: usecase,
+	}
+}
+
+// Enroll godoc
+// @Summary Start two-factor enrollment
+// @Description Create a TOTP secret for the logged in user, as text and as an otpauth URI to show as a QR code. It replaces a previous enrollment not confirmed yet and only takes effect once confirmed with a code
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} dto.MFAEnrollResponse "Secret created"
+// @Failure 401 {object} model.Response "Missing or invalid token"
+// @Failure 409 {object} model.Response "Two-factor authentication already enabled"
+// @Failure 500 {object} model.Response "Internal server error"
+// @Router /auth/mfa/enroll [post]
+func (mc *MFAController) Enroll(ctx *gin.Context) {
+	response, err := mc.mfaUsecase.Enroll(ctx.Request.Context(), ctx.GetInt(middleware.ContextUserID))
+	if err != nil {
+		mfaError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// Confirm godoc
+// @Summary Confirm two-factor enrollment
+// @Description Turn two-factor authentication on with a code of the authenticator app and return the recovery codes, each usable once in place of a code. They are not shown again
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param code body dto.MFACodeRequest true "Code of the authenticator app"
+// @Success 200 {object} dto.MFARecoveryCodesResponse "Two-factor authentication enabled"
+// @Failure 400 {object} model.Response "Bad request - Invalid input data"
+// @Failure 401 {object} model.Response "Missing or invalid token, or wrong code"
+// @Failure 409 {object} model.Response "Nothing to confirm, or already enabled"
+// @Failure 429 {object} model.Response "Too many wrong codes; the Retry-After header tells when to try again"
+// @Failure 500 {object} model.Response "Internal server error"
+// @Router /auth/mfa/confirm [post]
+func (mc *MFAController) Confirm(ctx *gin.Context) {
+	var req dto.MFACodeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	response, err := mc.mfaUsecase.Confirm(ctx.Request.Context(), ctx.GetInt(middleware.ContextUserID), req.Code)
+	if err != nil {
+		mfaError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// RegenerateRecoveryCodes godoc
+// @Summary Replace the recovery codes
+// @Description Discard the recovery codes of the logged in user, used or not, and return new ones. Takes a code of the authenticator app or a recovery code
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param code body dto.MFACodeRequest true "Code of the authenticator app or recovery code"
+// @Success 200 {object} dto.MFARecoveryCodesResponse "New recovery codes"
+// @Failure 400 {object} model.Response "Bad request - Invalid input data"
+// @Failure 401 {object} model.Response "Missing or invalid token, or wrong code"
+// @Failure 409 {object} model.Response "Two-factor authentication not enabled"
+// @Failure 429 {object} model.Response "Too many wrong codes; the Retry-After header tells when to try again"
+// @Failure 500 {object} model.Response "Internal server error"
+// @Router /auth/mfa/recovery-codes [post]
+func (mc *MFAController) RegenerateRecoveryCodes(ctx *gin.Context) {
+	var req dto.MFACodeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	response, err := mc.mfaUsecase.RegenerateRecoveryCodes(ctx.Request.Context(), ctx.GetInt(middleware.ContextUserID), req.Code)
+	if err != nil {
+		mfaError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// Disable godoc
+// @Summary Turn two-factor authentication off
+// @Description Remove the TOTP secret and the recovery codes of the logged in user. Takes a code of the authenticator app or a recovery code
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param code body dto.MFACodeRequest true "Code of the authenticator app or recovery code"
+// @Success 204 "Two-factor authentication disabled"
+// @Failure 400 {object} model.Response "Bad request - Invalid input data"
+// @Failure 401 {object} model.Response "Missing or invalid token, or wrong code"
+// @Failure 409 {object} model.Response "Two-factor authentication not enabled"
+// @Failure 429 {object} model.Response "Too many wrong codes; the Retry-After header tells when to try again"
+// @Failure 500 {object} model.Response "Internal server error"
+// @Router /auth/mfa [delete]
+func (mc *MFAController) Disable(ctx *gin.Context) {
+	var req dto.MFACodeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := mc.mfaUsecase.Disable(ctx.Request.Context(), ctx.GetInt(middleware.ContextUserID), req.Code); err != nil {
+		mfaError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// Verify godoc
+// @Summary Finish a two-factor login
+// @Description Exchange the mfa_token returned by /login, valid for 5 minutes, and a code of the authenticator app or a recovery code for the JWT token. The anonymous cart of cart_token (or of the X-Cart-Token header) is merged into the user's cart. Wrong codes count as failed logins
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body dto.MFAVerifyRequest true "Challenge and code"
+// @Param X-Cart-Token header string false "Token of the anonymous cart to merge"
+// @Success 200 {object} dto.LoginResponse "Login successful"
+// @Failure 400 {object} model.Response "Bad request - Invalid input data"
+// @Failure 401 {object} model.Response "Invalid or expired mfa_token, or wrong code"
+// @Failure 429 {object} model.Response "Too many wrong codes; the Retry-After header tells when to try again"
+// @Failure 500 {object} model.Response "Internal server error"
+// @Router /auth/mfa/verify [post]
+func (mc *MFAController) Verify(ctx *gin.Context) {
+	var req dto.MFAVerifyRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.CartToken == "" {
+		req.CartToken = ctx.GetHeader(CartTokenHeader)
+	}
+
+	response, err := mc.mfaUsecase.Verify(ctx.Request.Context(), req)
+	if err != nil {
+		mfaError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// --- Helper Functions ---
+
+// mfaError answers with the status of err, telling throttled clients when
+// to try again
+func mfaError(ctx *gin.Context, err error) {
+	var throttled *usecase.LoginThrottledError
+	if errors.As(err, &throttled) {
+		setRetryAfter(ctx, throttled.RetryAfter)
+	}
+	ctx.JSON(mfaErrorStatus(err), gin.H{"error": err.Error()})
+}
+
+func mfaErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, usecase.ErrInvalidMFAToken), errors.Is(err, usecase.ErrInvalidMFACode):
+		return http.StatusUnauthorized
+	case errors.Is(err, usecase.ErrMFAAlreadyEnabled), errors.Is(err, usecase.ErrMFANotEnabled), errors.Is(err, usecase.ErrMFANotPending):
+		return http.StatusConflict
+	case errors.Is(err, usecase.ErrLoginThrottled):
+		return http.StatusTooManyRequests
+	case errors.Is(err, usecase.ErrUserNotFound):
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
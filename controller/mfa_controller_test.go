@@ -0,0 +1,194 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"go-api/dto"
+	"go-api/middleware"
+	"go-api/usecase"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnrollMFA(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Success", func(t *testing.T) {
+		mockUsecase := &MockMFAUsecase{
+			EnrollFunc: func(ctx context.Context, userID int) (*dto.MFAEnrollResponse, error) {
+				assert.Equal(t, 7, userID)
+				return &dto.MFAEnrollResponse{Secret: "ABC", OTPAuthURI: "otpauth://totp/go-api:ana?secret=ABC"}, nil
+			},
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodPost, "/auth/mfa/enroll", nil)
+		c.Set(middleware.ContextUserID, 7)
+
+		NewMFAController(mockUsecase).Enroll(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response dto.MFAEnrollResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "ABC", response.Secret)
+	})
+
+	t.Run("Already Enabled", func(t *testing.T) {
+		mockUsecase := &MockMFAUsecase{
+			EnrollFunc: func(ctx context.Context, userID int) (*dto.MFAEnrollResponse, error) {
+				return nil, usecase.ErrMFAAlreadyEnabled
+			},
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodPost, "/auth/mfa/enroll", nil)
+
+		NewMFAController(mockUsecase).Enroll(c)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+}
+
+func TestConfirmMFA(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name   string
+		body   string
+		err    error
+		status int
+	}{
+		{"Success", `{"code":"123456"}`, nil, http.StatusOK},
+		{"Missing Code", `{}`, nil, http.StatusBadRequest},
+		{"Wrong Code", `{"code":"123456"}`, usecase.ErrInvalidMFACode, http.StatusUnauthorized},
+		{"Nothing Pending", `{"code":"123456"}`, usecase.ErrMFANotPending, http.StatusConflict},
+		{"Internal Error", `{"code":"123456"}`, errors.New("db down"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUsecase := &MockMFAUsecase{
+				ConfirmFunc: func(ctx context.Context, userID int, code string) (*dto.MFARecoveryCodesResponse, error) {
+					assert.Equal(t, 7, userID)
+					assert.Equal(t, "123456", code)
+					if tt.err != nil {
+						return nil, tt.err
+					}
+					return &dto.MFARecoveryCodesResponse{RecoveryCodes: []string{"AAAA-BBBB-CCCC-DDDD"}}, nil
+				},
+			}
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest(http.MethodPost, "/auth/mfa/confirm", strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Set(middleware.ContextUserID, 7)
+
+			NewMFAController(mockUsecase).Confirm(c)
+
+			assert.Equal(t, tt.status, w.Code)
+		})
+	}
+}
+
+func TestDisableMFA(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{"Disabled", nil, http.StatusNoContent},
+		{"Wrong Code", usecase.ErrInvalidMFACode, http.StatusUnauthorized},
+		{"Not Enabled", usecase.ErrMFANotEnabled, http.StatusConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUsecase := &MockMFAUsecase{
+				DisableFunc: func(ctx context.Context, userID int, code string) error {
+					assert.Equal(t, 7, userID)
+					return tt.err
+				},
+			}
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest(http.MethodDelete, "/auth/mfa", strings.NewReader(`{"code":"AAAA-BBBB-CCCC-DDDD"}`))
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Set(middleware.ContextUserID, 7)
+
+			NewMFAController(mockUsecase).Disable(c)
+
+			assert.Equal(t, tt.status, c.Writer.Status())
+		})
+	}
+}
+
+func TestVerifyMFA(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Success With Cart Header", func(t *testing.T) {
+		mockUsecase := &MockMFAUsecase{
+			VerifyFunc: func(ctx context.Context, request dto.MFAVerifyRequest) (*dto.LoginResponse, error) {
+				assert.Equal(t, "challenge", request.MFAToken)
+				assert.Equal(t, "anon-token", request.CartToken)
+				return &dto.LoginResponse{Token: "jwt"}, nil
+			},
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodPost, "/auth/mfa/verify", strings.NewReader(`{"mfa_token":"challenge","code":"123456"}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Request.Header.Set(CartTokenHeader, "anon-token")
+
+		NewMFAController(mockUsecase).Verify(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"token":"jwt"}`, w.Body.String())
+	})
+
+	t.Run("Throttled", func(t *testing.T) {
+		mockUsecase := &MockMFAUsecase{
+			VerifyFunc: func(ctx context.Context, request dto.MFAVerifyRequest) (*dto.LoginResponse, error) {
+				return nil, &usecase.LoginThrottledError{RetryAfter: 1500 * time.Millisecond}
+			},
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodPost, "/auth/mfa/verify", strings.NewReader(`{"mfa_token":"challenge","code":"123456"}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		NewMFAController(mockUsecase).Verify(c)
+
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.Equal(t, "2", w.Header().Get("Retry-After"))
+	})
+
+	t.Run("Invalid Challenge", func(t *testing.T) {
+		mockUsecase := &MockMFAUsecase{
+			VerifyFunc: func(ctx context.Context, request dto.MFAVerifyRequest) (*dto.LoginResponse, error) {
+				return nil, usecase.ErrInvalidMFAToken
+			},
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodPost, "/auth/mfa/verify", strings.NewReader(`{"mfa_token":"x","code":"123456"}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		NewMFAController(mockUsecase).Verify(c)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
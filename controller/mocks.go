@@ -1,51 +1,117 @@
 package controller
 
 import (
+	"context"
 	"go-api/dto"
 	"go-api/model"
+	"go-api/usecase"
+	"io"
+	"time"
 )
 
 // MockProductUsecase é um mock do ProductUsecase para testes do controller
 type MockProductUsecase struct {
-	GetProductsFunc    func() ([]model.Product, error)
-	CreateProductFunc  func(product model.Product) (model.Product, error)
-	GetProductByIdFunc func(id_product int) (*model.Product, error)
+	GetProductsFunc        func(filter model.ProductFilter, opts model.PriceOptions) ([]model.Product, error)
+	ExportProductsFunc     func(ctx context.Context, filter model.ProductFilter, opts model.PriceOptions, fn func(model.Product) error) error
+	CreateProductFunc      func(ctx context.Context, product model.Product) (model.Product, error)
+	GetProductByIdFunc     func(id_product int, opts model.PriceOptions) (*model.Product, error)
+	GetPriceHistoryFunc    func(id_product int) ([]model.ProductPrice, error)
+	SchedulePriceFunc      func(ctx context.Context, id_product int, change model.PriceChange) (model.ProductPrice, error)
+	DeleteProductFunc      func(ctx context.Context, id_product int) error
+	GetDeletedProductsFunc func() ([]model.Product, error)
+	RestoreProductFunc     func(ctx context.Context, id_product int) (*model.Product, error)
+	PurgeProductFunc       func(ctx context.Context, id_product int) error
 }
 
-func (m *MockProductUsecase) GetProducts() ([]model.Product, error) {
+func (m *MockProductUsecase) GetProducts(filter model.ProductFilter, opts model.PriceOptions) ([]model.Product, error) {
 	if m.GetProductsFunc != nil {
-		return m.GetProductsFunc()
+		return m.GetProductsFunc(filter, opts)
 	}
 	return nil, nil
 }
 
-func (m *MockProductUsecase) CreateProduct(product model.Product) (model.Product, error) {
+func (m *MockProductUsecase) ExportProducts(ctx context.Context, filter model.ProductFilter, opts model.PriceOptions, fn func(model.Product) error) error {
+	if m.ExportProductsFunc != nil {
+		return m.ExportProductsFunc(ctx, filter, opts, fn)
+	}
+	return nil
+}
+
+func (m *MockProductUsecase) CreateProduct(ctx context.Context, product model.Product) (model.Product, error) {
 	if m.CreateProductFunc != nil {
-		return m.CreateProductFunc(product)
+		return m.CreateProductFunc(ctx, product)
 	}
 	return model.Product{}, nil
 }
 
-func (m *MockProductUsecase) GetProductById(id_product int) (*model.Product, error) {
+func (m *MockProductUsecase) GetProductById(id_product int, opts model.PriceOptions) (*model.Product, error) {
 	if m.GetProductByIdFunc != nil {
-		return m.GetProductByIdFunc(id_product)
+		return m.GetProductByIdFunc(id_product, opts)
+	}
+	return nil, nil
+}
+
+func (m *MockProductUsecase) GetPriceHistory(id_product int) ([]model.ProductPrice, error) {
+	if m.GetPriceHistoryFunc != nil {
+		return m.GetPriceHistoryFunc(id_product)
+	}
+	return nil, nil
+}
+
+func (m *MockProductUsecase) SchedulePrice(ctx context.Context, id_product int, change model.PriceChange) (model.ProductPrice, error) {
+	if m.SchedulePriceFunc != nil {
+		return m.SchedulePriceFunc(ctx, id_product, change)
+	}
+	return model.ProductPrice{}, nil
+}
+
+func (m *MockProductUsecase) DeleteProduct(ctx context.Context, id_product int) error {
+	if m.DeleteProductFunc != nil {
+		return m.DeleteProductFunc(ctx, id_product)
+	}
+	return nil
+}
+
+func (m *MockProductUsecase) GetDeletedProducts() ([]model.Product, error) {
+	if m.GetDeletedProductsFunc != nil {
+		return m.GetDeletedProductsFunc()
 	}
 	return nil, nil
 }
 
+func (m *MockProductUsecase) RestoreProduct(ctx context.Context, id_product int) (*model.Product, error) {
+	if m.RestoreProductFunc != nil {
+		return m.RestoreProductFunc(ctx, id_product)
+	}
+	return nil, nil
+}
+
+func (m *MockProductUsecase) PurgeProduct(ctx context.Context, id_product int) error {
+	if m.PurgeProductFunc != nil {
+		return m.PurgeProductFunc(ctx, id_product)
+	}
+	return nil
+}
+
 // MockUserUsecase é um mock do UserUsecase para testes do controller
 type MockUserUsecase struct {
-	CreateUserFunc  func(user dto.CreateUserRequest) (*dto.UserResponse, error)
-	GetUserByIDFunc func(id int) (*dto.UserResponse, error)
-	UpdateUserFunc  func(id int, user dto.UpdateUserRequest) error
-	DeleteUserFunc  func(id int) error
-	GetUsersFunc    func() ([]dto.UserResponse, error)
-	LoginFunc       func(req dto.LoginRequest) (*dto.LoginResponse, error)
+	CreateUserFunc         func(ctx context.Context, user dto.CreateUserRequest) (*dto.UserResponse, error)
+	GetUserByIDFunc        func(id int) (*dto.UserResponse, error)
+	UpdateUserFunc         func(ctx context.Context, id int, user dto.UpdateUserRequest) error
+	DeleteUserFunc         func(ctx context.Context, id int) error
+	GetUsersFunc           func(filter model.UserFilter) ([]dto.UserResponse, error)
+	ExportUsersFunc        func(ctx context.Context, filter model.UserFilter, fn func(dto.UserResponse) error) error
+	GetDeletedUsersFunc    func() ([]dto.UserResponse, error)
+	RestoreUserFunc        func(ctx context.Context, id int) (*dto.UserResponse, error)
+	PurgeUserFunc          func(ctx context.Context, id int) error
+	LoginFunc              func(ctx context.Context, req dto.LoginRequest) (*dto.LoginResponse, error)
+	VerifyEmailFunc        func(ctx context.Context, token string) (*dto.UserResponse, error)
+	ResendVerificationFunc func(ctx context.Context, email string) error
 }
 
-func (m *MockUserUsecase) CreateUser(user dto.CreateUserRequest) (*dto.UserResponse, error) {
+func (m *MockUserUsecase) CreateUser(ctx context.Context, user dto.CreateUserRequest) (*dto.UserResponse, error) {
 	if m.CreateUserFunc != nil {
-		return m.CreateUserFunc(user)
+		return m.CreateUserFunc(ctx, user)
 	}
 	return nil, nil
 }
@@ -57,30 +123,860 @@ func (m *MockUserUsecase) GetUserByID(id int) (*dto.UserResponse, error) {
 	return nil, nil
 }
 
-func (m *MockUserUsecase) UpdateUser(id int, user dto.UpdateUserRequest) error {
+func (m *MockUserUsecase) UpdateUser(ctx context.Context, id int, user dto.UpdateUserRequest) error {
 	if m.UpdateUserFunc != nil {
-		return m.UpdateUserFunc(id, user)
+		return m.UpdateUserFunc(ctx, id, user)
 	}
 	return nil
 }
 
-func (m *MockUserUsecase) DeleteUser(id int) error {
+func (m *MockUserUsecase) DeleteUser(ctx context.Context, id int) error {
 	if m.DeleteUserFunc != nil {
-		return m.DeleteUserFunc(id)
+		return m.DeleteUserFunc(ctx, id)
 	}
 	return nil
 }
 
-func (m *MockUserUsecase) GetUsers() ([]dto.UserResponse, error) {
+func (m *MockUserUsecase) GetUsers(filter model.UserFilter) ([]dto.UserResponse, error) {
 	if m.GetUsersFunc != nil {
-		return m.GetUsersFunc()
+		return m.GetUsersFunc(filter)
+	}
+	return nil, nil
+}
+
+func (m *MockUserUsecase) ExportUsers(ctx context.Context, filter model.UserFilter, fn func(dto.UserResponse) error) error {
+	if m.ExportUsersFunc != nil {
+		return m.ExportUsersFunc(ctx, filter, fn)
+	}
+	return nil
+}
+
+func (m *MockUserUsecase) GetDeletedUsers() ([]dto.UserResponse, error) {
+	if m.GetDeletedUsersFunc != nil {
+		return m.GetDeletedUsersFunc()
+	}
+	return nil, nil
+}
+
+func (m *MockUserUsecase) RestoreUser(ctx context.Context, id int) (*dto.UserResponse, error) {
+	if m.RestoreUserFunc != nil {
+		return m.RestoreUserFunc(ctx, id)
 	}
 	return nil, nil
 }
 
-func (m *MockUserUsecase) Login(req dto.LoginRequest) (*dto.LoginResponse, error) {
+func (m *MockUserUsecase) PurgeUser(ctx context.Context, id int) error {
+	if m.PurgeUserFunc != nil {
+		return m.PurgeUserFunc(ctx, id)
+	}
+	return nil
+}
+
+func (m *MockUserUsecase) Login(ctx context.Context, req dto.LoginRequest) (*dto.LoginResponse, error) {
 	if m.LoginFunc != nil {
-		return m.LoginFunc(req)
+		return m.LoginFunc(ctx, req)
+	}
+	return nil, nil
+}
+
+func (m *MockUserUsecase) VerifyEmail(ctx context.Context, token string) (*dto.UserResponse, error) {
+	if m.VerifyEmailFunc != nil {
+		return m.VerifyEmailFunc(ctx, token)
+	}
+	return nil, nil
+}
+
+func (m *MockUserUsecase) ResendVerification(ctx context.Context, email string) error {
+	if m.ResendVerificationFunc != nil {
+		return m.ResendVerificationFunc(ctx, email)
+	}
+	return nil
+}
+
+// MockCategoryUsecase é um mock do CategoryUsecase para testes do controller
+type MockCategoryUsecase struct {
+	GetCategoryTreeFunc      func() ([]model.Category, error)
+	GetCategoryByIDFunc      func(id int) (*model.Category, error)
+	CreateCategoryFunc       func(ctx context.Context, category model.Category) (model.Category, error)
+	UpdateCategoryFunc       func(ctx context.Context, id int, changes model.Category) (*model.Category, error)
+	MoveCategoryFunc         func(ctx context.Context, id int, parentID *int) (*model.Category, error)
+	DeleteCategoryFunc       func(ctx context.Context, id int) error
+	SetProductCategoriesFunc func(ctx context.Context, productID int, categoryIDs []int) ([]model.Category, error)
+	GetProductCategoriesFunc func(productID int) ([]model.Category, error)
+}
+
+func (m *MockCategoryUsecase) GetCategoryTree() ([]model.Category, error) {
+	if m.GetCategoryTreeFunc != nil {
+		return m.GetCategoryTreeFunc()
+	}
+	return nil, nil
+}
+
+func (m *MockCategoryUsecase) GetCategoryByID(id int) (*model.Category, error) {
+	if m.GetCategoryByIDFunc != nil {
+		return m.GetCategoryByIDFunc(id)
+	}
+	return nil, nil
+}
+
+func (m *MockCategoryUsecase) CreateCategory(ctx context.Context, category model.Category) (model.Category, error) {
+	if m.CreateCategoryFunc != nil {
+		return m.CreateCategoryFunc(ctx, category)
+	}
+	return model.Category{}, nil
+}
+
+func (m *MockCategoryUsecase) UpdateCategory(ctx context.Context, id int, changes model.Category) (*model.Category, error) {
+	if m.UpdateCategoryFunc != nil {
+		return m.UpdateCategoryFunc(ctx, id, changes)
+	}
+	return nil, nil
+}
+
+func (m *MockCategoryUsecase) MoveCategory(ctx context.Context, id int, parentID *int) (*model.Category, error) {
+	if m.MoveCategoryFunc != nil {
+		return m.MoveCategoryFunc(ctx, id, parentID)
+	}
+	return nil, nil
+}
+
+func (m *MockCategoryUsecase) DeleteCategory(ctx context.Context, id int) error {
+	if m.DeleteCategoryFunc != nil {
+		return m.DeleteCategoryFunc(ctx, id)
+	}
+	return nil
+}
+
+func (m *MockCategoryUsecase) SetProductCategories(ctx context.Context, productID int, categoryIDs []int) ([]model.Category, error) {
+	if m.SetProductCategoriesFunc != nil {
+		return m.SetProductCategoriesFunc(ctx, productID, categoryIDs)
+	}
+	return nil, nil
+}
+
+func (m *MockCategoryUsecase) GetProductCategories(productID int) ([]model.Category, error) {
+	if m.GetProductCategoriesFunc != nil {
+		return m.GetProductCategoriesFunc(productID)
+	}
+	return nil, nil
+}
+
+// MockPricingUsecase é um mock do PricingUsecase para testes do controller
+type MockPricingUsecase struct {
+	GetPriceListsFunc       func() ([]model.PriceList, error)
+	CreatePriceListFunc     func(list model.PriceList) (model.PriceList, error)
+	GetPriceListItemsFunc   func(priceListID int) ([]model.PriceListItem, error)
+	SetPriceListItemFunc    func(priceListID, productID int, amount string) (model.PriceListItem, error)
+	DeletePriceListItemFunc func(priceListID, productID int) error
+	GetExchangeRatesFunc    func() ([]model.ExchangeRate, error)
+	SetExchangeRatesFunc    func(rates []model.ExchangeRate) ([]model.ExchangeRate, error)
+	ImportExchangeRatesFunc func(reader io.Reader, source string) (int, error)
+}
+
+func (m *MockPricingUsecase) GetPriceLists() ([]model.PriceList, error) {
+	if m.GetPriceListsFunc != nil {
+		return m.GetPriceListsFunc()
+	}
+	return nil, nil
+}
+
+func (m *MockPricingUsecase) CreatePriceList(list model.PriceList) (model.PriceList, error) {
+	if m.CreatePriceListFunc != nil {
+		return m.CreatePriceListFunc(list)
+	}
+	return model.PriceList{}, nil
+}
+
+func (m *MockPricingUsecase) GetPriceListItems(priceListID int) ([]model.PriceListItem, error) {
+	if m.GetPriceListItemsFunc != nil {
+		return m.GetPriceListItemsFunc(priceListID)
+	}
+	return nil, nil
+}
+
+func (m *MockPricingUsecase) SetPriceListItem(priceListID, productID int, amount string) (model.PriceListItem, error) {
+	if m.SetPriceListItemFunc != nil {
+		return m.SetPriceListItemFunc(priceListID, productID, amount)
+	}
+	return model.PriceListItem{}, nil
+}
+
+func (m *MockPricingUsecase) DeletePriceListItem(priceListID, productID int) error {
+	if m.DeletePriceListItemFunc != nil {
+		return m.DeletePriceListItemFunc(priceListID, productID)
+	}
+	return nil
+}
+
+func (m *MockPricingUsecase) GetExchangeRates() ([]model.ExchangeRate, error) {
+	if m.GetExchangeRatesFunc != nil {
+		return m.GetExchangeRatesFunc()
+	}
+	return nil, nil
+}
+
+func (m *MockPricingUsecase) SetExchangeRates(rates []model.ExchangeRate) ([]model.ExchangeRate, error) {
+	if m.SetExchangeRatesFunc != nil {
+		return m.SetExchangeRatesFunc(rates)
+	}
+	return rates, nil
+}
+
+func (m *MockPricingUsecase) ImportExchangeRates(reader io.Reader, source string) (int, error) {
+	if m.ImportExchangeRatesFunc != nil {
+		return m.ImportExchangeRatesFunc(reader, source)
+	}
+	return 0, nil
+}
+
+// MockVariantUsecase é um mock do VariantUsecase para testes do controller
+type MockVariantUsecase struct {
+	GetOptionTypesFunc   func() ([]model.OptionType, error)
+	CreateOptionTypeFunc func(name string, values []string) (*model.OptionType, error)
+	AddOptionValueFunc   func(optionTypeID int, value string) (model.OptionValue, error)
+	GetVariantsFunc      func(productID int) ([]model.Variant, error)
+	CreateVariantFunc    func(productID int, input model.VariantInput) (*model.Variant, error)
+	UpdateVariantFunc    func(productID, variantID int, input model.VariantInput) (*model.Variant, error)
+	DeleteVariantFunc    func(productID, variantID int) error
+}
+
+func (m *MockVariantUsecase) GetOptionTypes() ([]model.OptionType, error) {
+	if m.GetOptionTypesFunc != nil {
+		return m.GetOptionTypesFunc()
+	}
+	return nil, nil
+}
+
+func (m *MockVariantUsecase) CreateOptionType(name string, values []string) (*model.OptionType, error) {
+	if m.CreateOptionTypeFunc != nil {
+		return m.CreateOptionTypeFunc(name, values)
+	}
+	return &model.OptionType{}, nil
+}
+
+func (m *MockVariantUsecase) AddOptionValue(optionTypeID int, value string) (model.OptionValue, error) {
+	if m.AddOptionValueFunc != nil {
+		return m.AddOptionValueFunc(optionTypeID, value)
+	}
+	return model.OptionValue{}, nil
+}
+
+func (m *MockVariantUsecase) GetVariants(productID int) ([]model.Variant, error) {
+	if m.GetVariantsFunc != nil {
+		return m.GetVariantsFunc(productID)
+	}
+	return nil, nil
+}
+
+func (m *MockVariantUsecase) CreateVariant(productID int, input model.VariantInput) (*model.Variant, error) {
+	if m.CreateVariantFunc != nil {
+		return m.CreateVariantFunc(productID, input)
+	}
+	return &model.Variant{}, nil
+}
+
+func (m *MockVariantUsecase) UpdateVariant(productID, variantID int, input model.VariantInput) (*model.Variant, error) {
+	if m.UpdateVariantFunc != nil {
+		return m.UpdateVariantFunc(productID, variantID, input)
+	}
+	return &model.Variant{}, nil
+}
+
+func (m *MockVariantUsecase) DeleteVariant(productID, variantID int) error {
+	if m.DeleteVariantFunc != nil {
+		return m.DeleteVariantFunc(productID, variantID)
+	}
+	return nil
+}
+
+// MockImageUsecase é um mock do ImageUsecase para testes do controller
+type MockImageUsecase struct {
+	GetImagesFunc       func(productID int) ([]model.ProductImage, error)
+	UploadImageFunc     func(productID int, data []byte) (*model.ProductImage, error)
+	SetPrimaryImageFunc func(productID, imageID int) ([]model.ProductImage, error)
+	ReorderImagesFunc   func(productID int, imageIDs []int) ([]model.ProductImage, error)
+	DeleteImageFunc     func(productID, imageID int) error
+}
+
+func (m *MockImageUsecase) GetImages(productID int) ([]model.ProductImage, error) {
+	if m.GetImagesFunc != nil {
+		return m.GetImagesFunc(productID)
+	}
+	return nil, nil
+}
+
+func (m *MockImageUsecase) UploadImage(productID int, data []byte) (*model.ProductImage, error) {
+	if m.UploadImageFunc != nil {
+		return m.UploadImageFunc(productID, data)
+	}
+	return &model.ProductImage{ProductID: productID}, nil
+}
+
+func (m *MockImageUsecase) SetPrimaryImage(productID, imageID int) ([]model.ProductImage, error) {
+	if m.SetPrimaryImageFunc != nil {
+		return m.SetPrimaryImageFunc(productID, imageID)
+	}
+	return nil, nil
+}
+
+func (m *MockImageUsecase) ReorderImages(productID int, imageIDs []int) ([]model.ProductImage, error) {
+	if m.ReorderImagesFunc != nil {
+		return m.ReorderImagesFunc(productID, imageIDs)
+	}
+	return nil, nil
+}
+
+func (m *MockImageUsecase) DeleteImage(productID, imageID int) error {
+	if m.DeleteImageFunc != nil {
+		return m.DeleteImageFunc(productID, imageID)
+	}
+	return nil
+}
+
+// MockImportUsecase é um mock do ImportUsecase para testes do controller
+type MockImportUsecase struct {
+	StartProductImportFunc func(ctx context.Context, body io.Reader, opts usecase.ImportOptions) (model.ImportJob, error)
+	GetImportJobFunc       func(id string) (model.ImportJob, error)
+}
+
+func (m *MockImportUsecase) StartProductImport(ctx context.Context, body io.Reader, opts usecase.ImportOptions) (model.ImportJob, error) {
+	if m.StartProductImportFunc != nil {
+		return m.StartProductImportFunc(ctx, body, opts)
+	}
+	return model.ImportJob{}, nil
+}
+
+func (m *MockImportUsecase) GetImportJob(id string) (model.ImportJob, error) {
+	if m.GetImportJobFunc != nil {
+		return m.GetImportJobFunc(id)
+	}
+	return model.ImportJob{}, nil
+}
+
+// MockAuditUsecase é um mock do AuditUsecase para testes do controller
+type MockAuditUsecase struct {
+	GetAuditEventsFunc func(filter model.AuditFilter) ([]model.AuditEvent, error)
+	VerifyChainFunc    func(ctx context.Context) (model.AuditVerification, error)
+}
+
+func (m *MockAuditUsecase) GetAuditEvents(filter model.AuditFilter) ([]model.AuditEvent, error) {
+	if m.GetAuditEventsFunc != nil {
+		return m.GetAuditEventsFunc(filter)
+	}
+	return nil, nil
+}
+
+func (m *MockAuditUsecase) VerifyChain(ctx context.Context) (model.AuditVerification, error) {
+	if m.VerifyChainFunc != nil {
+		return m.VerifyChainFunc(ctx)
+	}
+	return model.AuditVerification{}, nil
+}
+
+// MockCartUsecase é um mock do CartUsecase para testes do controller
+type MockCartUsecase struct {
+	GetCartFunc            func(owner model.CartOwner) (*model.Cart, error)
+	AddItemFunc            func(owner model.CartOwner, input model.CartItemInput) (*model.Cart, error)
+	UpdateItemQuantityFunc func(owner model.CartOwner, itemID, quantity int) (*model.Cart, error)
+	RemoveItemFunc         func(owner model.CartOwner, itemID int) (*model.Cart, error)
+	MergeCartFunc          func(userID int, token string) error
+	PriceCartFunc          func(owner model.CartOwner, codes []string, destination *model.TaxDestination) (*model.PricingResult, error)
+}
+
+func (m *MockCartUsecase) GetCart(owner model.CartOwner) (*model.Cart, error) {
+	if m.GetCartFunc != nil {
+		return m.GetCartFunc(owner)
+	}
+	return &model.Cart{}, nil
+}
+
+func (m *MockCartUsecase) AddItem(owner model.CartOwner, input model.CartItemInput) (*model.Cart, error) {
+	if m.AddItemFunc != nil {
+		return m.AddItemFunc(owner, input)
+	}
+	return &model.Cart{}, nil
+}
+
+func (m *MockCartUsecase) UpdateItemQuantity(owner model.CartOwner, itemID, quantity int) (*model.Cart, error) {
+	if m.UpdateItemQuantityFunc != nil {
+		return m.UpdateItemQuantityFunc(owner, itemID, quantity)
+	}
+	return &model.Cart{}, nil
+}
+
+func (m *MockCartUsecase) RemoveItem(owner model.CartOwner, itemID int) (*model.Cart, error) {
+	if m.RemoveItemFunc != nil {
+		return m.RemoveItemFunc(owner, itemID)
+	}
+	return &model.Cart{}, nil
+}
+
+func (m *MockCartUsecase) MergeCart(userID int, token string) error {
+	if m.MergeCartFunc != nil {
+		return m.MergeCartFunc(userID, token)
+	}
+	return nil
+}
+
+func (m *MockCartUsecase) PriceCart(owner model.CartOwner, codes []string, destination *model.TaxDestination) (*model.PricingResult, error) {
+	if m.PriceCartFunc != nil {
+		return m.PriceCartFunc(owner, codes, destination)
+	}
+	return &model.PricingResult{}, nil
+}
+
+// MockOrderUsecase é um mock do OrderUsecase para testes do controller
+type MockOrderUsecase struct {
+	CheckoutFunc            func(ctx context.Context, userID int, items []model.CartItemInput, codes []string, destination *model.TaxDestination) (*model.Order, error)
+	GetUserOrdersFunc       func(userID int) ([]model.Order, error)
+	GetUserOrderFunc        func(userID, orderID int) (*model.Order, error)
+	CancelUserOrderFunc     func(ctx context.Context, userID, orderID int) (*model.Order, error)
+	GetOrdersFunc           func(filter model.OrderFilter) ([]model.Order, error)
+	GetOrderFunc            func(orderID int) (*model.Order, error)
+	TransitionOrderFunc     func(ctx context.Context, orderID int, status, note string) (*model.Order, error)
+	RecordPaymentStatusFunc func(ctx context.Context, orderID int, status, note string) (*model.Order, error)
+}
+
+func (m *MockOrderUsecase) Checkout(ctx context.Context, userID int, items []model.CartItemInput, codes []string, destination *model.TaxDestination) (*model.Order, error) {
+	if m.CheckoutFunc != nil {
+		return m.CheckoutFunc(ctx, userID, items, codes, destination)
+	}
+	return &model.Order{}, nil
+}
+
+func (m *MockOrderUsecase) GetUserOrders(userID int) ([]model.Order, error) {
+	if m.GetUserOrdersFunc != nil {
+		return m.GetUserOrdersFunc(userID)
+	}
+	return []model.Order{}, nil
+}
+
+func (m *MockOrderUsecase) GetUserOrder(userID, orderID int) (*model.Order, error) {
+	if m.GetUserOrderFunc != nil {
+		return m.GetUserOrderFunc(userID, orderID)
+	}
+	return &model.Order{}, nil
+}
+
+func (m *MockOrderUsecase) CancelUserOrder(ctx context.Context, userID, orderID int) (*model.Order, error) {
+	if m.CancelUserOrderFunc != nil {
+		return m.CancelUserOrderFunc(ctx, userID, orderID)
+	}
+	return &model.Order{}, nil
+}
+
+func (m *MockOrderUsecase) GetOrders(filter model.OrderFilter) ([]model.Order, error) {
+	if m.GetOrdersFunc != nil {
+		return m.GetOrdersFunc(filter)
+	}
+	return []model.Order{}, nil
+}
+
+func (m *MockOrderUsecase) GetOrder(orderID int) (*model.Order, error) {
+	if m.GetOrderFunc != nil {
+		return m.GetOrderFunc(orderID)
+	}
+	return &model.Order{}, nil
+}
+
+func (m *MockOrderUsecase) TransitionOrder(ctx context.Context, orderID int, status, note string) (*model.Order, error) {
+	if m.TransitionOrderFunc != nil {
+		return m.TransitionOrderFunc(ctx, orderID, status, note)
+	}
+	return &model.Order{}, nil
+}
+
+func (m *MockOrderUsecase) RecordPaymentStatus(ctx context.Context, orderID int, status, note string) (*model.Order, error) {
+	if m.RecordPaymentStatusFunc != nil {
+		return m.RecordPaymentStatusFunc(ctx, orderID, status, note)
+	}
+	return &model.Order{}, nil
+}
+
+// MockPaymentUsecase é um mock do PaymentUsecase para testes do controller
+type MockPaymentUsecase struct {
+	PayOrderFunc         func(ctx context.Context, userID, orderID int) (*model.Payment, error)
+	GetOrderPaymentsFunc func(orderID int) ([]model.Payment, error)
+	RefundOrderFunc      func(ctx context.Context, orderID int) (*model.Order, error)
+	HandleWebhookFunc    func(ctx context.Context, payload []byte, signature string) (bool, error)
+}
+
+func (m *MockPaymentUsecase) PayOrder(ctx context.Context, userID, orderID int) (*model.Payment, error) {
+	if m.PayOrderFunc != nil {
+		return m.PayOrderFunc(ctx, userID, orderID)
+	}
+	return &model.Payment{}, nil
+}
+
+func (m *MockPaymentUsecase) GetOrderPayments(orderID int) ([]model.Payment, error) {
+	if m.GetOrderPaymentsFunc != nil {
+		return m.GetOrderPaymentsFunc(orderID)
+	}
+	return []model.Payment{}, nil
+}
+
+func (m *MockPaymentUsecase) RefundOrder(ctx context.Context, orderID int) (*model.Order, error) {
+	if m.RefundOrderFunc != nil {
+		return m.RefundOrderFunc(ctx, orderID)
+	}
+	return &model.Order{}, nil
+}
+
+func (m *MockPaymentUsecase) HandleWebhook(ctx context.Context, payload []byte, signature string) (bool, error) {
+	if m.HandleWebhookFunc != nil {
+		return m.HandleWebhookFunc(ctx, payload, signature)
+	}
+	return true, nil
+}
+
+// MockPromotionUsecase é um mock do PromotionUsecase para testes do controller
+type MockPromotionUsecase struct {
+	CreatePromotionFunc    func(input model.PromotionInput) (*model.Promotion, error)
+	GetPromotionsFunc      func() ([]model.Promotion, error)
+	GetPromotionFunc       func(id int) (*model.Promotion, error)
+	SetPromotionActiveFunc func(id int, active bool) (*model.Promotion, error)
+	PriceLinesFunc         func(userID *int, lines []model.PricingLine, codes []string) (*model.PricingResult, error)
+}
+
+func (m *MockPromotionUsecase) CreatePromotion(input model.PromotionInput) (*model.Promotion, error) {
+	if m.CreatePromotionFunc != nil {
+		return m.CreatePromotionFunc(input)
+	}
+	return &model.Promotion{}, nil
+}
+
+func (m *MockPromotionUsecase) GetPromotions() ([]model.Promotion, error) {
+	if m.GetPromotionsFunc != nil {
+		return m.GetPromotionsFunc()
+	}
+	return []model.Promotion{}, nil
+}
+
+func (m *MockPromotionUsecase) GetPromotion(id int) (*model.Promotion, error) {
+	if m.GetPromotionFunc != nil {
+		return m.GetPromotionFunc(id)
+	}
+	return &model.Promotion{}, nil
+}
+
+func (m *MockPromotionUsecase) SetPromotionActive(id int, active bool) (*model.Promotion, error) {
+	if m.SetPromotionActiveFunc != nil {
+		return m.SetPromotionActiveFunc(id, active)
+	}
+	return &model.Promotion{}, nil
+}
+
+func (m *MockPromotionUsecase) PriceLines(userID *int, lines []model.PricingLine, codes []string) (*model.PricingResult, error) {
+	if m.PriceLinesFunc != nil {
+		return m.PriceLinesFunc(userID, lines, codes)
+	}
+	return &model.PricingResult{}, nil
+}
+
+// MockTaxUsecase é um mock do TaxUsecase para testes do controller
+type MockTaxUsecase struct {
+	GetTaxCategoriesFunc      func() []string
+	GetTaxRegionFunc          func(destination model.TaxDestination, at time.Time) (*model.TaxRegion, error)
+	SetProductTaxCategoryFunc func(ctx context.Context, productID int, category string) (*model.Product, error)
+	TaxLinesFunc              func(destination model.TaxDestination, lines []model.PricedLine) (*model.TaxBreakdown, error)
+}
+
+func (m *MockTaxUsecase) GetTaxCategories() []string {
+	if m.GetTaxCategoriesFunc != nil {
+		return m.GetTaxCategoriesFunc()
+	}
+	return []string{}
+}
+
+func (m *MockTaxUsecase) GetTaxRegion(destination model.TaxDestination, at time.Time) (*model.TaxRegion, error) {
+	if m.GetTaxRegionFunc != nil {
+		return m.GetTaxRegionFunc(destination, at)
+	}
+	return &model.TaxRegion{}, nil
+}
+
+func (m *MockTaxUsecase) SetProductTaxCategory(ctx context.Context, productID int, category string) (*model.Product, error) {
+	if m.SetProductTaxCategoryFunc != nil {
+		return m.SetProductTaxCategoryFunc(ctx, productID, category)
+	}
+	return &model.Product{}, nil
+}
+
+func (m *MockTaxUsecase) TaxLines(destination model.TaxDestination, lines []model.PricedLine) (*model.TaxBreakdown, error) {
+	if m.TaxLinesFunc != nil {
+		return m.TaxLinesFunc(destination, lines)
+	}
+	return &model.TaxBreakdown{}, nil
+}
+
+// MockPasswordResetUsecase é um mock do PasswordResetUsecase para testes do controller
+type MockPasswordResetUsecase struct {
+	ForgotPasswordFunc func(ctx context.Context, email string) error
+	ResetPasswordFunc  func(ctx context.Context, token, password string) error
+}
+
+func (m *MockPasswordResetUsecase) ForgotPassword(ctx context.Context, email string) error {
+	if m.ForgotPasswordFunc != nil {
+		return m.ForgotPasswordFunc(ctx, email)
+	}
+	return nil
+}
+
+func (m *MockPasswordResetUsecase) ResetPassword(ctx context.Context, token, password string) error {
+	if m.ResetPasswordFunc != nil {
+		return m.ResetPasswordFunc(ctx, token, password)
+	}
+	return nil
+}
+
+// MockLoginThrottleUsecase é um mock do LoginThrottleUsecase para testes do controller
+type MockLoginThrottleUsecase struct {
+	CheckFunc   func(ctx context.Context, email string) error
+	FailFunc    func(ctx context.Context, email string) error
+	SucceedFunc func(ctx context.Context, email string) error
+	UnlockFunc  func(ctx context.Context, email, ip string) error
+}
+
+func (m *MockLoginThrottleUsecase) Check(ctx context.Context, email string) error {
+	if m.CheckFunc != nil {
+		return m.CheckFunc(ctx, email)
+	}
+	return nil
+}
+
+func (m *MockLoginThrottleUsecase) Fail(ctx context.Context, email string) error {
+	if m.FailFunc != nil {
+		return m.FailFunc(ctx, email)
+	}
+	return nil
+}
+
+func (m *MockLoginThrottleUsecase) Succeed(ctx context.Context, email string) error {
+	if m.SucceedFunc != nil {
+		return m.SucceedFunc(ctx, email)
+	}
+	return nil
+}
+
+func (m *MockLoginThrottleUsecase) Unlock(ctx context.Context, email, ip string) error {
+	if m.UnlockFunc != nil {
+		return m.UnlockFunc(ctx, email, ip)
+	}
+	return nil
+}
+
+// MockMFAUsecase é um mock do MFAUsecase para testes do controller
+type MockMFAUsecase struct {
+	EnrollFunc                  func(ctx context.Context, userID int) (*dto.MFAEnrollResponse, error)
+	ConfirmFunc                 func(ctx context.Context, userID int, code string) (*dto.MFARecoveryCodesResponse, error)
+	RegenerateRecoveryCodesFunc func(ctx context.Context, userID int, code string) (*dto.MFARecoveryCodesResponse, error)
+	DisableFunc                 func(ctx context.Context, userID int, code string) error
+	VerifyFunc                  func(ctx context.Context, request dto.MFAVerifyRequest) (*dto.LoginResponse, error)
+}
+
+func (m *MockMFAUsecase) Enroll(ctx context.Context, userID int) (*dto.MFAEnrollResponse, error) {
+	if m.EnrollFunc != nil {
+		return m.EnrollFunc(ctx, userID)
 	}
 	return nil, nil
 }
+
+func (m *MockMFAUsecase) Confirm(ctx context.Context, userID int, code string) (*dto.MFARecoveryCodesResponse, error) {
+	if m.ConfirmFunc != nil {
+		return m.ConfirmFunc(ctx, userID, code)
+	}
+	return nil, nil
+}
+
+func (m *MockMFAUsecase) RegenerateRecoveryCodes(ctx context.Context, userID int, code string) (*dto.MFARecoveryCodesResponse, error) {
+	if m.RegenerateRecoveryCodesFunc != nil {
+		return m.RegenerateRecoveryCodesFunc(ctx, userID, code)
+	}
+	return nil, nil
+}
+
+func (m *MockMFAUsecase) Disable(ctx context.Context, userID int, code string) error {
+	if m.DisableFunc != nil {
+		return m.DisableFunc(ctx, userID, code)
+	}
+	return nil
+}
+
+func (m *MockMFAUsecase) Verify(ctx context.Context, request dto.MFAVerifyRequest) (*dto.LoginResponse, error) {
+	if m.VerifyFunc != nil {
+		return m.VerifyFunc(ctx, request)
+	}
+	return nil, nil
+}
+
+// MockAPIKeyUsecase é um mock do APIKeyUsecase para testes do controller
+type MockAPIKeyUsecase struct {
+	CreateAPIKeyFunc       func(ctx context.Context, request dto.CreateAPIKeyRequest) (*dto.APIKeySecretResponse, error)
+	GetAPIKeysFunc         func(userID int) ([]dto.APIKeyResponse, error)
+	RotateAPIKeyFunc       func(ctx context.Context, id int) (*dto.APIKeySecretResponse, error)
+	RevokeAPIKeyFunc       func(ctx context.Context, id int) error
+	AuthenticateAPIKeyFunc func(ctx context.Context, key, ip string) (*model.APIKeyPrincipal, error)
+}
+
+func (m *MockAPIKeyUsecase) CreateAPIKey(ctx context.Context, request dto.CreateAPIKeyRequest) (*dto.APIKeySecretResponse, error) {
+	if m.CreateAPIKeyFunc != nil {
+		return m.CreateAPIKeyFunc(ctx, request)
+	}
+	return nil, nil
+}
+
+func (m *MockAPIKeyUsecase) GetAPIKeys(userID int) ([]dto.APIKeyResponse, error) {
+	if m.GetAPIKeysFunc != nil {
+		return m.GetAPIKeysFunc(userID)
+	}
+	return nil, nil
+}
+
+func (m *MockAPIKeyUsecase) RotateAPIKey(ctx context.Context, id int) (*dto.APIKeySecretResponse, error) {
+	if m.RotateAPIKeyFunc != nil {
+		return m.RotateAPIKeyFunc(ctx, id)
+	}
+	return nil, nil
+}
+
+func (m *MockAPIKeyUsecase) RevokeAPIKey(ctx context.Context, id int) error {
+	if m.RevokeAPIKeyFunc != nil {
+		return m.RevokeAPIKeyFunc(ctx, id)
+	}
+	return nil
+}
+
+func (m *MockAPIKeyUsecase) AuthenticateAPIKey(ctx context.Context, key, ip string) (*model.APIKeyPrincipal, error) {
+	if m.AuthenticateAPIKeyFunc != nil {
+		return m.AuthenticateAPIKeyFunc(ctx, key, ip)
+	}
+	return nil, nil
+}
+
+// MockOIDCUsecase é um mock do OIDCUsecase para testes do controller
+type MockOIDCUsecase struct {
+	AuthorizeFunc     func(ctx context.Context, provider string, linkUserID int) (*dto.OIDCAuthorizeResponse, error)
+	CallbackFunc      func(ctx context.Context, provider string, userID int, request dto.OIDCCallbackRequest) (*dto.LoginResponse, error)
+	GetIdentitiesFunc func(userID int) ([]dto.UserIdentityResponse, error)
+	UnlinkFunc        func(ctx context.Context, userID int, provider string) error
+}
+
+func (m *MockOIDCUsecase) Authorize(ctx context.Context, provider string, linkUserID int) (*dto.OIDCAuthorizeResponse, error) {
+	if m.AuthorizeFunc != nil {
+		return m.AuthorizeFunc(ctx, provider, linkUserID)
+	}
+	return nil, nil
+}
+
+func (m *MockOIDCUsecase) Callback(ctx context.Context, provider string, userID int, request dto.OIDCCallbackRequest) (*dto.LoginResponse, error) {
+	if m.CallbackFunc != nil {
+		return m.CallbackFunc(ctx, provider, userID, request)
+	}
+	return nil, nil
+}
+
+func (m *MockOIDCUsecase) GetIdentities(userID int) ([]dto.UserIdentityResponse, error) {
+	if m.GetIdentitiesFunc != nil {
+		return m.GetIdentitiesFunc(userID)
+	}
+	return nil, nil
+}
+
+func (m *MockOIDCUsecase) Unlink(ctx context.Context, userID int, provider string) error {
+	if m.UnlinkFunc != nil {
+		return m.UnlinkFunc(ctx, userID, provider)
+	}
+	return nil
+}
+
+// MockOAuthUsecase é um mock do OAuthUsecase para testes do controller
+type MockOAuthUsecase struct {
+	CreateClientFunc            func(ctx context.Context, request dto.CreateOAuthClientRequest) (*dto.OAuthClientSecretResponse, error)
+	GetClientsFunc              func() ([]dto.OAuthClientResponse, error)
+	RevokeClientFunc            func(ctx context.Context, clientID string) error
+	GetAuthorizationFunc        func(userID int, request dto.OAuthAuthorizeRequest) (*dto.OAuthAuthorizationResponse, error)
+	AuthorizeFunc               func(ctx context.Context, userID int, mfa bool, request dto.OAuthApproveRequest) (*dto.OAuthAuthorizeResponse, error)
+	TokenFunc                   func(ctx context.Context, request dto.OAuthTokenRequest) (*dto.OAuthTokenResponse, error)
+	IntrospectFunc              func(ctx context.Context, request dto.OAuthTokenActionRequest) (*dto.OAuthIntrospectionResponse, error)
+	RevokeFunc                  func(ctx context.Context, request dto.OAuthTokenActionRequest) error
+	AuthenticateAccessTokenFunc func(ctx context.Context, token string) (*model.OAuthPrincipal, error)
+	GetConsentsFunc             func(userID int) ([]dto.OAuthConsentResponse, error)
+	RevokeConsentFunc           func(ctx context.Context, userID int, clientID string) error
+}
+
+func (m *MockOAuthUsecase) CreateClient(ctx context.Context, request dto.CreateOAuthClientRequest) (*dto.OAuthClientSecretResponse, error) {
+	if m.CreateClientFunc != nil {
+		return m.CreateClientFunc(ctx, request)
+	}
+	return nil, nil
+}
+
+func (m *MockOAuthUsecase) GetClients() ([]dto.OAuthClientResponse, error) {
+	if m.GetClientsFunc != nil {
+		return m.GetClientsFunc()
+	}
+	return nil, nil
+}
+
+func (m *MockOAuthUsecase) RevokeClient(ctx context.Context, clientID string) error {
+	if m.RevokeClientFunc != nil {
+		return m.RevokeClientFunc(ctx, clientID)
+	}
+	return nil
+}
+
+func (m *MockOAuthUsecase) GetAuthorization(userID int, request dto.OAuthAuthorizeRequest) (*dto.OAuthAuthorizationResponse, error) {
+	if m.GetAuthorizationFunc != nil {
+		return m.GetAuthorizationFunc(userID, request)
+	}
+	return nil, nil
+}
+
+func (m *MockOAuthUsecase) Authorize(ctx context.Context, userID int, mfa bool, request dto.OAuthApproveRequest) (*dto.OAuthAuthorizeResponse, error) {
+	if m.AuthorizeFunc != nil {
+		return m.AuthorizeFunc(ctx, userID, mfa, request)
+	}
+	return nil, nil
+}
+
+func (m *MockOAuthUsecase) Token(ctx context.Context, request dto.OAuthTokenRequest) (*dto.OAuthTokenResponse, error) {
+	if m.TokenFunc != nil {
+		return m.TokenFunc(ctx, request)
+	}
+	return nil, nil
+}
+
+func (m *MockOAuthUsecase) Introspect(ctx context.Context, request dto.OAuthTokenActionRequest) (*dto.OAuthIntrospectionResponse, error) {
+	if m.IntrospectFunc != nil {
+		return m.IntrospectFunc(ctx, request)
+	}
+	return nil, nil
+}
+
+func (m *MockOAuthUsecase) Revoke(ctx context.Context, request dto.OAuthTokenActionRequest) error {
+	if m.RevokeFunc != nil {
+		return m.RevokeFunc(ctx, request)
+	}
+	return nil
+}
+
+func (m *MockOAuthUsecase) AuthenticateAccessToken(ctx context.Context, token string) (*model.OAuthPrincipal, error) {
+	if m.AuthenticateAccessTokenFunc != nil {
+		return m.AuthenticateAccessTokenFunc(ctx, token)
+	}
+	return nil, nil
+}
+
+func (m *MockOAuthUsecase) GetConsents(userID int) ([]dto.OAuthConsentResponse, error) {
+	if m.GetConsentsFunc != nil {
+		return m.GetConsentsFunc(userID)
+	}
+	return nil, nil
+}
+
+func (m *MockOAuthUsecase) RevokeConsent(ctx context.Context, userID int, clientID string) error {
+	if m.RevokeConsentFunc != nil {
+		return m.RevokeConsentFunc(ctx, userID, clientID)
+	}
+	return nil
+}
@@ -0,0 +1,377 @@
+package controller
+
+import (
+	"errors"
+	"go-api/dto"
+	"go-api/middleware"
+	"go-api/usecase"
+	"net/http"
+	"net/url"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OAuthController handles HTTP requests for the OAuth2 authorization server
+type OAuthController struct {
+	oauthUsecase usecase.OAuthUsecase
+}
+
+// NewOAuthController creates a new OAuthController
+func NewOAuthController(usecase usecase.OAuthUsecase) *OAuthController {
+	return &OAuthController{
+		oauthUsecase: usecase,
+	}
+}
+
+// CreateClient godoc
+// @Summary Register an OAuth client
+// @Description Register a third-party application. Confidential clients get a secret, returned only once; public clients, such as mobile apps, get none and can only use authorization_code with PKCE. client_credentials acts as the admin or service account of user_id, limited to the scopes of the client
+// @Tags oauth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param client body dto.CreateOAuthClientRequest true "Client to register"
+// @Success 201 {object} dto.OAuthClientSecretResponse "Client registered"
+// @Failure 400 {object} model.Response "Bad request - Invalid grant type, scope or redirect URI, or client_credentials without a confidential client acting as an admin or service account"
+// @Failure 401 {object} model.Response "Missing or invalid token"
+// @Failure 403 {object} model.Response "Admin role required"
+// @Failure 404 {object} model.Response "User not found"
+// @Failure 500 {object} model.Response "Internal server error"
+// @Router /oauth/clients [post]
+func (oc *OAuthController) CreateClient(ctx *gin.Context) {
+	var req dto.CreateOAuthClientRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	response, err := oc.oauthUsecase.CreateClient(ctx.Request.Context(), req)
+	if err != nil {
+		ctx.JSON(oauthErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, response)
+}
+
+// GetClients godoc
+// @Summary List OAuth clients
+// @Description List the registered clients, revoked ones included; never their secrets
+// @Tags oauth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} dto.OAuthClientResponse "Clients"
+// @Failure 401 {object} model.Response "Missing or invalid token"
+// @Failure 403 {object} model.Response "Admin role required"
+// @Failure 500 {object} model.Response "Internal server error"
+// @Router /oauth/clients [get]
+func (oc *OAuthController) GetClients(ctx *gin.Context) {
+	clients, err := oc.oauthUsecase.GetClients()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, clients)
+}
+
+// RevokeClient godoc
+// @Summary Revoke an OAuth client
+// @Description Turn a client off for good: its access tokens stop working and it cannot get new ones
+// @Tags oauth
+// @Produce json
+// @Security BearerAuth
+// @Param clientId path string true "Client ID"
+// @Success 204 "Client revoked"
+// @Failure 401 {object} model.Response "Missing or invalid token"
+// @Failure 403 {object} model.Response "Admin role required"
+// @Failure 404 {object} model.Response "Client not found"
+// @Failure 409 {object} model.Response "Client already revoked"
+// @Failure 500 {object} model.Response "Internal server error"
+// @Router /oauth/clients/{clientId} [delete]
+func (oc *OAuthController) RevokeClient(ctx *gin.Context) {
+	if err := oc.oauthUsecase.RevokeClient(ctx.Request.Context(), ctx.Param("clientId")); err != nil {
+		ctx.JSON(oauthErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// GetAuthorization godoc
+// @Summary Check an authorization request
+// @Description Validate the query of an authorization request (RFC 6749, 4.1.1) for the consent page: the client, its registered redirect URI and a S256 code_challenge are required. Tells the application and scopes asked for, and whether the logged in user already granted them. Errors are never sent to the redirect URI; show them to the user
+// @Tags oauth
+// @Produce json
+// @Param response_type query string true "Must be code"
+// @Param client_id query string true "Client ID"
+// @Param redirect_uri query string true "Redirect URI registered for the client"
+// @Param scope query string false "Scopes asked for, separated by spaces; all the scopes of the client when empty"
+// @Param state query string false "Value sent back to the redirect URI"
+// @Param code_challenge query string true "PKCE code challenge"
+// @Param code_challenge_method query string true "Must be S256"
+// @Success 200 {object} dto.OAuthAuthorizationResponse "Valid request"
+// @Failure 400 {object} model.Response "Invalid request, with an OAuth error code"
+// @Failure 401 {object} model.Response "Unknown or revoked client, or invalid token"
+// @Failure 500 {object} model.Response "Internal server error"
+// @Router /oauth/authorize [get]
+func (oc *OAuthController) GetAuthorization(ctx *gin.Context) {
+	var req dto.OAuthAuthorizeRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	response, err := oc.oauthUsecase.GetAuthorization(ctx.GetInt(middleware.ContextUserID), req)
+	if err != nil {
+		ctx.JSON(oauthErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// Authorize godoc
+// @Summary Answer an authorization request
+// @Description Approve or refuse the request checked by GET /oauth/authorize, then send the user to the redirect_uri returned, carrying a code valid for a minute or error=access_denied, with the state. A logged in user approves with its token; others sign in with email and password, as at /login. A user with two-factor authentication then gets an MFA challenge, to exchange at /auth/mfa/verify before approving again with the token. Approving records the consent of the user to the scopes
+// @Tags oauth
+// @Accept json
+// @Produce json
+// @Param request body dto.OAuthApproveRequest true "Authorization request and answer"
+// @Success 200 {object} dto.OAuthAuthorizeResponse "Redirect URI, or MFA challenge"
+// @Failure 400 {object} model.Response "Invalid request, with an OAuth error code"
+// @Failure 401 {object} model.Response "Unknown or revoked client, invalid token or credentials, or no login"
+// @Failure 429 {object} model.Response "Too many failed logins; the Retry-After header tells when to try again"
+// @Failure 500 {object} model.Response "Internal server error"
+// @Router /oauth/authorize [post]
+func (oc *OAuthController) Authorize(ctx *gin.Context) {
+	var req dto.OAuthApproveRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	response, err := oc.oauthUsecase.Authorize(ctx.Request.Context(), ctx.GetInt(middleware.ContextUserID), ctx.GetBool(middleware.ContextMFA), req)
+	if err != nil {
+		var throttled *usecase.LoginThrottledError
+		if errors.As(err, &throttled) {
+			setRetryAfter(ctx, throttled.RetryAfter)
+		}
+		ctx.JSON(oauthErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// Token godoc
+// @Summary Issue an access token
+// @Description Token endpoint (RFC 6749, 3.2). Exchange an authorization code with its code_verifier, or get a token as the account of the client with client_credentials. Confidential clients authenticate with HTTP Basic or client_id and client_secret in the form; public clients send only client_id. Send the token as a bearer token: it works on the routes its scopes allow
+// @Tags oauth
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Param grant_type formData string true "authorization_code or client_credentials"
+// @Param code formData string false "Authorization code"
+// @Param redirect_uri formData string false "Redirect URI of the authorization request"
+// @Param code_verifier formData string false "PKCE code verifier"
+// @Param scope formData string false "Scopes asked for with client_credentials, separated by spaces"
+// @Param client_id formData string false "Client ID, unless sent with HTTP Basic"
+// @Param client_secret formData string false "Client secret, unless sent with HTTP Basic"
+// @Success 200 {object} dto.OAuthTokenResponse "Token issued"
+// @Failure 400 {object} dto.OAuthErrorResponse "Invalid request, grant or scope"
+// @Failure 401 {object} dto.OAuthErrorResponse "Client authentication failed"
+// @Failure 500 {object} dto.OAuthErrorResponse "Internal server error"
+// @Router /oauth/token [post]
+func (oc *OAuthController) Token(ctx *gin.Context) {
+	ctx.Header("Cache-Control", "no-store")
+	var req dto.OAuthTokenRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		writeOAuthError(ctx, &usecase.OAuthError{Code: "invalid_request", Description: err.Error()})
+		return
+	}
+	if err := clientCredentials(ctx, &req.ClientID, &req.ClientSecret); err != nil {
+		writeOAuthError(ctx, err)
+		return
+	}
+
+	response, err := oc.oauthUsecase.Token(ctx.Request.Context(), req)
+	if err != nil {
+		writeOAuthError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// Introspect godoc
+// @Summary Introspect an access token
+// @Description Introspection endpoint (RFC 7662): tell whether an access token of the authenticated client is active, with its scopes and user. Tokens of other clients are reported inactive
+// @Tags oauth
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Param token formData string true "Access token"
+// @Param token_type_hint formData string false "Ignored; only access tokens exist"
+// @Param client_id formData string false "Client ID, unless sent with HTTP Basic"
+// @Param client_secret formData string false "Client secret, unless sent with HTTP Basic"
+// @Success 200 {object} dto.OAuthIntrospectionResponse "State of the token"
+// @Failure 400 {object} dto.OAuthErrorResponse "Invalid request"
+// @Failure 401 {object} dto.OAuthErrorResponse "Client authentication failed"
+// @Failure 500 {object} dto.OAuthErrorResponse "Internal server error"
+// @Router /oauth/introspect [post]
+func (oc *OAuthController) Introspect(ctx *gin.Context) {
+	ctx.Header("Cache-Control", "no-store")
+	var req dto.OAuthTokenActionRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		writeOAuthError(ctx, &usecase.OAuthError{Code: "invalid_request", Description: err.Error()})
+		return
+	}
+	if err := clientCredentials(ctx, &req.ClientID, &req.ClientSecret); err != nil {
+		writeOAuthError(ctx, err)
+		return
+	}
+
+	response, err := oc.oauthUsecase.Introspect(ctx.Request.Context(), req)
+	if err != nil {
+		writeOAuthError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// Revoke godoc
+// @Summary Revoke an access token
+// @Description Revocation endpoint (RFC 7009): turn off an access token of the authenticated client. Answers 200 for unknown tokens too
+// @Tags oauth
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Param token formData string true "Access token"
+// @Param token_type_hint formData string false "Ignored; only access tokens exist"
+// @Param client_id formData string false "Client ID, unless sent with HTTP Basic"
+// @Param client_secret formData string false "Client secret, unless sent with HTTP Basic"
+// @Success 200 "Token revoked, or unknown"
+// @Failure 400 {object} dto.OAuthErrorResponse "Invalid request"
+// @Failure 401 {object} dto.OAuthErrorResponse "Client authentication failed"
+// @Failure 500 {object} dto.OAuthErrorResponse "Internal server error"
+// @Router /oauth/revoke [post]
+func (oc *OAuthController) Revoke(ctx *gin.Context) {
+	var req dto.OAuthTokenActionRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		writeOAuthError(ctx, &usecase.OAuthError{Code: "invalid_request", Description: err.Error()})
+		return
+	}
+	if err := clientCredentials(ctx, &req.ClientID, &req.ClientSecret); err != nil {
+		writeOAuthError(ctx, err)
+		return
+	}
+
+	if err := oc.oauthUsecase.Revoke(ctx.Request.Context(), req); err != nil {
+		writeOAuthError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+}
+
+// GetConsents godoc
+// @Summary List the applications granted access
+// @Description List the OAuth clients the logged in user granted access to, with the scopes granted
+// @Tags oauth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} dto.OAuthConsentResponse "Consents"
+// @Failure 401 {object} model.Response "Missing or invalid token"
+// @Failure 500 {object} model.Response "Internal server error"
+// @Router /me/oauth/consents [get]
+func (oc *OAuthController) GetConsents(ctx *gin.Context) {
+	consents, err := oc.oauthUsecase.GetConsents(ctx.GetInt(middleware.ContextUserID))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, consents)
+}
+
+// RevokeConsent godoc
+// @Summary Revoke the access of an application
+// @Description Withdraw the consent the logged in user gave a client: its access tokens for the user stop working and the next authorization asks again
+// @Tags oauth
+// @Produce json
+// @Security BearerAuth
+// @Param clientId path string true "Client ID"
+// @Success 204 "Access revoked"
+// @Failure 401 {object} model.Response "Missing or invalid token"
+// @Failure 404 {object} model.Response "No consent given to the client"
+// @Failure 500 {object} model.Response "Internal server error"
+// @Router /me/oauth/consents/{clientId} [delete]
+func (oc *OAuthController) RevokeConsent(ctx *gin.Context) {
+	if err := oc.oauthUsecase.RevokeConsent(ctx.Request.Context(), ctx.GetInt(middleware.ContextUserID), ctx.Param("clientId")); err != nil {
+		ctx.JSON(oauthErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// --- Helper Functions ---
+
+func oauthErrorStatus(err error) int {
+	var oauthErr *usecase.OAuthError
+	switch {
+	case errors.As(err, &oauthErr):
+		if oauthErr.Code == usecase.ErrOAuthInvalidClient.Code {
+			return http.StatusUnauthorized
+		}
+		return http.StatusBadRequest
+	case errors.Is(err, usecase.ErrOAuthClientNotFound), errors.Is(err, usecase.ErrUserNotFound), errors.Is(err, usecase.ErrOAuthConsentNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, usecase.ErrOAuthClientRevoked):
+		return http.StatusConflict
+	case errors.Is(err, usecase.ErrInvalidOAuthGrantType), errors.Is(err, usecase.ErrInvalidOAuthScope),
+		errors.Is(err, usecase.ErrInvalidRedirectURI), errors.Is(err, usecase.ErrInvalidOAuthClientOwner):
+		return http.StatusBadRequest
+	case errors.Is(err, usecase.ErrOAuthLoginRequired), errors.Is(err, usecase.ErrInvalidCredentials):
+		return http.StatusUnauthorized
+	case errors.Is(err, usecase.ErrLoginThrottled):
+		return http.StatusTooManyRequests
+	case errors.Is(err, usecase.ErrEmailNotVerified):
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// clientCredentials takes the client ID and secret of the HTTP Basic
+// header, whose parts are form-encoded (RFC 6749, 2.3.1). A client must
+// not use both ways at once
+func clientCredentials(ctx *gin.Context, clientID, clientSecret *string) error {
+	username, password, ok := ctx.Request.BasicAuth()
+	if !ok {
+		return nil
+	}
+	if *clientID != "" || *clientSecret != "" {
+		return &usecase.OAuthError{Code: "invalid_request", Description: "send the client credentials either with HTTP Basic or in the form"}
+	}
+	var err error
+	if *clientID, err = url.QueryUnescape(username); err != nil {
+		return usecase.ErrOAuthInvalidClient
+	}
+	if *clientSecret, err = url.QueryUnescape(password); err != nil {
+		return usecase.ErrOAuthInvalidClient
+	}
+	return nil
+}
+
+// writeOAuthError answers with the error body of RFC 6749, 5.2
+func writeOAuthError(ctx *gin.Context, err error) {
+	var oauthErr *usecase.OAuthError
+	if !errors.As(err, &oauthErr) {
+		ctx.JSON(http.StatusInternalServerError, dto.OAuthErrorResponse{Error: "server_error", ErrorDescription: err.Error()})
+		return
+	}
+	if oauthErr.Code == usecase.ErrOAuthInvalidClient.Code {
+		ctx.Header("WWW-Authenticate", `Basic realm="oauth"`)
+	}
+	ctx.JSON(oauthErrorStatus(err), dto.OAuthErrorResponse{Error: oauthErr.Code, ErrorDescription: oauthErr.Description})
+}
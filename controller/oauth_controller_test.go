@@ -0,0 +1,334 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"go-api/dto"
+	"go-api/middleware"
+	"go-api/usecase"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOAuthCreateClient(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{"Success", nil, http.StatusCreated},
+		{"Invalid Redirect URI", usecase.ErrInvalidRedirectURI, http.StatusBadRequest},
+		{"Owner Not Found", usecase.ErrUserNotFound, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUsecase := &MockOAuthUsecase{
+				CreateClientFunc: func(ctx context.Context, request dto.CreateOAuthClientRequest) (*dto.OAuthClientSecretResponse, error) {
+					if tt.err != nil {
+						return nil, tt.err
+					}
+					return &dto.OAuthClientSecretResponse{ClientSecret: "secret", Client: dto.OAuthClientResponse{ClientID: "webapp"}}, nil
+				},
+			}
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			body := `{"name": "Agenda", "grant_types": ["authorization_code"], "redirect_uris": ["https://agenda.example.com/cb"], "scopes": ["orders:read"]}`
+			c.Request, _ = http.NewRequest(http.MethodPost, "/oauth/clients", strings.NewReader(body))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			NewOAuthController(mockUsecase).CreateClient(c)
+
+			assert.Equal(t, tt.status, w.Code)
+		})
+	}
+}
+
+func TestOAuthRevokeClient(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{"Success", nil, http.StatusNoContent},
+		{"Not Found", usecase.ErrOAuthClientNotFound, http.StatusNotFound},
+		{"Already Revoked", usecase.ErrOAuthClientRevoked, http.StatusConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUsecase := &MockOAuthUsecase{
+				RevokeClientFunc: func(ctx context.Context, clientID string) error {
+					assert.Equal(t, "webapp", clientID)
+					return tt.err
+				},
+			}
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest(http.MethodDelete, "/oauth/clients/webapp", nil)
+			c.Params = gin.Params{{Key: "clientId", Value: "webapp"}}
+
+			NewOAuthController(mockUsecase).RevokeClient(c)
+
+			assert.Equal(t, tt.status, c.Writer.Status())
+		})
+	}
+}
+
+func TestOAuthGetAuthorization(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	query := "/oauth/authorize?response_type=code&client_id=webapp&redirect_uri=https%3A%2F%2Fagenda.example.com%2Fcb&scope=orders%3Aread&state=xyz&code_challenge=abc&code_challenge_method=S256"
+
+	t.Run("Success", func(t *testing.T) {
+		mockUsecase := &MockOAuthUsecase{
+			GetAuthorizationFunc: func(userID int, request dto.OAuthAuthorizeRequest) (*dto.OAuthAuthorizationResponse, error) {
+				assert.Equal(t, 7, userID)
+				assert.Equal(t, "https://agenda.example.com/cb", request.RedirectURI)
+				assert.Equal(t, "xyz", request.State)
+				return &dto.OAuthAuthorizationResponse{ClientID: "webapp", ClientName: "Agenda", ConsentRequired: true}, nil
+			},
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodGet, query, nil)
+		c.Set(middleware.ContextUserID, 7)
+
+		NewOAuthController(mockUsecase).GetAuthorization(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("Unregistered Redirect URI", func(t *testing.T) {
+		mockUsecase := &MockOAuthUsecase{
+			GetAuthorizationFunc: func(userID int, request dto.OAuthAuthorizeRequest) (*dto.OAuthAuthorizationResponse, error) {
+				return nil, &usecase.OAuthError{Code: "invalid_request", Description: "redirect_uri is not registered for the client"}
+			},
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodGet, query, nil)
+
+		NewOAuthController(mockUsecase).GetAuthorization(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Missing Parameters", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodGet, "/oauth/authorize?client_id=webapp", nil)
+
+		NewOAuthController(&MockOAuthUsecase{}).GetAuthorization(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestOAuthAuthorize(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	body := `{"response_type": "code", "client_id": "webapp", "redirect_uri": "https://agenda.example.com/cb", "code_challenge": "abc", "code_challenge_method": "S256", "approve": true, "email": "user@example.com", "password": "secret"}`
+
+	tests := []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{"Success", nil, http.StatusOK},
+		{"Wrong Password", usecase.ErrInvalidCredentials, http.StatusUnauthorized},
+		{"No Login", usecase.ErrOAuthLoginRequired, http.StatusUnauthorized},
+		{"Throttled", &usecase.LoginThrottledError{RetryAfter: time.Minute}, http.StatusTooManyRequests},
+		{"Unknown Client", usecase.ErrOAuthInvalidClient, http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUsecase := &MockOAuthUsecase{
+				AuthorizeFunc: func(ctx context.Context, userID int, mfa bool, request dto.OAuthApproveRequest) (*dto.OAuthAuthorizeResponse, error) {
+					assert.Zero(t, userID)
+					assert.True(t, request.Approve)
+					assert.Equal(t, "user@example.com", request.Email)
+					if tt.err != nil {
+						return nil, tt.err
+					}
+					return &dto.OAuthAuthorizeResponse{RedirectURI: "https://agenda.example.com/cb?code=abc"}, nil
+				},
+			}
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest(http.MethodPost, "/oauth/authorize", strings.NewReader(body))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			NewOAuthController(mockUsecase).Authorize(c)
+
+			assert.Equal(t, tt.status, w.Code)
+			if tt.status == http.StatusTooManyRequests {
+				assert.Equal(t, "60", w.Header().Get("Retry-After"))
+			}
+		})
+	}
+}
+
+func TestOAuthToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	form := url.Values{"grant_type": {"authorization_code"}, "code": {"abc"}, "redirect_uri": {"https://agenda.example.com/cb"}, "code_verifier": {"verifier"}}
+
+	newRequest := func(values url.Values) *http.Request {
+		req, _ := http.NewRequest(http.MethodPost, "/oauth/token", strings.NewReader(values.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		return req
+	}
+
+	t.Run("Basic Authentication", func(t *testing.T) {
+		mockUsecase := &MockOAuthUsecase{
+			TokenFunc: func(ctx context.Context, request dto.OAuthTokenRequest) (*dto.OAuthTokenResponse, error) {
+				assert.Equal(t, "webapp", request.ClientID)
+				assert.Equal(t, "s3cr:t", request.ClientSecret)
+				assert.Equal(t, "abc", request.Code)
+				return &dto.OAuthTokenResponse{AccessToken: "gat_token", TokenType: "Bearer", ExpiresIn: 3600, Scope: "orders:read"}, nil
+			},
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = newRequest(form)
+		c.Request.SetBasicAuth("webapp", url.QueryEscape("s3cr:t"))
+
+		NewOAuthController(mockUsecase).Token(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+		var response dto.OAuthTokenResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "gat_token", response.AccessToken)
+	})
+
+	t.Run("Credentials Sent Twice", func(t *testing.T) {
+		values := url.Values{"client_id": {"webapp"}}
+		for k, v := range form {
+			values[k] = v
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = newRequest(values)
+		c.Request.SetBasicAuth("webapp", "secret")
+
+		NewOAuthController(&MockOAuthUsecase{}).Token(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"error": "invalid_request", "error_description": "send the client credentials either with HTTP Basic or in the form"}`, w.Body.String())
+	})
+
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		code   string
+	}{
+		{"Invalid Client", usecase.ErrOAuthInvalidClient, http.StatusUnauthorized, "invalid_client"},
+		{"Invalid Grant", usecase.ErrOAuthInvalidGrant, http.StatusBadRequest, "invalid_grant"},
+		{"Internal Error", context.DeadlineExceeded, http.StatusInternalServerError, "server_error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUsecase := &MockOAuthUsecase{
+				TokenFunc: func(ctx context.Context, request dto.OAuthTokenRequest) (*dto.OAuthTokenResponse, error) {
+					return nil, tt.err
+				},
+			}
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = newRequest(form)
+
+			NewOAuthController(mockUsecase).Token(c)
+
+			assert.Equal(t, tt.status, w.Code)
+			var response dto.OAuthErrorResponse
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, tt.code, response.Error)
+			if tt.status == http.StatusUnauthorized {
+				assert.NotEmpty(t, w.Header().Get("WWW-Authenticate"))
+			}
+		})
+	}
+}
+
+func TestOAuthIntrospectAndRevoke(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockUsecase := &MockOAuthUsecase{
+		IntrospectFunc: func(ctx context.Context, request dto.OAuthTokenActionRequest) (*dto.OAuthIntrospectionResponse, error) {
+			assert.Equal(t, "gat_token", request.Token)
+			assert.Equal(t, "worker", request.ClientID)
+			return &dto.OAuthIntrospectionResponse{Active: true, ClientID: "worker"}, nil
+		},
+		RevokeFunc: func(ctx context.Context, request dto.OAuthTokenActionRequest) error {
+			assert.Equal(t, "gat_token", request.Token)
+			return nil
+		},
+	}
+	newContext := func(path string) (*httptest.ResponseRecorder, *gin.Context) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodPost, path, strings.NewReader("token=gat_token"))
+		c.Request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		c.Request.SetBasicAuth("worker", "secret")
+		return w, c
+	}
+
+	w, c := newContext("/oauth/introspect")
+	NewOAuthController(mockUsecase).Introspect(c)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"active": true, "client_id": "worker"}`, w.Body.String())
+
+	w, c = newContext("/oauth/revoke")
+	NewOAuthController(mockUsecase).Revoke(c)
+	assert.Equal(t, http.StatusOK, c.Writer.Status())
+}
+
+func TestOAuthRevokeConsent(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{"Success", nil, http.StatusNoContent},
+		{"No Consent", usecase.ErrOAuthConsentNotFound, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUsecase := &MockOAuthUsecase{
+				RevokeConsentFunc: func(ctx context.Context, userID int, clientID string) error {
+					assert.Equal(t, 7, userID)
+					assert.Equal(t, "webapp", clientID)
+					return tt.err
+				},
+			}
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest(http.MethodDelete, "/me/oauth/consents/webapp", nil)
+			c.Params = gin.Params{{Key: "clientId", Value: "webapp"}}
+			c.Set(middleware.ContextUserID, 7)
+
+			NewOAuthController(mockUsecase).RevokeConsent(c)
+
+			assert.Equal(t, tt.status, c.Writer.Status())
+		})
+	}
+}
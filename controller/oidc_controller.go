@@ -0,0 +1,161 @@
+package controller
+
+import (
+	"errors"
+	"go-api/dto"
+	"go-api/middleware"
+	"go-api/usecase"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OIDCController handles HTTP requests for signing in with identity providers
+type OIDCController struct {
+	oidcUsecase usecase.OIDCUsecase
+}
+
+// NewOIDCController creates a new OIDCController
+func NewOIDCController(usecase usecase.OIDCUsecase) *OIDCController {
+	return &OIDCController{
+		oidcUsecase: usecase,
+	}
+}
+
+// Authorize godoc
+// @Summary Start a sign in with an identity provider
+// @Description Return the URL of the provider to send the user to and the state it sends back to the callback, valid for 10 minutes. The client must keep the state and only call the callback with the state it started, so nobody can sign it in to another account
+// @Tags auth
+// @Produce json
+// @Param provider path string true "Name of the identity provider"
+// @Success 200 {object} dto.OIDCAuthorizeResponse "Sign in started"
+// @Failure 401 {object} model.Response "Identity provider unavailable"
+// @Failure 404 {object} model.Response "Unknown identity provider"
+// @Failure 500 {object} model.Response "Internal server error"
+// @Router /auth/oidc/{provider}/authorize [get]
+func (oc *OIDCController) Authorize(ctx *gin.Context) {
+	response, err := oc.oidcUsecase.Authorize(ctx.Request.Context(), ctx.Param("provider"), 0)
+	if err != nil {
+		ctx.JSON(oidcErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// Link godoc
+// @Summary Start linking an identity provider to the account
+// @Description Like authorize, for the logged in user: the callback, called with the same token, links the identity to the account and signs in with it
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Param provider path string true "Name of the identity provider"
+// @Success 200 {object} dto.OIDCAuthorizeResponse "Linking started"
+// @Failure 401 {object} model.Response "Missing or invalid token, or identity provider unavailable"
+// @Failure 404 {object} model.Response "Unknown identity provider"
+// @Failure 500 {object} model.Response "Internal server error"
+// @Router /auth/oidc/{provider}/link [post]
+func (oc *OIDCController) Link(ctx *gin.Context) {
+	response, err := oc.oidcUsecase.Authorize(ctx.Request.Context(), ctx.Param("provider"), ctx.GetInt(middleware.ContextUserID))
+	if err != nil {
+		ctx.JSON(oidcErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// Callback godoc
+// @Summary Finish a sign in with an identity provider
+// @Description Exchange the code the provider sent back for the JWT token, as a password login does: a user with two-factor authentication gets an MFA challenge unless the provider reports a login with more than one factor. Takes the query parameters of the redirect or the same fields as a JSON body. An identity not linked yet is linked when the sign in was started by link, or to the user with the same verified email when the provider is trusted to. The anonymous cart of cart_token (or of the X-Cart-Token header) is merged into the user's cart
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param provider path string true "Name of the identity provider"
+// @Param code query string false "Code sent by the provider"
+// @Param state query string true "State returned by authorize or link"
+// @Param X-Cart-Token header string false "Token of the anonymous cart to merge"
+// @Success 200 {object} dto.LoginResponse "Login successful"
+// @Failure 400 {object} model.Response "Missing, unknown, used or expired state"
+// @Failure 401 {object} model.Response "Sign in failed at the provider, or no account linked to the identity"
+// @Failure 404 {object} model.Response "Unknown identity provider"
+// @Failure 409 {object} model.Response "Identity linked to another account, or the account has one of this provider"
+// @Failure 500 {object} model.Response "Internal server error"
+// @Router /auth/oidc/{provider}/callback [get]
+// @Router /auth/oidc/{provider}/callback [post]
+func (oc *OIDCController) Callback(ctx *gin.Context) {
+	var req dto.OIDCCallbackRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.CartToken == "" {
+		req.CartToken = ctx.GetHeader(CartTokenHeader)
+	}
+
+	response, err := oc.oidcUsecase.Callback(ctx.Request.Context(), ctx.Param("provider"), ctx.GetInt(middleware.ContextUserID), req)
+	if err != nil {
+		ctx.JSON(oidcErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// Unlink godoc
+// @Summary Unlink an identity provider from the account
+// @Description Remove the identity of the provider from the logged in user, who keeps signing in with the password
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Param provider path string true "Name of the identity provider"
+// @Success 204 "Identity unlinked"
+// @Failure 401 {object} model.Response "Missing or invalid token"
+// @Failure 404 {object} model.Response "Unknown identity provider, or none linked"
+// @Failure 500 {object} model.Response "Internal server error"
+// @Router /auth/oidc/{provider}/link [delete]
+func (oc *OIDCController) Unlink(ctx *gin.Context) {
+	if err := oc.oidcUsecase.Unlink(ctx.Request.Context(), ctx.GetInt(middleware.ContextUserID), ctx.Param("provider")); err != nil {
+		ctx.JSON(oidcErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// GetIdentities godoc
+// @Summary List the linked identity providers
+// @Description List the identities at identity providers linked to the logged in user
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} dto.UserIdentityResponse "Linked identities"
+// @Failure 401 {object} model.Response "Missing or invalid token"
+// @Failure 500 {object} model.Response "Internal server error"
+// @Router /me/identities [get]
+func (oc *OIDCController) GetIdentities(ctx *gin.Context) {
+	identities, err := oc.oidcUsecase.GetIdentities(ctx.GetInt(middleware.ContextUserID))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, identities)
+}
+
+// --- Helper Functions ---
+
+func oidcErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, usecase.ErrUnknownOIDCProvider), errors.Is(err, usecase.ErrOIDCNotLinked):
+		return http.StatusNotFound
+	case errors.Is(err, usecase.ErrInvalidOIDCState):
+		return http.StatusBadRequest
+	case errors.Is(err, usecase.ErrOIDCSignInFailed), errors.Is(err, usecase.ErrOIDCAccountNotFound):
+		return http.StatusUnauthorized
+	case errors.Is(err, usecase.ErrOIDCIdentityTaken):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,216 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"go-api/dto"
+	"go-api/middleware"
+	"go-api/usecase"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOIDCAuthorize(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{"Success", nil, http.StatusOK},
+		{"Unknown Provider", usecase.ErrUnknownOIDCProvider, http.StatusNotFound},
+		{"Provider Unavailable", usecase.ErrOIDCSignInFailed, http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUsecase := &MockOIDCUsecase{
+				AuthorizeFunc: func(ctx context.Context, provider string, linkUserID int) (*dto.OIDCAuthorizeResponse, error) {
+					assert.Equal(t, "corp", provider)
+					assert.Zero(t, linkUserID)
+					if tt.err != nil {
+						return nil, tt.err
+					}
+					return &dto.OIDCAuthorizeResponse{AuthorizationURL: "https://idp.example.com/authorize", State: "state"}, nil
+				},
+			}
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest(http.MethodGet, "/auth/oidc/corp/authorize", nil)
+			c.Params = gin.Params{{Key: "provider", Value: "corp"}}
+
+			NewOIDCController(mockUsecase).Authorize(c)
+
+			assert.Equal(t, tt.status, w.Code)
+		})
+	}
+}
+
+func TestOIDCLink(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockUsecase := &MockOIDCUsecase{
+		AuthorizeFunc: func(ctx context.Context, provider string, linkUserID int) (*dto.OIDCAuthorizeResponse, error) {
+			assert.Equal(t, 7, linkUserID)
+			return &dto.OIDCAuthorizeResponse{AuthorizationURL: "https://idp.example.com/authorize", State: "state"}, nil
+		},
+	}
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodPost, "/auth/oidc/corp/link", nil)
+	c.Params = gin.Params{{Key: "provider", Value: "corp"}}
+	c.Set(middleware.ContextUserID, 7)
+
+	NewOIDCController(mockUsecase).Link(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response dto.OIDCAuthorizeResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "state", response.State)
+}
+
+func TestOIDCCallback(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Query Parameters", func(t *testing.T) {
+		mockUsecase := &MockOIDCUsecase{
+			CallbackFunc: func(ctx context.Context, provider string, userID int, request dto.OIDCCallbackRequest) (*dto.LoginResponse, error) {
+				assert.Equal(t, "corp", provider)
+				assert.Zero(t, userID)
+				assert.Equal(t, dto.OIDCCallbackRequest{Code: "code", State: "state", CartToken: "anon-token"}, request)
+				return &dto.LoginResponse{Token: "jwt"}, nil
+			},
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodGet, "/auth/oidc/corp/callback?code=code&state=state", nil)
+		c.Request.Header.Set(CartTokenHeader, "anon-token")
+		c.Params = gin.Params{{Key: "provider", Value: "corp"}}
+
+		NewOIDCController(mockUsecase).Callback(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response dto.LoginResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "jwt", response.Token)
+	})
+
+	t.Run("JSON Body Of A Logged In User", func(t *testing.T) {
+		mockUsecase := &MockOIDCUsecase{
+			CallbackFunc: func(ctx context.Context, provider string, userID int, request dto.OIDCCallbackRequest) (*dto.LoginResponse, error) {
+				assert.Equal(t, 7, userID)
+				assert.Equal(t, "code", request.Code)
+				return &dto.LoginResponse{Token: "jwt"}, nil
+			},
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodPost, "/auth/oidc/corp/callback", strings.NewReader(`{"code":"code","state":"state"}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Params = gin.Params{{Key: "provider", Value: "corp"}}
+		c.Set(middleware.ContextUserID, 7)
+
+		NewOIDCController(mockUsecase).Callback(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	tests := []struct {
+		name   string
+		query  string
+		err    error
+		status int
+	}{
+		{"Missing State", "?code=code", nil, http.StatusBadRequest},
+		{"Invalid State", "?code=code&state=state", usecase.ErrInvalidOIDCState, http.StatusBadRequest},
+		{"Provider Error", "?error=access_denied&state=state", usecase.ErrOIDCSignInFailed, http.StatusUnauthorized},
+		{"No Account", "?code=code&state=state", usecase.ErrOIDCAccountNotFound, http.StatusUnauthorized},
+		{"Identity Taken", "?code=code&state=state", usecase.ErrOIDCIdentityTaken, http.StatusConflict},
+		{"Internal Error", "?code=code&state=state", errors.New("db down"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUsecase := &MockOIDCUsecase{
+				CallbackFunc: func(ctx context.Context, provider string, userID int, request dto.OIDCCallbackRequest) (*dto.LoginResponse, error) {
+					return nil, tt.err
+				},
+			}
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest(http.MethodGet, "/auth/oidc/corp/callback"+tt.query, nil)
+			c.Params = gin.Params{{Key: "provider", Value: "corp"}}
+
+			NewOIDCController(mockUsecase).Callback(c)
+
+			assert.Equal(t, tt.status, w.Code)
+		})
+	}
+}
+
+func TestOIDCUnlink(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{"Success", nil, http.StatusNoContent},
+		{"Not Linked", usecase.ErrOIDCNotLinked, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUsecase := &MockOIDCUsecase{
+				UnlinkFunc: func(ctx context.Context, userID int, provider string) error {
+					assert.Equal(t, 7, userID)
+					assert.Equal(t, "corp", provider)
+					return tt.err
+				},
+			}
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest(http.MethodDelete, "/auth/oidc/corp/link", nil)
+			c.Params = gin.Params{{Key: "provider", Value: "corp"}}
+			c.Set(middleware.ContextUserID, 7)
+
+			NewOIDCController(mockUsecase).Unlink(c)
+
+			assert.Equal(t, tt.status, c.Writer.Status())
+		})
+	}
+}
+
+func TestGetIdentities(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockUsecase := &MockOIDCUsecase{
+		GetIdentitiesFunc: func(userID int) ([]dto.UserIdentityResponse, error) {
+			assert.Equal(t, 7, userID)
+			return []dto.UserIdentityResponse{{Provider: "corp", Email: "ana@corp.example"}}, nil
+		},
+	}
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodGet, "/me/identities", nil)
+	c.Set(middleware.ContextUserID, 7)
+
+	NewOIDCController(mockUsecase).GetIdentities(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response []dto.UserIdentityResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "corp", response[0].Provider)
+}
@@ -0,0 +1,338 @@
+package controller
+
+import (
+	"errors"
+	"go-api/dto"
+	"go-api/middleware"
+	"go-api/model"
+	"go-api/usecase"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OrderController handles HTTP requests for checkout and orders
+type OrderController struct {
+	orderUsecase usecase.OrderUsecase
+}
+
+// NewOrderController creates a new OrderController
+func NewOrderController(usecase usecase.OrderUsecase) *OrderController {
+	return &OrderController{
+		orderUsecase: usecase,
+	}
+}
+
+// Checkout godoc
+// @Summary Place an order
+// @Description Order the given items or, without items, the whole cart of the authenticated user, which is then emptied. Prices are the ones in effect now, the automatic promotions and the given discount codes are applied and redeemed, names and prices are kept on the order as they are, and the variant stock is taken at once. Every item must share the same currency
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param checkout body dto.CheckoutRequest false "Items to order, the cart when empty, discount codes and the destination to tax the items"
+// @Success 201 {object} dto.OrderResponse "Order placed"
+// @Failure 400 {object} model.Response "Bad request - Empty cart, invalid items, mixed currencies, rejected discount code or destination without tax rates"
+// @Failure 401 {object} model.Response "Unauthorized"
+// @Failure 404 {object} model.Response "Product or variant not found"
+// @Failure 409 {object} model.Response "Not enough stock, cart item no longer available or promotion used up meanwhile"
+// @Failure 500 {object} model.Response "Internal server error"
+// @Router /checkout [post]
+func (oc *OrderController) Checkout(ctx *gin.Context) {
+	var req dto.CheckoutRequest
+	if ctx.Request.ContentLength != 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	items := make([]model.CartItemInput, 0, len(req.Items))
+	for _, item := range req.Items {
+		items = append(items, model.CartItemInput{
+			ProductID: item.ProductID,
+			VariantID: item.VariantID,
+			Quantity:  item.Quantity,
+		})
+	}
+
+	var destination *model.TaxDestination
+	if req.Destination != nil {
+		destination = &model.TaxDestination{Country: req.Destination.Country, State: req.Destination.State}
+	}
+
+	order, err := oc.orderUsecase.Checkout(ctx.Request.Context(), ctx.GetInt(middleware.ContextUserID), items, req.Codes, destination)
+	if err != nil {
+		ctx.JSON(orderErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, toOrderResponse(*order))
+}
+
+// GetMyOrders godoc
+// @Summary List my orders
+// @Description Get the orders of the authenticated user, newest first
+// @Tags orders
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} dto.OrderResponse "Orders"
+// @Failure 401 {object} model.Response "Unauthorized"
+// @Failure 403 {object} model.Response "Access token without the scope of the route"
+// @Failure 500 {object} model.Response "Internal server error"
+// @Router /me/orders [get]
+func (oc *OrderController) GetMyOrders(ctx *gin.Context) {
+	orders, err := oc.orderUsecase.GetUserOrders(ctx.GetInt(middleware.ContextUserID))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, toOrderResponses(orders))
+}
+
+// GetMyOrder godoc
+// @Summary Get one of my orders
+// @Description Get an order of the authenticated user with its status history
+// @Tags orders
+// @Produce json
+// @Security BearerAuth
+// @Param orderId path int true "Order ID" minimum(1)
+// @Success 200 {object} dto.OrderResponse "Order"
+// @Failure 400 {object} model.Response "Bad request - Invalid ID format"
+// @Failure 401 {object} model.Response "Unauthorized"
+// @Failure 403 {object} model.Response "Access token without the scope of the route"
+// @Failure 404 {object} model.Response "Order not found"
+// @Failure 500 {object} model.Response "Internal server error"
+// @Router /me/orders/{orderId} [get]
+func (oc *OrderController) GetMyOrder(ctx *gin.Context) {
+	orderId, err := strconv.Atoi(ctx.Param("orderId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid order ID"})
+		return
+	}
+
+	order, err := oc.orderUsecase.GetUserOrder(ctx.GetInt(middleware.ContextUserID), orderId)
+	if err != nil {
+		ctx.JSON(orderErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, toOrderResponse(*order))
+}
+
+// CancelMyOrder godoc
+// @Summary Cancel one of my orders
+// @Description Cancel an order of the authenticated user while it is pending; its stock is given back
+// @Tags orders
+// @Produce json
+// @Security BearerAuth
+// @Param orderId path int true "Order ID" minimum(1)
+// @Success 200 {object} dto.OrderResponse "Cancelled order"
+// @Failure 400 {object} model.Response "Bad request - Invalid ID format"
+// @Failure 401 {object} model.Response "Unauthorized"
+// @Failure 403 {object} model.Response "Access token without the scope of the route"
+// @Failure 404 {object} model.Response "Order not found"
+// @Failure 409 {object} model.Response "The order is no longer pending"
+// @Failure 500 {object} model.Response "Internal server error"
+// @Router /me/orders/{orderId}/cancel [post]
+func (oc *OrderController) CancelMyOrder(ctx *gin.Context) {
+	orderId, err := strconv.Atoi(ctx.Param("orderId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid order ID"})
+		return
+	}
+
+	order, err := oc.orderUsecase.CancelUserOrder(ctx.Request.Context(), ctx.GetInt(middleware.ContextUserID), orderId)
+	if err != nil {
+		ctx.JSON(orderErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, toOrderResponse(*order))
+}
+
+// GetOrders godoc
+// @Summary List orders
+// @Description Get the orders of every user, newest first
+// @Tags orders
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param status query string false "Only orders in this status" Enums(pending, paid, shipped, delivered, cancelled, refunded)
+// @Param user_id query int false "Only orders of this user"
+// @Success 200 {array} dto.OrderResponse "Orders"
+// @Failure 400 {object} model.Response "Bad request - Invalid filter"
+// @Failure 401 {object} model.Response "Unauthorized"
+// @Failure 403 {object} model.Response "Admin role required, or API key or access token without the scope of the route"
+// @Failure 500 {object} model.Response "Internal server error"
+// @Router /orders [get]
+func (oc *OrderController) GetOrders(ctx *gin.Context) {
+	filter := model.OrderFilter{Status: ctx.Query("status")}
+	if raw := ctx.Query("user_id"); raw != "" {
+		userID, err := strconv.Atoi(raw)
+		if err != nil || userID < 1 {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+			return
+		}
+		filter.UserID = userID
+	}
+
+	orders, err := oc.orderUsecase.GetOrders(filter)
+	if err != nil {
+		ctx.JSON(orderErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, toOrderResponses(orders))
+}
+
+// GetOrder godoc
+// @Summary Get an order
+// @Description Get any order with its status history
+// @Tags orders
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param orderId path int true "Order ID" minimum(1)
+// @Success 200 {object} dto.OrderResponse "Order"
+// @Failure 400 {object} model.Response "Bad request - Invalid ID format"
+// @Failure 401 {object} model.Response "Unauthorized"
+// @Failure 403 {object} model.Response "Admin role required, or API key or access token without the scope of the route"
+// @Failure 404 {object} model.Response "Order not found"
+// @Failure 500 {object} model.Response "Internal server error"
+// @Router /orders/{orderId} [get]
+func (oc *OrderController) GetOrder(ctx *gin.Context) {
+	orderId, err := strconv.Atoi(ctx.Param("orderId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid order ID"})
+		return
+	}
+
+	order, err := oc.orderUsecase.GetOrder(orderId)
+	if err != nil {
+		ctx.JSON(orderErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, toOrderResponse(*order))
+}
+
+// TransitionOrder godoc
+// @Summary Change the status of an order
+// @Description Move an order to a fulfilment status. Allowed: pending to cancelled, paid to shipped, shipped to delivered. Paid and refunded follow the payments: an order is paid through its payment, and refunded with POST /orders/{orderId}/refund. Cancelling gives the stock back
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param orderId path int true "Order ID" minimum(1)
+// @Param transition body dto.TransitionOrderRequest true "New status"
+// @Success 200 {object} dto.OrderResponse "Order after the change"
+// @Failure 400 {object} model.Response "Bad request - Invalid status, or paid or refunded"
+// @Failure 401 {object} model.Response "Unauthorized"
+// @Failure 403 {object} model.Response "Admin role required, or API key or access token without the scope of the route"
+// @Failure 404 {object} model.Response "Order not found"
+// @Failure 409 {object} model.Response "Transition not allowed from the current status"
+// @Failure 500 {object} model.Response "Internal server error"
+// @Router /orders/{orderId}/status [post]
+func (oc *OrderController) TransitionOrder(ctx *gin.Context) {
+	orderId, err := strconv.Atoi(ctx.Param("orderId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid order ID"})
+		return
+	}
+
+	var req dto.TransitionOrderRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	order, err := oc.orderUsecase.TransitionOrder(ctx.Request.Context(), orderId, req.Status, req.Note)
+	if err != nil {
+		ctx.JSON(orderErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, toOrderResponse(*order))
+}
+
+// --- Helper Functions ---
+
+func orderErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, usecase.ErrOrderNotFound), errors.Is(err, usecase.ErrProductNotFound),
+		errors.Is(err, usecase.ErrVariantNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, usecase.ErrInvalidTransition), errors.Is(err, usecase.ErrInsufficientStock),
+		errors.Is(err, usecase.ErrCartItemUnavailable), errors.Is(err, usecase.ErrPromotionUnavailable):
+		return http.StatusConflict
+	case errors.Is(err, usecase.ErrInvalidOrderStatus), errors.Is(err, usecase.ErrEmptyOrder),
+		errors.Is(err, usecase.ErrMixedCurrencies), errors.Is(err, usecase.ErrInvalidQuantity),
+		errors.Is(err, usecase.ErrVariantRequired), errors.Is(err, usecase.ErrCodeRejected),
+		errors.Is(err, usecase.ErrInvalidTaxDestination), errors.Is(err, usecase.ErrTaxRegionNotFound):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func toOrderResponses(orders []model.Order) []dto.OrderResponse {
+	responses := make([]dto.OrderResponse, 0, len(orders))
+	for _, order := range orders {
+		responses = append(responses, toOrderResponse(order))
+	}
+	return responses
+}
+
+func toOrderResponse(order model.Order) dto.OrderResponse {
+	items := make([]dto.OrderItemResponse, 0, len(order.Items))
+	for _, item := range order.Items {
+		items = append(items, dto.OrderItemResponse{
+			ID:          item.ID,
+			ProductID:   item.ProductID,
+			VariantID:   item.VariantID,
+			ProductName: item.ProductName,
+			SKU:         item.SKU,
+			UnitPrice:   toMoneyResponse(item.UnitPrice),
+			Quantity:    item.Quantity,
+			Subtotal:    toMoneyResponse(item.Subtotal),
+			Discount:    toMoneyResponse(item.Discount),
+			Tax:         toMoneyResponse(item.Tax),
+		})
+	}
+
+	response := dto.OrderResponse{
+		ID:          order.ID,
+		UserID:      order.UserID,
+		Status:      order.Status,
+		Items:       items,
+		Discount:    toMoneyResponse(order.Discount),
+		Tax:         toMoneyResponse(order.Tax),
+		TaxIncluded: order.TaxIncluded,
+		Total:       toMoneyResponse(order.Total),
+		CreatedAt:   order.CreatedAt,
+		UpdatedAt:   order.UpdatedAt,
+	}
+	if order.TaxDestination != nil {
+		response.TaxDestination = &dto.TaxDestinationResponse{Country: order.TaxDestination.Country, State: order.TaxDestination.State}
+	}
+	for _, tax := range order.Taxes {
+		response.Taxes = append(response.Taxes, toTaxAmountResponse(tax))
+	}
+	for _, applied := range order.Promotions {
+		response.Promotions = append(response.Promotions, toAppliedPromotionResponse(applied))
+	}
+	for _, transition := range order.History {
+		response.History = append(response.History, dto.OrderTransitionResponse{
+			From:       transition.From,
+			To:         transition.To,
+			ActorID:    transition.ActorID,
+			Note:       transition.Note,
+			OccurredAt: transition.OccurredAt,
+		})
+	}
+	return response
+}
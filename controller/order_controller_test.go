@@ -0,0 +1,237 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"go-api/dto"
+	"go-api/middleware"
+	"go-api/model"
+	"go-api/usecase"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckout(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Whole Cart Without Body", func(t *testing.T) {
+		mockUsecase := &MockOrderUsecase{
+			CheckoutFunc: func(ctx context.Context, userID int, items []model.CartItemInput, codes []string, destination *model.TaxDestination) (*model.Order, error) {
+				assert.Equal(t, 7, userID)
+				assert.Empty(t, items)
+				return &model.Order{
+					ID: 10, UserID: &userID, Status: model.OrderStatusPending,
+					Items: []model.OrderItem{{ID: 1, ProductName: "Camiseta", Quantity: 2,
+						UnitPrice: model.Money{Amount: 4990, Currency: "BRL"}, Subtotal: model.Money{Amount: 9980, Currency: "BRL"}}},
+					Total:   model.Money{Amount: 9980, Currency: "BRL"},
+					History: []model.OrderTransition{{To: model.OrderStatusPending, ActorID: &userID}},
+				}, nil
+			},
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodPost, "/checkout", nil)
+		c.Set(middleware.ContextUserID, 7)
+
+		NewOrderController(mockUsecase).Checkout(c)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		var response dto.OrderResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, 10, response.ID)
+		assert.Equal(t, "pending", response.Status)
+		assert.Equal(t, dto.MoneyResponse{Amount: "99.80", Currency: "BRL"}, response.Total)
+		assert.Len(t, response.History, 1)
+	})
+
+	t.Run("Given Items", func(t *testing.T) {
+		mockUsecase := &MockOrderUsecase{
+			CheckoutFunc: func(ctx context.Context, userID int, items []model.CartItemInput, codes []string, destination *model.TaxDestination) (*model.Order, error) {
+				assert.Equal(t, []model.CartItemInput{{ProductID: 1, Quantity: 3}}, items)
+				return &model.Order{ID: 10}, nil
+			},
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodPost, "/checkout", bytes.NewBufferString(`{"items": [{"product_id": 1, "quantity": 3}]}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Set(middleware.ContextUserID, 7)
+
+		NewOrderController(mockUsecase).Checkout(c)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+	})
+
+	t.Run("Invalid Item", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodPost, "/checkout", bytes.NewBufferString(`{"items": [{"product_id": 1, "quantity": 0}]}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Set(middleware.ContextUserID, 7)
+
+		NewOrderController(&MockOrderUsecase{}).Checkout(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Empty Cart", func(t *testing.T) {
+		mockUsecase := &MockOrderUsecase{
+			CheckoutFunc: func(ctx context.Context, userID int, items []model.CartItemInput, codes []string, destination *model.TaxDestination) (*model.Order, error) {
+				return nil, fmt.Errorf("%w: the cart is empty", usecase.ErrEmptyOrder)
+			},
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodPost, "/checkout", nil)
+		c.Set(middleware.ContextUserID, 7)
+
+		NewOrderController(mockUsecase).Checkout(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Rejected Code", func(t *testing.T) {
+		mockUsecase := &MockOrderUsecase{
+			CheckoutFunc: func(ctx context.Context, userID int, items []model.CartItemInput, codes []string, destination *model.TaxDestination) (*model.Order, error) {
+				assert.Equal(t, []string{"VERAO"}, codes)
+				return nil, fmt.Errorf("%w: VERAO: the code has expired", usecase.ErrCodeRejected)
+			},
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodPost, "/checkout", bytes.NewBufferString(`{"codes": ["VERAO"]}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Set(middleware.ContextUserID, 7)
+
+		NewOrderController(mockUsecase).Checkout(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"error": "discount code rejected: VERAO: the code has expired"}`, w.Body.String())
+	})
+
+	t.Run("Promotion Used Up Meanwhile", func(t *testing.T) {
+		mockUsecase := &MockOrderUsecase{
+			CheckoutFunc: func(ctx context.Context, userID int, items []model.CartItemInput, codes []string, destination *model.TaxDestination) (*model.Order, error) {
+				return nil, usecase.ErrPromotionUnavailable
+			},
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodPost, "/checkout", bytes.NewBufferString(`{"codes": ["BEMVINDO10"]}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Set(middleware.ContextUserID, 7)
+
+		NewOrderController(mockUsecase).Checkout(c)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+}
+
+func TestCancelMyOrder(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("No Longer Pending", func(t *testing.T) {
+		mockUsecase := &MockOrderUsecase{
+			CancelUserOrderFunc: func(ctx context.Context, userID, orderID int) (*model.Order, error) {
+				assert.Equal(t, 7, userID)
+				assert.Equal(t, 10, orderID)
+				return nil, fmt.Errorf("%w: shipped to cancelled", usecase.ErrInvalidTransition)
+			},
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodPost, "/me/orders/10/cancel", nil)
+		c.Params = gin.Params{{Key: "orderId", Value: "10"}}
+		c.Set(middleware.ContextUserID, 7)
+
+		NewOrderController(mockUsecase).CancelMyOrder(c)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+}
+
+func TestGetOrders(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Filtered", func(t *testing.T) {
+		mockUsecase := &MockOrderUsecase{
+			GetOrdersFunc: func(filter model.OrderFilter) ([]model.Order, error) {
+				assert.Equal(t, model.OrderFilter{UserID: 7, Status: "paid"}, filter)
+				return []model.Order{{ID: 10, Status: "paid"}}, nil
+			},
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodGet, "/orders?status=paid&user_id=7", nil)
+
+		NewOrderController(mockUsecase).GetOrders(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("Invalid User ID", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodGet, "/orders?user_id=abc", nil)
+
+		NewOrderController(&MockOrderUsecase{}).GetOrders(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestTransitionOrder(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Success", func(t *testing.T) {
+		mockUsecase := &MockOrderUsecase{
+			TransitionOrderFunc: func(ctx context.Context, orderID int, status, note string) (*model.Order, error) {
+				assert.Equal(t, 10, orderID)
+				assert.Equal(t, "shipped", status)
+				assert.Equal(t, "Enviado pelos Correios", note)
+				return &model.Order{ID: 10, Status: status}, nil
+			},
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodPost, "/orders/10/status", bytes.NewBufferString(`{"status": "shipped", "note": "Enviado pelos Correios"}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Params = gin.Params{{Key: "orderId", Value: "10"}}
+
+		NewOrderController(mockUsecase).TransitionOrder(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("Order Not Found", func(t *testing.T) {
+		mockUsecase := &MockOrderUsecase{
+			TransitionOrderFunc: func(ctx context.Context, orderID int, status, note string) (*model.Order, error) {
+				return nil, usecase.ErrOrderNotFound
+			},
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodPost, "/orders/99/status", bytes.NewBufferString(`{"status": "shipped"}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Params = gin.Params{{Key: "orderId", Value: "99"}}
+
+		NewOrderController(mockUsecase).TransitionOrder(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
@@ -0,0 +1,90 @@
+package controller
+
+import (
+	"errors"
+	"go-api/dto"
+	"go-api/model"
+	"go-api/usecase"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// forgotPasswordMessage is the answer to every valid forgot password request,
+// whether or not the email has an account
+const forgotPasswordMessage = "If the email belongs to an account, a password reset link was sent to it"
+
+// PasswordResetController handles HTTP requests for recovering the password
+type PasswordResetController struct {
+	passwordResetUsecase usecase.PasswordResetUsecase
+}
+
+// NewPasswordResetController creates a new PasswordResetController
+func NewPasswordResetController(usecase usecase.PasswordResetUsecase) *PasswordResetController {
+	return &PasswordResetController{
+		passwordResetUsecase: usecase,
+	}
+}
+
+// ForgotPassword godoc
+// @Summary Ask for a password reset email
+// @Description Email a single-use link to choose a new password, valid for a short time. The answer is the same whether or not the email has an account
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body dto.ForgotPasswordRequest true "Email of the account"
+// @Success 202 {object} model.Response "Request accepted"
+// @Failure 400 {object} model.Response "Bad request - Invalid email"
+// @Failure 429 {object} model.Response "Too many requests for the email or from the IP; the Retry-After header tells when to try again"
+// @Failure 500 {object} model.Response "Internal server error"
+// @Router /auth/password/forgot [post]
+func (pc *PasswordResetController) ForgotPassword(ctx *gin.Context) {
+	var req dto.ForgotPasswordRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := pc.passwordResetUsecase.ForgotPassword(ctx.Request.Context(), req.Email); err != nil {
+		status := http.StatusInternalServerError
+		var throttled *usecase.PasswordResetThrottledError
+		if errors.As(err, &throttled) {
+			setRetryAfter(ctx, throttled.RetryAfter)
+			status = http.StatusTooManyRequests
+		}
+		ctx.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, model.Response{Message: forgotPasswordMessage})
+}
+
+// ResetPassword godoc
+// @Summary Choose a new password
+// @Description Set a new password with the token of the reset email. The token works once; the new password also invalidates the other tokens of the user
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body dto.ResetPasswordRequest true "Token and new password"
+// @Success 204 "Password changed"
+// @Failure 400 {object} model.Response "Bad request - Invalid input data, a password breaking the strength rules, or an invalid, used or expired token"
+// @Failure 500 {object} model.Response "Internal server error"
+// @Router /auth/password/reset [post]
+func (pc *PasswordResetController) ResetPassword(ctx *gin.Context) {
+	var req dto.ResetPasswordRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := pc.passwordResetUsecase.ResetPassword(ctx.Request.Context(), req.Token, req.Password); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, usecase.ErrInvalidResetToken) || errors.Is(err, usecase.ErrWeakPassword) {
+			status = http.StatusBadRequest
+		}
+		ctx.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,97 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"go-api/internal/password"
+	"go-api/model"
+	"go-api/usecase"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestForgotPassword(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name   string
+		body   string
+		err    error
+		status int
+	}{
+		{"Accepted", `{"email": "ana@example.com"}`, nil, http.StatusAccepted},
+		{"Invalid Email", `{"email": "ana"}`, nil, http.StatusBadRequest},
+		{"Throttled", `{"email": "ana@example.com"}`, &usecase.PasswordResetThrottledError{RetryAfter: 2 * time.Second}, http.StatusTooManyRequests},
+		{"Internal Error", `{"email": "ana@example.com"}`, errors.New("db down"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUsecase := &MockPasswordResetUsecase{
+				ForgotPasswordFunc: func(ctx context.Context, email string) error {
+					assert.Equal(t, "ana@example.com", email)
+					return tt.err
+				},
+			}
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest(http.MethodPost, "/auth/password/forgot", bytes.NewBufferString(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			NewPasswordResetController(mockUsecase).ForgotPassword(c)
+
+			assert.Equal(t, tt.status, w.Code)
+			if tt.status == http.StatusTooManyRequests {
+				assert.Equal(t, "2", w.Header().Get("Retry-After"))
+			}
+			if tt.status == http.StatusAccepted {
+				var response model.Response
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Equal(t, forgotPasswordMessage, response.Message)
+			}
+		})
+	}
+}
+
+func TestResetPassword(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name   string
+		body   string
+		err    error
+		status int
+	}{
+		{"Success", `{"token": "abc", "password": "newpassword"}`, nil, http.StatusNoContent},
+		{"Missing Password", `{"token": "abc"}`, nil, http.StatusBadRequest},
+		{"Weak Password", `{"token": "abc", "password": "newpassword"}`, &password.WeakError{Reason: "it is too common"}, http.StatusBadRequest},
+		{"Invalid Token", `{"token": "abc", "password": "newpassword"}`, usecase.ErrInvalidResetToken, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUsecase := &MockPasswordResetUsecase{
+				ResetPasswordFunc: func(ctx context.Context, token, newPassword string) error {
+					assert.Equal(t, "abc", token)
+					assert.Equal(t, "newpassword", newPassword)
+					return tt.err
+				},
+			}
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest(http.MethodPost, "/auth/password/reset", bytes.NewBufferString(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			NewPasswordResetController(mockUsecase).ResetPassword(c)
+
+			assert.Equal(t, tt.status, c.Writer.Status())
+		})
+	}
+}
@@ -0,0 +1,196 @@
+package controller
+
+import (
+	"errors"
+	"go-api/dto"
+	"go-api/middleware"
+	"go-api/model"
+	"go-api/usecase"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PaymentSignatureHeader carries the signature of the payment webhooks
+const PaymentSignatureHeader = "X-Payment-Signature"
+
+// maxWebhookSize bounds the body of a payment webhook
+const maxWebhookSize = 1 << 20
+
+// PaymentController handles HTTP requests for order payments and the webhooks
+// of the payment provider
+type PaymentController struct {
+	paymentUsecase usecase.PaymentUsecase
+}
+
+// NewPaymentController creates a new PaymentController
+func NewPaymentController(usecase usecase.PaymentUsecase) *PaymentController {
+	return &PaymentController{
+		paymentUsecase: usecase,
+	}
+}
+
+// PayOrder godoc
+// @Summary Pay one of my orders
+// @Description Charge the total of a pending order of the authenticated user and mark it paid. Repeating the request after a timeout does not charge twice
+// @Tags orders
+// @Produce json
+// @Security BearerAuth
+// @Param orderId path int true "Order ID" minimum(1)
+// @Success 201 {object} dto.PaymentResponse "Payment"
+// @Failure 400 {object} model.Response "Bad request - Invalid ID format"
+// @Failure 401 {object} model.Response "Unauthorized"
+// @Failure 402 {object} model.Response "Payment declined"
+// @Failure 403 {object} model.Response "Access token without the scope of the route"
+// @Failure 404 {object} model.Response "Order not found"
+// @Failure 409 {object} model.Response "The order is not pending"
+// @Failure 500 {object} model.Response "Internal server error"
+// @Failure 504 {object} model.Response "Payment provider did not answer in time"
+// @Router /me/orders/{orderId}/pay [post]
+func (pc *PaymentController) PayOrder(ctx *gin.Context) {
+	orderId, err := strconv.Atoi(ctx.Param("orderId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid order ID"})
+		return
+	}
+
+	payment, err := pc.paymentUsecase.PayOrder(ctx.Request.Context(), ctx.GetInt(middleware.ContextUserID), orderId)
+	if err != nil {
+		ctx.JSON(paymentErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, toPaymentResponse(*payment))
+}
+
+// GetOrderPayments godoc
+// @Summary List the payments of an order
+// @Description Get every attempt to pay the order, oldest first
+// @Tags orders
+// @Produce json
+// @Security BearerAuth
+// @Param orderId path int true "Order ID" minimum(1)
+// @Success 200 {array} dto.PaymentResponse "Payments"
+// @Failure 400 {object} model.Response "Bad request - Invalid ID format"
+// @Failure 401 {object} model.Response "Unauthorized"
+// @Failure 403 {object} model.Response "Forbidden"
+// @Failure 404 {object} model.Response "Order not found"
+// @Failure 500 {object} model.Response "Internal server error"
+// @Router /orders/{orderId}/payments [get]
+func (pc *PaymentController) GetOrderPayments(ctx *gin.Context) {
+	orderId, err := strconv.Atoi(ctx.Param("orderId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid order ID"})
+		return
+	}
+
+	payments, err := pc.paymentUsecase.GetOrderPayments(orderId)
+	if err != nil {
+		ctx.JSON(paymentErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	response := make([]dto.PaymentResponse, 0, len(payments))
+	for _, payment := range payments {
+		response = append(response, toPaymentResponse(payment))
+	}
+	ctx.JSON(http.StatusOK, response)
+}
+
+// RefundOrder godoc
+// @Summary Refund an order
+// @Description Give back the captured payment of a paid or delivered order and mark it refunded
+// @Tags orders
+// @Produce json
+// @Security BearerAuth
+// @Param orderId path int true "Order ID" minimum(1)
+// @Success 200 {object} dto.OrderResponse "Refunded order"
+// @Failure 400 {object} model.Response "Bad request - Invalid ID format"
+// @Failure 401 {object} model.Response "Unauthorized"
+// @Failure 403 {object} model.Response "Forbidden"
+// @Failure 404 {object} model.Response "Order not found"
+// @Failure 409 {object} model.Response "The order cannot be refunded or has no captured payment"
+// @Failure 500 {object} model.Response "Internal server error"
+// @Failure 504 {object} model.Response "Payment provider did not answer in time"
+// @Router /orders/{orderId}/refund [post]
+func (pc *PaymentController) RefundOrder(ctx *gin.Context) {
+	orderId, err := strconv.Atoi(ctx.Param("orderId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid order ID"})
+		return
+	}
+
+	order, err := pc.paymentUsecase.RefundOrder(ctx.Request.Context(), orderId)
+	if err != nil {
+		ctx.JSON(paymentErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, toOrderResponse(*order))
+}
+
+// HandlePaymentWebhook godoc
+// @Summary Receive a payment webhook
+// @Description Endpoint for the payment provider. The body must be signed in X-Payment-Signature; each event is applied once and repeated deliveries are acknowledged without effect. Any response other than 200 makes the provider deliver the event again
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Param X-Payment-Signature header string true "Signature of the body, t=<unix time>,v1=<hex HMAC-SHA256>"
+// @Success 200 {object} model.Response "Event received"
+// @Failure 400 {object} model.Response "Invalid signature or body"
+// @Failure 404 {object} model.Response "Unknown payment"
+// @Failure 500 {object} model.Response "Internal server error"
+// @Router /payments/webhook [post]
+func (pc *PaymentController) HandlePaymentWebhook(ctx *gin.Context) {
+	body, err := io.ReadAll(io.LimitReader(ctx.Request.Body, maxWebhookSize))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	applied, err := pc.paymentUsecase.HandleWebhook(ctx.Request.Context(), body, ctx.GetHeader(PaymentSignatureHeader))
+	if err != nil {
+		ctx.JSON(paymentErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	message := "Event applied"
+	if !applied {
+		message = "Event already received"
+	}
+	ctx.JSON(http.StatusOK, model.Response{Message: message})
+}
+
+// --- Helper Functions ---
+
+func paymentErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, usecase.ErrPaymentDeclined):
+		return http.StatusPaymentRequired
+	case errors.Is(err, usecase.ErrPaymentUnavailable):
+		return http.StatusGatewayTimeout
+	case errors.Is(err, usecase.ErrOrderNotPayable), errors.Is(err, usecase.ErrNothingToRefund):
+		return http.StatusConflict
+	case errors.Is(err, usecase.ErrInvalidWebhook):
+		return http.StatusBadRequest
+	case errors.Is(err, usecase.ErrPaymentNotFound):
+		return http.StatusNotFound
+	default:
+		return orderErrorStatus(err)
+	}
+}
+
+func toPaymentResponse(payment model.Payment) dto.PaymentResponse {
+	return dto.PaymentResponse{
+		ID:        payment.ID,
+		OrderID:   payment.OrderID,
+		Provider:  payment.Provider,
+		IntentID:  payment.IntentID,
+		Status:    payment.Status,
+		Amount:    toMoneyResponse(payment.Amount),
+		CreatedAt: payment.CreatedAt,
+		UpdatedAt: payment.UpdatedAt,
+	}
+}
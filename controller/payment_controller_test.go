@@ -0,0 +1,96 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"go-api/middleware"
+	"go-api/model"
+	"go-api/usecase"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPayOrder(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{"Success", nil, http.StatusCreated},
+		{"Declined", usecase.ErrPaymentDeclined, http.StatusPaymentRequired},
+		{"Provider Timeout", fmt.Errorf("%w: timed out", usecase.ErrPaymentUnavailable), http.StatusGatewayTimeout},
+		{"Not Pending", fmt.Errorf("%w: the order is paid", usecase.ErrOrderNotPayable), http.StatusConflict},
+		{"Order Not Found", usecase.ErrOrderNotFound, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUsecase := &MockPaymentUsecase{
+				PayOrderFunc: func(ctx context.Context, userID, orderID int) (*model.Payment, error) {
+					assert.Equal(t, 7, userID)
+					assert.Equal(t, 10, orderID)
+					if tt.err != nil {
+						return nil, tt.err
+					}
+					return &model.Payment{ID: 1, OrderID: orderID, Status: model.PaymentStatusSucceeded}, nil
+				},
+			}
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest(http.MethodPost, "/me/orders/10/pay", nil)
+			c.Params = gin.Params{{Key: "orderId", Value: "10"}}
+			c.Set(middleware.ContextUserID, 7)
+
+			NewPaymentController(mockUsecase).PayOrder(c)
+
+			assert.Equal(t, tt.status, w.Code)
+		})
+	}
+}
+
+func TestHandlePaymentWebhook(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Repeated Event Is Acknowledged", func(t *testing.T) {
+		mockUsecase := &MockPaymentUsecase{
+			HandleWebhookFunc: func(ctx context.Context, payload []byte, signature string) (bool, error) {
+				assert.Equal(t, `{"id":"evt_1"}`, string(payload))
+				assert.Equal(t, "t=1,v1=abc", signature)
+				return false, nil
+			},
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodPost, "/payments/webhook", bytes.NewBufferString(`{"id":"evt_1"}`))
+		c.Request.Header.Set(PaymentSignatureHeader, "t=1,v1=abc")
+
+		NewPaymentController(mockUsecase).HandlePaymentWebhook(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"message": "Event already received"}`, w.Body.String())
+	})
+
+	t.Run("Invalid Signature", func(t *testing.T) {
+		mockUsecase := &MockPaymentUsecase{
+			HandleWebhookFunc: func(ctx context.Context, payload []byte, signature string) (bool, error) {
+				return false, fmt.Errorf("%w: bad signature", usecase.ErrInvalidWebhook)
+			},
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodPost, "/payments/webhook", bytes.NewBufferString(`{}`))
+
+		NewPaymentController(mockUsecase).HandlePaymentWebhook(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
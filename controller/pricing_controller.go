@@ -0,0 +1,341 @@
+package controller
+
+import (
+	"errors"
+	"go-api/dto"
+	"go-api/model"
+	"go-api/usecase"
+	"io"
+	"math/big"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PricingController handles HTTP requests for price lists and exchange rates
+type PricingController struct {
+	pricingUsecase usecase.PricingUsecase
+}
+
+// NewPricingController creates a new PricingController
+func NewPricingController(usecase usecase.PricingUsecase) *PricingController {
+	return &PricingController{
+		pricingUsecase: usecase,
+	}
+}
+
+// GetPriceLists godoc
+// @Summary List price lists
+// @Description Get every price list with its currency and market
+// @Tags pricing
+// @Accept json
+// @Produce json
+// @Success 200 {array} dto.PriceListResponse "Price lists"
+// @Failure 500 {object} model.Response "Internal server error"
+// @Router /price-lists [get]
+func (pc *PricingController) GetPriceLists(ctx *gin.Context) {
+	lists, err := pc.pricingUsecase.GetPriceLists()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	responses := make([]dto.PriceListResponse, 0, len(lists))
+	for _, list := range lists {
+		responses = append(responses, toPriceListResponse(list))
+	}
+	ctx.JSON(http.StatusOK, responses)
+}
+
+// CreatePriceList godoc
+// @Summary Create a price list
+// @Description Create a list of explicit product prices for a currency, optionally scoped to a market
+// @Tags pricing
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param priceList body dto.CreatePriceListRequest true "Price list information"
+// @Success 201 {object} dto.PriceListResponse "Price list created successfully"
+// @Failure 400 {object} model.Response "Bad request - Invalid input data"
+// @Failure 401 {object} model.Response "Missing or invalid token"
+// @Failure 403 {object} model.Response "Admin role required"
+// @Failure 409 {object} model.Response "Code already used"
+// @Failure 500 {object} model.Response "Internal server error"
+// @Router /price-list [post]
+func (pc *PricingController) CreatePriceList(ctx *gin.Context) {
+	var req dto.CreatePriceListRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	list, err := pc.pricingUsecase.CreatePriceList(model.PriceList{
+		Code:     req.Code,
+		Name:     req.Name,
+		Currency: req.Currency,
+		Market:   req.Market,
+	})
+	if err != nil {
+		ctx.JSON(pricingErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, toPriceListResponse(list))
+}
+
+// GetPriceListItems godoc
+// @Summary List the prices of a price list
+// @Description Get the explicit product prices of a price list
+// @Tags pricing
+// @Accept json
+// @Produce json
+// @Param priceListId path int true "Price list ID" minimum(1)
+// @Success 200 {array} dto.PriceListItemResponse "Price list items"
+// @Failure 400 {object} model.Response "Bad request - Invalid ID format"
+// @Failure 404 {object} model.Response "Price list not found"
+// @Failure 500 {object} model.Response "Internal server error"
+// @Router /price-lists/{priceListId}/items [get]
+func (pc *PricingController) GetPriceListItems(ctx *gin.Context) {
+	priceListId, err := strconv.Atoi(ctx.Param("priceListId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid price list ID"})
+		return
+	}
+
+	items, err := pc.pricingUsecase.GetPriceListItems(priceListId)
+	if err != nil {
+		ctx.JSON(pricingErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	responses := make([]dto.PriceListItemResponse, 0, len(items))
+	for _, item := range items {
+		responses = append(responses, toPriceListItemResponse(item))
+	}
+	ctx.JSON(http.StatusOK, responses)
+}
+
+// SetPriceListItem godoc
+// @Summary Set the price of a product in a price list
+// @Description Insert or replace the explicit price of a product, in the currency of the list
+// @Tags pricing
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param priceListId path int true "Price list ID" minimum(1)
+// @Param productId path int true "Product ID" minimum(1)
+// @Param item body dto.SetPriceListItemRequest true "Price"
+// @Success 200 {object} dto.PriceListItemResponse "Price saved successfully"
+// @Failure 400 {object} model.Response "Bad request - Invalid input data"
+// @Failure 401 {object} model.Response "Missing or invalid token"
+// @Failure 403 {object} model.Response "Admin role required"
+// @Failure 404 {object} model.Response "Price list or product not found"
+// @Failure 500 {object} model.Response "Internal server error"
+// @Router /price-lists/{priceListId}/items/{productId} [put]
+func (pc *PricingController) SetPriceListItem(ctx *gin.Context) {
+	priceListId, productId, ok := priceListItemParams(ctx)
+	if !ok {
+		return
+	}
+
+	var req dto.SetPriceListItemRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	item, err := pc.pricingUsecase.SetPriceListItem(priceListId, productId, req.Price.String())
+	if err != nil {
+		ctx.JSON(pricingErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, toPriceListItemResponse(item))
+}
+
+// DeletePriceListItem godoc
+// @Summary Remove the price of a product from a price list
+// @Description The product falls back to exchange-rate conversion in the currency of the list
+// @Tags pricing
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param priceListId path int true "Price list ID" minimum(1)
+// @Param productId path int true "Product ID" minimum(1)
+// @Success 204 "Price removed successfully"
+// @Failure 400 {object} model.Response "Bad request - Invalid ID format"
+// @Failure 401 {object} model.Response "Missing or invalid token"
+// @Failure 403 {object} model.Response "Admin role required"
+// @Failure 404 {object} model.Response "Price list not found"
+// @Failure 500 {object} model.Response "Internal server error"
+// @Router /price-lists/{priceListId}/items/{productId} [delete]
+func (pc *PricingController) DeletePriceListItem(ctx *gin.Context) {
+	priceListId, productId, ok := priceListItemParams(ctx)
+	if !ok {
+		return
+	}
+
+	if err := pc.pricingUsecase.DeletePriceListItem(priceListId, productId); err != nil {
+		ctx.JSON(pricingErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// GetExchangeRates godoc
+// @Summary List exchange rates
+// @Description Get the exchange rates with their source and last update time
+// @Tags pricing
+// @Accept json
+// @Produce json
+// @Success 200 {array} dto.ExchangeRateResponse "Exchange rates"
+// @Failure 500 {object} model.Response "Internal server error"
+// @Router /exchange-rates [get]
+func (pc *PricingController) GetExchangeRates(ctx *gin.Context) {
+	rates, err := pc.pricingUsecase.GetExchangeRates()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, toExchangeRateResponses(rates))
+}
+
+// SetExchangeRates godoc
+// @Summary Update exchange rates
+// @Description Insert or replace exchange rates. All rates are saved or none is
+// @Tags pricing
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param rates body dto.SetExchangeRatesRequest true "Exchange rates"
+// @Success 200 {array} dto.ExchangeRateResponse "Saved exchange rates"
+// @Failure 400 {object} model.Response "Bad request - Invalid input data"
+// @Failure 401 {object} model.Response "Missing or invalid token"
+// @Failure 403 {object} model.Response "Admin role required"
+// @Failure 500 {object} model.Response "Internal server error"
+// @Router /exchange-rates [put]
+func (pc *PricingController) SetExchangeRates(ctx *gin.Context) {
+	var req dto.SetExchangeRatesRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	rates := make([]model.ExchangeRate, 0, len(req.Rates))
+	for _, r := range req.Rates {
+		value, ok := new(big.Rat).SetString(r.Rate.String())
+		if !ok {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": usecase.ErrInvalidExchangeRate.Error()})
+			return
+		}
+		rates = append(rates, model.ExchangeRate{Base: r.Base, Quote: r.Quote, Rate: value})
+	}
+
+	saved, err := pc.pricingUsecase.SetExchangeRates(rates)
+	if err != nil {
+		ctx.JSON(pricingErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, toExchangeRateResponses(saved))
+}
+
+// ImportExchangeRates godoc
+// @Summary Import exchange rates from a CSV file
+// @Description Load rates from a CSV with the columns base,quote,rate and an optional RFC 3339 updated_at. The file is sent as the multipart field "file" or as the raw request body
+// @Tags pricing
+// @Accept multipart/form-data,text/csv
+// @Produce json
+// @Security BearerAuth
+// @Param file formData file false "CSV file"
+// @Success 200 {object} dto.ImportExchangeRatesResponse "Rates imported successfully"
+// @Failure 400 {object} model.Response "Bad request - Invalid file"
+// @Failure 401 {object} model.Response "Missing or invalid token"
+// @Failure 403 {object} model.Response "Admin role required"
+// @Failure 500 {object} model.Response "Internal server error"
+// @Router /exchange-rates/import [post]
+func (pc *PricingController) ImportExchangeRates(ctx *gin.Context) {
+	var reader io.Reader = ctx.Request.Body
+	source := "import"
+	if fileHeader, err := ctx.FormFile("file"); err == nil {
+		file, err := fileHeader.Open()
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		defer file.Close()
+		reader = file
+		source = fileHeader.Filename
+	}
+
+	imported, err := pc.pricingUsecase.ImportExchangeRates(reader, source)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.ImportExchangeRatesResponse{Imported: imported})
+}
+
+// --- Helper Functions ---
+
+func priceListItemParams(ctx *gin.Context) (int, int, bool) {
+	priceListId, err := strconv.Atoi(ctx.Param("priceListId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid price list ID"})
+		return 0, 0, false
+	}
+	productId, err := strconv.Atoi(ctx.Param("productId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return 0, 0, false
+	}
+	return priceListId, productId, true
+}
+
+func pricingErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, usecase.ErrPriceListNotFound), errors.Is(err, usecase.ErrProductNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, usecase.ErrPriceListCodeTaken):
+		return http.StatusConflict
+	case errors.Is(err, usecase.ErrInvalidExchangeRate), errors.Is(err, model.ErrUnknownCurrency), errors.Is(err, model.ErrInvalidAmount):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func toPriceListResponse(list model.PriceList) dto.PriceListResponse {
+	return dto.PriceListResponse{
+		ID:       list.ID,
+		Code:     list.Code,
+		Name:     list.Name,
+		Currency: list.Currency,
+		Market:   list.Market,
+	}
+}
+
+func toPriceListItemResponse(item model.PriceListItem) dto.PriceListItemResponse {
+	return dto.PriceListItemResponse{
+		ProductID: item.ProductID,
+		Price:     toMoneyResponse(item.Price),
+	}
+}
+
+func toExchangeRateResponses(rates []model.ExchangeRate) []dto.ExchangeRateResponse {
+	responses := make([]dto.ExchangeRateResponse, 0, len(rates))
+	for _, rate := range rates {
+		responses = append(responses, dto.ExchangeRateResponse{
+			Base:      rate.Base,
+			Quote:     rate.Quote,
+			Rate:      rate.Rate.FloatString(10),
+			Source:    rate.Source,
+			UpdatedAt: rate.UpdatedAt,
+		})
+	}
+	return responses
+}
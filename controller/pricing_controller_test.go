@@ -0,0 +1,160 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"go-api/dto"
+	"go-api/model"
+	"go-api/usecase"
+	"io"
+	"math/big"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreatePriceList(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Code Conflict", func(t *testing.T) {
+		mockUsecase := &MockPricingUsecase{
+			CreatePriceListFunc: func(list model.PriceList) (model.PriceList, error) {
+				return model.PriceList{}, usecase.ErrPriceListCodeTaken
+			},
+		}
+
+		jsonBody, _ := json.Marshal(dto.CreatePriceListRequest{Code: "usd-default", Name: "Dólar", Currency: "USD"})
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodPost, "/price-list", bytes.NewBuffer(jsonBody))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		pricingController := NewPricingController(mockUsecase)
+		pricingController.CreatePriceList(c)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+}
+
+func TestSetPriceListItem(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Success", func(t *testing.T) {
+		mockUsecase := &MockPricingUsecase{
+			SetPriceListItemFunc: func(priceListID, productID int, amount string) (model.PriceListItem, error) {
+				assert.Equal(t, "5.49", amount)
+				return model.PriceListItem{PriceListID: priceListID, ProductID: productID, Price: model.Money{Amount: 549, Currency: "USD"}}, nil
+			},
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Params = gin.Params{{Key: "priceListId", Value: "1"}, {Key: "productId", Value: "2"}}
+		c.Request, _ = http.NewRequest(http.MethodPut, "/price-lists/1/items/2", bytes.NewBufferString(`{"price": 5.49}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		pricingController := NewPricingController(mockUsecase)
+		pricingController.SetPriceListItem(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response dto.PriceListItemResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, dto.MoneyResponse{Amount: "5.49", Currency: "USD"}, response.Price)
+	})
+
+	t.Run("Product Not Found", func(t *testing.T) {
+		mockUsecase := &MockPricingUsecase{
+			SetPriceListItemFunc: func(priceListID, productID int, amount string) (model.PriceListItem, error) {
+				return model.PriceListItem{}, usecase.ErrProductNotFound
+			},
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Params = gin.Params{{Key: "priceListId", Value: "1"}, {Key: "productId", Value: "99"}}
+		c.Request, _ = http.NewRequest(http.MethodPut, "/price-lists/1/items/99", bytes.NewBufferString(`{"price": "5.49"}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		pricingController := NewPricingController(mockUsecase)
+		pricingController.SetPriceListItem(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestSetExchangeRates(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Success", func(t *testing.T) {
+		mockUsecase := &MockPricingUsecase{
+			SetExchangeRatesFunc: func(rates []model.ExchangeRate) ([]model.ExchangeRate, error) {
+				assert.Equal(t, 0, rates[0].Rate.Cmp(big.NewRat(185, 1000)))
+				return rates, nil
+			},
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodPut, "/exchange-rates",
+			bytes.NewBufferString(`{"rates": [{"base": "BRL", "quote": "USD", "rate": "0.185"}]}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		pricingController := NewPricingController(mockUsecase)
+		pricingController.SetExchangeRates(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response []dto.ExchangeRateResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "0.1850000000", response[0].Rate)
+	})
+
+	t.Run("Empty Rates", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodPut, "/exchange-rates", bytes.NewBufferString(`{"rates": []}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		pricingController := NewPricingController(&MockPricingUsecase{})
+		pricingController.SetExchangeRates(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestImportExchangeRates(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Multipart File", func(t *testing.T) {
+		mockUsecase := &MockPricingUsecase{
+			ImportExchangeRatesFunc: func(reader io.Reader, source string) (int, error) {
+				content, _ := io.ReadAll(reader)
+				assert.Equal(t, "BRL,USD,0.185\n", string(content))
+				assert.Equal(t, "rates.csv", source)
+				return 1, nil
+			},
+		}
+
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		part, _ := writer.CreateFormFile("file", "rates.csv")
+		part.Write([]byte("BRL,USD,0.185\n"))
+		writer.Close()
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodPost, "/exchange-rates/import", body)
+		c.Request.Header.Set("Content-Type", writer.FormDataContentType())
+
+		pricingController := NewPricingController(mockUsecase)
+		pricingController.ImportExchangeRates(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"imported": 1}`, w.Body.String())
+	})
+}
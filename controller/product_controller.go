@@ -1,11 +1,15 @@
 package controller
 
 import (
+	"errors"
 	"go-api/dto"
+	"go-api/internal/export"
 	"go-api/model"
 	"go-api/usecase"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -26,17 +30,35 @@ func NewProductController(usecase usecase.ProductUsecase) *ProductController {
 
 // GetProducts godoc
 // @Summary List all products
-// @Description Get a list of all products in the system
+// @Description Get a list of all products in the system, optionally restricted to a category and its descendants
 // @Tags products
 // @Accept json
 // @Produce json
+// @Param category query string false "Category ID or path (e.g. roupas/camisetas)"
+// @Param currency query string false "ISO 4217 currency to present the prices in"
+// @Param market query string false "Market whose price lists are preferred"
+// @Param as_of query string false "RFC 3339 instant to resolve the price history at, defaults to now"
+// @Param updated_since query string false "RFC 3339 instant, only products changed at or after it (incremental sync)"
 // @Success 200 {array} dto.ProductResponse "List of products"
+// @Failure 400 {object} model.Response "Bad request - Unknown currency, invalid as_of or updated_since"
+// @Failure 422 {object} model.Response "No price or exchange rate for the currency"
 // @Failure 500 {object} model.Response "Internal server error"
 // @Router /products [get]
 func (p *ProductController) GetProducts(ctx *gin.Context) {
-	products, err := p.productUsecase.GetProducts()
+	filter, err := productFilterFromQuery(ctx)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	opts, err := priceOptionsFromQuery(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	products, err := p.productUsecase.GetProducts(filter, opts)
+	if err != nil {
+		ctx.JSON(productErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
@@ -48,15 +70,66 @@ func (p *ProductController) GetProducts(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, productResponses)
 }
 
+// ExportProducts godoc
+// @Summary Export products
+// @Description Stream the products as CSV, NDJSON or XLSX, chosen by format or the Accept header (CSV by default). Accepts the listing filters; prices are the ones in effect at as_of, in the product currency. CSV and NDJSON are gzip encoded when the client accepts it
+// @Tags products
+// @Produce text/csv
+// @Produce application/x-ndjson
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param format query string false "csv, ndjson or xlsx, overrides the Accept header"
+// @Param gzip query bool false "Compress the export even without Accept-Encoding: gzip"
+// @Param category query string false "Category ID or path (e.g. roupas/camisetas)"
+// @Param as_of query string false "RFC 3339 instant to resolve the price history at, defaults to now"
+// @Param updated_since query string false "RFC 3339 instant, only products changed at or after it (incremental sync)"
+// @Success 200 {file} file "Export with the columns id, sku, name, price and currency"
+// @Failure 400 {object} model.Response "Bad request - Unknown format, invalid as_of or updated_since, or a currency was requested"
+// @Failure 401 {object} model.Response "Unauthorized"
+// @Failure 403 {object} model.Response "Admin role required, or API key or access token without the scope of the route"
+// @Failure 406 {object} model.Response "None of the accepted media types is supported"
+// @Failure 500 {object} model.Response "Internal server error"
+// @Router /products/export [get]
+func (p *ProductController) ExportProducts(ctx *gin.Context) {
+	filter, err := productFilterFromQuery(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	opts, err := priceOptionsFromQuery(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if opts.Currency != "" || opts.Market != "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "exports use the product currency, currency and market are not supported"})
+		return
+	}
+
+	stream := newExportStream(ctx, "products", []string{"id", "sku", "name", "price", "currency"})
+	if stream == nil {
+		return
+	}
+	stream.Finish(p.productUsecase.ExportProducts(ctx.Request.Context(), filter, opts, func(product model.Product) error {
+		return stream.WriteRow(product.ID, product.SKU, product.Name, export.Number(product.Price.String()), product.Price.Currency)
+	}))
+}
+
 // CreateProduct godoc
 // @Summary Create a new product
 // @Description Create a new product with the provided information
 // @Tags products
 // @Accept json
 // @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
 // @Param product body dto.CreateProductRequest true "Product information"
 // @Success 201 {object} dto.ProductResponse "Product created successfully"
 // @Failure 400 {object} model.Response "Bad request - Invalid input data"
+// @Failure 401 {object} model.Response "Missing or invalid token"
+// @Failure 403 {object} model.Response "Admin role required, or API key or access token without the scope of the route"
+// @Failure 409 {object} model.Response "SKU already used by another product"
 // @Failure 500 {object} model.Response "Internal server error"
 // @Router /product [post]
 func (p *ProductController) CreateProduct(ctx *gin.Context) {
@@ -66,11 +139,15 @@ func (p *ProductController) CreateProduct(ctx *gin.Context) {
 		return
 	}
 
-	product := toProductModel(req)
+	product, err := toProductModel(req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
-	insertedProduct, err := p.productUsecase.CreateProduct(product)
+	insertedProduct, err := p.productUsecase.CreateProduct(ctx.Request.Context(), product)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.JSON(productErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
@@ -84,9 +161,13 @@ func (p *ProductController) CreateProduct(ctx *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param productId path int true "Product ID" minimum(1)
+// @Param currency query string false "ISO 4217 currency to present the price in"
+// @Param market query string false "Market whose price lists are preferred"
+// @Param as_of query string false "RFC 3339 instant to resolve the price history at, defaults to now"
 // @Success 200 {object} dto.ProductResponse "Product found"
-// @Failure 400 {object} model.Response "Bad request - Invalid ID format"
+// @Failure 400 {object} model.Response "Bad request - Invalid ID format, unknown currency or invalid as_of"
 // @Failure 404 {object} model.Response "Product not found"
+// @Failure 422 {object} model.Response "No price or exchange rate for the currency"
 // @Failure 500 {object} model.Response "Internal server error"
 // @Router /products/{productId} [get]
 func (p *ProductController) GetProductById(ctx *gin.Context) {
@@ -108,9 +189,15 @@ func (p *ProductController) GetProductById(ctx *gin.Context) {
 		return
 	}
 
-	product, err := p.productUsecase.GetProductById(productId)
+	opts, err := priceOptionsFromQuery(ctx)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	product, err := p.productUsecase.GetProductById(productId, opts)
+	if err != nil {
+		ctx.JSON(productErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 	if product == nil {
@@ -123,19 +210,345 @@ func (p *ProductController) GetProductById(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, toProductResponse(*product))
 }
 
+// GetProductPrices godoc
+// @Summary Get the price timeline of a product
+// @Description Get every regular and sale price of the product, past and scheduled, with its status
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param productId path int true "Product ID" minimum(1)
+// @Success 200 {array} dto.ProductPriceResponse "Price timeline"
+// @Failure 400 {object} model.Response "Bad request - Invalid ID format"
+// @Failure 404 {object} model.Response "Product not found"
+// @Failure 500 {object} model.Response "Internal server error"
+// @Router /products/{productId}/prices [get]
+func (p *ProductController) GetProductPrices(ctx *gin.Context) {
+	productId, err := strconv.Atoi(ctx.Param("productId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return
+	}
+
+	prices, err := p.productUsecase.GetPriceHistory(productId)
+	if err != nil {
+		ctx.JSON(productErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	responses := make([]dto.ProductPriceResponse, 0, len(prices))
+	for _, price := range prices {
+		responses = append(responses, toProductPriceResponse(price))
+	}
+	ctx.JSON(http.StatusOK, responses)
+}
+
+// ScheduleProductPrice godoc
+// @Summary Schedule a product price change
+// @Description Append a regular price change or a temporary sale price to the product timeline. Past entries are never changed
+// @Tags products
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param productId path int true "Product ID" minimum(1)
+// @Param price body dto.SchedulePriceRequest true "Price change"
+// @Success 201 {object} dto.ProductPriceResponse "Price change scheduled successfully"
+// @Failure 400 {object} model.Response "Bad request - Invalid price or schedule"
+// @Failure 401 {object} model.Response "Missing or invalid token"
+// @Failure 403 {object} model.Response "Admin role required, or API key or access token without the scope of the route"
+// @Failure 404 {object} model.Response "Product not found"
+// @Failure 500 {object} model.Response "Internal server error"
+// @Router /products/{productId}/prices [post]
+func (p *ProductController) ScheduleProductPrice(ctx *gin.Context) {
+	productId, err := strconv.Atoi(ctx.Param("productId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return
+	}
+
+	var req dto.SchedulePriceRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	change := model.PriceChange{
+		Amount:      req.Price.String(),
+		Kind:        req.Kind,
+		EffectiveTo: req.EffectiveTo,
+	}
+	if req.EffectiveFrom != nil {
+		change.EffectiveFrom = *req.EffectiveFrom
+	}
+
+	price, err := p.productUsecase.SchedulePrice(ctx.Request.Context(), productId, change)
+	if err != nil {
+		ctx.JSON(productErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, toProductPriceResponse(price))
+}
+
+// DeleteProduct godoc
+// @Summary Delete a product
+// @Description Move a product to the trash. It disappears from listings and lookups until restored, and can be purged from the trash
+// @Tags products
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param productId path int true "Product ID" minimum(1)
+// @Success 204 "Product moved to the trash"
+// @Failure 400 {object} model.Response "Bad request - Invalid ID format"
+// @Failure 401 {object} model.Response "Unauthorized"
+// @Failure 403 {object} model.Response "Admin role required, or API key or access token without the scope of the route"
+// @Failure 404 {object} model.Response "Product not found"
+// @Failure 500 {object} model.Response "Internal server error"
+// @Router /products/{productId} [delete]
+func (p *ProductController) DeleteProduct(ctx *gin.Context) {
+	productId, err := strconv.Atoi(ctx.Param("productId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return
+	}
+
+	if err := p.productUsecase.DeleteProduct(ctx.Request.Context(), productId); err != nil {
+		ctx.JSON(productErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// GetDeletedProducts godoc
+// @Summary List deleted products
+// @Description Get the products in the trash, most recently deleted first
+// @Tags trash
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} dto.ProductResponse "Products in the trash"
+// @Failure 401 {object} model.Response "Unauthorized"
+// @Failure 403 {object} model.Response "Forbidden"
+// @Failure 500 {object} model.Response "Internal server error"
+// @Router /trash/products [get]
+func (p *ProductController) GetDeletedProducts(ctx *gin.Context) {
+	products, err := p.productUsecase.GetDeletedProducts()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	productResponses := make([]dto.ProductResponse, 0, len(products))
+	for _, product := range products {
+		productResponses = append(productResponses, toProductResponse(product))
+	}
+
+	ctx.JSON(http.StatusOK, productResponses)
+}
+
+// RestoreProduct godoc
+// @Summary Restore a deleted product
+// @Description Take a product out of the trash
+// @Tags trash
+// @Produce json
+// @Security BearerAuth
+// @Param productId path int true "Product ID" minimum(1)
+// @Success 200 {object} dto.ProductResponse "Restored product"
+// @Failure 400 {object} model.Response "Bad request - Invalid ID format"
+// @Failure 401 {object} model.Response "Unauthorized"
+// @Failure 403 {object} model.Response "Forbidden"
+// @Failure 404 {object} model.Response "Product not in the trash"
+// @Failure 409 {object} model.Response "SKU taken by another product in the meantime"
+// @Failure 500 {object} model.Response "Internal server error"
+// @Router /trash/products/{productId}/restore [post]
+func (p *ProductController) RestoreProduct(ctx *gin.Context) {
+	productId, err := strconv.Atoi(ctx.Param("productId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return
+	}
+
+	product, err := p.productUsecase.RestoreProduct(ctx.Request.Context(), productId)
+	if err != nil {
+		ctx.JSON(productErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, toProductResponse(*product))
+}
+
+// PurgeProduct godoc
+// @Summary Purge a deleted product
+// @Description Permanently delete a product in the trash with its prices, variants, images and category assignments
+// @Tags trash
+// @Produce json
+// @Security BearerAuth
+// @Param productId path int true "Product ID" minimum(1)
+// @Success 204 "Product purged"
+// @Failure 400 {object} model.Response "Bad request - Invalid ID format"
+// @Failure 401 {object} model.Response "Unauthorized"
+// @Failure 403 {object} model.Response "Forbidden"
+// @Failure 404 {object} model.Response "Product not in the trash"
+// @Failure 500 {object} model.Response "Internal server error"
+// @Router /trash/products/{productId} [delete]
+func (p *ProductController) PurgeProduct(ctx *gin.Context) {
+	productId, err := strconv.Atoi(ctx.Param("productId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return
+	}
+
+	if err := p.productUsecase.PurgeProduct(ctx.Request.Context(), productId); err != nil {
+		ctx.JSON(productErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
 // --- Helper Functions ---
 
-func toProductModel(req dto.CreateProductRequest) model.Product {
+func toProductModel(req dto.CreateProductRequest) (model.Product, error) {
+	currency := req.Currency
+	if currency == "" {
+		currency = model.DefaultCurrency
+	}
+	price, err := model.ParseMoney(req.Price.String(), currency)
+	if err != nil {
+		return model.Product{}, err
+	}
+	if price.IsNegative() {
+		return model.Product{}, errors.New("price must not be negative")
+	}
+
 	return model.Product{
 		Name:  req.Name,
-		Price: req.Price,
+		SKU:   req.SKU,
+		Price: price,
+	}, nil
+}
+
+// productFilterFromQuery reads the listing filters, category being an ID or a path
+func productFilterFromQuery(ctx *gin.Context) (model.ProductFilter, error) {
+	var filter model.ProductFilter
+	if category := ctx.Query("category"); category != "" {
+		if categoryId, err := strconv.Atoi(category); err == nil {
+			filter.CategoryID = categoryId
+		} else {
+			filter.CategoryPath = category
+		}
+	}
+	updatedSince, err := updatedSinceFromQuery(ctx)
+	if err != nil {
+		return model.ProductFilter{}, err
+	}
+	filter.UpdatedSince = updatedSince
+	return filter, nil
+}
+
+// updatedSinceFromQuery reads the updated_since parameter of the listings,
+// zero when absent
+func updatedSinceFromQuery(ctx *gin.Context) (time.Time, error) {
+	updatedSince := ctx.Query("updated_since")
+	if updatedSince == "" {
+		return time.Time{}, nil
+	}
+	parsed, err := time.Parse(time.RFC3339, updatedSince)
+	if err != nil {
+		return time.Time{}, errors.New("updated_since must be an RFC 3339 timestamp")
+	}
+	return parsed, nil
+}
+
+// priceOptionsFromQuery reads the currency, market and as_of query parameters
+func priceOptionsFromQuery(ctx *gin.Context) (model.PriceOptions, error) {
+	opts := model.PriceOptions{
+		Currency: strings.ToUpper(ctx.Query("currency")),
+		Market:   ctx.Query("market"),
+	}
+	if opts.Currency != "" {
+		if _, err := model.CurrencyExponent(opts.Currency); err != nil {
+			return model.PriceOptions{}, err
+		}
+	}
+	if asOf := ctx.Query("as_of"); asOf != "" {
+		parsed, err := time.Parse(time.RFC3339, asOf)
+		if err != nil {
+			return model.PriceOptions{}, errors.New("as_of must be an RFC 3339 timestamp")
+		}
+		opts.AsOf = parsed
+	}
+	return opts, nil
+}
+
+func productErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, usecase.ErrProductNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, usecase.ErrInvalidPriceSchedule), errors.Is(err, usecase.ErrPriceChangeInPast),
+		errors.Is(err, model.ErrInvalidAmount):
+		return http.StatusBadRequest
+	case errors.Is(err, usecase.ErrExchangeRateNotFound):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, usecase.ErrProductSKUTaken):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func toProductPriceResponse(price model.ProductPrice) dto.ProductPriceResponse {
+	return dto.ProductPriceResponse{
+		ID:            price.ID,
+		Price:         toMoneyResponse(price.Price),
+		Kind:          price.Kind,
+		EffectiveFrom: price.EffectiveFrom,
+		EffectiveTo:   price.EffectiveTo,
+		Status:        price.Status,
+		CreatedAt:     price.CreatedAt,
 	}
 }
 
 func toProductResponse(product model.Product) dto.ProductResponse {
-	return dto.ProductResponse{
-		ID:    product.ID,
-		Name:  product.Name,
-		Price: product.Price,
+	response := dto.ProductResponse{
+		ID:          product.ID,
+		Name:        product.Name,
+		SKU:         product.SKU,
+		Price:       toMoneyResponse(product.Price),
+		TaxCategory: product.TaxCategory,
+		CreatedAt:   product.CreatedAt,
+		UpdatedAt:   product.UpdatedAt,
+		CreatedBy:   product.CreatedBy,
+		UpdatedBy:   product.UpdatedBy,
+		DeletedAt:   product.DeletedAt,
+	}
+	if conversion := product.Conversion; conversion != nil {
+		response.Conversion = &dto.PriceConversionResponse{
+			Source:        conversion.Source,
+			PriceList:     conversion.PriceListCode,
+			OriginalPrice: toMoneyResponse(conversion.Original),
+			RateTimestamp: conversion.RateTimestamp,
+			Rounding:      string(conversion.Rounding),
+		}
+		if conversion.Rate != nil {
+			response.Conversion.Rate = conversion.Rate.FloatString(10)
+		}
+	}
+	if len(product.Variants) > 0 {
+		for _, option := range product.Options {
+			response.Options = append(response.Options, dto.ProductOptionResponse{Name: option.Name, Values: option.Values})
+		}
+		response.Variants = toVariantResponses(product.Variants)
+	}
+	if len(product.Images) > 0 {
+		response.Images = toProductImageResponses(product.Images)
+	}
+	return response
+}
+
+func toMoneyResponse(money model.Money) dto.MoneyResponse {
+	return dto.MoneyResponse{
+		Amount:   money.String(),
+		Currency: money.Currency,
 	}
 }
@@ -2,13 +2,20 @@ package controller
 
 import (
 	"bytes"
+	"compress/gzip"
+	"context"
 	"encoding/json"
 	"errors"
 	"go-api/dto"
+	"go-api/internal/export"
 	"go-api/model"
+	"go-api/usecase"
+	"io"
+	"math/big"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
@@ -21,10 +28,10 @@ func TestGetProducts(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		// Mock Usecase
 		mockUsecase := &MockProductUsecase{
-			GetProductsFunc: func() ([]model.Product, error) {
+			GetProductsFunc: func(filter model.ProductFilter, opts model.PriceOptions) ([]model.Product, error) {
 				return []model.Product{
-					{ID: 1, Name: "Product 1", Price: 10.0},
-					{ID: 2, Name: "Product 2", Price: 20.0},
+					{ID: 1, Name: "Product 1", Price: model.Money{Amount: 1000, Currency: "BRL"}},
+					{ID: 2, Name: "Product 2", Price: model.Money{Amount: 2000, Currency: "BRL"}},
 				}, nil
 			},
 		}
@@ -46,13 +53,13 @@ func TestGetProducts(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Len(t, response, 2)
 		assert.Equal(t, "Product 1", response[0].Name)
-		assert.Equal(t, 10.0, response[0].Price)
+		assert.Equal(t, dto.MoneyResponse{Amount: "10.00", Currency: "BRL"}, response[0].Price)
 	})
 
 	t.Run("Error", func(t *testing.T) {
 		// Mock Usecase
 		mockUsecase := &MockProductUsecase{
-			GetProductsFunc: func() ([]model.Product, error) {
+			GetProductsFunc: func(filter model.ProductFilter, opts model.PriceOptions) ([]model.Product, error) {
 				return nil, errors.New("error getting products")
 			},
 		}
@@ -70,6 +77,121 @@ func TestGetProducts(t *testing.T) {
 		// Assertions
 		assert.Equal(t, http.StatusInternalServerError, w.Code)
 	})
+	t.Run("Category Filter", func(t *testing.T) {
+		var received model.ProductFilter
+		mockUsecase := &MockProductUsecase{
+			GetProductsFunc: func(filter model.ProductFilter, opts model.PriceOptions) ([]model.Product, error) {
+				received = filter
+				return nil, nil
+			},
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		req, _ := http.NewRequest(http.MethodGet, "/products?category=roupas/camisetas", nil)
+		c.Request = req
+
+		productController := NewProductController(mockUsecase)
+		productController.GetProducts(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "roupas/camisetas", received.CategoryPath)
+		assert.Equal(t, 0, received.CategoryID)
+	})
+
+	t.Run("Updated Since", func(t *testing.T) {
+		var received model.ProductFilter
+		mockUsecase := &MockProductUsecase{
+			GetProductsFunc: func(filter model.ProductFilter, opts model.PriceOptions) ([]model.Product, error) {
+				received = filter
+				return nil, nil
+			},
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodGet, "/products?updated_since=2026-03-01T09:00:00-03:00", nil)
+
+		NewProductController(mockUsecase).GetProducts(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.True(t, time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC).Equal(received.UpdatedSince))
+	})
+
+	t.Run("Invalid Updated Since", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodGet, "/products?updated_since=2026-03-01", nil)
+
+		NewProductController(&MockProductUsecase{}).GetProducts(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Currency Conversion", func(t *testing.T) {
+		updatedAt := time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+		mockUsecase := &MockProductUsecase{
+			GetProductsFunc: func(filter model.ProductFilter, opts model.PriceOptions) ([]model.Product, error) {
+				assert.Equal(t, model.PriceOptions{Currency: "USD", Market: "US"}, opts)
+				return []model.Product{{
+					ID:    1,
+					Name:  "Product 1",
+					Price: model.Money{Amount: 185, Currency: "USD"},
+					Conversion: &model.PriceConversion{
+						Source:        model.PriceSourceExchangeRate,
+						Original:      model.Money{Amount: 1000, Currency: "BRL"},
+						Rate:          big.NewRat(185, 1000),
+						RateTimestamp: &updatedAt,
+						Rounding:      model.RoundHalfUp,
+					},
+				}}, nil
+			},
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodGet, "/products?currency=usd&market=US", nil)
+
+		productController := NewProductController(mockUsecase)
+		productController.GetProducts(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response []dto.ProductResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, dto.MoneyResponse{Amount: "1.85", Currency: "USD"}, response[0].Price)
+		assert.Equal(t, "0.1850000000", response[0].Conversion.Rate)
+		assert.Equal(t, dto.MoneyResponse{Amount: "10.00", Currency: "BRL"}, response[0].Conversion.OriginalPrice)
+		assert.Equal(t, "half_up", response[0].Conversion.Rounding)
+	})
+
+	t.Run("Unknown Currency", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodGet, "/products?currency=XYZ", nil)
+
+		productController := NewProductController(&MockProductUsecase{})
+		productController.GetProducts(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Missing Exchange Rate", func(t *testing.T) {
+		mockUsecase := &MockProductUsecase{
+			GetProductsFunc: func(filter model.ProductFilter, opts model.PriceOptions) ([]model.Product, error) {
+				return nil, usecase.ErrExchangeRateNotFound
+			},
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodGet, "/products?currency=JPY", nil)
+
+		productController := NewProductController(mockUsecase)
+		productController.GetProducts(c)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	})
 }
 
 func TestCreateProduct(t *testing.T) {
@@ -78,7 +200,7 @@ func TestCreateProduct(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		// Mock Usecase
 		mockUsecase := &MockProductUsecase{
-			CreateProductFunc: func(product model.Product) (model.Product, error) {
+			CreateProductFunc: func(ctx context.Context, product model.Product) (model.Product, error) {
 				return model.Product{ID: 1, Name: product.Name, Price: product.Price}, nil
 			},
 		}
@@ -86,7 +208,7 @@ func TestCreateProduct(t *testing.T) {
 		// Request body
 		reqBody := dto.CreateProductRequest{
 			Name:  "Test Product",
-			Price: 25.50,
+			Price: "25.50",
 		}
 		jsonBody, _ := json.Marshal(reqBody)
 
@@ -107,7 +229,7 @@ func TestCreateProduct(t *testing.T) {
 		err := json.Unmarshal(w.Body.Bytes(), &response)
 		assert.NoError(t, err)
 		assert.Equal(t, "Test Product", response.Name)
-		assert.Equal(t, 25.50, response.Price)
+		assert.Equal(t, dto.MoneyResponse{Amount: "25.50", Currency: "BRL"}, response.Price)
 		assert.Equal(t, 1, response.ID)
 	})
 
@@ -129,16 +251,78 @@ func TestCreateProduct(t *testing.T) {
 		assert.Equal(t, http.StatusBadRequest, w.Code)
 	})
 
+	t.Run("Numeric Price Keeps Precision", func(t *testing.T) {
+		var received model.Product
+		mockUsecase := &MockProductUsecase{
+			CreateProductFunc: func(ctx context.Context, product model.Product) (model.Product, error) {
+				received = product
+				product.ID = 1
+				product.CreatedAt = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+				product.UpdatedAt = product.CreatedAt
+				return product, nil
+			},
+		}
+
+		jsonBody := []byte(`{"name": "Test", "price": 0.3, "currency": "usd"}`)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		req, _ := http.NewRequest(http.MethodPost, "/product", bytes.NewBuffer(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+		c.Request = req
+
+		productController := NewProductController(mockUsecase)
+		productController.CreateProduct(c)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Equal(t, model.Money{Amount: 30, Currency: "USD"}, received.Price)
+		assert.JSONEq(t, `{"id": 1, "name": "Test", "price": {"amount": "0.30", "currency": "USD"}, "created_at": "2026-03-01T12:00:00Z", "updated_at": "2026-03-01T12:00:00Z"}`, w.Body.String())
+	})
+
+	t.Run("Negative Price", func(t *testing.T) {
+		mockUsecase := &MockProductUsecase{}
+
+		jsonBody := []byte(`{"name": "Test", "price": "-1.00"}`)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		req, _ := http.NewRequest(http.MethodPost, "/product", bytes.NewBuffer(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+		c.Request = req
+
+		productController := NewProductController(mockUsecase)
+		productController.CreateProduct(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Unknown Currency", func(t *testing.T) {
+		mockUsecase := &MockProductUsecase{}
+
+		jsonBody := []byte(`{"name": "Test", "price": "1.00", "currency": "XYZ"}`)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		req, _ := http.NewRequest(http.MethodPost, "/product", bytes.NewBuffer(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+		c.Request = req
+
+		productController := NewProductController(mockUsecase)
+		productController.CreateProduct(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
 	t.Run("Usecase Error", func(t *testing.T) {
 		mockUsecase := &MockProductUsecase{
-			CreateProductFunc: func(product model.Product) (model.Product, error) {
+			CreateProductFunc: func(ctx context.Context, product model.Product) (model.Product, error) {
 				return model.Product{}, errors.New("database error")
 			},
 		}
 
 		reqBody := dto.CreateProductRequest{
 			Name:  "Test Product",
-			Price: 25.50,
+			Price: "25.50",
 		}
 		jsonBody, _ := json.Marshal(reqBody)
 
@@ -160,8 +344,8 @@ func TestGetProductById(t *testing.T) {
 
 	t.Run("Success", func(t *testing.T) {
 		mockUsecase := &MockProductUsecase{
-			GetProductByIdFunc: func(id_product int) (*model.Product, error) {
-				return &model.Product{ID: 1, Name: "Test Product", Price: 25.50}, nil
+			GetProductByIdFunc: func(id_product int, opts model.PriceOptions) (*model.Product, error) {
+				return &model.Product{ID: 1, Name: "Test Product", Price: model.Money{Amount: 2550, Currency: "BRL"}}, nil
 			},
 		}
 
@@ -179,7 +363,7 @@ func TestGetProductById(t *testing.T) {
 		err := json.Unmarshal(w.Body.Bytes(), &response)
 		assert.NoError(t, err)
 		assert.Equal(t, "Test Product", response.Name)
-		assert.Equal(t, 25.50, response.Price)
+		assert.Equal(t, dto.MoneyResponse{Amount: "25.50", Currency: "BRL"}, response.Price)
 	})
 
 	t.Run("Invalid ID", func(t *testing.T) {
@@ -199,7 +383,7 @@ func TestGetProductById(t *testing.T) {
 
 	t.Run("Product Not Found", func(t *testing.T) {
 		mockUsecase := &MockProductUsecase{
-			GetProductByIdFunc: func(id_product int) (*model.Product, error) {
+			GetProductByIdFunc: func(id_product int, opts model.PriceOptions) (*model.Product, error) {
 				return nil, nil // Product not found
 			},
 		}
@@ -231,3 +415,329 @@ func TestGetProductById(t *testing.T) {
 		assert.Equal(t, http.StatusBadRequest, w.Code)
 	})
 }
+
+func TestGetProductAsOf(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Parses As Of", func(t *testing.T) {
+		mockUsecase := &MockProductUsecase{
+			GetProductByIdFunc: func(id_product int, opts model.PriceOptions) (*model.Product, error) {
+				assert.Equal(t, time.Date(2025, 12, 24, 0, 0, 0, 0, time.UTC), opts.AsOf)
+				return &model.Product{ID: id_product, Name: "Product 1", Price: model.Money{Amount: 1990, Currency: "BRL"}}, nil
+			},
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Params = gin.Params{{Key: "productId", Value: "1"}}
+		c.Request, _ = http.NewRequest(http.MethodGet, "/products/1?as_of=2025-12-24T00:00:00Z", nil)
+
+		productController := NewProductController(mockUsecase)
+		productController.GetProductById(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("Invalid As Of", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodGet, "/products?as_of=yesterday", nil)
+
+		productController := NewProductController(&MockProductUsecase{})
+		productController.GetProducts(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestGetProductPrices(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Success", func(t *testing.T) {
+		from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+		mockUsecase := &MockProductUsecase{
+			GetPriceHistoryFunc: func(id_product int) ([]model.ProductPrice, error) {
+				return []model.ProductPrice{
+					{ID: 1, Price: model.Money{Amount: 2999, Currency: "BRL"}, Kind: model.PriceKindRegular, EffectiveFrom: from, Status: model.PriceStatusActive},
+				}, nil
+			},
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Params = gin.Params{{Key: "productId", Value: "1"}}
+		c.Request, _ = http.NewRequest(http.MethodGet, "/products/1/prices", nil)
+
+		productController := NewProductController(mockUsecase)
+		productController.GetProductPrices(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response []dto.ProductPriceResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Len(t, response, 1)
+		assert.Equal(t, "active", response[0].Status)
+		assert.Equal(t, dto.MoneyResponse{Amount: "29.99", Currency: "BRL"}, response[0].Price)
+	})
+
+	t.Run("Product Not Found", func(t *testing.T) {
+		mockUsecase := &MockProductUsecase{
+			GetPriceHistoryFunc: func(id_product int) ([]model.ProductPrice, error) {
+				return nil, usecase.ErrProductNotFound
+			},
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Params = gin.Params{{Key: "productId", Value: "99"}}
+		c.Request, _ = http.NewRequest(http.MethodGet, "/products/99/prices", nil)
+
+		productController := NewProductController(mockUsecase)
+		productController.GetProductPrices(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestScheduleProductPrice(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Success", func(t *testing.T) {
+		mockUsecase := &MockProductUsecase{
+			SchedulePriceFunc: func(ctx context.Context, id_product int, change model.PriceChange) (model.ProductPrice, error) {
+				assert.Equal(t, "19.90", change.Amount)
+				assert.Equal(t, model.PriceKindSale, change.Kind)
+				assert.Equal(t, time.Date(2026, 11, 27, 0, 0, 0, 0, time.UTC), change.EffectiveFrom)
+				return model.ProductPrice{ID: 2, Price: model.Money{Amount: 1990, Currency: "BRL"}, Kind: change.Kind,
+					EffectiveFrom: change.EffectiveFrom, EffectiveTo: change.EffectiveTo, Status: model.PriceStatusScheduled}, nil
+			},
+		}
+
+		body := `{"price": "19.90", "kind": "sale", "effective_from": "2026-11-27T00:00:00Z", "effective_to": "2026-11-30T00:00:00Z"}`
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Params = gin.Params{{Key: "productId", Value: "1"}}
+		c.Request, _ = http.NewRequest(http.MethodPost, "/products/1/prices", bytes.NewBufferString(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		productController := NewProductController(mockUsecase)
+		productController.ScheduleProductPrice(c)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		var response dto.ProductPriceResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "scheduled", response.Status)
+	})
+
+	t.Run("Invalid Schedule", func(t *testing.T) {
+		mockUsecase := &MockProductUsecase{
+			SchedulePriceFunc: func(ctx context.Context, id_product int, change model.PriceChange) (model.ProductPrice, error) {
+				return model.ProductPrice{}, usecase.ErrPriceChangeInPast
+			},
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Params = gin.Params{{Key: "productId", Value: "1"}}
+		c.Request, _ = http.NewRequest(http.MethodPost, "/products/1/prices", bytes.NewBufferString(`{"price": "19.90"}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		productController := NewProductController(mockUsecase)
+		productController.ScheduleProductPrice(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Unknown Kind", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Params = gin.Params{{Key: "productId", Value: "1"}}
+		c.Request, _ = http.NewRequest(http.MethodPost, "/products/1/prices", bytes.NewBufferString(`{"price": "19.90", "kind": "flash"}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		productController := NewProductController(&MockProductUsecase{})
+		productController.ScheduleProductPrice(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestExportProducts(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	products := []model.Product{
+		{ID: 1, Name: "Camiseta", SKU: "CAM-01", Price: model.Money{Amount: 4990, Currency: "BRL"}},
+		{ID: 2, Name: "=cmd()", Price: model.Money{Amount: 1000, Currency: "USD"}},
+	}
+	mockUsecase := func(filter *model.ProductFilter) *MockProductUsecase {
+		return &MockProductUsecase{
+			ExportProductsFunc: func(ctx context.Context, f model.ProductFilter, opts model.PriceOptions, fn func(model.Product) error) error {
+				if filter != nil {
+					*filter = f
+				}
+				for _, product := range products {
+					if err := fn(product); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		}
+	}
+	exportRequest := func(target string, headers map[string]string) (*httptest.ResponseRecorder, *gin.Context) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodGet, target, nil)
+		for name, value := range headers {
+			c.Request.Header.Set(name, value)
+		}
+		return w, c
+	}
+
+	t.Run("CSV By Default With Filters", func(t *testing.T) {
+		var filter model.ProductFilter
+		w, c := exportRequest("/products/export?category=roupas/camisetas", nil)
+
+		NewProductController(mockUsecase(&filter)).ExportProducts(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "roupas/camisetas", filter.CategoryPath)
+		assert.Equal(t, "text/csv", w.Header().Get("Content-Type"))
+		assert.Contains(t, w.Header().Get("Content-Disposition"), `attachment; filename="products-`)
+		assert.Equal(t, "id,sku,name,price,currency\n1,CAM-01,Camiseta,49.90,BRL\n2,,'=cmd(),10.00,USD\n", w.Body.String())
+	})
+
+	t.Run("NDJSON From Accept With Gzip", func(t *testing.T) {
+		w, c := exportRequest("/products/export", map[string]string{"Accept": "application/x-ndjson", "Accept-Encoding": "gzip, deflate"})
+
+		NewProductController(mockUsecase(nil)).ExportProducts(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/x-ndjson", w.Header().Get("Content-Type"))
+		assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+		reader, err := gzip.NewReader(w.Body)
+		assert.NoError(t, err)
+		body, err := io.ReadAll(reader)
+		assert.NoError(t, err)
+		assert.Equal(t, `{"id":1,"sku":"CAM-01","name":"Camiseta","price":49.90,"currency":"BRL"}`+"\n"+
+			`{"id":2,"sku":"","name":"=cmd()","price":10.00,"currency":"USD"}`+"\n", string(body))
+	})
+
+	t.Run("XLSX From Format Is Not Gzipped", func(t *testing.T) {
+		w, c := exportRequest("/products/export?format=xlsx&gzip=true", map[string]string{"Accept": "text/csv"})
+
+		NewProductController(mockUsecase(nil)).ExportProducts(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, export.ContentType(export.FormatXLSX), w.Header().Get("Content-Type"))
+		assert.Empty(t, w.Header().Get("Content-Encoding"))
+		assert.Equal(t, "PK", w.Body.String()[:2])
+	})
+
+	t.Run("Empty Export Has The Header", func(t *testing.T) {
+		w, c := exportRequest("/products/export", nil)
+
+		NewProductController(&MockProductUsecase{}).ExportProducts(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "id,sku,name,price,currency\n", w.Body.String())
+	})
+
+	t.Run("Unknown Format", func(t *testing.T) {
+		w, c := exportRequest("/products/export?format=pdf", nil)
+
+		NewProductController(mockUsecase(nil)).ExportProducts(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Not Acceptable", func(t *testing.T) {
+		w, c := exportRequest("/products/export", map[string]string{"Accept": "application/pdf"})
+
+		NewProductController(mockUsecase(nil)).ExportProducts(c)
+
+		assert.Equal(t, http.StatusNotAcceptable, w.Code)
+	})
+
+	t.Run("Currency Is Rejected", func(t *testing.T) {
+		w, c := exportRequest("/products/export?currency=USD", nil)
+
+		NewProductController(mockUsecase(nil)).ExportProducts(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Error Before The First Row", func(t *testing.T) {
+		w, c := exportRequest("/products/export", nil)
+		failing := &MockProductUsecase{
+			ExportProductsFunc: func(ctx context.Context, filter model.ProductFilter, opts model.PriceOptions, fn func(model.Product) error) error {
+				return errors.New("connection failed")
+			},
+		}
+
+		NewProductController(failing).ExportProducts(c)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Contains(t, w.Body.String(), "connection failed")
+	})
+}
+
+func TestProductTrash(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Delete Missing Product", func(t *testing.T) {
+		mockUsecase := &MockProductUsecase{
+			DeleteProductFunc: func(ctx context.Context, id int) error {
+				return usecase.ErrProductNotFound
+			},
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodDelete, "/products/99", nil)
+		c.Params = gin.Params{{Key: "productId", Value: "99"}}
+
+		NewProductController(mockUsecase).DeleteProduct(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("List Deleted Products", func(t *testing.T) {
+		deletedAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+		mockUsecase := &MockProductUsecase{
+			GetDeletedProductsFunc: func() ([]model.Product, error) {
+				return []model.Product{{ID: 1, Name: "Camiseta", Price: model.Money{Amount: 4990, Currency: "BRL"}, DeletedAt: &deletedAt}}, nil
+			},
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodGet, "/trash/products", nil)
+
+		NewProductController(mockUsecase).GetDeletedProducts(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response []dto.ProductResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Len(t, response, 1)
+		assert.Equal(t, deletedAt, *response[0].DeletedAt)
+	})
+
+	t.Run("Restore With The SKU Taken", func(t *testing.T) {
+		mockUsecase := &MockProductUsecase{
+			RestoreProductFunc: func(ctx context.Context, id int) (*model.Product, error) {
+				return nil, usecase.ErrProductSKUTaken
+			},
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodPost, "/trash/products/1/restore", nil)
+		c.Params = gin.Params{{Key: "productId", Value: "1"}}
+
+		NewProductController(mockUsecase).RestoreProduct(c)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+}
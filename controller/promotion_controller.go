@@ -0,0 +1,219 @@
+package controller
+
+import (
+	"errors"
+	"go-api/dto"
+	"go-api/model"
+	"go-api/usecase"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PromotionController handles HTTP requests for discount codes and automatic promotions
+type PromotionController struct {
+	promotionUsecase usecase.PromotionUsecase
+}
+
+// NewPromotionController creates a new PromotionController
+func NewPromotionController(usecase usecase.PromotionUsecase) *PromotionController {
+	return &PromotionController{
+		promotionUsecase: usecase,
+	}
+}
+
+// GetPromotions godoc
+// @Summary List promotions
+// @Description Get every discount code and automatic promotion, newest first
+// @Tags promotions
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} dto.PromotionResponse "Promotions"
+// @Failure 401 {object} model.Response "Missing or invalid token"
+// @Failure 403 {object} model.Response "Admin role required"
+// @Failure 500 {object} model.Response "Internal server error"
+// @Router /promotions [get]
+func (pc *PromotionController) GetPromotions(ctx *gin.Context) {
+	promotions, err := pc.promotionUsecase.GetPromotions()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	responses := make([]dto.PromotionResponse, 0, len(promotions))
+	for _, promotion := range promotions {
+		responses = append(responses, toPromotionResponse(promotion))
+	}
+	ctx.JSON(http.StatusOK, responses)
+}
+
+// CreatePromotion godoc
+// @Summary Create a promotion
+// @Description Create a discount code or, without a code, a promotion applied automatically to every cart and order that qualifies. Stackable promotions combine with each other; otherwise the combination with the largest discount wins
+// @Tags promotions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param promotion body dto.CreatePromotionRequest true "Promotion rules"
+// @Success 201 {object} dto.PromotionResponse "Promotion created successfully"
+// @Failure 400 {object} model.Response "Bad request - Invalid input data"
+// @Failure 401 {object} model.Response "Missing or invalid token"
+// @Failure 403 {object} model.Response "Admin role required"
+// @Failure 404 {object} model.Response "Product or category not found"
+// @Failure 409 {object} model.Response "Code already used"
+// @Failure 500 {object} model.Response "Internal server error"
+// @Router /promotions [post]
+func (pc *PromotionController) CreatePromotion(ctx *gin.Context) {
+	var req dto.CreatePromotionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	promotion, err := pc.promotionUsecase.CreatePromotion(model.PromotionInput{
+		Code:         req.Code,
+		Name:         req.Name,
+		Kind:         req.Kind,
+		Percent:      req.Percent,
+		Amount:       req.Amount.String(),
+		MinTotal:     req.MinTotal.String(),
+		Currency:     req.Currency,
+		ProductIDs:   req.ProductIDs,
+		CategoryIDs:  req.CategoryIDs,
+		UsageLimit:   req.UsageLimit,
+		PerUserLimit: req.PerUserLimit,
+		StartsAt:     req.StartsAt,
+		EndsAt:       req.EndsAt,
+		Stackable:    req.Stackable,
+	})
+	if err != nil {
+		ctx.JSON(promotionErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, toPromotionResponse(*promotion))
+}
+
+// GetPromotion godoc
+// @Summary Get a promotion
+// @Description Get a discount code or automatic promotion with its rules and usage
+// @Tags promotions
+// @Produce json
+// @Security BearerAuth
+// @Param promotionId path int true "Promotion ID" minimum(1)
+// @Success 200 {object} dto.PromotionResponse "Promotion"
+// @Failure 400 {object} model.Response "Bad request - Invalid ID format"
+// @Failure 401 {object} model.Response "Missing or invalid token"
+// @Failure 403 {object} model.Response "Admin role required"
+// @Failure 404 {object} model.Response "Promotion not found"
+// @Failure 500 {object} model.Response "Internal server error"
+// @Router /promotions/{promotionId} [get]
+func (pc *PromotionController) GetPromotion(ctx *gin.Context) {
+	promotionId, err := strconv.Atoi(ctx.Param("promotionId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid promotion ID"})
+		return
+	}
+
+	promotion, err := pc.promotionUsecase.GetPromotion(promotionId)
+	if err != nil {
+		ctx.JSON(promotionErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, toPromotionResponse(*promotion))
+}
+
+// SetPromotionActive godoc
+// @Summary Turn a promotion on or off
+// @Description Inactive promotions are no longer applied; orders already placed keep their discount
+// @Tags promotions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param promotionId path int true "Promotion ID" minimum(1)
+// @Param active body dto.SetPromotionActiveRequest true "New state"
+// @Success 200 {object} dto.PromotionResponse "Promotion after the change"
+// @Failure 400 {object} model.Response "Bad request - Invalid ID format or input data"
+// @Failure 401 {object} model.Response "Missing or invalid token"
+// @Failure 403 {object} model.Response "Admin role required"
+// @Failure 404 {object} model.Response "Promotion not found"
+// @Failure 500 {object} model.Response "Internal server error"
+// @Router /promotions/{promotionId}/active [put]
+func (pc *PromotionController) SetPromotionActive(ctx *gin.Context) {
+	promotionId, err := strconv.Atoi(ctx.Param("promotionId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid promotion ID"})
+		return
+	}
+
+	var req dto.SetPromotionActiveRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	promotion, err := pc.promotionUsecase.SetPromotionActive(promotionId, *req.Active)
+	if err != nil {
+		ctx.JSON(promotionErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, toPromotionResponse(*promotion))
+}
+
+// --- Helper Functions ---
+
+func promotionErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, usecase.ErrPromotionNotFound), errors.Is(err, usecase.ErrProductNotFound),
+		errors.Is(err, usecase.ErrCategoryNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, usecase.ErrPromotionCodeTaken):
+		return http.StatusConflict
+	case errors.Is(err, usecase.ErrInvalidPromotion), errors.Is(err, model.ErrUnknownCurrency),
+		errors.Is(err, model.ErrInvalidAmount):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func toPromotionResponse(promotion model.Promotion) dto.PromotionResponse {
+	response := dto.PromotionResponse{
+		ID:           promotion.ID,
+		Code:         promotion.Code,
+		Name:         promotion.Name,
+		Kind:         promotion.Kind,
+		Percent:      promotion.Percent,
+		ProductIDs:   promotion.ProductIDs,
+		CategoryIDs:  promotion.CategoryIDs,
+		UsageLimit:   promotion.UsageLimit,
+		PerUserLimit: promotion.PerUserLimit,
+		Uses:         promotion.Uses,
+		StartsAt:     promotion.StartsAt,
+		EndsAt:       promotion.EndsAt,
+		Stackable:    promotion.Stackable,
+		Active:       promotion.Active,
+		CreatedAt:    promotion.CreatedAt,
+	}
+	if promotion.Amount != nil {
+		amount := toMoneyResponse(*promotion.Amount)
+		response.Amount = &amount
+	}
+	if promotion.MinTotal != nil {
+		minTotal := toMoneyResponse(*promotion.MinTotal)
+		response.MinTotal = &minTotal
+	}
+	return response
+}
+
+func toAppliedPromotionResponse(applied model.AppliedPromotion) dto.AppliedPromotionResponse {
+	return dto.AppliedPromotionResponse{
+		PromotionID: applied.PromotionID,
+		Code:        applied.Code,
+		Name:        applied.Name,
+		Discount:    toMoneyResponse(applied.Discount),
+	}
+}
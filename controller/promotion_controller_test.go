@@ -0,0 +1,133 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go-api/dto"
+	"go-api/model"
+	"go-api/usecase"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreatePromotion(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Success", func(t *testing.T) {
+		mockUsecase := &MockPromotionUsecase{
+			CreatePromotionFunc: func(input model.PromotionInput) (*model.Promotion, error) {
+				assert.Equal(t, "verao20", input.Code)
+				assert.Equal(t, "20.00", input.Amount)
+				assert.Equal(t, "", input.MinTotal)
+				assert.Equal(t, []int{1}, input.ProductIDs)
+				amount := model.Money{Amount: 2000, Currency: "BRL"}
+				return &model.Promotion{ID: 4, Code: "VERAO20", Name: input.Name, Kind: input.Kind, Amount: &amount,
+					ProductIDs: input.ProductIDs, CategoryIDs: []int{}, Active: true}, nil
+			},
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		body := `{"code": "verao20", "name": "Verão", "kind": "fixed", "amount": "20.00", "currency": "BRL", "product_ids": [1]}`
+		c.Request, _ = http.NewRequest(http.MethodPost, "/promotions", bytes.NewBufferString(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		NewPromotionController(mockUsecase).CreatePromotion(c)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		var response dto.PromotionResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "VERAO20", response.Code)
+		assert.Equal(t, dto.MoneyResponse{Amount: "20.00", Currency: "BRL"}, *response.Amount)
+		assert.Nil(t, response.MinTotal)
+	})
+
+	tests := []struct {
+		name   string
+		body   string
+		err    error
+		status int
+	}{
+		{"Unknown Kind", `{"name": "Verão", "kind": "bogo"}`, nil, http.StatusBadRequest},
+		{"Invalid Rules", `{"name": "Verão", "kind": "percentage", "percent": 200}`, fmt.Errorf("%w: percent must be between 1 and 100", usecase.ErrInvalidPromotion), http.StatusBadRequest},
+		{"Code Taken", `{"code": "VERAO", "name": "Verão", "kind": "percentage", "percent": 10}`, usecase.ErrPromotionCodeTaken, http.StatusConflict},
+		{"Product Not Found", `{"name": "Verão", "kind": "percentage", "percent": 10, "product_ids": [99]}`, fmt.Errorf("%w: 99", usecase.ErrProductNotFound), http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUsecase := &MockPromotionUsecase{
+				CreatePromotionFunc: func(input model.PromotionInput) (*model.Promotion, error) {
+					return nil, tt.err
+				},
+			}
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest(http.MethodPost, "/promotions", bytes.NewBufferString(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			NewPromotionController(mockUsecase).CreatePromotion(c)
+
+			assert.Equal(t, tt.status, w.Code)
+		})
+	}
+}
+
+func TestSetPromotionActive(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Turns Off", func(t *testing.T) {
+		mockUsecase := &MockPromotionUsecase{
+			SetPromotionActiveFunc: func(id int, active bool) (*model.Promotion, error) {
+				assert.Equal(t, 4, id)
+				assert.False(t, active)
+				return &model.Promotion{ID: id, Active: active}, nil
+			},
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodPut, "/promotions/4/active", bytes.NewBufferString(`{"active": false}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Params = gin.Params{{Key: "promotionId", Value: "4"}}
+
+		NewPromotionController(mockUsecase).SetPromotionActive(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("Missing State", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodPut, "/promotions/4/active", bytes.NewBufferString(`{}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Params = gin.Params{{Key: "promotionId", Value: "4"}}
+
+		NewPromotionController(&MockPromotionUsecase{}).SetPromotionActive(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		mockUsecase := &MockPromotionUsecase{
+			SetPromotionActiveFunc: func(id int, active bool) (*model.Promotion, error) {
+				return nil, usecase.ErrPromotionNotFound
+			},
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodPut, "/promotions/99/active", bytes.NewBufferString(`{"active": true}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Params = gin.Params{{Key: "promotionId", Value: "99"}}
+
+		NewPromotionController(mockUsecase).SetPromotionActive(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
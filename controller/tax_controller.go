@@ -0,0 +1,180 @@
+package controller
+
+import (
+	"errors"
+	"go-api/dto"
+	"go-api/internal/tax"
+	"go-api/model"
+	"go-api/usecase"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TaxController handles HTTP requests for tax categories and rate tables
+type TaxController struct {
+	taxUsecase usecase.TaxUsecase
+}
+
+// NewTaxController creates a new TaxController
+func NewTaxController(usecase usecase.TaxUsecase) *TaxController {
+	return &TaxController{
+		taxUsecase: usecase,
+	}
+}
+
+// GetTaxCategories godoc
+// @Summary List tax categories
+// @Description Get the tax categories of the rate table, which products may be assigned to
+// @Tags taxes
+// @Produce json
+// @Success 200 {object} dto.TaxCategoriesResponse "Tax categories"
+// @Router /tax/categories [get]
+func (tc *TaxController) GetTaxCategories(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, dto.TaxCategoriesResponse{
+		Categories: tc.taxUsecase.GetTaxCategories(),
+		Default:    tax.DefaultCategory,
+	})
+}
+
+// GetTaxRates godoc
+// @Summary Get the tax rates of a destination
+// @Description Get the rates in effect at a country, or at a state of it, with the country rates first. Rates are versioned, as_of selects the version in effect at another time
+// @Tags taxes
+// @Produce json
+// @Param country query string true "ISO 3166-1 alpha-2 country code"
+// @Param state query string false "State, for countries that tax by state"
+// @Param as_of query string false "RFC 3339 instant to read the rates at, defaults to now"
+// @Success 200 {object} dto.TaxRegionResponse "Rates in effect"
+// @Failure 400 {object} model.Response "Bad request - Invalid country or as_of"
+// @Failure 404 {object} model.Response "No tax rates for the country"
+// @Failure 500 {object} model.Response "Internal server error"
+// @Router /tax/rates [get]
+func (tc *TaxController) GetTaxRates(ctx *gin.Context) {
+	at := time.Now()
+	if asOf := ctx.Query("as_of"); asOf != "" {
+		var err error
+		if at, err = time.Parse(time.RFC3339, asOf); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "as_of must be an RFC 3339 timestamp"})
+			return
+		}
+	}
+
+	destination := model.TaxDestination{Country: ctx.Query("country"), State: ctx.Query("state")}
+	region, err := tc.taxUsecase.GetTaxRegion(destination, at)
+	if err != nil {
+		ctx.JSON(taxErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, toTaxRegionResponse(*region))
+}
+
+// SetProductTaxCategory godoc
+// @Summary Assign the tax category of a product
+// @Description Assign one of the categories of the rate table to the product; carts and orders not yet placed are taxed with it
+// @Tags taxes
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param productId path int true "Product ID" minimum(1)
+// @Param category body dto.SetTaxCategoryRequest true "Tax category"
+// @Success 200 {object} dto.ProductResponse "Product after the change"
+// @Failure 400 {object} model.Response "Bad request - Invalid ID format or unknown tax category"
+// @Failure 401 {object} model.Response "Missing or invalid token"
+// @Failure 403 {object} model.Response "Admin role required, or API key or access token without the scope of the route"
+// @Failure 404 {object} model.Response "Product not found"
+// @Failure 500 {object} model.Response "Internal server error"
+// @Router /products/{productId}/tax-category [put]
+func (tc *TaxController) SetProductTaxCategory(ctx *gin.Context) {
+	productId, err := strconv.Atoi(ctx.Param("productId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return
+	}
+
+	var req dto.SetTaxCategoryRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	product, err := tc.taxUsecase.SetProductTaxCategory(ctx.Request.Context(), productId, req.TaxCategory)
+	if err != nil {
+		ctx.JSON(taxErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, toProductResponse(*product))
+}
+
+// --- Helper Functions ---
+
+func taxErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, usecase.ErrProductNotFound), errors.Is(err, usecase.ErrTaxRegionNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, usecase.ErrInvalidTaxDestination), errors.Is(err, usecase.ErrInvalidTaxCategory):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func toTaxRegionResponse(region model.TaxRegion) dto.TaxRegionResponse {
+	response := dto.TaxRegionResponse{
+		Destination:      dto.TaxDestinationResponse{Country: region.Destination.Country, State: region.Destination.State},
+		Version:          region.Version,
+		PricesIncludeTax: region.PricesIncludeTax,
+		Rounding:         region.Rounding,
+		Rates:            make([]dto.TaxRateResponse, 0, len(region.Rates)),
+	}
+	for _, rate := range region.Rates {
+		response.Rates = append(response.Rates, dto.TaxRateResponse{
+			Name:          rate.Name,
+			Category:      rate.Category,
+			Percent:       rate.Percent,
+			EffectiveFrom: rate.EffectiveFrom,
+			EffectiveTo:   rate.EffectiveTo,
+		})
+	}
+	return response
+}
+
+func toTaxBreakdownResponse(breakdown model.TaxBreakdown) dto.TaxBreakdownResponse {
+	response := dto.TaxBreakdownResponse{
+		Destination: dto.TaxDestinationResponse{Country: breakdown.Destination.Country, State: breakdown.Destination.State},
+		Included:    breakdown.Included,
+		Rounding:    breakdown.Rounding,
+		Lines:       make([]dto.LineTaxResponse, 0, len(breakdown.Lines)),
+		Taxes:       make([]dto.TaxAmountResponse, 0, len(breakdown.Taxes)),
+		Total:       toMoneyResponse(breakdown.Total),
+	}
+	for _, line := range breakdown.Lines {
+		taxes := make([]dto.TaxAmountResponse, 0, len(line.Taxes))
+		for _, amount := range line.Taxes {
+			taxes = append(taxes, toTaxAmountResponse(amount))
+		}
+		response.Lines = append(response.Lines, dto.LineTaxResponse{
+			Category: line.Category,
+			Net:      toMoneyResponse(line.Net),
+			Tax:      toMoneyResponse(line.Tax),
+			Taxes:    taxes,
+		})
+	}
+	for _, amount := range breakdown.Taxes {
+		response.Taxes = append(response.Taxes, toTaxAmountResponse(amount))
+	}
+	return response
+}
+
+func toTaxAmountResponse(amount model.TaxAmount) dto.TaxAmountResponse {
+	return dto.TaxAmountResponse{
+		Name:    amount.Name,
+		Percent: amount.Percent,
+		Amount:  toMoneyResponse(amount.Amount),
+	}
+}
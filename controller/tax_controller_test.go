@@ -0,0 +1,163 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"go-api/dto"
+	"go-api/model"
+	"go-api/usecase"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetTaxCategories(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockUsecase := &MockTaxUsecase{
+		GetTaxCategoriesFunc: func() []string {
+			return []string{"standard", "reduced", "exempt"}
+		},
+	}
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodGet, "/tax/categories", nil)
+
+	NewTaxController(mockUsecase).GetTaxCategories(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response dto.TaxCategoriesResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, []string{"standard", "reduced", "exempt"}, response.Categories)
+	assert.Equal(t, "standard", response.Default)
+}
+
+func TestGetTaxRates(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Success", func(t *testing.T) {
+		from := time.Date(2024, 3, 20, 3, 0, 0, 0, time.UTC)
+		mockUsecase := &MockTaxUsecase{
+			GetTaxRegionFunc: func(destination model.TaxDestination, at time.Time) (*model.TaxRegion, error) {
+				assert.Equal(t, model.TaxDestination{Country: "BR", State: "RJ"}, destination)
+				assert.True(t, at.Equal(time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)))
+				return &model.TaxRegion{
+					Destination:      destination,
+					Version:          "2026-01-01",
+					PricesIncludeTax: true,
+					Rounding:         "line",
+					Rates:            []model.TaxRate{{Name: "ICMS", Category: "standard", Percent: "22", EffectiveFrom: from}},
+				}, nil
+			},
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodGet, "/tax/rates?country=BR&state=RJ&as_of=2024-04-01T00:00:00Z", nil)
+
+		NewTaxController(mockUsecase).GetTaxRates(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response dto.TaxRegionResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, dto.TaxDestinationResponse{Country: "BR", State: "RJ"}, response.Destination)
+		assert.True(t, response.PricesIncludeTax)
+		assert.Len(t, response.Rates, 1)
+		assert.Equal(t, "22", response.Rates[0].Percent)
+		assert.Nil(t, response.Rates[0].EffectiveTo)
+	})
+
+	tests := []struct {
+		name   string
+		query  string
+		err    error
+		status int
+	}{
+		{"Invalid As Of", "?country=BR&as_of=yesterday", nil, http.StatusBadRequest},
+		{"Invalid Country", "?country=Brasil", usecase.ErrInvalidTaxDestination, http.StatusBadRequest},
+		{"Region Not Found", "?country=JP", fmt.Errorf("%w: JP", usecase.ErrTaxRegionNotFound), http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUsecase := &MockTaxUsecase{
+				GetTaxRegionFunc: func(destination model.TaxDestination, at time.Time) (*model.TaxRegion, error) {
+					return nil, tt.err
+				},
+			}
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest(http.MethodGet, "/tax/rates"+tt.query, nil)
+
+			NewTaxController(mockUsecase).GetTaxRates(c)
+
+			assert.Equal(t, tt.status, w.Code)
+		})
+	}
+}
+
+func TestSetProductTaxCategory(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Success", func(t *testing.T) {
+		mockUsecase := &MockTaxUsecase{
+			SetProductTaxCategoryFunc: func(ctx context.Context, productID int, category string) (*model.Product, error) {
+				assert.Equal(t, 1, productID)
+				assert.Equal(t, "reduced", category)
+				return &model.Product{ID: 1, Name: "Arroz", Price: model.Money{Amount: 2500, Currency: "BRL"}, TaxCategory: category}, nil
+			},
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Params = gin.Params{{Key: "productId", Value: "1"}}
+		c.Request, _ = http.NewRequest(http.MethodPut, "/products/1/tax-category", bytes.NewBufferString(`{"tax_category": "reduced"}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		NewTaxController(mockUsecase).SetProductTaxCategory(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response dto.ProductResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "reduced", response.TaxCategory)
+	})
+
+	tests := []struct {
+		name   string
+		id     string
+		body   string
+		err    error
+		status int
+	}{
+		{"Invalid ID", "abc", `{"tax_category": "reduced"}`, nil, http.StatusBadRequest},
+		{"Missing Category", "1", `{}`, nil, http.StatusBadRequest},
+		{"Unknown Category", "1", `{"tax_category": "luxury"}`, fmt.Errorf("%w: %q", usecase.ErrInvalidTaxCategory, "luxury"), http.StatusBadRequest},
+		{"Product Not Found", "99", `{"tax_category": "reduced"}`, usecase.ErrProductNotFound, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUsecase := &MockTaxUsecase{
+				SetProductTaxCategoryFunc: func(ctx context.Context, productID int, category string) (*model.Product, error) {
+					return nil, tt.err
+				},
+			}
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Params = gin.Params{{Key: "productId", Value: tt.id}}
+			c.Request, _ = http.NewRequest(http.MethodPut, "/products/"+tt.id+"/tax-category", bytes.NewBufferString(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			NewTaxController(mockUsecase).SetProductTaxCategory(c)
+
+			assert.Equal(t, tt.status, w.Code)
+		})
+	}
+}
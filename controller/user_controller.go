@@ -1,14 +1,26 @@
 package controller
 
 import (
+	"errors"
 	"go-api/dto"
+	"go-api/middleware"
+	"go-api/model"
 	"go-api/usecase"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
+// resendVerificationMessage is the answer to every valid resend request,
+// whether or not the email has an account
+const resendVerificationMessage = "If the email belongs to an account waiting for verification, a new link was sent to it"
+
+// signupAcceptedMessage is the answer to every valid sign up when taken
+// emails are hidden
+const signupAcceptedMessage = "Sign up received; check your email to continue"
+
 // UserController handles HTTP requests for users
 type UserController struct {
 	userUsecase usecase.UserUsecase
@@ -23,13 +35,15 @@ func NewUserController(usecase usecase.UserUsecase) *UserController {
 
 // CreateUser godoc
 // @Summary Create a new user
-// @Description Create a new user with the provided information
+// @Description Create a new user with the provided information. When the API hides taken emails, every valid sign up answers 202 with the same message, and the owner of a taken email is told by email instead
 // @Tags users
 // @Accept json
 // @Produce json
 // @Param user body dto.CreateUserRequest true "User information"
 // @Success 201 {object} dto.UserResponse "User created successfully"
-// @Failure 400 {object} model.Response "Bad request - Invalid input data"
+// @Success 202 {object} model.Response "Sign up accepted, when taken emails are hidden"
+// @Failure 400 {object} model.Response "Bad request - Invalid input data, or a password breaking the strength rules"
+// @Failure 409 {object} model.Response "Email in use, or reserved by a deleted user"
 // @Failure 500 {object} model.Response "Internal server error"
 // @Router /user [post]
 func (uc *UserController) CreateUser(ctx *gin.Context) {
@@ -39,9 +53,13 @@ func (uc *UserController) CreateUser(ctx *gin.Context) {
 		return
 	}
 
-	userResponse, err := uc.userUsecase.CreateUser(req)
+	userResponse, err := uc.userUsecase.CreateUser(ctx.Request.Context(), req)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.JSON(userErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	if userResponse == nil {
+		ctx.JSON(http.StatusAccepted, model.Response{Message: signupAcceptedMessage})
 		return
 	}
 
@@ -50,13 +68,16 @@ func (uc *UserController) CreateUser(ctx *gin.Context) {
 
 // GetUserByID godoc
 // @Summary Get user by ID
-// @Description Get a specific user by their ID
+// @Description Get a specific user by their ID. Users see their own account; admins any account
 // @Tags users
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param userId path int true "User ID" minimum(1)
 // @Success 200 {object} dto.UserResponse "User found"
 // @Failure 400 {object} model.Response "Bad request - Invalid ID format"
+// @Failure 401 {object} model.Response "Missing or invalid token"
+// @Failure 403 {object} model.Response "Another user's account, without the admin role"
 // @Failure 404 {object} model.Response "User not found"
 // @Failure 500 {object} model.Response "Internal server error"
 // @Router /users/{userId} [get]
@@ -67,6 +88,10 @@ func (uc *UserController) GetUserByID(ctx *gin.Context) {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
 		return
 	}
+	if !canManageUser(ctx, userId) {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "insufficient permissions"})
+		return
+	}
 
 	userResponse, err := uc.userUsecase.GetUserByID(userId)
 	if err != nil {
@@ -83,15 +108,19 @@ func (uc *UserController) GetUserByID(ctx *gin.Context) {
 
 // UpdateUser godoc
 // @Summary Update a user
-// @Description Update an existing user's information
+// @Description Update an existing user's information. Users update their own account; admins any account
 // @Tags users
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param userId path int true "User ID" minimum(1)
 // @Param user body dto.UpdateUserRequest true "User information"
 // @Success 204 "User updated successfully"
-// @Failure 400 {object} model.Response "Bad request - Invalid input data"
+// @Failure 400 {object} model.Response "Bad request - Invalid input data, or a password breaking the strength rules"
+// @Failure 401 {object} model.Response "Missing or invalid token"
+// @Failure 403 {object} model.Response "Another user's account, without the admin role"
 // @Failure 404 {object} model.Response "User not found"
+// @Failure 409 {object} model.Response "Email in use, or reserved by a deleted user"
 // @Failure 500 {object} model.Response "Internal server error"
 // @Router /users/{userId} [put]
 func (uc *UserController) UpdateUser(ctx *gin.Context) {
@@ -101,6 +130,10 @@ func (uc *UserController) UpdateUser(ctx *gin.Context) {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
 		return
 	}
+	if !canManageUser(ctx, userId) {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "insufficient permissions"})
+		return
+	}
 
 	var req dto.UpdateUserRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
@@ -108,9 +141,9 @@ func (uc *UserController) UpdateUser(ctx *gin.Context) {
 		return
 	}
 
-	err = uc.userUsecase.UpdateUser(userId, req)
+	err = uc.userUsecase.UpdateUser(ctx.Request.Context(), userId, req)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.JSON(userErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
@@ -119,13 +152,17 @@ func (uc *UserController) UpdateUser(ctx *gin.Context) {
 
 // DeleteUser godoc
 // @Summary Delete a user
-// @Description Delete a user by their ID
+// @Description Move a user to the trash. It can no longer log in and disappears from listings until restored, and can be purged from the trash
 // @Tags users
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param userId path int true "User ID" minimum(1)
 // @Success 204 "User deleted successfully"
 // @Failure 400 {object} model.Response "Bad request - Invalid ID format"
+// @Failure 401 {object} model.Response "Missing or invalid token"
+// @Failure 403 {object} model.Response "Admin role, or two-factor authentication for the roles of MFA_REQUIRED_ROLES, required"
+// @Failure 404 {object} model.Response "User not found"
 // @Failure 500 {object} model.Response "Internal server error"
 // @Router /users/{userId} [delete]
 func (uc *UserController) DeleteUser(ctx *gin.Context) {
@@ -136,9 +173,9 @@ func (uc *UserController) DeleteUser(ctx *gin.Context) {
 		return
 	}
 
-	err = uc.userUsecase.DeleteUser(userId)
+	err = uc.userUsecase.DeleteUser(ctx.Request.Context(), userId)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.JSON(userErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
@@ -151,11 +188,22 @@ func (uc *UserController) DeleteUser(ctx *gin.Context) {
 // @Tags users
 // @Accept json
 // @Produce json
+// @Security BearerAuth
+// @Param updated_since query string false "RFC 3339 instant, only users changed at or after it (incremental sync)"
 // @Success 200 {array} dto.UserResponse "List of users"
+// @Failure 400 {object} model.Response "Bad request - Invalid updated_since"
+// @Failure 401 {object} model.Response "Missing or invalid token"
+// @Failure 403 {object} model.Response "Admin role required"
 // @Failure 500 {object} model.Response "Internal server error"
 // @Router /users [get]
 func (uc *UserController) GetUsers(ctx *gin.Context) {
-	users, err := uc.userUsecase.GetUsers()
+	updatedSince, err := updatedSinceFromQuery(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	users, err := uc.userUsecase.GetUsers(model.UserFilter{UpdatedSince: updatedSince})
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -164,16 +212,134 @@ func (uc *UserController) GetUsers(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, users)
 }
 
+// ExportUsers godoc
+// @Summary Export users
+// @Description Stream the users as CSV, NDJSON or XLSX, chosen by format or the Accept header (CSV by default). Accepts the listing filters. Password hashes are never exported. CSV and NDJSON are gzip encoded when the client accepts it
+// @Tags users
+// @Produce text/csv
+// @Produce application/x-ndjson
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Security BearerAuth
+// @Param format query string false "csv, ndjson or xlsx, overrides the Accept header"
+// @Param gzip query bool false "Compress the export even without Accept-Encoding: gzip"
+// @Param updated_since query string false "RFC 3339 instant, only users changed at or after it (incremental sync)"
+// @Success 200 {file} file "Export with the columns id, name, email, role, created_at and updated_at"
+// @Failure 400 {object} model.Response "Bad request - Unknown format or invalid updated_since"
+// @Failure 401 {object} model.Response "Unauthorized"
+// @Failure 403 {object} model.Response "Forbidden"
+// @Failure 406 {object} model.Response "None of the accepted media types is supported"
+// @Failure 500 {object} model.Response "Internal server error"
+// @Router /users/export [get]
+func (uc *UserController) ExportUsers(ctx *gin.Context) {
+	updatedSince, err := updatedSinceFromQuery(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	stream := newExportStream(ctx, "users", []string{"id", "name", "email", "role", "created_at", "updated_at"})
+	if stream == nil {
+		return
+	}
+	stream.Finish(uc.userUsecase.ExportUsers(ctx.Request.Context(), model.UserFilter{UpdatedSince: updatedSince}, func(user dto.UserResponse) error {
+		return stream.WriteRow(user.ID, user.Name, user.Email, user.Role, user.CreatedAt.Format(time.RFC3339), user.UpdatedAt.Format(time.RFC3339))
+	}))
+}
+
+// GetDeletedUsers godoc
+// @Summary List deleted users
+// @Description Get the users in the trash, most recently deleted first
+// @Tags trash
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} dto.UserResponse "Users in the trash"
+// @Failure 401 {object} model.Response "Unauthorized"
+// @Failure 403 {object} model.Response "Forbidden"
+// @Failure 500 {object} model.Response "Internal server error"
+// @Router /trash/users [get]
+func (uc *UserController) GetDeletedUsers(ctx *gin.Context) {
+	users, err := uc.userUsecase.GetDeletedUsers()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, users)
+}
+
+// RestoreUser godoc
+// @Summary Restore a deleted user
+// @Description Take a user out of the trash
+// @Tags trash
+// @Produce json
+// @Security BearerAuth
+// @Param userId path int true "User ID" minimum(1)
+// @Success 200 {object} dto.UserResponse "Restored user"
+// @Failure 400 {object} model.Response "Bad request - Invalid ID format"
+// @Failure 401 {object} model.Response "Unauthorized"
+// @Failure 403 {object} model.Response "Forbidden"
+// @Failure 404 {object} model.Response "User not in the trash"
+// @Failure 409 {object} model.Response "Email taken by another user in the meantime"
+// @Failure 500 {object} model.Response "Internal server error"
+// @Router /trash/users/{userId}/restore [post]
+func (uc *UserController) RestoreUser(ctx *gin.Context) {
+	userId, err := strconv.Atoi(ctx.Param("userId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	userResponse, err := uc.userUsecase.RestoreUser(ctx.Request.Context(), userId)
+	if err != nil {
+		ctx.JSON(userErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, userResponse)
+}
+
+// PurgeUser godoc
+// @Summary Purge a deleted user
+// @Description Permanently delete a user in the trash, releasing its email
+// @Tags trash
+// @Produce json
+// @Security BearerAuth
+// @Param userId path int true "User ID" minimum(1)
+// @Success 204 "User purged"
+// @Failure 400 {object} model.Response "Bad request - Invalid ID format"
+// @Failure 401 {object} model.Response "Unauthorized"
+// @Failure 403 {object} model.Response "Forbidden"
+// @Failure 404 {object} model.Response "User not in the trash"
+// @Failure 500 {object} model.Response "Internal server error"
+// @Router /trash/users/{userId} [delete]
+func (uc *UserController) PurgeUser(ctx *gin.Context) {
+	userId, err := strconv.Atoi(ctx.Param("userId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	if err := uc.userUsecase.PurgeUser(ctx.Request.Context(), userId); err != nil {
+		ctx.JSON(userErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
 // Login godoc
 // @Summary User login
-// @Description Authenticate a user and return a JWT token
+// @Description Authenticate a user and return a JWT token. The anonymous cart of cart_token (or of the X-Cart-Token header) is merged into the user's cart. A user with two-factor authentication gets mfa_required and an mfa_token instead, to send with a code to /auth/mfa/verify. Repeated failures make the account and the IP wait, longer after each failure, and then lock them out for a while
 // @Tags users
 // @Accept json
 // @Produce json
 // @Param credentials body dto.LoginRequest true "User credentials"
+// @Param X-Cart-Token header string false "Token of the anonymous cart to merge"
 // @Success 200 {object} dto.LoginResponse "Login successful"
 // @Failure 400 {object} model.Response "Bad request - Invalid input data"
 // @Failure 401 {object} model.Response "Unauthorized - Invalid credentials"
+// @Failure 403 {object} model.Response "Email not verified, when verification is required"
+// @Failure 429 {object} model.Response "Too many failed logins; the Retry-After header tells when to try again"
 // @Failure 500 {object} model.Response "Internal server error"
 // @Router /login [post]
 func (uc *UserController) Login(ctx *gin.Context) {
@@ -182,16 +348,98 @@ func (uc *UserController) Login(ctx *gin.Context) {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if req.CartToken == "" {
+		req.CartToken = ctx.GetHeader(CartTokenHeader)
+	}
 
-	response, err := uc.userUsecase.Login(req)
+	response, err := uc.userUsecase.Login(ctx.Request.Context(), req)
 	if err != nil {
-		if err.Error() == "invalid credentials" {
-			ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
-			return
+		var throttled *usecase.LoginThrottledError
+		if errors.As(err, &throttled) {
+			setRetryAfter(ctx, throttled.RetryAfter)
 		}
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.JSON(userErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
 	ctx.JSON(http.StatusOK, response)
 }
+
+// VerifyEmail godoc
+// @Summary Verify an email
+// @Description Confirm the email of a verification link. On sign up it marks the email verified; on an email change it replaces the email with the pending one
+// @Tags auth
+// @Produce json
+// @Param token query string true "Token of the verification email"
+// @Success 200 {object} dto.UserResponse "Email verified"
+// @Failure 400 {object} model.Response "Bad request - Invalid, expired or outdated token"
+// @Failure 409 {object} model.Response "The pending email was taken in the meantime"
+// @Failure 500 {object} model.Response "Internal server error"
+// @Router /auth/email/verify [get]
+func (uc *UserController) VerifyEmail(ctx *gin.Context) {
+	user, err := uc.userUsecase.VerifyEmail(ctx.Request.Context(), ctx.Query("token"))
+	if err != nil {
+		ctx.JSON(userErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, user)
+}
+
+// ResendVerification godoc
+// @Summary Ask for a new verification email
+// @Description Email a new verification link to the pending email of the account, or to its email while unverified. The answer is the same whether or not the email has an account
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body dto.ResendVerificationRequest true "Email of the account"
+// @Success 202 {object} model.Response "Request accepted"
+// @Failure 400 {object} model.Response "Bad request - Invalid email"
+// @Failure 429 {object} model.Response "Too many requests from the IP; the Retry-After header tells when to try again"
+// @Failure 500 {object} model.Response "Internal server error"
+// @Router /auth/email/resend [post]
+func (uc *UserController) ResendVerification(ctx *gin.Context) {
+	var req dto.ResendVerificationRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := uc.userUsecase.ResendVerification(ctx.Request.Context(), req.Email); err != nil {
+		var limited *usecase.VerificationRateLimitedError
+		if errors.As(err, &limited) {
+			setRetryAfter(ctx, limited.RetryAfter)
+		}
+		ctx.JSON(userErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, model.Response{Message: resendVerificationMessage})
+}
+
+// --- Helper Functions ---
+
+// canManageUser lets the authenticated user reach their own account, and
+// admins any account
+func canManageUser(ctx *gin.Context, userId int) bool {
+	return userId == ctx.GetInt(middleware.ContextUserID) || ctx.GetString(middleware.ContextRole) == model.RoleAdmin
+}
+
+func userErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, usecase.ErrUserNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, usecase.ErrEmailTaken), errors.Is(err, usecase.ErrEmailReserved):
+		return http.StatusConflict
+	case errors.Is(err, usecase.ErrInvalidCredentials):
+		return http.StatusUnauthorized
+	case errors.Is(err, usecase.ErrVerificationRateLimited), errors.Is(err, usecase.ErrLoginThrottled):
+		return http.StatusTooManyRequests
+	case errors.Is(err, usecase.ErrInvalidVerificationToken), errors.Is(err, usecase.ErrWeakPassword):
+		return http.StatusBadRequest
+	case errors.Is(err, usecase.ErrEmailNotVerified):
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -2,12 +2,19 @@ package controller
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"go-api/dto"
+	"go-api/internal/password"
+	"go-api/middleware"
+	"go-api/model"
+	"go-api/usecase"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
@@ -18,7 +25,7 @@ func TestCreateUser(t *testing.T) {
 
 	t.Run("Success", func(t *testing.T) {
 		mockUsecase := &MockUserUsecase{
-			CreateUserFunc: func(user dto.CreateUserRequest) (*dto.UserResponse, error) {
+			CreateUserFunc: func(ctx context.Context, user dto.CreateUserRequest) (*dto.UserResponse, error) {
 				return &dto.UserResponse{ID: 1, Name: user.Name, Email: user.Email}, nil
 			},
 		}
@@ -47,6 +54,44 @@ func TestCreateUser(t *testing.T) {
 		assert.Equal(t, "leandro@example.com", response.Email)
 		assert.Equal(t, 1, response.ID)
 	})
+
+	t.Run("Hidden Taken Emails", func(t *testing.T) {
+		mockUsecase := &MockUserUsecase{
+			CreateUserFunc: func(ctx context.Context, user dto.CreateUserRequest) (*dto.UserResponse, error) {
+				return nil, nil
+			},
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodPost, "/user", bytes.NewBufferString(`{"name": "Ana", "email": "ana@example.com", "password": "password123"}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		NewUserController(mockUsecase).CreateUser(c)
+
+		assert.Equal(t, http.StatusAccepted, w.Code)
+		var response model.Response
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, signupAcceptedMessage, response.Message)
+	})
+
+	t.Run("Weak Password", func(t *testing.T) {
+		mockUsecase := &MockUserUsecase{
+			CreateUserFunc: func(ctx context.Context, user dto.CreateUserRequest) (*dto.UserResponse, error) {
+				return nil, &password.WeakError{Reason: "it is too common"}
+			},
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodPost, "/user", bytes.NewBufferString(`{"name": "Ana", "email": "ana@example.com", "password": "password123"}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		NewUserController(mockUsecase).CreateUser(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"error": "weak password: it is too common"}`, w.Body.String())
+	})
 }
 
 func TestGetUserByID(t *testing.T) {
@@ -64,6 +109,8 @@ func TestGetUserByID(t *testing.T) {
 		req, _ := http.NewRequest(http.MethodGet, "/users/1", nil)
 		c.Params = gin.Params{{Key: "userId", Value: "1"}}
 		c.Request = req
+		c.Set(middleware.ContextUserID, 1)
+		c.Set(middleware.ContextRole, model.RoleCustomer)
 
 		userController := NewUserController(mockUsecase)
 		userController.GetUserByID(c)
@@ -87,12 +134,44 @@ func TestGetUserByID(t *testing.T) {
 		req, _ := http.NewRequest(http.MethodGet, "/users/1", nil)
 		c.Params = gin.Params{{Key: "userId", Value: "1"}}
 		c.Request = req
+		c.Set(middleware.ContextUserID, 1)
+		c.Set(middleware.ContextRole, model.RoleCustomer)
 
 		userController := NewUserController(mockUsecase)
 		userController.GetUserByID(c)
 
 		assert.Equal(t, http.StatusNotFound, w.Code)
 	})
+
+	tests := []struct {
+		name   string
+		role   string
+		status int
+	}{
+		{"Another User", model.RoleCustomer, http.StatusForbidden},
+		{"Admin", model.RoleAdmin, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUsecase := &MockUserUsecase{
+				GetUserByIDFunc: func(id int) (*dto.UserResponse, error) {
+					return &dto.UserResponse{ID: id, Name: "Ana", Email: "ana@example.com"}, nil
+				},
+			}
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest(http.MethodGet, "/users/2", nil)
+			c.Params = gin.Params{{Key: "userId", Value: "2"}}
+			c.Set(middleware.ContextUserID, 1)
+			c.Set(middleware.ContextRole, tt.role)
+
+			NewUserController(mockUsecase).GetUserByID(c)
+
+			assert.Equal(t, tt.status, w.Code)
+			assert.Equal(t, tt.status == http.StatusOK, strings.Contains(w.Body.String(), "ana@example.com"))
+		})
+	}
 }
 
 func TestUpdateUser(t *testing.T) {
@@ -100,7 +179,7 @@ func TestUpdateUser(t *testing.T) {
 
 	t.Run("Success", func(t *testing.T) {
 		mockUsecase := &MockUserUsecase{
-			UpdateUserFunc: func(id int, user dto.UpdateUserRequest) error {
+			UpdateUserFunc: func(ctx context.Context, id int, user dto.UpdateUserRequest) error {
 				return nil
 			},
 		}
@@ -116,12 +195,68 @@ func TestUpdateUser(t *testing.T) {
 		req.Header.Set("Content-Type", "application/json")
 		c.Params = gin.Params{{Key: "userId", Value: "1"}}
 		c.Request = req
+		c.Set(middleware.ContextUserID, 1)
+		c.Set(middleware.ContextRole, model.RoleCustomer)
 
 		userController := NewUserController(mockUsecase)
 		userController.UpdateUser(c)
 
 		assert.Equal(t, http.StatusNoContent, c.Writer.Status())
 	})
+
+	tests := []struct {
+		name   string
+		role   string
+		status int
+	}{
+		{"Another User", model.RoleCustomer, http.StatusForbidden},
+		{"Admin", model.RoleAdmin, http.StatusNoContent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			updated := false
+			mockUsecase := &MockUserUsecase{
+				UpdateUserFunc: func(ctx context.Context, id int, user dto.UpdateUserRequest) error {
+					updated = true
+					return nil
+				},
+			}
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest(http.MethodPut, "/users/2", strings.NewReader(`{"email": "taken@example.com"}`))
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Params = gin.Params{{Key: "userId", Value: "2"}}
+			c.Set(middleware.ContextUserID, 1)
+			c.Set(middleware.ContextRole, tt.role)
+
+			NewUserController(mockUsecase).UpdateUser(c)
+
+			assert.Equal(t, tt.status, c.Writer.Status())
+			assert.Equal(t, tt.status == http.StatusNoContent, updated)
+		})
+	}
+
+	t.Run("Invalid Email", func(t *testing.T) {
+		mockUsecase := &MockUserUsecase{
+			UpdateUserFunc: func(ctx context.Context, id int, user dto.UpdateUserRequest) error {
+				t.Fatal("an invalid email should not reach the usecase")
+				return nil
+			},
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodPut, "/users/1", strings.NewReader(`{"email": "not-an-email"}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Params = gin.Params{{Key: "userId", Value: "1"}}
+		c.Set(middleware.ContextUserID, 1)
+		c.Set(middleware.ContextRole, model.RoleCustomer)
+
+		NewUserController(mockUsecase).UpdateUser(c)
+
+		assert.Equal(t, http.StatusBadRequest, c.Writer.Status())
+	})
 }
 
 func TestDeleteUser(t *testing.T) {
@@ -129,7 +264,7 @@ func TestDeleteUser(t *testing.T) {
 
 	t.Run("Success", func(t *testing.T) {
 		mockUsecase := &MockUserUsecase{
-			DeleteUserFunc: func(id int) error {
+			DeleteUserFunc: func(ctx context.Context, id int) error {
 				return nil
 			},
 		}
@@ -146,9 +281,28 @@ func TestDeleteUser(t *testing.T) {
 		assert.Equal(t, http.StatusNoContent, c.Writer.Status())
 	})
 
+	t.Run("Not Found", func(t *testing.T) {
+		mockUsecase := &MockUserUsecase{
+			DeleteUserFunc: func(ctx context.Context, id int) error {
+				return usecase.ErrUserNotFound
+			},
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		req, _ := http.NewRequest(http.MethodDelete, "/users/99", nil)
+		c.Params = gin.Params{{Key: "userId", Value: "99"}}
+		c.Request = req
+
+		userController := NewUserController(mockUsecase)
+		userController.DeleteUser(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
 	t.Run("Error", func(t *testing.T) {
 		mockUsecase := &MockUserUsecase{
-			DeleteUserFunc: func(id int) error {
+			DeleteUserFunc: func(ctx context.Context, id int) error {
 				return errors.New("delete failed")
 			},
 		}
@@ -171,7 +325,7 @@ func TestGetUsers(t *testing.T) {
 
 	t.Run("Success", func(t *testing.T) {
 		mockUsecase := &MockUserUsecase{
-			GetUsersFunc: func() ([]dto.UserResponse, error) {
+			GetUsersFunc: func(filter model.UserFilter) ([]dto.UserResponse, error) {
 				return []dto.UserResponse{
 					{ID: 1, Name: "User 1", Email: "user1@example.com"},
 					{ID: 2, Name: "User 2", Email: "user2@example.com"},
@@ -193,6 +347,34 @@ func TestGetUsers(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Len(t, response, 2)
 	})
+
+	t.Run("Updated Since", func(t *testing.T) {
+		since := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+		mockUsecase := &MockUserUsecase{
+			GetUsersFunc: func(filter model.UserFilter) ([]dto.UserResponse, error) {
+				assert.True(t, since.Equal(filter.UpdatedSince))
+				return []dto.UserResponse{}, nil
+			},
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodGet, "/users?updated_since=2026-03-01T12:00:00Z", nil)
+
+		NewUserController(mockUsecase).GetUsers(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("Invalid Updated Since", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodGet, "/users?updated_since=yesterday", nil)
+
+		NewUserController(&MockUserUsecase{}).GetUsers(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
 }
 
 func TestLogin(t *testing.T) {
@@ -200,7 +382,7 @@ func TestLogin(t *testing.T) {
 
 	t.Run("Success", func(t *testing.T) {
 		mockUsecase := &MockUserUsecase{
-			LoginFunc: func(req dto.LoginRequest) (*dto.LoginResponse, error) {
+			LoginFunc: func(ctx context.Context, req dto.LoginRequest) (*dto.LoginResponse, error) {
 				return &dto.LoginResponse{Token: "valid-token"}, nil
 			},
 		}
@@ -227,10 +409,29 @@ func TestLogin(t *testing.T) {
 		assert.Equal(t, "valid-token", response.Token)
 	})
 
+	t.Run("Cart Token From Header", func(t *testing.T) {
+		mockUsecase := &MockUserUsecase{
+			LoginFunc: func(ctx context.Context, req dto.LoginRequest) (*dto.LoginResponse, error) {
+				assert.Equal(t, "anon-token", req.CartToken)
+				return &dto.LoginResponse{Token: "valid-token"}, nil
+			},
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodPost, "/login", bytes.NewBufferString(`{"email": "user@example.com", "password": "password123"}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Request.Header.Set(CartTokenHeader, "anon-token")
+
+		NewUserController(mockUsecase).Login(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
 	t.Run("Invalid Credentials", func(t *testing.T) {
 		mockUsecase := &MockUserUsecase{
-			LoginFunc: func(req dto.LoginRequest) (*dto.LoginResponse, error) {
-				return nil, errors.New("invalid credentials")
+			LoginFunc: func(ctx context.Context, req dto.LoginRequest) (*dto.LoginResponse, error) {
+				return nil, usecase.ErrInvalidCredentials
 			},
 		}
 
@@ -258,7 +459,7 @@ func TestLogin(t *testing.T) {
 
 	t.Run("Internal Error", func(t *testing.T) {
 		mockUsecase := &MockUserUsecase{
-			LoginFunc: func(req dto.LoginRequest) (*dto.LoginResponse, error) {
+			LoginFunc: func(ctx context.Context, req dto.LoginRequest) (*dto.LoginResponse, error) {
 				return nil, errors.New("database error")
 			},
 		}
@@ -284,4 +485,185 @@ func TestLogin(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, "database error", resp["error"])
 	})
+
+	t.Run("Throttled", func(t *testing.T) {
+		mockUsecase := &MockUserUsecase{
+			LoginFunc: func(ctx context.Context, req dto.LoginRequest) (*dto.LoginResponse, error) {
+				return nil, &usecase.LoginThrottledError{RetryAfter: 1500 * time.Millisecond}
+			},
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodPost, "/login", bytes.NewBufferString(`{"email": "user@example.com", "password": "password123"}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		NewUserController(mockUsecase).Login(c)
+
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.Equal(t, "2", w.Header().Get("Retry-After"))
+	})
+
+	t.Run("Email Not Verified", func(t *testing.T) {
+		mockUsecase := &MockUserUsecase{
+			LoginFunc: func(ctx context.Context, req dto.LoginRequest) (*dto.LoginResponse, error) {
+				return nil, usecase.ErrEmailNotVerified
+			},
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodPost, "/login", bytes.NewBufferString(`{"email": "user@example.com", "password": "password123"}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		NewUserController(mockUsecase).Login(c)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+}
+
+func TestExportUsers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Success", func(t *testing.T) {
+		createdAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+		mockUsecase := &MockUserUsecase{
+			ExportUsersFunc: func(ctx context.Context, filter model.UserFilter, fn func(dto.UserResponse) error) error {
+				assert.Equal(t, createdAt.Add(-time.Hour), filter.UpdatedSince)
+				return fn(dto.UserResponse{ID: 1, Name: "Leandro", Email: "leandro@example.com", Role: "admin", CreatedAt: createdAt, UpdatedAt: createdAt})
+			},
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodGet, "/users/export?format=ndjson&updated_since=2026-03-01T11:00:00Z", nil)
+
+		userController := NewUserController(mockUsecase)
+		userController.ExportUsers(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, `{"id":1,"name":"Leandro","email":"leandro@example.com","role":"admin","created_at":"2026-03-01T12:00:00Z","updated_at":"2026-03-01T12:00:00Z"}`+"\n", w.Body.String())
+		assert.NotContains(t, w.Body.String(), "password")
+	})
+
+	t.Run("Invalid Updated Since", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodGet, "/users/export?updated_since=yesterday", nil)
+
+		NewUserController(&MockUserUsecase{}).ExportUsers(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestUserTrash(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Restore With The Email Taken", func(t *testing.T) {
+		mockUsecase := &MockUserUsecase{
+			RestoreUserFunc: func(ctx context.Context, id int) (*dto.UserResponse, error) {
+				return nil, usecase.ErrEmailTaken
+			},
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodPost, "/trash/users/1/restore", nil)
+		c.Params = gin.Params{{Key: "userId", Value: "1"}}
+
+		userController := NewUserController(mockUsecase)
+		userController.RestoreUser(c)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("Purge Outside The Trash", func(t *testing.T) {
+		mockUsecase := &MockUserUsecase{
+			PurgeUserFunc: func(ctx context.Context, id int) error {
+				return usecase.ErrUserNotFound
+			},
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodDelete, "/trash/users/1", nil)
+		c.Params = gin.Params{{Key: "userId", Value: "1"}}
+
+		userController := NewUserController(mockUsecase)
+		userController.PurgeUser(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestVerifyEmail(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{"Verified", nil, http.StatusOK},
+		{"Invalid Token", usecase.ErrInvalidVerificationToken, http.StatusBadRequest},
+		{"Email Taken Meanwhile", usecase.ErrEmailTaken, http.StatusConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUsecase := &MockUserUsecase{
+				VerifyEmailFunc: func(ctx context.Context, token string) (*dto.UserResponse, error) {
+					assert.Equal(t, "abc.def", token)
+					if tt.err != nil {
+						return nil, tt.err
+					}
+					return &dto.UserResponse{ID: 7, Email: "ana@example.com"}, nil
+				},
+			}
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest(http.MethodGet, "/auth/email/verify?token=abc.def", nil)
+
+			NewUserController(mockUsecase).VerifyEmail(c)
+
+			assert.Equal(t, tt.status, w.Code)
+		})
+	}
+}
+
+func TestResendVerification(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name   string
+		body   string
+		err    error
+		status int
+	}{
+		{"Accepted", `{"email": "ana@example.com"}`, nil, http.StatusAccepted},
+		{"Invalid Email", `{"email": "ana"}`, nil, http.StatusBadRequest},
+		{"Rate Limited", `{"email": "ana@example.com"}`, &usecase.VerificationRateLimitedError{RetryAfter: 3 * time.Second}, http.StatusTooManyRequests},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUsecase := &MockUserUsecase{
+				ResendVerificationFunc: func(ctx context.Context, email string) error {
+					return tt.err
+				},
+			}
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest(http.MethodPost, "/auth/email/resend", bytes.NewBufferString(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			NewUserController(mockUsecase).ResendVerification(c)
+
+			assert.Equal(t, tt.status, w.Code)
+			if tt.status == http.StatusTooManyRequests {
+				assert.Equal(t, "3", w.Header().Get("Retry-After"))
+			}
+		})
+	}
 }
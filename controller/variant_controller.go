@@ -0,0 +1,344 @@
+package controller
+
+import (
+	"errors"
+	"go-api/dto"
+	"go-api/model"
+	"go-api/usecase"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// VariantController handles HTTP requests for option types and product variants
+type VariantController struct {
+	variantUsecase usecase.VariantUsecase
+}
+
+// NewVariantController creates a new VariantController
+func NewVariantController(usecase usecase.VariantUsecase) *VariantController {
+	return &VariantController{
+		variantUsecase: usecase,
+	}
+}
+
+// GetOptionTypes godoc
+// @Summary List option types
+// @Description Get every option type (size, color, ...) with its values
+// @Tags variants
+// @Accept json
+// @Produce json
+// @Success 200 {array} dto.OptionTypeResponse "Option types"
+// @Failure 500 {object} model.Response "Internal server error"
+// @Router /option-types [get]
+func (vc *VariantController) GetOptionTypes(ctx *gin.Context) {
+	optionTypes, err := vc.variantUsecase.GetOptionTypes()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	responses := make([]dto.OptionTypeResponse, 0, len(optionTypes))
+	for _, optionType := range optionTypes {
+		responses = append(responses, toOptionTypeResponse(optionType))
+	}
+	ctx.JSON(http.StatusOK, responses)
+}
+
+// CreateOptionType godoc
+// @Summary Create an option type
+// @Description Create an option type with its values in display order
+// @Tags variants
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param optionType body dto.CreateOptionTypeRequest true "Option type information"
+// @Success 201 {object} dto.OptionTypeResponse "Option type created successfully"
+// @Failure 400 {object} model.Response "Bad request - Invalid input data"
+// @Failure 401 {object} model.Response "Missing or invalid token"
+// @Failure 403 {object} model.Response "Admin role required"
+// @Failure 409 {object} model.Response "Name or value already used"
+// @Failure 500 {object} model.Response "Internal server error"
+// @Router /option-type [post]
+func (vc *VariantController) CreateOptionType(ctx *gin.Context) {
+	var req dto.CreateOptionTypeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	optionType, err := vc.variantUsecase.CreateOptionType(req.Name, req.Values)
+	if err != nil {
+		ctx.JSON(variantErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, toOptionTypeResponse(*optionType))
+}
+
+// AddOptionValue godoc
+// @Summary Add a value to an option type
+// @Description Append a value after the existing values of the option type
+// @Tags variants
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param optionTypeId path int true "Option type ID" minimum(1)
+// @Param value body dto.AddOptionValueRequest true "Option value"
+// @Success 201 {object} dto.OptionValueResponse "Value added successfully"
+// @Failure 400 {object} model.Response "Bad request - Invalid input data"
+// @Failure 401 {object} model.Response "Missing or invalid token"
+// @Failure 403 {object} model.Response "Admin role required"
+// @Failure 404 {object} model.Response "Option type not found"
+// @Failure 409 {object} model.Response "Value already exists"
+// @Failure 500 {object} model.Response "Internal server error"
+// @Router /option-types/{optionTypeId}/values [post]
+func (vc *VariantController) AddOptionValue(ctx *gin.Context) {
+	optionTypeId, err := strconv.Atoi(ctx.Param("optionTypeId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid option type ID"})
+		return
+	}
+
+	var req dto.AddOptionValueRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	value, err := vc.variantUsecase.AddOptionValue(optionTypeId, req.Value)
+	if err != nil {
+		ctx.JSON(variantErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, toOptionValueResponse(value))
+}
+
+// GetVariants godoc
+// @Summary List the variants of a product
+// @Description Get the variants of a product with their SKU, price, stock and option values
+// @Tags variants
+// @Accept json
+// @Produce json
+// @Param productId path int true "Product ID" minimum(1)
+// @Success 200 {array} dto.VariantResponse "Product variants"
+// @Failure 400 {object} model.Response "Bad request - Invalid ID format"
+// @Failure 404 {object} model.Response "Product not found"
+// @Failure 500 {object} model.Response "Internal server error"
+// @Router /products/{productId}/variants [get]
+func (vc *VariantController) GetVariants(ctx *gin.Context) {
+	productId, err := strconv.Atoi(ctx.Param("productId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return
+	}
+
+	variants, err := vc.variantUsecase.GetVariants(productId)
+	if err != nil {
+		ctx.JSON(variantErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, toVariantResponses(variants))
+}
+
+// CreateVariant godoc
+// @Summary Create a product variant
+// @Description Create a variant taking one value of each option type used by the product
+// @Tags variants
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param productId path int true "Product ID" minimum(1)
+// @Param variant body dto.CreateVariantRequest true "Variant information"
+// @Success 201 {object} dto.VariantResponse "Variant created successfully"
+// @Failure 400 {object} model.Response "Bad request - Invalid input data or options"
+// @Failure 401 {object} model.Response "Missing or invalid token"
+// @Failure 403 {object} model.Response "Admin role required, or API key or access token without the scope of the route"
+// @Failure 404 {object} model.Response "Product or option value not found"
+// @Failure 409 {object} model.Response "SKU or option combination already used"
+// @Failure 500 {object} model.Response "Internal server error"
+// @Router /products/{productId}/variants [post]
+func (vc *VariantController) CreateVariant(ctx *gin.Context) {
+	productId, err := strconv.Atoi(ctx.Param("productId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return
+	}
+
+	var req dto.CreateVariantRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	variant, err := vc.variantUsecase.CreateVariant(productId, model.VariantInput{
+		SKU:            req.SKU,
+		PriceOverride:  req.PriceOverride,
+		Stock:          req.Stock,
+		OptionValueIDs: req.OptionValueIDs,
+	})
+	if err != nil {
+		ctx.JSON(variantErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, toVariantResponse(*variant))
+}
+
+// UpdateVariant godoc
+// @Summary Update a product variant
+// @Description Replace the SKU, price override and stock of a variant. The option values of a variant never change
+// @Tags variants
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param productId path int true "Product ID" minimum(1)
+// @Param variantId path int true "Variant ID" minimum(1)
+// @Param variant body dto.UpdateVariantRequest true "Variant information"
+// @Success 200 {object} dto.VariantResponse "Variant updated successfully"
+// @Failure 400 {object} model.Response "Bad request - Invalid input data"
+// @Failure 401 {object} model.Response "Missing or invalid token"
+// @Failure 403 {object} model.Response "Admin role required, or API key or access token without the scope of the route"
+// @Failure 404 {object} model.Response "Product or variant not found"
+// @Failure 409 {object} model.Response "SKU already used"
+// @Failure 500 {object} model.Response "Internal server error"
+// @Router /products/{productId}/variants/{variantId} [put]
+func (vc *VariantController) UpdateVariant(ctx *gin.Context) {
+	productId, variantId, ok := variantParams(ctx)
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateVariantRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	variant, err := vc.variantUsecase.UpdateVariant(productId, variantId, model.VariantInput{
+		SKU:           req.SKU,
+		PriceOverride: req.PriceOverride,
+		Stock:         req.Stock,
+	})
+	if err != nil {
+		ctx.JSON(variantErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, toVariantResponse(*variant))
+}
+
+// DeleteVariant godoc
+// @Summary Delete a product variant
+// @Description Delete a variant of a product
+// @Tags variants
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param productId path int true "Product ID" minimum(1)
+// @Param variantId path int true "Variant ID" minimum(1)
+// @Success 204 "Variant deleted successfully"
+// @Failure 400 {object} model.Response "Bad request - Invalid ID format"
+// @Failure 401 {object} model.Response "Missing or invalid token"
+// @Failure 403 {object} model.Response "Admin role required, or API key or access token without the scope of the route"
+// @Failure 404 {object} model.Response "Product or variant not found"
+// @Failure 500 {object} model.Response "Internal server error"
+// @Router /products/{productId}/variants/{variantId} [delete]
+func (vc *VariantController) DeleteVariant(ctx *gin.Context) {
+	productId, variantId, ok := variantParams(ctx)
+	if !ok {
+		return
+	}
+
+	if err := vc.variantUsecase.DeleteVariant(productId, variantId); err != nil {
+		ctx.JSON(variantErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// --- Helper Functions ---
+
+func variantParams(ctx *gin.Context) (int, int, bool) {
+	productId, err := strconv.Atoi(ctx.Param("productId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return 0, 0, false
+	}
+	variantId, err := strconv.Atoi(ctx.Param("variantId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid variant ID"})
+		return 0, 0, false
+	}
+	return productId, variantId, true
+}
+
+func variantErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, usecase.ErrProductNotFound), errors.Is(err, usecase.ErrVariantNotFound),
+		errors.Is(err, usecase.ErrOptionTypeNotFound), errors.Is(err, usecase.ErrOptionValueNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, usecase.ErrOptionTypeNameTaken), errors.Is(err, usecase.ErrOptionValueTaken),
+		errors.Is(err, usecase.ErrSKUTaken), errors.Is(err, usecase.ErrDuplicateVariant):
+		return http.StatusConflict
+	case errors.Is(err, usecase.ErrInvalidVariantOptions), errors.Is(err, usecase.ErrInvalidStock),
+		errors.Is(err, usecase.ErrInvalidSKU), errors.Is(err, model.ErrInvalidAmount):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func toOptionValueResponse(value model.OptionValue) dto.OptionValueResponse {
+	return dto.OptionValueResponse{
+		ID:       value.ID,
+		Value:    value.Value,
+		Position: value.Position,
+	}
+}
+
+func toOptionTypeResponse(optionType model.OptionType) dto.OptionTypeResponse {
+	values := make([]dto.OptionValueResponse, 0, len(optionType.Values))
+	for _, value := range optionType.Values {
+		values = append(values, toOptionValueResponse(value))
+	}
+	return dto.OptionTypeResponse{
+		ID:     optionType.ID,
+		Name:   optionType.Name,
+		Values: values,
+	}
+}
+
+func toVariantResponse(variant model.Variant) dto.VariantResponse {
+	options := make([]dto.VariantOptionResponse, 0, len(variant.Options))
+	for _, option := range variant.Options {
+		options = append(options, dto.VariantOptionResponse{
+			OptionType:    option.OptionType,
+			OptionValueID: option.OptionValueID,
+			Value:         option.Value,
+		})
+	}
+	return dto.VariantResponse{
+		ID:              variant.ID,
+		SKU:             variant.SKU,
+		Price:           toMoneyResponse(variant.Price),
+		PriceOverridden: variant.PriceOverride != nil,
+		Stock:           variant.Stock,
+		Options:         options,
+	}
+}
+
+func toVariantResponses(variants []model.Variant) []dto.VariantResponse {
+	responses := make([]dto.VariantResponse, 0, len(variants))
+	for _, variant := range variants {
+		responses = append(responses, toVariantResponse(variant))
+	}
+	return responses
+}
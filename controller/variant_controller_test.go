@@ -0,0 +1,188 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"go-api/dto"
+	"go-api/model"
+	"go-api/usecase"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateOptionType(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Success", func(t *testing.T) {
+		mockUsecase := &MockVariantUsecase{
+			CreateOptionTypeFunc: func(name string, values []string) (*model.OptionType, error) {
+				assert.Equal(t, []string{"P", "M"}, values)
+				return &model.OptionType{ID: 1, Name: name, Values: []model.OptionValue{
+					{ID: 11, OptionTypeID: 1, Value: "P", Position: 0},
+					{ID: 12, OptionTypeID: 1, Value: "M", Position: 1},
+				}}, nil
+			},
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodPost, "/option-type", bytes.NewBufferString(`{"name": "size", "values": ["P", "M"]}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		variantController := NewVariantController(mockUsecase)
+		variantController.CreateOptionType(c)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		var response dto.OptionTypeResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Len(t, response.Values, 2)
+	})
+
+	t.Run("Name Conflict", func(t *testing.T) {
+		mockUsecase := &MockVariantUsecase{
+			CreateOptionTypeFunc: func(name string, values []string) (*model.OptionType, error) {
+				return nil, usecase.ErrOptionTypeNameTaken
+			},
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodPost, "/option-type", bytes.NewBufferString(`{"name": "size"}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		variantController := NewVariantController(mockUsecase)
+		variantController.CreateOptionType(c)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+}
+
+func TestCreateVariant(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Success", func(t *testing.T) {
+		override := model.Money{Amount: 5490, Currency: "BRL"}
+		mockUsecase := &MockVariantUsecase{
+			CreateVariantFunc: func(productID int, input model.VariantInput) (*model.Variant, error) {
+				assert.Equal(t, 1, productID)
+				assert.Equal(t, []int{11, 21}, input.OptionValueIDs)
+				assert.Equal(t, "54.90", *input.PriceOverride)
+				return &model.Variant{ID: 3, ProductID: productID, SKU: "CAM-AZUL-M", PriceOverride: &override, Price: override, Stock: input.Stock,
+					Options: []model.VariantOption{{OptionTypeID: 1, OptionType: "size", OptionValueID: 11, Value: "M"}}}, nil
+			},
+		}
+
+		body := `{"sku": "cam-azul-m", "price_override": "54.90", "stock": 4, "option_value_ids": [11, 21]}`
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Params = gin.Params{{Key: "productId", Value: "1"}}
+		c.Request, _ = http.NewRequest(http.MethodPost, "/products/1/variants", bytes.NewBufferString(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		variantController := NewVariantController(mockUsecase)
+		variantController.CreateVariant(c)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		var response dto.VariantResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.True(t, response.PriceOverridden)
+		assert.Equal(t, 4, response.Stock)
+		assert.Equal(t, "M", response.Options[0].Value)
+	})
+
+	t.Run("Duplicate Combination", func(t *testing.T) {
+		mockUsecase := &MockVariantUsecase{
+			CreateVariantFunc: func(productID int, input model.VariantInput) (*model.Variant, error) {
+				return nil, usecase.ErrDuplicateVariant
+			},
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Params = gin.Params{{Key: "productId", Value: "1"}}
+		c.Request, _ = http.NewRequest(http.MethodPost, "/products/1/variants", bytes.NewBufferString(`{"sku": "X", "option_value_ids": [11]}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		variantController := NewVariantController(mockUsecase)
+		variantController.CreateVariant(c)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("Negative Stock", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Params = gin.Params{{Key: "productId", Value: "1"}}
+		c.Request, _ = http.NewRequest(http.MethodPost, "/products/1/variants", bytes.NewBufferString(`{"sku": "X", "stock": -1}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		variantController := NewVariantController(&MockVariantUsecase{})
+		variantController.CreateVariant(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestDeleteVariant(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Not Found", func(t *testing.T) {
+		mockUsecase := &MockVariantUsecase{
+			DeleteVariantFunc: func(productID, variantID int) error {
+				return usecase.ErrVariantNotFound
+			},
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Params = gin.Params{{Key: "productId", Value: "1"}, {Key: "variantId", Value: "9"}}
+		c.Request, _ = http.NewRequest(http.MethodDelete, "/products/1/variants/9", nil)
+
+		variantController := NewVariantController(mockUsecase)
+		variantController.DeleteVariant(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestGetProductWithVariants(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Includes Variant Matrix", func(t *testing.T) {
+		mockUsecase := &MockProductUsecase{
+			GetProductByIdFunc: func(id_product int, opts model.PriceOptions) (*model.Product, error) {
+				price := model.Money{Amount: 4990, Currency: "BRL"}
+				return &model.Product{
+					ID: id_product, Name: "Camiseta", Price: price,
+					Options: []model.ProductOption{{Name: "size", Values: []string{"M", "G"}}},
+					Variants: []model.Variant{
+						{ID: 1, SKU: "CAM-M", Price: price, Options: []model.VariantOption{{OptionType: "size", Value: "M"}}},
+						{ID: 2, SKU: "CAM-G", Price: price, Options: []model.VariantOption{{OptionType: "size", Value: "G"}}},
+					},
+				}, nil
+			},
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Params = gin.Params{{Key: "productId", Value: "1"}}
+		c.Request, _ = http.NewRequest(http.MethodGet, "/products/1", nil)
+
+		productController := NewProductController(mockUsecase)
+		productController.GetProductById(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response dto.ProductResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"M", "G"}, response.Options[0].Values)
+		assert.Len(t, response.Variants, 2)
+		assert.Equal(t, "CAM-G", response.Variants[1].SKU)
+	})
+}
@@ -2,17 +2,463 @@
 CREATE TABLE IF NOT EXISTS users (
     id SERIAL PRIMARY KEY,
     name VARCHAR(255) NOT NULL,
-    email VARCHAR(255) NOT NULL UNIQUE,
-    password VARCHAR(255) NOT NULL
+    email VARCHAR(255) NOT NULL, -- único entre os usuários fora da lixeira
+    password VARCHAR(255) NOT NULL,
+    role VARCHAR(20) NOT NULL DEFAULT 'customer', -- customer | admin | service
+    email_verified_at TIMESTAMPTZ, -- NULL até o usuário abrir o link enviado ao email
+    pending_email VARCHAR(255), -- troca de email aguardando a confirmação do novo endereço
+    verification_sent_at TIMESTAMPTZ, -- último envio do link, para limitar os reenvios
+    signup_notice_sent_at TIMESTAMPTZ, -- último aviso de tentativa de cadastro com o email
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    created_by INTEGER, -- usuário autenticado que criou; NULL no cadastro anônimo
+    updated_by INTEGER, -- sem FK: o registro sobrevive à exclusão do autor
+    deleted_at TIMESTAMPTZ -- preenchido enquanto o usuário está na lixeira
 );
 
 -- Criação da tabela products simplificada
 CREATE TABLE IF NOT EXISTS products (
     id SERIAL PRIMARY KEY,
     product_name VARCHAR(255) NOT NULL,
-    price DECIMAL(10,2) NOT NULL
+    price NUMERIC(12,3) NOT NULL, -- preço inicial; o vigente vem de product_prices
+    currency CHAR(3) NOT NULL DEFAULT 'BRL', -- código ISO 4217
+    sku VARCHAR(64), -- opcional e único fora da lixeira; a importação casa por SKU antes do nome
+    tax_category VARCHAR(32) NOT NULL DEFAULT 'standard', -- categoria declarada na tabela de alíquotas (db/tax_rates.json)
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(), -- muda com o produto e com seu histórico de preços
+    created_by INTEGER,
+    updated_by INTEGER,
+    deleted_at TIMESTAMPTZ -- preenchido enquanto o produto está na lixeira
 );
 
+-- Histórico de preços (somente inserção). O preço vigente em um instante é a
+-- promoção (sale) em vigor, senão o preço regular com effective_from mais recente
+CREATE TABLE IF NOT EXISTS product_prices (
+    id SERIAL PRIMARY KEY,
+    product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    price NUMERIC(12,3) NOT NULL, -- na moeda do produto
+    kind VARCHAR(10) NOT NULL DEFAULT 'regular' CHECK (kind IN ('regular', 'sale')),
+    effective_from TIMESTAMPTZ NOT NULL,
+    effective_to TIMESTAMPTZ, -- obrigatório para promoções
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (effective_to IS NULL OR effective_to > effective_from)
+);
+
+-- Taxonomia de categorias (lista de adjacência + caminho materializado)
+CREATE TABLE IF NOT EXISTS categories (
+    id SERIAL PRIMARY KEY,
+    parent_id INTEGER REFERENCES categories(id),
+    name VARCHAR(255) NOT NULL,
+    slug VARCHAR(255) NOT NULL,
+    path TEXT NOT NULL UNIQUE -- slug único entre irmãos
+);
+
+-- Associação N:N entre produtos e categorias
+CREATE TABLE IF NOT EXISTS product_categories (
+    product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    category_id INTEGER NOT NULL REFERENCES categories(id) ON DELETE CASCADE,
+    PRIMARY KEY (product_id, category_id)
+);
+
+-- Tipos de opção (tamanho, cor...) e seus valores
+CREATE TABLE IF NOT EXISTS option_types (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(50) NOT NULL UNIQUE
+);
+
+CREATE TABLE IF NOT EXISTS option_values (
+    id SERIAL PRIMARY KEY,
+    option_type_id INTEGER NOT NULL REFERENCES option_types(id) ON DELETE CASCADE,
+    value VARCHAR(100) NOT NULL,
+    position INTEGER NOT NULL DEFAULT 0,
+    UNIQUE (option_type_id, value)
+);
+
+-- Variantes: uma combinação de valores de opção com SKU, preço e estoque próprios
+CREATE TABLE IF NOT EXISTS product_variants (
+    id SERIAL PRIMARY KEY,
+    product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    sku VARCHAR(64) NOT NULL UNIQUE,
+    price_override NUMERIC(12,3), -- NULL herda o preço do produto
+    stock INTEGER NOT NULL DEFAULT 0 CHECK (stock >= 0),
+    options_key TEXT NOT NULL, -- IDs dos valores ordenados, garante combinação única
+    UNIQUE (product_id, options_key)
+);
+
+CREATE TABLE IF NOT EXISTS variant_option_values (
+    variant_id INTEGER NOT NULL REFERENCES product_variants(id) ON DELETE CASCADE,
+    option_value_id INTEGER NOT NULL REFERENCES option_values(id),
+    PRIMARY KEY (variant_id, option_value_id)
+);
+
+-- Imagens dos produtos; os arquivos ficam no BlobStore, aqui só as chaves
+CREATE TABLE IF NOT EXISTS product_images (
+    id SERIAL PRIMARY KEY,
+    product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    storage_key TEXT NOT NULL UNIQUE,
+    content_type VARCHAR(50) NOT NULL,
+    width INTEGER NOT NULL,
+    height INTEGER NOT NULL,
+    size_bytes BIGINT NOT NULL,
+    position INTEGER NOT NULL DEFAULT 0,
+    is_primary BOOLEAN NOT NULL DEFAULT FALSE,
+    thumbnails JSONB NOT NULL DEFAULT '[]', -- [{name, key, width, height}]
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- No máximo uma imagem principal por produto
+CREATE UNIQUE INDEX IF NOT EXISTS idx_product_images_primary ON product_images(product_id) WHERE is_primary;
+
+-- Listas de preços explícitos por moeda (e, opcionalmente, por mercado)
+CREATE TABLE IF NOT EXISTS price_lists (
+    id SERIAL PRIMARY KEY,
+    code VARCHAR(50) NOT NULL UNIQUE,
+    name VARCHAR(255) NOT NULL,
+    currency CHAR(3) NOT NULL,
+    market VARCHAR(20) -- NULL vale para todos os mercados
+);
+
+CREATE TABLE IF NOT EXISTS price_list_items (
+    price_list_id INTEGER NOT NULL REFERENCES price_lists(id) ON DELETE CASCADE,
+    product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    price NUMERIC(12,3) NOT NULL, -- na moeda da lista
+    PRIMARY KEY (price_list_id, product_id)
+);
+
+-- Taxas de câmbio: 1 unidade de base = rate unidades de quote
+CREATE TABLE IF NOT EXISTS exchange_rates (
+    base CHAR(3) NOT NULL,
+    quote CHAR(3) NOT NULL,
+    rate NUMERIC(20,10) NOT NULL CHECK (rate > 0),
+    source VARCHAR(255) NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (base, quote)
+);
+
+-- Carrinhos: um por usuário ou, para visitantes, por token de sessão
+CREATE TABLE IF NOT EXISTS carts (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER UNIQUE REFERENCES users(id) ON DELETE CASCADE,
+    token_hash TEXT UNIQUE, -- SHA-256 do token do carrinho anônimo
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (user_id IS NOT NULL OR token_hash IS NOT NULL)
+);
+
+CREATE TABLE IF NOT EXISTS cart_items (
+    id SERIAL PRIMARY KEY,
+    cart_id INTEGER NOT NULL REFERENCES carts(id) ON DELETE CASCADE,
+    product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    variant_id INTEGER REFERENCES product_variants(id) ON DELETE CASCADE,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    price NUMERIC(12,3) NOT NULL, -- preço unitário quando o item foi adicionado, na moeda do produto
+    added_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Uma linha por produto/variante em cada carrinho
+CREATE UNIQUE INDEX IF NOT EXISTS idx_cart_items_line ON cart_items(cart_id, product_id, (COALESCE(variant_id, 0)));
+
+-- Pedidos: os itens guardam nome e preço do produto no momento da compra
+CREATE TABLE IF NOT EXISTS orders (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER REFERENCES users(id) ON DELETE SET NULL, -- o pedido sobrevive à exclusão definitiva do usuário
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    currency CHAR(3) NOT NULL,
+    discount NUMERIC(12,3) NOT NULL DEFAULT 0, -- soma dos descontos das promoções
+    tax NUMERIC(12,3) NOT NULL DEFAULT 0, -- soma dos impostos, inclusos ou não nos preços
+    tax_included BOOLEAN NOT NULL DEFAULT FALSE, -- impostos já inclusos nos preços não somam ao total
+    tax_country CHAR(2), -- destino que definiu as alíquotas; NULL quando não houve cálculo
+    tax_state VARCHAR(8),
+    total NUMERIC(12,3) NOT NULL, -- soma dos itens menos o desconto, mais os impostos não inclusos
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS order_items (
+    id SERIAL PRIMARY KEY,
+    order_id INTEGER NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    product_id INTEGER REFERENCES products(id) ON DELETE SET NULL,
+    variant_id INTEGER REFERENCES product_variants(id) ON DELETE SET NULL,
+    product_name VARCHAR(255) NOT NULL,
+    sku VARCHAR(64),
+    unit_price NUMERIC(12,3) NOT NULL, -- na moeda do pedido
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    discount NUMERIC(12,3) NOT NULL DEFAULT 0, -- parte do desconto do pedido neste item
+    tax NUMERIC(12,3) NOT NULL DEFAULT 0 -- imposto do item depois do desconto
+);
+
+-- Impostos do pedido por nome e alíquota, como calculados no checkout
+CREATE TABLE IF NOT EXISTS order_taxes (
+    id SERIAL PRIMARY KEY,
+    order_id INTEGER NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    name VARCHAR(64) NOT NULL,
+    percent VARCHAR(16) NOT NULL, -- como na tabela de alíquotas, ex.: 8.875
+    amount NUMERIC(12,3) NOT NULL -- na moeda do pedido
+);
+
+-- Cada mudança de status do pedido, inclusive a criação (from_status NULL)
+CREATE TABLE IF NOT EXISTS order_status_history (
+    id SERIAL PRIMARY KEY,
+    order_id INTEGER NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    from_status VARCHAR(20),
+    to_status VARCHAR(20) NOT NULL,
+    actor_id INTEGER, -- sem FK, como em audit_events
+    note TEXT NOT NULL DEFAULT '',
+    occurred_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Promoções: com code é um cupom, sem code é aplicada automaticamente.
+-- currency restringe amount e min_total; percentuais sem mínimo valem em qualquer moeda
+CREATE TABLE IF NOT EXISTS promotions (
+    id SERIAL PRIMARY KEY,
+    code VARCHAR(32) UNIQUE, -- sempre em maiúsculas
+    name VARCHAR(255) NOT NULL,
+    kind VARCHAR(20) NOT NULL, -- percentage | fixed
+    percent INTEGER CHECK (percent BETWEEN 1 AND 100),
+    currency CHAR(3),
+    amount NUMERIC(12,3),
+    min_total NUMERIC(12,3),
+    usage_limit INTEGER,
+    per_user_limit INTEGER,
+    uses INTEGER NOT NULL DEFAULT 0, -- resgates vigentes; o UPDATE que o incrementa serializa os checkouts concorrentes
+    starts_at TIMESTAMPTZ,
+    ends_at TIMESTAMPTZ, -- exclusivo
+    stackable BOOLEAN NOT NULL DEFAULT FALSE,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Elegibilidade: sem linhas nas duas tabelas, todos os produtos são elegíveis
+CREATE TABLE IF NOT EXISTS promotion_products (
+    promotion_id INTEGER NOT NULL REFERENCES promotions(id) ON DELETE CASCADE,
+    product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    PRIMARY KEY (promotion_id, product_id)
+);
+
+CREATE TABLE IF NOT EXISTS promotion_categories (
+    promotion_id INTEGER NOT NULL REFERENCES promotions(id) ON DELETE CASCADE,
+    category_id INTEGER NOT NULL REFERENCES categories(id) ON DELETE CASCADE,
+    PRIMARY KEY (promotion_id, category_id)
+);
+
+-- Cada uso de uma promoção por um pedido; released_at é preenchido quando o
+-- pedido é cancelado e o uso volta a ficar disponível
+CREATE TABLE IF NOT EXISTS promotion_redemptions (
+    id SERIAL PRIMARY KEY,
+    promotion_id INTEGER NOT NULL REFERENCES promotions(id) ON DELETE CASCADE,
+    order_id INTEGER NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    user_id INTEGER, -- sem FK, como em audit_events
+    discount NUMERIC(12,3) NOT NULL, -- na moeda do pedido
+    redeemed_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    released_at TIMESTAMPTZ
+);
+
+-- Pagamentos de pedidos: cada tentativa gera um intent no provedor
+CREATE TABLE IF NOT EXISTS payments (
+    id SERIAL PRIMARY KEY,
+    order_id INTEGER NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    provider VARCHAR(20) NOT NULL,
+    intent_id VARCHAR(100) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    currency CHAR(3) NOT NULL,
+    amount NUMERIC(12,3) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (provider, intent_id)
+);
+
+-- Webhooks de pagamento já processados, para ignorar reenvios do mesmo evento
+CREATE TABLE IF NOT EXISTS payment_webhook_events (
+    provider VARCHAR(20) NOT NULL,
+    event_id VARCHAR(100) NOT NULL,
+    event_type VARCHAR(50) NOT NULL,
+    received_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (provider, event_id)
+);
+
+-- Tokens de redefinição de senha: só o hash é guardado. Cada token vale uma
+-- única vez e até expires_at; mudar a senha invalida os tokens pendentes
+CREATE TABLE IF NOT EXISTS password_reset_tokens (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token_hash CHAR(64) NOT NULL UNIQUE, -- SHA-256 do token enviado por email
+    expires_at TIMESTAMPTZ NOT NULL,
+    used_at TIMESTAMPTZ, -- preenchido no uso ou na invalidação
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Segundo fator (TOTP) dos usuários; o segredo é cifrado com MFA_ENCRYPTION_KEY
+CREATE TABLE IF NOT EXISTS user_mfa (
+    user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    secret_encrypted TEXT NOT NULL,
+    confirmed_at TIMESTAMPTZ, -- NULL enquanto a inscrição não foi confirmada com um código
+    last_used_step BIGINT NOT NULL DEFAULT 0, -- passo do último código aceito, que não vale de novo
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Códigos de recuperação do segundo fator, de uso único
+CREATE TABLE IF NOT EXISTS mfa_recovery_codes (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    code_hash CHAR(64) NOT NULL, -- SHA-256 do código
+    used_at TIMESTAMPTZ
+);
+
+-- Chaves de API de usuários e contas de serviço, para integrações; só o
+-- SHA-256 da chave é guardado, o prefixo fica visível para identificá-la
+CREATE TABLE IF NOT EXISTS api_keys (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    prefix VARCHAR(16) NOT NULL UNIQUE, -- início da chave, ex.: gak_3f9a1c0b7d2e
+    key_hash CHAR(64) NOT NULL, -- SHA-256 da chave inteira
+    scopes TEXT[] NOT NULL, -- ex.: {products:read,products:write}
+    allowed_ips TEXT[] NOT NULL DEFAULT '{}', -- IPs e faixas CIDR aceitos; vazio aceita qualquer um
+    expires_at TIMESTAMPTZ, -- NULL para chaves que não expiram
+    last_used_at TIMESTAMPTZ, -- atualizado no máximo uma vez por minuto
+    last_used_ip VARCHAR(45),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    created_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    rotated_at TIMESTAMPTZ,
+    revoked_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_api_keys_user_id ON api_keys (user_id);
+
+-- Logins iniciados em um provedor OpenID Connect, aguardando a volta do
+-- usuário com o código; cada um vale uma única vez
+CREATE TABLE IF NOT EXISTS oidc_login_states (
+    state_hash CHAR(64) PRIMARY KEY, -- SHA-256 do state enviado ao provedor
+    provider VARCHAR(50) NOT NULL,
+    nonce VARCHAR(64) NOT NULL,
+    code_verifier VARCHAR(128) NOT NULL, -- verificador PKCE
+    link_user_id INTEGER REFERENCES users(id) ON DELETE CASCADE, -- usuário vinculando a identidade; NULL num login
+    expires_at TIMESTAMPTZ NOT NULL
+);
+
+-- Identidades de provedores externos vinculadas aos usuários
+CREATE TABLE IF NOT EXISTS user_identities (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    provider VARCHAR(50) NOT NULL,
+    subject VARCHAR(255) NOT NULL, -- "sub" do ID token
+    email VARCHAR(255) NOT NULL DEFAULT '', -- email informado pelo provedor no último login
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_login_at TIMESTAMPTZ,
+    UNIQUE (provider, subject),
+    UNIQUE (user_id, provider)
+);
+
+-- Aplicações de terceiros registradas como clientes OAuth2; só o SHA-256 do
+-- segredo é guardado, e clientes públicos não têm segredo
+CREATE TABLE IF NOT EXISTS oauth_clients (
+    id SERIAL PRIMARY KEY,
+    client_id VARCHAR(64) NOT NULL UNIQUE,
+    name VARCHAR(100) NOT NULL,
+    secret_hash CHAR(64), -- NULL para clientes públicos
+    redirect_uris TEXT[] NOT NULL DEFAULT '{}',
+    grant_types TEXT[] NOT NULL, -- authorization_code, client_credentials
+    scopes TEXT[] NOT NULL, -- escopos que o cliente pode receber
+    user_id INTEGER REFERENCES users(id) ON DELETE CASCADE, -- conta usada no client_credentials
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    created_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    revoked_at TIMESTAMPTZ
+);
+
+-- Consentimentos: escopos que cada usuário concedeu a cada cliente
+CREATE TABLE IF NOT EXISTS oauth_consents (
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    client_id VARCHAR(64) NOT NULL REFERENCES oauth_clients(client_id) ON DELETE CASCADE,
+    scopes TEXT[] NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, client_id)
+);
+
+-- Códigos de autorização aguardando a troca pelo token; cada um vale uma única vez
+CREATE TABLE IF NOT EXISTS oauth_codes (
+    code_hash CHAR(64) PRIMARY KEY, -- SHA-256 do código
+    client_id VARCHAR(64) NOT NULL REFERENCES oauth_clients(client_id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    redirect_uri TEXT NOT NULL,
+    scopes TEXT[] NOT NULL,
+    code_challenge VARCHAR(128) NOT NULL, -- desafio PKCE S256
+    mfa BOOLEAN NOT NULL DEFAULT FALSE, -- o login do usuário passou pelo segundo fator
+    expires_at TIMESTAMPTZ NOT NULL
+);
+
+-- Tokens de acesso emitidos aos clientes; só o SHA-256 do token é guardado
+CREATE TABLE IF NOT EXISTS oauth_tokens (
+    id SERIAL PRIMARY KEY,
+    token_hash CHAR(64) NOT NULL UNIQUE,
+    client_id VARCHAR(64) NOT NULL REFERENCES oauth_clients(client_id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    scopes TEXT[] NOT NULL,
+    mfa BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMPTZ NOT NULL,
+    revoked_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_oauth_tokens_client_user ON oauth_tokens (client_id, user_id);
+
+-- Caixa de saída de emails: gravados na mesma transação da mudança que os
+-- envia e entregues depois pelo mailer, com novas tentativas em caso de falha
+CREATE TABLE IF NOT EXISTS email_outbox (
+    id BIGSERIAL PRIMARY KEY,
+    recipient VARCHAR(255) NOT NULL,
+    subject TEXT NOT NULL,
+    body TEXT NOT NULL, -- apagado após o envio: pode conter links com tokens
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    sent_at TIMESTAMPTZ
+);
+
+-- Contadores de falhas de login por janela (conta ou IP), usados com
+-- THROTTLE_STORE=postgres; expiram sozinhos depois de duas janelas
+CREATE TABLE IF NOT EXISTS throttle_counters (
+    key VARCHAR(320) NOT NULL, -- account:<email> ou ip:<endereço>
+    window_start TIMESTAMPTZ NOT NULL,
+    count INTEGER NOT NULL DEFAULT 0,
+    expires_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (key, window_start)
+);
+
+-- Bloqueios em vigor: esperas progressivas e lockouts
+CREATE TABLE IF NOT EXISTS throttle_blocks (
+    key VARCHAR(320) PRIMARY KEY,
+    blocked_until TIMESTAMPTZ NOT NULL
+);
+
+-- Log de auditoria, somente inserção: cada evento guarda o hash do anterior,
+-- então editar ou apagar uma linha quebra a cadeia
+CREATE TABLE IF NOT EXISTS audit_events (
+    id BIGSERIAL PRIMARY KEY,
+    occurred_at TIMESTAMPTZ NOT NULL,
+    actor_id INTEGER, -- sem FK: o log sobrevive ao usuário; NULL em requisições anônimas
+    action VARCHAR(30) NOT NULL,
+    entity_type VARCHAR(30) NOT NULL,
+    entity_id TEXT NOT NULL,
+    changes JSON NOT NULL, -- JSON e não JSONB: o texto gravado é o que entrou no hash
+    request_id TEXT NOT NULL DEFAULT '',
+    ip TEXT NOT NULL DEFAULT '',
+    user_agent TEXT NOT NULL DEFAULT '',
+    prev_hash TEXT NOT NULL DEFAULT '', -- vazio no primeiro evento
+    hash TEXT NOT NULL
+);
+
+CREATE OR REPLACE FUNCTION audit_events_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS audit_events_append_only ON audit_events;
+CREATE TRIGGER audit_events_append_only BEFORE UPDATE OR DELETE ON audit_events
+    FOR EACH ROW EXECUTE FUNCTION audit_events_append_only();
+
 -- Inserção de alguns produtos de exemplo
 INSERT INTO products (product_name, price) VALUES 
     ('Produto Teste 1', 29.99),
@@ -20,6 +466,44 @@ INSERT INTO products (product_name, price) VALUES
     ('Produto Teste 3', 19.99)
 ON CONFLICT DO NOTHING;
 
+-- Abre o histórico de preços dos produtos que ainda não têm um
+INSERT INTO product_prices (product_id, price, kind, effective_from)
+SELECT p.id, p.price, 'regular', NOW() FROM products p
+WHERE NOT EXISTS (SELECT 1 FROM product_prices pp WHERE pp.product_id = p.id);
+
+-- Unicidade só entre registros fora da lixeira: o email de um usuário excluído
+-- continua reservado pela política do usecase, não pelo banco
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email_active ON users(email) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_products_sku_active ON products(sku) WHERE deleted_at IS NULL;
+
 -- Índices para melhor performance
 CREATE INDEX IF NOT EXISTS idx_products_name ON products(product_name);
 CREATE INDEX IF NOT EXISTS idx_products_price ON products(price);
+CREATE INDEX IF NOT EXISTS idx_categories_parent ON categories(parent_id);
+CREATE INDEX IF NOT EXISTS idx_categories_path ON categories(path text_pattern_ops);
+CREATE INDEX IF NOT EXISTS idx_product_categories_category ON product_categories(category_id);
+CREATE INDEX IF NOT EXISTS idx_product_prices_product ON product_prices(product_id, effective_from);
+CREATE INDEX IF NOT EXISTS idx_product_variants_product ON product_variants(product_id);
+CREATE INDEX IF NOT EXISTS idx_product_images_product ON product_images(product_id, position);
+CREATE INDEX IF NOT EXISTS idx_price_list_items_product ON price_list_items(product_id);
+CREATE INDEX IF NOT EXISTS idx_users_deleted ON users(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_products_deleted ON products(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_users_updated ON users(updated_at);
+CREATE INDEX IF NOT EXISTS idx_products_updated ON products(updated_at);
+CREATE INDEX IF NOT EXISTS idx_orders_user ON orders(user_id, id);
+CREATE INDEX IF NOT EXISTS idx_orders_status ON orders(status, id);
+CREATE INDEX IF NOT EXISTS idx_order_items_order ON order_items(order_id);
+CREATE INDEX IF NOT EXISTS idx_order_taxes_order ON order_taxes(order_id, id);
+CREATE INDEX IF NOT EXISTS idx_order_status_history_order ON order_status_history(order_id, id);
+CREATE INDEX IF NOT EXISTS idx_payments_order ON payments(order_id, id);
+CREATE INDEX IF NOT EXISTS idx_promotions_automatic ON promotions(id) WHERE code IS NULL AND active;
+CREATE INDEX IF NOT EXISTS idx_promotion_redemptions_user ON promotion_redemptions(promotion_id, user_id) WHERE released_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_promotion_redemptions_order ON promotion_redemptions(order_id);
+CREATE INDEX IF NOT EXISTS idx_password_reset_tokens_user ON password_reset_tokens(user_id) WHERE used_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_mfa_recovery_codes_user ON mfa_recovery_codes(user_id, code_hash) WHERE used_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_email_outbox_due ON email_outbox(next_attempt_at, id) WHERE sent_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_throttle_counters_expires ON throttle_counters(expires_at);
+CREATE INDEX IF NOT EXISTS idx_throttle_blocks_until ON throttle_blocks(blocked_until);
+CREATE INDEX IF NOT EXISTS idx_audit_events_actor ON audit_events(actor_id, id);
+CREATE INDEX IF NOT EXISTS idx_audit_events_entity ON audit_events(entity_type, entity_id, id);
+CREATE INDEX IF NOT EXISTS idx_audit_events_occurred ON audit_events(occurred_at);
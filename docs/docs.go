@@ -24,45 +24,54 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/login": {
-            "post": {
-                "description": "Authenticate a user and return a JWT token",
-                "consumes": [
-                    "application/json"
+        "/api-keys": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "List the API keys, revoked ones included, with their last use; never their secrets",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "auth"
                 ],
-                "summary": "User login",
+                "summary": "List API keys",
                 "parameters": [
                     {
-                        "description": "User credentials",
-                        "name": "credentials",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.LoginRequest"
-                        }
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Only the keys of this user",
+                        "name": "user_id",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Login successful",
+                        "description": "API keys",
                         "schema": {
-                            "$ref": "#/definitions/dto.LoginResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.APIKeyResponse"
+                            }
                         }
                     },
                     "400": {
-                        "description": "Bad request - Invalid input data",
+                        "description": "Bad request - Invalid user_id",
                         "schema": {
                             "$ref": "#/definitions/model.Response"
                         }
                     },
                     "401": {
-                        "description": "Unauthorized - Invalid credentials",
+                        "description": "Missing or invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin role required",
                         "schema": {
                             "$ref": "#/definitions/model.Response"
                         }
@@ -74,11 +83,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/product": {
+            },
             "post": {
-                "description": "Create a new product with the provided information",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a key acting as an admin or a service account, limited to its scopes and, optionally, to some IPs and until a date. The key is returned only once; send it in the X-API-Key header or as a bearer token",
                 "consumes": [
                     "application/json"
                 ],
@@ -86,63 +98,49 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "products"
+                    "auth"
                 ],
-                "summary": "Create a new product",
+                "summary": "Create an API key",
                 "parameters": [
                     {
-                        "description": "Product information",
-                        "name": "product",
+                        "description": "Owner and limits of the key",
+                        "name": "apiKey",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.CreateProductRequest"
+                            "$ref": "#/definitions/dto.CreateAPIKeyRequest"
                         }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "Product created successfully",
+                        "description": "Key created",
                         "schema": {
-                            "$ref": "#/definitions/dto.ProductResponse"
+                            "$ref": "#/definitions/dto.APIKeySecretResponse"
                         }
                     },
                     "400": {
-                        "description": "Bad request - Invalid input data",
+                        "description": "Bad request - Invalid scope, allowed IP or expiry, or owner not an admin or service account",
                         "schema": {
                             "$ref": "#/definitions/model.Response"
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "401": {
+                        "description": "Missing or invalid token",
                         "schema": {
                             "$ref": "#/definitions/model.Response"
                         }
-                    }
-                }
-            }
-        },
-        "/products": {
-            "get": {
-                "description": "Get a list of all products in the system",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "products"
-                ],
-                "summary": "List all products",
-                "responses": {
-                    "200": {
-                        "description": "List of products",
+                    },
+                    "403": {
+                        "description": "Admin role required",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/dto.ProductResponse"
-                            }
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
                         }
                     },
                     "500": {
@@ -154,35 +152,34 @@ const docTemplate = `{
                 }
             }
         },
-        "/products/{productId}": {
-            "get": {
-                "description": "Get a specific product by its ID",
-                "consumes": [
-                    "application/json"
+        "/api-keys/{keyId}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Turn the key off for good. It stays listed, with the time it was revoked",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "products"
+                    "auth"
                 ],
-                "summary": "Get product by ID",
+                "summary": "Revoke an API key",
                 "parameters": [
                     {
                         "minimum": 1,
                         "type": "integer",
-                        "description": "Product ID",
-                        "name": "productId",
+                        "description": "API key ID",
+                        "name": "keyId",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Product found",
-                        "schema": {
-                            "$ref": "#/definitions/dto.ProductResponse"
-                        }
+                    "204": {
+                        "description": "Key revoked"
                     },
                     "400": {
                         "description": "Bad request - Invalid ID format",
@@ -190,8 +187,26 @@ const docTemplate = `{
                             "$ref": "#/definitions/model.Response"
                         }
                     },
+                    "401": {
+                        "description": "Missing or invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin role required",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
                     "404": {
-                        "description": "Product not found",
+                        "description": "API key not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "API key already revoked",
                         "schema": {
                             "$ref": "#/definitions/model.Response"
                         }
@@ -205,39 +220,64 @@ const docTemplate = `{
                 }
             }
         },
-        "/user": {
+        "/api-keys/{keyId}/rotate": {
             "post": {
-                "description": "Create a new user with the provided information",
-                "consumes": [
-                    "application/json"
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Give the key a new secret, keeping its owner, scopes, allowed IPs and expiry. The old secret stops working at once; the new key is returned only once",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "auth"
                 ],
-                "summary": "Create a new user",
+                "summary": "Rotate an API key",
                 "parameters": [
                     {
-                        "description": "User information",
-                        "name": "user",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.CreateUserRequest"
-                        }
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "API key ID",
+                        "name": "keyId",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "User created successfully",
+                    "200": {
+                        "description": "Key rotated",
                         "schema": {
-                            "$ref": "#/definitions/dto.UserResponse"
+                            "$ref": "#/definitions/dto.APIKeySecretResponse"
                         }
                     },
                     "400": {
-                        "description": "Bad request - Invalid input data",
+                        "description": "Bad request - Invalid ID format",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin role required",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "API key not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "API key revoked",
                         "schema": {
                             "$ref": "#/definitions/model.Response"
                         }
@@ -251,29 +291,98 @@ const docTemplate = `{
                 }
             }
         },
-        "/users": {
+        "/audit": {
             "get": {
-                "description": "Get a list of all users in the system",
-                "consumes": [
-                    "application/json"
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Get the changes made to users and products, newest first. Page backwards with before_id set to the last id received",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "audit"
+                ],
+                "summary": "List audit events",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User who made the change",
+                        "name": "actor_id",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "user",
+                            "product",
+                            "product_import"
+                        ],
+                        "type": "string",
+                        "description": "Entity type",
+                        "name": "entity_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Entity ID, with entity_type",
+                        "name": "entity_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start of the time range, inclusive (RFC 3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End of the time range, exclusive (RFC 3339)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only events older than this one",
+                        "name": "before_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, default 100, at most 1000",
+                        "name": "limit",
+                        "in": "query"
+                    }
                 ],
-                "summary": "List all users",
                 "responses": {
                     "200": {
-                        "description": "List of users",
+                        "description": "Audit events",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/dto.UserResponse"
+                                "$ref": "#/definitions/model.AuditEvent"
                             }
                         }
                     },
+                    "400": {
+                        "description": "Bad request - Invalid filter",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -283,44 +392,36 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/{userId}": {
+        "/audit/verify": {
             "get": {
-                "description": "Get a specific user by their ID",
-                "consumes": [
-                    "application/json"
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Recompute the hash chain of the whole audit log and report the first event that was edited or follows a removed one. Keep last_hash elsewhere to also detect events removed from the end",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users"
-                ],
-                "summary": "Get user by ID",
-                "parameters": [
-                    {
-                        "minimum": 1,
-                        "type": "integer",
-                        "description": "User ID",
-                        "name": "userId",
-                        "in": "path",
-                        "required": true
-                    }
+                    "audit"
                 ],
+                "summary": "Verify the audit log",
                 "responses": {
                     "200": {
-                        "description": "User found",
+                        "description": "Verification result",
                         "schema": {
-                            "$ref": "#/definitions/dto.UserResponse"
+                            "$ref": "#/definitions/model.AuditVerification"
                         }
                     },
-                    "400": {
-                        "description": "Bad request - Invalid ID format",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/model.Response"
                         }
                     },
-                    "404": {
-                        "description": "User not found",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/model.Response"
                         }
@@ -332,9 +433,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
-                "description": "Update an existing user's information",
+            }
+        },
+        "/auth/email/resend": {
+            "post": {
+                "description": "Email a new verification link to the pending email of the account, or to its email while unverified. The answer is the same whether or not the email has an account",
                 "consumes": [
                     "application/json"
                 ],
@@ -342,40 +445,35 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "auth"
                 ],
-                "summary": "Update a user",
+                "summary": "Ask for a new verification email",
                 "parameters": [
                     {
-                        "minimum": 1,
-                        "type": "integer",
-                        "description": "User ID",
-                        "name": "userId",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "User information",
-                        "name": "user",
+                        "description": "Email of the account",
+                        "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.UpdateUserRequest"
+                            "$ref": "#/definitions/dto.ResendVerificationRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "User updated successfully"
+                    "202": {
+                        "description": "Request accepted",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
                     },
                     "400": {
-                        "description": "Bad request - Invalid input data",
+                        "description": "Bad request - Invalid email",
                         "schema": {
                             "$ref": "#/definitions/model.Response"
                         }
                     },
-                    "404": {
-                        "description": "User not found",
+                    "429": {
+                        "description": "Too many requests from the IP; the Retry-After header tells when to try again",
                         "schema": {
                             "$ref": "#/definitions/model.Response"
                         }
@@ -387,35 +485,349 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Delete a user by their ID",
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/auth/email/verify": {
+            "get": {
+                "description": "Confirm the email of a verification link. On sign up it marks the email verified; on an email change it replaces the email with the pending one",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "auth"
                 ],
-                "summary": "Delete a user",
+                "summary": "Verify an email",
                 "parameters": [
                     {
-                        "minimum": 1,
-                        "type": "integer",
-                        "description": "User ID",
-                        "name": "userId",
-                        "in": "path",
+                        "type": "string",
+                        "description": "Token of the verification email",
+                        "name": "token",
+                        "in": "query",
                         "required": true
                     }
                 ],
+                "responses": {
+                    "200": {
+                        "description": "Email verified",
+                        "schema": {
+                            "$ref": "#/definitions/dto.UserResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - Invalid, expired or outdated token",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "The pending email was taken in the meantime",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/lockouts": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lift the lockout, or the wait, of an account and/or an IP and forget their failed logins. The unlock is recorded in the audit log",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Clear a login lockout",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Email of the account",
+                        "name": "email",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "IP address",
+                        "name": "ip",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "204": {
-                        "description": "User deleted successfully"
+                        "description": "Lockout cleared"
                     },
                     "400": {
-                        "description": "Bad request - Invalid ID format",
+                        "description": "Bad request - Neither email nor ip given",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin role required",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/mfa": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Remove the TOTP secret and the recovery codes of the logged in user. Takes a code of the authenticator app or a recovery code",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Turn two-factor authentication off",
+                "parameters": [
+                    {
+                        "description": "Code of the authenticator app or recovery code",
+                        "name": "code",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.MFACodeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Two-factor authentication disabled"
+                    },
+                    "400": {
+                        "description": "Bad request - Invalid input data",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token, or wrong code",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Two-factor authentication not enabled",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many wrong codes; the Retry-After header tells when to try again",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/mfa/confirm": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Turn two-factor authentication on with a code of the authenticator app and return the recovery codes, each usable once in place of a code. They are not shown again",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Confirm two-factor enrollment",
+                "parameters": [
+                    {
+                        "description": "Code of the authenticator app",
+                        "name": "code",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.MFACodeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Two-factor authentication enabled",
+                        "schema": {
+                            "$ref": "#/definitions/dto.MFARecoveryCodesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - Invalid input data",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token, or wrong code",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Nothing to confirm, or already enabled",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many wrong codes; the Retry-After header tells when to try again",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/mfa/enroll": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a TOTP secret for the logged in user, as text and as an otpauth URI to show as a QR code. It replaces a previous enrollment not confirmed yet and only takes effect once confirmed with a code",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Start two-factor enrollment",
+                "responses": {
+                    "200": {
+                        "description": "Secret created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.MFAEnrollResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Two-factor authentication already enabled",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/mfa/recovery-codes": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Discard the recovery codes of the logged in user, used or not, and return new ones. Takes a code of the authenticator app or a recovery code",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Replace the recovery codes",
+                "parameters": [
+                    {
+                        "description": "Code of the authenticator app or recovery code",
+                        "name": "code",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.MFACodeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "New recovery codes",
+                        "schema": {
+                            "$ref": "#/definitions/dto.MFARecoveryCodesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - Invalid input data",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token, or wrong code",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Two-factor authentication not enabled",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many wrong codes; the Retry-After header tells when to try again",
                         "schema": {
                             "$ref": "#/definitions/model.Response"
                         }
@@ -428,95 +840,8518 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "dto.CreateProductRequest": {
+        },
+        "/auth/mfa/verify": {
+            "post": {
+                "description": "Exchange the mfa_token returned by /login, valid for 5 minutes, and a code of the authenticator app or a recovery code for the JWT token. The anonymous cart of cart_token (or of the X-Cart-Token header) is merged into the user's cart. Wrong codes count as failed logins",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Finish a two-factor login",
+                "parameters": [
+                    {
+                        "description": "Challenge and code",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.MFAVerifyRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Token of the anonymous cart to merge",
+                        "name": "X-Cart-Token",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Login successful",
+                        "schema": {
+                            "$ref": "#/definitions/dto.LoginResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - Invalid input data",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid or expired mfa_token, or wrong code",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many wrong codes; the Retry-After header tells when to try again",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/oidc/{provider}/authorize": {
+            "get": {
+                "description": "Return the URL of the provider to send the user to and the state it sends back to the callback, valid for 10 minutes. The client must keep the state and only call the callback with the state it started, so nobody can sign it in to another account",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Start a sign in with an identity provider",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of the identity provider",
+                        "name": "provider",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Sign in started",
+                        "schema": {
+                            "$ref": "#/definitions/dto.OIDCAuthorizeResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Identity provider unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Unknown identity provider",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/oidc/{provider}/callback": {
+            "get": {
+                "description": "Exchange the code the provider sent back for the JWT token, as a password login does: a user with two-factor authentication gets an MFA challenge unless the provider reports a login with more than one factor. Takes the query parameters of the redirect or the same fields as a JSON body. An identity not linked yet is linked when the sign in was started by link, or to the user with the same verified email when the provider is trusted to. The anonymous cart of cart_token (or of the X-Cart-Token header) is merged into the user's cart",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Finish a sign in with an identity provider",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of the identity provider",
+                        "name": "provider",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Code sent by the provider",
+                        "name": "code",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "State returned by authorize or link",
+                        "name": "state",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Token of the anonymous cart to merge",
+                        "name": "X-Cart-Token",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Login successful",
+                        "schema": {
+                            "$ref": "#/definitions/dto.LoginResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Missing, unknown, used or expired state",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Sign in failed at the provider, or no account linked to the identity",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Unknown identity provider",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Identity linked to another account, or the account has one of this provider",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Exchange the code the provider sent back for the JWT token, as a password login does: a user with two-factor authentication gets an MFA challenge unless the provider reports a login with more than one factor. Takes the query parameters of the redirect or the same fields as a JSON body. An identity not linked yet is linked when the sign in was started by link, or to the user with the same verified email when the provider is trusted to. The anonymous cart of cart_token (or of the X-Cart-Token header) is merged into the user's cart",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Finish a sign in with an identity provider",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of the identity provider",
+                        "name": "provider",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Code sent by the provider",
+                        "name": "code",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "State returned by authorize or link",
+                        "name": "state",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Token of the anonymous cart to merge",
+                        "name": "X-Cart-Token",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Login successful",
+                        "schema": {
+                            "$ref": "#/definitions/dto.LoginResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Missing, unknown, used or expired state",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Sign in failed at the provider, or no account linked to the identity",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Unknown identity provider",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Identity linked to another account, or the account has one of this provider",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/oidc/{provider}/link": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Like authorize, for the logged in user: the callback, called with the same token, links the identity to the account and signs in with it",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Start linking an identity provider to the account",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of the identity provider",
+                        "name": "provider",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Linking started",
+                        "schema": {
+                            "$ref": "#/definitions/dto.OIDCAuthorizeResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token, or identity provider unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Unknown identity provider",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Remove the identity of the provider from the logged in user, who keeps signing in with the password",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Unlink an identity provider from the account",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of the identity provider",
+                        "name": "provider",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Identity unlinked"
+                    },
+                    "401": {
+                        "description": "Missing or invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Unknown identity provider, or none linked",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/password/forgot": {
+            "post": {
+                "description": "Email a single-use link to choose a new password, valid for a short time. The answer is the same whether or not the email has an account",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Ask for a password reset email",
+                "parameters": [
+                    {
+                        "description": "Email of the account",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ForgotPasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Request accepted",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - Invalid email",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests for the email or from the IP; the Retry-After header tells when to try again",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/password/reset": {
+            "post": {
+                "description": "Set a new password with the token of the reset email. The token works once; the new password also invalidates the other tokens of the user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Choose a new password",
+                "parameters": [
+                    {
+                        "description": "Token and new password",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResetPasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Password changed"
+                    },
+                    "400": {
+                        "description": "Bad request - Invalid input data, a password breaking the strength rules, or an invalid, used or expired token",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/cart": {
+            "get": {
+                "description": "Get the cart of the authenticated user or, without a token, of the anonymous session in X-Cart-Token, priced at the current prices",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cart"
+                ],
+                "summary": "Get the cart",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Token of the anonymous cart",
+                        "name": "X-Cart-Token",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Cart",
+                        "schema": {
+                            "$ref": "#/definitions/dto.CartResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/cart/items": {
+            "post": {
+                "description": "Add a product, or one of its variants, to the cart. Adding an item already in the cart adds up the quantities and refreshes its price. Anonymous requests without X-Cart-Token get a new cart whose token is returned in the X-Cart-Token header",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cart"
+                ],
+                "summary": "Add an item to the cart",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Token of the anonymous cart",
+                        "name": "X-Cart-Token",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Item to add",
+                        "name": "item",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.AddCartItemRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Cart after the change",
+                        "schema": {
+                            "$ref": "#/definitions/dto.CartResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - Invalid input data or variant",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Product or variant not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Not enough stock",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/cart/items/{itemId}": {
+            "put": {
+                "description": "Replace the quantity of an item of the cart; its added price is kept",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cart"
+                ],
+                "summary": "Change the quantity of a cart item",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Token of the anonymous cart",
+                        "name": "X-Cart-Token",
+                        "in": "header"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Cart item ID",
+                        "name": "itemId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New quantity",
+                        "name": "item",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateCartItemRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Cart after the change",
+                        "schema": {
+                            "$ref": "#/definitions/dto.CartResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - Invalid input data",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Item not found in the cart",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Not enough stock",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Remove an item from the cart",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cart"
+                ],
+                "summary": "Remove an item from the cart",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Token of the anonymous cart",
+                        "name": "X-Cart-Token",
+                        "in": "header"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Cart item ID",
+                        "name": "itemId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Cart after the change",
+                        "schema": {
+                            "$ref": "#/definitions/dto.CartResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - Invalid ID format",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Item not found in the cart",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/cart/pricing": {
+            "get": {
+                "description": "Apply the automatic promotions and the given discount codes to the available items of the cart, without redeeming them. The breakdown shows the discount of each promotion on each item and why each rejected code was not applied. With a destination country, the discounted items are taxed and the taxes itemized per line",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cart"
+                ],
+                "summary": "Price the cart with promotions and taxes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Token of the anonymous cart",
+                        "name": "X-Cart-Token",
+                        "in": "header"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Discount codes to try",
+                        "name": "code",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ISO 3166-1 alpha-2 country of the destination, to tax the items",
+                        "name": "country",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "State of the destination",
+                        "name": "state",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Price breakdown",
+                        "schema": {
+                            "$ref": "#/definitions/dto.CartPricingResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Items in more than one currency, invalid destination or destination without tax rates",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/categories": {
+            "get": {
+                "description": "Get every category nested under its parent",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "categories"
+                ],
+                "summary": "Get the category tree",
+                "responses": {
+                    "200": {
+                        "description": "Category tree",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.CategoryResponse"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/categories/{categoryId}": {
+            "get": {
+                "description": "Get a category together with its subtree",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "categories"
+                ],
+                "summary": "Get category by ID",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Category ID",
+                        "name": "categoryId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Category found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.CategoryResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - Invalid ID format",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Category not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Rename a category. Changing the slug updates the path of the whole subtree",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "categories"
+                ],
+                "summary": "Update a category",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Category ID",
+                        "name": "categoryId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Category information",
+                        "name": "category",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateCategoryRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Category updated successfully",
+                        "schema": {
+                            "$ref": "#/definitions/dto.CategoryResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - Invalid input data",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin role required, or API key or access token without the scope of the route",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Category not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Slug already used by a sibling, or category changed by another request",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Delete a category without subcategories. Products are only unassigned from it",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "categories"
+                ],
+                "summary": "Delete a category",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Category ID",
+                        "name": "categoryId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Category deleted successfully"
+                    },
+                    "400": {
+                        "description": "Bad request - Invalid ID format",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin role required, or API key or access token without the scope of the route",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Category not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Category has subcategories",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/categories/{categoryId}/move": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Move a category, with all its descendants, under another parent or to the root",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "categories"
+                ],
+                "summary": "Move a category subtree",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Category ID",
+                        "name": "categoryId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New parent",
+                        "name": "move",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.MoveCategoryRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Category moved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/dto.CategoryResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - Invalid input data or cyclic move",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin role required, or API key or access token without the scope of the route",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Category not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Slug already used under the new parent, or category or parent changed by another request",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/category": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Create a root category or a subcategory of an existing one",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "categories"
+                ],
+                "summary": "Create a new category",
+                "parameters": [
+                    {
+                        "description": "Category information",
+                        "name": "category",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateCategoryRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Category created successfully",
+                        "schema": {
+                            "$ref": "#/definitions/dto.CategoryResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - Invalid input data",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin role required, or API key or access token without the scope of the route",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Parent category not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Slug already used by a sibling",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/checkout": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Order the given items or, without items, the whole cart of the authenticated user, which is then emptied. Prices are the ones in effect now, the automatic promotions and the given discount codes are applied and redeemed, names and prices are kept on the order as they are, and the variant stock is taken at once. Every item must share the same currency",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Place an order",
+                "parameters": [
+                    {
+                        "description": "Items to order, the cart when empty, discount codes and the destination to tax the items",
+                        "name": "checkout",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/dto.CheckoutRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Order placed",
+                        "schema": {
+                            "$ref": "#/definitions/dto.OrderResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - Empty cart, invalid items, mixed currencies, rejected discount code or destination without tax rates",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Product or variant not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Not enough stock, cart item no longer available or promotion used up meanwhile",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/exchange-rates": {
+            "get": {
+                "description": "Get the exchange rates with their source and last update time",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pricing"
+                ],
+                "summary": "List exchange rates",
+                "responses": {
+                    "200": {
+                        "description": "Exchange rates",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.ExchangeRateResponse"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Insert or replace exchange rates. All rates are saved or none is",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pricing"
+                ],
+                "summary": "Update exchange rates",
+                "parameters": [
+                    {
+                        "description": "Exchange rates",
+                        "name": "rates",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.SetExchangeRatesRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Saved exchange rates",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.ExchangeRateResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - Invalid input data",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin role required",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/exchange-rates/import": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Load rates from a CSV with the columns base,quote,rate and an optional RFC 3339 updated_at. The file is sent as the multipart field \"file\" or as the raw request body",
+                "consumes": [
+                    "multipart/form-data",
+                    "text/csv"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pricing"
+                ],
+                "summary": "Import exchange rates from a CSV file",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "CSV file",
+                        "name": "file",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Rates imported successfully",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ImportExchangeRatesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - Invalid file",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin role required",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/login": {
+            "post": {
+                "description": "Authenticate a user and return a JWT token. The anonymous cart of cart_token (or of the X-Cart-Token header) is merged into the user's cart. A user with two-factor authentication gets mfa_required and an mfa_token instead, to send with a code to /auth/mfa/verify. Repeated failures make the account and the IP wait, longer after each failure, and then lock them out for a while",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "User login",
+                "parameters": [
+                    {
+                        "description": "User credentials",
+                        "name": "credentials",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.LoginRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Token of the anonymous cart to merge",
+                        "name": "X-Cart-Token",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Login successful",
+                        "schema": {
+                            "$ref": "#/definitions/dto.LoginResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - Invalid input data",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid credentials",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Email not verified, when verification is required",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many failed logins; the Retry-After header tells when to try again",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/identities": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the identities at identity providers linked to the logged in user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "List the linked identity providers",
+                "responses": {
+                    "200": {
+                        "description": "Linked identities",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.UserIdentityResponse"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/oauth/consents": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the OAuth clients the logged in user granted access to, with the scopes granted",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "oauth"
+                ],
+                "summary": "List the applications granted access",
+                "responses": {
+                    "200": {
+                        "description": "Consents",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.OAuthConsentResponse"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/oauth/consents/{clientId}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Withdraw the consent the logged in user gave a client: its access tokens for the user stop working and the next authorization asks again",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "oauth"
+                ],
+                "summary": "Revoke the access of an application",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Client ID",
+                        "name": "clientId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Access revoked"
+                    },
+                    "401": {
+                        "description": "Missing or invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "No consent given to the client",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/orders": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the orders of the authenticated user, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "List my orders",
+                "responses": {
+                    "200": {
+                        "description": "Orders",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.OrderResponse"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Access token without the scope of the route",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/orders/{orderId}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get an order of the authenticated user with its status history",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Get one of my orders",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Order ID",
+                        "name": "orderId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Order",
+                        "schema": {
+                            "$ref": "#/definitions/dto.OrderResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - Invalid ID format",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Access token without the scope of the route",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Order not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/orders/{orderId}/cancel": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Cancel an order of the authenticated user while it is pending; its stock is given back",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Cancel one of my orders",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Order ID",
+                        "name": "orderId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Cancelled order",
+                        "schema": {
+                            "$ref": "#/definitions/dto.OrderResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - Invalid ID format",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Access token without the scope of the route",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Order not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "The order is no longer pending",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/orders/{orderId}/pay": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Charge the total of a pending order of the authenticated user and mark it paid. Repeating the request after a timeout does not charge twice",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Pay one of my orders",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Order ID",
+                        "name": "orderId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Payment",
+                        "schema": {
+                            "$ref": "#/definitions/dto.PaymentResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - Invalid ID format",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "402": {
+                        "description": "Payment declined",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Access token without the scope of the route",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Order not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "The order is not pending",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "504": {
+                        "description": "Payment provider did not answer in time",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/oauth/authorize": {
+            "get": {
+                "description": "Validate the query of an authorization request (RFC 6749, 4.1.1) for the consent page: the client, its registered redirect URI and a S256 code_challenge are required. Tells the application and scopes asked for, and whether the logged in user already granted them. Errors are never sent to the redirect URI; show them to the user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "oauth"
+                ],
+                "summary": "Check an authorization request",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Must be code",
+                        "name": "response_type",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Client ID",
+                        "name": "client_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Redirect URI registered for the client",
+                        "name": "redirect_uri",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Scopes asked for, separated by spaces; all the scopes of the client when empty",
+                        "name": "scope",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Value sent back to the redirect URI",
+                        "name": "state",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "PKCE code challenge",
+                        "name": "code_challenge",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Must be S256",
+                        "name": "code_challenge_method",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Valid request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.OAuthAuthorizationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request, with an OAuth error code",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unknown or revoked client, or invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Approve or refuse the request checked by GET /oauth/authorize, then send the user to the redirect_uri returned, carrying a code valid for a minute or error=access_denied, with the state. A logged in user approves with its token; others sign in with email and password, as at /login. A user with two-factor authentication then gets an MFA challenge, to exchange at /auth/mfa/verify before approving again with the token. Approving records the consent of the user to the scopes",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "oauth"
+                ],
+                "summary": "Answer an authorization request",
+                "parameters": [
+                    {
+                        "description": "Authorization request and answer",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.OAuthApproveRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Redirect URI, or MFA challenge",
+                        "schema": {
+                            "$ref": "#/definitions/dto.OAuthAuthorizeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request, with an OAuth error code",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unknown or revoked client, invalid token or credentials, or no login",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many failed logins; the Retry-After header tells when to try again",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/oauth/clients": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the registered clients, revoked ones included; never their secrets",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "oauth"
+                ],
+                "summary": "List OAuth clients",
+                "responses": {
+                    "200": {
+                        "description": "Clients",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.OAuthClientResponse"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin role required",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Register a third-party application. Confidential clients get a secret, returned only once; public clients, such as mobile apps, get none and can only use authorization_code with PKCE. client_credentials acts as the admin or service account of user_id, limited to the scopes of the client",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "oauth"
+                ],
+                "summary": "Register an OAuth client",
+                "parameters": [
+                    {
+                        "description": "Client to register",
+                        "name": "client",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateOAuthClientRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Client registered",
+                        "schema": {
+                            "$ref": "#/definitions/dto.OAuthClientSecretResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - Invalid grant type, scope or redirect URI, or client_credentials without a confidential client acting as an admin or service account",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin role required",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/oauth/clients/{clientId}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Turn a client off for good: its access tokens stop working and it cannot get new ones",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "oauth"
+                ],
+                "summary": "Revoke an OAuth client",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Client ID",
+                        "name": "clientId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Client revoked"
+                    },
+                    "401": {
+                        "description": "Missing or invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin role required",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Client not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Client already revoked",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/oauth/introspect": {
+            "post": {
+                "description": "Introspection endpoint (RFC 7662): tell whether an access token of the authenticated client is active, with its scopes and user. Tokens of other clients are reported inactive",
+                "consumes": [
+                    "application/x-www-form-urlencoded"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "oauth"
+                ],
+                "summary": "Introspect an access token",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Access token",
+                        "name": "token",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Ignored; only access tokens exist",
+                        "name": "token_type_hint",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Client ID, unless sent with HTTP Basic",
+                        "name": "client_id",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Client secret, unless sent with HTTP Basic",
+                        "name": "client_secret",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "State of the token",
+                        "schema": {
+                            "$ref": "#/definitions/dto.OAuthIntrospectionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.OAuthErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Client authentication failed",
+                        "schema": {
+                            "$ref": "#/definitions/dto.OAuthErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.OAuthErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/oauth/revoke": {
+            "post": {
+                "description": "Revocation endpoint (RFC 7009): turn off an access token of the authenticated client. Answers 200 for unknown tokens too",
+                "consumes": [
+                    "application/x-www-form-urlencoded"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "oauth"
+                ],
+                "summary": "Revoke an access token",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Access token",
+                        "name": "token",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Ignored; only access tokens exist",
+                        "name": "token_type_hint",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Client ID, unless sent with HTTP Basic",
+                        "name": "client_id",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Client secret, unless sent with HTTP Basic",
+                        "name": "client_secret",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Token revoked, or unknown"
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.OAuthErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Client authentication failed",
+                        "schema": {
+                            "$ref": "#/definitions/dto.OAuthErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.OAuthErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/oauth/token": {
+            "post": {
+                "description": "Token endpoint (RFC 6749, 3.2). Exchange an authorization code with its code_verifier, or get a token as the account of the client with client_credentials. Confidential clients authenticate with HTTP Basic or client_id and client_secret in the form; public clients send only client_id. Send the token as a bearer token: it works on the routes its scopes allow",
+                "consumes": [
+                    "application/x-www-form-urlencoded"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "oauth"
+                ],
+                "summary": "Issue an access token",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "authorization_code or client_credentials",
+                        "name": "grant_type",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Authorization code",
+                        "name": "code",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Redirect URI of the authorization request",
+                        "name": "redirect_uri",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "PKCE code verifier",
+                        "name": "code_verifier",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Scopes asked for with client_credentials, separated by spaces",
+                        "name": "scope",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Client ID, unless sent with HTTP Basic",
+                        "name": "client_id",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Client secret, unless sent with HTTP Basic",
+                        "name": "client_secret",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Token issued",
+                        "schema": {
+                            "$ref": "#/definitions/dto.OAuthTokenResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request, grant or scope",
+                        "schema": {
+                            "$ref": "#/definitions/dto.OAuthErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Client authentication failed",
+                        "schema": {
+                            "$ref": "#/definitions/dto.OAuthErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.OAuthErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/option-type": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create an option type with its values in display order",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "variants"
+                ],
+                "summary": "Create an option type",
+                "parameters": [
+                    {
+                        "description": "Option type information",
+                        "name": "optionType",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateOptionTypeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Option type created successfully",
+                        "schema": {
+                            "$ref": "#/definitions/dto.OptionTypeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - Invalid input data",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin role required",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Name or value already used",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/option-types": {
+            "get": {
+                "description": "Get every option type (size, color, ...) with its values",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "variants"
+                ],
+                "summary": "List option types",
+                "responses": {
+                    "200": {
+                        "description": "Option types",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.OptionTypeResponse"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/option-types/{optionTypeId}/values": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Append a value after the existing values of the option type",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "variants"
+                ],
+                "summary": "Add a value to an option type",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Option type ID",
+                        "name": "optionTypeId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Option value",
+                        "name": "value",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.AddOptionValueRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Value added successfully",
+                        "schema": {
+                            "$ref": "#/definitions/dto.OptionValueResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - Invalid input data",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin role required",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Option type not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Value already exists",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Get the orders of every user, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "List orders",
+                "parameters": [
+                    {
+                        "enum": [
+                            "pending",
+                            "paid",
+                            "shipped",
+                            "delivered",
+                            "cancelled",
+                            "refunded"
+                        ],
+                        "type": "string",
+                        "description": "Only orders in this status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only orders of this user",
+                        "name": "user_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Orders",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.OrderResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - Invalid filter",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin role required, or API key or access token without the scope of the route",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/{orderId}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Get any order with its status history",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Get an order",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Order ID",
+                        "name": "orderId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Order",
+                        "schema": {
+                            "$ref": "#/definitions/dto.OrderResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - Invalid ID format",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin role required, or API key or access token without the scope of the route",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Order not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/{orderId}/payments": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get every attempt to pay the order, oldest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "List the payments of an order",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Order ID",
+                        "name": "orderId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Payments",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.PaymentResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - Invalid ID format",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Order not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/{orderId}/refund": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Give back the captured payment of a paid or delivered order and mark it refunded",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Refund an order",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Order ID",
+                        "name": "orderId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Refunded order",
+                        "schema": {
+                            "$ref": "#/definitions/dto.OrderResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - Invalid ID format",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Order not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "The order cannot be refunded or has no captured payment",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "504": {
+                        "description": "Payment provider did not answer in time",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/{orderId}/status": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Move an order to a fulfilment status. Allowed: pending to cancelled, paid to shipped, shipped to delivered. Paid and refunded follow the payments: an order is paid through its payment, and refunded with POST /orders/{orderId}/refund. Cancelling gives the stock back",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Change the status of an order",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Order ID",
+                        "name": "orderId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New status",
+                        "name": "transition",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.TransitionOrderRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Order after the change",
+                        "schema": {
+                            "$ref": "#/definitions/dto.OrderResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - Invalid status, or paid or refunded",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin role required, or API key or access token without the scope of the route",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Order not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Transition not allowed from the current status",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/payments/webhook": {
+            "post": {
+                "description": "Endpoint for the payment provider. The body must be signed in X-Payment-Signature; each event is applied once and repeated deliveries are acknowledged without effect. Any response other than 200 makes the provider deliver the event again",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Receive a payment webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Signature of the body, t=\u003cunix time\u003e,v1=\u003chex HMAC-SHA256\u003e",
+                        "name": "X-Payment-Signature",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Event received",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid signature or body",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Unknown payment",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/price-list": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a list of explicit product prices for a currency, optionally scoped to a market",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pricing"
+                ],
+                "summary": "Create a price list",
+                "parameters": [
+                    {
+                        "description": "Price list information",
+                        "name": "priceList",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreatePriceListRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Price list created successfully",
+                        "schema": {
+                            "$ref": "#/definitions/dto.PriceListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - Invalid input data",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin role required",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Code already used",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/price-lists": {
+            "get": {
+                "description": "Get every price list with its currency and market",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pricing"
+                ],
+                "summary": "List price lists",
+                "responses": {
+                    "200": {
+                        "description": "Price lists",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.PriceListResponse"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/price-lists/{priceListId}/items": {
+            "get": {
+                "description": "Get the explicit product prices of a price list",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pricing"
+                ],
+                "summary": "List the prices of a price list",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Price list ID",
+                        "name": "priceListId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Price list items",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.PriceListItemResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - Invalid ID format",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Price list not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/price-lists/{priceListId}/items/{productId}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Insert or replace the explicit price of a product, in the currency of the list",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pricing"
+                ],
+                "summary": "Set the price of a product in a price list",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Price list ID",
+                        "name": "priceListId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "productId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Price",
+                        "name": "item",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.SetPriceListItemRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Price saved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/dto.PriceListItemResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - Invalid input data",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin role required",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Price list or product not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "The product falls back to exchange-rate conversion in the currency of the list",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pricing"
+                ],
+                "summary": "Remove the price of a product from a price list",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Price list ID",
+                        "name": "priceListId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "productId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Price removed successfully"
+                    },
+                    "400": {
+                        "description": "Bad request - Invalid ID format",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin role required",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Price list not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/product": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Create a new product with the provided information",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Create a new product",
+                "parameters": [
+                    {
+                        "description": "Product information",
+                        "name": "product",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateProductRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Product created successfully",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProductResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - Invalid input data",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin role required, or API key or access token without the scope of the route",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "SKU already used by another product",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/products": {
+            "get": {
+                "description": "Get a list of all products in the system, optionally restricted to a category and its descendants",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "List all products",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Category ID or path (e.g. roupas/camisetas)",
+                        "name": "category",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ISO 4217 currency to present the prices in",
+                        "name": "currency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Market whose price lists are preferred",
+                        "name": "market",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "RFC 3339 instant to resolve the price history at, defaults to now",
+                        "name": "as_of",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "RFC 3339 instant, only products changed at or after it (incremental sync)",
+                        "name": "updated_since",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of products",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.ProductResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - Unknown currency, invalid as_of or updated_since",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "No price or exchange rate for the currency",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/export": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Stream the products as CSV, NDJSON or XLSX, chosen by format or the Accept header (CSV by default). Accepts the listing filters; prices are the ones in effect at as_of, in the product currency. CSV and NDJSON are gzip encoded when the client accepts it",
+                "produces": [
+                    "text/csv",
+                    "application/x-ndjson",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Export products",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "csv, ndjson or xlsx, overrides the Accept header",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Compress the export even without Accept-Encoding: gzip",
+                        "name": "gzip",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Category ID or path (e.g. roupas/camisetas)",
+                        "name": "category",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "RFC 3339 instant to resolve the price history at, defaults to now",
+                        "name": "as_of",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "RFC 3339 instant, only products changed at or after it (incremental sync)",
+                        "name": "updated_since",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Export with the columns id, sku, name, price and currency",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - Unknown format, invalid as_of or updated_since, or a currency was requested",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin role required, or API key or access token without the scope of the route",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "406": {
+                        "description": "None of the accepted media types is supported",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/import": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Start a background import of a CSV (columns name, price, currency, sku) or NDJSON (one CreateProductRequest per line) file, sent as the multipart field \"file\" or as the raw body. Each row is validated like POST /product and upserted by SKU, or by name when it has none. Poll the returned job for progress and the per-row error report",
+                "consumes": [
+                    "multipart/form-data",
+                    "text/csv",
+                    "application/x-ndjson"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Import products in bulk",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "CSV or NDJSON file",
+                        "name": "file",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "csv or ndjson, detected from the Content-Type or file extension when omitted",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Validate and match the rows without writing anything",
+                        "name": "dry_run",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Import started",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ImportJobResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - Empty file or invalid dry_run",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin role required, or API key or access token without the scope of the route",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "413": {
+                        "description": "File too large",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "415": {
+                        "description": "Unknown file format",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/import/{jobId}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Get the status, progress, counters and per-row error report of an import job",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Get the progress of a product import",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Import job ID",
+                        "name": "jobId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Import job",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ImportJobResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin role required, or API key or access token without the scope of the route",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Import job not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/{productId}": {
+            "get": {
+                "description": "Get a specific product by its ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Get product by ID",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "productId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ISO 4217 currency to present the price in",
+                        "name": "currency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Market whose price lists are preferred",
+                        "name": "market",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "RFC 3339 instant to resolve the price history at, defaults to now",
+                        "name": "as_of",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Product found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProductResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - Invalid ID format, unknown currency or invalid as_of",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Product not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "No price or exchange rate for the currency",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Move a product to the trash. It disappears from listings and lookups until restored, and can be purged from the trash",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Delete a product",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "productId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Product moved to the trash"
+                    },
+                    "400": {
+                        "description": "Bad request - Invalid ID format",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin role required, or API key or access token without the scope of the route",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Product not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/{productId}/categories": {
+            "get": {
+                "description": "Get the categories a product is assigned to",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "categories"
+                ],
+                "summary": "List the categories of a product",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "productId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Product categories",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.CategoryResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - Invalid ID format",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Product not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Replace the categories a product is assigned to",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "categories"
+                ],
+                "summary": "Assign categories to a product",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "productId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Category IDs",
+                        "name": "categories",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.SetProductCategoriesRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Product categories",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.CategoryResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - Invalid input data",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin role required, or API key or access token without the scope of the route",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Product or category not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/{productId}/images": {
+            "get": {
+                "description": "Get the images of a product ordered by position, with thumbnail URLs",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "images"
+                ],
+                "summary": "List the images of a product",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "productId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Product images",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.ProductImageResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - Invalid ID format",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Product not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Upload a JPEG, PNG or GIF image as the multipart field \"file\". The type is sniffed from the content; small, medium and large JPEG thumbnails are generated. The first image of a product becomes its primary image",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "images"
+                ],
+                "summary": "Upload a product image",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "productId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Image file",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Image uploaded successfully",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProductImageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - Missing file",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin role required, or API key or access token without the scope of the route",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Product not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "413": {
+                        "description": "File too large",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "415": {
+                        "description": "Not a JPEG, PNG or GIF image",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Image dimensions out of bounds",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/{productId}/images/order": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Set the display order of the images; every image of the product must be listed once",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "images"
+                ],
+                "summary": "Reorder the images of a product",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "productId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Image IDs in the new order",
+                        "name": "order",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ReorderImagesRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Product images",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.ProductImageResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - Invalid order",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin role required, or API key or access token without the scope of the route",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Product not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/{productId}/images/{imageId}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Delete an image and its thumbnails; if it was the primary image the next one takes its place",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "images"
+                ],
+                "summary": "Delete a product image",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "productId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Image ID",
+                        "name": "imageId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Image deleted successfully"
+                    },
+                    "400": {
+                        "description": "Bad request - Invalid ID format",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin role required, or API key or access token without the scope of the route",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Product or image not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/{productId}/images/{imageId}/primary": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Make the image the primary image of its product",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "images"
+                ],
+                "summary": "Set the primary image of a product",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "productId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Image ID",
+                        "name": "imageId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Product images",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.ProductImageResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - Invalid ID format",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin role required, or API key or access token without the scope of the route",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Product or image not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/{productId}/prices": {
+            "get": {
+                "description": "Get every regular and sale price of the product, past and scheduled, with its status",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Get the price timeline of a product",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "productId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Price timeline",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.ProductPriceResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - Invalid ID format",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Product not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Append a regular price change or a temporary sale price to the product timeline. Past entries are never changed",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Schedule a product price change",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "productId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Price change",
+                        "name": "price",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.SchedulePriceRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Price change scheduled successfully",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProductPriceResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - Invalid price or schedule",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin role required, or API key or access token without the scope of the route",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Product not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/{productId}/tax-category": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Assign one of the categories of the rate table to the product; carts and orders not yet placed are taxed with it",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "taxes"
+                ],
+                "summary": "Assign the tax category of a product",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "productId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Tax category",
+                        "name": "category",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.SetTaxCategoryRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Product after the change",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProductResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - Invalid ID format or unknown tax category",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin role required, or API key or access token without the scope of the route",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Product not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/{productId}/variants": {
+            "get": {
+                "description": "Get the variants of a product with their SKU, price, stock and option values",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "variants"
+                ],
+                "summary": "List the variants of a product",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "productId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Product variants",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.VariantResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - Invalid ID format",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Product not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Create a variant taking one value of each option type used by the product",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "variants"
+                ],
+                "summary": "Create a product variant",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "productId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Variant information",
+                        "name": "variant",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateVariantRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Variant created successfully",
+                        "schema": {
+                            "$ref": "#/definitions/dto.VariantResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - Invalid input data or options",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin role required, or API key or access token without the scope of the route",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Product or option value not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "SKU or option combination already used",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/{productId}/variants/{variantId}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Replace the SKU, price override and stock of a variant. The option values of a variant never change",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "variants"
+                ],
+                "summary": "Update a product variant",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "productId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Variant ID",
+                        "name": "variantId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Variant information",
+                        "name": "variant",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateVariantRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Variant updated successfully",
+                        "schema": {
+                            "$ref": "#/definitions/dto.VariantResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - Invalid input data",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin role required, or API key or access token without the scope of the route",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Product or variant not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "SKU already used",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Delete a variant of a product",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "variants"
+                ],
+                "summary": "Delete a product variant",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "productId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Variant ID",
+                        "name": "variantId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Variant deleted successfully"
+                    },
+                    "400": {
+                        "description": "Bad request - Invalid ID format",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin role required, or API key or access token without the scope of the route",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Product or variant not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/promotions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get every discount code and automatic promotion, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "promotions"
+                ],
+                "summary": "List promotions",
+                "responses": {
+                    "200": {
+                        "description": "Promotions",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.PromotionResponse"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin role required",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a discount code or, without a code, a promotion applied automatically to every cart and order that qualifies. Stackable promotions combine with each other; otherwise the combination with the largest discount wins",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "promotions"
+                ],
+                "summary": "Create a promotion",
+                "parameters": [
+                    {
+                        "description": "Promotion rules",
+                        "name": "promotion",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreatePromotionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Promotion created successfully",
+                        "schema": {
+                            "$ref": "#/definitions/dto.PromotionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - Invalid input data",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin role required",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Product or category not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Code already used",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/promotions/{promotionId}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a discount code or automatic promotion with its rules and usage",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "promotions"
+                ],
+                "summary": "Get a promotion",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Promotion ID",
+                        "name": "promotionId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Promotion",
+                        "schema": {
+                            "$ref": "#/definitions/dto.PromotionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - Invalid ID format",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin role required",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Promotion not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/promotions/{promotionId}/active": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Inactive promotions are no longer applied; orders already placed keep their discount",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "promotions"
+                ],
+                "summary": "Turn a promotion on or off",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Promotion ID",
+                        "name": "promotionId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New state",
+                        "name": "active",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.SetPromotionActiveRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Promotion after the change",
+                        "schema": {
+                            "$ref": "#/definitions/dto.PromotionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - Invalid ID format or input data",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin role required",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Promotion not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/tax/categories": {
+            "get": {
+                "description": "Get the tax categories of the rate table, which products may be assigned to",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "taxes"
+                ],
+                "summary": "List tax categories",
+                "responses": {
+                    "200": {
+                        "description": "Tax categories",
+                        "schema": {
+                            "$ref": "#/definitions/dto.TaxCategoriesResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tax/rates": {
+            "get": {
+                "description": "Get the rates in effect at a country, or at a state of it, with the country rates first. Rates are versioned, as_of selects the version in effect at another time",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "taxes"
+                ],
+                "summary": "Get the tax rates of a destination",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ISO 3166-1 alpha-2 country code",
+                        "name": "country",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "State, for countries that tax by state",
+                        "name": "state",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "RFC 3339 instant to read the rates at, defaults to now",
+                        "name": "as_of",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Rates in effect",
+                        "schema": {
+                            "$ref": "#/definitions/dto.TaxRegionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - Invalid country or as_of",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "No tax rates for the country",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/trash/products": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the products in the trash, most recently deleted first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trash"
+                ],
+                "summary": "List deleted products",
+                "responses": {
+                    "200": {
+                        "description": "Products in the trash",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.ProductResponse"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/trash/products/{productId}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Permanently delete a product in the trash with its prices, variants, images and category assignments",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trash"
+                ],
+                "summary": "Purge a deleted product",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "productId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Product purged"
+                    },
+                    "400": {
+                        "description": "Bad request - Invalid ID format",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Product not in the trash",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/trash/products/{productId}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Take a product out of the trash",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trash"
+                ],
+                "summary": "Restore a deleted product",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "productId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Restored product",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProductResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - Invalid ID format",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Product not in the trash",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "SKU taken by another product in the meantime",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/trash/users": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the users in the trash, most recently deleted first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trash"
+                ],
+                "summary": "List deleted users",
+                "responses": {
+                    "200": {
+                        "description": "Users in the trash",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.UserResponse"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/trash/users/{userId}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Permanently delete a user in the trash, releasing its email",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trash"
+                ],
+                "summary": "Purge a deleted user",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "User purged"
+                    },
+                    "400": {
+                        "description": "Bad request - Invalid ID format",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "User not in the trash",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/trash/users/{userId}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Take a user out of the trash",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trash"
+                ],
+                "summary": "Restore a deleted user",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Restored user",
+                        "schema": {
+                            "$ref": "#/definitions/dto.UserResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - Invalid ID format",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "User not in the trash",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Email taken by another user in the meantime",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/user": {
+            "post": {
+                "description": "Create a new user with the provided information. When the API hides taken emails, every valid sign up answers 202 with the same message, and the owner of a taken email is told by email instead",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Create a new user",
+                "parameters": [
+                    {
+                        "description": "User information",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "User created successfully",
+                        "schema": {
+                            "$ref": "#/definitions/dto.UserResponse"
+                        }
+                    },
+                    "202": {
+                        "description": "Sign up accepted, when taken emails are hidden",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - Invalid input data, or a password breaking the strength rules",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Email in use, or reserved by a deleted user",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/users": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a list of all users in the system",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "List all users",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "RFC 3339 instant, only users changed at or after it (incremental sync)",
+                        "name": "updated_since",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of users",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.UserResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - Invalid updated_since",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin role required",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/export": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Stream the users as CSV, NDJSON or XLSX, chosen by format or the Accept header (CSV by default). Accepts the listing filters. Password hashes are never exported. CSV and NDJSON are gzip encoded when the client accepts it",
+                "produces": [
+                    "text/csv",
+                    "application/x-ndjson",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Export users",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "csv, ndjson or xlsx, overrides the Accept header",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Compress the export even without Accept-Encoding: gzip",
+                        "name": "gzip",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "RFC 3339 instant, only users changed at or after it (incremental sync)",
+                        "name": "updated_since",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Export with the columns id, name, email, role, created_at and updated_at",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - Unknown format or invalid updated_since",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "406": {
+                        "description": "None of the accepted media types is supported",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{userId}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a specific user by their ID. Users see their own account; admins any account",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get user by ID",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.UserResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - Invalid ID format",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Another user's account, without the admin role",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update an existing user's information. Users update their own account; admins any account",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Update a user",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "User information",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "User updated successfully"
+                    },
+                    "400": {
+                        "description": "Bad request - Invalid input data, or a password breaking the strength rules",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Another user's account, without the admin role",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Email in use, or reserved by a deleted user",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Move a user to the trash. It can no longer log in and disappears from listings until restored, and can be purged from the trash",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Delete a user",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "User deleted successfully"
+                    },
+                    "400": {
+                        "description": "Bad request - Invalid ID format",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin role, or two-factor authentication for the roles of MFA_REQUIRED_ROLES, required",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "dto.APIKeyResponse": {
+            "type": "object",
+            "properties": {
+                "allowed_ips": {
+                    "description": "@Description IPs and CIDR ranges the key works from; empty accepts any",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "created_at": {
+                    "description": "@Description When the key was created",
+                    "type": "string"
+                },
+                "created_by": {
+                    "description": "@Description Admin who created the key",
+                    "type": "integer"
+                },
+                "expires_at": {
+                    "description": "@Description When the key stops working, absent when it does not expire",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "@Description Unique identifier of the key\n@Example 4",
+                    "type": "integer",
+                    "example": 4
+                },
+                "last_used_at": {
+                    "description": "@Description Last use of the key, recorded at most once a minute",
+                    "type": "string"
+                },
+                "last_used_ip": {
+                    "description": "@Description IP of the last use\n@Example \"203.0.113.9\"",
+                    "type": "string",
+                    "example": "203.0.113.9"
+                },
+                "name": {
+                    "description": "@Description Name telling what the key is for\n@Example \"Integração do armazém\"",
+                    "type": "string",
+                    "example": "Integração do armazém"
+                },
+                "prefix": {
+                    "description": "@Description Start of the key, to tell it apart\n@Example \"gak_3f9a1c0b7d2e\"",
+                    "type": "string",
+                    "example": "gak_3f9a1c0b7d2e"
+                },
+                "revoked_at": {
+                    "description": "@Description When the key was revoked, absent while active",
+                    "type": "string"
+                },
+                "rotated_at": {
+                    "description": "@Description Last rotation of the secret",
+                    "type": "string"
+                },
+                "scopes": {
+                    "description": "@Description Scopes granted",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "user_id": {
+                    "description": "@Description User or service account the key acts as\n@Example 9",
+                    "type": "integer",
+                    "example": 9
+                }
+            }
+        },
+        "dto.APIKeySecretResponse": {
+            "type": "object",
+            "properties": {
+                "api_key": {
+                    "$ref": "#/definitions/dto.APIKeyResponse"
+                },
+                "key": {
+                    "description": "@Description The key, sent in the X-API-Key header or as a bearer token\n@Example \"gak_3f9a1c0b7d2e_Zm9vYmFyYmF6cXV4cXV1eGNvcmdlZ3JhdWx0Z2FycGx5\"",
+                    "type": "string",
+                    "example": "gak_3f9a1c0b7d2e_Zm9vYmFyYmF6cXV4cXV1eGNvcmdlZ3JhdWx0Z2FycGx5"
+                }
+            }
+        },
+        "dto.AddCartItemRequest": {
+            "type": "object",
+            "required": [
+                "product_id",
+                "quantity"
+            ],
+            "properties": {
+                "product_id": {
+                    "description": "@Description ID of the product\n@Example 1",
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 1
+                },
+                "quantity": {
+                    "description": "@Description Quantity to add to the line of the product or variant\n@Example 1",
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 1
+                },
+                "variant_id": {
+                    "description": "@Description ID of the variant, required for products with variants\n@Example 2",
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 2
+                }
+            }
+        },
+        "dto.AddOptionValueRequest": {
+            "type": "object",
+            "required": [
+                "value"
+            ],
+            "properties": {
+                "value": {
+                    "description": "@Description New value, appended after the existing ones\n@Example \"GG\"",
+                    "type": "string",
+                    "example": "GG"
+                }
+            }
+        },
+        "dto.AdjustmentResponse": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "@Description Discount on the line",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.MoneyResponse"
+                        }
+                    ]
+                },
+                "code": {
+                    "description": "@Description Discount code, absent for automatic promotions\n@Example \"BEMVINDO10\"",
+                    "type": "string",
+                    "example": "BEMVINDO10"
+                },
+                "name": {
+                    "description": "@Description Name of the promotion\n@Example \"Boas-vindas\"",
+                    "type": "string",
+                    "example": "Boas-vindas"
+                },
+                "promotion_id": {
+                    "description": "@Description ID of the promotion\n@Example 1",
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "dto.AppliedPromotionResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "@Description Discount code, absent for automatic promotions\n@Example \"BEMVINDO10\"",
+                    "type": "string",
+                    "example": "BEMVINDO10"
+                },
+                "discount": {
+                    "description": "@Description Discount given by the promotion",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.MoneyResponse"
+                        }
+                    ]
+                },
+                "name": {
+                    "description": "@Description Name of the promotion\n@Example \"Boas-vindas\"",
+                    "type": "string",
+                    "example": "Boas-vindas"
+                },
+                "promotion_id": {
+                    "description": "@Description ID of the promotion\n@Example 1",
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "dto.CartItemResponse": {
+            "type": "object",
+            "properties": {
+                "added_price": {
+                    "description": "@Description Unit price when the item was added",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.MoneyResponse"
+                        }
+                    ]
+                },
+                "available": {
+                    "description": "@Description False when the product is no longer sold or the stock does not cover the quantity\n@Example true",
+                    "type": "boolean",
+                    "example": true
+                },
+                "id": {
+                    "description": "@Description Unique identifier of the item\n@Example 1",
+                    "type": "integer",
+                    "example": 1
+                },
+                "price_changed": {
+                    "description": "@Description Whether the unit price changed since the item was added\n@Example false",
+                    "type": "boolean",
+                    "example": false
+                },
+                "product_id": {
+                    "description": "@Description ID of the product\n@Example 1",
+                    "type": "integer",
+                    "example": 1
+                },
+                "product_name": {
+                    "description": "@Description Name of the product\n@Example \"Camiseta\"",
+                    "type": "string",
+                    "example": "Camiseta"
+                },
+                "quantity": {
+                    "description": "@Description Quantity of the item\n@Example 2",
+                    "type": "integer",
+                    "example": 2
+                },
+                "sku": {
+                    "description": "@Description SKU of the variant or of the product\n@Example \"CAM-AZUL-M\"",
+                    "type": "string",
+                    "example": "CAM-AZUL-M"
+                },
+                "stock": {
+                    "description": "@Description Units in stock, only for variants\n@Example 10",
+                    "type": "integer",
+                    "example": 10
+                },
+                "subtotal": {
+                    "description": "@Description Current unit price times the quantity",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.MoneyResponse"
+                        }
+                    ]
+                },
+                "unit_price": {
+                    "description": "@Description Current unit price",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.MoneyResponse"
+                        }
+                    ]
+                },
+                "variant_id": {
+                    "description": "@Description ID of the variant, for products with variants\n@Example 2",
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "dto.CartPricingResponse": {
+            "type": "object",
+            "properties": {
+                "applied": {
+                    "description": "@Description Promotions and discount codes applied",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.AppliedPromotionResponse"
+                    }
+                },
+                "discount": {
+                    "description": "@Description Sum of the discounts, absent for an empty cart",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.MoneyResponse"
+                        }
+                    ]
+                },
+                "lines": {
+                    "description": "@Description Available items of the cart with their discounts",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.PricedLineResponse"
+                    }
+                },
+                "rejected": {
+                    "description": "@Description Discount codes not applied, and why",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.RejectedCodeResponse"
+                    }
+                },
+                "subtotal": {
+                    "description": "@Description Sum of the items, absent for an empty cart",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.MoneyResponse"
+                        }
+                    ]
+                },
+                "tax": {
+                    "description": "@Description Taxes of the discounted items, present when a destination country was given",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.TaxBreakdownResponse"
+                        }
+                    ]
+                },
+                "total": {
+                    "description": "@Description Subtotal less the discount, plus the taxes not included in the prices; absent for an empty cart",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.MoneyResponse"
+                        }
+                    ]
+                }
+            }
+        },
+        "dto.CartResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "description": "@Description Unique identifier of the cart, 0 while the cart is empty and was never created\n@Example 1",
+                    "type": "integer",
+                    "example": 1
+                },
+                "items": {
+                    "description": "@Description Items of the cart",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.CartItemResponse"
+                    }
+                },
+                "subtotals": {
+                    "description": "@Description Sum of the available items, one entry per currency",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.MoneyResponse"
+                    }
+                },
+                "token": {
+                    "description": "@Description Token of a new anonymous cart, also sent in the X-Cart-Token header; only returned once\n@Example \"4f1c2e...\"",
+                    "type": "string",
+                    "example": "4f1c2e..."
+                },
+                "updated_at": {
+                    "description": "@Description Last change of the cart\n@Example \"2026-03-01T12:00:00Z\"",
+                    "type": "string",
+                    "example": "2026-03-01T12:00:00Z"
+                }
+            }
+        },
+        "dto.CategoryResponse": {
+            "type": "object",
+            "properties": {
+                "children": {
+                    "description": "@Description Subcategories",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.CategoryResponse"
+                    }
+                },
+                "id": {
+                    "description": "@Description Unique identifier of the category\n@Example 1",
+                    "type": "integer",
+                    "example": 1
+                },
+                "name": {
+                    "description": "@Description Name of the category\n@Example \"Camisetas\"",
+                    "type": "string",
+                    "example": "Camisetas"
+                },
+                "parent_id": {
+                    "description": "@Description Parent category, null for root categories\n@Example 1",
+                    "type": "integer",
+                    "example": 1
+                },
+                "path": {
+                    "description": "@Description Materialized path of the category\n@Example \"roupas/camisetas\"",
+                    "type": "string",
+                    "example": "roupas/camisetas"
+                },
+                "slug": {
+                    "description": "@Description Slug of the category\n@Example \"camisetas\"",
+                    "type": "string",
+                    "example": "camisetas"
+                }
+            }
+        },
+        "dto.CheckoutItem": {
+            "type": "object",
+            "required": [
+                "product_id",
+                "quantity"
+            ],
+            "properties": {
+                "product_id": {
+                    "description": "@Description ID of the product\n@Example 1",
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 1
+                },
+                "quantity": {
+                    "description": "@Description Quantity to order\n@Example 1",
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 1
+                },
+                "variant_id": {
+                    "description": "@Description ID of the variant, required for products with variants\n@Example 2",
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 2
+                }
+            }
+        },
+        "dto.CheckoutRequest": {
+            "type": "object",
+            "properties": {
+                "codes": {
+                    "description": "@Description Discount codes to apply; the order is refused if any of them is rejected\n@Example [\"BEMVINDO10\"]",
+                    "type": "array",
+                    "maxItems": 5,
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "BEMVINDO10"
+                    ]
+                },
+                "destination": {
+                    "description": "@Description Where the order is delivered; the items are taxed with its rates. Without it the order is placed without taxes",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.TaxDestinationRequest"
+                        }
+                    ]
+                },
+                "items": {
+                    "description": "@Description Items to order; when empty the whole cart of the user is ordered and emptied",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.CheckoutItem"
+                    }
+                }
+            }
+        },
+        "dto.CreateAPIKeyRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "scopes",
+                "user_id"
+            ],
+            "properties": {
+                "allowed_ips": {
+                    "description": "@Description IPs and CIDR ranges the key works from; empty accepts any\n@Example [\"203.0.113.0/24\"]",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "expires_at": {
+                    "description": "@Description When the key stops working; absent for keys that do not expire",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "@Description Name telling what the key is for\n@Example \"Integração do armazém\"",
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "Integração do armazém"
+                },
+                "scopes": {
+                    "description": "@Description Scopes granted: products:read, products:write, orders:read, orders:write\n@Example [\"products:read\",\"products:write\"]",
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "user_id": {
+                    "description": "@Description User or service account the key acts as\n@Example 9",
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 9
+                }
+            }
+        },
+        "dto.CreateCategoryRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "description": "@Description Name of the category\n@Example \"Camisetas\"",
+                    "type": "string",
+                    "example": "Camisetas"
+                },
+                "parent_id": {
+                    "description": "@Description Parent category, omitted or null for a root category\n@Example 1",
+                    "type": "integer",
+                    "example": 1
+                },
+                "slug": {
+                    "description": "@Description Slug of the category, derived from the name when omitted. Must be unique among its siblings\n@Example \"camisetas\"",
+                    "type": "string",
+                    "example": "camisetas"
+                }
+            }
+        },
+        "dto.CreateOAuthClientRequest": {
+            "type": "object",
+            "required": [
+                "grant_types",
+                "name",
+                "scopes"
+            ],
+            "properties": {
+                "grant_types": {
+                    "description": "@Description Grant types the client uses: authorization_code, client_credentials\n@Example [\"authorization_code\"]",
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "name": {
+                    "description": "@Description Name of the application, shown to users when asked for consent\n@Example \"Agenda de Entregas\"",
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "Agenda de Entregas"
+                },
+                "public": {
+                    "description": "@Description Whether the client cannot keep a secret, such as a mobile or single page app; public clients only use authorization_code with PKCE\n@Example false",
+                    "type": "boolean",
+                    "example": false
+                },
+                "redirect_uris": {
+                    "description": "@Description URIs the users are sent back to with the code, compared exactly; https, or http on localhost\n@Example [\"https://agenda.example.com/oauth/callback\"]",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "scopes": {
+                    "description": "@Description Most scopes the client can be granted: products:read, products:write, orders:read, orders:write\n@Example [\"orders:read\"]",
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "user_id": {
+                    "description": "@Description Admin or service account the client acts as with client_credentials\n@Example 9",
+                    "type": "integer",
+                    "example": 9
+                }
+            }
+        },
+        "dto.CreateOptionTypeRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "values"
+            ],
+            "properties": {
+                "name": {
+                    "description": "@Description Name of the option type\n@Example \"size\"",
+                    "type": "string",
+                    "example": "size"
+                },
+                "values": {
+                    "description": "@Description Values of the option type, in display order\n@Example [\"P\", \"M\", \"G\"]",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "P",
+                        "M",
+                        "G"
+                    ]
+                }
+            }
+        },
+        "dto.CreatePriceListRequest": {
+            "type": "object",
+            "required": [
+                "code",
+                "currency",
+                "name"
+            ],
+            "properties": {
+                "code": {
+                    "description": "@Description Unique code of the price list\n@Example \"usd-default\"",
+                    "type": "string",
+                    "example": "usd-default"
+                },
+                "currency": {
+                    "description": "@Description ISO 4217 currency of the prices in the list\n@Example \"USD\"",
+                    "type": "string",
+                    "example": "USD"
+                },
+                "market": {
+                    "description": "@Description Optional market the list applies to\n@Example \"US\"",
+                    "type": "string",
+                    "example": "US"
+                },
+                "name": {
+                    "description": "@Description Name of the price list\n@Example \"Preços em dólar\"",
+                    "type": "string",
+                    "example": "Preços em dólar"
+                }
+            }
+        },
+        "dto.CreateProductRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "price"
+            ],
+            "properties": {
+                "currency": {
+                    "description": "@Description ISO 4217 currency of the price, defaults to BRL\n@Example \"BRL\"",
+                    "type": "string",
+                    "example": "BRL"
+                },
+                "name": {
+                    "description": "@Description Name of the product\n@Example \"iPhone 15\"",
+                    "type": "string",
+                    "example": "iPhone 15"
+                },
+                "price": {
+                    "description": "@Description Price of the product as a decimal string (numbers are also accepted)\n@Example \"999.99\"",
+                    "type": "string",
+                    "example": "999.99"
+                },
+                "sku": {
+                    "description": "@Description Optional stock keeping unit, unique among products\n@Example \"IPH-15-128\"",
+                    "type": "string",
+                    "maxLength": 64,
+                    "example": "IPH-15-128"
+                }
+            }
+        },
+        "dto.CreatePromotionRequest": {
+            "type": "object",
+            "required": [
+                "kind",
+                "name"
+            ],
+            "properties": {
+                "amount": {
+                    "description": "@Description Discount of fixed promotions, as a decimal string, spread over the eligible items\n@Example \"20.00\"",
+                    "type": "string",
+                    "example": "20.00"
+                },
+                "category_ids": {
+                    "description": "@Description Categories the promotion is restricted to, subcategories included\n@Example [2]",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "code": {
+                    "description": "@Description Discount code, matched without regard to case; leave out for an automatic promotion\n@Example \"BEMVINDO10\"",
+                    "type": "string",
+                    "maxLength": 32,
+                    "example": "BEMVINDO10"
+                },
+                "currency": {
+                    "description": "@Description ISO 4217 currency of amount and min_total\n@Example \"BRL\"",
+                    "type": "string",
+                    "example": "BRL"
+                },
+                "ends_at": {
+                    "description": "@Description End of the validity, exclusive\n@Example \"2026-04-01T00:00:00Z\"",
+                    "type": "string",
+                    "example": "2026-04-01T00:00:00Z"
+                },
+                "kind": {
+                    "description": "@Description Kind of discount: percentage or fixed\n@Example \"percentage\"",
+                    "type": "string",
+                    "enum": [
+                        "percentage",
+                        "fixed"
+                    ],
+                    "example": "percentage"
+                },
+                "min_total": {
+                    "description": "@Description Minimum subtotal of the order, as a decimal string\n@Example \"100.00\"",
+                    "type": "string",
+                    "example": "100.00"
+                },
+                "name": {
+                    "description": "@Description Name shown in the price breakdown\n@Example \"Boas-vindas\"",
+                    "type": "string",
+                    "maxLength": 255,
+                    "example": "Boas-vindas"
+                },
+                "per_user_limit": {
+                    "description": "@Description Maximum number of redemptions by each user; the code then requires signing in\n@Example 1",
+                    "type": "integer",
+                    "example": 1
+                },
+                "percent": {
+                    "description": "@Description Discount of percentage promotions, from 1 to 100\n@Example 10",
+                    "type": "integer",
+                    "example": 10
+                },
+                "product_ids": {
+                    "description": "@Description Products the promotion is restricted to\n@Example [1]",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "stackable": {
+                    "description": "@Description Whether the promotion combines with the other stackable ones\n@Example false",
+                    "type": "boolean",
+                    "example": false
+                },
+                "starts_at": {
+                    "description": "@Description Start of the validity\n@Example \"2026-03-01T00:00:00Z\"",
+                    "type": "string",
+                    "example": "2026-03-01T00:00:00Z"
+                },
+                "usage_limit": {
+                    "description": "@Description Maximum number of redemptions by everyone\n@Example 100",
+                    "type": "integer",
+                    "example": 100
+                }
+            }
+        },
+        "dto.CreateUserRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "name",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "description": "@Description Email of the user\n@Example \"user@example.com\"",
+                    "type": "string",
+                    "example": "user@example.com"
+                },
+                "name": {
+                    "description": "@Description Name of the user\n@Example \"Leandro\"",
+                    "type": "string",
+                    "example": "Leandro"
+                },
+                "password": {
+                    "description": "@Description Password of the user\n@Example \"correct horse battery\"",
+                    "type": "string",
+                    "example": "correct horse battery"
+                }
+            }
+        },
+        "dto.CreateVariantRequest": {
+            "type": "object",
+            "required": [
+                "sku"
+            ],
+            "properties": {
+                "option_value_ids": {
+                    "description": "@Description One option value ID per option type of the product\n@Example [11, 21]",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        11,
+                        21
+                    ]
+                },
+                "price_override": {
+                    "description": "@Description Optional price replacing the product price, in the product currency\n@Example \"54.90\"",
+                    "type": "string",
+                    "example": "54.90"
+                },
+                "sku": {
+                    "description": "@Description Unique stock keeping unit, stored in upper case\n@Example \"CAM-AZUL-M\"",
+                    "type": "string",
+                    "example": "CAM-AZUL-M"
+                },
+                "stock": {
+                    "description": "@Description Units in stock\n@Example 10",
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 10
+                }
+            }
+        },
+        "dto.ExchangeRateRequest": {
+            "type": "object",
+            "required": [
+                "base",
+                "quote",
+                "rate"
+            ],
+            "properties": {
+                "base": {
+                    "description": "@Description Currency being converted\n@Example \"BRL\"",
+                    "type": "string",
+                    "example": "BRL"
+                },
+                "quote": {
+                    "description": "@Description Currency the base is converted into\n@Example \"USD\"",
+                    "type": "string",
+                    "example": "USD"
+                },
+                "rate": {
+                    "description": "@Description Units of quote for one unit of base, as a decimal string\n@Example \"0.185\"",
+                    "type": "string",
+                    "example": "0.185"
+                }
+            }
+        },
+        "dto.ExchangeRateResponse": {
+            "type": "object",
+            "properties": {
+                "base": {
+                    "description": "@Description Currency being converted\n@Example \"BRL\"",
+                    "type": "string",
+                    "example": "BRL"
+                },
+                "quote": {
+                    "description": "@Description Currency the base is converted into\n@Example \"USD\"",
+                    "type": "string",
+                    "example": "USD"
+                },
+                "rate": {
+                    "description": "@Description Units of quote for one unit of base\n@Example \"0.1850000000\"",
+                    "type": "string",
+                    "example": "0.1850000000"
+                },
+                "source": {
+                    "description": "@Description Origin of the rate (manual or the imported file name)\n@Example \"manual\"",
+                    "type": "string",
+                    "example": "manual"
+                },
+                "updated_at": {
+                    "description": "@Description When the rate was last updated",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ForgotPasswordRequest": {
+            "type": "object",
+            "required": [
+                "email"
+            ],
+            "properties": {
+                "email": {
+                    "description": "@Description Email of the account\n@Example \"user@example.com\"",
+                    "type": "string",
+                    "example": "user@example.com"
+                }
+            }
+        },
+        "dto.ImageThumbnailResponse": {
+            "type": "object",
+            "properties": {
+                "height": {
+                    "description": "@Description Height in pixels\n@Example 100",
+                    "type": "integer",
+                    "example": 100
+                },
+                "url": {
+                    "description": "@Description URL of the thumbnail\n@Example \"http://localhost:8000/media/products/1/9f86d081884c7d65/small.jpg\"",
+                    "type": "string",
+                    "example": "http://localhost:8000/media/products/1/9f86d081884c7d65/small.jpg"
+                },
+                "width": {
+                    "description": "@Description Width in pixels\n@Example 150",
+                    "type": "integer",
+                    "example": 150
+                }
+            }
+        },
+        "dto.ImportExchangeRatesResponse": {
+            "type": "object",
+            "properties": {
+                "imported": {
+                    "description": "@Description Number of rates imported\n@Example 4",
+                    "type": "integer",
+                    "example": 4
+                }
+            }
+        },
+        "dto.ImportJobResponse": {
+            "type": "object",
+            "properties": {
+                "created": {
+                    "description": "@Description Products created\n@Example 8000",
+                    "type": "integer",
+                    "example": 8000
+                },
+                "created_at": {
+                    "description": "@Description When the job was started",
+                    "type": "string"
+                },
+                "dry_run": {
+                    "description": "@Description Whether the job only validates; created and updated then count what would happen\n@Example false",
+                    "type": "boolean",
+                    "example": false
+                },
+                "error": {
+                    "description": "@Description Why the whole job failed, e.g. a missing column",
+                    "type": "string"
+                },
+                "errors": {
+                    "description": "@Description Rejected rows with the reason, in file order",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.ImportRowErrorResponse"
+                    }
+                },
+                "failed": {
+                    "description": "@Description Rows rejected\n@Example 10",
+                    "type": "integer",
+                    "example": 10
+                },
+                "finished_at": {
+                    "description": "@Description When the job finished",
+                    "type": "string"
+                },
+                "format": {
+                    "description": "@Description csv or ndjson\n@Example \"csv\"",
+                    "type": "string",
+                    "example": "csv"
+                },
+                "id": {
+                    "description": "@Description Identifier used to poll the job\n@Example \"6f1d2c3b4a5968778695a4b3\"",
+                    "type": "string",
+                    "example": "6f1d2c3b4a5968778695a4b3"
+                },
+                "progress": {
+                    "description": "@Description Share of the file processed so far, from 0 to 100\n@Example 42",
+                    "type": "integer",
+                    "example": 42
+                },
+                "rows": {
+                    "description": "@Description Rows read so far\n@Example 8400",
+                    "type": "integer",
+                    "example": 8400
+                },
+                "status": {
+                    "description": "@Description pending, running, completed or failed\n@Example \"running\"",
+                    "type": "string",
+                    "example": "running"
+                },
+                "updated": {
+                    "description": "@Description Existing products updated, matched by SKU or name\n@Example 390",
+                    "type": "integer",
+                    "example": 390
+                }
+            }
+        },
+        "dto.ImportRowErrorResponse": {
+            "type": "object",
+            "properties": {
+                "line": {
+                    "description": "@Description Line of the row in the file, starting at 1\n@Example 12",
+                    "type": "integer",
+                    "example": 12
+                },
+                "message": {
+                    "description": "@Description Why the row was rejected\n@Example \"duplicate of line 4\"",
+                    "type": "string",
+                    "example": "duplicate of line 4"
+                }
+            }
+        },
+        "dto.LineTaxResponse": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "description": "@Description Tax category of the product\n@Example \"standard\"",
+                    "type": "string",
+                    "example": "standard"
+                },
+                "net": {
+                    "description": "@Description Amount of the line without the taxes",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.MoneyResponse"
+                        }
+                    ]
+                },
+                "tax": {
+                    "description": "@Description Sum of the taxes of the line",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.MoneyResponse"
+                        }
+                    ]
+                },
+                "taxes": {
+                    "description": "@Description Taxes of the line",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.TaxAmountResponse"
+                    }
+                }
+            }
+        },
+        "dto.LoginRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
+            "properties": {
+                "cart_token": {
+                    "description": "CartToken is the token of the anonymous cart to merge into the user's cart;\nthe controller falls back to the X-Cart-Token header",
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.LoginResponse": {
+            "type": "object",
+            "properties": {
+                "mfa_enrollment_required": {
+                    "description": "MFAEnrollmentRequired tells that the role of the user demands\ntwo-factor authentication, which is not on yet",
+                    "type": "boolean"
+                },
+                "mfa_required": {
+                    "type": "boolean"
+                },
+                "mfa_token": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.MFACodeRequest": {
+            "type": "object",
+            "required": [
+                "code"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.MFAEnrollResponse": {
+            "type": "object",
+            "properties": {
+                "otpauth_uri": {
+                    "type": "string"
+                },
+                "secret": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.MFARecoveryCodesResponse": {
+            "type": "object",
+            "properties": {
+                "recovery_codes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "dto.MFAVerifyRequest": {
+            "type": "object",
+            "required": [
+                "code",
+                "mfa_token"
+            ],
+            "properties": {
+                "cart_token": {
+                    "description": "CartToken is the token of the anonymous cart to merge into the user's cart;\nthe controller falls back to the X-Cart-Token header",
+                    "type": "string"
+                },
+                "code": {
+                    "description": "Code is a code of the authenticator app or a recovery code",
+                    "type": "string"
+                },
+                "mfa_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.MoneyResponse": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "@Description Amount in major units with the currency precision\n@Example \"999.99\"",
+                    "type": "string",
+                    "example": "999.99"
+                },
+                "currency": {
+                    "description": "@Description ISO 4217 currency code\n@Example \"BRL\"",
+                    "type": "string",
+                    "example": "BRL"
+                }
+            }
+        },
+        "dto.MoveCategoryRequest": {
+            "type": "object",
+            "properties": {
+                "parent_id": {
+                    "description": "@Description New parent category, null to move the subtree to the root\n@Example 2",
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "dto.OAuthApproveRequest": {
+            "type": "object",
+            "required": [
+                "client_id",
+                "code_challenge",
+                "code_challenge_method",
+                "redirect_uri",
+                "response_type"
+            ],
+            "properties": {
+                "approve": {
+                    "description": "Approve is false when the user refuses",
+                    "type": "boolean"
+                },
+                "client_id": {
+                    "type": "string"
+                },
+                "code_challenge": {
+                    "type": "string"
+                },
+                "code_challenge_method": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                },
+                "redirect_uri": {
+                    "type": "string"
+                },
+                "response_type": {
+                    "type": "string"
+                },
+                "scope": {
+                    "description": "Scope lists the scopes asked for, separated by spaces; empty asks for\nall the scopes of the client",
+                    "type": "string"
+                },
+                "state": {
+                    "description": "State is sent back untouched to the redirect URI",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.OAuthAuthorizationResponse": {
+            "type": "object",
+            "properties": {
+                "client_id": {
+                    "description": "@Description Public identifier of the client\n@Example \"3f9a1c0b7d2e4a5b6c7d8e9f\"",
+                    "type": "string",
+                    "example": "3f9a1c0b7d2e4a5b6c7d8e9f"
+                },
+                "client_name": {
+                    "description": "@Description Name of the application asking for access\n@Example \"Agenda de Entregas\"",
+                    "type": "string",
+                    "example": "Agenda de Entregas"
+                },
+                "consent_required": {
+                    "description": "@Description Whether the logged in user has not granted all the scopes yet; true for anonymous requests",
+                    "type": "boolean"
+                },
+                "scopes": {
+                    "description": "@Description Scopes asked for",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "dto.OAuthAuthorizeResponse": {
+            "type": "object",
+            "properties": {
+                "mfa_required": {
+                    "type": "boolean"
+                },
+                "mfa_token": {
+                    "type": "string"
+                },
+                "redirect_uri": {
+                    "type": "string",
+                    "example": "https://agenda.example.com/oauth/callback?code=Zm9vYmFy\u0026state=xyz"
+                }
+            }
+        },
+        "dto.OAuthClientResponse": {
+            "type": "object",
+            "properties": {
+                "client_id": {
+                    "description": "@Description Public identifier of the client\n@Example \"3f9a1c0b7d2e4a5b6c7d8e9f\"",
+                    "type": "string",
+                    "example": "3f9a1c0b7d2e4a5b6c7d8e9f"
+                },
+                "created_at": {
+                    "description": "@Description When the client was registered",
+                    "type": "string"
+                },
+                "created_by": {
+                    "description": "@Description Admin who registered the client",
+                    "type": "integer"
+                },
+                "grant_types": {
+                    "description": "@Description Grant types the client uses",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "name": {
+                    "description": "@Description Name of the application\n@Example \"Agenda de Entregas\"",
+                    "type": "string",
+                    "example": "Agenda de Entregas"
+                },
+                "public": {
+                    "description": "@Description Whether the client has no secret",
+                    "type": "boolean"
+                },
+                "redirect_uris": {
+                    "description": "@Description URIs the users are sent back to with the code",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "revoked_at": {
+                    "description": "@Description When the client was revoked, absent while active",
+                    "type": "string"
+                },
+                "scopes": {
+                    "description": "@Description Most scopes the client can be granted",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "user_id": {
+                    "description": "@Description Admin or service account the client acts as with client_credentials",
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.OAuthClientSecretResponse": {
+            "type": "object",
+            "properties": {
+                "client": {
+                    "$ref": "#/definitions/dto.OAuthClientResponse"
+                },
+                "client_secret": {
+                    "description": "@Description Secret of a confidential client, absent for public ones\n@Example \"Zm9vYmFyYmF6cXV4cXV1eGNvcmdlZ3JhdWx0Z2FycGx5\"",
+                    "type": "string",
+                    "example": "Zm9vYmFyYmF6cXV4cXV1eGNvcmdlZ3JhdWx0Z2FycGx5"
+                }
+            }
+        },
+        "dto.OAuthConsentResponse": {
+            "type": "object",
+            "properties": {
+                "client_id": {
+                    "description": "@Description Public identifier of the client\n@Example \"3f9a1c0b7d2e4a5b6c7d8e9f\"",
+                    "type": "string",
+                    "example": "3f9a1c0b7d2e4a5b6c7d8e9f"
+                },
+                "client_name": {
+                    "description": "@Description Name of the application\n@Example \"Agenda de Entregas\"",
+                    "type": "string",
+                    "example": "Agenda de Entregas"
+                },
+                "created_at": {
+                    "description": "@Description When the user first granted access",
+                    "type": "string"
+                },
+                "scopes": {
+                    "description": "@Description Scopes granted",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "updated_at": {
+                    "description": "@Description When the user last changed the scopes granted",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.OAuthErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "invalid_grant"
+                },
+                "error_description": {
+                    "type": "string",
+                    "example": "invalid, used or expired authorization code, or wrong code_verifier"
+                }
+            }
+        },
+        "dto.OAuthIntrospectionResponse": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "client_id": {
+                    "type": "string",
+                    "example": "3f9a1c0b7d2e4a5b6c7d8e9f"
+                },
+                "exp": {
+                    "type": "integer",
+                    "example": 1792418400
+                },
+                "iat": {
+                    "type": "integer",
+                    "example": 1792414800
+                },
+                "scope": {
+                    "type": "string",
+                    "example": "orders:read"
+                },
+                "sub": {
+                    "type": "string",
+                    "example": "7"
+                },
+                "token_type": {
+                    "type": "string",
+                    "example": "Bearer"
+                },
+                "username": {
+                    "type": "string",
+                    "example": "user@example.com"
+                }
+            }
+        },
+        "dto.OAuthTokenResponse": {
+            "type": "object",
+            "properties": {
+                "access_token": {
+                    "type": "string",
+                    "example": "gat_Zm9vYmFyYmF6cXV4cXV1eGNvcmdlZ3JhdWx0Z2FycGx5"
+                },
+                "expires_in": {
+                    "type": "integer",
+                    "example": 3600
+                },
+                "scope": {
+                    "type": "string",
+                    "example": "orders:read"
+                },
+                "token_type": {
+                    "type": "string",
+                    "example": "Bearer"
+                }
+            }
+        },
+        "dto.OIDCAuthorizeResponse": {
+            "type": "object",
+            "properties": {
+                "authorization_url": {
+                    "description": "@Description URL of the identity provider to send the user to",
+                    "type": "string",
+                    "example": "https://login.example.com/authorize?client_id=go-api\u0026response_type=code"
+                },
+                "state": {
+                    "description": "@Description Opaque value the provider returns to the callback",
+                    "type": "string",
+                    "example": "Nf0yG8vVq3kz8T1Zb2Ew5w"
+                }
+            }
+        },
+        "dto.OptionTypeResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "description": "@Description Unique identifier of the option type\n@Example 1",
+                    "type": "integer",
+                    "example": 1
+                },
+                "name": {
+                    "description": "@Description Name of the option type\n@Example \"size\"",
+                    "type": "string",
+                    "example": "size"
+                },
+                "values": {
+                    "description": "@Description Values of the option type",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.OptionValueResponse"
+                    }
+                }
+            }
+        },
+        "dto.OptionValueResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "description": "@Description Unique identifier of the value\n@Example 11",
+                    "type": "integer",
+                    "example": 11
+                },
+                "position": {
+                    "description": "@Description Display position within the option type\n@Example 1",
+                    "type": "integer",
+                    "example": 1
+                },
+                "value": {
+                    "description": "@Description The value\n@Example \"M\"",
+                    "type": "string",
+                    "example": "M"
+                }
+            }
+        },
+        "dto.OrderItemResponse": {
+            "type": "object",
+            "properties": {
+                "discount": {
+                    "description": "@Description Share of the order discount given to the item",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.MoneyResponse"
+                        }
+                    ]
+                },
+                "id": {
+                    "description": "@Description Unique identifier of the item\n@Example 1",
+                    "type": "integer",
+                    "example": 1
+                },
+                "product_id": {
+                    "description": "@Description ID of the product, absent once the product is purged\n@Example 1",
+                    "type": "integer",
+                    "example": 1
+                },
+                "product_name": {
+                    "description": "@Description Name of the product at checkout\n@Example \"Camiseta\"",
+                    "type": "string",
+                    "example": "Camiseta"
+                },
+                "quantity": {
+                    "description": "@Description Quantity ordered\n@Example 2",
+                    "type": "integer",
+                    "example": 2
+                },
+                "sku": {
+                    "description": "@Description SKU of the variant or of the product at checkout\n@Example \"CAM-AZUL-M\"",
+                    "type": "string",
+                    "example": "CAM-AZUL-M"
+                },
+                "subtotal": {
+                    "description": "@Description Unit price times the quantity",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.MoneyResponse"
+                        }
+                    ]
+                },
+                "tax": {
+                    "description": "@Description Tax of the item after its discount",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.MoneyResponse"
+                        }
+                    ]
+                },
+                "unit_price": {
+                    "description": "@Description Unit price at checkout",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.MoneyResponse"
+                        }
+                    ]
+                },
+                "variant_id": {
+                    "description": "@Description ID of the variant, for products with variants\n@Example 2",
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "dto.OrderResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "description": "@Description When the order was placed\n@Example \"2026-03-01T12:00:00Z\"",
+                    "type": "string",
+                    "example": "2026-03-01T12:00:00Z"
+                },
+                "discount": {
+                    "description": "@Description Discount of the promotions applied",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.MoneyResponse"
+                        }
+                    ]
+                },
+                "history": {
+                    "description": "@Description Status changes, oldest first; only returned for a single order",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.OrderTransitionResponse"
+                    }
+                },
+                "id": {
+                    "description": "@Description Unique identifier of the order\n@Example 1",
+                    "type": "integer",
+                    "example": 1
+                },
+                "items": {
+                    "description": "@Description Items of the order",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.OrderItemResponse"
+                    }
+                },
+                "promotions": {
+                    "description": "@Description Promotions and discount codes applied; only returned for a single order",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.AppliedPromotionResponse"
+                    }
+                },
+                "status": {
+                    "description": "@Description Current status: pending, paid, shipped, delivered, cancelled or refunded\n@Example \"pending\"",
+                    "type": "string",
+                    "example": "pending"
+                },
+                "tax": {
+                    "description": "@Description Sum of the taxes of the items",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.MoneyResponse"
+                        }
+                    ]
+                },
+                "tax_destination": {
+                    "description": "@Description Destination whose tax rates were applied, absent for orders placed without taxes",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.TaxDestinationResponse"
+                        }
+                    ]
+                },
+                "tax_included": {
+                    "description": "@Description Whether the prices already included the taxes, which then did not add to the total\n@Example true",
+                    "type": "boolean",
+                    "example": true
+                },
+                "taxes": {
+                    "description": "@Description Sum of each tax over the items; only returned for a single order",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.TaxAmountResponse"
+                    }
+                },
+                "total": {
+                    "description": "@Description Sum of the items less the discount, plus the taxes not included in the prices",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.MoneyResponse"
+                        }
+                    ]
+                },
+                "updated_at": {
+                    "description": "@Description Last status change\n@Example \"2026-03-01T12:00:00Z\"",
+                    "type": "string",
+                    "example": "2026-03-01T12:00:00Z"
+                },
+                "user_id": {
+                    "description": "@Description ID of the user who placed the order\n@Example 7",
+                    "type": "integer",
+                    "example": 7
+                }
+            }
+        },
+        "dto.OrderTransitionResponse": {
+            "type": "object",
+            "properties": {
+                "actor_id": {
+                    "description": "@Description ID of the user who made the change\n@Example 1",
+                    "type": "integer",
+                    "example": 1
+                },
+                "from": {
+                    "description": "@Description Previous status, absent for the creation of the order\n@Example \"pending\"",
+                    "type": "string",
+                    "example": "pending"
+                },
+                "note": {
+                    "description": "@Description Note recorded with the change\n@Example \"Pagamento confirmado\"",
+                    "type": "string",
+                    "example": "Pagamento confirmado"
+                },
+                "occurred_at": {
+                    "description": "@Description When the change happened\n@Example \"2026-03-01T12:00:00Z\"",
+                    "type": "string",
+                    "example": "2026-03-01T12:00:00Z"
+                },
+                "to": {
+                    "description": "@Description New status\n@Example \"paid\"",
+                    "type": "string",
+                    "example": "paid"
+                }
+            }
+        },
+        "dto.PaymentResponse": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "@Description Amount charged",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.MoneyResponse"
+                        }
+                    ]
+                },
+                "created_at": {
+                    "description": "@Description When the payment was started\n@Example \"2026-03-01T12:00:00Z\"",
+                    "type": "string",
+                    "example": "2026-03-01T12:00:00Z"
+                },
+                "id": {
+                    "description": "@Description Unique identifier of the payment\n@Example 1",
+                    "type": "integer",
+                    "example": 1
+                },
+                "intent_id": {
+                    "description": "@Description ID of the payment at the provider\n@Example \"pi_3f9a...\"",
+                    "type": "string",
+                    "example": "pi_3f9a..."
+                },
+                "order_id": {
+                    "description": "@Description ID of the order\n@Example 10",
+                    "type": "integer",
+                    "example": 10
+                },
+                "provider": {
+                    "description": "@Description Payment provider\n@Example \"fake\"",
+                    "type": "string",
+                    "example": "fake"
+                },
+                "status": {
+                    "description": "@Description Status: pending, succeeded, failed or refunded\n@Example \"succeeded\"",
+                    "type": "string",
+                    "example": "succeeded"
+                },
+                "updated_at": {
+                    "description": "@Description Last status change\n@Example \"2026-03-01T12:00:00Z\"",
+                    "type": "string",
+                    "example": "2026-03-01T12:00:00Z"
+                }
+            }
+        },
+        "dto.PriceConversionResponse": {
+            "type": "object",
+            "properties": {
+                "original_price": {
+                    "description": "@Description Price of the product in its own currency",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.MoneyResponse"
+                        }
+                    ]
+                },
+                "price_list": {
+                    "description": "@Description Code of the price list that defined the price\n@Example \"usd-default\"",
+                    "type": "string",
+                    "example": "usd-default"
+                },
+                "rate": {
+                    "description": "@Description Exchange rate applied to the original price\n@Example \"0.1850000000\"",
+                    "type": "string",
+                    "example": "0.1850000000"
+                },
+                "rate_timestamp": {
+                    "description": "@Description When the exchange rate was last updated",
+                    "type": "string"
+                },
+                "rounding": {
+                    "description": "@Description Rounding rule applied to the converted amount\n@Example \"half_up\"",
+                    "type": "string",
+                    "example": "half_up"
+                },
+                "source": {
+                    "description": "@Description Where the price came from: price_list or exchange_rate\n@Example \"exchange_rate\"",
+                    "type": "string",
+                    "example": "exchange_rate"
+                }
+            }
+        },
+        "dto.PriceListItemResponse": {
+            "type": "object",
+            "properties": {
+                "price": {
+                    "description": "@Description Explicit price of the product",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.MoneyResponse"
+                        }
+                    ]
+                },
+                "product_id": {
+                    "description": "@Description Product the price applies to\n@Example 1",
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "dto.PriceListResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "@Description Unique code of the price list\n@Example \"usd-default\"",
+                    "type": "string",
+                    "example": "usd-default"
+                },
+                "currency": {
+                    "description": "@Description ISO 4217 currency of the prices in the list\n@Example \"USD\"",
+                    "type": "string",
+                    "example": "USD"
+                },
+                "id": {
+                    "description": "@Description Unique identifier of the price list\n@Example 1",
+                    "type": "integer",
+                    "example": 1
+                },
+                "market": {
+                    "description": "@Description Market the list applies to\n@Example \"US\"",
+                    "type": "string",
+                    "example": "US"
+                },
+                "name": {
+                    "description": "@Description Name of the price list\n@Example \"Preços em dólar\"",
+                    "type": "string",
+                    "example": "Preços em dólar"
+                }
+            }
+        },
+        "dto.PricedLineResponse": {
+            "type": "object",
+            "properties": {
+                "adjustments": {
+                    "description": "@Description Discount of each promotion on the line, in the order they were applied",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.AdjustmentResponse"
+                    }
+                },
+                "discount": {
+                    "description": "@Description Sum of the adjustments",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.MoneyResponse"
+                        }
+                    ]
+                },
+                "product_id": {
+                    "description": "@Description ID of the product\n@Example 1",
+                    "type": "integer",
+                    "example": 1
+                },
+                "quantity": {
+                    "description": "@Description Quantity in the cart\n@Example 2",
+                    "type": "integer",
+                    "example": 2
+                },
+                "subtotal": {
+                    "description": "@Description Unit price times the quantity",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.MoneyResponse"
+                        }
+                    ]
+                },
+                "total": {
+                    "description": "@Description Subtotal less the discount",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.MoneyResponse"
+                        }
+                    ]
+                },
+                "variant_id": {
+                    "description": "@Description ID of the variant, for products with variants\n@Example 2",
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "dto.ProductImageResponse": {
+            "type": "object",
+            "properties": {
+                "content_type": {
+                    "description": "@Description Content type sniffed from the upload\n@Example \"image/jpeg\"",
+                    "type": "string",
+                    "example": "image/jpeg"
+                },
+                "created_at": {
+                    "description": "@Description When the image was uploaded",
+                    "type": "string"
+                },
+                "height": {
+                    "description": "@Description Height in pixels\n@Example 800",
+                    "type": "integer",
+                    "example": 800
+                },
+                "id": {
+                    "description": "@Description Unique identifier of the image\n@Example 1",
+                    "type": "integer",
+                    "example": 1
+                },
+                "position": {
+                    "description": "@Description Position of the image, starting at 0\n@Example 0",
+                    "type": "integer",
+                    "example": 0
+                },
+                "primary": {
+                    "description": "@Description Whether this is the primary image of the product\n@Example true",
+                    "type": "boolean",
+                    "example": true
+                },
+                "size": {
+                    "description": "@Description Size of the original in bytes\n@Example 245760",
+                    "type": "integer",
+                    "example": 245760
+                },
+                "thumbnails": {
+                    "description": "@Description JPEG thumbnails keyed by size name (small, medium, large)",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/dto.ImageThumbnailResponse"
+                    }
+                },
+                "url": {
+                    "description": "@Description URL of the original image, signed and temporary for private storage\n@Example \"http://localhost:8000/media/products/1/9f86d081884c7d65/original.jpg\"",
+                    "type": "string",
+                    "example": "http://localhost:8000/media/products/1/9f86d081884c7d65/original.jpg"
+                },
+                "width": {
+                    "description": "@Description Width in pixels\n@Example 1200",
+                    "type": "integer",
+                    "example": 1200
+                }
+            }
+        },
+        "dto.ProductOptionResponse": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "description": "@Description Name of the option type\n@Example \"size\"",
+                    "type": "string",
+                    "example": "size"
+                },
+                "values": {
+                    "description": "@Description Values in use by the variants of the product\n@Example [\"M\", \"G\"]",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "M",
+                        "G"
+                    ]
+                }
+            }
+        },
+        "dto.ProductPriceResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "description": "@Description When the entry was recorded",
+                    "type": "string"
+                },
+                "effective_from": {
+                    "description": "@Description When the price takes effect",
+                    "type": "string"
+                },
+                "effective_to": {
+                    "description": "@Description When the price stops being in effect (sale prices only)",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "@Description Unique identifier of the entry\n@Example 1",
+                    "type": "integer",
+                    "example": 1
+                },
+                "kind": {
+                    "description": "@Description regular or sale\n@Example \"regular\"",
+                    "type": "string",
+                    "example": "regular"
+                },
+                "price": {
+                    "description": "@Description Price of the entry",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.MoneyResponse"
+                        }
+                    ]
+                },
+                "status": {
+                    "description": "@Description scheduled, active, overridden (by a sale), superseded or expired\n@Example \"active\"",
+                    "type": "string",
+                    "example": "active"
+                }
+            }
+        },
+        "dto.ProductResponse": {
+            "type": "object",
+            "properties": {
+                "conversion": {
+                    "description": "@Description How the price was obtained when a currency was requested",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.PriceConversionResponse"
+                        }
+                    ]
+                },
+                "created_at": {
+                    "description": "@Description When the product was created",
+                    "type": "string"
+                },
+                "created_by": {
+                    "description": "@Description User who created the product, absent for anonymous changes\n@Example 1",
+                    "type": "integer",
+                    "example": 1
+                },
+                "deleted_at": {
+                    "description": "@Description When the product was moved to the trash, present in trash listings",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "@Description Unique identifier of the product\n@Example 1",
+                    "type": "integer",
+                    "example": 1
+                },
+                "images": {
+                    "description": "@Description Images of the product ordered by position",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.ProductImageResponse"
+                    }
+                },
+                "name": {
+                    "description": "@Description Name of the product\n@Example \"iPhone 15\"",
+                    "type": "string",
+                    "example": "iPhone 15"
+                },
+                "options": {
+                    "description": "@Description Option types of the variant matrix with the values in use",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.ProductOptionResponse"
+                    }
+                },
+                "price": {
+                    "description": "@Description Price of the product",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.MoneyResponse"
+                        }
+                    ]
+                },
+                "sku": {
+                    "description": "@Description Stock keeping unit of the product, when it has one\n@Example \"IPH-15-128\"",
+                    "type": "string",
+                    "example": "IPH-15-128"
+                },
+                "tax_category": {
+                    "description": "@Description Tax category selecting the tax rates of the product\n@Example \"standard\"",
+                    "type": "string",
+                    "example": "standard"
+                },
+                "updated_at": {
+                    "description": "@Description When the product or its price history last changed",
+                    "type": "string"
+                },
+                "updated_by": {
+                    "description": "@Description User who last changed the product, absent for anonymous changes\n@Example 1",
+                    "type": "integer",
+                    "example": 1
+                },
+                "variants": {
+                    "description": "@Description Variants of the product",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.VariantResponse"
+                    }
+                }
+            }
+        },
+        "dto.PromotionResponse": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "description": "@Description Whether the promotion can be applied\n@Example true",
+                    "type": "boolean",
+                    "example": true
+                },
+                "amount": {
+                    "description": "@Description Discount of fixed promotions",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.MoneyResponse"
+                        }
+                    ]
+                },
+                "category_ids": {
+                    "description": "@Description Categories the promotion is restricted to",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "code": {
+                    "description": "@Description Discount code, absent for automatic promotions\n@Example \"BEMVINDO10\"",
+                    "type": "string",
+                    "example": "BEMVINDO10"
+                },
+                "created_at": {
+                    "description": "@Description When the promotion was created\n@Example \"2026-03-01T12:00:00Z\"",
+                    "type": "string",
+                    "example": "2026-03-01T12:00:00Z"
+                },
+                "ends_at": {
+                    "description": "@Description End of the validity, exclusive\n@Example \"2026-04-01T00:00:00Z\"",
+                    "type": "string",
+                    "example": "2026-04-01T00:00:00Z"
+                },
+                "id": {
+                    "description": "@Description Unique identifier of the promotion\n@Example 1",
+                    "type": "integer",
+                    "example": 1
+                },
+                "kind": {
+                    "description": "@Description Kind of discount: percentage or fixed\n@Example \"percentage\"",
+                    "type": "string",
+                    "example": "percentage"
+                },
+                "min_total": {
+                    "description": "@Description Minimum subtotal of the order",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.MoneyResponse"
+                        }
+                    ]
+                },
+                "name": {
+                    "description": "@Description Name shown in the price breakdown\n@Example \"Boas-vindas\"",
+                    "type": "string",
+                    "example": "Boas-vindas"
+                },
+                "per_user_limit": {
+                    "description": "@Description Maximum number of redemptions by each user\n@Example 1",
+                    "type": "integer",
+                    "example": 1
+                },
+                "percent": {
+                    "description": "@Description Discount of percentage promotions\n@Example 10",
+                    "type": "integer",
+                    "example": 10
+                },
+                "product_ids": {
+                    "description": "@Description Products the promotion is restricted to",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "stackable": {
+                    "description": "@Description Whether the promotion combines with the other stackable ones\n@Example false",
+                    "type": "boolean",
+                    "example": false
+                },
+                "starts_at": {
+                    "description": "@Description Start of the validity\n@Example \"2026-03-01T00:00:00Z\"",
+                    "type": "string",
+                    "example": "2026-03-01T00:00:00Z"
+                },
+                "usage_limit": {
+                    "description": "@Description Maximum number of redemptions by everyone\n@Example 100",
+                    "type": "integer",
+                    "example": 100
+                },
+                "uses": {
+                    "description": "@Description Redemptions so far, not counting cancelled orders\n@Example 12",
+                    "type": "integer",
+                    "example": 12
+                }
+            }
+        },
+        "dto.RejectedCodeResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "@Description Code as given, in upper case\n@Example \"VERAO\"",
+                    "type": "string",
+                    "example": "VERAO"
+                },
+                "reason": {
+                    "description": "@Description Why the code was not applied\n@Example \"the code has expired\"",
+                    "type": "string",
+                    "example": "the code has expired"
+                }
+            }
+        },
+        "dto.ReorderImagesRequest": {
+            "type": "object",
+            "required": [
+                "image_ids"
+            ],
+            "properties": {
+                "image_ids": {
+                    "description": "@Description Every image ID of the product exactly once, in the new order\n@Example [3, 1, 2]",
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        3,
+                        1,
+                        2
+                    ]
+                }
+            }
+        },
+        "dto.ResendVerificationRequest": {
+            "type": "object",
+            "required": [
+                "email"
+            ],
+            "properties": {
+                "email": {
+                    "description": "@Description Current email of the account\n@Example \"user@example.com\"",
+                    "type": "string",
+                    "example": "user@example.com"
+                }
+            }
+        },
+        "dto.ResetPasswordRequest": {
+            "type": "object",
+            "required": [
+                "password",
+                "token"
+            ],
+            "properties": {
+                "password": {
+                    "description": "@Description New password of the user\n@Example \"correct horse battery staple\"",
+                    "type": "string",
+                    "example": "correct horse battery staple"
+                },
+                "token": {
+                    "description": "@Description Token of the link sent by email\n@Example \"q3Jb6m0n2x8V4tQy1Zk7cR5sW9hL0pXaE2dF6gH8iJ4\"",
+                    "type": "string",
+                    "example": "q3Jb6m0n2x8V4tQy1Zk7cR5sW9hL0pXaE2dF6gH8iJ4"
+                }
+            }
+        },
+        "dto.SchedulePriceRequest": {
+            "type": "object",
+            "required": [
+                "price"
+            ],
+            "properties": {
+                "effective_from": {
+                    "description": "@Description When the price takes effect, defaults to now",
+                    "type": "string"
+                },
+                "effective_to": {
+                    "description": "@Description When a sale price ends",
+                    "type": "string"
+                },
+                "kind": {
+                    "description": "@Description regular (lasts until superseded) or sale (temporary, needs effective_to)\n@Example \"sale\"",
+                    "type": "string",
+                    "enum": [
+                        "regular",
+                        "sale"
+                    ],
+                    "example": "sale"
+                },
+                "price": {
+                    "description": "@Description New price in the product currency, as a decimal string\n@Example \"19.90\"",
+                    "type": "string",
+                    "example": "19.90"
+                }
+            }
+        },
+        "dto.SetExchangeRatesRequest": {
+            "type": "object",
+            "required": [
+                "rates"
+            ],
+            "properties": {
+                "rates": {
+                    "description": "@Description Rates to insert or replace",
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/dto.ExchangeRateRequest"
+                    }
+                }
+            }
+        },
+        "dto.SetPriceListItemRequest": {
+            "type": "object",
+            "required": [
+                "price"
+            ],
+            "properties": {
+                "price": {
+                    "description": "@Description Price in the currency of the list, as a decimal string\n@Example \"199.90\"",
+                    "type": "string",
+                    "example": "199.90"
+                }
+            }
+        },
+        "dto.SetProductCategoriesRequest": {
+            "type": "object",
+            "required": [
+                "category_ids"
+            ],
+            "properties": {
+                "category_ids": {
+                    "description": "@Description IDs of the categories the product belongs to\n@Example [1, 2]",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        1,
+                        2
+                    ]
+                }
+            }
+        },
+        "dto.SetPromotionActiveRequest": {
             "type": "object",
             "required": [
-                "name",
-                "price"
+                "active"
+            ],
+            "properties": {
+                "active": {
+                    "description": "@Description Whether the promotion can be applied\n@Example false",
+                    "type": "boolean",
+                    "example": false
+                }
+            }
+        },
+        "dto.SetTaxCategoryRequest": {
+            "type": "object",
+            "required": [
+                "tax_category"
             ],
             "properties": {
+                "tax_category": {
+                    "description": "@Description One of the categories of the rate table\n@Example \"reduced\"",
+                    "type": "string",
+                    "example": "reduced"
+                }
+            }
+        },
+        "dto.TaxAmountResponse": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "@Description Amount of the tax",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.MoneyResponse"
+                        }
+                    ]
+                },
                 "name": {
-                    "description": "@Description Name of the product\n@Example \"iPhone 15\"",
+                    "description": "@Description Name of the tax\n@Example \"ICMS\"",
                     "type": "string",
-                    "example": "iPhone 15"
+                    "example": "ICMS"
                 },
-                "price": {
-                    "description": "@Description Price of the product\n@Example 999.99",
-                    "type": "number",
-                    "minimum": 0,
-                    "example": 999.99
+                "percent": {
+                    "description": "@Description Rate in percent\n@Example \"18\"",
+                    "type": "string",
+                    "example": "18"
                 }
             }
         },
-        "dto.CreateUserRequest": {
+        "dto.TaxBreakdownResponse": {
+            "type": "object",
+            "properties": {
+                "destination": {
+                    "description": "@Description Destination whose rates were applied",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.TaxDestinationResponse"
+                        }
+                    ]
+                },
+                "included": {
+                    "description": "@Description Whether the prices already include the taxes, which then do not add to the total\n@Example true",
+                    "type": "boolean",
+                    "example": true
+                },
+                "lines": {
+                    "description": "@Description Tax of each line, in the order of the priced lines",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.LineTaxResponse"
+                    }
+                },
+                "rounding": {
+                    "description": "@Description Rounding of the taxes: line or total\n@Example \"line\"",
+                    "type": "string",
+                    "example": "line"
+                },
+                "taxes": {
+                    "description": "@Description Sum of each tax over the lines",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.TaxAmountResponse"
+                    }
+                },
+                "total": {
+                    "description": "@Description Sum of the taxes",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.MoneyResponse"
+                        }
+                    ]
+                }
+            }
+        },
+        "dto.TaxCategoriesResponse": {
+            "type": "object",
+            "properties": {
+                "categories": {
+                    "description": "@Description Categories declared in the rate table\n@Example [\"standard\", \"reduced\", \"exempt\"]",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "standard",
+                        "reduced",
+                        "exempt"
+                    ]
+                },
+                "default": {
+                    "description": "@Description Category of the products that were not assigned one\n@Example \"standard\"",
+                    "type": "string",
+                    "example": "standard"
+                }
+            }
+        },
+        "dto.TaxDestinationRequest": {
             "type": "object",
             "required": [
-                "email",
-                "name",
-                "password"
+                "country"
             ],
             "properties": {
-                "email": {
-                    "description": "@Description Email of the user\n@Example \"user@example.com\"",
+                "country": {
+                    "description": "@Description ISO 3166-1 alpha-2 country code\n@Example \"BR\"",
                     "type": "string",
-                    "example": "user@example.com"
+                    "example": "BR"
+                },
+                "state": {
+                    "description": "@Description State, for countries that tax by state\n@Example \"SP\"",
+                    "type": "string",
+                    "maxLength": 8,
+                    "example": "SP"
+                }
+            }
+        },
+        "dto.TaxDestinationResponse": {
+            "type": "object",
+            "properties": {
+                "country": {
+                    "description": "@Description ISO 3166-1 alpha-2 country code\n@Example \"BR\"",
+                    "type": "string",
+                    "example": "BR"
+                },
+                "state": {
+                    "description": "@Description State, when one was given\n@Example \"SP\"",
+                    "type": "string",
+                    "example": "SP"
+                }
+            }
+        },
+        "dto.TaxRateResponse": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "description": "@Description Tax category the rate applies to\n@Example \"standard\"",
+                    "type": "string",
+                    "example": "standard"
+                },
+                "effective_from": {
+                    "description": "@Description Start of the version\n@Example \"2023-01-01T03:00:00Z\"",
+                    "type": "string",
+                    "example": "2023-01-01T03:00:00Z"
+                },
+                "effective_to": {
+                    "description": "@Description End of the version, exclusive; absent while it has no end",
+                    "type": "string"
                 },
                 "name": {
-                    "description": "@Description Name of the user\n@Example \"Leandro\"",
+                    "description": "@Description Name of the tax\n@Example \"ICMS\"",
                     "type": "string",
-                    "example": "Leandro"
+                    "example": "ICMS"
                 },
-                "password": {
-                    "description": "@Description Password of the user\n@Example \"password123\"",
+                "percent": {
+                    "description": "@Description Rate in percent\n@Example \"18\"",
                     "type": "string",
-                    "minLength": 6,
-                    "example": "password123"
+                    "example": "18"
                 }
             }
         },
-        "dto.LoginRequest": {
+        "dto.TaxRegionResponse": {
+            "type": "object",
+            "properties": {
+                "destination": {
+                    "description": "@Description Destination of the rates",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.TaxDestinationResponse"
+                        }
+                    ]
+                },
+                "prices_include_tax": {
+                    "description": "@Description Whether prices already include the taxes at the destination\n@Example true",
+                    "type": "boolean",
+                    "example": true
+                },
+                "rates": {
+                    "description": "@Description Rates in effect, the country rates first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.TaxRateResponse"
+                    }
+                },
+                "rounding": {
+                    "description": "@Description Rounding of the taxes: line or total\n@Example \"line\"",
+                    "type": "string",
+                    "example": "line"
+                },
+                "version": {
+                    "description": "@Description Version of the rate table\n@Example \"2026-01-01\"",
+                    "type": "string",
+                    "example": "2026-01-01"
+                }
+            }
+        },
+        "dto.TransitionOrderRequest": {
             "type": "object",
             "required": [
-                "email",
-                "password"
+                "status"
             ],
             "properties": {
-                "email": {
-                    "type": "string"
+                "note": {
+                    "description": "@Description Optional note recorded with the change\n@Example \"Enviado pelos Correios\"",
+                    "type": "string",
+                    "maxLength": 500,
+                    "example": "Enviado pelos Correios"
                 },
-                "password": {
-                    "type": "string"
+                "status": {
+                    "description": "@Description New status: shipped, delivered or cancelled; paid and refunded follow the payments\n@Example \"shipped\"",
+                    "type": "string",
+                    "example": "shipped"
                 }
             }
         },
-        "dto.LoginResponse": {
+        "dto.UpdateCartItemRequest": {
             "type": "object",
+            "required": [
+                "quantity"
+            ],
             "properties": {
-                "token": {
-                    "type": "string"
+                "quantity": {
+                    "description": "@Description New quantity of the item\n@Example 3",
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 3
                 }
             }
         },
-        "dto.ProductResponse": {
+        "dto.UpdateCategoryRequest": {
             "type": "object",
             "properties": {
-                "id": {
-                    "description": "@Description Unique identifier of the product\n@Example 1",
-                    "type": "integer",
-                    "example": 1
-                },
                 "name": {
-                    "description": "@Description Name of the product\n@Example \"iPhone 15\"",
+                    "description": "@Description Name of the category\n@Example \"Camisetas\"",
                     "type": "string",
-                    "example": "iPhone 15"
+                    "example": "Camisetas"
                 },
-                "price": {
-                    "description": "@Description Price of the product\n@Example 999.99",
-                    "type": "number",
-                    "example": 999.99
+                "slug": {
+                    "description": "@Description Slug of the category\n@Example \"camisetas\"",
+                    "type": "string",
+                    "example": "camisetas"
                 }
             }
         },
@@ -534,20 +9369,84 @@ const docTemplate = `{
                     "example": "Leandro"
                 },
                 "password": {
-                    "description": "@Description Password of the user\n@Example \"newpassword123\"",
+                    "description": "@Description Password of the user\n@Example \"correct horse battery staple\"",
+                    "type": "string",
+                    "example": "correct horse battery staple"
+                }
+            }
+        },
+        "dto.UpdateVariantRequest": {
+            "type": "object",
+            "required": [
+                "sku"
+            ],
+            "properties": {
+                "price_override": {
+                    "description": "@Description Price replacing the product price; omit to inherit the product price\n@Example \"54.90\"",
+                    "type": "string",
+                    "example": "54.90"
+                },
+                "sku": {
+                    "description": "@Description Unique stock keeping unit, stored in upper case\n@Example \"CAM-AZUL-M\"",
+                    "type": "string",
+                    "example": "CAM-AZUL-M"
+                },
+                "stock": {
+                    "description": "@Description Units in stock\n@Example 10",
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 10
+                }
+            }
+        },
+        "dto.UserIdentityResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "description": "@Description When the identity was linked",
+                    "type": "string"
+                },
+                "email": {
+                    "description": "@Description Email the provider reported at the last sign in\n@Example \"user@example.com\"",
+                    "type": "string",
+                    "example": "user@example.com"
+                },
+                "last_login_at": {
+                    "description": "@Description When the user last signed in with the identity",
+                    "type": "string"
+                },
+                "provider": {
+                    "description": "@Description Name of the identity provider\n@Example \"corp\"",
                     "type": "string",
-                    "example": "newpassword123"
+                    "example": "corp"
                 }
             }
         },
         "dto.UserResponse": {
             "type": "object",
             "properties": {
+                "created_at": {
+                    "description": "@Description When the user was created",
+                    "type": "string"
+                },
+                "created_by": {
+                    "description": "@Description User who created the user, absent for sign ups\n@Example 1",
+                    "type": "integer",
+                    "example": 1
+                },
+                "deleted_at": {
+                    "description": "@Description When the user was moved to the trash, present in trash listings",
+                    "type": "string"
+                },
                 "email": {
                     "description": "@Description Email of the user\n@Example \"user@example.com\"",
                     "type": "string",
                     "example": "user@example.com"
                 },
+                "email_verified_at": {
+                    "description": "@Description When the user confirmed the email, absent while unverified",
+                    "type": "string"
+                },
                 "id": {
                     "description": "@Description Unique identifier of the user\n@Example 1",
                     "type": "integer",
@@ -557,6 +9456,147 @@ const docTemplate = `{
                     "description": "@Description Name of the user\n@Example \"Leandro\"",
                     "type": "string",
                     "example": "Leandro"
+                },
+                "pending_email": {
+                    "description": "@Description New email waiting for confirmation, absent when none\n@Example \"new@example.com\"",
+                    "type": "string",
+                    "example": "new@example.com"
+                },
+                "role": {
+                    "description": "@Description Role of the user, present in exports\n@Example \"customer\"",
+                    "type": "string",
+                    "example": "customer"
+                },
+                "updated_at": {
+                    "description": "@Description When the user was last changed",
+                    "type": "string"
+                },
+                "updated_by": {
+                    "description": "@Description User who last changed the user, absent for anonymous changes\n@Example 1",
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "dto.VariantOptionResponse": {
+            "type": "object",
+            "properties": {
+                "option_type": {
+                    "description": "@Description Name of the option type\n@Example \"size\"",
+                    "type": "string",
+                    "example": "size"
+                },
+                "option_value_id": {
+                    "description": "@Description ID of the option value\n@Example 11",
+                    "type": "integer",
+                    "example": 11
+                },
+                "value": {
+                    "description": "@Description The option value\n@Example \"M\"",
+                    "type": "string",
+                    "example": "M"
+                }
+            }
+        },
+        "dto.VariantResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "description": "@Description Unique identifier of the variant\n@Example 1",
+                    "type": "integer",
+                    "example": 1
+                },
+                "options": {
+                    "description": "@Description Option values of the variant",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.VariantOptionResponse"
+                    }
+                },
+                "price": {
+                    "description": "@Description Price of the variant: its override or the product price",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.MoneyResponse"
+                        }
+                    ]
+                },
+                "price_overridden": {
+                    "description": "@Description Whether the price overrides the product price\n@Example false",
+                    "type": "boolean",
+                    "example": false
+                },
+                "sku": {
+                    "description": "@Description Stock keeping unit\n@Example \"CAM-AZUL-M\"",
+                    "type": "string",
+                    "example": "CAM-AZUL-M"
+                },
+                "stock": {
+                    "description": "@Description Units in stock\n@Example 10",
+                    "type": "integer",
+                    "example": 10
+                }
+            }
+        },
+        "model.AuditEvent": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "actor_id": {
+                    "description": "ActorID is nil for anonymous requests (e.g. sign up)",
+                    "type": "integer"
+                },
+                "changes": {
+                    "description": "Changes maps each changed field to its before and after values, secrets redacted",
+                    "type": "object"
+                },
+                "entity_id": {
+                    "type": "string"
+                },
+                "entity_type": {
+                    "type": "string"
+                },
+                "hash": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "ip": {
+                    "type": "string"
+                },
+                "occurred_at": {
+                    "type": "string"
+                },
+                "prev_hash": {
+                    "type": "string"
+                },
+                "request_id": {
+                    "type": "string"
+                },
+                "user_agent": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.AuditVerification": {
+            "type": "object",
+            "properties": {
+                "broken_at": {
+                    "description": "BrokenAt is the first event whose hash does not match, when the chain is broken",
+                    "type": "integer"
+                },
+                "checked": {
+                    "type": "integer"
+                },
+                "last_hash": {
+                    "description": "LastHash is the hash of the newest event; kept somewhere else, it also\nreveals events removed from the end of the log",
+                    "type": "string"
+                },
+                "valid": {
+                    "type": "boolean"
                 }
             }
         },
@@ -571,15 +9611,73 @@ const docTemplate = `{
             }
         }
     },
+    "securityDefinitions": {
+        "APIKeyAuth": {
+            "description": "Chave de API criada em /api-keys, limitada aos seus escopos; também aceita como \"Bearer \u003cchave\u003e\"",
+            "type": "apiKey",
+            "name": "X-API-Key",
+            "in": "header"
+        },
+        "BearerAuth": {
+            "description": "Token JWT obtido em /login, ou access token OAuth emitido em /oauth/token, no formato \"Bearer \u003ctoken\u003e\"",
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
+        }
+    },
     "tags": [
         {
             "description": "Operações relacionadas a produtos",
             "name": "products"
         },
+        {
+            "description": "Operações da taxonomia de categorias de produtos",
+            "name": "categories"
+        },
+        {
+            "description": "Tipos de opção (tamanho, cor) e variantes de produtos",
+            "name": "variants"
+        },
+        {
+            "description": "Upload, ordem e imagem principal das imagens de produtos",
+            "name": "images"
+        },
+        {
+            "description": "Listas de preços e taxas de câmbio",
+            "name": "pricing"
+        },
+        {
+            "description": "Lixeira de produtos e usuários excluídos: restauração e exclusão definitiva",
+            "name": "trash"
+        },
+        {
+            "description": "Log de auditoria das alterações em usuários, produtos e categorias",
+            "name": "audit"
+        },
+        {
+            "description": "Carrinho de compras do usuário autenticado ou da sessão anônima",
+            "name": "cart"
+        },
+        {
+            "description": "Checkout e pedidos, com o ciclo de status pending, paid, shipped, delivered, cancelled e refunded",
+            "name": "orders"
+        },
+        {
+            "description": "Cupons de desconto e promoções automáticas, com regras de elegibilidade, limites de uso e acúmulo",
+            "name": "promotions"
+        },
+        {
+            "description": "Categorias fiscais dos produtos e tabelas de alíquotas por país e estado",
+            "name": "taxes"
+        },
         {
             "description": "Operações relacionadas a usuários",
             "name": "users"
         },
+        {
+            "description": "Recuperação de acesso, verificação de email, autenticação em dois fatores e bloqueio de logins após falhas repetidas",
+            "name": "auth"
+        },
         {
             "description": "Endpoints de verificação de saúde da API",
             "name": "health"
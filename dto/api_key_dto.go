@@ -0,0 +1,82 @@
+package dto
+
+import "time"
+
+// CreateAPIKeyRequest represents the request body for creating an API key
+type CreateAPIKeyRequest struct {
+	// @Description User or service account the key acts as
+	// @Example 9
+	UserID int `json:"user_id" binding:"required,min=1" example:"9"`
+
+	// @Description Name telling what the key is for
+	// @Example "Integração do armazém"
+	Name string `json:"name" binding:"required,max=100" example:"Integração do armazém"`
+
+	// @Description Scopes granted: products:read, products:write, orders:read, orders:write
+	// @Example ["products:read","products:write"]
+	Scopes []string `json:"scopes" binding:"required,min=1"`
+
+	// @Description IPs and CIDR ranges the key works from; empty accepts any
+	// @Example ["203.0.113.0/24"]
+	AllowedIPs []string `json:"allowed_ips,omitempty"`
+
+	// @Description When the key stops working; absent for keys that do not expire
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// APIKeyResponse represents an API key, without its secret
+type APIKeyResponse struct {
+	// @Description Unique identifier of the key
+	// @Example 4
+	ID int `json:"id" example:"4"`
+
+	// @Description User or service account the key acts as
+	// @Example 9
+	UserID int `json:"user_id" example:"9"`
+
+	// @Description Name telling what the key is for
+	// @Example "Integração do armazém"
+	Name string `json:"name" example:"Integração do armazém"`
+
+	// @Description Start of the key, to tell it apart
+	// @Example "gak_3f9a1c0b7d2e"
+	Prefix string `json:"prefix" example:"gak_3f9a1c0b7d2e"`
+
+	// @Description Scopes granted
+	Scopes []string `json:"scopes"`
+
+	// @Description IPs and CIDR ranges the key works from; empty accepts any
+	AllowedIPs []string `json:"allowed_ips"`
+
+	// @Description When the key stops working, absent when it does not expire
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+
+	// @Description Last use of the key, recorded at most once a minute
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+
+	// @Description IP of the last use
+	// @Example "203.0.113.9"
+	LastUsedIP string `json:"last_used_ip,omitempty" example:"203.0.113.9"`
+
+	// @Description When the key was created
+	CreatedAt time.Time `json:"created_at"`
+
+	// @Description Admin who created the key
+	CreatedBy *int `json:"created_by,omitempty"`
+
+	// @Description Last rotation of the secret
+	RotatedAt *time.Time `json:"rotated_at,omitempty"`
+
+	// @Description When the key was revoked, absent while active
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+}
+
+// APIKeySecretResponse represents a new or rotated API key with its secret,
+// shown only once
+type APIKeySecretResponse struct {
+	// @Description The key, sent in the X-API-Key header or as a bearer token
+	// @Example "gak_3f9a1c0b7d2e_Zm9vYmFyYmF6cXV4cXV1eGNvcmdlZ3JhdWx0Z2FycGx5"
+	Key string `json:"key" example:"gak_3f9a1c0b7d2e_Zm9vYmFyYmF6cXV4cXV1eGNvcmdlZ3JhdWx0Z2FycGx5"`
+
+	APIKey APIKeyResponse `json:"api_key"`
+}
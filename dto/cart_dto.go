@@ -0,0 +1,94 @@
+package dto
+
+import "time"
+
+// AddCartItemRequest represents the request body for adding an item to the cart
+type AddCartItemRequest struct {
+	// @Description ID of the product
+	// @Example 1
+	ProductID int `json:"product_id" binding:"required,min=1" example:"1"`
+
+	// @Description ID of the variant, required for products with variants
+	// @Example 2
+	VariantID *int `json:"variant_id,omitempty" binding:"omitempty,min=1" example:"2"`
+
+	// @Description Quantity to add to the line of the product or variant
+	// @Example 1
+	Quantity int `json:"quantity" binding:"required,min=1" example:"1"`
+}
+
+// UpdateCartItemRequest represents the request body for changing the quantity of a cart item
+type UpdateCartItemRequest struct {
+	// @Description New quantity of the item
+	// @Example 3
+	Quantity int `json:"quantity" binding:"required,min=1" example:"3"`
+}
+
+// CartItemResponse represents a line of the cart priced at the current prices
+type CartItemResponse struct {
+	// @Description Unique identifier of the item
+	// @Example 1
+	ID int `json:"id" example:"1"`
+
+	// @Description ID of the product
+	// @Example 1
+	ProductID int `json:"product_id" example:"1"`
+
+	// @Description ID of the variant, for products with variants
+	// @Example 2
+	VariantID *int `json:"variant_id,omitempty" example:"2"`
+
+	// @Description Name of the product
+	// @Example "Camiseta"
+	ProductName string `json:"product_name" example:"Camiseta"`
+
+	// @Description SKU of the variant or of the product
+	// @Example "CAM-AZUL-M"
+	SKU string `json:"sku,omitempty" example:"CAM-AZUL-M"`
+
+	// @Description Quantity of the item
+	// @Example 2
+	Quantity int `json:"quantity" example:"2"`
+
+	// @Description Current unit price
+	UnitPrice MoneyResponse `json:"unit_price"`
+
+	// @Description Unit price when the item was added
+	AddedPrice MoneyResponse `json:"added_price"`
+
+	// @Description Whether the unit price changed since the item was added
+	// @Example false
+	PriceChanged bool `json:"price_changed" example:"false"`
+
+	// @Description Current unit price times the quantity
+	Subtotal MoneyResponse `json:"subtotal"`
+
+	// @Description Units in stock, only for variants
+	// @Example 10
+	Stock *int `json:"stock,omitempty" example:"10"`
+
+	// @Description False when the product is no longer sold or the stock does not cover the quantity
+	// @Example true
+	Available bool `json:"available" example:"true"`
+}
+
+// CartResponse represents the cart of a user or of an anonymous session
+type CartResponse struct {
+	// @Description Unique identifier of the cart, 0 while the cart is empty and was never created
+	// @Example 1
+	ID int `json:"id" example:"1"`
+
+	// @Description Token of a new anonymous cart, also sent in the X-Cart-Token header; only returned once
+	// @Example "4f1c2e..."
+	Token string `json:"token,omitempty" example:"4f1c2e..."`
+
+	// @Description Items of the cart
+	Items []CartItemResponse `json:"items"`
+
+	// @Description Sum of the available items, one entry per currency
+	Subtotals []MoneyResponse `json:"subtotals"`
+
+	// @Description Last change of the cart
+	// @Example "2026-03-01T12:00:00Z"
+	UpdatedAt *time.Time `json:"updated_at,omitempty" example:"2026-03-01T12:00:00Z"`
+}
@@ -0,0 +1,67 @@
+package dto
+
+// CreateCategoryRequest represents the request body for creating a category
+type CreateCategoryRequest struct {
+	// @Description Name of the category
+	// @Example "Camisetas"
+	Name string `json:"name" binding:"required" example:"Camisetas"`
+
+	// @Description Slug of the category, derived from the name when omitted. Must be unique among its siblings
+	// @Example "camisetas"
+	Slug string `json:"slug,omitempty" example:"camisetas"`
+
+	// @Description Parent category, omitted or null for a root category
+	// @Example 1
+	ParentID *int `json:"parent_id,omitempty" example:"1"`
+}
+
+// UpdateCategoryRequest represents the request body for renaming a category
+type UpdateCategoryRequest struct {
+	// @Description Name of the category
+	// @Example "Camisetas"
+	Name string `json:"name,omitempty" example:"Camisetas"`
+
+	// @Description Slug of the category
+	// @Example "camisetas"
+	Slug string `json:"slug,omitempty" example:"camisetas"`
+}
+
+// MoveCategoryRequest represents the request body for moving a category subtree
+type MoveCategoryRequest struct {
+	// @Description New parent category, null to move the subtree to the root
+	// @Example 2
+	ParentID *int `json:"parent_id" example:"2"`
+}
+
+// SetProductCategoriesRequest represents the request body for assigning categories to a product
+type SetProductCategoriesRequest struct {
+	// @Description IDs of the categories the product belongs to
+	// @Example [1, 2]
+	CategoryIDs []int `json:"category_ids" binding:"required" example:"1,2"`
+}
+
+// CategoryResponse represents the response body for category operations
+type CategoryResponse struct {
+	// @Description Unique identifier of the category
+	// @Example 1
+	ID int `json:"id" example:"1"`
+
+	// @Description Parent category, null for root categories
+	// @Example 1
+	ParentID *int `json:"parent_id" example:"1"`
+
+	// @Description Name of the category
+	// @Example "Camisetas"
+	Name string `json:"name" example:"Camisetas"`
+
+	// @Description Slug of the category
+	// @Example "camisetas"
+	Slug string `json:"slug" example:"camisetas"`
+
+	// @Description Materialized path of the category
+	// @Example "roupas/camisetas"
+	Path string `json:"path" example:"roupas/camisetas"`
+
+	// @Description Subcategories
+	Children []CategoryResponse `json:"children,omitempty"`
+}
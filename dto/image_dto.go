@@ -0,0 +1,66 @@
+package dto
+
+import "time"
+
+// ReorderImagesRequest represents the request body for reordering the images of a product
+type ReorderImagesRequest struct {
+	// @Description Every image ID of the product exactly once, in the new order
+	// @Example [3, 1, 2]
+	ImageIDs []int `json:"image_ids" binding:"required,min=1" example:"3,1,2"`
+}
+
+// ProductImageResponse represents an image of a product
+type ProductImageResponse struct {
+	// @Description Unique identifier of the image
+	// @Example 1
+	ID int `json:"id" example:"1"`
+
+	// @Description URL of the original image, signed and temporary for private storage
+	// @Example "http://localhost:8000/media/products/1/9f86d081884c7d65/original.jpg"
+	URL string `json:"url" example:"http://localhost:8000/media/products/1/9f86d081884c7d65/original.jpg"`
+
+	// @Description Content type sniffed from the upload
+	// @Example "image/jpeg"
+	ContentType string `json:"content_type" example:"image/jpeg"`
+
+	// @Description Width in pixels
+	// @Example 1200
+	Width int `json:"width" example:"1200"`
+
+	// @Description Height in pixels
+	// @Example 800
+	Height int `json:"height" example:"800"`
+
+	// @Description Size of the original in bytes
+	// @Example 245760
+	Size int64 `json:"size" example:"245760"`
+
+	// @Description Position of the image, starting at 0
+	// @Example 0
+	Position int `json:"position" example:"0"`
+
+	// @Description Whether this is the primary image of the product
+	// @Example true
+	Primary bool `json:"primary" example:"true"`
+
+	// @Description JPEG thumbnails keyed by size name (small, medium, large)
+	Thumbnails map[string]ImageThumbnailResponse `json:"thumbnails"`
+
+	// @Description When the image was uploaded
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ImageThumbnailResponse represents a thumbnail of a product image
+type ImageThumbnailResponse struct {
+	// @Description URL of the thumbnail
+	// @Example "http://localhost:8000/media/products/1/9f86d081884c7d65/small.jpg"
+	URL string `json:"url" example:"http://localhost:8000/media/products/1/9f86d081884c7d65/small.jpg"`
+
+	// @Description Width in pixels
+	// @Example 150
+	Width int `json:"width" example:"150"`
+
+	// @Description Height in pixels
+	// @Example 100
+	Height int `json:"height" example:"100"`
+}
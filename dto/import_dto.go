@@ -0,0 +1,65 @@
+package dto
+
+import "time"
+
+// ImportJobResponse represents the progress and outcome of a bulk import
+type ImportJobResponse struct {
+	// @Description Identifier used to poll the job
+	// @Example "6f1d2c3b4a5968778695a4b3"
+	ID string `json:"id" example:"6f1d2c3b4a5968778695a4b3"`
+
+	// @Description pending, running, completed or failed
+	// @Example "running"
+	Status string `json:"status" example:"running"`
+
+	// @Description csv or ndjson
+	// @Example "csv"
+	Format string `json:"format" example:"csv"`
+
+	// @Description Whether the job only validates; created and updated then count what would happen
+	// @Example false
+	DryRun bool `json:"dry_run" example:"false"`
+
+	// @Description Share of the file processed so far, from 0 to 100
+	// @Example 42
+	Progress int `json:"progress" example:"42"`
+
+	// @Description Rows read so far
+	// @Example 8400
+	Rows int `json:"rows" example:"8400"`
+
+	// @Description Products created
+	// @Example 8000
+	Created int `json:"created" example:"8000"`
+
+	// @Description Existing products updated, matched by SKU or name
+	// @Example 390
+	Updated int `json:"updated" example:"390"`
+
+	// @Description Rows rejected
+	// @Example 10
+	Failed int `json:"failed" example:"10"`
+
+	// @Description Rejected rows with the reason, in file order
+	Errors []ImportRowErrorResponse `json:"errors"`
+
+	// @Description Why the whole job failed, e.g. a missing column
+	Error string `json:"error,omitempty"`
+
+	// @Description When the job was started
+	CreatedAt time.Time `json:"created_at"`
+
+	// @Description When the job finished
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+// ImportRowErrorResponse represents a rejected row of an import file
+type ImportRowErrorResponse struct {
+	// @Description Line of the row in the file, starting at 1
+	// @Example 12
+	Line int `json:"line" example:"12"`
+
+	// @Description Why the row was rejected
+	// @Example "duplicate of line 4"
+	Message string `json:"message" example:"duplicate of line 4"`
+}
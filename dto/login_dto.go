@@ -4,9 +4,19 @@ package dto
 type LoginRequest struct {
 	Email    string `json:"email" binding:"required,email"`
 	Password string `json:"password" binding:"required"`
+	// CartToken is the token of the anonymous cart to merge into the user's cart;
+	// the controller falls back to the X-Cart-Token header
+	CartToken string `json:"cart_token,omitempty"`
 }
 
-// LoginResponse represents the response body for user login
+// LoginResponse represents the response body for user login. A user with
+// two-factor authentication gets no token but an MFA challenge, to be sent
+// with a code to /auth/mfa/verify
 type LoginResponse struct {
-	Token string `json:"token"`
+	Token       string `json:"token,omitempty"`
+	MFARequired bool   `json:"mfa_required,omitempty"`
+	MFAToken    string `json:"mfa_token,omitempty"`
+	// MFAEnrollmentRequired tells that the role of the user demands
+	// two-factor authentication, which is not on yet
+	MFAEnrollmentRequired bool `json:"mfa_enrollment_required,omitempty"`
 }
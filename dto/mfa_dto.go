@@ -0,0 +1,32 @@
+package dto
+
+// MFAEnrollResponse represents the response body of a two-factor enrollment:
+// the secret to type in an authenticator app, or the otpauth URI to show as
+// a QR code
+type MFAEnrollResponse struct {
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauth_uri"`
+}
+
+// MFACodeRequest represents the request body carrying a code of the
+// authenticator app or, where accepted, a recovery code
+type MFACodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// MFAVerifyRequest represents the request body exchanging an MFA challenge
+// and a code for the access token
+type MFAVerifyRequest struct {
+	MFAToken string `json:"mfa_token" binding:"required"`
+	// Code is a code of the authenticator app or a recovery code
+	Code string `json:"code" binding:"required"`
+	// CartToken is the token of the anonymous cart to merge into the user's cart;
+	// the controller falls back to the X-Cart-Token header
+	CartToken string `json:"cart_token,omitempty"`
+}
+
+// MFARecoveryCodesResponse represents the response body listing the
+// recovery codes, shown only once
+type MFARecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
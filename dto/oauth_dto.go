@@ -0,0 +1,201 @@
+package dto
+
+import "time"
+
+// CreateOAuthClientRequest represents the request body for registering an
+// OAuth client
+type CreateOAuthClientRequest struct {
+	// @Description Name of the application, shown to users when asked for consent
+	// @Example "Agenda de Entregas"
+	Name string `json:"name" binding:"required,max=100" example:"Agenda de Entregas"`
+
+	// @Description Whether the client cannot keep a secret, such as a mobile or single page app; public clients only use authorization_code with PKCE
+	// @Example false
+	Public bool `json:"public" example:"false"`
+
+	// @Description Grant types the client uses: authorization_code, client_credentials
+	// @Example ["authorization_code"]
+	GrantTypes []string `json:"grant_types" binding:"required,min=1"`
+
+	// @Description URIs the users are sent back to with the code, compared exactly; https, or http on localhost
+	// @Example ["https://agenda.example.com/oauth/callback"]
+	RedirectURIs []string `json:"redirect_uris,omitempty"`
+
+	// @Description Most scopes the client can be granted: products:read, products:write, orders:read, orders:write
+	// @Example ["orders:read"]
+	Scopes []string `json:"scopes" binding:"required,min=1"`
+
+	// @Description Admin or service account the client acts as with client_credentials
+	// @Example 9
+	UserID *int `json:"user_id,omitempty" example:"9"`
+}
+
+// OAuthClientResponse represents an OAuth client, without its secret
+type OAuthClientResponse struct {
+	// @Description Public identifier of the client
+	// @Example "3f9a1c0b7d2e4a5b6c7d8e9f"
+	ClientID string `json:"client_id" example:"3f9a1c0b7d2e4a5b6c7d8e9f"`
+
+	// @Description Name of the application
+	// @Example "Agenda de Entregas"
+	Name string `json:"name" example:"Agenda de Entregas"`
+
+	// @Description Whether the client has no secret
+	Public bool `json:"public"`
+
+	// @Description Grant types the client uses
+	GrantTypes []string `json:"grant_types"`
+
+	// @Description URIs the users are sent back to with the code
+	RedirectURIs []string `json:"redirect_uris"`
+
+	// @Description Most scopes the client can be granted
+	Scopes []string `json:"scopes"`
+
+	// @Description Admin or service account the client acts as with client_credentials
+	UserID *int `json:"user_id,omitempty"`
+
+	// @Description When the client was registered
+	CreatedAt time.Time `json:"created_at"`
+
+	// @Description Admin who registered the client
+	CreatedBy *int `json:"created_by,omitempty"`
+
+	// @Description When the client was revoked, absent while active
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+}
+
+// OAuthClientSecretResponse represents a new client with its secret, shown
+// only once
+type OAuthClientSecretResponse struct {
+	// @Description Secret of a confidential client, absent for public ones
+	// @Example "Zm9vYmFyYmF6cXV4cXV1eGNvcmdlZ3JhdWx0Z2FycGx5"
+	ClientSecret string `json:"client_secret,omitempty" example:"Zm9vYmFyYmF6cXV4cXV1eGNvcmdlZ3JhdWx0Z2FycGx5"`
+
+	Client OAuthClientResponse `json:"client"`
+}
+
+// OAuthAuthorizeRequest represents the parameters of an authorization
+// request (RFC 6749, 4.1.1) with PKCE (RFC 7636)
+type OAuthAuthorizeRequest struct {
+	ResponseType string `form:"response_type" json:"response_type" binding:"required"`
+	ClientID     string `form:"client_id" json:"client_id" binding:"required"`
+	RedirectURI  string `form:"redirect_uri" json:"redirect_uri" binding:"required"`
+	// Scope lists the scopes asked for, separated by spaces; empty asks for
+	// all the scopes of the client
+	Scope string `form:"scope" json:"scope,omitempty"`
+	// State is sent back untouched to the redirect URI
+	State               string `form:"state" json:"state,omitempty"`
+	CodeChallenge       string `form:"code_challenge" json:"code_challenge" binding:"required"`
+	CodeChallengeMethod string `form:"code_challenge_method" json:"code_challenge_method" binding:"required"`
+}
+
+// OAuthApproveRequest represents the answer of the user to an authorization
+// request. A user not logged in signs in with email and password, as at
+// /login
+type OAuthApproveRequest struct {
+	OAuthAuthorizeRequest
+	// Approve is false when the user refuses
+	Approve  bool   `json:"approve"`
+	Email    string `json:"email,omitempty"`
+	Password string `json:"password,omitempty"`
+}
+
+// OAuthAuthorizationResponse represents what the consent page shows about
+// a valid authorization request
+type OAuthAuthorizationResponse struct {
+	// @Description Public identifier of the client
+	// @Example "3f9a1c0b7d2e4a5b6c7d8e9f"
+	ClientID string `json:"client_id" example:"3f9a1c0b7d2e4a5b6c7d8e9f"`
+
+	// @Description Name of the application asking for access
+	// @Example "Agenda de Entregas"
+	ClientName string `json:"client_name" example:"Agenda de Entregas"`
+
+	// @Description Scopes asked for
+	Scopes []string `json:"scopes"`
+
+	// @Description Whether the logged in user has not granted all the scopes yet; true for anonymous requests
+	ConsentRequired bool `json:"consent_required"`
+}
+
+// OAuthAuthorizeResponse represents the answer to an authorization request:
+// the URI to send the user back to, with the code or the error. A user
+// signing in with two-factor authentication gets an MFA challenge instead,
+// to exchange at /auth/mfa/verify before approving again with the token
+type OAuthAuthorizeResponse struct {
+	RedirectURI string `json:"redirect_uri,omitempty" example:"https://agenda.example.com/oauth/callback?code=Zm9vYmFy&state=xyz"`
+	MFARequired bool   `json:"mfa_required,omitempty"`
+	MFAToken    string `json:"mfa_token,omitempty"`
+}
+
+// OAuthTokenRequest represents the form posted to the token endpoint. The
+// client authenticates with HTTP Basic or with client_id and client_secret
+// in the form
+type OAuthTokenRequest struct {
+	GrantType    string `form:"grant_type" binding:"required"`
+	Code         string `form:"code"`
+	RedirectURI  string `form:"redirect_uri"`
+	CodeVerifier string `form:"code_verifier"`
+	Scope        string `form:"scope"`
+	ClientID     string `form:"client_id"`
+	ClientSecret string `form:"client_secret"`
+}
+
+// OAuthTokenResponse represents an access token issued (RFC 6749, 5.1)
+type OAuthTokenResponse struct {
+	AccessToken string `json:"access_token" example:"gat_Zm9vYmFyYmF6cXV4cXV1eGNvcmdlZ3JhdWx0Z2FycGx5"`
+	TokenType   string `json:"token_type" example:"Bearer"`
+	ExpiresIn   int    `json:"expires_in" example:"3600"`
+	Scope       string `json:"scope" example:"orders:read"`
+}
+
+// OAuthErrorResponse represents an error of the token, introspection and
+// revocation endpoints (RFC 6749, 5.2)
+type OAuthErrorResponse struct {
+	Error            string `json:"error" example:"invalid_grant"`
+	ErrorDescription string `json:"error_description,omitempty" example:"invalid, used or expired authorization code, or wrong code_verifier"`
+}
+
+// OAuthTokenActionRequest represents the form posted to the introspection
+// (RFC 7662) and revocation (RFC 7009) endpoints, authenticated like the
+// token endpoint
+type OAuthTokenActionRequest struct {
+	Token         string `form:"token" binding:"required"`
+	TokenTypeHint string `form:"token_type_hint"`
+	ClientID      string `form:"client_id"`
+	ClientSecret  string `form:"client_secret"`
+}
+
+// OAuthIntrospectionResponse represents the state of a token (RFC 7662,
+// 2.2); inactive tokens only carry active false
+type OAuthIntrospectionResponse struct {
+	Active    bool   `json:"active"`
+	Scope     string `json:"scope,omitempty" example:"orders:read"`
+	ClientID  string `json:"client_id,omitempty" example:"3f9a1c0b7d2e4a5b6c7d8e9f"`
+	Username  string `json:"username,omitempty" example:"user@example.com"`
+	TokenType string `json:"token_type,omitempty" example:"Bearer"`
+	Exp       int64  `json:"exp,omitempty" example:"1792418400"`
+	Iat       int64  `json:"iat,omitempty" example:"1792414800"`
+	Sub       string `json:"sub,omitempty" example:"7"`
+}
+
+// OAuthConsentResponse represents the scopes the user granted a client
+type OAuthConsentResponse struct {
+	// @Description Public identifier of the client
+	// @Example "3f9a1c0b7d2e4a5b6c7d8e9f"
+	ClientID string `json:"client_id" example:"3f9a1c0b7d2e4a5b6c7d8e9f"`
+
+	// @Description Name of the application
+	// @Example "Agenda de Entregas"
+	ClientName string `json:"client_name" example:"Agenda de Entregas"`
+
+	// @Description Scopes granted
+	Scopes []string `json:"scopes"`
+
+	// @Description When the user first granted access
+	CreatedAt time.Time `json:"created_at"`
+
+	// @Description When the user last changed the scopes granted
+	UpdatedAt time.Time `json:"updated_at"`
+}
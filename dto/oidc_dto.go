@@ -0,0 +1,46 @@
+package dto
+
+import "time"
+
+// OIDCAuthorizeResponse represents the response body starting a sign in at
+// an identity provider: the client sends the user to AuthorizationURL and
+// keeps State to compare with the one the provider sends back
+type OIDCAuthorizeResponse struct {
+	// @Description URL of the identity provider to send the user to
+	AuthorizationURL string `json:"authorization_url" example:"https://login.example.com/authorize?client_id=go-api&response_type=code"`
+
+	// @Description Opaque value the provider returns to the callback
+	State string `json:"state" example:"Nf0yG8vVq3kz8T1Zb2Ew5w"`
+}
+
+// OIDCCallbackRequest represents what the identity provider sends back to
+// the callback, as query parameters or as a JSON body
+type OIDCCallbackRequest struct {
+	Code  string `form:"code" json:"code"`
+	State string `form:"state" json:"state" binding:"required"`
+	// Error and ErrorDescription are set by the provider when the user did
+	// not sign in or did not consent
+	Error            string `form:"error" json:"error,omitempty"`
+	ErrorDescription string `form:"error_description" json:"error_description,omitempty"`
+	// CartToken is the token of the anonymous cart to merge into the user's cart;
+	// the controller falls back to the X-Cart-Token header
+	CartToken string `form:"cart_token" json:"cart_token,omitempty"`
+}
+
+// UserIdentityResponse represents an account at an identity provider linked
+// to the user
+type UserIdentityResponse struct {
+	// @Description Name of the identity provider
+	// @Example "corp"
+	Provider string `json:"provider" example:"corp"`
+
+	// @Description Email the provider reported at the last sign in
+	// @Example "user@example.com"
+	Email string `json:"email,omitempty" example:"user@example.com"`
+
+	// @Description When the identity was linked
+	CreatedAt time.Time `json:"created_at"`
+
+	// @Description When the user last signed in with the identity
+	LastLoginAt *time.Time `json:"last_login_at,omitempty"`
+}
@@ -0,0 +1,189 @@
+package dto
+
+import "time"
+
+// CheckoutItem represents a product, or one of its variants, to order
+type CheckoutItem struct {
+	// @Description ID of the product
+	// @Example 1
+	ProductID int `json:"product_id" binding:"required,min=1" example:"1"`
+
+	// @Description ID of the variant, required for products with variants
+	// @Example 2
+	VariantID *int `json:"variant_id,omitempty" binding:"omitempty,min=1" example:"2"`
+
+	// @Description Quantity to order
+	// @Example 1
+	Quantity int `json:"quantity" binding:"required,min=1" example:"1"`
+}
+
+// CheckoutRequest represents the request body for placing an order
+type CheckoutRequest struct {
+	// @Description Items to order; when empty the whole cart of the user is ordered and emptied
+	Items []CheckoutItem `json:"items,omitempty" binding:"omitempty,dive"`
+
+	// @Description Discount codes to apply; the order is refused if any of them is rejected
+	// @Example ["BEMVINDO10"]
+	Codes []string `json:"codes,omitempty" binding:"omitempty,max=5,dive,max=32" example:"BEMVINDO10"`
+
+	// @Description Where the order is delivered; the items are taxed with its rates. Without it the order is placed without taxes
+	Destination *TaxDestinationRequest `json:"destination,omitempty"`
+}
+
+// TransitionOrderRequest represents the request body for changing the status of an order
+type TransitionOrderRequest struct {
+	// @Description New status: shipped, delivered or cancelled; paid and refunded follow the payments
+	// @Example "shipped"
+	Status string `json:"status" binding:"required" example:"shipped"`
+
+	// @Description Optional note recorded with the change
+	// @Example "Enviado pelos Correios"
+	Note string `json:"note,omitempty" binding:"max=500" example:"Enviado pelos Correios"`
+}
+
+// OrderItemResponse represents a line of an order as it was at checkout
+type OrderItemResponse struct {
+	// @Description Unique identifier of the item
+	// @Example 1
+	ID int `json:"id" example:"1"`
+
+	// @Description ID of the product, absent once the product is purged
+	// @Example 1
+	ProductID *int `json:"product_id,omitempty" example:"1"`
+
+	// @Description ID of the variant, for products with variants
+	// @Example 2
+	VariantID *int `json:"variant_id,omitempty" example:"2"`
+
+	// @Description Name of the product at checkout
+	// @Example "Camiseta"
+	ProductName string `json:"product_name" example:"Camiseta"`
+
+	// @Description SKU of the variant or of the product at checkout
+	// @Example "CAM-AZUL-M"
+	SKU string `json:"sku,omitempty" example:"CAM-AZUL-M"`
+
+	// @Description Unit price at checkout
+	UnitPrice MoneyResponse `json:"unit_price"`
+
+	// @Description Quantity ordered
+	// @Example 2
+	Quantity int `json:"quantity" example:"2"`
+
+	// @Description Unit price times the quantity
+	Subtotal MoneyResponse `json:"subtotal"`
+
+	// @Description Share of the order discount given to the item
+	Discount MoneyResponse `json:"discount"`
+
+	// @Description Tax of the item after its discount
+	Tax MoneyResponse `json:"tax"`
+}
+
+// OrderTransitionResponse represents a status change of an order
+type OrderTransitionResponse struct {
+	// @Description Previous status, absent for the creation of the order
+	// @Example "pending"
+	From string `json:"from,omitempty" example:"pending"`
+
+	// @Description New status
+	// @Example "paid"
+	To string `json:"to" example:"paid"`
+
+	// @Description ID of the user who made the change
+	// @Example 1
+	ActorID *int `json:"actor_id,omitempty" example:"1"`
+
+	// @Description Note recorded with the change
+	// @Example "Pagamento confirmado"
+	Note string `json:"note,omitempty" example:"Pagamento confirmado"`
+
+	// @Description When the change happened
+	// @Example "2026-03-01T12:00:00Z"
+	OccurredAt time.Time `json:"occurred_at" example:"2026-03-01T12:00:00Z"`
+}
+
+// OrderResponse represents an order
+type OrderResponse struct {
+	// @Description Unique identifier of the order
+	// @Example 1
+	ID int `json:"id" example:"1"`
+
+	// @Description ID of the user who placed the order
+	// @Example 7
+	UserID *int `json:"user_id,omitempty" example:"7"`
+
+	// @Description Current status: pending, paid, shipped, delivered, cancelled or refunded
+	// @Example "pending"
+	Status string `json:"status" example:"pending"`
+
+	// @Description Items of the order
+	Items []OrderItemResponse `json:"items"`
+
+	// @Description Discount of the promotions applied
+	Discount MoneyResponse `json:"discount"`
+
+	// @Description Sum of the taxes of the items
+	Tax MoneyResponse `json:"tax"`
+
+	// @Description Whether the prices already included the taxes, which then did not add to the total
+	// @Example true
+	TaxIncluded bool `json:"tax_included" example:"true"`
+
+	// @Description Sum of the items less the discount, plus the taxes not included in the prices
+	Total MoneyResponse `json:"total"`
+
+	// @Description Destination whose tax rates were applied, absent for orders placed without taxes
+	TaxDestination *TaxDestinationResponse `json:"tax_destination,omitempty"`
+
+	// @Description Sum of each tax over the items; only returned for a single order
+	Taxes []TaxAmountResponse `json:"taxes,omitempty"`
+
+	// @Description Promotions and discount codes applied; only returned for a single order
+	Promotions []AppliedPromotionResponse `json:"promotions,omitempty"`
+
+	// @Description When the order was placed
+	// @Example "2026-03-01T12:00:00Z"
+	CreatedAt time.Time `json:"created_at" example:"2026-03-01T12:00:00Z"`
+
+	// @Description Last status change
+	// @Example "2026-03-01T12:00:00Z"
+	UpdatedAt time.Time `json:"updated_at" example:"2026-03-01T12:00:00Z"`
+
+	// @Description Status changes, oldest first; only returned for a single order
+	History []OrderTransitionResponse `json:"history,omitempty"`
+}
+
+// PaymentResponse represents an attempt to pay an order
+type PaymentResponse struct {
+	// @Description Unique identifier of the payment
+	// @Example 1
+	ID int `json:"id" example:"1"`
+
+	// @Description ID of the order
+	// @Example 10
+	OrderID int `json:"order_id" example:"10"`
+
+	// @Description Payment provider
+	// @Example "fake"
+	Provider string `json:"provider" example:"fake"`
+
+	// @Description ID of the payment at the provider
+	// @Example "pi_3f9a..."
+	IntentID string `json:"intent_id" example:"pi_3f9a..."`
+
+	// @Description Status: pending, succeeded, failed or refunded
+	// @Example "succeeded"
+	Status string `json:"status" example:"succeeded"`
+
+	// @Description Amount charged
+	Amount MoneyResponse `json:"amount"`
+
+	// @Description When the payment was started
+	// @Example "2026-03-01T12:00:00Z"
+	CreatedAt time.Time `json:"created_at" example:"2026-03-01T12:00:00Z"`
+
+	// @Description Last status change
+	// @Example "2026-03-01T12:00:00Z"
+	UpdatedAt time.Time `json:"updated_at" example:"2026-03-01T12:00:00Z"`
+}
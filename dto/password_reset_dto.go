@@ -0,0 +1,19 @@
+package dto
+
+// ForgotPasswordRequest represents the request body for asking a password reset email
+type ForgotPasswordRequest struct {
+	// @Description Email of the account
+	// @Example "user@example.com"
+	Email string `json:"email" binding:"required,email" example:"user@example.com"`
+}
+
+// ResetPasswordRequest represents the request body for choosing a new password
+type ResetPasswordRequest struct {
+	// @Description Token of the link sent by email
+	// @Example "q3Jb6m0n2x8V4tQy1Zk7cR5sW9hL0pXaE2dF6gH8iJ4"
+	Token string `json:"token" binding:"required" example:"q3Jb6m0n2x8V4tQy1Zk7cR5sW9hL0pXaE2dF6gH8iJ4"`
+
+	// @Description New password of the user
+	// @Example "correct horse battery staple"
+	Password string `json:"password" binding:"required" example:"correct horse battery staple"`
+}
@@ -0,0 +1,115 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// CreatePriceListRequest represents the request body for creating a price list
+type CreatePriceListRequest struct {
+	// @Description Unique code of the price list
+	// @Example "usd-default"
+	Code string `json:"code" binding:"required" example:"usd-default"`
+
+	// @Description Name of the price list
+	// @Example "Preços em dólar"
+	Name string `json:"name" binding:"required" example:"Preços em dólar"`
+
+	// @Description ISO 4217 currency of the prices in the list
+	// @Example "USD"
+	Currency string `json:"currency" binding:"required,len=3" example:"USD"`
+
+	// @Description Optional market the list applies to
+	// @Example "US"
+	Market string `json:"market,omitempty" example:"US"`
+}
+
+// PriceListResponse represents the response body for price list operations
+type PriceListResponse struct {
+	// @Description Unique identifier of the price list
+	// @Example 1
+	ID int `json:"id" example:"1"`
+
+	// @Description Unique code of the price list
+	// @Example "usd-default"
+	Code string `json:"code" example:"usd-default"`
+
+	// @Description Name of the price list
+	// @Example "Preços em dólar"
+	Name string `json:"name" example:"Preços em dólar"`
+
+	// @Description ISO 4217 currency of the prices in the list
+	// @Example "USD"
+	Currency string `json:"currency" example:"USD"`
+
+	// @Description Market the list applies to
+	// @Example "US"
+	Market string `json:"market,omitempty" example:"US"`
+}
+
+// SetPriceListItemRequest represents the request body for setting an explicit product price
+type SetPriceListItemRequest struct {
+	// @Description Price in the currency of the list, as a decimal string
+	// @Example "199.90"
+	Price json.Number `json:"price" binding:"required" swaggertype:"string" example:"199.90"`
+}
+
+// PriceListItemResponse represents an explicit product price of a price list
+type PriceListItemResponse struct {
+	// @Description Product the price applies to
+	// @Example 1
+	ProductID int `json:"product_id" example:"1"`
+
+	// @Description Explicit price of the product
+	Price MoneyResponse `json:"price"`
+}
+
+// ExchangeRateRequest represents one exchange rate to be saved
+type ExchangeRateRequest struct {
+	// @Description Currency being converted
+	// @Example "BRL"
+	Base string `json:"base" binding:"required,len=3" example:"BRL"`
+
+	// @Description Currency the base is converted into
+	// @Example "USD"
+	Quote string `json:"quote" binding:"required,len=3" example:"USD"`
+
+	// @Description Units of quote for one unit of base, as a decimal string
+	// @Example "0.185"
+	Rate json.Number `json:"rate" binding:"required" swaggertype:"string" example:"0.185"`
+}
+
+// SetExchangeRatesRequest represents the request body for updating exchange rates
+type SetExchangeRatesRequest struct {
+	// @Description Rates to insert or replace
+	Rates []ExchangeRateRequest `json:"rates" binding:"required,min=1,dive"`
+}
+
+// ExchangeRateResponse represents an exchange rate
+type ExchangeRateResponse struct {
+	// @Description Currency being converted
+	// @Example "BRL"
+	Base string `json:"base" example:"BRL"`
+
+	// @Description Currency the base is converted into
+	// @Example "USD"
+	Quote string `json:"quote" example:"USD"`
+
+	// @Description Units of quote for one unit of base
+	// @Example "0.1850000000"
+	Rate string `json:"rate" example:"0.1850000000"`
+
+	// @Description Origin of the rate (manual or the imported file name)
+	// @Example "manual"
+	Source string `json:"source" example:"manual"`
+
+	// @Description When the rate was last updated
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ImportExchangeRatesResponse represents the result of an exchange rate file import
+type ImportExchangeRatesResponse struct {
+	// @Description Number of rates imported
+	// @Example 4
+	Imported int `json:"imported" example:"4"`
+}
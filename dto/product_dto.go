@@ -1,14 +1,27 @@
 package dto
 
+import (
+	"encoding/json"
+	"time"
+)
+
 // CreateProductRequest represents the request body for creating a product
 type CreateProductRequest struct {
 	// @Description Name of the product
 	// @Example "iPhone 15"
 	Name string `json:"name" binding:"required" example:"iPhone 15"`
 
-	// @Description Price of the product
-	// @Example 999.99
-	Price float64 `json:"price" binding:"required,min=0" example:"999.99"`
+	// @Description Price of the product as a decimal string (numbers are also accepted)
+	// @Example "999.99"
+	Price json.Number `json:"price" binding:"required" swaggertype:"string" example:"999.99"`
+
+	// @Description ISO 4217 currency of the price, defaults to BRL
+	// @Example "BRL"
+	Currency string `json:"currency,omitempty" binding:"omitempty,len=3" example:"BRL"`
+
+	// @Description Optional stock keeping unit, unique among products
+	// @Example "IPH-15-128"
+	SKU string `json:"sku,omitempty" binding:"omitempty,max=64" example:"IPH-15-128"`
 }
 
 // ProductResponse represents the response body for product operations
@@ -21,7 +34,123 @@ type ProductResponse struct {
 	// @Example "iPhone 15"
 	Name string `json:"name" example:"iPhone 15"`
 
+	// @Description Stock keeping unit of the product, when it has one
+	// @Example "IPH-15-128"
+	SKU string `json:"sku,omitempty" example:"IPH-15-128"`
+
 	// @Description Price of the product
-	// @Example 999.99
-	Price float64 `json:"price" example:"999.99"`
+	Price MoneyResponse `json:"price"`
+
+	// @Description Tax category selecting the tax rates of the product
+	// @Example "standard"
+	TaxCategory string `json:"tax_category,omitempty" example:"standard"`
+
+	// @Description How the price was obtained when a currency was requested
+	Conversion *PriceConversionResponse `json:"conversion,omitempty"`
+
+	// @Description Option types of the variant matrix with the values in use
+	Options []ProductOptionResponse `json:"options,omitempty"`
+
+	// @Description Variants of the product
+	Variants []VariantResponse `json:"variants,omitempty"`
+
+	// @Description Images of the product ordered by position
+	Images []ProductImageResponse `json:"images,omitempty"`
+
+	// @Description When the product was created
+	CreatedAt time.Time `json:"created_at"`
+
+	// @Description When the product or its price history last changed
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// @Description User who created the product, absent for anonymous changes
+	// @Example 1
+	CreatedBy *int `json:"created_by,omitempty" example:"1"`
+
+	// @Description User who last changed the product, absent for anonymous changes
+	// @Example 1
+	UpdatedBy *int `json:"updated_by,omitempty" example:"1"`
+
+	// @Description When the product was moved to the trash, present in trash listings
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+}
+
+// MoneyResponse represents a monetary amount; the amount is a string to keep its exact precision
+type MoneyResponse struct {
+	// @Description Amount in major units with the currency precision
+	// @Example "999.99"
+	Amount string `json:"amount" example:"999.99"`
+
+	// @Description ISO 4217 currency code
+	// @Example "BRL"
+	Currency string `json:"currency" example:"BRL"`
+}
+
+// PriceConversionResponse describes how a price was resolved in the requested currency
+type PriceConversionResponse struct {
+	// @Description Where the price came from: price_list or exchange_rate
+	// @Example "exchange_rate"
+	Source string `json:"source" example:"exchange_rate"`
+
+	// @Description Code of the price list that defined the price
+	// @Example "usd-default"
+	PriceList string `json:"price_list,omitempty" example:"usd-default"`
+
+	// @Description Price of the product in its own currency
+	OriginalPrice MoneyResponse `json:"original_price"`
+
+	// @Description Exchange rate applied to the original price
+	// @Example "0.1850000000"
+	Rate string `json:"rate,omitempty" example:"0.1850000000"`
+
+	// @Description When the exchange rate was last updated
+	RateTimestamp *time.Time `json:"rate_timestamp,omitempty"`
+
+	// @Description Rounding rule applied to the converted amount
+	// @Example "half_up"
+	Rounding string `json:"rounding,omitempty" example:"half_up"`
+}
+
+// SchedulePriceRequest represents the request body for scheduling a product price change
+type SchedulePriceRequest struct {
+	// @Description New price in the product currency, as a decimal string
+	// @Example "19.90"
+	Price json.Number `json:"price" binding:"required" swaggertype:"string" example:"19.90"`
+
+	// @Description regular (lasts until superseded) or sale (temporary, needs effective_to)
+	// @Example "sale"
+	Kind string `json:"kind,omitempty" binding:"omitempty,oneof=regular sale" example:"sale"`
+
+	// @Description When the price takes effect, defaults to now
+	EffectiveFrom *time.Time `json:"effective_from,omitempty"`
+
+	// @Description When a sale price ends
+	EffectiveTo *time.Time `json:"effective_to,omitempty"`
+}
+
+// ProductPriceResponse represents an entry of the product price timeline
+type ProductPriceResponse struct {
+	// @Description Unique identifier of the entry
+	// @Example 1
+	ID int `json:"id" example:"1"`
+
+	// @Description Price of the entry
+	Price MoneyResponse `json:"price"`
+
+	// @Description regular or sale
+	// @Example "regular"
+	Kind string `json:"kind" example:"regular"`
+
+	// @Description When the price takes effect
+	EffectiveFrom time.Time `json:"effective_from"`
+
+	// @Description When the price stops being in effect (sale prices only)
+	EffectiveTo *time.Time `json:"effective_to,omitempty"`
+
+	// @Description scheduled, active, overridden (by a sale), superseded or expired
+	// @Example "active"
+	Status string `json:"status" example:"active"`
+
+	// @Description When the entry was recorded
+	CreatedAt time.Time `json:"created_at"`
 }
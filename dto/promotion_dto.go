@@ -0,0 +1,237 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// CreatePromotionRequest represents the request body for creating a promotion
+type CreatePromotionRequest struct {
+	// @Description Discount code, matched without regard to case; leave out for an automatic promotion
+	// @Example "BEMVINDO10"
+	Code string `json:"code,omitempty" binding:"max=32" example:"BEMVINDO10"`
+
+	// @Description Name shown in the price breakdown
+	// @Example "Boas-vindas"
+	Name string `json:"name" binding:"required,max=255" example:"Boas-vindas"`
+
+	// @Description Kind of discount: percentage or fixed
+	// @Example "percentage"
+	Kind string `json:"kind" binding:"required,oneof=percentage fixed" example:"percentage"`
+
+	// @Description Discount of percentage promotions, from 1 to 100
+	// @Example 10
+	Percent int `json:"percent,omitempty" example:"10"`
+
+	// @Description Discount of fixed promotions, as a decimal string, spread over the eligible items
+	// @Example "20.00"
+	Amount json.Number `json:"amount,omitempty" swaggertype:"string" example:"20.00"`
+
+	// @Description Minimum subtotal of the order, as a decimal string
+	// @Example "100.00"
+	MinTotal json.Number `json:"min_total,omitempty" swaggertype:"string" example:"100.00"`
+
+	// @Description ISO 4217 currency of amount and min_total
+	// @Example "BRL"
+	Currency string `json:"currency,omitempty" binding:"omitempty,len=3" example:"BRL"`
+
+	// @Description Products the promotion is restricted to
+	// @Example [1]
+	ProductIDs []int `json:"product_ids,omitempty" binding:"omitempty,dive,min=1"`
+
+	// @Description Categories the promotion is restricted to, subcategories included
+	// @Example [2]
+	CategoryIDs []int `json:"category_ids,omitempty" binding:"omitempty,dive,min=1"`
+
+	// @Description Maximum number of redemptions by everyone
+	// @Example 100
+	UsageLimit *int `json:"usage_limit,omitempty" example:"100"`
+
+	// @Description Maximum number of redemptions by each user; the code then requires signing in
+	// @Example 1
+	PerUserLimit *int `json:"per_user_limit,omitempty" example:"1"`
+
+	// @Description Start of the validity
+	// @Example "2026-03-01T00:00:00Z"
+	StartsAt *time.Time `json:"starts_at,omitempty" example:"2026-03-01T00:00:00Z"`
+
+	// @Description End of the validity, exclusive
+	// @Example "2026-04-01T00:00:00Z"
+	EndsAt *time.Time `json:"ends_at,omitempty" example:"2026-04-01T00:00:00Z"`
+
+	// @Description Whether the promotion combines with the other stackable ones
+	// @Example false
+	Stackable bool `json:"stackable" example:"false"`
+}
+
+// SetPromotionActiveRequest represents the request body for turning a promotion on or off
+type SetPromotionActiveRequest struct {
+	// @Description Whether the promotion can be applied
+	// @Example false
+	Active *bool `json:"active" binding:"required" example:"false"`
+}
+
+// PromotionResponse represents a promotion
+type PromotionResponse struct {
+	// @Description Unique identifier of the promotion
+	// @Example 1
+	ID int `json:"id" example:"1"`
+
+	// @Description Discount code, absent for automatic promotions
+	// @Example "BEMVINDO10"
+	Code string `json:"code,omitempty" example:"BEMVINDO10"`
+
+	// @Description Name shown in the price breakdown
+	// @Example "Boas-vindas"
+	Name string `json:"name" example:"Boas-vindas"`
+
+	// @Description Kind of discount: percentage or fixed
+	// @Example "percentage"
+	Kind string `json:"kind" example:"percentage"`
+
+	// @Description Discount of percentage promotions
+	// @Example 10
+	Percent int `json:"percent,omitempty" example:"10"`
+
+	// @Description Discount of fixed promotions
+	Amount *MoneyResponse `json:"amount,omitempty"`
+
+	// @Description Minimum subtotal of the order
+	MinTotal *MoneyResponse `json:"min_total,omitempty"`
+
+	// @Description Products the promotion is restricted to
+	ProductIDs []int `json:"product_ids"`
+
+	// @Description Categories the promotion is restricted to
+	CategoryIDs []int `json:"category_ids"`
+
+	// @Description Maximum number of redemptions by everyone
+	// @Example 100
+	UsageLimit *int `json:"usage_limit,omitempty" example:"100"`
+
+	// @Description Maximum number of redemptions by each user
+	// @Example 1
+	PerUserLimit *int `json:"per_user_limit,omitempty" example:"1"`
+
+	// @Description Redemptions so far, not counting cancelled orders
+	// @Example 12
+	Uses int `json:"uses" example:"12"`
+
+	// @Description Start of the validity
+	// @Example "2026-03-01T00:00:00Z"
+	StartsAt *time.Time `json:"starts_at,omitempty" example:"2026-03-01T00:00:00Z"`
+
+	// @Description End of the validity, exclusive
+	// @Example "2026-04-01T00:00:00Z"
+	EndsAt *time.Time `json:"ends_at,omitempty" example:"2026-04-01T00:00:00Z"`
+
+	// @Description Whether the promotion combines with the other stackable ones
+	// @Example false
+	Stackable bool `json:"stackable" example:"false"`
+
+	// @Description Whether the promotion can be applied
+	// @Example true
+	Active bool `json:"active" example:"true"`
+
+	// @Description When the promotion was created
+	// @Example "2026-03-01T12:00:00Z"
+	CreatedAt time.Time `json:"created_at" example:"2026-03-01T12:00:00Z"`
+}
+
+// AppliedPromotionResponse represents a promotion applied to a cart or order
+type AppliedPromotionResponse struct {
+	// @Description ID of the promotion
+	// @Example 1
+	PromotionID int `json:"promotion_id" example:"1"`
+
+	// @Description Discount code, absent for automatic promotions
+	// @Example "BEMVINDO10"
+	Code string `json:"code,omitempty" example:"BEMVINDO10"`
+
+	// @Description Name of the promotion
+	// @Example "Boas-vindas"
+	Name string `json:"name" example:"Boas-vindas"`
+
+	// @Description Discount given by the promotion
+	Discount MoneyResponse `json:"discount"`
+}
+
+// AdjustmentResponse represents the discount a promotion gave to a line
+type AdjustmentResponse struct {
+	// @Description ID of the promotion
+	// @Example 1
+	PromotionID int `json:"promotion_id" example:"1"`
+
+	// @Description Discount code, absent for automatic promotions
+	// @Example "BEMVINDO10"
+	Code string `json:"code,omitempty" example:"BEMVINDO10"`
+
+	// @Description Name of the promotion
+	// @Example "Boas-vindas"
+	Name string `json:"name" example:"Boas-vindas"`
+
+	// @Description Discount on the line
+	Amount MoneyResponse `json:"amount"`
+}
+
+// PricedLineResponse represents a cart item with its discounts
+type PricedLineResponse struct {
+	// @Description ID of the product
+	// @Example 1
+	ProductID int `json:"product_id" example:"1"`
+
+	// @Description ID of the variant, for products with variants
+	// @Example 2
+	VariantID *int `json:"variant_id,omitempty" example:"2"`
+
+	// @Description Quantity in the cart
+	// @Example 2
+	Quantity int `json:"quantity" example:"2"`
+
+	// @Description Unit price times the quantity
+	Subtotal MoneyResponse `json:"subtotal"`
+
+	// @Description Sum of the adjustments
+	Discount MoneyResponse `json:"discount"`
+
+	// @Description Subtotal less the discount
+	Total MoneyResponse `json:"total"`
+
+	// @Description Discount of each promotion on the line, in the order they were applied
+	Adjustments []AdjustmentResponse `json:"adjustments"`
+}
+
+// RejectedCodeResponse represents a discount code that was not applied
+type RejectedCodeResponse struct {
+	// @Description Code as given, in upper case
+	// @Example "VERAO"
+	Code string `json:"code" example:"VERAO"`
+
+	// @Description Why the code was not applied
+	// @Example "the code has expired"
+	Reason string `json:"reason" example:"the code has expired"`
+}
+
+// CartPricingResponse represents the promotions applied to the cart
+type CartPricingResponse struct {
+	// @Description Available items of the cart with their discounts
+	Lines []PricedLineResponse `json:"lines"`
+
+	// @Description Promotions and discount codes applied
+	Applied []AppliedPromotionResponse `json:"applied"`
+
+	// @Description Discount codes not applied, and why
+	Rejected []RejectedCodeResponse `json:"rejected"`
+
+	// @Description Sum of the items, absent for an empty cart
+	Subtotal *MoneyResponse `json:"subtotal,omitempty"`
+
+	// @Description Sum of the discounts, absent for an empty cart
+	Discount *MoneyResponse `json:"discount,omitempty"`
+
+	// @Description Subtotal less the discount, plus the taxes not included in the prices; absent for an empty cart
+	Total *MoneyResponse `json:"total,omitempty"`
+
+	// @Description Taxes of the discounted items, present when a destination country was given
+	Tax *TaxBreakdownResponse `json:"tax,omitempty"`
+}
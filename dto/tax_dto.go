@@ -0,0 +1,139 @@
+package dto
+
+import "time"
+
+// TaxDestinationRequest represents where an order is delivered, which selects the tax rates
+type TaxDestinationRequest struct {
+	// @Description ISO 3166-1 alpha-2 country code
+	// @Example "BR"
+	Country string `json:"country" binding:"required,len=2" example:"BR"`
+
+	// @Description State, for countries that tax by state
+	// @Example "SP"
+	State string `json:"state,omitempty" binding:"max=8" example:"SP"`
+}
+
+// SetTaxCategoryRequest represents the request body for assigning the tax category of a product
+type SetTaxCategoryRequest struct {
+	// @Description One of the categories of the rate table
+	// @Example "reduced"
+	TaxCategory string `json:"tax_category" binding:"required" example:"reduced"`
+}
+
+// TaxCategoriesResponse lists the tax categories products may be assigned to
+type TaxCategoriesResponse struct {
+	// @Description Categories declared in the rate table
+	// @Example ["standard", "reduced", "exempt"]
+	Categories []string `json:"categories" example:"standard,reduced,exempt"`
+
+	// @Description Category of the products that were not assigned one
+	// @Example "standard"
+	Default string `json:"default" example:"standard"`
+}
+
+// TaxDestinationResponse represents the destination whose rates were applied
+type TaxDestinationResponse struct {
+	// @Description ISO 3166-1 alpha-2 country code
+	// @Example "BR"
+	Country string `json:"country" example:"BR"`
+
+	// @Description State, when one was given
+	// @Example "SP"
+	State string `json:"state,omitempty" example:"SP"`
+}
+
+// TaxRateResponse represents a version of a tax of a category
+type TaxRateResponse struct {
+	// @Description Name of the tax
+	// @Example "ICMS"
+	Name string `json:"name" example:"ICMS"`
+
+	// @Description Tax category the rate applies to
+	// @Example "standard"
+	Category string `json:"category" example:"standard"`
+
+	// @Description Rate in percent
+	// @Example "18"
+	Percent string `json:"percent" example:"18"`
+
+	// @Description Start of the version
+	// @Example "2023-01-01T03:00:00Z"
+	EffectiveFrom time.Time `json:"effective_from" example:"2023-01-01T03:00:00Z"`
+
+	// @Description End of the version, exclusive; absent while it has no end
+	EffectiveTo *time.Time `json:"effective_to,omitempty"`
+}
+
+// TaxRegionResponse represents the rates in effect at a destination
+type TaxRegionResponse struct {
+	// @Description Destination of the rates
+	Destination TaxDestinationResponse `json:"destination"`
+
+	// @Description Version of the rate table
+	// @Example "2026-01-01"
+	Version string `json:"version,omitempty" example:"2026-01-01"`
+
+	// @Description Whether prices already include the taxes at the destination
+	// @Example true
+	PricesIncludeTax bool `json:"prices_include_tax" example:"true"`
+
+	// @Description Rounding of the taxes: line or total
+	// @Example "line"
+	Rounding string `json:"rounding" example:"line"`
+
+	// @Description Rates in effect, the country rates first
+	Rates []TaxRateResponse `json:"rates"`
+}
+
+// TaxAmountResponse represents how much of a tax is paid
+type TaxAmountResponse struct {
+	// @Description Name of the tax
+	// @Example "ICMS"
+	Name string `json:"name" example:"ICMS"`
+
+	// @Description Rate in percent
+	// @Example "18"
+	Percent string `json:"percent" example:"18"`
+
+	// @Description Amount of the tax
+	Amount MoneyResponse `json:"amount"`
+}
+
+// LineTaxResponse represents the tax of a line after its discount
+type LineTaxResponse struct {
+	// @Description Tax category of the product
+	// @Example "standard"
+	Category string `json:"category" example:"standard"`
+
+	// @Description Amount of the line without the taxes
+	Net MoneyResponse `json:"net"`
+
+	// @Description Sum of the taxes of the line
+	Tax MoneyResponse `json:"tax"`
+
+	// @Description Taxes of the line
+	Taxes []TaxAmountResponse `json:"taxes"`
+}
+
+// TaxBreakdownResponse represents the itemized tax of a cart
+type TaxBreakdownResponse struct {
+	// @Description Destination whose rates were applied
+	Destination TaxDestinationResponse `json:"destination"`
+
+	// @Description Whether the prices already include the taxes, which then do not add to the total
+	// @Example true
+	Included bool `json:"included" example:"true"`
+
+	// @Description Rounding of the taxes: line or total
+	// @Example "line"
+	Rounding string `json:"rounding" example:"line"`
+
+	// @Description Tax of each line, in the order of the priced lines
+	Lines []LineTaxResponse `json:"lines"`
+
+	// @Description Sum of each tax over the lines
+	Taxes []TaxAmountResponse `json:"taxes"`
+
+	// @Description Sum of the taxes
+	Total MoneyResponse `json:"total"`
+}
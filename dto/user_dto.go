@@ -1,5 +1,7 @@
 package dto
 
+import "time"
+
 // CreateUserRequest represents the request body for creating a user
 type CreateUserRequest struct {
 	// @Description Name of the user
@@ -11,8 +13,8 @@ type CreateUserRequest struct {
 	Email string `json:"email" binding:"required,email" example:"user@example.com"`
 
 	// @Description Password of the user
-	// @Example "password123"
-	Password string `json:"password" binding:"required,min=6" example:"password123"`
+	// @Example "correct horse battery"
+	Password string `json:"password" binding:"required" example:"correct horse battery"`
 }
 
 // UpdateUserRequest represents the request body for updating a user
@@ -23,11 +25,11 @@ type UpdateUserRequest struct {
 
 	// @Description Email of the user
 	// @Example "user@example.com"
-	Email string `json:"email,omitempty" example:"user@example.com"`
+	Email string `json:"email,omitempty" binding:"omitempty,email" example:"user@example.com"`
 
 	// @Description Password of the user
-	// @Example "newpassword123"
-	Password string `json:"password,omitempty" example:"newpassword123"`
+	// @Example "correct horse battery staple"
+	Password string `json:"password,omitempty" example:"correct horse battery staple"`
 }
 
 // UserResponse represents the response body for user operations
@@ -43,4 +45,40 @@ type UserResponse struct {
 	// @Description Email of the user
 	// @Example "user@example.com"
 	Email string `json:"email" example:"user@example.com"`
+
+	// @Description Role of the user, present in exports
+	// @Example "customer"
+	Role string `json:"role,omitempty" example:"customer"`
+
+	// @Description When the user confirmed the email, absent while unverified
+	EmailVerifiedAt *time.Time `json:"email_verified_at,omitempty"`
+
+	// @Description New email waiting for confirmation, absent when none
+	// @Example "new@example.com"
+	PendingEmail string `json:"pending_email,omitempty" example:"new@example.com"`
+
+	// @Description When the user was created
+	CreatedAt time.Time `json:"created_at"`
+
+	// @Description When the user was last changed
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// @Description User who created the user, absent for sign ups
+	// @Example 1
+	CreatedBy *int `json:"created_by,omitempty" example:"1"`
+
+	// @Description User who last changed the user, absent for anonymous changes
+	// @Example 1
+	UpdatedBy *int `json:"updated_by,omitempty" example:"1"`
+
+	// @Description When the user was moved to the trash, present in trash listings
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+}
+
+// ResendVerificationRequest represents the request body for asking for a new
+// verification email
+type ResendVerificationRequest struct {
+	// @Description Current email of the account
+	// @Example "user@example.com"
+	Email string `json:"email" binding:"required,email" example:"user@example.com"`
 }
@@ -0,0 +1,133 @@
+package dto
+
+// CreateOptionTypeRequest represents the request body for creating an option type
+type CreateOptionTypeRequest struct {
+	// @Description Name of the option type
+	// @Example "size"
+	Name string `json:"name" binding:"required" example:"size"`
+
+	// @Description Values of the option type, in display order
+	// @Example ["P", "M", "G"]
+	Values []string `json:"values" binding:"dive,required" example:"P,M,G"`
+}
+
+// AddOptionValueRequest represents the request body for adding a value to an option type
+type AddOptionValueRequest struct {
+	// @Description New value, appended after the existing ones
+	// @Example "GG"
+	Value string `json:"value" binding:"required" example:"GG"`
+}
+
+// OptionValueResponse represents a value of an option type
+type OptionValueResponse struct {
+	// @Description Unique identifier of the value
+	// @Example 11
+	ID int `json:"id" example:"11"`
+
+	// @Description The value
+	// @Example "M"
+	Value string `json:"value" example:"M"`
+
+	// @Description Display position within the option type
+	// @Example 1
+	Position int `json:"position" example:"1"`
+}
+
+// OptionTypeResponse represents an option type with its values
+type OptionTypeResponse struct {
+	// @Description Unique identifier of the option type
+	// @Example 1
+	ID int `json:"id" example:"1"`
+
+	// @Description Name of the option type
+	// @Example "size"
+	Name string `json:"name" example:"size"`
+
+	// @Description Values of the option type
+	Values []OptionValueResponse `json:"values"`
+}
+
+// CreateVariantRequest represents the request body for creating a product variant
+type CreateVariantRequest struct {
+	// @Description Unique stock keeping unit, stored in upper case
+	// @Example "CAM-AZUL-M"
+	SKU string `json:"sku" binding:"required" example:"CAM-AZUL-M"`
+
+	// @Description Optional price replacing the product price, in the product currency
+	// @Example "54.90"
+	PriceOverride *string `json:"price_override,omitempty" example:"54.90"`
+
+	// @Description Units in stock
+	// @Example 10
+	Stock int `json:"stock" binding:"min=0" example:"10"`
+
+	// @Description One option value ID per option type of the product
+	// @Example [11, 21]
+	OptionValueIDs []int `json:"option_value_ids" example:"11,21"`
+}
+
+// UpdateVariantRequest represents the request body for updating a product variant
+type UpdateVariantRequest struct {
+	// @Description Unique stock keeping unit, stored in upper case
+	// @Example "CAM-AZUL-M"
+	SKU string `json:"sku" binding:"required" example:"CAM-AZUL-M"`
+
+	// @Description Price replacing the product price; omit to inherit the product price
+	// @Example "54.90"
+	PriceOverride *string `json:"price_override,omitempty" example:"54.90"`
+
+	// @Description Units in stock
+	// @Example 10
+	Stock int `json:"stock" binding:"min=0" example:"10"`
+}
+
+// VariantOptionResponse represents the value a variant takes for an option type
+type VariantOptionResponse struct {
+	// @Description Name of the option type
+	// @Example "size"
+	OptionType string `json:"option_type" example:"size"`
+
+	// @Description ID of the option value
+	// @Example 11
+	OptionValueID int `json:"option_value_id" example:"11"`
+
+	// @Description The option value
+	// @Example "M"
+	Value string `json:"value" example:"M"`
+}
+
+// VariantResponse represents a product variant
+type VariantResponse struct {
+	// @Description Unique identifier of the variant
+	// @Example 1
+	ID int `json:"id" example:"1"`
+
+	// @Description Stock keeping unit
+	// @Example "CAM-AZUL-M"
+	SKU string `json:"sku" example:"CAM-AZUL-M"`
+
+	// @Description Price of the variant: its override or the product price
+	Price MoneyResponse `json:"price"`
+
+	// @Description Whether the price overrides the product price
+	// @Example false
+	PriceOverridden bool `json:"price_overridden" example:"false"`
+
+	// @Description Units in stock
+	// @Example 10
+	Stock int `json:"stock" example:"10"`
+
+	// @Description Option values of the variant
+	Options []VariantOptionResponse `json:"options"`
+}
+
+// ProductOptionResponse represents one axis of the variant matrix of a product
+type ProductOptionResponse struct {
+	// @Description Name of the option type
+	// @Example "size"
+	Name string `json:"name" example:"size"`
+
+	// @Description Values in use by the variants of the product
+	// @Example ["M", "G"]
+	Values []string `json:"values" example:"M,G"`
+}
@@ -0,0 +1,155 @@
+// Package audit carries the request metadata recorded with audit events and
+// computes their redacted diffs and chain hashes
+package audit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"go-api/model"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Redacted replaces the values of secret fields in diffs
+const Redacted = "[REDACTED]"
+
+// Metadata identifies who made a change and through which request
+type Metadata struct {
+	// ActorID is nil for anonymous requests
+	ActorID   *int
+	RequestID string
+	IP        string
+	UserAgent string
+}
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying the metadata
+func NewContext(ctx context.Context, metadata Metadata) context.Context {
+	return context.WithValue(ctx, contextKey{}, metadata)
+}
+
+// FromContext returns the metadata of the request, empty outside of one
+func FromContext(ctx context.Context) Metadata {
+	metadata, _ := ctx.Value(contextKey{}).(Metadata)
+	return metadata
+}
+
+// IsSecret reports whether the values of the field must never reach the log
+func IsSecret(field string) bool {
+	field = strings.ToLower(field)
+	for _, marker := range []string{"password", "secret", "token", "api_key"} {
+		if strings.Contains(field, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// Diff compares the JSON forms of before and after and returns the changed
+// top-level fields as {"field": {"before": ..., "after": ...}}. A nil side
+// (creation or purge) omits that key; secret fields show up as changed but
+// with their values redacted
+func Diff(before, after interface{}) (json.RawMessage, error) {
+	beforeFields, err := fields(before)
+	if err != nil {
+		return nil, err
+	}
+	afterFields, err := fields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(beforeFields)+len(afterFields))
+	for key := range beforeFields {
+		keys = append(keys, key)
+	}
+	for key := range afterFields {
+		if _, ok := beforeFields[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	changes := make(map[string]map[string]interface{})
+	for _, key := range keys {
+		oldValue, hadOld := beforeFields[key]
+		newValue, hasNew := afterFields[key]
+		if hadOld && hasNew && string(oldValue) == string(newValue) {
+			continue
+		}
+		change := make(map[string]interface{}, 2)
+		if hadOld {
+			change["before"] = redact(key, oldValue)
+		}
+		if hasNew {
+			change["after"] = redact(key, newValue)
+		}
+		changes[key] = change
+	}
+	return json.Marshal(changes)
+}
+
+// Hash chains the event to the previous one: it covers every recorded field
+// and the previous hash, in a fixed encoding
+func Hash(prevHash string, event model.AuditEvent) (string, error) {
+	changes := event.Changes
+	if len(changes) == 0 {
+		changes = json.RawMessage("{}")
+	}
+	payload, err := json.Marshal(struct {
+		PrevHash   string          `json:"prev_hash"`
+		OccurredAt string          `json:"occurred_at"`
+		ActorID    *int            `json:"actor_id"`
+		Action     string          `json:"action"`
+		EntityType string          `json:"entity_type"`
+		EntityID   string          `json:"entity_id"`
+		Changes    json.RawMessage `json:"changes"`
+		RequestID  string          `json:"request_id"`
+		IP         string          `json:"ip"`
+		UserAgent  string          `json:"user_agent"`
+	}{
+		PrevHash:   prevHash,
+		OccurredAt: event.OccurredAt.UTC().Format(time.RFC3339Nano),
+		ActorID:    event.ActorID,
+		Action:     event.Action,
+		EntityType: event.EntityType,
+		EntityID:   event.EntityID,
+		Changes:    changes,
+		RequestID:  event.RequestID,
+		IP:         event.IP,
+		UserAgent:  event.UserAgent,
+	})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(payload)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// --- Helper Functions ---
+
+func fields(value interface{}) (map[string]json.RawMessage, error) {
+	if value == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var result map[string]json.RawMessage
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func redact(field string, value json.RawMessage) interface{} {
+	if IsSecret(field) {
+		return Redacted
+	}
+	return value
+}
@@ -0,0 +1,86 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"go-api/model"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff(t *testing.T) {
+	t.Run("Only Changed Fields With Secrets Redacted", func(t *testing.T) {
+		before := map[string]interface{}{"name": "Leandro", "email": "old@example.com", "password": "$2a$10$old"}
+		after := map[string]interface{}{"name": "Leandro", "email": "new@example.com", "password": "$2a$10$new"}
+
+		changes, err := Diff(before, after)
+
+		require.NoError(t, err)
+		assert.JSONEq(t, `{
+			"email": {"before": "old@example.com", "after": "new@example.com"},
+			"password": {"before": "[REDACTED]", "after": "[REDACTED]"}
+		}`, string(changes))
+	})
+
+	t.Run("Creation Has No Before", func(t *testing.T) {
+		changes, err := Diff(nil, map[string]interface{}{"name": "Camiseta", "api_key": "k"})
+
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"name": {"after": "Camiseta"}, "api_key": {"after": "[REDACTED]"}}`, string(changes))
+	})
+
+	t.Run("No Changes", func(t *testing.T) {
+		changes, err := Diff(map[string]int{"stock": 1}, map[string]int{"stock": 1})
+
+		require.NoError(t, err)
+		assert.JSONEq(t, `{}`, string(changes))
+	})
+}
+
+func TestHash(t *testing.T) {
+	actor := 7
+	event := model.AuditEvent{
+		OccurredAt: time.Date(2026, 3, 1, 12, 0, 0, 123456000, time.UTC),
+		ActorID:    &actor,
+		Action:     model.AuditActionUpdate,
+		EntityType: model.AuditEntityUser,
+		EntityID:   "1",
+		Changes:    json.RawMessage(`{"name":{"before":"a","after":"b"}}`),
+		RequestID:  "req-1",
+		IP:         "10.0.0.1",
+		UserAgent:  "curl/8.0",
+	}
+
+	first, err := Hash("", event)
+	require.NoError(t, err)
+	again, err := Hash("", event)
+	require.NoError(t, err)
+	assert.Equal(t, first, again)
+	assert.Len(t, first, 64)
+
+	chained, err := Hash(first, event)
+	require.NoError(t, err)
+	assert.NotEqual(t, first, chained)
+
+	// The time zone of the instant does not matter, only the instant
+	event.OccurredAt = event.OccurredAt.In(time.FixedZone("BRT", -3*3600))
+	sameInstant, err := Hash("", event)
+	require.NoError(t, err)
+	assert.Equal(t, first, sameInstant)
+
+	event.Changes = json.RawMessage(`{"name":{"before":"a","after":"c"}}`)
+	tampered, err := Hash("", event)
+	require.NoError(t, err)
+	assert.NotEqual(t, first, tampered)
+}
+
+func TestContext(t *testing.T) {
+	actor := 7
+	ctx := NewContext(context.Background(), Metadata{ActorID: &actor, RequestID: "req-1"})
+
+	assert.Equal(t, Metadata{ActorID: &actor, RequestID: "req-1"}, FromContext(ctx))
+	assert.Equal(t, Metadata{}, FromContext(context.Background()))
+}
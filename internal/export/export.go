@@ -0,0 +1,97 @@
+// Package export writes tabular data row by row in CSV, NDJSON or XLSX so
+// large exports can be streamed without holding the rows in memory
+package export
+
+import (
+	"errors"
+	"io"
+	"mime"
+	"strings"
+)
+
+const (
+	FormatCSV    = "csv"
+	FormatNDJSON = "ndjson"
+	FormatXLSX   = "xlsx"
+)
+
+var ErrUnknownFormat = errors.New("export: unknown format")
+
+// Number is a decimal value written as a number cell, kept as a string so
+// the exact precision survives (e.g. monetary amounts)
+type Number string
+
+// Writer writes the rows of an export. Values must be in the column order
+// given to NewWriter; supported types are string, Number, int, int64 and bool
+type Writer interface {
+	WriteRow(values ...interface{}) error
+	// Close flushes the remaining data; it does not close the underlying writer
+	Close() error
+}
+
+// NewWriter creates a writer of the format that writes the header (when the
+// format has one) before the first row
+func NewWriter(format string, w io.Writer, columns []string) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w, columns)
+	case FormatNDJSON:
+		return newNDJSONWriter(w, columns), nil
+	case FormatXLSX:
+		return newXLSXWriter(w, columns)
+	default:
+		return nil, ErrUnknownFormat
+	}
+}
+
+var contentTypes = map[string]string{
+	FormatCSV:    "text/csv",
+	FormatNDJSON: "application/x-ndjson",
+	FormatXLSX:   "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+}
+
+// mediaTypes maps the accepted media types to formats, including aliases
+var mediaTypes = map[string]string{
+	"text/csv":               FormatCSV,
+	"application/csv":        FormatCSV,
+	"application/x-ndjson":   FormatNDJSON,
+	"application/ndjson":     FormatNDJSON,
+	"application/jsonl":      FormatNDJSON,
+	contentTypes[FormatXLSX]: FormatXLSX,
+}
+
+// ContentType returns the media type of the format
+func ContentType(format string) string {
+	return contentTypes[format]
+}
+
+// ParseFormat validates a format name given by the client
+func ParseFormat(name string) (string, error) {
+	format := strings.ToLower(strings.TrimSpace(name))
+	if _, ok := contentTypes[format]; !ok {
+		return "", ErrUnknownFormat
+	}
+	return format, nil
+}
+
+// FormatFromAccept picks the first supported format of an Accept header, in
+// the order the client listed them. Wildcards and an empty header select
+// fallback; ErrUnknownFormat means nothing acceptable is supported
+func FormatFromAccept(accept, fallback string) (string, error) {
+	if strings.TrimSpace(accept) == "" {
+		return fallback, nil
+	}
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil || params["q"] == "0" {
+			continue
+		}
+		if format, ok := mediaTypes[mediaType]; ok {
+			return format, nil
+		}
+		if mediaType == "*/*" {
+			return fallback, nil
+		}
+	}
+	return "", ErrUnknownFormat
+}
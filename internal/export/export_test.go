@@ -0,0 +1,98 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeAll(t *testing.T, format string, columns []string, rows ...[]interface{}) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	writer, err := NewWriter(format, &buf, columns)
+	require.NoError(t, err)
+	for _, row := range rows {
+		require.NoError(t, writer.WriteRow(row...))
+	}
+	require.NoError(t, writer.Close())
+	return buf.Bytes()
+}
+
+func TestCSVWriter(t *testing.T) {
+	out := writeAll(t, FormatCSV, []string{"id", "name", "price"},
+		[]interface{}{1, "Camiseta, azul", Number("49.90")},
+		[]interface{}{2, "=HYPERLINK(\"x\")", Number("-1.00")},
+	)
+
+	assert.Equal(t, "id,name,price\n1,\"Camiseta, azul\",49.90\n2,\"'=HYPERLINK(\"\"x\"\")\",-1.00\n", string(out))
+}
+
+func TestNDJSONWriter(t *testing.T) {
+	out := writeAll(t, FormatNDJSON, []string{"id", "name", "price", "active"},
+		[]interface{}{1, "Camiseta \"azul\"", Number("49.90"), true},
+	)
+
+	assert.Equal(t, "{\"id\":1,\"name\":\"Camiseta \\\"azul\\\"\",\"price\":49.90,\"active\":true}\n", string(out))
+	var decoded map[string]interface{}
+	assert.NoError(t, json.Unmarshal(out, &decoded))
+}
+
+func TestXLSXWriter(t *testing.T) {
+	out := writeAll(t, FormatXLSX, []string{"id", "name", "price"},
+		[]interface{}{1, "Tênis <esportivo> & cia", Number("199.90")},
+	)
+
+	archive, err := zip.NewReader(bytes.NewReader(out), int64(len(out)))
+	require.NoError(t, err)
+
+	var names []string
+	var sheet string
+	for _, file := range archive.File {
+		names = append(names, file.Name)
+		if file.Name == "xl/worksheets/sheet1.xml" {
+			reader, err := file.Open()
+			require.NoError(t, err)
+			content, err := io.ReadAll(reader)
+			require.NoError(t, err)
+			sheet = string(content)
+		}
+	}
+	assert.ElementsMatch(t, []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels", "xl/worksheets/sheet1.xml"}, names)
+	assert.Contains(t, sheet, `<row><c t="inlineStr"><is><t xml:space="preserve">id</t></is></c>`)
+	assert.Contains(t, sheet, `<row><c><v>1</v></c><c t="inlineStr"><is><t xml:space="preserve">Tênis &lt;esportivo&gt; &amp; cia</t></is></c><c><v>199.90</v></c></row>`)
+	assert.True(t, strings.HasSuffix(sheet, "</sheetData></worksheet>"))
+}
+
+func TestNewWriterUnknownFormat(t *testing.T) {
+	_, err := NewWriter("pdf", io.Discard, []string{"id"})
+	assert.ErrorIs(t, err, ErrUnknownFormat)
+}
+
+func TestFormatFromAccept(t *testing.T) {
+	tests := []struct {
+		accept string
+		want   string
+		err    error
+	}{
+		{"", FormatCSV, nil},
+		{"*/*", FormatCSV, nil},
+		{"application/x-ndjson", FormatNDJSON, nil},
+		{"application/json;q=0.9, application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", FormatXLSX, nil},
+		{"text/csv;q=0, application/ndjson", FormatNDJSON, nil},
+		{"application/pdf", "", ErrUnknownFormat},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.accept, func(t *testing.T) {
+			format, err := FormatFromAccept(tt.accept, FormatCSV)
+			assert.Equal(t, tt.want, format)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
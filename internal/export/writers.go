@@ -0,0 +1,214 @@
+package export
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// --- CSV ---
+
+type csvWriter struct {
+	writer *csv.Writer
+	record []string
+}
+
+func newCSVWriter(w io.Writer, columns []string) (*csvWriter, error) {
+	cw := &csvWriter{writer: csv.NewWriter(w), record: make([]string, len(columns))}
+	if err := cw.writer.Write(columns); err != nil {
+		return nil, err
+	}
+	return cw, nil
+}
+
+func (cw *csvWriter) WriteRow(values ...interface{}) error {
+	for i, value := range values {
+		if s, ok := value.(string); ok {
+			cw.record[i] = escapeFormula(s)
+			continue
+		}
+		cw.record[i] = formatValue(value)
+	}
+	if err := cw.writer.Write(cw.record[:len(values)]); err != nil {
+		return err
+	}
+	return cw.writer.Error()
+}
+
+func (cw *csvWriter) Close() error {
+	cw.writer.Flush()
+	return cw.writer.Error()
+}
+
+// escapeFormula prefixes text that spreadsheets would evaluate as a formula,
+// so a product or user name cannot inject one into the finance team's sheet
+func escapeFormula(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+// --- NDJSON ---
+
+type ndjsonWriter struct {
+	writer  *bufio.Writer
+	columns [][]byte
+}
+
+func newNDJSONWriter(w io.Writer, columns []string) *ndjsonWriter {
+	keys := make([][]byte, len(columns))
+	for i, column := range columns {
+		keys[i], _ = json.Marshal(column)
+	}
+	return &ndjsonWriter{writer: bufio.NewWriter(w), columns: keys}
+}
+
+func (nw *ndjsonWriter) WriteRow(values ...interface{}) error {
+	// The object is written by hand to keep the column order
+	nw.writer.WriteByte('{')
+	for i, value := range values {
+		if i > 0 {
+			nw.writer.WriteByte(',')
+		}
+		nw.writer.Write(nw.columns[i])
+		nw.writer.WriteByte(':')
+		var encoded []byte
+		var err error
+		if number, ok := value.(Number); ok {
+			encoded = []byte(number)
+		} else {
+			encoded, err = json.Marshal(value)
+		}
+		if err != nil {
+			return err
+		}
+		nw.writer.Write(encoded)
+	}
+	nw.writer.WriteString("}\n")
+	// bufio keeps the first write error, so checking once per row is enough
+	_, err := nw.writer.Write(nil)
+	return err
+}
+
+func (nw *ndjsonWriter) Close() error {
+	return nw.writer.Flush()
+}
+
+// --- XLSX ---
+
+// The workbook parts other than the sheet are fixed, the sheet is streamed
+// as the last zip entry and closed by Close
+const (
+	xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/><Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/><Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/></Types>`
+	xlsxRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/></Relationships>`
+	xlsxWorkbook = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="Export" sheetId="1" r:id="rId1"/></sheets></workbook>`
+	xlsxWorkbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/></Relationships>`
+	xlsxSheetStart = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`
+	xlsxSheetEnd = `</sheetData></worksheet>`
+)
+
+type xlsxWriter struct {
+	archive *zip.Writer
+	sheet   *bufio.Writer
+}
+
+func newXLSXWriter(w io.Writer, columns []string) (*xlsxWriter, error) {
+	archive := zip.NewWriter(w)
+	parts := []struct{ name, content string }{
+		{"[Content_Types].xml", xlsxContentTypes},
+		{"_rels/.rels", xlsxRels},
+		{"xl/workbook.xml", xlsxWorkbook},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels},
+	}
+	for _, part := range parts {
+		entry, err := archive.Create(part.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.WriteString(entry, part.content); err != nil {
+			return nil, err
+		}
+	}
+
+	entry, err := archive.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+	xw := &xlsxWriter{archive: archive, sheet: bufio.NewWriter(entry)}
+	xw.sheet.WriteString(xlsxSheetStart)
+
+	header := make([]interface{}, len(columns))
+	for i, column := range columns {
+		header[i] = column
+	}
+	if err := xw.WriteRow(header...); err != nil {
+		return nil, err
+	}
+	return xw, nil
+}
+
+func (xw *xlsxWriter) WriteRow(values ...interface{}) error {
+	xw.sheet.WriteString("<row>")
+	for _, value := range values {
+		switch v := value.(type) {
+		case string:
+			// Inline strings avoid a shared string table, which would need every
+			// value before the sheet could be written
+			xw.sheet.WriteString(`<c t="inlineStr"><is><t xml:space="preserve">`)
+			xml.EscapeText(xw.sheet, []byte(v))
+			xw.sheet.WriteString("</t></is></c>")
+		case bool:
+			cell := `<c t="b"><v>0</v></c>`
+			if v {
+				cell = `<c t="b"><v>1</v></c>`
+			}
+			xw.sheet.WriteString(cell)
+		default:
+			fmt.Fprintf(xw.sheet, "<c><v>%s</v></c>", formatValue(v))
+		}
+	}
+	xw.sheet.WriteString("</row>")
+	_, err := xw.sheet.Write(nil)
+	return err
+}
+
+func (xw *xlsxWriter) Close() error {
+	xw.sheet.WriteString(xlsxSheetEnd)
+	if err := xw.sheet.Flush(); err != nil {
+		return err
+	}
+	return xw.archive.Close()
+}
+
+// --- Helper Functions ---
+
+func formatValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case Number:
+		return string(v)
+	case int:
+		return strconv.Itoa(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,112 @@
+// Package imaging inspects uploaded images and renders thumbnails using only
+// the standard library decoders (JPEG, PNG and GIF)
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/gif"
+	"image/jpeg"
+	_ "image/png"
+	"net/http"
+)
+
+var ErrUnknownFormat = errors.New("imaging: unknown image format")
+
+// Info describes an image without decoding its pixels
+type Info struct {
+	// ContentType is sniffed from the content, never taken from the client
+	ContentType string
+	Format      string
+	Width       int
+	Height      int
+}
+
+// Inspect sniffs the content type and reads the dimensions from the image header
+func Inspect(data []byte) (Info, error) {
+	info := Info{ContentType: http.DetectContentType(data)}
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		if errors.Is(err, image.ErrFormat) {
+			return info, ErrUnknownFormat
+		}
+		return info, err
+	}
+	info.Format = format
+	info.Width = config.Width
+	info.Height = config.Height
+	return info, nil
+}
+
+// Decode decodes the full image
+func Decode(data []byte) (image.Image, error) {
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if errors.Is(err, image.ErrFormat) {
+		return nil, ErrUnknownFormat
+	}
+	return img, err
+}
+
+// Fit returns the size of the image scaled to fit in a maxSide square,
+// keeping the aspect ratio and never upscaling
+func Fit(width, height, maxSide int) (int, int) {
+	if width <= maxSide && height <= maxSide {
+		return width, height
+	}
+	if width >= height {
+		return maxSide, max(1, height*maxSide/width)
+	}
+	return max(1, width*maxSide/height), maxSide
+}
+
+// Thumbnail scales the image to fit in a maxSide square. Each destination
+// pixel averages the source pixels it covers, which avoids the aliasing of
+// nearest-neighbour sampling when shrinking
+func Thumbnail(src image.Image, maxSide int) *image.RGBA {
+	bounds := src.Bounds()
+	srcW, srcH := bounds.Dx(), bounds.Dy()
+	dstW, dstH := Fit(srcW, srcH, maxSide)
+	dst := image.NewRGBA(image.Rect(0, 0, dstW, dstH))
+
+	for y := 0; y < dstH; y++ {
+		y0 := bounds.Min.Y + y*srcH/dstH
+		y1 := max(y0+1, bounds.Min.Y+(y+1)*srcH/dstH)
+		for x := 0; x < dstW; x++ {
+			x0 := bounds.Min.X + x*srcW/dstW
+			x1 := max(x0+1, bounds.Min.X+(x+1)*srcW/dstW)
+
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, ca := src.At(sx, sy).RGBA()
+					r, g, b, a = r+uint64(cr), g+uint64(cg), b+uint64(cb), a+uint64(ca)
+					n++
+				}
+			}
+			dst.SetRGBA(x, y, color.RGBA{
+				R: uint8(r / n >> 8),
+				G: uint8(g / n >> 8),
+				B: uint8(b / n >> 8),
+				A: uint8(a / n >> 8),
+			})
+		}
+	}
+	return dst
+}
+
+// EncodeJPEG flattens the image on a white background, since JPEG has no
+// transparency, and encodes it
+func EncodeJPEG(img image.Image, quality int) ([]byte, error) {
+	flat := image.NewRGBA(img.Bounds())
+	draw.Draw(flat, flat.Bounds(), image.White, image.Point{}, draw.Src)
+	draw.Draw(flat, flat.Bounds(), img, img.Bounds().Min, draw.Over)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, flat, &jpeg.Options{Quality: quality}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
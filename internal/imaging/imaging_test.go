@@ -0,0 +1,80 @@
+package imaging
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func pngBytes(t *testing.T, width, height int, fill color.Color) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, fill)
+		}
+	}
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+func TestInspect(t *testing.T) {
+	t.Run("PNG", func(t *testing.T) {
+		info, err := Inspect(pngBytes(t, 30, 20, color.White))
+		require.NoError(t, err)
+		assert.Equal(t, Info{ContentType: "image/png", Format: "png", Width: 30, Height: 20}, info)
+	})
+
+	t.Run("NotAnImage", func(t *testing.T) {
+		info, err := Inspect([]byte("<html><body>hi</body></html>"))
+		assert.Equal(t, ErrUnknownFormat, err)
+		assert.Equal(t, "text/html; charset=utf-8", info.ContentType)
+	})
+}
+
+func TestFit(t *testing.T) {
+	tests := []struct {
+		width, height, maxSide int
+		wantW, wantH           int
+	}{
+		{1600, 1200, 400, 400, 300},
+		{1200, 1600, 400, 300, 400},
+		{100, 50, 400, 100, 50},
+		{5000, 1, 150, 150, 1},
+	}
+	for _, tt := range tests {
+		w, h := Fit(tt.width, tt.height, tt.maxSide)
+		assert.Equal(t, tt.wantW, w)
+		assert.Equal(t, tt.wantH, h)
+	}
+}
+
+func TestThumbnail(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 40, 20))
+	for y := 0; y < 20; y++ {
+		for x := 0; x < 40; x++ {
+			if x < 20 {
+				src.Set(x, y, color.RGBA{R: 255, A: 255})
+			} else {
+				src.Set(x, y, color.RGBA{B: 255, A: 255})
+			}
+		}
+	}
+
+	thumb := Thumbnail(src, 10)
+	assert.Equal(t, image.Rect(0, 0, 10, 5), thumb.Bounds())
+	assert.Equal(t, color.RGBA{R: 255, A: 255}, thumb.RGBAAt(0, 0))
+	assert.Equal(t, color.RGBA{B: 255, A: 255}, thumb.RGBAAt(9, 4))
+
+	data, err := EncodeJPEG(thumb, 85)
+	require.NoError(t, err)
+	info, err := Inspect(data)
+	require.NoError(t, err)
+	assert.Equal(t, "image/jpeg", info.ContentType)
+	assert.Equal(t, 10, info.Width)
+}
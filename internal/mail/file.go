@@ -0,0 +1,48 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+)
+
+// FileMailer writes each message to a .eml file in a directory, for local
+// runs without an SMTP server; the files open in any mail client
+type FileMailer struct {
+	dir  string
+	from *mail.Address
+	seq  atomic.Int64
+}
+
+var _ Mailer = (*FileMailer)(nil)
+
+// NewFileMailer creates the directory when it does not exist
+func NewFileMailer(dir, from string) (*FileMailer, error) {
+	sender, err := mail.ParseAddress(from)
+	if err != nil {
+		return nil, fmt.Errorf("mail: invalid sender %q: %w", from, err)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &FileMailer{dir: dir, from: sender}, nil
+}
+
+// Send writes the message as <unix nanos>-<sequence>.eml, so the files sort
+// in the order they were sent
+func (fm *FileMailer) Send(ctx context.Context, message Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	now := time.Now()
+	data, _, err := formatMessage(fm.from, message, now)
+	if err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%d-%06d.eml", now.UnixNano(), fm.seq.Add(1))
+	return os.WriteFile(filepath.Join(fm.dir, name), data, 0o600)
+}
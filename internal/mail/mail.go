@@ -0,0 +1,94 @@
+// Package mail sends emails through the Mailer interface. The "smtp" driver
+// talks to a real server; "file" writes each message to a directory and
+// "memory" keeps them in memory, so local runs and tests need no server.
+package mail
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+var ErrInvalidMessage = errors.New("mail: invalid message")
+
+// Message is a plain text email
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer delivers emails
+type Mailer interface {
+	Send(ctx context.Context, message Message) error
+}
+
+// Config selects and configures the Mailer
+type Config struct {
+	// Driver is "smtp", "file" or "memory"
+	Driver string
+	// From is the sender of every message
+	From string
+
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUsername string
+	SMTPPassword string
+
+	// FileDir is where the "file" driver writes the messages
+	FileDir string
+}
+
+// NewConfig reads the mail configuration from environment variables
+func NewConfig() *Config {
+	return &Config{
+		Driver:       getEnv("MAIL_DRIVER", "file"),
+		From:         getEnv("MAIL_FROM", "no-reply@localhost"),
+		SMTPHost:     getEnv("SMTP_HOST", ""),
+		SMTPPort:     getEnv("SMTP_PORT", "587"),
+		SMTPUsername: getEnv("SMTP_USERNAME", ""),
+		SMTPPassword: getEnv("SMTP_PASSWORD", ""),
+		FileDir:      getEnv("MAIL_FILE_DIR", "./mail"),
+	}
+}
+
+// New creates the Mailer selected by the configuration
+func New(config *Config) (Mailer, error) {
+	switch config.Driver {
+	case "smtp":
+		return NewSMTPMailer(SMTPOptions{
+			Host:     config.SMTPHost,
+			Port:     config.SMTPPort,
+			Username: config.SMTPUsername,
+			Password: config.SMTPPassword,
+			From:     config.From,
+		})
+	case "file":
+		return NewFileMailer(config.FileDir, config.From)
+	case "memory":
+		return NewMemoryMailer(), nil
+	default:
+		return nil, fmt.Errorf("mail: unknown driver %q", config.Driver)
+	}
+}
+
+// validate rejects messages without a recipient and headers that could
+// inject other headers
+func (m Message) validate() error {
+	if strings.TrimSpace(m.To) == "" {
+		return fmt.Errorf("%w: missing recipient", ErrInvalidMessage)
+	}
+	if strings.ContainsAny(m.To, "\r\n") || strings.ContainsAny(m.Subject, "\r\n") {
+		return fmt.Errorf("%w: line break in a header", ErrInvalidMessage)
+	}
+	return nil
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
@@ -0,0 +1,119 @@
+package mail
+
+import (
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatMessage(t *testing.T) {
+	from := &mail.Address{Name: "Loja", Address: "no-reply@loja.example"}
+
+	t.Run("Success", func(t *testing.T) {
+		message := Message{To: "ana@example.com", Subject: "Redefinição de senha", Body: "Olá, Ana!\nUse o link abaixo."}
+		data, to, err := formatMessage(from, message, time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC))
+		require.NoError(t, err)
+		assert.Equal(t, "ana@example.com", to.Address)
+
+		parsed, err := mail.ReadMessage(strings.NewReader(string(data)))
+		require.NoError(t, err)
+		subject, err := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+		require.NoError(t, err)
+		assert.Equal(t, "Redefinição de senha", subject)
+		assert.Equal(t, `"Loja" <no-reply@loja.example>`, parsed.Header.Get("From"))
+		assert.Equal(t, "<ana@example.com>", parsed.Header.Get("To"))
+		assert.Equal(t, "Fri, 02 Jan 2026 03:04:05 +0000", parsed.Header.Get("Date"))
+		assert.True(t, strings.HasSuffix(parsed.Header.Get("Message-ID"), "@loja.example>"))
+
+		body, err := io.ReadAll(quotedprintable.NewReader(parsed.Body))
+		require.NoError(t, err)
+		assert.Equal(t, "Olá, Ana!\r\nUse o link abaixo.", string(body))
+	})
+
+	t.Run("Rejects Header Injection", func(t *testing.T) {
+		_, _, err := formatMessage(from, Message{To: "ana@example.com", Subject: "Oi\r\nBcc: eve@example.com"}, time.Now())
+		assert.True(t, errors.Is(err, ErrInvalidMessage))
+	})
+
+	t.Run("Rejects Invalid Recipient", func(t *testing.T) {
+		_, _, err := formatMessage(from, Message{To: "not an address", Subject: "Oi"}, time.Now())
+		assert.True(t, errors.Is(err, ErrInvalidMessage))
+	})
+}
+
+func TestSMTPMailer(t *testing.T) {
+	t.Run("Sends To The Server", func(t *testing.T) {
+		mailer, err := NewSMTPMailer(SMTPOptions{Host: "smtp.example.com", Port: "587", Username: "user", Password: "secret", From: "no-reply@loja.example"})
+		require.NoError(t, err)
+
+		var sentTo []string
+		var sentAddr, sentFrom string
+		mailer.send = func(addr string, auth smtp.Auth, from string, to []string, msg []byte) error {
+			assert.NotNil(t, auth)
+			sentAddr, sentFrom, sentTo = addr, from, to
+			return nil
+		}
+
+		err = mailer.Send(context.Background(), Message{To: "Ana <ana@example.com>", Subject: "Oi", Body: "Olá"})
+		assert.NoError(t, err)
+		assert.Equal(t, "smtp.example.com:587", sentAddr)
+		assert.Equal(t, "no-reply@loja.example", sentFrom)
+		assert.Equal(t, []string{"ana@example.com"}, sentTo)
+	})
+
+	t.Run("Host Required", func(t *testing.T) {
+		_, err := NewSMTPMailer(SMTPOptions{Port: "587", From: "no-reply@loja.example"})
+		assert.Error(t, err)
+	})
+}
+
+func TestFileMailer(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "mail")
+	mailer, err := NewFileMailer(dir, "no-reply@localhost")
+	require.NoError(t, err)
+
+	require.NoError(t, mailer.Send(context.Background(), Message{To: "ana@example.com", Subject: "Primeiro", Body: "1"}))
+	require.NoError(t, mailer.Send(context.Background(), Message{To: "ana@example.com", Subject: "Segundo", Body: "2"}))
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	data, err := os.ReadFile(filepath.Join(dir, entries[1].Name()))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "Subject: Segundo\r\n")
+	assert.True(t, strings.HasSuffix(entries[1].Name(), ".eml"))
+}
+
+func TestMemoryMailer(t *testing.T) {
+	mailer := NewMemoryMailer()
+
+	require.NoError(t, mailer.Send(context.Background(), Message{To: "ana@example.com", Subject: "Oi"}))
+	assert.Equal(t, []Message{{To: "ana@example.com", Subject: "Oi"}}, mailer.Messages())
+
+	unreachable := errors.New("connection refused")
+	mailer.Fail(unreachable)
+	assert.Equal(t, unreachable, mailer.Send(context.Background(), Message{To: "ana@example.com"}))
+	mailer.Fail(nil)
+	assert.Len(t, mailer.Messages(), 1)
+}
+
+func TestNew(t *testing.T) {
+	mailer, err := New(&Config{Driver: "memory"})
+	require.NoError(t, err)
+	assert.IsType(t, &MemoryMailer{}, mailer)
+
+	_, err = New(&Config{Driver: "pigeon"})
+	assert.Error(t, err)
+}
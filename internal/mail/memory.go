@@ -0,0 +1,51 @@
+package mail
+
+import (
+	"context"
+	"sync"
+)
+
+// MemoryMailer keeps the messages it is given, for tests
+type MemoryMailer struct {
+	mu       sync.Mutex
+	messages []Message
+	// fail, when set, is returned by Send instead of keeping the message
+	fail error
+}
+
+var _ Mailer = (*MemoryMailer)(nil)
+
+func NewMemoryMailer() *MemoryMailer {
+	return &MemoryMailer{}
+}
+
+func (mm *MemoryMailer) Send(ctx context.Context, message Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := message.validate(); err != nil {
+		return err
+	}
+	mm.mu.Lock()
+	defer mm.mu.Unlock()
+	if mm.fail != nil {
+		return mm.fail
+	}
+	mm.messages = append(mm.messages, message)
+	return nil
+}
+
+// Messages returns a copy of the messages sent so far, oldest first
+func (mm *MemoryMailer) Messages() []Message {
+	mm.mu.Lock()
+	defer mm.mu.Unlock()
+	return append([]Message(nil), mm.messages...)
+}
+
+// Fail makes the next sends return err, as an unreachable server would; nil
+// makes them succeed again
+func (mm *MemoryMailer) Fail(err error) {
+	mm.mu.Lock()
+	defer mm.mu.Unlock()
+	mm.fail = err
+}
@@ -0,0 +1,112 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// SMTPOptions configures an SMTPMailer
+type SMTPOptions struct {
+	Host string
+	Port string
+	// Username and Password enable PLAIN authentication, which net/smtp only
+	// sends over TLS (STARTTLS) or to localhost
+	Username string
+	Password string
+	From     string
+}
+
+// SMTPMailer sends the messages through an SMTP server
+type SMTPMailer struct {
+	addr string
+	auth smtp.Auth
+	from *mail.Address
+	// send is smtp.SendMail, replaced in tests
+	send func(addr string, auth smtp.Auth, from string, to []string, msg []byte) error
+}
+
+var _ Mailer = (*SMTPMailer)(nil)
+
+// NewSMTPMailer validates the options and creates the mailer
+func NewSMTPMailer(options SMTPOptions) (*SMTPMailer, error) {
+	if options.Host == "" {
+		return nil, errors.New("mail: SMTP_HOST is required by the smtp driver")
+	}
+	from, err := mail.ParseAddress(options.From)
+	if err != nil {
+		return nil, fmt.Errorf("mail: invalid sender %q: %w", options.From, err)
+	}
+
+	var auth smtp.Auth
+	if options.Username != "" {
+		auth = smtp.PlainAuth("", options.Username, options.Password, options.Host)
+	}
+	return &SMTPMailer{
+		addr: net.JoinHostPort(options.Host, options.Port),
+		auth: auth,
+		from: from,
+		send: smtp.SendMail,
+	}, nil
+}
+
+// Send delivers the message; net/smtp has no timeouts of its own, so a
+// canceled context only stops the send from starting
+func (sm *SMTPMailer) Send(ctx context.Context, message Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	data, to, err := formatMessage(sm.from, message, time.Now())
+	if err != nil {
+		return err
+	}
+	return sm.send(sm.addr, sm.auth, sm.from.Address, []string{to.Address}, data)
+}
+
+// formatMessage renders the message as RFC 5322 text, UTF-8 and
+// quoted-printable, returning it with the parsed recipient
+func formatMessage(from *mail.Address, message Message, now time.Time) ([]byte, *mail.Address, error) {
+	if err := message.validate(); err != nil {
+		return nil, nil, err
+	}
+	to, err := mail.ParseAddress(message.To)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: recipient %q: %v", ErrInvalidMessage, message.To, err)
+	}
+
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, nil, err
+	}
+	domain := from.Address[strings.LastIndex(from.Address, "@")+1:]
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", from.String())
+	fmt.Fprintf(&buf, "To: %s\r\n", to.String())
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", message.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", now.Format(time.RFC1123Z))
+	fmt.Fprintf(&buf, "Message-ID: <%s@%s>\r\n", hex.EncodeToString(id), domain)
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+
+	body := quotedprintable.NewWriter(&buf)
+	text := strings.ReplaceAll(strings.ReplaceAll(message.Body, "\r\n", "\n"), "\n", "\r\n")
+	if _, err := body.Write([]byte(text)); err != nil {
+		return nil, nil, err
+	}
+	if err := body.Close(); err != nil {
+		return nil, nil, err
+	}
+	return buf.Bytes(), to, nil
+}
@@ -0,0 +1,167 @@
+package oidc
+
+import (
+	"context"
+	"errors"
+	"go-api/internal/oidc/oidctest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestProvider(t *testing.T, server *oidctest.Server) *Provider {
+	provider, err := New(&Config{
+		Issuer:       server.Issuer(),
+		ClientID:     server.ClientID,
+		ClientSecret: server.ClientSecret,
+		RedirectURL:  "http://localhost:3000/callback",
+		Scopes:       []string{"email"},
+		Timeout:      5 * time.Second,
+	})
+	assert.NoError(t, err)
+	return provider
+}
+
+// signIn runs the authorization code flow against server as claims and
+// returns the ID token, or the error of the exchange
+func signIn(t *testing.T, provider *Provider, server *oidctest.Server, claims oidctest.Claims) (*IDToken, error) {
+	verifier, err := NewCodeVerifier()
+	assert.NoError(t, err)
+	authURL, err := provider.AuthCodeURL(context.Background(), "the-state", "the-nonce", CodeChallenge(verifier))
+	assert.NoError(t, err)
+	code, state, err := server.Login(authURL, claims)
+	assert.NoError(t, err)
+	assert.Equal(t, "the-state", state)
+	return provider.Exchange(context.Background(), code, verifier, "the-nonce")
+}
+
+func TestAuthorizationCodeFlow(t *testing.T) {
+	for _, secret := range []string{"s3cr&t:value", ""} {
+		server := oidctest.NewServer("go-api", secret)
+		defer server.Close()
+		provider := newTestProvider(t, server)
+
+		token, err := signIn(t, provider, server, oidctest.Claims{
+			Subject: "248289761001", Email: "ana@corp.example", EmailVerified: true, Name: "Ana", AMR: []string{"pwd", "mfa"},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, &IDToken{Subject: "248289761001", Email: "ana@corp.example", EmailVerified: true, Name: "Ana", AMR: []string{"pwd", "mfa"}}, token)
+		assert.True(t, token.MFA())
+	}
+}
+
+func TestAuthCodeURL(t *testing.T) {
+	server := oidctest.NewServer("go-api", "secret")
+	defer server.Close()
+
+	authURL, err := newTestProvider(t, server).AuthCodeURL(context.Background(), "state", "nonce", CodeChallenge("verifier"))
+	assert.NoError(t, err)
+
+	parsed, err := url.Parse(authURL)
+	assert.NoError(t, err)
+	query := parsed.Query()
+	assert.Equal(t, server.Issuer()+"/authorize", parsed.Scheme+"://"+parsed.Host+parsed.Path)
+	assert.Equal(t, "openid email", query.Get("scope"))
+	assert.Equal(t, "S256", query.Get("code_challenge_method"))
+	// RFC 7636, appendix B
+	assert.Equal(t, "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM", CodeChallenge("dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"))
+}
+
+func TestExchangeRejectsAWrongVerifier(t *testing.T) {
+	server := oidctest.NewServer("go-api", "secret")
+	defer server.Close()
+	provider := newTestProvider(t, server)
+
+	authURL, err := provider.AuthCodeURL(context.Background(), "state", "nonce", CodeChallenge("the verifier"))
+	assert.NoError(t, err)
+	code, _, err := server.Login(authURL, oidctest.Claims{Subject: "1"})
+	assert.NoError(t, err)
+
+	_, err = provider.Exchange(context.Background(), code, "another verifier", "nonce")
+	assert.ErrorContains(t, err, "invalid_grant")
+}
+
+func TestVerifyIDToken(t *testing.T) {
+	server := oidctest.NewServer("go-api", "secret")
+	defer server.Close()
+	other := oidctest.NewServer("go-api", "secret")
+	defer other.Close()
+	provider := newTestProvider(t, server)
+
+	valid := func(change func(jwt.MapClaims)) jwt.MapClaims {
+		now := time.Now()
+		claims := jwt.MapClaims{"iss": server.Issuer(), "sub": "1", "aud": "go-api", "exp": now.Add(time.Minute).Unix(), "iat": now.Unix(), "nonce": "nonce"}
+		change(claims)
+		return claims
+	}
+
+	_, err := provider.VerifyIDToken(context.Background(), server.SignIDToken(valid(func(jwt.MapClaims) {})), "nonce")
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name  string
+		token string
+		nonce string
+	}{
+		{"Wrong Nonce", server.SignIDToken(valid(func(jwt.MapClaims) {})), "another"},
+		{"Wrong Audience", server.SignIDToken(valid(func(c jwt.MapClaims) { c["aud"] = "someone-else" })), "nonce"},
+		{"Issued To Another Party", server.SignIDToken(valid(func(c jwt.MapClaims) { c["aud"] = []string{"go-api", "x"}; c["azp"] = "x" })), "nonce"},
+		{"Wrong Issuer", server.SignIDToken(valid(func(c jwt.MapClaims) { c["iss"] = other.Issuer() })), "nonce"},
+		{"Expired", server.SignIDToken(valid(func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Hour).Unix() })), "nonce"},
+		{"No Expiry", server.SignIDToken(valid(func(c jwt.MapClaims) { delete(c, "exp") })), "nonce"},
+		{"No Subject", server.SignIDToken(valid(func(c jwt.MapClaims) { delete(c, "sub") })), "nonce"},
+		{"Signed By Another Provider", other.SignIDToken(valid(func(jwt.MapClaims) {})), "nonce"},
+		{"Unsigned", unsigned(valid(func(jwt.MapClaims) {})), "nonce"},
+		{"HMAC With The Client Secret", hmacSigned(valid(func(jwt.MapClaims) {}), "secret"), "nonce"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := provider.VerifyIDToken(context.Background(), tt.token, tt.nonce)
+
+			assert.True(t, errors.Is(err, ErrInvalidIDToken), err)
+		})
+	}
+}
+
+func TestKeyRotation(t *testing.T) {
+	server := oidctest.NewServer("go-api", "secret")
+	defer server.Close()
+	provider := newTestProvider(t, server)
+
+	_, err := signIn(t, provider, server, oidctest.Claims{Subject: "1"})
+	assert.NoError(t, err)
+
+	// Unknown keys are looked up at most once a minute
+	server.RotateKey()
+	_, err = signIn(t, provider, server, oidctest.Claims{Subject: "1"})
+	assert.True(t, errors.Is(err, ErrInvalidIDToken))
+
+	provider.keysFetchedAt = time.Now().Add(-keysRefreshInterval)
+	_, err = signIn(t, provider, server, oidctest.Claims{Subject: "1"})
+	assert.NoError(t, err)
+}
+
+func TestDiscoveryChecksTheIssuer(t *testing.T) {
+	server := oidctest.NewServer("go-api", "secret")
+	defer server.Close()
+
+	provider, err := New(&Config{Issuer: server.Issuer() + "/", ClientID: "go-api", RedirectURL: "http://localhost/callback"})
+	assert.NoError(t, err)
+	_, err = provider.AuthCodeURL(context.Background(), "state", "nonce", "challenge")
+
+	assert.ErrorContains(t, err, "does not match")
+}
+
+func unsigned(claims jwt.MapClaims) string {
+	token, _ := jwt.NewWithClaims(jwt.SigningMethodNone, claims).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	return token
+}
+
+func hmacSigned(claims jwt.MapClaims, secret string) string {
+	token, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+	return token
+}
@@ -0,0 +1,104 @@
+// Package password hashes passwords and checks their strength. New hashes
+// are PHC strings such as $argon2id$v=19$m=65536,t=3,p=4$<salt>$<hash>,
+// which carry the algorithm and its parameters: hashes made with older
+// settings keep working and can be told apart to be rehashed. bcrypt hashes
+// ($2a$, $2b$, $2y$), the format used before, are still verified.
+package password
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrUnknownHash   = errors.New("password: unknown hash format")
+	ErrMalformedHash = errors.New("password: malformed hash")
+)
+
+// Hasher hashes passwords with one algorithm and verifies the hashes of
+// every supported one
+type Hasher interface {
+	// Hash returns the hash of password with a new random salt
+	Hash(password string) (string, error)
+	// Verify tells whether password matches hash
+	Verify(password, hash string) (bool, error)
+	// NeedsRehash tells that hash was made with another algorithm, or other
+	// parameters, than Hash uses now
+	NeedsRehash(hash string) bool
+}
+
+// Verify tells whether password matches hash, of any supported algorithm
+func Verify(password, hash string) (bool, error) {
+	switch {
+	case strings.HasPrefix(hash, "$"+argon2idName+"$"):
+		return verifyArgon2id(password, hash)
+	case isBcrypt(hash):
+		return verifyBcrypt(password, hash)
+	default:
+		return false, ErrUnknownHash
+	}
+}
+
+// Config selects the algorithm of new hashes and its parameters
+type Config struct {
+	// Algorithm is "argon2id" or "bcrypt"
+	Algorithm string
+	Argon2id  Argon2idParams
+	// BcryptCost is the cost of new bcrypt hashes
+	BcryptCost int
+}
+
+// NewConfig reads the hashing configuration from environment variables
+func NewConfig() *Config {
+	return &Config{
+		Algorithm: getEnv("PASSWORD_HASH_ALGORITHM", argon2idName),
+		Argon2id: Argon2idParams{
+			Memory:      uint32(getEnvInt("PASSWORD_ARGON2_MEMORY", int(DefaultArgon2idParams.Memory))),
+			Iterations:  uint32(getEnvInt("PASSWORD_ARGON2_ITERATIONS", int(DefaultArgon2idParams.Iterations))),
+			Parallelism: uint8(getEnvInt("PASSWORD_ARGON2_PARALLELISM", int(DefaultArgon2idParams.Parallelism))),
+			SaltLength:  DefaultArgon2idParams.SaltLength,
+			KeyLength:   DefaultArgon2idParams.KeyLength,
+		},
+		BcryptCost: getEnvInt("PASSWORD_BCRYPT_COST", DefaultBcryptCost),
+	}
+}
+
+// New creates the Hasher selected by the configuration
+func New(config *Config) (Hasher, error) {
+	switch config.Algorithm {
+	case argon2idName:
+		hasher, err := NewArgon2idHasher(config.Argon2id)
+		if err != nil {
+			return nil, err
+		}
+		return hasher, nil
+	case "bcrypt":
+		hasher, err := NewBcryptHasher(config.BcryptCost)
+		if err != nil {
+			return nil, err
+		}
+		return hasher, nil
+	default:
+		return nil, fmt.Errorf("password: unknown algorithm %q", config.Algorithm)
+	}
+}
+
+// --- Helper Functions ---
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+func getEnvInt(key string, defaultValue int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil || value <= 0 {
+		return defaultValue
+	}
+	return value
+}
@@ -0,0 +1,141 @@
+package password
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// testParams keep the tests fast; production uses DefaultArgon2idParams
+var testParams = Argon2idParams{Memory: 64, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+
+func TestArgon2idHasher(t *testing.T) {
+	hasher, err := NewArgon2idHasher(testParams)
+	assert.NoError(t, err)
+
+	hash, err := hasher.Hash("correct horse")
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(hash, "$argon2id$v=19$m=64,t=1,p=1$"))
+
+	ok, err := hasher.Verify("correct horse", hash)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	ok, err = hasher.Verify("wrong horse", hash)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	other, err := hasher.Hash("correct horse")
+	assert.NoError(t, err)
+	assert.NotEqual(t, hash, other, "each hash has its own salt")
+
+	assert.False(t, hasher.NeedsRehash(hash))
+	stronger, err := NewArgon2idHasher(Argon2idParams{Memory: 128, Iterations: 2, Parallelism: 1, SaltLength: 16, KeyLength: 32})
+	assert.NoError(t, err)
+	assert.True(t, stronger.NeedsRehash(hash))
+	ok, err = stronger.Verify("correct horse", hash)
+	assert.NoError(t, err)
+	assert.True(t, ok, "hashes of older parameters still verify")
+}
+
+func TestVerify(t *testing.T) {
+	t.Run("Reads The Parameters Of The Hash", func(t *testing.T) {
+		params, salt, key, err := decodeArgon2id(encodeArgon2id(testParams, []byte("somesalt"), []byte("0123456789abcdef")))
+		assert.NoError(t, err)
+		assert.Equal(t, Argon2idParams{Memory: 64, Iterations: 1, Parallelism: 1, SaltLength: 8, KeyLength: 16}, params)
+		assert.Equal(t, []byte("somesalt"), salt)
+		assert.Equal(t, []byte("0123456789abcdef"), key)
+	})
+
+	t.Run("Bcrypt", func(t *testing.T) {
+		legacy, err := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+		assert.NoError(t, err)
+
+		ok, err := Verify("password123", string(legacy))
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		ok, err = Verify("password124", string(legacy))
+		assert.NoError(t, err)
+		assert.False(t, ok)
+
+		hasher, err := NewArgon2idHasher(testParams)
+		assert.NoError(t, err)
+		assert.True(t, hasher.NeedsRehash(string(legacy)))
+	})
+
+	t.Run("Malformed Hashes", func(t *testing.T) {
+		_, err := Verify("password", "plain text")
+		assert.True(t, errors.Is(err, ErrUnknownHash))
+		_, err = Verify("password", "$argon2id$v=19$m=64,t=1$c29tZXNhbHQ$aGFzaA")
+		assert.True(t, errors.Is(err, ErrMalformedHash))
+		_, err = Verify("password", "$2a$10$short")
+		assert.True(t, errors.Is(err, ErrMalformedHash))
+	})
+}
+
+func TestBcryptHasher(t *testing.T) {
+	hasher, err := NewBcryptHasher(bcrypt.MinCost)
+	assert.NoError(t, err)
+
+	hash, err := hasher.Hash("correct horse")
+	assert.NoError(t, err)
+	ok, err := hasher.Verify("correct horse", hash)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.False(t, hasher.NeedsRehash(hash))
+
+	costlier, err := NewBcryptHasher(bcrypt.MinCost + 1)
+	assert.NoError(t, err)
+	assert.True(t, costlier.NeedsRehash(hash))
+}
+
+func TestNew(t *testing.T) {
+	hasher, err := New(&Config{Algorithm: "argon2id", Argon2id: testParams})
+	assert.NoError(t, err)
+	assert.IsType(t, &Argon2idHasher{}, hasher)
+
+	hasher, err = New(&Config{Algorithm: "bcrypt", BcryptCost: bcrypt.MinCost})
+	assert.NoError(t, err)
+	assert.IsType(t, &BcryptHasher{}, hasher)
+
+	_, err = New(&Config{Algorithm: "md5"})
+	assert.Error(t, err)
+	_, err = New(&Config{Algorithm: "argon2id"})
+	assert.Error(t, err)
+}
+
+func TestPolicy(t *testing.T) {
+	tests := []struct {
+		name     string
+		password string
+		reason   string
+	}{
+		{"Strong", "correct horse battery", ""},
+		{"Too Short", "k9#Lm2", "it must have at least 8 characters"},
+		{"Too Long", strings.Repeat("k9#Lm2", 30), "it must have at most 128 characters"},
+		{"Common", "Password123", "it is too common"},
+		{"Contains The Email", "xx-ana.souza@example.com", "it must not contain your email or name"},
+		{"Contains The Local Part", "ANA.SOUZA!2026", "it must not contain your email or name"},
+		{"Contains A Name", "souzarocks!", "it must not contain your email or name"},
+		{"Short Names Are Fine", "bananas-are-yellow", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := DefaultPolicy.Validate(tt.password, "ana.souza@example.com", "Ana Souza")
+			if tt.reason == "" {
+				assert.NoError(t, err)
+				return
+			}
+			var weak *WeakError
+			assert.True(t, errors.As(err, &weak))
+			assert.Equal(t, tt.reason, weak.Reason)
+			assert.True(t, errors.Is(err, ErrWeak))
+		})
+	}
+
+	t.Run("Zero Policy Only Refuses Nothing", func(t *testing.T) {
+		assert.NoError(t, Policy{}.Validate("123456", "ana@example.com", "Ana"))
+	})
+}
@@ -0,0 +1,116 @@
+package password
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// ErrWeak is matched by every *WeakError
+var ErrWeak = errors.New("weak password")
+
+// WeakError tells why Policy refused a password; it matches ErrWeak
+type WeakError struct {
+	Reason string
+}
+
+func (e *WeakError) Error() string {
+	return "weak password: " + e.Reason
+}
+
+func (e *WeakError) Unwrap() error {
+	return ErrWeak
+}
+
+// Policy holds the strength rules of new passwords
+type Policy struct {
+	// MinLength and MaxLength bound the length in characters; a zero
+	// MaxLength sets no bound
+	MinLength int
+	MaxLength int
+	// RejectCommon refuses the passwords of the embedded list of the most
+	// used ones, ignoring case
+	RejectCommon bool
+	// RejectPersonal refuses passwords containing the email of the user, the
+	// part of it before the @, or their name
+	RejectPersonal bool
+}
+
+// DefaultPolicy asks for 8 characters, at most 128 so hashing stays cheap,
+// and refuses common and personal passwords
+var DefaultPolicy = Policy{
+	MinLength:      8,
+	MaxLength:      128,
+	RejectCommon:   true,
+	RejectPersonal: true,
+}
+
+// personalMinLength is the shortest part of a name or email looked for in a
+// password; shorter ones, such as "Li" or "Ana", appear in too many words
+const personalMinLength = 4
+
+//go:embed common_passwords.txt
+var commonPasswordsFile string
+
+var commonPasswords = sync.OnceValue(func() map[string]struct{} {
+	set := make(map[string]struct{})
+	for _, line := range strings.Split(commonPasswordsFile, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			set[strings.ToLower(line)] = struct{}{}
+		}
+	}
+	return set
+})
+
+// Validate returns a *WeakError when password breaks the policy for the
+// user of email and name
+func (p Policy) Validate(password, email, name string) error {
+	length := utf8.RuneCountInString(password)
+	if length < p.MinLength {
+		return &WeakError{Reason: fmt.Sprintf("it must have at least %d characters", p.MinLength)}
+	}
+	if p.MaxLength > 0 && length > p.MaxLength {
+		return &WeakError{Reason: fmt.Sprintf("it must have at most %d characters", p.MaxLength)}
+	}
+
+	lower := strings.ToLower(password)
+	if p.RejectCommon {
+		if _, common := commonPasswords()[lower]; common {
+			return &WeakError{Reason: "it is too common"}
+		}
+	}
+	if p.RejectPersonal {
+		for _, part := range personalParts(email, name) {
+			if strings.Contains(lower, part) {
+				return &WeakError{Reason: "it must not contain your email or name"}
+			}
+		}
+	}
+	return nil
+}
+
+// --- Helper Functions ---
+
+// personalParts returns, in lower case, the email, its local part, the name
+// without spaces and each word of the name, skipping the short ones
+func personalParts(email, name string) []string {
+	email = strings.ToLower(strings.TrimSpace(email))
+	name = strings.ToLower(strings.TrimSpace(name))
+
+	candidates := []string{email, strings.Join(strings.Fields(name), "")}
+	if local, _, found := strings.Cut(email, "@"); found {
+		candidates = append(candidates, local)
+	}
+	candidates = append(candidates, strings.Fields(name)...)
+
+	var parts []string
+	for _, candidate := range candidates {
+		if utf8.RuneCountInString(candidate) >= personalMinLength {
+			parts = append(parts, candidate)
+		}
+	}
+	return parts
+}
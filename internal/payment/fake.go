@@ -0,0 +1,242 @@
+package payment
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Outcome is the scripted result of the next call to the fake gateway
+type Outcome string
+
+const (
+	OutcomeSucceed Outcome = "succeed"
+	OutcomeDecline Outcome = "decline"
+	OutcomeTimeout Outcome = "timeout"
+)
+
+// SignatureTolerance is how old a webhook signature may be, to limit replays
+const SignatureTolerance = 5 * time.Minute
+
+// FakeGateway is an in-memory provider. Every call succeeds unless Script
+// queued another outcome for it; webhooks are signed with HMAC-SHA256 the
+// way a real provider would sign them
+type FakeGateway struct {
+	secret []byte
+	now    func() time.Time
+
+	mu         sync.Mutex
+	script     []Outcome
+	intents    map[string]*Intent
+	idempotent map[string]string
+}
+
+// Ensure FakeGateway implements PaymentGateway
+var _ PaymentGateway = (*FakeGateway)(nil)
+
+// NewFakeGateway creates a fake gateway whose webhooks are signed with secret
+func NewFakeGateway(secret []byte) *FakeGateway {
+	return &FakeGateway{
+		secret:     secret,
+		now:        time.Now,
+		intents:    make(map[string]*Intent),
+		idempotent: make(map[string]string),
+	}
+}
+
+func (g *FakeGateway) Name() string {
+	return "fake"
+}
+
+// Script queues the outcomes of the next calls to CreateIntent, Capture and
+// Refund, in order; once they run out, calls succeed again
+func (g *FakeGateway) Script(outcomes ...Outcome) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.script = append(g.script, outcomes...)
+}
+
+// CreateIntent creates an intent awaiting capture; a declined intent is kept
+// as failed and returned with ErrDeclined
+func (g *FakeGateway) CreateIntent(ctx context.Context, request IntentRequest) (*Intent, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if id, ok := g.idempotent[request.IdempotencyKey]; ok && request.IdempotencyKey != "" {
+		intent := *g.intents[id]
+		return &intent, nil
+	}
+	outcome, err := g.next(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := randomID("pi_")
+	if err != nil {
+		return nil, err
+	}
+	intent := &Intent{
+		ID:       id,
+		OrderID:  request.OrderID,
+		Amount:   request.Amount,
+		Currency: request.Currency,
+		Status:   IntentRequiresCapture,
+	}
+	if outcome == OutcomeDecline {
+		intent.Status = IntentFailed
+	}
+	g.intents[id] = intent
+	if request.IdempotencyKey != "" {
+		g.idempotent[request.IdempotencyKey] = id
+	}
+
+	result := *intent
+	if outcome == OutcomeDecline {
+		return &result, ErrDeclined
+	}
+	return &result, nil
+}
+
+// Capture charges an intent awaiting capture; capturing a succeeded intent
+// again returns it unchanged
+func (g *FakeGateway) Capture(ctx context.Context, intentID string) (*Intent, error) {
+	return g.move(ctx, intentID, IntentRequiresCapture, IntentSucceeded)
+}
+
+// Refund gives back the amount of a succeeded intent; refunding a refunded
+// intent again returns it unchanged
+func (g *FakeGateway) Refund(ctx context.Context, intentID string) (*Intent, error) {
+	return g.move(ctx, intentID, IntentSucceeded, IntentRefunded)
+}
+
+// VerifyWebhook checks a signature made by Sign and decodes the event
+func (g *FakeGateway) VerifyWebhook(payload []byte, signature string) (*Event, error) {
+	var timestamp, mac string
+	for _, part := range strings.Split(signature, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			mac = value
+		}
+	}
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || mac == "" {
+		return nil, ErrInvalidSignature
+	}
+	expected := g.mac(timestamp, payload)
+	if !hmac.Equal([]byte(mac), []byte(expected)) {
+		return nil, ErrInvalidSignature
+	}
+	if age := g.now().Sub(time.Unix(seconds, 0)); age > SignatureTolerance || age < -SignatureTolerance {
+		return nil, fmt.Errorf("%w: signature expired", ErrInvalidSignature)
+	}
+
+	var event Event
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, fmt.Errorf("payment: invalid webhook body: %w", err)
+	}
+	if event.ID == "" || event.Type == "" || event.IntentID == "" {
+		return nil, fmt.Errorf("payment: webhook body without id, type or intent_id")
+	}
+	return &event, nil
+}
+
+// Webhook builds the signed body the provider would send about the intent,
+// under a new event ID
+func (g *FakeGateway) Webhook(eventType, intentID string) ([]byte, string, error) {
+	id, err := randomID("evt_")
+	if err != nil {
+		return nil, "", err
+	}
+	payload, err := json.Marshal(Event{
+		ID:         id,
+		Type:       eventType,
+		IntentID:   intentID,
+		OccurredAt: g.now().UTC(),
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return payload, g.Sign(payload), nil
+}
+
+// Sign returns the signature header for the body, "t=<unix time>,v1=<hex HMAC>"
+func (g *FakeGateway) Sign(payload []byte) string {
+	timestamp := strconv.FormatInt(g.now().Unix(), 10)
+	return "t=" + timestamp + ",v1=" + g.mac(timestamp, payload)
+}
+
+// --- Helper Functions ---
+
+// move changes the status of the intent when it is in from; an intent already
+// in to is returned as is, so retried calls are harmless
+func (g *FakeGateway) move(ctx context.Context, intentID, from, to string) (*Intent, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	intent, ok := g.intents[intentID]
+	if !ok {
+		return nil, ErrIntentNotFound
+	}
+	if intent.Status == to {
+		result := *intent
+		return &result, nil
+	}
+	if intent.Status != from {
+		return nil, fmt.Errorf("%w: %s is %s", ErrInvalidState, intentID, intent.Status)
+	}
+	outcome, err := g.next(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if outcome == OutcomeDecline {
+		return nil, ErrDeclined
+	}
+
+	intent.Status = to
+	result := *intent
+	return &result, nil
+}
+
+// next takes the scripted outcome of the call; a timeout fails at once, as if
+// the provider had not answered in time
+func (g *FakeGateway) next(ctx context.Context) (Outcome, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	if len(g.script) == 0 {
+		return OutcomeSucceed, nil
+	}
+	outcome := g.script[0]
+	g.script = g.script[1:]
+	if outcome == OutcomeTimeout {
+		return "", ErrTimeout
+	}
+	return outcome, nil
+}
+
+func (g *FakeGateway) mac(timestamp string, payload []byte) string {
+	h := hmac.New(sha256.New, g.secret)
+	h.Write([]byte(timestamp))
+	h.Write([]byte("."))
+	h.Write(payload)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func randomID(prefix string) (string, error) {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return prefix + hex.EncodeToString(b), nil
+}
@@ -0,0 +1,91 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFakeGateway(t *testing.T) {
+	ctx := context.Background()
+	request := IntentRequest{OrderID: 10, Amount: 9980, Currency: "BRL", IdempotencyKey: "order-10-1"}
+
+	t.Run("CaptureAndRefund", func(t *testing.T) {
+		gateway := NewFakeGateway([]byte("secret"))
+
+		intent, err := gateway.CreateIntent(ctx, request)
+		require.NoError(t, err)
+		assert.Equal(t, IntentRequiresCapture, intent.Status)
+		assert.Equal(t, int64(9980), intent.Amount)
+
+		again, err := gateway.CreateIntent(ctx, request)
+		require.NoError(t, err)
+		assert.Equal(t, intent.ID, again.ID)
+
+		captured, err := gateway.Capture(ctx, intent.ID)
+		require.NoError(t, err)
+		assert.Equal(t, IntentSucceeded, captured.Status)
+
+		refunded, err := gateway.Refund(ctx, intent.ID)
+		require.NoError(t, err)
+		assert.Equal(t, IntentRefunded, refunded.Status)
+
+		_, err = gateway.Capture(ctx, intent.ID)
+		assert.True(t, errors.Is(err, ErrInvalidState))
+		_, err = gateway.Refund(ctx, "pi_unknown")
+		assert.Equal(t, ErrIntentNotFound, err)
+	})
+
+	t.Run("ScriptedOutcomes", func(t *testing.T) {
+		gateway := NewFakeGateway([]byte("secret"))
+		gateway.Script(OutcomeTimeout, OutcomeDecline)
+
+		_, err := gateway.CreateIntent(ctx, request)
+		assert.Equal(t, ErrTimeout, err)
+
+		declined, err := gateway.CreateIntent(ctx, request)
+		assert.Equal(t, ErrDeclined, err)
+		assert.Equal(t, IntentFailed, declined.Status)
+
+		intent, err := gateway.CreateIntent(ctx, IntentRequest{OrderID: 10, Amount: 9980, Currency: "BRL", IdempotencyKey: "order-10-2"})
+		require.NoError(t, err)
+		assert.NotEqual(t, declined.ID, intent.ID)
+
+		gateway.Script(OutcomeDecline)
+		_, err = gateway.Capture(ctx, intent.ID)
+		assert.Equal(t, ErrDeclined, err)
+		captured, err := gateway.Capture(ctx, intent.ID)
+		require.NoError(t, err)
+		assert.Equal(t, IntentSucceeded, captured.Status)
+	})
+
+	t.Run("Webhooks", func(t *testing.T) {
+		gateway := NewFakeGateway([]byte("secret"))
+
+		payload, signature, err := gateway.Webhook(EventPaymentSucceeded, "pi_1")
+		require.NoError(t, err)
+		event, err := gateway.VerifyWebhook(payload, signature)
+		require.NoError(t, err)
+		assert.Equal(t, EventPaymentSucceeded, event.Type)
+		assert.Equal(t, "pi_1", event.IntentID)
+
+		tampered := append([]byte{}, payload...)
+		tampered[len(tampered)-2] = 'x'
+		_, err = gateway.VerifyWebhook(tampered, signature)
+		assert.True(t, errors.Is(err, ErrInvalidSignature))
+
+		_, err = NewFakeGateway([]byte("other")).VerifyWebhook(payload, signature)
+		assert.True(t, errors.Is(err, ErrInvalidSignature))
+
+		_, err = gateway.VerifyWebhook(payload, "")
+		assert.True(t, errors.Is(err, ErrInvalidSignature))
+
+		gateway.now = func() time.Time { return time.Now().Add(SignatureTolerance + time.Minute) }
+		_, err = gateway.VerifyWebhook(payload, signature)
+		assert.True(t, errors.Is(err, ErrInvalidSignature))
+	})
+}
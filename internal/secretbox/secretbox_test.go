@@ -0,0 +1,34 @@
+package secretbox
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBox(t *testing.T) {
+	box, err := New("passphrase")
+	require.NoError(t, err)
+
+	sealed, err := box.Seal([]byte("totp key"))
+	require.NoError(t, err)
+	again, err := box.Seal([]byte("totp key"))
+	require.NoError(t, err)
+	assert.NotEqual(t, sealed, again)
+
+	opened, err := box.Open(sealed)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("totp key"), opened)
+
+	other, err := New("other passphrase")
+	require.NoError(t, err)
+	_, err = other.Open(sealed)
+	assert.Equal(t, ErrDecrypt, err)
+
+	_, err = box.Open("not base64!")
+	assert.Equal(t, ErrDecrypt, err)
+
+	_, err = New("")
+	assert.Error(t, err)
+}
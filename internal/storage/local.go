@@ -0,0 +1,166 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LocalStore keeps objects as files under a root directory. It also serves
+// them over HTTP, checking the URL signature when a signing key is set
+type LocalStore struct {
+	root       string
+	baseURL    string
+	signingKey []byte
+	ttl        time.Duration
+	now        func() time.Time
+}
+
+var _ BlobStore = (*LocalStore)(nil)
+
+// NewLocalStore creates the root directory if needed
+func NewLocalStore(root, baseURL string, signingKey []byte, ttl time.Duration) (*LocalStore, error) {
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, err
+	}
+	return &LocalStore{
+		root:       root,
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		signingKey: signingKey,
+		ttl:        ttl,
+		now:        time.Now,
+	}, nil
+}
+
+func (ls *LocalStore) path(key string) (string, error) {
+	if err := validateKey(key); err != nil {
+		return "", err
+	}
+	return filepath.Join(ls.root, filepath.FromSlash(key)), nil
+}
+
+// Put writes the object to a temporary file first so readers never see a partial file
+func (ls *LocalStore) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	path, err := ls.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, body); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func (ls *LocalStore) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, err := ls.path(key)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	return file, err
+}
+
+func (ls *LocalStore) Delete(ctx context.Context, key string) error {
+	path, err := ls.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// URL returns the public URL of the object, signed with an expiry when the store has a signing key
+func (ls *LocalStore) URL(key string) (string, error) {
+	if err := validateKey(key); err != nil {
+		return "", err
+	}
+	u := ls.baseURL + "/" + escapeKey(key)
+	if len(ls.signingKey) == 0 {
+		return u, nil
+	}
+
+	expires := strconv.FormatInt(ls.now().Add(ls.ttl).Unix(), 10)
+	query := url.Values{}
+	query.Set("expires", expires)
+	query.Set("signature", ls.sign(key, expires))
+	return u + "?" + query.Encode(), nil
+}
+
+func (ls *LocalStore) sign(key, expires string) string {
+	mac := hmac.New(sha256.New, ls.signingKey)
+	mac.Write([]byte(key + "\n" + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// ServeHTTP serves the object named by the request path, which must be
+// relative to the store base URL (use http.StripPrefix)
+func (ls *LocalStore) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	key := strings.TrimPrefix(r.URL.Path, "/")
+	if len(ls.signingKey) > 0 {
+		expires := r.URL.Query().Get("expires")
+		signature := r.URL.Query().Get("signature")
+		unix, err := strconv.ParseInt(expires, 10, 64)
+		if err != nil || ls.now().Unix() > unix || !hmac.Equal([]byte(signature), []byte(ls.sign(key, expires))) {
+			http.Error(w, "invalid or expired signature", http.StatusForbidden)
+			return
+		}
+	}
+
+	path, err := ls.path(key)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil || info.IsDir() {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Cache-Control", "private, max-age=300")
+	http.ServeContent(w, r, info.Name(), info.ModTime(), file)
+}
+
+// escapeKey escapes each segment of the key for use in a URL path
+func escapeKey(key string) string {
+	parts := strings.Split(key, "/")
+	for i, part := range parts {
+		parts[i] = url.PathEscape(part)
+	}
+	return strings.Join(parts, "/")
+}
@@ -0,0 +1,78 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalStore(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("PutOpenDelete", func(t *testing.T) {
+		store, err := NewLocalStore(t.TempDir(), "http://localhost:8000/media", nil, time.Hour)
+		require.NoError(t, err)
+
+		require.NoError(t, store.Put(ctx, "products/1/original.png", strings.NewReader("png"), 3, "image/png"))
+		reader, err := store.Open(ctx, "products/1/original.png")
+		require.NoError(t, err)
+		body, _ := io.ReadAll(reader)
+		reader.Close()
+		assert.Equal(t, "png", string(body))
+
+		u, err := store.URL("products/1/original.png")
+		require.NoError(t, err)
+		assert.Equal(t, "http://localhost:8000/media/products/1/original.png", u)
+
+		require.NoError(t, store.Delete(ctx, "products/1/original.png"))
+		_, err = store.Open(ctx, "products/1/original.png")
+		assert.True(t, errors.Is(err, ErrNotFound))
+		assert.NoError(t, store.Delete(ctx, "products/1/original.png"))
+	})
+
+	t.Run("InvalidKey", func(t *testing.T) {
+		store, err := NewLocalStore(t.TempDir(), "http://localhost:8000/media", nil, time.Hour)
+		require.NoError(t, err)
+		for _, key := range []string{"", "/abs", "a/../b", "../x", `a\b`} {
+			assert.Equal(t, ErrInvalidKey, store.Put(ctx, key, strings.NewReader("x"), 1, ""), key)
+		}
+	})
+
+	t.Run("SignedURL", func(t *testing.T) {
+		store, err := NewLocalStore(t.TempDir(), "http://localhost:8000/media", []byte("key"), time.Minute)
+		require.NoError(t, err)
+		now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+		store.now = func() time.Time { return now }
+		require.NoError(t, store.Put(ctx, "products/1/small.jpg", strings.NewReader("jpg"), 3, "image/jpeg"))
+
+		signed, err := store.URL("products/1/small.jpg")
+		require.NoError(t, err)
+		u, _ := url.Parse(signed)
+		handler := http.StripPrefix("/media", store)
+
+		serve := func(target string) *httptest.ResponseRecorder {
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+			return w
+		}
+
+		w := serve(u.RequestURI())
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "jpg", w.Body.String())
+
+		assert.Equal(t, http.StatusForbidden, serve("/media/products/1/small.jpg").Code)
+		assert.Equal(t, http.StatusForbidden, serve(strings.Replace(u.RequestURI(), "small", "large", 1)).Code)
+
+		now = now.Add(2 * time.Minute)
+		assert.Equal(t, http.StatusForbidden, serve(u.RequestURI()).Code)
+	})
+}
@@ -0,0 +1,302 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	sigV4Algorithm   = "AWS4-HMAC-SHA256"
+	sigV4TimeFormat  = "20060102T150405Z"
+	sigV4DateFormat  = "20060102"
+	unsignedPayload  = "UNSIGNED-PAYLOAD"
+	maxPresignExpiry = 7 * 24 * time.Hour
+)
+
+// S3Options configures an S3Store
+type S3Options struct {
+	// Endpoint is the base URL of the S3-compatible service, e.g. https://s3.us-east-1.amazonaws.com
+	// or http://localhost:9000 for MinIO. The bucket is addressed path-style
+	Endpoint  string
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	// PublicURL serves objects of a public bucket; empty keeps the bucket private and URLs presigned
+	PublicURL string
+	URLTTL    time.Duration
+	// Client defaults to http.DefaultClient
+	Client *http.Client
+}
+
+// S3Store keeps objects in a bucket of an S3-compatible service, signing
+// every request with AWS Signature Version 4
+type S3Store struct {
+	endpoint *url.URL
+	options  S3Options
+	now      func() time.Time
+}
+
+var _ BlobStore = (*S3Store)(nil)
+
+func NewS3Store(options S3Options) (*S3Store, error) {
+	if options.Endpoint == "" || options.Bucket == "" {
+		return nil, errors.New("storage: s3 endpoint and bucket are required")
+	}
+	endpoint, err := url.Parse(strings.TrimRight(options.Endpoint, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("storage: invalid s3 endpoint: %w", err)
+	}
+	if options.Region == "" {
+		options.Region = "us-east-1"
+	}
+	if options.URLTTL <= 0 || options.URLTTL > maxPresignExpiry {
+		options.URLTTL = time.Hour
+	}
+	if options.Client == nil {
+		options.Client = http.DefaultClient
+	}
+	return &S3Store{endpoint: endpoint, options: options, now: time.Now}, nil
+}
+
+func (s *S3Store) objectURL(key string) *url.URL {
+	u := *s.endpoint
+	u.Path = s.endpoint.Path + "/" + s.options.Bucket + "/" + key
+	u.RawPath = encodePath(u.Path)
+	return &u
+}
+
+// Put uploads the object; the body is buffered to compute the payload hash
+func (s *S3Store) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	if err := validateKey(key); err != nil {
+		return err
+	}
+	payload, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, s.objectURL(key).String(), bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.ContentLength = int64(len(payload))
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	resp, err := s.do(req, hashHex(payload))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (s *S3Store) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	if err := validateKey(key); err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.objectURL(key).String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.do(req, hashHex(nil))
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+func (s *S3Store) Delete(ctx context.Context, key string) error {
+	if err := validateKey(key); err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, s.objectURL(key).String(), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := s.do(req, hashHex(nil))
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// URL returns the public URL of the object, or a presigned GET URL when the bucket is private
+func (s *S3Store) URL(key string) (string, error) {
+	if err := validateKey(key); err != nil {
+		return "", err
+	}
+	if s.options.PublicURL != "" {
+		return strings.TrimRight(s.options.PublicURL, "/") + "/" + escapeKey(key), nil
+	}
+	return s.presign(http.MethodGet, s.objectURL(key), s.options.URLTTL), nil
+}
+
+// do signs the request with an Authorization header, sends it and maps error statuses
+func (s *S3Store) do(req *http.Request, payloadHash string) (*http.Response, error) {
+	now := s.now().UTC()
+	req.Header.Set("Host", req.URL.Host)
+	req.Header.Set("X-Amz-Date", now.Format(sigV4TimeFormat))
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	signed := []string{"host", "x-amz-content-sha256", "x-amz-date"}
+	if req.Header.Get("Content-Type") != "" {
+		signed = []string{"content-type", "host", "x-amz-content-sha256", "x-amz-date"}
+	}
+	headers := make(map[string]string, len(signed))
+	for _, name := range signed {
+		headers[name] = req.Header.Get(name)
+	}
+	req.Header.Del("Host")
+
+	scope := s.scope(now)
+	canonical := canonicalRequest(req.Method, req.URL, headers, payloadHash)
+	signature := s.signature(now, stringToSign(now, scope, canonical))
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		sigV4Algorithm, s.options.AccessKey, scope, strings.Join(signed, ";"), signature))
+
+	resp, err := s.options.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return nil, ErrNotFound
+	}
+	if resp.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		resp.Body.Close()
+		return nil, fmt.Errorf("storage: s3 %s %s: %s: %s", req.Method, req.URL.Path, resp.Status, strings.TrimSpace(string(message)))
+	}
+	return resp, nil
+}
+
+// presign builds a URL carrying its signature in the query string
+func (s *S3Store) presign(method string, target *url.URL, ttl time.Duration) string {
+	now := s.now().UTC()
+	scope := s.scope(now)
+
+	query := url.Values{}
+	query.Set("X-Amz-Algorithm", sigV4Algorithm)
+	query.Set("X-Amz-Credential", s.options.AccessKey+"/"+scope)
+	query.Set("X-Amz-Date", now.Format(sigV4TimeFormat))
+	query.Set("X-Amz-Expires", strconv.Itoa(int(ttl.Seconds())))
+	query.Set("X-Amz-SignedHeaders", "host")
+
+	u := *target
+	u.RawQuery = canonicalQuery(query)
+	canonical := canonicalRequest(method, &u, map[string]string{"host": u.Host}, unsignedPayload)
+	query.Set("X-Amz-Signature", s.signature(now, stringToSign(now, scope, canonical)))
+	u.RawQuery = canonicalQuery(query)
+	return u.String()
+}
+
+func (s *S3Store) scope(now time.Time) string {
+	return now.Format(sigV4DateFormat) + "/" + s.options.Region + "/s3/aws4_request"
+}
+
+func (s *S3Store) signature(now time.Time, toSign string) string {
+	key := hmacSHA256([]byte("AWS4"+s.options.SecretKey), now.Format(sigV4DateFormat))
+	key = hmacSHA256(key, s.options.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	return hex.EncodeToString(hmacSHA256(key, toSign))
+}
+
+// --- Signature Version 4 helpers ---
+
+func canonicalRequest(method string, u *url.URL, headers map[string]string, payloadHash string) string {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + strings.TrimSpace(headers[name]) + "\n")
+	}
+
+	query, _ := url.ParseQuery(u.RawQuery)
+	return strings.Join([]string{
+		method,
+		encodePath(u.Path),
+		canonicalQuery(query),
+		canonicalHeaders.String(),
+		strings.Join(names, ";"),
+		payloadHash,
+	}, "\n")
+}
+
+// canonicalQuery sorts the parameters and escapes them the way SigV4 expects
+func canonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var parts []string
+	for _, key := range keys {
+		values := append([]string(nil), query[key]...)
+		sort.Strings(values)
+		for _, value := range values {
+			parts = append(parts, uriEncode(key)+"="+uriEncode(value))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+func encodePath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		segments[i] = uriEncode(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// uriEncode escapes everything except the unreserved characters of RFC 3986
+func uriEncode(value string) string {
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		if ('A' <= c && c <= 'Z') || ('a' <= c && c <= 'z') || ('0' <= c && c <= '9') || c == '-' || c == '_' || c == '.' || c == '~' {
+			b.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+	return b.String()
+}
+
+func stringToSign(now time.Time, scope, canonical string) string {
+	return strings.Join([]string{sigV4Algorithm, now.Format(sigV4TimeFormat), scope, hashHex([]byte(canonical))}, "\n")
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func hashHex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,113 @@
+// Package storage keeps binary objects (such as product images) behind the
+// BlobStore interface, with a local filesystem and an S3-compatible backend.
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrNotFound   = errors.New("storage: object not found")
+	ErrInvalidKey = errors.New("storage: invalid object key")
+)
+
+// BlobStore stores objects under slash separated keys
+type BlobStore interface {
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+	// URL returns the address clients use to download the object; for private
+	// stores it is a signed URL that expires
+	URL(key string) (string, error)
+}
+
+// Config selects and configures the BlobStore
+type Config struct {
+	// Driver is "local" or "s3"
+	Driver string
+
+	LocalDir string
+	// BaseURL is where the local files are served, e.g. http://localhost:8000/media
+	BaseURL string
+	// SigningKey makes local URLs signed; empty keeps them public
+	SigningKey string
+
+	S3Endpoint  string
+	S3Region    string
+	S3Bucket    string
+	S3AccessKey string
+	S3SecretKey string
+	// S3PublicURL is used for URLs when the bucket is public; empty means private with signed URLs
+	S3PublicURL string
+
+	// URLTTL is how long signed URLs stay valid
+	URLTTL time.Duration
+}
+
+// NewConfig reads the storage configuration from environment variables
+func NewConfig() *Config {
+	ttl, err := strconv.Atoi(getEnv("STORAGE_URL_TTL_SECONDS", "3600"))
+	if err != nil {
+		ttl = 3600
+	}
+	return &Config{
+		Driver:      getEnv("STORAGE_DRIVER", "local"),
+		LocalDir:    getEnv("STORAGE_LOCAL_DIR", "./uploads"),
+		BaseURL:     getEnv("STORAGE_BASE_URL", "http://localhost:8000/media"),
+		SigningKey:  getEnv("STORAGE_SIGNING_KEY", ""),
+		S3Endpoint:  getEnv("S3_ENDPOINT", ""),
+		S3Region:    getEnv("S3_REGION", "us-east-1"),
+		S3Bucket:    getEnv("S3_BUCKET", ""),
+		S3AccessKey: getEnv("S3_ACCESS_KEY", ""),
+		S3SecretKey: getEnv("S3_SECRET_KEY", ""),
+		S3PublicURL: getEnv("S3_PUBLIC_URL", ""),
+		URLTTL:      time.Duration(ttl) * time.Second,
+	}
+}
+
+// New creates the BlobStore selected by the configuration
+func New(config *Config) (BlobStore, error) {
+	switch config.Driver {
+	case "local":
+		return NewLocalStore(config.LocalDir, config.BaseURL, []byte(config.SigningKey), config.URLTTL)
+	case "s3":
+		return NewS3Store(S3Options{
+			Endpoint:  config.S3Endpoint,
+			Region:    config.S3Region,
+			Bucket:    config.S3Bucket,
+			AccessKey: config.S3AccessKey,
+			SecretKey: config.S3SecretKey,
+			PublicURL: config.S3PublicURL,
+			URLTTL:    config.URLTTL,
+		})
+	default:
+		return nil, fmt.Errorf("storage: unknown driver %q", config.Driver)
+	}
+}
+
+// validateKey rejects keys that could escape the store root
+func validateKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return ErrInvalidKey
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return ErrInvalidKey
+		}
+	}
+	return nil
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
@@ -0,0 +1,460 @@
+// Package tax computes the taxes of carts and orders from rate tables by
+// country and state, versioned with effective dates. Tables are plain JSON
+// files, so the same rates run in production and in offline tests.
+package tax
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-api/model"
+	"io"
+	"math/big"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidTable    = errors.New("tax: invalid rate table")
+	ErrRegionNotFound  = errors.New("tax: no rates for the region")
+	ErrUnknownCategory = errors.New("tax: unknown tax category")
+)
+
+// DefaultCategory is the tax category of products that were not given one;
+// every table must declare it
+const DefaultCategory = "standard"
+
+// Rounding defines when the taxes are rounded to the minor unit of the currency
+type Rounding string
+
+const (
+	// RoundPerLine rounds the tax of every line; the total is the sum of the lines
+	RoundPerLine Rounding = "line"
+	// RoundPerTotal rounds each tax once over the whole cart or order. The
+	// difference to the rounded lines goes to the line paying the most of
+	// the tax, so the lines still add up to the total
+	RoundPerTotal Rounding = "total"
+)
+
+// rounding is how the exact amounts are brought to the minor unit
+const rounding = model.RoundHalfUp
+
+var (
+	countryPattern  = regexp.MustCompile(`^[A-Z]{2}$`)
+	statePattern    = regexp.MustCompile(`^[A-Z0-9]{1,8}$`)
+	categoryPattern = regexp.MustCompile(`^[a-z0-9_-]{1,32}$`)
+)
+
+// Table is a set of rate tables, one per country
+type Table struct {
+	// Version identifies the table, e.g. the date it was published
+	Version    string    `json:"version"`
+	Categories []string  `json:"categories"`
+	Countries  []Country `json:"countries"`
+}
+
+// Country holds the rates of a country and of its states
+type Country struct {
+	// Code is the ISO 3166-1 alpha-2 code
+	Code string `json:"code"`
+	// PricesIncludeTax is true where prices already include the taxes (VAT
+	// style); otherwise the taxes are added on top of the prices
+	PricesIncludeTax bool `json:"prices_include_tax"`
+	// Rounding defaults to RoundPerLine
+	Rounding Rounding `json:"rounding"`
+	// Rates apply in the whole country; the rates of a state add to them
+	Rates  []Rate            `json:"rates"`
+	States map[string][]Rate `json:"states,omitempty"`
+}
+
+// Rate is one version of a tax for a category. Versions of the same tax and
+// category may not overlap
+type Rate struct {
+	Name     string `json:"name"`
+	Category string `json:"category"`
+	// Percent is a decimal between 0 and 100, e.g. "18" or "8.875"
+	Percent       string     `json:"percent"`
+	EffectiveFrom time.Time  `json:"effective_from"`
+	EffectiveTo   *time.Time `json:"effective_to,omitempty"`
+
+	fraction *big.Rat
+}
+
+// Line is a line of a cart or order to tax
+type Line struct {
+	// Category defaults to DefaultCategory
+	Category string
+	// Amount is what the line costs after its discounts
+	Amount model.Money
+}
+
+// Load reads and validates a JSON rate table
+func Load(reader io.Reader) (*Table, error) {
+	var table Table
+	decoder := json.NewDecoder(reader)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&table); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTable, err)
+	}
+	if err := table.validate(); err != nil {
+		return nil, err
+	}
+	return &table, nil
+}
+
+// LoadFile reads and validates the JSON rate table at path
+func LoadFile(path string) (*Table, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return Load(file)
+}
+
+// HasCategory reports whether the table declares the tax category
+func (t *Table) HasCategory(category string) bool {
+	for _, declared := range t.Categories {
+		if declared == category {
+			return true
+		}
+	}
+	return false
+}
+
+// Region returns the rates in effect at the destination at the given time
+func (t *Table) Region(destination model.TaxDestination, at time.Time) (*model.TaxRegion, error) {
+	country, rates, err := t.ratesAt(destination, at)
+	if err != nil {
+		return nil, err
+	}
+
+	region := &model.TaxRegion{
+		Destination:      normalizeDestination(destination),
+		Version:          t.Version,
+		PricesIncludeTax: country.PricesIncludeTax,
+		Rounding:         string(country.Rounding),
+		Rates:            make([]model.TaxRate, 0, len(rates)),
+	}
+	for _, rate := range rates {
+		region.Rates = append(region.Rates, model.TaxRate{
+			Name:          rate.Name,
+			Category:      rate.Category,
+			Percent:       rate.Percent,
+			EffectiveFrom: rate.EffectiveFrom,
+			EffectiveTo:   rate.EffectiveTo,
+		})
+	}
+	return region, nil
+}
+
+// Calculate computes the taxes of the lines delivered to the destination,
+// with the rates in effect at the given time. Every line must share the
+// same currency. When the country prices include the taxes, they are taken
+// out of the line amounts; otherwise they are charged on top of them
+func (t *Table) Calculate(destination model.TaxDestination, at time.Time, lines []Line) (*model.TaxBreakdown, error) {
+	country, rates, err := t.ratesAt(destination, at)
+	if err != nil {
+		return nil, err
+	}
+	var currency string
+	if len(lines) > 0 {
+		currency = lines[0].Amount.Currency
+	}
+
+	breakdown := &model.TaxBreakdown{
+		Destination: normalizeDestination(destination),
+		Included:    country.PricesIncludeTax,
+		Rounding:    string(country.Rounding),
+		Lines:       make([]model.LineTax, len(lines)),
+		Taxes:       []model.TaxAmount{},
+		Total:       model.Money{Currency: currency},
+	}
+
+	// exact holds, per tax and line, the unrounded amount the line pays
+	var exact [][]*big.Rat
+	for i, line := range lines {
+		if line.Amount.Currency != currency {
+			return nil, model.ErrCurrencyMismatch
+		}
+		category := line.Category
+		if category == "" {
+			category = DefaultCategory
+		}
+		if !t.HasCategory(category) {
+			return nil, fmt.Errorf("%w: %q", ErrUnknownCategory, category)
+		}
+		breakdown.Lines[i] = model.LineTax{
+			Category: category,
+			Net:      line.Amount,
+			Tax:      model.Money{Currency: currency},
+			Taxes:    []model.TaxAmount{},
+		}
+
+		applicable := ratesFor(rates, category)
+		// Included taxes are a share of the amount: rate / (1 + sum of the rates)
+		divisor := big.NewRat(1, 1)
+		if country.PricesIncludeTax {
+			for _, rate := range applicable {
+				divisor.Add(divisor, rate.fraction)
+			}
+		}
+		for _, rate := range applicable {
+			index := taxIndex(breakdown, rate, currency)
+			for len(exact) <= index {
+				exact = append(exact, make([]*big.Rat, len(lines)))
+			}
+			share := new(big.Rat).Quo(rate.fraction, divisor)
+			exact[index][i] = share.Mul(share, line.Amount.Rat())
+		}
+	}
+
+	for index := range breakdown.Taxes {
+		amounts, err := roundTax(exact[index], currency, country.Rounding)
+		if err != nil {
+			return nil, err
+		}
+		for i, amount := range amounts {
+			if amount == nil {
+				continue
+			}
+			line := &breakdown.Lines[i]
+			line.Taxes = append(line.Taxes, model.TaxAmount{
+				Name:    breakdown.Taxes[index].Name,
+				Percent: breakdown.Taxes[index].Percent,
+				Amount:  *amount,
+			})
+			if line.Tax, err = line.Tax.Add(*amount); err != nil {
+				return nil, err
+			}
+			if breakdown.Taxes[index].Amount, err = breakdown.Taxes[index].Amount.Add(*amount); err != nil {
+				return nil, err
+			}
+			if breakdown.Total, err = breakdown.Total.Add(*amount); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if country.PricesIncludeTax {
+		for i := range breakdown.Lines {
+			line := &breakdown.Lines[i]
+			if line.Net, err = line.Net.Sub(line.Tax); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return breakdown, nil
+}
+
+// Config locates the rate table
+type Config struct {
+	// RatesFile is the path of the JSON rate table
+	RatesFile string
+}
+
+// NewConfig reads the tax configuration from environment variables
+func NewConfig() *Config {
+	return &Config{
+		RatesFile: getEnv("TAX_RATES_FILE", "db/tax_rates.json"),
+	}
+}
+
+// --- Helper Functions ---
+
+// ratesAt finds the country of the destination and the rates in effect at
+// the time, the country rates first
+func (t *Table) ratesAt(destination model.TaxDestination, at time.Time) (*Country, []*Rate, error) {
+	destination = normalizeDestination(destination)
+	for i := range t.Countries {
+		country := &t.Countries[i]
+		if country.Code != destination.Country {
+			continue
+		}
+		var rates []*Rate
+		for _, list := range [][]Rate{country.Rates, country.States[destination.State]} {
+			for j := range list {
+				if inEffect(list[j], at) {
+					rates = append(rates, &list[j])
+				}
+			}
+		}
+		return country, rates, nil
+	}
+	return nil, nil, fmt.Errorf("%w: %s", ErrRegionNotFound, destination.Country)
+}
+
+func normalizeDestination(destination model.TaxDestination) model.TaxDestination {
+	return model.TaxDestination{
+		Country: strings.ToUpper(strings.TrimSpace(destination.Country)),
+		State:   strings.ToUpper(strings.TrimSpace(destination.State)),
+	}
+}
+
+func inEffect(rate Rate, at time.Time) bool {
+	return !rate.EffectiveFrom.After(at) && (rate.EffectiveTo == nil || at.Before(*rate.EffectiveTo))
+}
+
+func ratesFor(rates []*Rate, category string) []*Rate {
+	var matching []*Rate
+	for _, rate := range rates {
+		if rate.Category == category {
+			matching = append(matching, rate)
+		}
+	}
+	return matching
+}
+
+// taxIndex returns the position of the tax in the breakdown totals, adding
+// it on first use. Taxes are told apart by name and percent, so the same tax
+// at different rates for different categories is itemized separately
+func taxIndex(breakdown *model.TaxBreakdown, rate *Rate, currency string) int {
+	for i, tax := range breakdown.Taxes {
+		if tax.Name == rate.Name && tax.Percent == rate.Percent {
+			return i
+		}
+	}
+	breakdown.Taxes = append(breakdown.Taxes, model.TaxAmount{
+		Name:    rate.Name,
+		Percent: rate.Percent,
+		Amount:  model.Money{Currency: currency},
+	})
+	return len(breakdown.Taxes) - 1
+}
+
+// roundTax rounds the exact amounts of one tax per line, nil for the lines
+// that do not pay it
+func roundTax(exact []*big.Rat, currency string, mode Rounding) ([]*model.Money, error) {
+	rounded := make([]*model.Money, len(exact))
+	sum := new(big.Rat)
+	lineTotal := model.Money{Currency: currency}
+	largest := -1
+	for i, amount := range exact {
+		if amount == nil {
+			continue
+		}
+		money, err := model.MoneyFromRat(amount, currency, rounding)
+		if err != nil {
+			return nil, err
+		}
+		rounded[i] = &money
+		sum.Add(sum, amount)
+		if lineTotal, err = lineTotal.Add(money); err != nil {
+			return nil, err
+		}
+		if largest < 0 || amount.Cmp(exact[largest]) > 0 {
+			largest = i
+		}
+	}
+	if mode != RoundPerTotal || largest < 0 {
+		return rounded, nil
+	}
+
+	total, err := model.MoneyFromRat(sum, currency, rounding)
+	if err != nil {
+		return nil, err
+	}
+	// The largest line absorbs the rounding difference
+	difference, err := total.Sub(lineTotal)
+	if err != nil {
+		return nil, err
+	}
+	if *rounded[largest], err = rounded[largest].Add(difference); err != nil {
+		return nil, err
+	}
+	return rounded, nil
+}
+
+func (t *Table) validate() error {
+	if !t.HasCategory(DefaultCategory) {
+		return fmt.Errorf("%w: the %q category must be declared", ErrInvalidTable, DefaultCategory)
+	}
+	for i, category := range t.Categories {
+		if !categoryPattern.MatchString(category) {
+			return fmt.Errorf("%w: invalid category %q", ErrInvalidTable, category)
+		}
+		for _, other := range t.Categories[:i] {
+			if other == category {
+				return fmt.Errorf("%w: category %q declared twice", ErrInvalidTable, category)
+			}
+		}
+	}
+
+	for i := range t.Countries {
+		country := &t.Countries[i]
+		if !countryPattern.MatchString(country.Code) {
+			return fmt.Errorf("%w: invalid country code %q", ErrInvalidTable, country.Code)
+		}
+		for _, other := range t.Countries[:i] {
+			if other.Code == country.Code {
+				return fmt.Errorf("%w: country %s listed twice", ErrInvalidTable, country.Code)
+			}
+		}
+		switch country.Rounding {
+		case "":
+			country.Rounding = RoundPerLine
+		case RoundPerLine, RoundPerTotal:
+		default:
+			return fmt.Errorf("%w: %s: rounding must be %q or %q", ErrInvalidTable, country.Code, RoundPerLine, RoundPerTotal)
+		}
+
+		if err := t.validateRates(country.Code, country.Rates); err != nil {
+			return err
+		}
+		for state, rates := range country.States {
+			if !statePattern.MatchString(state) {
+				return fmt.Errorf("%w: %s: invalid state %q", ErrInvalidTable, country.Code, state)
+			}
+			if err := t.validateRates(country.Code+"-"+state, rates); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// validateRates parses the percents and checks the versions of a tax do not
+// overlap within the country, or within a state
+func (t *Table) validateRates(region string, rates []Rate) error {
+	for i := range rates {
+		rate := &rates[i]
+		if strings.TrimSpace(rate.Name) == "" {
+			return fmt.Errorf("%w: %s: rate without a name", ErrInvalidTable, region)
+		}
+		if !t.HasCategory(rate.Category) {
+			return fmt.Errorf("%w: %s: %s: undeclared category %q", ErrInvalidTable, region, rate.Name, rate.Category)
+		}
+		percent, ok := new(big.Rat).SetString(rate.Percent)
+		if !ok || percent.Sign() < 0 || percent.Cmp(big.NewRat(100, 1)) > 0 {
+			return fmt.Errorf("%w: %s: %s: percent must be a decimal between 0 and 100", ErrInvalidTable, region, rate.Name)
+		}
+		rate.fraction = percent.Quo(percent, big.NewRat(100, 1))
+		if rate.EffectiveFrom.IsZero() {
+			return fmt.Errorf("%w: %s: %s: effective_from is required", ErrInvalidTable, region, rate.Name)
+		}
+		if rate.EffectiveTo != nil && !rate.EffectiveTo.After(rate.EffectiveFrom) {
+			return fmt.Errorf("%w: %s: %s: effective_to must be after effective_from", ErrInvalidTable, region, rate.Name)
+		}
+
+		for _, other := range rates[:i] {
+			if other.Name == rate.Name && other.Category == rate.Category && overlaps(other, *rate) {
+				return fmt.Errorf("%w: %s: %s: overlapping versions for %s", ErrInvalidTable, region, rate.Name, rate.Category)
+			}
+		}
+	}
+	return nil
+}
+
+func overlaps(a, b Rate) bool {
+	aEndsAfterB := a.EffectiveTo == nil || a.EffectiveTo.After(b.EffectiveFrom)
+	bEndsAfterA := b.EffectiveTo == nil || b.EffectiveTo.After(a.EffectiveFrom)
+	return aEndsAfterB && bEndsAfterA
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
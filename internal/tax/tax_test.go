@@ -0,0 +1,167 @@
+package tax
+
+import (
+	"errors"
+	"go-api/model"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func brl(amount int64) model.Money {
+	return model.Money{Amount: amount, Currency: "BRL"}
+}
+
+func usd(amount int64) model.Money {
+	return model.Money{Amount: amount, Currency: "USD"}
+}
+
+func TestLoadFile(t *testing.T) {
+	table, err := LoadFile("../../db/tax_rates.json")
+	require.NoError(t, err)
+	assert.True(t, table.HasCategory(DefaultCategory))
+	assert.False(t, table.HasCategory("luxury"))
+}
+
+func TestTable_Calculate(t *testing.T) {
+	table, err := LoadFile("../../db/tax_rates.json")
+	require.NoError(t, err)
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("Tax Inclusive Per Line", func(t *testing.T) {
+		breakdown, err := table.Calculate(model.TaxDestination{Country: "br", State: "sp"}, at, []Line{
+			{Amount: brl(4990)},
+			{Category: "reduced", Amount: brl(1990)},
+			{Category: "exempt", Amount: brl(1000)},
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, model.TaxDestination{Country: "BR", State: "SP"}, breakdown.Destination)
+		assert.True(t, breakdown.Included)
+		assert.Equal(t, "line", breakdown.Rounding)
+		// 49.90 * 0.18 / 1.18 = 7.6118 and 19.90 * 0.07 / 1.07 = 1.3019
+		assert.Equal(t, []model.LineTax{
+			{Category: "standard", Net: brl(4229), Tax: brl(761), Taxes: []model.TaxAmount{{Name: "ICMS", Percent: "18", Amount: brl(761)}}},
+			{Category: "reduced", Net: brl(1860), Tax: brl(130), Taxes: []model.TaxAmount{{Name: "ICMS", Percent: "7", Amount: brl(130)}}},
+			{Category: "exempt", Net: brl(1000), Tax: brl(0), Taxes: []model.TaxAmount{}},
+		}, breakdown.Lines)
+		assert.Equal(t, []model.TaxAmount{
+			{Name: "ICMS", Percent: "18", Amount: brl(761)},
+			{Name: "ICMS", Percent: "7", Amount: brl(130)},
+		}, breakdown.Taxes)
+		assert.Equal(t, brl(891), breakdown.Total)
+	})
+
+	t.Run("Tax Exclusive Per Total", func(t *testing.T) {
+		lines := []Line{{Amount: usd(110)}, {Amount: usd(110)}, {Amount: usd(110)}}
+		breakdown, err := table.Calculate(model.TaxDestination{Country: "US", State: "NY"}, at, lines)
+
+		require.NoError(t, err)
+		assert.False(t, breakdown.Included)
+		// 4% of 3.30 is 0.132 and 0.375% is 0.012375: rounding each line
+		// would charge 0.12, the differences go to the first line
+		assert.Equal(t, []model.TaxAmount{
+			{Name: "Sales tax", Percent: "4", Amount: usd(13)},
+			{Name: "MCTD surcharge", Percent: "0.375", Amount: usd(1)},
+		}, breakdown.Taxes)
+		assert.Equal(t, usd(6), breakdown.Lines[0].Tax)
+		assert.Equal(t, usd(4), breakdown.Lines[1].Tax)
+		assert.Equal(t, usd(4), breakdown.Lines[2].Tax)
+		assert.Equal(t, usd(110), breakdown.Lines[0].Net)
+		assert.Equal(t, usd(14), breakdown.Total)
+	})
+
+	t.Run("State Without Rates", func(t *testing.T) {
+		breakdown, err := table.Calculate(model.TaxDestination{Country: "US", State: "OR"}, at, []Line{{Amount: usd(1000)}})
+
+		require.NoError(t, err)
+		assert.Empty(t, breakdown.Taxes)
+		assert.Equal(t, usd(0), breakdown.Total)
+	})
+
+	t.Run("Versioned Rates", func(t *testing.T) {
+		rio := model.TaxDestination{Country: "BR", State: "RJ"}
+		change := time.Date(2024, 3, 20, 3, 0, 0, 0, time.UTC)
+
+		before, err := table.Calculate(rio, change.Add(-time.Second), []Line{{Amount: brl(12000)}})
+		require.NoError(t, err)
+		after, err := table.Calculate(rio, change, []Line{{Amount: brl(12000)}})
+		require.NoError(t, err)
+
+		assert.Equal(t, []model.TaxAmount{{Name: "ICMS", Percent: "20", Amount: brl(2000)}}, before.Taxes)
+		assert.Equal(t, []model.TaxAmount{{Name: "ICMS", Percent: "22", Amount: brl(2164)}}, after.Taxes)
+	})
+
+	t.Run("Unknown Country", func(t *testing.T) {
+		_, err := table.Calculate(model.TaxDestination{Country: "AR"}, at, []Line{{Amount: brl(1000)}})
+		assert.True(t, errors.Is(err, ErrRegionNotFound))
+	})
+
+	t.Run("Unknown Category", func(t *testing.T) {
+		_, err := table.Calculate(model.TaxDestination{Country: "DE"}, at, []Line{{Category: "luxury", Amount: brl(1000)}})
+		assert.True(t, errors.Is(err, ErrUnknownCategory))
+	})
+
+	t.Run("Mixed Currencies", func(t *testing.T) {
+		_, err := table.Calculate(model.TaxDestination{Country: "DE"}, at, []Line{{Amount: brl(1000)}, {Amount: usd(1000)}})
+		assert.Equal(t, model.ErrCurrencyMismatch, err)
+	})
+
+	t.Run("Total Out Of Range", func(t *testing.T) {
+		// Each line pays 18/118 of the largest amount; seven of them overflow
+		lines := make([]Line, 7)
+		for i := range lines {
+			lines[i] = Line{Amount: brl(math.MaxInt64)}
+		}
+		_, err := table.Calculate(model.TaxDestination{Country: "BR", State: "SP"}, at, lines)
+		assert.True(t, errors.Is(err, model.ErrAmountOutOfRange))
+	})
+}
+
+func TestTable_Region(t *testing.T) {
+	table, err := LoadFile("../../db/tax_rates.json")
+	require.NoError(t, err)
+
+	region, err := table.Region(model.TaxDestination{Country: "US", State: "CA"}, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC))
+
+	require.NoError(t, err)
+	assert.Equal(t, "2026-01-01", region.Version)
+	assert.False(t, region.PricesIncludeTax)
+	assert.Equal(t, "total", region.Rounding)
+	require.Len(t, region.Rates, 1)
+	assert.Equal(t, "7.25", region.Rates[0].Percent)
+}
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name  string
+		table string
+	}{
+		{"Without The Default Category", `{"categories": ["reduced"], "countries": []}`},
+		{"Unknown Field", `{"categories": ["standard"], "countries": [], "currency": "BRL"}`},
+		{"Invalid Rounding", `{"categories": ["standard"], "countries": [{"code": "BR", "rounding": "up", "rates": []}]}`},
+		{"Undeclared Category", `{"categories": ["standard"], "countries": [{"code": "BR", "rates": [
+			{"name": "ICMS", "category": "food", "percent": "7", "effective_from": "2024-01-01T00:00:00Z"}]}]}`},
+		{"Percent Out Of Range", `{"categories": ["standard"], "countries": [{"code": "BR", "rates": [
+			{"name": "ICMS", "category": "standard", "percent": "118", "effective_from": "2024-01-01T00:00:00Z"}]}]}`},
+		{"Overlapping Versions", `{"categories": ["standard"], "countries": [{"code": "BR", "states": {"RJ": [
+			{"name": "ICMS", "category": "standard", "percent": "20", "effective_from": "2023-01-01T00:00:00Z", "effective_to": "2024-06-01T00:00:00Z"},
+			{"name": "ICMS", "category": "standard", "percent": "22", "effective_from": "2024-03-20T00:00:00Z"}]}}]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Load(strings.NewReader(tt.table))
+			assert.True(t, errors.Is(err, ErrInvalidTable), "got %v", err)
+		})
+	}
+
+	t.Run("Rounding Defaults To Per Line", func(t *testing.T) {
+		table, err := Load(strings.NewReader(`{"categories": ["standard"], "countries": [{"code": "BR", "rates": []}]}`))
+		require.NoError(t, err)
+		assert.Equal(t, RoundPerLine, table.Countries[0].Rounding)
+	})
+}
@@ -0,0 +1,105 @@
+package throttle
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often the MemoryStore drops expired counters and
+// blocks, so keys that stop failing do not pile up
+const sweepInterval = time.Minute
+
+// MemoryStore keeps the counters in the process, for a single instance of
+// the API and for tests
+type MemoryStore struct {
+	mu       sync.Mutex
+	counters map[windowKey]memoryCounter
+	blocks   map[string]time.Time
+	sweptAt  time.Time
+}
+
+type windowKey struct {
+	key   string
+	start int64
+}
+
+type memoryCounter struct {
+	count     int
+	expiresAt time.Time
+}
+
+var _ Store = (*MemoryStore)(nil)
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		counters: make(map[windowKey]memoryCounter),
+		blocks:   make(map[string]time.Time),
+	}
+}
+
+func (ms *MemoryStore) Incr(ctx context.Context, key string, start time.Time, ttl time.Duration) (int, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	ms.sweep(start)
+
+	window := windowKey{key: key, start: start.UnixNano()}
+	counter := ms.counters[window]
+	counter.count++
+	counter.expiresAt = start.Add(ttl)
+	ms.counters[window] = counter
+	return counter.count, nil
+}
+
+func (ms *MemoryStore) Count(ctx context.Context, key string, start time.Time) (int, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	return ms.counters[windowKey{key: key, start: start.UnixNano()}].count, nil
+}
+
+func (ms *MemoryStore) Block(ctx context.Context, key string, until time.Time) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	if until.After(ms.blocks[key]) {
+		ms.blocks[key] = until
+	}
+	return nil
+}
+
+func (ms *MemoryStore) BlockedUntil(ctx context.Context, key string) (time.Time, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	return ms.blocks[key], nil
+}
+
+func (ms *MemoryStore) Reset(ctx context.Context, key string) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	for window := range ms.counters {
+		if window.key == key {
+			delete(ms.counters, window)
+		}
+	}
+	delete(ms.blocks, key)
+	return nil
+}
+
+// --- Helper Functions ---
+
+// sweep drops what expired before now, at most once per sweepInterval
+func (ms *MemoryStore) sweep(now time.Time) {
+	if now.Sub(ms.sweptAt) < sweepInterval {
+		return
+	}
+	ms.sweptAt = now
+	for window, counter := range ms.counters {
+		if counter.expiresAt.Before(now) {
+			delete(ms.counters, window)
+		}
+	}
+	for key, until := range ms.blocks {
+		if until.Before(now) {
+			delete(ms.blocks, key)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package throttle
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// PostgresStore keeps the counters in the throttle_counters and
+// throttle_blocks tables, shared by every instance of the API
+type PostgresStore struct {
+	db *sql.DB
+}
+
+var _ Store = (*PostgresStore)(nil)
+
+func NewPostgresStore(db *sql.DB) *PostgresStore {
+	return &PostgresStore{db: db}
+}
+
+// Incr also drops the counters and blocks that expired, which keeps the
+// tables as small as the set of keys failing right now
+func (ps *PostgresStore) Incr(ctx context.Context, key string, start time.Time, ttl time.Duration) (int, error) {
+	if _, err := ps.db.ExecContext(ctx, `DELETE FROM throttle_counters WHERE expires_at < $1`, start); err != nil {
+		return 0, err
+	}
+	if _, err := ps.db.ExecContext(ctx, `DELETE FROM throttle_blocks WHERE blocked_until < $1`, start); err != nil {
+		return 0, err
+	}
+
+	var count int
+	err := ps.db.QueryRowContext(ctx, `INSERT INTO throttle_counters (key, window_start, count, expires_at) VALUES ($1, $2, 1, $3)
+		ON CONFLICT (key, window_start) DO UPDATE SET count = throttle_counters.count + 1
+		RETURNING count`, key, start, start.Add(ttl)).Scan(&count)
+	return count, err
+}
+
+func (ps *PostgresStore) Count(ctx context.Context, key string, start time.Time) (int, error) {
+	var count int
+	err := ps.db.QueryRowContext(ctx, `SELECT count FROM throttle_counters WHERE key = $1 AND window_start = $2`, key, start).Scan(&count)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	return count, err
+}
+
+func (ps *PostgresStore) Block(ctx context.Context, key string, until time.Time) error {
+	_, err := ps.db.ExecContext(ctx, `INSERT INTO throttle_blocks (key, blocked_until) VALUES ($1, $2)
+		ON CONFLICT (key) DO UPDATE SET blocked_until = GREATEST(throttle_blocks.blocked_until, EXCLUDED.blocked_until)`, key, until)
+	return err
+}
+
+func (ps *PostgresStore) BlockedUntil(ctx context.Context, key string) (time.Time, error) {
+	var until time.Time
+	err := ps.db.QueryRowContext(ctx, `SELECT blocked_until FROM throttle_blocks WHERE key = $1`, key).Scan(&until)
+	if err == sql.ErrNoRows {
+		return time.Time{}, nil
+	}
+	return until, err
+}
+
+func (ps *PostgresStore) Reset(ctx context.Context, key string) error {
+	tx, err := ps.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM throttle_counters WHERE key = $1`, key); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM throttle_blocks WHERE key = $1`, key); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,165 @@
+// Package throttle counts the failures of a key (an account, an IP) over a
+// sliding window and blocks keys that fail too often: first with delays that
+// double with each failure, then with a lockout. The counters live in a
+// Store, in memory or in Postgres; the Store operations map one to one to
+// Redis commands (INCR with EXPIRE, GET, SET with PX, DEL), so a Redis store
+// can be added without touching the Limiter.
+package throttle
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Store keeps the failure counters and the blocks of the keys
+type Store interface {
+	// Incr adds one to the counter of key for the window starting at start,
+	// kept for at least ttl after start, and returns the new count
+	Incr(ctx context.Context, key string, start time.Time, ttl time.Duration) (int, error)
+	// Count returns the counter of key for the window starting at start
+	Count(ctx context.Context, key string, start time.Time) (int, error)
+	// Block keeps key blocked until the given instant, or later if it is
+	// already blocked for longer
+	Block(ctx context.Context, key string, until time.Time) error
+	// BlockedUntil returns until when key is blocked, zero when it never was
+	BlockedUntil(ctx context.Context, key string) (time.Time, error)
+	// Reset removes the counters and the block of key
+	Reset(ctx context.Context, key string) error
+}
+
+// Policy holds when failures start to block a key, and for how long
+type Policy struct {
+	// Window is the span over which failures are counted
+	Window time.Duration
+	// FreeFailures is how many failures within the window cost nothing
+	FreeFailures int
+	// BaseDelay is the block after the first failure over FreeFailures,
+	// doubled after each other one up to MaxDelay
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	// LockoutFailures locks the key out for LockoutDuration once it fails
+	// that many times within the window; zero never locks it out
+	LockoutFailures int
+	LockoutDuration time.Duration
+}
+
+// Outcome is the state of a key right after a failure
+type Outcome struct {
+	// Failures is the estimate of the failures within the window
+	Failures int
+	// Wait is how long the key is now blocked, zero when it is not
+	Wait time.Duration
+	// Locked tells that this failure locked the key out
+	Locked bool
+}
+
+// Limiter applies a Policy to the keys of a Store
+type Limiter struct {
+	store  Store
+	policy Policy
+	now    func() time.Time
+}
+
+// NewLimiter creates a Limiter counting failures in store
+func NewLimiter(store Store, policy Policy) *Limiter {
+	return &Limiter{store: store, policy: policy, now: time.Now}
+}
+
+// Wait returns how long key must wait before its next attempt; zero lets it
+// try now
+func (l *Limiter) Wait(ctx context.Context, key string) (time.Duration, error) {
+	until, err := l.store.BlockedUntil(ctx, key)
+	if err != nil {
+		return 0, err
+	}
+	if wait := until.Sub(l.now()); wait > 0 {
+		return wait, nil
+	}
+	return 0, nil
+}
+
+// Fail records a failure of key and blocks it as the policy says
+func (l *Limiter) Fail(ctx context.Context, key string) (Outcome, error) {
+	now := l.now()
+	start := now.Truncate(l.policy.Window)
+	current, err := l.store.Incr(ctx, key, start, 2*l.policy.Window)
+	if err != nil {
+		return Outcome{}, err
+	}
+	previous, err := l.store.Count(ctx, key, start.Add(-l.policy.Window))
+	if err != nil {
+		return Outcome{}, err
+	}
+
+	// The sliding window weighs the previous window by how much of it still
+	// overlaps the last Window up to now
+	overlap := 1 - float64(now.Sub(start))/float64(l.policy.Window)
+	outcome := Outcome{Failures: current + int(float64(previous)*overlap)}
+	switch {
+	case l.policy.LockoutFailures > 0 && outcome.Failures >= l.policy.LockoutFailures:
+		outcome.Wait, outcome.Locked = l.policy.LockoutDuration, true
+	case outcome.Failures > l.policy.FreeFailures:
+		outcome.Wait = l.delay(outcome.Failures - l.policy.FreeFailures)
+	default:
+		return outcome, nil
+	}
+	if err := l.store.Block(ctx, key, now.Add(outcome.Wait)); err != nil {
+		return Outcome{}, err
+	}
+	return outcome, nil
+}
+
+// Reset forgets the failures of key and lifts its block
+func (l *Limiter) Reset(ctx context.Context, key string) error {
+	return l.store.Reset(ctx, key)
+}
+
+// Config selects the Store
+type Config struct {
+	// Driver is "memory" or "postgres"
+	Driver string
+}
+
+// NewConfig reads the throttle configuration from environment variables
+func NewConfig() *Config {
+	return &Config{
+		Driver: getEnv("THROTTLE_STORE", "memory"),
+	}
+}
+
+// New creates the Store selected by the configuration; db is only used by
+// the postgres driver
+func New(config *Config, db *sql.DB) (Store, error) {
+	switch config.Driver {
+	case "memory":
+		return NewMemoryStore(), nil
+	case "postgres":
+		return NewPostgresStore(db), nil
+	default:
+		return nil, fmt.Errorf("throttle: unknown store %q", config.Driver)
+	}
+}
+
+// --- Helper Functions ---
+
+// delay is BaseDelay doubled for each excess failure after the first
+func (l *Limiter) delay(excess int) time.Duration {
+	delay := l.policy.BaseDelay
+	for i := 1; i < excess && delay < l.policy.MaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > l.policy.MaxDelay {
+		return l.policy.MaxDelay
+	}
+	return delay
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
@@ -0,0 +1,117 @@
+package throttle
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimiter(t *testing.T) {
+	ctx := context.Background()
+	policy := Policy{
+		Window:          10 * time.Minute,
+		FreeFailures:    2,
+		BaseDelay:       time.Second,
+		MaxDelay:        4 * time.Second,
+		LockoutFailures: 6,
+		LockoutDuration: 15 * time.Minute,
+	}
+	newLimiter := func(now *time.Time) *Limiter {
+		limiter := NewLimiter(NewMemoryStore(), policy)
+		limiter.now = func() time.Time { return *now }
+		return limiter
+	}
+
+	t.Run("DelaysDoubleThenLockOut", func(t *testing.T) {
+		now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+		limiter := newLimiter(&now)
+
+		var waits []time.Duration
+		for i := 0; i < 6; i++ {
+			outcome, err := limiter.Fail(ctx, "account:ana")
+			require.NoError(t, err)
+			assert.Equal(t, i+1, outcome.Failures)
+			assert.Equal(t, i == 5, outcome.Locked)
+			waits = append(waits, outcome.Wait)
+		}
+		assert.Equal(t, []time.Duration{0, 0, time.Second, 2 * time.Second, 4 * time.Second, 15 * time.Minute}, waits)
+
+		wait, err := limiter.Wait(ctx, "account:ana")
+		require.NoError(t, err)
+		assert.Equal(t, 15*time.Minute, wait)
+		wait, err = limiter.Wait(ctx, "account:bia")
+		require.NoError(t, err)
+		assert.Zero(t, wait)
+
+		require.NoError(t, limiter.Reset(ctx, "account:ana"))
+		wait, err = limiter.Wait(ctx, "account:ana")
+		require.NoError(t, err)
+		assert.Zero(t, wait)
+	})
+
+	t.Run("SlidingWindowWeighsThePreviousWindow", func(t *testing.T) {
+		now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+		limiter := newLimiter(&now)
+		for i := 0; i < 4; i++ {
+			_, err := limiter.Fail(ctx, "ip:10.0.0.1")
+			require.NoError(t, err)
+		}
+
+		// A quarter into the next window, three quarters of the 4 failures count
+		now = now.Add(12*time.Minute + 30*time.Second)
+		outcome, err := limiter.Fail(ctx, "ip:10.0.0.1")
+		require.NoError(t, err)
+		assert.Equal(t, 4, outcome.Failures)
+
+		// Two windows later nothing is left
+		now = now.Add(20 * time.Minute)
+		outcome, err = limiter.Fail(ctx, "ip:10.0.0.1")
+		require.NoError(t, err)
+		assert.Equal(t, 1, outcome.Failures)
+		assert.Zero(t, outcome.Wait)
+	})
+}
+
+func TestPostgresStore(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("Incr", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectExec(regexp.QuoteMeta("DELETE FROM throttle_counters WHERE expires_at < $1")).WithArgs(start).WillReturnResult(sqlmock.NewResult(0, 3))
+		mock.ExpectExec(regexp.QuoteMeta("DELETE FROM throttle_blocks WHERE blocked_until < $1")).WithArgs(start).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO throttle_counters (key, window_start, count, expires_at) VALUES ($1, $2, 1, $3) ON CONFLICT (key, window_start) DO UPDATE SET count = throttle_counters.count + 1 RETURNING count")).
+			WithArgs("account:ana", start, start.Add(20*time.Minute)).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+
+		count, err := NewPostgresStore(db).Incr(ctx, "account:ana", start, 20*time.Minute)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, count)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("NeverBlocked", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT blocked_until FROM throttle_blocks WHERE key = $1")).
+			WithArgs("account:ana").
+			WillReturnRows(sqlmock.NewRows([]string{"blocked_until"}))
+
+		until, err := NewPostgresStore(db).BlockedUntil(ctx, "account:ana")
+
+		assert.NoError(t, err)
+		assert.True(t, until.IsZero())
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -1,22 +1,133 @@
 package util
 
 import (
+	"errors"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
-const (
-	SecretKey = "secret"
+// challengeType marks MFA challenge tokens, which ParseToken refuses, so one
+// cannot stand in for an access token
+const challengeType = "mfa_challenge"
+
+var (
+	ErrInvalidToken     = errors.New("invalid token")
+	ErrWeakSecretKey    = errors.New("jwt secret key must have at least 32 bytes")
+	ErrMissingSecretKey = errors.New("jwt secret key is not set")
 )
 
-func GenerateToken(email string, userID int) (string, error) {
+// minSecretKeyLength is the size of the HS256 output; a shorter key is
+// easier to guess than the signature itself
+const minSecretKeyLength = 32
+
+// secretKey signs and checks every token; SetSecretKey sets it at startup
+var secretKey []byte
+
+// SetSecretKey sets the HS256 key of the tokens. It must be called before
+// any token is generated or parsed; until then both fail
+func SetSecretKey(key string) error {
+	if len(key) < minSecretKeyLength {
+		return ErrWeakSecretKey
+	}
+	secretKey = []byte(key)
+	return nil
+}
+
+// Claims are the identity data carried by the access token
+type Claims struct {
+	UserID int
+	Email  string
+	Role   string
+	// MFA tells that the login passed a second factor
+	MFA bool
+}
+
+func GenerateToken(email string, userID int, role string, mfa bool) (string, error) {
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256,
 		jwt.MapClaims{
 			"email": email,
 			"id":    userID,
+			"role":  role,
+			"mfa":   mfa,
 			"exp":   time.Now().Add(time.Hour * 24).Unix(),
 		})
 
-	return token.SignedString([]byte(SecretKey))
+	return signToken(token)
+}
+
+// ParseToken validates the signature and expiry of a token generated by GenerateToken
+func ParseToken(tokenString string) (*Claims, error) {
+	mapClaims, err := parseClaims(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	if typ, _ := mapClaims["typ"].(string); typ != "" {
+		return nil, ErrInvalidToken
+	}
+	id, _ := mapClaims["id"].(float64)
+	email, _ := mapClaims["email"].(string)
+	role, _ := mapClaims["role"].(string)
+	mfa, _ := mapClaims["mfa"].(bool)
+	if id == 0 {
+		return nil, ErrInvalidToken
+	}
+
+	return &Claims{UserID: int(id), Email: email, Role: role, MFA: mfa}, nil
+}
+
+// GenerateChallengeToken returns the token that proves the password of the
+// user was right, to be exchanged with a second factor for an access token
+func GenerateChallengeToken(userID int, ttl time.Duration) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256,
+		jwt.MapClaims{
+			"typ": challengeType,
+			"id":  userID,
+			"exp": time.Now().Add(ttl).Unix(),
+		})
+
+	return signToken(token)
+}
+
+// ParseChallengeToken returns the user of a token generated by GenerateChallengeToken
+func ParseChallengeToken(tokenString string) (int, error) {
+	mapClaims, err := parseClaims(tokenString)
+	if err != nil {
+		return 0, err
+	}
+	if typ, _ := mapClaims["typ"].(string); typ != challengeType {
+		return 0, ErrInvalidToken
+	}
+	id, _ := mapClaims["id"].(float64)
+	if id == 0 {
+		return 0, ErrInvalidToken
+	}
+	return int(id), nil
+}
+
+// --- Helper Functions ---
+
+func signToken(token *jwt.Token) (string, error) {
+	if len(secretKey) == 0 {
+		return "", ErrMissingSecretKey
+	}
+	return token.SignedString(secretKey)
+}
+
+func parseClaims(tokenString string) (jwt.MapClaims, error) {
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		if len(secretKey) == 0 {
+			return nil, ErrMissingSecretKey
+		}
+		return secretKey, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithExpirationRequired())
+	if err != nil || !token.Valid {
+		return nil, ErrInvalidToken
+	}
+
+	mapClaims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+	return mapClaims, nil
 }
@@ -0,0 +1,219 @@
+package middleware
+
+import (
+	"context"
+	"go-api/internal/audit"
+	"go-api/internal/util"
+	"go-api/model"
+	"log"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Keys under which AuthRequired stores the authenticated identity in the gin context
+const (
+	ContextUserID = "userID"
+	ContextEmail  = "email"
+	ContextRole   = "role"
+	// ContextMFA is true when the login passed a second factor
+	ContextMFA = "mfa"
+	// ContextAPIKeyID is only set for requests made with an API key, and
+	// ContextOAuthClientID for those made with an OAuth access token
+	ContextAPIKeyID      = "apiKeyID"
+	ContextOAuthClientID = "oauthClientID"
+	// ContextScopes is set for requests made with an API key or an access
+	// token
+	ContextScopes = "scopes"
+)
+
+// HeaderAPIKey carries an API key; a bearer token starting with
+// model.APIKeyPrefix is taken as one too
+const HeaderAPIKey = "X-API-Key"
+
+// APIKeyAuthenticator checks the API keys accepted besides JWTs. It returns
+// nil, nil for a key that does not work from ip, whatever the reason
+type APIKeyAuthenticator interface {
+	AuthenticateAPIKey(ctx context.Context, key, ip string) (*model.APIKeyPrincipal, error)
+}
+
+// AccessTokenAuthenticator checks the OAuth access tokens issued to
+// third-party clients. It returns nil, nil for a token that does not work
+type AccessTokenAuthenticator interface {
+	AuthenticateAccessToken(ctx context.Context, token string) (*model.OAuthPrincipal, error)
+}
+
+// AuthRequired rejects requests without a valid "Authorization: Bearer <token>"
+// header or, when apiKeys is not nil, a valid API key or, when accessTokens
+// is not nil, a valid OAuth access token
+func AuthRequired(apiKeys APIKeyAuthenticator, accessTokens AccessTokenAuthenticator) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		token := credential(ctx)
+		if token == "" {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "missing bearer token"})
+			return
+		}
+
+		authenticate(ctx, apiKeys, accessTokens, token)
+	}
+}
+
+// AuthOptional lets anonymous requests through and authenticates the ones
+// carrying a bearer token, an API key or an access token, rejecting invalid
+// ones like AuthRequired
+func AuthOptional(apiKeys APIKeyAuthenticator, accessTokens AccessTokenAuthenticator) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		token := credential(ctx)
+		if token == "" {
+			ctx.Next()
+			return
+		}
+
+		authenticate(ctx, apiKeys, accessTokens, token)
+	}
+}
+
+// RequireRole must run after AuthRequired and only lets the given roles through
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		role := ctx.GetString(ContextRole)
+		for _, allowed := range roles {
+			if role == allowed {
+				ctx.Next()
+				return
+			}
+		}
+		ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "insufficient permissions"})
+	}
+}
+
+// RequireMFA must run after AuthRequired and rejects the given roles unless
+// their login passed a second factor; other roles go through
+func RequireMFA(roles ...string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		role := ctx.GetString(ContextRole)
+		for _, required := range roles {
+			if role == required && !ctx.GetBool(ContextMFA) {
+				ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "two-factor authentication required"})
+				return
+			}
+		}
+		ctx.Next()
+	}
+}
+
+// RequireScope must run after AuthRequired and rejects API keys and access
+// tokens without the scope. JWTs go through: what a user may do is up to
+// RequireRole
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if scopes, ok := ctx.Get(ContextScopes); ok && !slices.Contains(scopes.([]string), scope) {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "credential lacks scope " + scope})
+			return
+		}
+		ctx.Next()
+	}
+}
+
+// --- Helper Functions ---
+
+// credential returns the API key of the X-API-Key header or else the bearer
+// token, empty when the request has neither
+func credential(ctx *gin.Context) string {
+	if key := ctx.GetHeader(HeaderAPIKey); key != "" {
+		return key
+	}
+	if token, ok := strings.CutPrefix(ctx.GetHeader("Authorization"), "Bearer "); ok {
+		return token
+	}
+	return ""
+}
+
+func authenticate(ctx *gin.Context, apiKeys APIKeyAuthenticator, accessTokens AccessTokenAuthenticator, token string) {
+	if strings.HasPrefix(token, model.APIKeyPrefix) {
+		authenticateAPIKey(ctx, apiKeys, token)
+		return
+	}
+	if strings.HasPrefix(token, model.AccessTokenPrefix) {
+		authenticateAccessToken(ctx, accessTokens, token)
+		return
+	}
+
+	claims, err := util.ParseToken(token)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Set(ContextUserID, claims.UserID)
+	ctx.Set(ContextEmail, claims.Email)
+	ctx.Set(ContextRole, claims.Role)
+	ctx.Set(ContextMFA, claims.MFA)
+	ctx.Next()
+}
+
+// authenticateAPIKey stores the owner of a valid key as the identity of the
+// request, and as the actor of its audit events
+func authenticateAPIKey(ctx *gin.Context, apiKeys APIKeyAuthenticator, key string) {
+	if apiKeys == nil {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "api keys are not accepted here"})
+		return
+	}
+	principal, err := apiKeys.AuthenticateAPIKey(ctx.Request.Context(), key, ctx.ClientIP())
+	if err != nil {
+		log.Printf("api key authentication: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "could not check the api key"})
+		return
+	}
+	if principal == nil {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid api key"})
+		return
+	}
+
+	ctx.Set(ContextUserID, principal.UserID)
+	ctx.Set(ContextEmail, principal.Email)
+	ctx.Set(ContextRole, principal.Role)
+	// Keys are made by admins who passed RequireMFA, and cannot answer a
+	// second factor themselves
+	ctx.Set(ContextMFA, true)
+	ctx.Set(ContextAPIKeyID, principal.KeyID)
+	ctx.Set(ContextScopes, principal.Scopes)
+
+	metadata := audit.FromContext(ctx.Request.Context())
+	metadata.ActorID = &principal.UserID
+	ctx.Request = ctx.Request.WithContext(audit.NewContext(ctx.Request.Context(), metadata))
+	ctx.Next()
+}
+
+// authenticateAccessToken stores the user a valid access token acts for as
+// the identity of the request, and as the actor of its audit events
+func authenticateAccessToken(ctx *gin.Context, accessTokens AccessTokenAuthenticator, token string) {
+	if accessTokens == nil {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "access tokens are not accepted here"})
+		return
+	}
+	principal, err := accessTokens.AuthenticateAccessToken(ctx.Request.Context(), token)
+	if err != nil {
+		log.Printf("access token authentication: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "could not check the access token"})
+		return
+	}
+	if principal == nil {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid access token"})
+		return
+	}
+
+	ctx.Set(ContextUserID, principal.UserID)
+	ctx.Set(ContextEmail, principal.Email)
+	ctx.Set(ContextRole, principal.Role)
+	ctx.Set(ContextMFA, principal.MFA)
+	ctx.Set(ContextOAuthClientID, principal.ClientID)
+	ctx.Set(ContextScopes, principal.Scopes)
+
+	metadata := audit.FromContext(ctx.Request.Context())
+	metadata.ActorID = &principal.UserID
+	ctx.Request = ctx.Request.WithContext(audit.NewContext(ctx.Request.Context(), metadata))
+	ctx.Next()
+}
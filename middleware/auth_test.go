@@ -0,0 +1,308 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"go-api/internal/audit"
+	"go-api/internal/util"
+	"go-api/model"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func newAuthRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/admin", AuthRequired(nil, nil), RequireRole(model.RoleAdmin), func(ctx *gin.Context) {
+		ctx.JSON(http.StatusOK, gin.H{"user_id": ctx.GetInt(ContextUserID)})
+	})
+	return router
+}
+
+func TestAuthRequired(t *testing.T) {
+	t.Run("Missing Token", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/admin", nil)
+		newAuthRouter().ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("Invalid Token", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/admin", nil)
+		req.Header.Set("Authorization", "Bearer not-a-token")
+		newAuthRouter().ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("Token Signed With Another Key", func(t *testing.T) {
+		forged, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+			"id": 1, "role": model.RoleAdmin, "mfa": true, "exp": time.Now().Add(time.Hour).Unix(),
+		}).SignedString([]byte("secret"))
+		assert.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/admin", nil)
+		req.Header.Set("Authorization", "Bearer "+forged)
+		newAuthRouter().ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
+
+func TestRequireRole(t *testing.T) {
+	t.Run("Admin", func(t *testing.T) {
+		token, err := util.GenerateToken("admin@example.com", 7, model.RoleAdmin, false)
+		assert.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/admin", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		newAuthRouter().ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"user_id": 7}`, w.Body.String())
+	})
+
+	t.Run("Customer", func(t *testing.T) {
+		token, err := util.GenerateToken("user@example.com", 8, model.RoleCustomer, false)
+		assert.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/admin", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		newAuthRouter().ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+}
+
+func TestRequireMFA(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/admin", AuthRequired(nil, nil), RequireMFA(model.RoleAdmin), func(ctx *gin.Context) {
+		ctx.Status(http.StatusNoContent)
+	})
+	request := func(token string) int {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/admin", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	withoutMFA, err := util.GenerateToken("admin@example.com", 7, model.RoleAdmin, false)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusForbidden, request(withoutMFA))
+
+	withMFA, err := util.GenerateToken("admin@example.com", 7, model.RoleAdmin, true)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, request(withMFA))
+
+	customer, err := util.GenerateToken("user@example.com", 8, model.RoleCustomer, false)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, request(customer))
+
+	// A challenge token is not an access token
+	challenge, err := util.GenerateChallengeToken(7, time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, request(challenge))
+}
+
+func TestAuthOptional(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/cart", AuthOptional(nil, nil), func(ctx *gin.Context) {
+		ctx.JSON(http.StatusOK, gin.H{"user_id": ctx.GetInt(ContextUserID)})
+	})
+
+	t.Run("Anonymous", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/cart", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"user_id": 0}`, w.Body.String())
+	})
+
+	t.Run("Authenticated", func(t *testing.T) {
+		token, err := util.GenerateToken("user@example.com", 8, model.RoleCustomer, false)
+		assert.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/cart", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"user_id": 8}`, w.Body.String())
+	})
+
+	t.Run("Invalid Token", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/cart", nil)
+		req.Header.Set("Authorization", "Bearer not-a-token")
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
+
+// apiKeyAuthenticatorFunc adapts a function to APIKeyAuthenticator
+type apiKeyAuthenticatorFunc func(ctx context.Context, key, ip string) (*model.APIKeyPrincipal, error)
+
+func (f apiKeyAuthenticatorFunc) AuthenticateAPIKey(ctx context.Context, key, ip string) (*model.APIKeyPrincipal, error) {
+	return f(ctx, key, ip)
+}
+
+func TestAPIKeyAuthentication(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	apiKeys := apiKeyAuthenticatorFunc(func(ctx context.Context, key, ip string) (*model.APIKeyPrincipal, error) {
+		switch key {
+		case "gak_valid":
+			return &model.APIKeyPrincipal{KeyID: 4, UserID: 9, Role: model.RoleService, Scopes: []string{model.ScopeProductsRead}}, nil
+		case "gak_broken":
+			return nil, errors.New("db down")
+		default:
+			return nil, nil
+		}
+	})
+	router := gin.New()
+	router.Use(RequestContext())
+	scoped := router.Group("/", AuthRequired(apiKeys, nil), RequireRole(model.RoleAdmin, model.RoleService), RequireMFA(model.RoleAdmin))
+	scoped.GET("/products/export", RequireScope(model.ScopeProductsRead), func(ctx *gin.Context) {
+		actor := audit.FromContext(ctx.Request.Context()).ActorID
+		ctx.JSON(http.StatusOK, gin.H{"user_id": ctx.GetInt(ContextUserID), "key_id": ctx.GetInt(ContextAPIKeyID), "actor_id": actor})
+	})
+	scoped.DELETE("/products/1", RequireScope(model.ScopeProductsWrite), func(ctx *gin.Context) {
+		ctx.Status(http.StatusNoContent)
+	})
+	router.GET("/me", AuthRequired(nil, nil), func(ctx *gin.Context) {
+		ctx.Status(http.StatusNoContent)
+	})
+	request := func(method, path string, header, value string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, path, nil)
+		req.Header.Set(header, value)
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("Header", func(t *testing.T) {
+		w := request(http.MethodGet, "/products/export", HeaderAPIKey, "gak_valid")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"user_id": 9, "key_id": 4, "actor_id": 9}`, w.Body.String())
+	})
+
+	t.Run("Bearer", func(t *testing.T) {
+		w := request(http.MethodGet, "/products/export", "Authorization", "Bearer gak_valid")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("Missing Scope", func(t *testing.T) {
+		w := request(http.MethodDelete, "/products/1", HeaderAPIKey, "gak_valid")
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("JWTs Skip Scopes", func(t *testing.T) {
+		token, err := util.GenerateToken("admin@example.com", 7, model.RoleAdmin, true)
+		assert.NoError(t, err)
+
+		w := request(http.MethodDelete, "/products/1", "Authorization", "Bearer "+token)
+
+		assert.Equal(t, http.StatusNoContent, w.Code)
+	})
+
+	t.Run("Invalid Key", func(t *testing.T) {
+		w := request(http.MethodGet, "/products/export", HeaderAPIKey, "gak_revoked")
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("Authenticator Error", func(t *testing.T) {
+		w := request(http.MethodGet, "/products/export", HeaderAPIKey, "gak_broken")
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+
+	t.Run("Not Accepted Without An Authenticator", func(t *testing.T) {
+		w := request(http.MethodGet, "/me", HeaderAPIKey, "gak_valid")
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
+
+// accessTokenAuthenticatorFunc adapts a function to AccessTokenAuthenticator
+type accessTokenAuthenticatorFunc func(ctx context.Context, token string) (*model.OAuthPrincipal, error)
+
+func (f accessTokenAuthenticatorFunc) AuthenticateAccessToken(ctx context.Context, token string) (*model.OAuthPrincipal, error) {
+	return f(ctx, token)
+}
+
+func TestAccessTokenAuthentication(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	accessTokens := accessTokenAuthenticatorFunc(func(ctx context.Context, token string) (*model.OAuthPrincipal, error) {
+		if token != "gat_valid" {
+			return nil, nil
+		}
+		return &model.OAuthPrincipal{TokenID: 3, ClientID: "webapp", UserID: 8, Role: model.RoleCustomer, Scopes: []string{model.ScopeOrdersRead}}, nil
+	})
+	router := gin.New()
+	router.Use(RequestContext())
+	delegated := router.Group("/me", AuthRequired(nil, accessTokens))
+	delegated.GET("/orders", RequireScope(model.ScopeOrdersRead), func(ctx *gin.Context) {
+		actor := audit.FromContext(ctx.Request.Context()).ActorID
+		ctx.JSON(http.StatusOK, gin.H{"user_id": ctx.GetInt(ContextUserID), "client_id": ctx.GetString(ContextOAuthClientID), "mfa": ctx.GetBool(ContextMFA), "actor_id": actor})
+	})
+	delegated.POST("/orders/1/cancel", RequireScope(model.ScopeOrdersWrite), func(ctx *gin.Context) {
+		ctx.Status(http.StatusNoContent)
+	})
+	router.GET("/products/export", AuthRequired(apiKeyAuthenticatorFunc(func(ctx context.Context, key, ip string) (*model.APIKeyPrincipal, error) {
+		return nil, nil
+	}), nil), func(ctx *gin.Context) {
+		ctx.Status(http.StatusNoContent)
+	})
+	request := func(method, path, token string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, path, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("Valid Token", func(t *testing.T) {
+		w := request(http.MethodGet, "/me/orders", "gat_valid")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"user_id": 8, "client_id": "webapp", "mfa": false, "actor_id": 8}`, w.Body.String())
+	})
+
+	t.Run("Missing Scope", func(t *testing.T) {
+		w := request(http.MethodPost, "/me/orders/1/cancel", "gat_valid")
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("Invalid Token", func(t *testing.T) {
+		w := request(http.MethodGet, "/me/orders", "gat_revoked")
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("Not Accepted Without An Authenticator", func(t *testing.T) {
+		w := request(http.MethodGet, "/products/export", "gat_valid")
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
@@ -0,0 +1,15 @@
+package middleware
+
+import (
+	"go-api/internal/util"
+	"os"
+	"testing"
+)
+
+// TestMain sets the key that signs the tokens of the tests
+func TestMain(m *testing.M) {
+	if err := util.SetSecretKey("test-jwt-secret-with-at-least-32-bytes"); err != nil {
+		panic(err)
+	}
+	os.Exit(m.Run())
+}
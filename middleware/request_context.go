@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"go-api/internal/audit"
+	"go-api/internal/util"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HeaderRequestID carries the request ID, both ways
+const HeaderRequestID = "X-Request-ID"
+
+// maxRequestIDLength bounds request IDs supplied by clients
+const maxRequestIDLength = 64
+
+// RequestContext identifies each request for the audit log: it keeps the
+// X-Request-ID of the client (or generates one), echoes it in the response
+// and stores it, with the client IP, the user agent and the user of a valid
+// bearer token, in the context of the request; AuthRequired sets the owner
+// of an API key or the user of an access token. Routes without AuthRequired still work without a token;
+// their changes are then recorded as anonymous
+func RequestContext() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		requestID := sanitizeRequestID(ctx.GetHeader(HeaderRequestID))
+		if requestID == "" {
+			requestID = newRequestID()
+		}
+		ctx.Header(HeaderRequestID, requestID)
+
+		metadata := audit.Metadata{
+			RequestID: requestID,
+			IP:        ctx.ClientIP(),
+			UserAgent: ctx.Request.UserAgent(),
+		}
+		if token, ok := strings.CutPrefix(ctx.GetHeader("Authorization"), "Bearer "); ok && token != "" {
+			if claims, err := util.ParseToken(token); err == nil {
+				userID := claims.UserID
+				metadata.ActorID = &userID
+			}
+		}
+
+		ctx.Request = ctx.Request.WithContext(audit.NewContext(ctx.Request.Context(), metadata))
+		ctx.Next()
+	}
+}
+
+// --- Helper Functions ---
+
+// sanitizeRequestID drops IDs that are too long or have characters other
+// than letters, digits and -_.:, so they are safe in logs and headers
+func sanitizeRequestID(id string) string {
+	if len(id) > maxRequestIDLength {
+		return ""
+	}
+	for _, r := range id {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("-_.:", r)) {
+			return ""
+		}
+	}
+	return id
+}
+
+func newRequestID() string {
+	buf := make([]byte, 16)
+	rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"go-api/internal/audit"
+	"go-api/internal/util"
+	"go-api/model"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func serveRequestContext(req *http.Request) (*httptest.ResponseRecorder, audit.Metadata) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	var metadata audit.Metadata
+	router.GET("/", RequestContext(), func(ctx *gin.Context) {
+		metadata = audit.FromContext(ctx.Request.Context())
+		ctx.Status(http.StatusNoContent)
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w, metadata
+}
+
+func TestRequestContext(t *testing.T) {
+	t.Run("Keeps Client Request ID And Actor", func(t *testing.T) {
+		token, err := util.GenerateToken("admin@example.com", 7, model.RoleAdmin, false)
+		assert.NoError(t, err)
+
+		req, _ := http.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = "10.0.0.1:5000"
+		req.Header.Set(HeaderRequestID, "req-123")
+		req.Header.Set("User-Agent", "curl/8.0")
+		req.Header.Set("Authorization", "Bearer "+token)
+		w, metadata := serveRequestContext(req)
+
+		assert.Equal(t, "req-123", w.Header().Get(HeaderRequestID))
+		actor := 7
+		assert.Equal(t, audit.Metadata{ActorID: &actor, RequestID: "req-123", IP: "10.0.0.1", UserAgent: "curl/8.0"}, metadata)
+	})
+
+	t.Run("Replaces Unsafe Request ID", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(HeaderRequestID, "bad id\r\n"+strings.Repeat("x", 80))
+		req.Header.Set("Authorization", "Bearer not-a-token")
+		w, metadata := serveRequestContext(req)
+
+		assert.Len(t, metadata.RequestID, 32)
+		assert.Equal(t, metadata.RequestID, w.Header().Get(HeaderRequestID))
+		assert.Nil(t, metadata.ActorID)
+	})
+}
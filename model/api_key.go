@@ -0,0 +1,52 @@
+package model
+
+import "time"
+
+// APIKeyPrefix starts every API key, so they are told apart from JWTs and
+// found by secret scanners
+const APIKeyPrefix = "gak_"
+
+// Scopes an API key or an OAuth access token can be granted; each one opens
+// the routes that declare it
+const (
+	ScopeProductsRead  = "products:read"
+	ScopeProductsWrite = "products:write"
+	ScopeOrdersRead    = "orders:read"
+	ScopeOrdersWrite   = "orders:write"
+)
+
+// APIKeyScopes lists the valid scopes, of OAuth clients too
+var APIKeyScopes = []string{ScopeProductsRead, ScopeProductsWrite, ScopeOrdersRead, ScopeOrdersWrite}
+
+// APIKey lets an integration call the API as the user owning it, a person
+// or a service account, within its scopes. Only the SHA-256 of the key is
+// stored; the prefix stays visible to tell the keys apart
+type APIKey struct {
+	ID     int    `json:"id"`
+	UserID int    `json:"user_id"`
+	Name   string `json:"name"`
+	// Prefix is the start of the key, such as gak_3f9a1c0b7d2e
+	Prefix  string   `json:"prefix"`
+	KeyHash string   `json:"-"`
+	Scopes  []string `json:"scopes"`
+	// AllowedIPs holds the IPs and CIDR ranges the key works from; empty
+	// means any
+	AllowedIPs []string `json:"allowed_ips"`
+	// ExpiresAt is nil for keys that do not expire
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	LastUsedIP string     `json:"last_used_ip,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	CreatedBy  *int       `json:"created_by,omitempty"`
+	RotatedAt  *time.Time `json:"rotated_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+}
+
+// APIKeyPrincipal is who a valid API key authenticates
+type APIKeyPrincipal struct {
+	KeyID  int
+	UserID int
+	Email  string
+	Role   string
+	Scopes []string
+}
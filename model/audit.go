@@ -0,0 +1,90 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Actions recorded in the audit log
+const (
+	AuditActionCreate        = "create"
+	AuditActionUpdate        = "update"
+	AuditActionDelete        = "delete"
+	AuditActionRestore       = "restore"
+	AuditActionPurge         = "purge"
+	AuditActionSchedulePrice = "schedule_price"
+	AuditActionImport        = "import"
+	AuditActionPasswordReset = "password_reset"
+	AuditActionLockout       = "lockout"
+	AuditActionUnlock        = "unlock"
+	AuditActionMFAEnable     = "mfa_enable"
+	AuditActionMFADisable    = "mfa_disable"
+	// AuditActionMFARecoveryCodes is a new set of recovery codes replacing
+	// the old one
+	AuditActionMFARecoveryCodes = "mfa_recovery_codes"
+	AuditActionRotate           = "rotate"
+	AuditActionRevoke           = "revoke"
+	// AuditActionLink and AuditActionUnlink add and remove the identity of
+	// an external provider to a user
+	AuditActionLink   = "link"
+	AuditActionUnlink = "unlink"
+	// AuditActionGrant is a user consenting to scopes of an OAuth client
+	AuditActionGrant = "grant"
+)
+
+// Entity types recorded in the audit log
+const (
+	AuditEntityUser          = "user"
+	AuditEntityProduct       = "product"
+	AuditEntityProductImport = "product_import"
+	AuditEntityCategory      = "category"
+	// AuditEntityLogin is the throttle key of an account or an IP, such as
+	// "account:ana@example.com" or "ip:203.0.113.9"
+	AuditEntityLogin  = "login"
+	AuditEntityAPIKey = "api_key"
+	// AuditEntityOAuthClient is identified by its client_id
+	AuditEntityOAuthClient = "oauth_client"
+)
+
+// AuditEvent is an entry of the append-only audit log. Each entry carries the
+// hash of the previous one, so editing or removing a row breaks the chain
+type AuditEvent struct {
+	ID         int64     `json:"id"`
+	OccurredAt time.Time `json:"occurred_at"`
+	// ActorID is nil for anonymous requests (e.g. sign up)
+	ActorID    *int   `json:"actor_id,omitempty"`
+	Action     string `json:"action"`
+	EntityType string `json:"entity_type"`
+	EntityID   string `json:"entity_id"`
+	// Changes maps each changed field to its before and after values, secrets redacted
+	Changes   json.RawMessage `json:"changes" swaggertype:"object"`
+	RequestID string          `json:"request_id,omitempty"`
+	IP        string          `json:"ip,omitempty"`
+	UserAgent string          `json:"user_agent,omitempty"`
+	PrevHash  string          `json:"prev_hash"`
+	Hash      string          `json:"hash"`
+}
+
+// AuditFilter holds the optional criteria accepted when listing audit events
+type AuditFilter struct {
+	ActorID    int
+	EntityType string
+	EntityID   string
+	// From is inclusive and To exclusive
+	From time.Time
+	To   time.Time
+	// BeforeID pages backwards from the newest events
+	BeforeID int64
+	Limit    int
+}
+
+// AuditVerification is the result of walking the hash chain
+type AuditVerification struct {
+	Valid   bool  `json:"valid"`
+	Checked int64 `json:"checked"`
+	// BrokenAt is the first event whose hash does not match, when the chain is broken
+	BrokenAt *int64 `json:"broken_at,omitempty"`
+	// LastHash is the hash of the newest event; kept somewhere else, it also
+	// reveals events removed from the end of the log
+	LastHash string `json:"last_hash,omitempty"`
+}
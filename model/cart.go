@@ -0,0 +1,56 @@
+package model
+
+import "time"
+
+// Cart holds the items a user, or an anonymous session, intends to buy
+type Cart struct {
+	ID int `json:"id"`
+	// UserID is nil for anonymous carts, which are found by their session token
+	UserID *int `json:"user_id,omitempty"`
+	// Token is only set when an anonymous cart is created; just its hash is stored
+	Token     string     `json:"token,omitempty"`
+	Items     []CartItem `json:"items"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	// Subtotals sum the available items, one entry per currency in the cart
+	Subtotals []Money `json:"subtotals"`
+}
+
+// CartItem is a line of a cart: a product, or one of its variants, and a quantity
+type CartItem struct {
+	ID        int  `json:"id"`
+	CartID    int  `json:"cart_id"`
+	ProductID int  `json:"product_id"`
+	VariantID *int `json:"variant_id,omitempty"`
+	Quantity  int  `json:"quantity"`
+	// AddedPrice is the unit price when the item was last added, in the product currency
+	AddedPrice Money     `json:"added_price"`
+	AddedAt    time.Time `json:"added_at"`
+
+	// The fields below reflect the product as it is now and are filled in when the cart is read
+	ProductName string `json:"product_name"`
+	SKU         string `json:"sku,omitempty"`
+	UnitPrice   Money  `json:"unit_price"`
+	// Stock is only known for variants, products without variants are not stocked
+	Stock *int `json:"stock,omitempty"`
+	// Active is false once the product is in the trash
+	Active       bool  `json:"active"`
+	Subtotal     Money `json:"subtotal"`
+	PriceChanged bool  `json:"price_changed"`
+	// Available is false when the product is in the trash or the stock does not cover the quantity
+	Available bool `json:"available"`
+}
+
+// CartOwner identifies whose cart a request refers to: the authenticated user
+// or, for anonymous requests, the session token
+type CartOwner struct {
+	UserID int
+	Token  string
+}
+
+// CartItemInput holds the fields accepted when adding an item to a cart
+type CartItemInput struct {
+	ProductID int
+	// VariantID is required for products with variants and not allowed otherwise
+	VariantID *int
+	Quantity  int
+}
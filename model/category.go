@@ -0,0 +1,12 @@
+package model
+
+// Category is a node of the product taxonomy. Path is the materialized path
+// of slugs from the root down to the node (e.g. "roupas/camisetas").
+type Category struct {
+	ID       int        `json:"id"`
+	ParentID *int       `json:"parent_id"`
+	Name     string     `json:"name"`
+	Slug     string     `json:"slug"`
+	Path     string     `json:"path"`
+	Children []Category `json:"children,omitempty"`
+}
@@ -0,0 +1,30 @@
+package model
+
+import "time"
+
+// UserIdentity links a user to their account at an external identity
+// provider, found by the subject the provider gives it
+type UserIdentity struct {
+	ID       int    `json:"id"`
+	UserID   int    `json:"user_id"`
+	Provider string `json:"provider"`
+	Subject  string `json:"subject"`
+	// Email is the address the provider reported at the last sign in
+	Email       string     `json:"email,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	LastLoginAt *time.Time `json:"last_login_at,omitempty"`
+}
+
+// OIDCLoginState is a sign in started at an identity provider, waiting for
+// the user to come back with the code. It works once
+type OIDCLoginState struct {
+	// StateHash is the SHA-256 of the state sent to the provider
+	StateHash    string
+	Provider     string
+	Nonce        string
+	CodeVerifier string
+	// LinkUserID is the user linking the identity to their account, 0 for
+	// a sign in
+	LinkUserID int
+	ExpiresAt  time.Time
+}
@@ -0,0 +1,32 @@
+package model
+
+import "time"
+
+// ProductImage is an image of a product stored in the blob store
+type ProductImage struct {
+	ID        int `json:"id"`
+	ProductID int `json:"product_id"`
+	// Key locates the original upload in the blob store
+	Key         string `json:"key"`
+	ContentType string `json:"content_type"`
+	Width       int    `json:"width"`
+	Height      int    `json:"height"`
+	Size        int64  `json:"size"`
+	// Position orders the images of a product, starting at 0
+	Position int  `json:"position"`
+	Primary  bool `json:"primary"`
+	// Thumbnails are JPEG renditions of the image, smallest first
+	Thumbnails []ImageThumbnail `json:"thumbnails"`
+	CreatedAt  time.Time        `json:"created_at"`
+	// URL is resolved from the blob store when the image is read, it is not stored
+	URL string `json:"url,omitempty"`
+}
+
+// ImageThumbnail is a scaled-down rendition of a product image
+type ImageThumbnail struct {
+	Name   string `json:"name"`
+	Key    string `json:"key"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+	URL    string `json:"url,omitempty"`
+}
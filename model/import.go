@@ -0,0 +1,41 @@
+package model
+
+import "time"
+
+// Status of an import job
+const (
+	ImportStatusPending   = "pending"
+	ImportStatusRunning   = "running"
+	ImportStatusCompleted = "completed"
+	ImportStatusFailed    = "failed"
+)
+
+// ImportJob tracks the progress of a bulk product import running in the background
+type ImportJob struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+	Format string `json:"format"`
+	// DryRun jobs validate and match every row but write nothing; Created and
+	// Updated then count what the import would do
+	DryRun bool `json:"dry_run"`
+	// TotalBytes and ReadBytes measure progress, the row count is unknown until the end
+	TotalBytes int64 `json:"total_bytes"`
+	ReadBytes  int64 `json:"read_bytes"`
+	Rows       int   `json:"rows"`
+	Created    int   `json:"created"`
+	Updated    int   `json:"updated"`
+	Failed     int   `json:"failed"`
+	// Errors lists the rows that were rejected, in file order
+	Errors []ImportRowError `json:"errors"`
+	// Error is set when the whole job failed, e.g. on a malformed header
+	Error      string     `json:"error,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+// ImportRowError explains why a row of an import file was rejected
+type ImportRowError struct {
+	// Line is the line of the row in the file, starting at 1
+	Line    int    `json:"line"`
+	Message string `json:"message"`
+}
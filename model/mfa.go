@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+// UserMFA is the TOTP second factor of a user. The secret is stored
+// encrypted; the enrollment only counts once confirmed with a code
+type UserMFA struct {
+	UserID          int        `json:"user_id"`
+	SecretEncrypted string     `json:"-"`
+	ConfirmedAt     *time.Time `json:"confirmed_at,omitempty"`
+	// LastUsedStep is the time step of the last code accepted, so no code
+	// works twice
+	LastUsedStep int64 `json:"-"`
+}
@@ -0,0 +1,222 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+var (
+	ErrCurrencyMismatch = errors.New("money: currency mismatch")
+	ErrUnknownCurrency  = errors.New("money: unknown currency")
+	ErrInvalidAmount    = errors.New("money: invalid amount")
+	ErrAmountOutOfRange = errors.New("money: amount out of range")
+)
+
+// DefaultCurrency is used when a price is informed without a currency
+const DefaultCurrency = "BRL"
+
+// RoundingMode defines how a value is rounded to the minor unit of a currency
+type RoundingMode string
+
+const (
+	// RoundHalfUp rounds ties away from zero (0.125 -> 0.13)
+	RoundHalfUp RoundingMode = "half_up"
+	// RoundHalfEven rounds ties to the even neighbour (0.125 -> 0.12)
+	RoundHalfEven RoundingMode = "half_even"
+)
+
+// currencyExponents holds the ISO 4217 minor unit of the supported currencies
+var currencyExponents = map[string]int{
+	"ARS": 2, "AUD": 2, "BHD": 3, "BRL": 2, "CAD": 2, "CHF": 2, "CLP": 0,
+	"CNY": 2, "COP": 2, "EUR": 2, "GBP": 2, "JOD": 3, "JPY": 0, "KRW": 0,
+	"KWD": 3, "MXN": 2, "OMR": 3, "PYG": 0, "TND": 3, "USD": 2, "UYU": 2,
+}
+
+// CurrencyExponent returns the number of decimal places of the currency minor unit
+func CurrencyExponent(currency string) (int, error) {
+	exponent, ok := currencyExponents[currency]
+	if !ok {
+		return 0, fmt.Errorf("%w: %q", ErrUnknownCurrency, currency)
+	}
+	return exponent, nil
+}
+
+// Money is an exact monetary amount, kept as an integer number of minor units
+// (centavos, cents) of an ISO 4217 currency
+type Money struct {
+	Amount   int64
+	Currency string
+}
+
+// NewMoney builds a Money from an amount already expressed in minor units
+func NewMoney(minorUnits int64, currency string) (Money, error) {
+	currency = strings.ToUpper(currency)
+	if _, err := CurrencyExponent(currency); err != nil {
+		return Money{}, err
+	}
+	return Money{Amount: minorUnits, Currency: currency}, nil
+}
+
+// ParseMoney parses a decimal string ("29.99") without going through float64,
+// rounding half up to the minor unit of the currency
+func ParseMoney(amount, currency string) (Money, error) {
+	amount = strings.TrimSpace(amount)
+	if amount == "" || strings.Contains(amount, "/") {
+		return Money{}, fmt.Errorf("%w: %q", ErrInvalidAmount, amount)
+	}
+	value, ok := new(big.Rat).SetString(amount)
+	if !ok {
+		return Money{}, fmt.Errorf("%w: %q", ErrInvalidAmount, amount)
+	}
+	return MoneyFromRat(value, currency, RoundHalfUp)
+}
+
+// MoneyFromRat rounds an exact value to the minor unit of the currency
+func MoneyFromRat(value *big.Rat, currency string, mode RoundingMode) (Money, error) {
+	currency = strings.ToUpper(currency)
+	exponent, err := CurrencyExponent(currency)
+	if err != nil {
+		return Money{}, err
+	}
+
+	scaled := new(big.Rat).Mul(value, new(big.Rat).SetInt(pow10(exponent)))
+	minorUnits, ok := roundRat(scaled, mode)
+	if !ok {
+		return Money{}, fmt.Errorf("%w: out of range", ErrInvalidAmount)
+	}
+	return Money{Amount: minorUnits, Currency: currency}, nil
+}
+
+// Rat returns the exact value in major units
+func (m Money) Rat() *big.Rat {
+	exponent := currencyExponents[m.Currency]
+	return new(big.Rat).SetFrac(big.NewInt(m.Amount), pow10(exponent))
+}
+
+// String formats the amount in major units with the currency precision ("29.99")
+func (m Money) String() string {
+	exponent := currencyExponents[m.Currency]
+	return m.Rat().FloatString(exponent)
+}
+
+func (m Money) Add(other Money) (Money, error) {
+	if m.Currency != other.Currency {
+		return Money{}, fmt.Errorf("%w: %s + %s", ErrCurrencyMismatch, m.Currency, other.Currency)
+	}
+	sum := m.Amount + other.Amount
+	if (other.Amount > 0 && sum < m.Amount) || (other.Amount < 0 && sum > m.Amount) {
+		return Money{}, fmt.Errorf("%w: %s + %s", ErrAmountOutOfRange, m, other)
+	}
+	return Money{Amount: sum, Currency: m.Currency}, nil
+}
+
+func (m Money) Sub(other Money) (Money, error) {
+	if m.Currency != other.Currency {
+		return Money{}, fmt.Errorf("%w: %s - %s", ErrCurrencyMismatch, m.Currency, other.Currency)
+	}
+	difference := m.Amount - other.Amount
+	if (other.Amount > 0 && difference > m.Amount) || (other.Amount < 0 && difference < m.Amount) {
+		return Money{}, fmt.Errorf("%w: %s - %s", ErrAmountOutOfRange, m, other)
+	}
+	return Money{Amount: difference, Currency: m.Currency}, nil
+}
+
+// Mul multiplies the amount by an integer quantity
+func (m Money) Mul(quantity int64) (Money, error) {
+	product := new(big.Int).Mul(big.NewInt(m.Amount), big.NewInt(quantity))
+	if !product.IsInt64() {
+		return Money{}, fmt.Errorf("%w: %s * %d", ErrAmountOutOfRange, m, quantity)
+	}
+	return Money{Amount: product.Int64(), Currency: m.Currency}, nil
+}
+
+// MulRat multiplies the amount by an exact factor (a percentage, a rate) and
+// rounds the result to the minor unit of the currency
+func (m Money) MulRat(factor *big.Rat, mode RoundingMode) (Money, error) {
+	scaled := new(big.Rat).Mul(new(big.Rat).SetInt64(m.Amount), factor)
+	minorUnits, ok := roundRat(scaled, mode)
+	if !ok {
+		return Money{}, fmt.Errorf("%w: %s * %s", ErrAmountOutOfRange, m, factor.RatString())
+	}
+	return Money{Amount: minorUnits, Currency: m.Currency}, nil
+}
+
+// Cmp compares two amounts of the same currency, returning -1, 0 or +1
+func (m Money) Cmp(other Money) (int, error) {
+	if m.Currency != other.Currency {
+		return 0, fmt.Errorf("%w: %s <> %s", ErrCurrencyMismatch, m.Currency, other.Currency)
+	}
+	switch {
+	case m.Amount < other.Amount:
+		return -1, nil
+	case m.Amount > other.Amount:
+		return 1, nil
+	default:
+		return 0, nil
+	}
+}
+
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+func (m Money) IsNegative() bool {
+	return m.Amount < 0
+}
+
+// MarshalJSON encodes the amount as a string so JavaScript clients don't lose precision
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Amount   string `json:"amount"`
+		Currency string `json:"currency"`
+	}{Amount: m.String(), Currency: m.Currency})
+}
+
+// UnmarshalJSON accepts the amount both as a string and as a JSON number
+func (m *Money) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Amount   json.Number `json:"amount"`
+		Currency string      `json:"currency"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if raw.Currency == "" {
+		raw.Currency = DefaultCurrency
+	}
+	parsed, err := ParseMoney(raw.Amount.String(), raw.Currency)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// --- Helper Functions ---
+
+func pow10(exponent int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(exponent)), nil)
+}
+
+// roundRat rounds the value to an integer using the given rounding mode
+func roundRat(value *big.Rat, mode RoundingMode) (int64, bool) {
+	quotient, remainder := new(big.Int).QuoRem(value.Num(), value.Denom(), new(big.Int))
+	if remainder.Sign() != 0 {
+		twice := new(big.Int).Lsh(new(big.Int).Abs(remainder), 1)
+		cmp := twice.Cmp(value.Denom())
+		if cmp > 0 || (cmp == 0 && (mode != RoundHalfEven || quotient.Bit(0) == 1)) {
+			if value.Sign() < 0 {
+				quotient.Sub(quotient, big.NewInt(1))
+			} else {
+				quotient.Add(quotient, big.NewInt(1))
+			}
+		}
+	}
+	if !quotient.IsInt64() {
+		return 0, false
+	}
+	return quotient.Int64(), true
+}
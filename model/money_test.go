@@ -0,0 +1,121 @@
+package model
+
+import (
+	"encoding/json"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMoney(t *testing.T) {
+	t.Run("Exact Decimal", func(t *testing.T) {
+		a, err := ParseMoney("0.1", "BRL")
+		assert.NoError(t, err)
+		b, err := ParseMoney("0.2", "BRL")
+		assert.NoError(t, err)
+
+		sum, err := a.Add(b)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(30), sum.Amount)
+		assert.Equal(t, "0.30", sum.String())
+	})
+
+	t.Run("Rounds To Currency Precision", func(t *testing.T) {
+		cases := []struct {
+			amount   string
+			currency string
+			expected string
+		}{
+			{"10.005", "BRL", "10.01"},
+			{"10.004", "usd", "10.00"},
+			{"-10.005", "BRL", "-10.01"},
+			{"1234.5", "JPY", "1235"},
+			{"1.2345", "KWD", "1.235"},
+			{"1e3", "EUR", "1000.00"},
+		}
+		for _, c := range cases {
+			money, err := ParseMoney(c.amount, c.currency)
+			assert.NoError(t, err)
+			assert.Equal(t, c.expected, money.String(), c.amount)
+		}
+	})
+
+	t.Run("Invalid Input", func(t *testing.T) {
+		_, err := ParseMoney("abc", "BRL")
+		assert.ErrorIs(t, err, ErrInvalidAmount)
+
+		_, err = ParseMoney("1/3", "BRL")
+		assert.ErrorIs(t, err, ErrInvalidAmount)
+
+		_, err = ParseMoney("1.00", "XYZ")
+		assert.ErrorIs(t, err, ErrUnknownCurrency)
+	})
+}
+
+func TestMoney_Arithmetic(t *testing.T) {
+	brl := Money{Amount: 1000, Currency: "BRL"}
+	usd := Money{Amount: 1000, Currency: "USD"}
+
+	t.Run("Currency Mismatch", func(t *testing.T) {
+		_, err := brl.Add(usd)
+		assert.ErrorIs(t, err, ErrCurrencyMismatch)
+
+		_, err = brl.Sub(usd)
+		assert.ErrorIs(t, err, ErrCurrencyMismatch)
+
+		_, err = brl.Cmp(usd)
+		assert.ErrorIs(t, err, ErrCurrencyMismatch)
+	})
+
+	t.Run("Multiplication", func(t *testing.T) {
+		product, err := brl.Mul(3)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3000), product.Amount)
+
+		// 10.00 * 1/8 = 1.25 exactly; 0.05 / 2 = 0.025 is a tie that depends on the mode
+		product, err = brl.MulRat(big.NewRat(1, 8), RoundHalfUp)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(125), product.Amount)
+		tie := Money{Amount: 5, Currency: "BRL"}
+		product, _ = tie.MulRat(big.NewRat(1, 2), RoundHalfUp)
+		assert.Equal(t, int64(3), product.Amount)
+		product, _ = tie.MulRat(big.NewRat(1, 2), RoundHalfEven)
+		assert.Equal(t, int64(2), product.Amount)
+	})
+
+	t.Run("Out Of Range", func(t *testing.T) {
+		largest := Money{Amount: math.MaxInt64, Currency: "BRL"}
+		smallest := Money{Amount: math.MinInt64, Currency: "BRL"}
+		one := Money{Amount: 1, Currency: "BRL"}
+
+		_, err := largest.Add(one)
+		assert.ErrorIs(t, err, ErrAmountOutOfRange)
+
+		_, err = smallest.Sub(one)
+		assert.ErrorIs(t, err, ErrAmountOutOfRange)
+
+		_, err = largest.Mul(2)
+		assert.ErrorIs(t, err, ErrAmountOutOfRange)
+
+		_, err = largest.MulRat(big.NewRat(3, 2), RoundHalfUp)
+		assert.ErrorIs(t, err, ErrAmountOutOfRange)
+	})
+}
+
+func TestMoney_JSON(t *testing.T) {
+	t.Run("Marshal As String", func(t *testing.T) {
+		data, err := json.Marshal(Money{Amount: 99999, Currency: "BRL"})
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"amount": "999.99", "currency": "BRL"}`, string(data))
+	})
+
+	t.Run("Unmarshal String Or Number", func(t *testing.T) {
+		var fromString, fromNumber Money
+		assert.NoError(t, json.Unmarshal([]byte(`{"amount": "19.99", "currency": "USD"}`), &fromString))
+		assert.NoError(t, json.Unmarshal([]byte(`{"amount": 19.99, "currency": "USD"}`), &fromNumber))
+		assert.Equal(t, Money{Amount: 1999, Currency: "USD"}, fromString)
+		assert.Equal(t, fromString, fromNumber)
+	})
+}
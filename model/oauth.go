@@ -0,0 +1,94 @@
+package model
+
+import "time"
+
+// AccessTokenPrefix starts every OAuth access token, so they are told apart
+// from JWTs and API keys
+const AccessTokenPrefix = "gat_"
+
+// Grant types a client can be registered for
+const (
+	GrantTypeAuthorizationCode = "authorization_code"
+	GrantTypeClientCredentials = "client_credentials"
+)
+
+// OAuthGrantTypes lists the valid grant types
+var OAuthGrantTypes = []string{GrantTypeAuthorizationCode, GrantTypeClientCredentials}
+
+// OAuthClient is a third-party application registered to get access tokens.
+// Confidential clients authenticate with a secret, of which only the SHA-256
+// is stored; public ones, such as mobile apps, only rely on PKCE
+type OAuthClient struct {
+	ID       int    `json:"id"`
+	ClientID string `json:"client_id"`
+	Name     string `json:"name"`
+	// SecretHash is empty for public clients
+	SecretHash   string   `json:"-"`
+	RedirectURIs []string `json:"redirect_uris"`
+	GrantTypes   []string `json:"grant_types"`
+	// Scopes are the most the client can be granted
+	Scopes []string `json:"scopes"`
+	// UserID is the admin or service account the client acts as with the
+	// client credentials grant, nil without it
+	UserID    *int       `json:"user_id,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	CreatedBy *int       `json:"created_by,omitempty"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+}
+
+// Public tells that the client has no secret
+func (c OAuthClient) Public() bool {
+	return c.SecretHash == ""
+}
+
+// OAuthConsent records the scopes a user granted to a client, so the next
+// authorizations within them need not ask again
+type OAuthConsent struct {
+	UserID   int    `json:"user_id"`
+	ClientID string `json:"client_id"`
+	// ClientName is read along with the consent
+	ClientName string    `json:"client_name"`
+	Scopes     []string  `json:"scopes"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// OAuthCode is an authorization code waiting to be exchanged for an access
+// token. Only its SHA-256 is stored, and it works once
+type OAuthCode struct {
+	CodeHash    string
+	ClientID    string
+	UserID      int
+	RedirectURI string
+	Scopes      []string
+	// CodeChallenge is the S256 PKCE challenge the verifier must match
+	CodeChallenge string
+	// MFA tells that the login of the user passed a second factor
+	MFA       bool
+	ExpiresAt time.Time
+}
+
+// OAuthToken is an access token issued to a client, acting as a user within
+// the scopes. Only its SHA-256 is stored
+type OAuthToken struct {
+	ID        int
+	TokenHash string
+	ClientID  string
+	UserID    int
+	Scopes    []string
+	MFA       bool
+	CreatedAt time.Time
+	ExpiresAt time.Time
+	RevokedAt *time.Time
+}
+
+// OAuthPrincipal is who a valid access token authenticates
+type OAuthPrincipal struct {
+	TokenID  int
+	ClientID string
+	UserID   int
+	Email    string
+	Role     string
+	Scopes   []string
+	MFA      bool
+}
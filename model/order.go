@@ -0,0 +1,109 @@
+package model
+
+import "time"
+
+// Order statuses; an order starts pending and only moves along orderTransitions
+const (
+	OrderStatusPending   = "pending"
+	OrderStatusPaid      = "paid"
+	OrderStatusShipped   = "shipped"
+	OrderStatusDelivered = "delivered"
+	OrderStatusCancelled = "cancelled"
+	OrderStatusRefunded  = "refunded"
+)
+
+// orderTransitions lists the statuses each status may move to; cancelled and
+// refunded are final
+var orderTransitions = map[string][]string{
+	OrderStatusPending:   {OrderStatusPaid, OrderStatusCancelled},
+	OrderStatusPaid:      {OrderStatusShipped, OrderStatusRefunded},
+	OrderStatusShipped:   {OrderStatusDelivered},
+	OrderStatusDelivered: {OrderStatusRefunded},
+}
+
+// IsOrderStatus reports whether status is one of the order statuses
+func IsOrderStatus(status string) bool {
+	if status == OrderStatusCancelled || status == OrderStatusRefunded {
+		return true
+	}
+	_, ok := orderTransitions[status]
+	return ok
+}
+
+// IsManualOrderStatus reports whether an order may be moved to status by
+// hand; paid and refunded follow the payments of the order
+func IsManualOrderStatus(status string) bool {
+	return status == OrderStatusShipped || status == OrderStatusDelivered || status == OrderStatusCancelled
+}
+
+// CanTransitionOrder reports whether an order may move from one status to the other
+func CanTransitionOrder(from, to string) bool {
+	for _, allowed := range orderTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Order is a purchase: the items are a snapshot of the products at checkout
+type Order struct {
+	ID int `json:"id"`
+	// UserID is nil once the user who placed the order is purged
+	UserID *int        `json:"user_id,omitempty"`
+	Status string      `json:"status"`
+	Items  []OrderItem `json:"items"`
+	// Discount is what the promotions took off the items; Tax sums the
+	// taxes, already in the prices when TaxIncluded. Total is the sum of the
+	// items less the discount plus the taxes not included. Every item of an
+	// order shares its currency
+	Discount    Money     `json:"discount"`
+	Tax         Money     `json:"tax"`
+	TaxIncluded bool      `json:"tax_included"`
+	Total       Money     `json:"total"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	// TaxDestination is nil for orders placed without taxes
+	TaxDestination *TaxDestination `json:"tax_destination,omitempty"`
+	// Taxes itemizes Tax; only filled in when a single order is read
+	Taxes []TaxAmount `json:"taxes,omitempty"`
+	// Promotions lists the promotions and codes redeemed by the order
+	Promotions []AppliedPromotion `json:"promotions,omitempty"`
+	// History lists every status change, oldest first; only filled in when a single order is read
+	History []OrderTransition `json:"history,omitempty"`
+}
+
+// OrderItem is a line of an order with the product name and price at purchase time
+type OrderItem struct {
+	ID      int `json:"id"`
+	OrderID int `json:"order_id"`
+	// ProductID and VariantID are nil once the product or variant is purged
+	ProductID   *int   `json:"product_id,omitempty"`
+	VariantID   *int   `json:"variant_id,omitempty"`
+	ProductName string `json:"product_name"`
+	SKU         string `json:"sku,omitempty"`
+	UnitPrice   Money  `json:"unit_price"`
+	Quantity    int    `json:"quantity"`
+	Subtotal    Money  `json:"subtotal"`
+	// Discount is the share of the order discount given to the item
+	Discount Money `json:"discount"`
+	// Tax is the tax of the item after its discount
+	Tax Money `json:"tax"`
+}
+
+// OrderTransition records a status change of an order
+type OrderTransition struct {
+	// From is empty for the creation of the order
+	From string `json:"from,omitempty"`
+	To   string `json:"to"`
+	// ActorID is the user who made the change, nil for system changes
+	ActorID    *int      `json:"actor_id,omitempty"`
+	Note       string    `json:"note,omitempty"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+// OrderFilter holds the optional criteria accepted when listing orders
+type OrderFilter struct {
+	UserID int
+	Status string
+}
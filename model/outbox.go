@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+// OutboxEmail is an email waiting in the outbox. It is written in the same
+// transaction as the change that sends it, so a rolled back change sends
+// nothing and a committed one is delivered even if the mailer is down
+type OutboxEmail struct {
+	ID        int64  `json:"id"`
+	Recipient string `json:"recipient"`
+	Subject   string `json:"subject"`
+	Body      string `json:"-"`
+	// Attempts counts the deliveries tried, including the one in progress
+	Attempts      int        `json:"attempts"`
+	LastError     string     `json:"last_error,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	NextAttemptAt time.Time  `json:"next_attempt_at"`
+	SentAt        *time.Time `json:"sent_at,omitempty"`
+}
@@ -0,0 +1,12 @@
+package model
+
+import "time"
+
+// PasswordResetToken lets a user who forgot the password choose a new one.
+// Only the SHA-256 of the token is stored; the token itself is only in the
+// email sent to the user
+type PasswordResetToken struct {
+	UserID    int       `json:"user_id"`
+	TokenHash string    `json:"-"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
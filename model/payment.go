@@ -0,0 +1,25 @@
+package model
+
+import "time"
+
+// Payment statuses, as kept for each attempt to pay an order
+const (
+	PaymentStatusPending   = "pending"
+	PaymentStatusSucceeded = "succeeded"
+	PaymentStatusFailed    = "failed"
+	PaymentStatusRefunded  = "refunded"
+)
+
+// Payment is an attempt to pay an order through a payment provider
+type Payment struct {
+	ID      int `json:"id"`
+	OrderID int `json:"order_id"`
+	// Provider is the gateway that holds the intent
+	Provider string `json:"provider"`
+	// IntentID identifies the payment at the provider
+	IntentID  string    `json:"intent_id"`
+	Status    string    `json:"status"`
+	Amount    Money     `json:"amount"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,62 @@
+package model
+
+import (
+	"math/big"
+	"time"
+)
+
+// PriceList groups explicit product prices for a currency, optionally scoped to a market
+type PriceList struct {
+	ID       int    `json:"id"`
+	Code     string `json:"code"`
+	Name     string `json:"name"`
+	Currency string `json:"currency"`
+	Market   string `json:"market,omitempty"`
+}
+
+// PriceListItem is the explicit price of a product in a price list
+type PriceListItem struct {
+	PriceListID int   `json:"price_list_id"`
+	ProductID   int   `json:"product_id"`
+	Price       Money `json:"price"`
+}
+
+// ListPrice is a PriceListItem resolved for a product listing
+type ListPrice struct {
+	PriceListCode string
+	Price         Money
+}
+
+// ExchangeRate converts one unit of Base into Rate units of Quote
+type ExchangeRate struct {
+	Base      string    `json:"base"`
+	Quote     string    `json:"quote"`
+	Rate      *big.Rat  `json:"-"`
+	Source    string    `json:"source"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// PriceOptions selects how product prices are presented
+type PriceOptions struct {
+	// Currency requested by the client, empty keeps the product currency
+	Currency string
+	// Market prefers the price lists of the market over the generic ones
+	Market string
+	// AsOf resolves the price history at a past or future instant, zero means now
+	AsOf time.Time
+}
+
+const (
+	PriceSourcePriceList    = "price_list"
+	PriceSourceExchangeRate = "exchange_rate"
+)
+
+// PriceConversion explains how a product price was obtained in the requested currency
+type PriceConversion struct {
+	Source        string       `json:"source"`
+	PriceListCode string       `json:"price_list,omitempty"`
+	Original      Money        `json:"original_price"`
+	Rate          *big.Rat     `json:"-"`
+	RateTimestamp *time.Time   `json:"rate_timestamp,omitempty"`
+	Rounding      RoundingMode `json:"rounding,omitempty"`
+}
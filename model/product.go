@@ -1,7 +1,86 @@
 package model
 
+import "time"
+
 type Product struct {
-	ID    int     `json:"id"`
-	Name  string  `json:"product_name"`
-	Price float64 `json:"price"`
+	ID   int    `json:"id"`
+	Name string `json:"product_name"`
+	// SKU is optional and unique among products; imports match on it before the name
+	SKU   string `json:"sku,omitempty"`
+	Price Money  `json:"price"`
+	// TaxCategory selects the tax rates of the product, "standard" unless assigned
+	TaxCategory string `json:"tax_category,omitempty"`
+	// Conversion is set when Price was resolved for a currency other than the product's own
+	Conversion *PriceConversion `json:"conversion,omitempty"`
+	// Options and Variants form the variant matrix, empty for products without variants
+	Options  []ProductOption `json:"options,omitempty"`
+	Variants []Variant       `json:"variants,omitempty"`
+	// Images are ordered by position, the primary image is flagged
+	Images []ProductImage `json:"images,omitempty"`
+	// UpdatedAt changes with the product record and its price history;
+	// CreatedBy and UpdatedBy are nil for anonymous changes
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	CreatedBy *int      `json:"created_by,omitempty"`
+	UpdatedBy *int      `json:"updated_by,omitempty"`
+	// DeletedAt is set while the product is in the trash
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+}
+
+// ProductFilter holds the optional criteria accepted when listing products
+type ProductFilter struct {
+	// CategoryID restricts the listing to the category and its descendants
+	CategoryID int
+	// CategoryPath does the same using the category materialized path
+	CategoryPath string
+	// UpdatedSince keeps the products changed at or after the instant, for incremental sync
+	UpdatedSince time.Time
+}
+
+const (
+	// PriceKindRegular is the list price of the product, in effect until superseded
+	PriceKindRegular = "regular"
+	// PriceKindSale is a temporary price that overrides the regular one within its window
+	PriceKindSale = "sale"
+)
+
+// Status of a price history entry relative to the current time
+const (
+	PriceStatusScheduled  = "scheduled"
+	PriceStatusActive     = "active"
+	PriceStatusOverridden = "overridden"
+	PriceStatusSuperseded = "superseded"
+	PriceStatusExpired    = "expired"
+)
+
+// ProductPrice is an entry of the append-only price history of a product
+type ProductPrice struct {
+	ID            int        `json:"id"`
+	ProductID     int        `json:"product_id"`
+	Price         Money      `json:"price"`
+	Kind          string     `json:"kind"`
+	EffectiveFrom time.Time  `json:"effective_from"`
+	EffectiveTo   *time.Time `json:"effective_to,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	// Status is computed when the timeline is read, it is not stored
+	Status string `json:"status,omitempty"`
+}
+
+// ProductImportBatch holds the writes of one batch of an import, applied in a single transaction
+type ProductImportBatch struct {
+	Create []Product
+	// Update renames existing products and sets their SKU when the row has one
+	Update []Product
+	// Reprice records a new regular price for existing products whose price changed
+	Reprice []Product
+}
+
+// PriceChange is a price change requested for a product, in the product currency
+type PriceChange struct {
+	Amount string
+	Kind   string
+	// EffectiveFrom defaults to now
+	EffectiveFrom time.Time
+	// EffectiveTo is required for sale prices and not allowed for regular ones
+	EffectiveTo *time.Time
 }
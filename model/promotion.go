@@ -0,0 +1,126 @@
+package model
+
+import "time"
+
+// Discount kinds of a promotion
+const (
+	DiscountPercentage = "percentage"
+	DiscountFixed      = "fixed"
+)
+
+// Promotion is a discount rule: a discount code when it has a Code, otherwise
+// an automatic promotion applied to every cart and order that qualifies
+type Promotion struct {
+	ID int `json:"id"`
+	// Code is kept in upper case and matched without regard to case
+	Code string `json:"code,omitempty"`
+	Name string `json:"name"`
+	Kind string `json:"kind"`
+	// Percent is the discount of percentage promotions, from 1 to 100
+	Percent int `json:"percent,omitempty"`
+	// Amount is the discount of fixed promotions, spread over the eligible items
+	Amount *Money `json:"amount,omitempty"`
+	// MinTotal is the order subtotal needed for the promotion to apply
+	MinTotal *Money `json:"min_total,omitempty"`
+	// ProductIDs and CategoryIDs restrict the eligible items; both empty means
+	// every item. A category also covers its subcategories
+	ProductIDs  []int `json:"product_ids"`
+	CategoryIDs []int `json:"category_ids"`
+	// UsageLimit bounds the redemptions of everyone, PerUserLimit those of each user
+	UsageLimit   *int `json:"usage_limit,omitempty"`
+	PerUserLimit *int `json:"per_user_limit,omitempty"`
+	Uses         int  `json:"uses"`
+	// StartsAt and EndsAt bound the validity; EndsAt is exclusive
+	StartsAt *time.Time `json:"starts_at,omitempty"`
+	EndsAt   *time.Time `json:"ends_at,omitempty"`
+	// Stackable promotions combine with each other; the others apply alone
+	Stackable bool      `json:"stackable"`
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Currency returns the currency the promotion is restricted to, empty when a
+// percentage without a minimum total applies in any currency
+func (p Promotion) Currency() string {
+	if p.Amount != nil {
+		return p.Amount.Currency
+	}
+	if p.MinTotal != nil {
+		return p.MinTotal.Currency
+	}
+	return ""
+}
+
+// PricingLine is an item priced by the promotion engine
+type PricingLine struct {
+	ProductID int
+	VariantID *int
+	Quantity  int
+	UnitPrice Money
+}
+
+// PricingResult is the breakdown of the promotions and taxes applied to a
+// cart or order. Total is the subtotal less the discount plus the taxes not
+// included in the prices
+type PricingResult struct {
+	Lines    []PricedLine       `json:"lines"`
+	Applied  []AppliedPromotion `json:"applied"`
+	Rejected []RejectedCode     `json:"rejected"`
+	Subtotal Money              `json:"subtotal"`
+	Discount Money              `json:"discount"`
+	Total    Money              `json:"total"`
+	// Tax is nil when no destination was given to tax the lines
+	Tax *TaxBreakdown `json:"tax,omitempty"`
+}
+
+// PricedLine is an item with the discount each promotion gave it
+type PricedLine struct {
+	ProductID   int          `json:"product_id"`
+	VariantID   *int         `json:"variant_id,omitempty"`
+	Quantity    int          `json:"quantity"`
+	Subtotal    Money        `json:"subtotal"`
+	Discount    Money        `json:"discount"`
+	Total       Money        `json:"total"`
+	Adjustments []Adjustment `json:"adjustments"`
+}
+
+// Adjustment is the share of a promotion in the discount of a line
+type Adjustment struct {
+	PromotionID int    `json:"promotion_id"`
+	Code        string `json:"code,omitempty"`
+	Name        string `json:"name"`
+	Amount      Money  `json:"amount"`
+}
+
+// AppliedPromotion is a promotion applied to the whole cart or order
+type AppliedPromotion struct {
+	PromotionID int    `json:"promotion_id"`
+	Code        string `json:"code,omitempty"`
+	Name        string `json:"name"`
+	Discount    Money  `json:"discount"`
+}
+
+// RejectedCode is a discount code that was not applied, and why
+type RejectedCode struct {
+	Code   string `json:"code"`
+	Reason string `json:"reason"`
+}
+
+// PromotionInput holds the fields accepted when creating a promotion; the
+// amounts are decimal strings in Currency
+type PromotionInput struct {
+	Code         string
+	Name         string
+	Kind         string
+	Percent      int
+	Amount       string
+	MinTotal     string
+	Currency     string
+	ProductIDs   []int
+	CategoryIDs  []int
+	UsageLimit   *int
+	PerUserLimit *int
+	StartsAt     *time.Time
+	EndsAt       *time.Time
+	Stackable    bool
+}
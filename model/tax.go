@@ -0,0 +1,61 @@
+package model
+
+import "time"
+
+// TaxDestination is where an order is delivered; it selects the tax rates
+type TaxDestination struct {
+	// Country is the ISO 3166-1 alpha-2 code
+	Country string `json:"country"`
+	// State narrows the rates down for countries that tax by state
+	State string `json:"state,omitempty"`
+}
+
+// TaxRegion is the rate table in effect for a destination at a given time
+type TaxRegion struct {
+	Destination TaxDestination `json:"destination"`
+	// Version identifies the rate table the rates come from
+	Version string `json:"version,omitempty"`
+	// PricesIncludeTax is true where prices already include the taxes
+	PricesIncludeTax bool      `json:"prices_include_tax"`
+	Rounding         string    `json:"rounding"`
+	Rates            []TaxRate `json:"rates"`
+}
+
+// TaxRate is one tax of a category, e.g. ICMS at 18% for the standard category
+type TaxRate struct {
+	Name     string `json:"name"`
+	Category string `json:"category"`
+	// Percent is a decimal, e.g. "18" or "8.875"
+	Percent       string     `json:"percent"`
+	EffectiveFrom time.Time  `json:"effective_from"`
+	EffectiveTo   *time.Time `json:"effective_to,omitempty"`
+}
+
+// TaxBreakdown is the itemized tax of a cart or order
+type TaxBreakdown struct {
+	Destination TaxDestination `json:"destination"`
+	// Included is true when the prices already include the taxes, which then
+	// do not add to the total
+	Included bool   `json:"included"`
+	Rounding string `json:"rounding"`
+	// Lines follow the order of the lines taxed
+	Lines []LineTax   `json:"lines"`
+	Taxes []TaxAmount `json:"taxes"`
+	Total Money       `json:"total"`
+}
+
+// LineTax is the tax of a line after its discount
+type LineTax struct {
+	Category string `json:"category"`
+	// Net is the amount of the line without the taxes
+	Net   Money       `json:"net"`
+	Tax   Money       `json:"tax"`
+	Taxes []TaxAmount `json:"taxes"`
+}
+
+// TaxAmount is how much of one tax a line, cart or order pays
+type TaxAmount struct {
+	Name    string `json:"name"`
+	Percent string `json:"percent"`
+	Amount  Money  `json:"amount"`
+}
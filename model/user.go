@@ -1,8 +1,41 @@
 package model
 
+import "time"
+
+const (
+	RoleCustomer = "customer"
+	RoleAdmin    = "admin"
+	// RoleService is a service account: it only calls the API with API keys
+	// and cannot log in with a password
+	RoleService = "service"
+)
+
 type User struct {
 	ID       int    `json:"id"`
 	Name     string `json:"name"`
 	Email    string `json:"email"`
 	Password string `json:"-"`
+	Role     string `json:"role"`
+	// EmailVerifiedAt is nil until the user follows the link sent to Email
+	EmailVerifiedAt *time.Time `json:"email_verified_at,omitempty"`
+	// PendingEmail holds a requested email change until the link sent to the
+	// new address is followed; Email stays in use meanwhile
+	PendingEmail string `json:"pending_email,omitempty"`
+	// MFAEnabled tells that login asks for a second factor; only filled in
+	// where the login needs it
+	MFAEnabled bool `json:"mfa_enabled,omitempty"`
+	// CreatedBy and UpdatedBy are the users who made the changes, nil when
+	// anonymous (e.g. sign up)
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	CreatedBy *int      `json:"created_by,omitempty"`
+	UpdatedBy *int      `json:"updated_by,omitempty"`
+	// DeletedAt is set while the user is in the trash
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+}
+
+// UserFilter holds the optional criteria accepted when listing users
+type UserFilter struct {
+	// UpdatedSince keeps the users changed at or after the instant, for incremental sync
+	UpdatedSince time.Time
 }
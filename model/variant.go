@@ -0,0 +1,53 @@
+package model
+
+// OptionType is an axis along which a product varies, e.g. size or color
+type OptionType struct {
+	ID     int           `json:"id"`
+	Name   string        `json:"name"`
+	Values []OptionValue `json:"values"`
+}
+
+// OptionValue is one of the values of an option type, e.g. "M" for size
+type OptionValue struct {
+	ID           int    `json:"id"`
+	OptionTypeID int    `json:"option_type_id"`
+	Value        string `json:"value"`
+	Position     int    `json:"position"`
+}
+
+// VariantOption is the value a variant takes for one option type
+type VariantOption struct {
+	OptionTypeID  int    `json:"option_type_id"`
+	OptionType    string `json:"option_type"`
+	OptionValueID int    `json:"option_value_id"`
+	Value         string `json:"value"`
+}
+
+// Variant is a purchasable combination of option values of a product
+type Variant struct {
+	ID        int    `json:"id"`
+	ProductID int    `json:"product_id"`
+	SKU       string `json:"sku"`
+	// PriceOverride replaces the product price for this variant, in the product currency
+	PriceOverride *Money          `json:"price_override,omitempty"`
+	Stock         int             `json:"stock"`
+	Options       []VariantOption `json:"options"`
+	// Price is the resolved price of the variant, filled in when the product is read
+	Price Money `json:"price"`
+}
+
+// ProductOption is one axis of the variant matrix of a product with the values in use
+type ProductOption struct {
+	Name   string   `json:"name"`
+	Values []string `json:"values"`
+}
+
+// VariantInput holds the fields accepted when creating or updating a variant
+type VariantInput struct {
+	SKU string
+	// PriceOverride is a decimal amount in the product currency, nil inherits the product price
+	PriceOverride *string
+	Stock         int
+	// OptionValueIDs is only used on creation, the options of a variant never change
+	OptionValueIDs []int
+}
@@ -0,0 +1,175 @@
+package repository
+
+import (
+	"database/sql"
+	"go-api/model"
+	"strconv"
+
+	"github.com/lib/pq"
+)
+
+// APIKeyRepositoryInterface defines the contract for the API keys
+type APIKeyRepositoryInterface interface {
+	CreateAPIKey(key model.APIKey, event model.AuditEvent) (int, error)
+	GetAPIKeys(userID int) ([]model.APIKey, error)
+	GetAPIKeyByID(id int) (*model.APIKey, error)
+	GetAPIKeyByPrefix(prefix string) (*model.APIKey, error)
+	RotateAPIKey(id int, prefix, keyHash string, event model.AuditEvent) error
+	RevokeAPIKey(id int, event model.AuditEvent) error
+	TouchAPIKey(id int, ip string) error
+}
+
+type APIKeyRepository struct {
+	connection *sql.DB
+}
+
+// Ensure APIKeyRepository implements APIKeyRepositoryInterface
+var _ APIKeyRepositoryInterface = (*APIKeyRepository)(nil)
+
+func NewAPIKeyRepository(connection *sql.DB) APIKeyRepositoryInterface {
+	return &APIKeyRepository{
+		connection: connection,
+	}
+}
+
+const selectAPIKeys = `SELECT id, user_id, name, prefix, key_hash, scopes, allowed_ips, expires_at,
+	last_used_at, COALESCE(last_used_ip, ''), created_at, created_by, rotated_at, revoked_at FROM api_keys`
+
+// CreateAPIKey stores a new key, of which only the prefix and the hash are
+// kept, and returns its ID
+func (ar *APIKeyRepository) CreateAPIKey(key model.APIKey, event model.AuditEvent) (int, error) {
+	var id int
+	err := withAuditEvent(ar.connection, &event, func(tx *sql.Tx) error {
+		err := tx.QueryRow(`INSERT INTO api_keys (user_id, name, prefix, key_hash, scopes, allowed_ips, expires_at, created_by)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id`,
+			key.UserID, key.Name, key.Prefix, key.KeyHash, pq.Array(key.Scopes), pq.Array(key.AllowedIPs), key.ExpiresAt, event.ActorID).Scan(&id)
+		event.EntityID = strconv.Itoa(id)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+// GetAPIKeys lists the keys of the user, revoked ones included, or of every
+// user when userID is 0
+func (ar *APIKeyRepository) GetAPIKeys(userID int) ([]model.APIKey, error) {
+	rows, err := ar.connection.Query(selectAPIKeys+` WHERE $1 = 0 OR user_id = $1 ORDER BY id`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := []model.APIKey{}
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, *key)
+	}
+	return keys, rows.Err()
+}
+
+func (ar *APIKeyRepository) GetAPIKeyByID(id int) (*model.APIKey, error) {
+	key, err := scanAPIKey(ar.connection.QueryRow(selectAPIKeys+` WHERE id = $1`, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return key, nil
+}
+
+// GetAPIKeyByPrefix finds the key a request presents by its visible prefix
+func (ar *APIKeyRepository) GetAPIKeyByPrefix(prefix string) (*model.APIKey, error) {
+	key, err := scanAPIKey(ar.connection.QueryRow(selectAPIKeys+` WHERE prefix = $1`, prefix))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return key, nil
+}
+
+// RotateAPIKey replaces the secret of a key, which keeps its settings; the
+// old secret stops working at once. It returns sql.ErrNoRows when the key
+// does not exist or was revoked
+func (ar *APIKeyRepository) RotateAPIKey(id int, prefix, keyHash string, event model.AuditEvent) error {
+	return withAuditEvent(ar.connection, &event, func(tx *sql.Tx) error {
+		result, err := tx.Exec(`UPDATE api_keys SET prefix = $2, key_hash = $3, rotated_at = NOW()
+			WHERE id = $1 AND revoked_at IS NULL`, id, prefix, keyHash)
+		if err != nil {
+			return err
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return sql.ErrNoRows
+		}
+		return nil
+	})
+}
+
+// RevokeAPIKey turns a key off for good, returning sql.ErrNoRows when it
+// does not exist or was already revoked
+func (ar *APIKeyRepository) RevokeAPIKey(id int, event model.AuditEvent) error {
+	return withAuditEvent(ar.connection, &event, func(tx *sql.Tx) error {
+		result, err := tx.Exec(`UPDATE api_keys SET revoked_at = NOW() WHERE id = $1 AND revoked_at IS NULL`, id)
+		if err != nil {
+			return err
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return sql.ErrNoRows
+		}
+		return nil
+	})
+}
+
+// TouchAPIKey records the use of a key. It writes at most once a minute per
+// key, so busy integrations do not turn every request into an UPDATE
+func (ar *APIKeyRepository) TouchAPIKey(id int, ip string) error {
+	_, err := ar.connection.Exec(`UPDATE api_keys SET last_used_at = NOW(), last_used_ip = $2
+		WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < NOW() - INTERVAL '1 minute' OR last_used_ip <> $2)`, id, ip)
+	return err
+}
+
+// --- Helper Functions ---
+
+func scanAPIKey(row rowScanner) (*model.APIKey, error) {
+	var key model.APIKey
+	var expiresAt, lastUsedAt, rotatedAt, revokedAt sql.NullTime
+	var createdBy sql.NullInt64
+	err := row.Scan(&key.ID, &key.UserID, &key.Name, &key.Prefix, &key.KeyHash, pq.Array(&key.Scopes), pq.Array(&key.AllowedIPs),
+		&expiresAt, &lastUsedAt, &key.LastUsedIP, &key.CreatedAt, &createdBy, &rotatedAt, &revokedAt)
+	if err != nil {
+		return nil, err
+	}
+	if expiresAt.Valid {
+		key.ExpiresAt = &expiresAt.Time
+	}
+	if lastUsedAt.Valid {
+		key.LastUsedAt = &lastUsedAt.Time
+	}
+	if createdBy.Valid {
+		id := int(createdBy.Int64)
+		key.CreatedBy = &id
+	}
+	if rotatedAt.Valid {
+		key.RotatedAt = &rotatedAt.Time
+	}
+	if revokedAt.Valid {
+		key.RevokedAt = &revokedAt.Time
+	}
+	return &key, nil
+}
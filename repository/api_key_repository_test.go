@@ -0,0 +1,176 @@
+package repository
+
+import (
+	"database/sql"
+	"go-api/model"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+var apiKeyColumns = []string{"id", "user_id", "name", "prefix", "key_hash", "scopes", "allowed_ips", "expires_at",
+	"last_used_at", "last_used_ip", "created_at", "created_by", "rotated_at", "revoked_at"}
+
+func TestAPIKeyRepository_CreateAPIKey(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	actorID := 1
+	expiresAt := time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)
+	key := model.APIKey{
+		UserID:     9,
+		Name:       "Armazém",
+		Prefix:     "gak_3f9a1c0b7d2e",
+		KeyHash:    "hash",
+		Scopes:     []string{model.ScopeProductsRead},
+		AllowedIPs: []string{"203.0.113.0/24"},
+		ExpiresAt:  &expiresAt,
+	}
+	event := model.AuditEvent{ActorID: &actorID, Action: model.AuditActionCreate, EntityType: model.AuditEntityAPIKey}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO api_keys (user_id, name, prefix, key_hash, scopes, allowed_ips, expires_at, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id`)).
+		WithArgs(9, "Armazém", "gak_3f9a1c0b7d2e", "hash", pq.Array([]string{model.ScopeProductsRead}), pq.Array([]string{"203.0.113.0/24"}), &expiresAt, &actorID).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+	event.EntityID = "4"
+	expectAuditEvent(mock, "", event)
+	mock.ExpectCommit()
+
+	repo := NewAPIKeyRepository(db)
+	id, err := repo.CreateAPIKey(key, model.AuditEvent{ActorID: &actorID, Action: model.AuditActionCreate, EntityType: model.AuditEntityAPIKey})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 4, id)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAPIKeyRepository_GetAPIKeyByPrefix(t *testing.T) {
+	query := regexp.QuoteMeta("SELECT id, user_id, name, prefix, key_hash, scopes, allowed_ips, expires_at, last_used_at, COALESCE(last_used_ip, ''), created_at, created_by, rotated_at, revoked_at FROM api_keys WHERE prefix = $1")
+
+	t.Run("Found", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		createdAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+		mock.ExpectQuery(query).
+			WithArgs("gak_3f9a1c0b7d2e").
+			WillReturnRows(sqlmock.NewRows(apiKeyColumns).
+				AddRow(4, 9, "Armazém", "gak_3f9a1c0b7d2e", "hash", "{products:read,products:write}", "{}", nil, nil, "", createdAt, 1, nil, nil))
+
+		repo := NewAPIKeyRepository(db)
+		key, err := repo.GetAPIKeyByPrefix("gak_3f9a1c0b7d2e")
+
+		assert.NoError(t, err)
+		createdBy := 1
+		assert.Equal(t, &model.APIKey{
+			ID:         4,
+			UserID:     9,
+			Name:       "Armazém",
+			Prefix:     "gak_3f9a1c0b7d2e",
+			KeyHash:    "hash",
+			Scopes:     []string{model.ScopeProductsRead, model.ScopeProductsWrite},
+			AllowedIPs: []string{},
+			CreatedAt:  createdAt,
+			CreatedBy:  &createdBy,
+		}, key)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectQuery(query).WithArgs("gak_000000000000").WillReturnError(sql.ErrNoRows)
+
+		repo := NewAPIKeyRepository(db)
+		key, err := repo.GetAPIKeyByPrefix("gak_000000000000")
+
+		assert.NoError(t, err)
+		assert.Nil(t, key)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestAPIKeyRepository_RotateAPIKey(t *testing.T) {
+	query := regexp.QuoteMeta(`UPDATE api_keys SET prefix = $2, key_hash = $3, rotated_at = NOW()
+			WHERE id = $1 AND revoked_at IS NULL`)
+	event := model.AuditEvent{Action: model.AuditActionRotate, EntityType: model.AuditEntityAPIKey, EntityID: "4"}
+
+	t.Run("Success", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(query).WithArgs(4, "gak_0123456789ab", "new hash").WillReturnResult(sqlmock.NewResult(0, 1))
+		expectAuditEvent(mock, "", event)
+		mock.ExpectCommit()
+
+		repo := NewAPIKeyRepository(db)
+		err = repo.RotateAPIKey(4, "gak_0123456789ab", "new hash", event)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Revoked", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(query).WithArgs(4, "gak_0123456789ab", "new hash").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		repo := NewAPIKeyRepository(db)
+		err = repo.RotateAPIKey(4, "gak_0123456789ab", "new hash", event)
+
+		assert.Equal(t, sql.ErrNoRows, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestAPIKeyRepository_RevokeAPIKey(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	event := model.AuditEvent{Action: model.AuditActionRevoke, EntityType: model.AuditEntityAPIKey, EntityID: "4"}
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE api_keys SET revoked_at = NOW() WHERE id = $1 AND revoked_at IS NULL")).
+		WithArgs(4).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectAuditEvent(mock, "", event)
+	mock.ExpectCommit()
+
+	repo := NewAPIKeyRepository(db)
+	err = repo.RevokeAPIKey(4, event)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAPIKeyRepository_TouchAPIKey(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE api_keys SET last_used_at = NOW(), last_used_ip = $2
+		WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < NOW() - INTERVAL '1 minute' OR last_used_ip <> $2)`)).
+		WithArgs(4, "203.0.113.9").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	repo := NewAPIKeyRepository(db)
+	err = repo.TouchAPIKey(4, "203.0.113.9")
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
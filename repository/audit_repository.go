@@ -0,0 +1,183 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"go-api/internal/audit"
+	"go-api/model"
+	"strings"
+	"time"
+)
+
+// AuditRepositoryInterface defines the contract for reading the audit log.
+// Events are written by the other repositories, in the transaction of the
+// change they describe; RecordAuditEvent is for events with no change in
+// the database, such as lockouts
+type AuditRepositoryInterface interface {
+	GetAuditEvents(filter model.AuditFilter) ([]model.AuditEvent, error)
+	WalkAuditEvents(ctx context.Context, fn func(model.AuditEvent) error) error
+	RecordAuditEvent(event model.AuditEvent) error
+}
+
+type AuditRepository struct {
+	connection *sql.DB
+}
+
+// Ensure AuditRepository implements AuditRepositoryInterface
+var _ AuditRepositoryInterface = (*AuditRepository)(nil)
+
+func NewAuditRepository(connection *sql.DB) AuditRepositoryInterface {
+	return &AuditRepository{
+		connection: connection,
+	}
+}
+
+// auditLockKey is the advisory lock that serializes appends, so every event
+// chains to the one committed right before it
+const auditLockKey = 7206583
+
+const selectAuditEvents = `SELECT id, occurred_at, actor_id, action, entity_type, entity_id, changes, request_id, ip, user_agent, prev_hash, hash FROM audit_events`
+
+// appendAuditEvent records the event in the transaction of the change it
+// describes, so the change and its audit entry are committed together
+func appendAuditEvent(tx *sql.Tx, event model.AuditEvent) error {
+	if _, err := tx.Exec(`SELECT pg_advisory_xact_lock($1)`, auditLockKey); err != nil {
+		return err
+	}
+
+	var prevHash string
+	err := tx.QueryRow(`SELECT hash FROM audit_events ORDER BY id DESC LIMIT 1`).Scan(&prevHash)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+
+	// Postgres keeps microseconds; the hash must cover the stored instant
+	event.OccurredAt = time.Now().UTC().Truncate(time.Microsecond)
+	if len(event.Changes) == 0 {
+		event.Changes = json.RawMessage("{}")
+	}
+	hash, err := audit.Hash(prevHash, event)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`INSERT INTO audit_events (
+		occurred_at, actor_id, action, entity_type, entity_id, changes, request_id, ip, user_agent, prev_hash, hash
+	) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`,
+		event.OccurredAt, event.ActorID, event.Action, event.EntityType, event.EntityID, string(event.Changes),
+		event.RequestID, event.IP, event.UserAgent, prevHash, hash)
+	return err
+}
+
+// withAuditEvent runs write and appends the event in the same transaction;
+// write may still fill in the event, e.g. with the ID of a created entity
+func withAuditEvent(connection *sql.DB, event *model.AuditEvent, write func(tx *sql.Tx) error) error {
+	tx, err := connection.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := write(tx); err != nil {
+		return err
+	}
+	if err := appendAuditEvent(tx, *event); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// nullableInt converts a nullable integer column to a pointer, nil for NULL
+func nullableInt(id sql.NullInt64) *int {
+	if !id.Valid {
+		return nil
+	}
+	value := int(id.Int64)
+	return &value
+}
+
+func scanAuditEvent(row rowScanner) (model.AuditEvent, error) {
+	var event model.AuditEvent
+	var actor sql.NullInt64
+	var changes []byte
+	err := row.Scan(&event.ID, &event.OccurredAt, &actor, &event.Action, &event.EntityType, &event.EntityID,
+		&changes, &event.RequestID, &event.IP, &event.UserAgent, &event.PrevHash, &event.Hash)
+	if err != nil {
+		return model.AuditEvent{}, err
+	}
+	event.OccurredAt = event.OccurredAt.UTC()
+	event.ActorID = nullableInt(actor)
+	event.Changes = json.RawMessage(changes)
+	return event, nil
+}
+
+// GetAuditEvents returns the events matching the filter, newest first
+func (ar *AuditRepository) GetAuditEvents(filter model.AuditFilter) ([]model.AuditEvent, error) {
+	var conditions []string
+	var args []interface{}
+	addCondition := func(condition string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+	if filter.ActorID != 0 {
+		addCondition("actor_id = $%d", filter.ActorID)
+	}
+	if filter.EntityType != "" {
+		addCondition("entity_type = $%d", filter.EntityType)
+	}
+	if filter.EntityID != "" {
+		addCondition("entity_id = $%d", filter.EntityID)
+	}
+	if !filter.From.IsZero() {
+		addCondition("occurred_at >= $%d", filter.From)
+	}
+	if !filter.To.IsZero() {
+		addCondition("occurred_at < $%d", filter.To)
+	}
+	if filter.BeforeID != 0 {
+		addCondition("id < $%d", filter.BeforeID)
+	}
+
+	query := selectAuditEvents
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	args = append(args, filter.Limit)
+	query += fmt.Sprintf(" ORDER BY id DESC LIMIT $%d", len(args))
+
+	rows, err := ar.connection.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []model.AuditEvent
+	for rows.Next() {
+		event, err := scanAuditEvent(rows)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
+
+// WalkAuditEvents streams the whole log, oldest first, to fn
+func (ar *AuditRepository) WalkAuditEvents(ctx context.Context, fn func(model.AuditEvent) error) error {
+	return streamCursor(ctx, ar.connection, selectAuditEvents+" ORDER BY id", nil, func(row rowScanner) error {
+		event, err := scanAuditEvent(row)
+		if err != nil {
+			return err
+		}
+		return fn(event)
+	})
+}
+
+// RecordAuditEvent appends an event on its own
+func (ar *AuditRepository) RecordAuditEvent(event model.AuditEvent) error {
+	return withAuditEvent(ar.connection, &event, func(tx *sql.Tx) error {
+		return nil
+	})
+}
@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"go-api/internal/audit"
+	"go-api/model"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+// argFunc matches a query argument with a function
+type argFunc func(driver.Value) bool
+
+func (f argFunc) Match(value driver.Value) bool {
+	return f(value)
+}
+
+// expectAuditEvent expects the event to be appended after prevHash, with a
+// hash that chains the stored fields to it
+func expectAuditEvent(mock sqlmock.Sqlmock, prevHash string, event model.AuditEvent) {
+	mock.ExpectExec(regexp.QuoteMeta("SELECT pg_advisory_xact_lock($1)")).
+		WithArgs(auditLockKey).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	rows := sqlmock.NewRows([]string{"hash"})
+	if prevHash != "" {
+		rows.AddRow(prevHash)
+	}
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT hash FROM audit_events ORDER BY id DESC LIMIT 1")).
+		WillReturnRows(rows)
+
+	if len(event.Changes) == 0 {
+		event.Changes = json.RawMessage("{}")
+	}
+	var actorID driver.Value
+	if event.ActorID != nil {
+		actorID = int64(*event.ActorID)
+	}
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO audit_events")).
+		WithArgs(
+			argFunc(func(value driver.Value) bool {
+				occurredAt, ok := value.(time.Time)
+				event.OccurredAt = occurredAt
+				return ok
+			}),
+			actorID, event.Action, event.EntityType, event.EntityID, string(event.Changes),
+			event.RequestID, event.IP, event.UserAgent, prevHash,
+			argFunc(func(value driver.Value) bool {
+				hash, err := audit.Hash(prevHash, event)
+				return err == nil && value == hash
+			}),
+		).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+}
+
+var auditEventColumns = []string{"id", "occurred_at", "actor_id", "action", "entity_type", "entity_id", "changes", "request_id", "ip", "user_agent", "prev_hash", "hash"}
+
+func TestAuditRepository_GetAuditEvents(t *testing.T) {
+	t.Run("Filters", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+		to := from.AddDate(0, 1, 0)
+		rows := sqlmock.NewRows(auditEventColumns).
+			AddRow(41, from.Add(time.Hour), 7, "update", "user", "1", []byte(`{"name":{"before":"a","after":"b"}}`), "req-1", "10.0.0.1", "curl/8.0", "aa", "bb").
+			AddRow(40, from, nil, "create", "user", "1", []byte(`{}`), "", "", "", "", "aa")
+
+		mock.ExpectQuery(regexp.QuoteMeta(selectAuditEvents+" WHERE actor_id = $1 AND entity_type = $2 AND entity_id = $3 AND occurred_at >= $4 AND occurred_at < $5 AND id < $6 ORDER BY id DESC LIMIT $7")).
+			WithArgs(7, "user", "1", from, to, int64(42), 10).
+			WillReturnRows(rows)
+
+		repo := NewAuditRepository(db)
+		events, err := repo.GetAuditEvents(model.AuditFilter{ActorID: 7, EntityType: "user", EntityID: "1", From: from, To: to, BeforeID: 42, Limit: 10})
+
+		assert.NoError(t, err)
+		assert.Len(t, events, 2)
+		assert.Equal(t, 7, *events[0].ActorID)
+		assert.JSONEq(t, `{"name":{"before":"a","after":"b"}}`, string(events[0].Changes))
+		assert.Nil(t, events[1].ActorID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("No Filters", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectQuery(regexp.QuoteMeta(selectAuditEvents + " ORDER BY id DESC LIMIT $1")).
+			WithArgs(100).
+			WillReturnRows(sqlmock.NewRows(auditEventColumns))
+
+		repo := NewAuditRepository(db)
+		events, err := repo.GetAuditEvents(model.AuditFilter{Limit: 100})
+
+		assert.NoError(t, err)
+		assert.Empty(t, events)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestAuditRepository_WalkAuditEvents(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DECLARE export_cursor NO SCROLL CURSOR FOR " + selectAuditEvents + " ORDER BY id")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("FETCH 500 FROM export_cursor").
+		WillReturnRows(sqlmock.NewRows(auditEventColumns).
+			AddRow(1, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), 7, "create", "product", "3", []byte(`{}`), "", "", "", "", "aa"))
+	mock.ExpectCommit()
+
+	var ids []int64
+	repo := NewAuditRepository(db)
+	err = repo.WalkAuditEvents(context.Background(), func(event model.AuditEvent) error {
+		ids = append(ids, event.ID)
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{1}, ids)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAuditRepository_RecordAuditEvent(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	event := model.AuditEvent{Action: model.AuditActionLockout, EntityType: model.AuditEntityLogin, EntityID: "account:ana@example.com", IP: "203.0.113.9"}
+	mock.ExpectBegin()
+	expectAuditEvent(mock, "aa", event)
+	mock.ExpectCommit()
+
+	err = NewAuditRepository(db).RecordAuditEvent(event)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
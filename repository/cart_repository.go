@@ -0,0 +1,208 @@
+package repository
+
+import (
+	"database/sql"
+	"go-api/model"
+	"time"
+)
+
+// CartRepositoryInterface defines the contract for shopping carts and their items
+type CartRepositoryInterface interface {
+	GetCartByUserID(userID int) (*model.Cart, error)
+	GetCartByTokenHash(tokenHash string) (*model.Cart, error)
+	CreateCart(userID *int, tokenHash string) (*model.Cart, error)
+	GetCartItems(cartID int, asOf time.Time) ([]model.CartItem, error)
+	SaveCartItem(item model.CartItem) error
+	UpdateCartItemQuantity(cartID, itemID, quantity int) error
+	DeleteCartItem(cartID, itemID int) error
+	ClaimCart(cartID, userID int) error
+	MergeCarts(sourceID, targetID int) error
+}
+
+type CartRepository struct {
+	connection *sql.DB
+}
+
+// Ensure CartRepository implements CartRepositoryInterface
+var _ CartRepositoryInterface = (*CartRepository)(nil)
+
+func NewCartRepository(connection *sql.DB) CartRepositoryInterface {
+	return &CartRepository{
+		connection: connection,
+	}
+}
+
+const selectCarts = `SELECT id, user_id, updated_at FROM carts`
+
+// selectCartItems joins each item with the product and variant as they are
+// now: the price in effect at $1, the variant override winning over it
+const selectCartItems = `SELECT ci.id, ci.cart_id, ci.product_id, ci.variant_id, ci.quantity, ci.price, ci.added_at,
+		p.product_name, COALESCE(v.sku, p.sku), COALESCE(v.price_override, rp.price, p.price), p.currency, v.stock, p.deleted_at IS NULL
+	FROM cart_items ci
+	JOIN products p ON p.id = ci.product_id
+	LEFT JOIN product_variants v ON v.id = ci.variant_id
+	` + resolvedPriceJoin
+
+func (cr *CartRepository) queryCart(condition string, args ...interface{}) (*model.Cart, error) {
+	var cart model.Cart
+	var userID sql.NullInt64
+	err := cr.connection.QueryRow(selectCarts+" "+condition, args...).Scan(&cart.ID, &userID, &cart.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	cart.UserID = nullableInt(userID)
+	return &cart, nil
+}
+
+func (cr *CartRepository) GetCartByUserID(userID int) (*model.Cart, error) {
+	return cr.queryCart("WHERE user_id = $1", userID)
+}
+
+func (cr *CartRepository) GetCartByTokenHash(tokenHash string) (*model.Cart, error) {
+	return cr.queryCart("WHERE token_hash = $1", tokenHash)
+}
+
+// CreateCart creates an empty cart owned by the user or, when userID is nil,
+// by the anonymous session whose token hashes to tokenHash
+func (cr *CartRepository) CreateCart(userID *int, tokenHash string) (*model.Cart, error) {
+	cart := model.Cart{UserID: userID}
+	err := cr.connection.QueryRow(`INSERT INTO carts (user_id, token_hash) VALUES ($1, NULLIF($2, '')) RETURNING id, updated_at`,
+		userID, tokenHash).Scan(&cart.ID, &cart.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &cart, nil
+}
+
+// GetCartItems returns the items of the cart in the order they were first added
+func (cr *CartRepository) GetCartItems(cartID int, asOf time.Time) ([]model.CartItem, error) {
+	rows, err := cr.connection.Query(selectCartItems+" WHERE ci.cart_id = $2 ORDER BY ci.id", asOf, cartID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []model.CartItem{}
+	for rows.Next() {
+		var item model.CartItem
+		var variantID, stock sql.NullInt64
+		var sku sql.NullString
+		var addedPrice, unitPrice, currency string
+		err := rows.Scan(&item.ID, &item.CartID, &item.ProductID, &variantID, &item.Quantity, &addedPrice, &item.AddedAt,
+			&item.ProductName, &sku, &unitPrice, &currency, &stock, &item.Active)
+		if err != nil {
+			return nil, err
+		}
+		if item.AddedPrice, err = model.ParseMoney(addedPrice, currency); err != nil {
+			return nil, err
+		}
+		if item.UnitPrice, err = model.ParseMoney(unitPrice, currency); err != nil {
+			return nil, err
+		}
+		item.VariantID = nullableInt(variantID)
+		item.Stock = nullableInt(stock)
+		item.SKU = sku.String
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// SaveCartItem adds the item to its cart; when the cart already has a line for
+// the same product and variant, the quantity is replaced and the added price
+// refreshed
+func (cr *CartRepository) SaveCartItem(item model.CartItem) error {
+	tx, err := cr.connection.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`INSERT INTO cart_items (cart_id, product_id, variant_id, quantity, price) VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (cart_id, product_id, (COALESCE(variant_id, 0)))
+		DO UPDATE SET quantity = EXCLUDED.quantity, price = EXCLUDED.price, added_at = NOW()`,
+		item.CartID, item.ProductID, item.VariantID, item.Quantity, item.AddedPrice.String())
+	if err != nil {
+		return err
+	}
+	if err := touchCart(tx, item.CartID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// UpdateCartItemQuantity changes the quantity of an item, keeping its added price
+func (cr *CartRepository) UpdateCartItemQuantity(cartID, itemID, quantity int) error {
+	return cr.changeCartItem(cartID, `UPDATE cart_items SET quantity = $3 WHERE cart_id = $1 AND id = $2`, cartID, itemID, quantity)
+}
+
+func (cr *CartRepository) DeleteCartItem(cartID, itemID int) error {
+	return cr.changeCartItem(cartID, `DELETE FROM cart_items WHERE cart_id = $1 AND id = $2`, cartID, itemID)
+}
+
+// changeCartItem runs a statement on one item of the cart and reports
+// sql.ErrNoRows when the item is not in the cart
+func (cr *CartRepository) changeCartItem(cartID int, query string, args ...interface{}) error {
+	tx, err := cr.connection.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(query, args...)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	if err := touchCart(tx, cartID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// ClaimCart hands an anonymous cart over to the user, dropping its token
+func (cr *CartRepository) ClaimCart(cartID, userID int) error {
+	_, err := cr.connection.Exec(`UPDATE carts SET user_id = $2, token_hash = NULL, updated_at = NOW() WHERE id = $1`, cartID, userID)
+	return err
+}
+
+// MergeCarts moves the items of the source cart into the target cart and
+// deletes the source. Lines present in both carts add up their quantities and
+// keep the most recently added price
+func (cr *CartRepository) MergeCarts(sourceID, targetID int) error {
+	tx, err := cr.connection.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`INSERT INTO cart_items (cart_id, product_id, variant_id, quantity, price, added_at)
+		SELECT $2, product_id, variant_id, quantity, price, added_at FROM cart_items WHERE cart_id = $1
+		ON CONFLICT (cart_id, product_id, (COALESCE(variant_id, 0)))
+		DO UPDATE SET quantity = cart_items.quantity + EXCLUDED.quantity,
+			price = CASE WHEN EXCLUDED.added_at > cart_items.added_at THEN EXCLUDED.price ELSE cart_items.price END,
+			added_at = GREATEST(cart_items.added_at, EXCLUDED.added_at)`, sourceID, targetID)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`DELETE FROM carts WHERE id = $1`, sourceID); err != nil {
+		return err
+	}
+	if err := touchCart(tx, targetID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func touchCart(tx *sql.Tx, cartID int) error {
+	_, err := tx.Exec(`UPDATE carts SET updated_at = NOW() WHERE id = $1`, cartID)
+	return err
+}
@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"database/sql"
+	"go-api/model"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+var cartItemRowColumns = []string{"id", "cart_id", "product_id", "variant_id", "quantity", "price", "added_at",
+	"product_name", "sku", "current_price", "currency", "stock", "active"}
+
+func TestCartRepository_GetCartItems(t *testing.T) {
+	t.Run("Reads Current Prices", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		addedAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+		rows := sqlmock.NewRows(cartItemRowColumns).
+			AddRow(1, 4, 1, 2, 2, "54.900", addedAt, "Camiseta", "CAM-AZUL-M", "59.900", "BRL", 3, true).
+			AddRow(2, 4, 3, nil, 1, "19.900", addedAt, "Caneca", nil, "19.900", "BRL", nil, false)
+		mock.ExpectQuery(`FROM cart_items ci JOIN products p ON p.id = ci.product_id LEFT JOIN product_variants v ON v.id = ci.variant_id LEFT JOIN LATERAL .* WHERE ci.cart_id = \$2 ORDER BY ci.id`).
+			WithArgs(productAsOf, 4).
+			WillReturnRows(rows)
+
+		repo := NewCartRepository(db)
+		items, err := repo.GetCartItems(4, productAsOf)
+
+		assert.NoError(t, err)
+		assert.Len(t, items, 2)
+		assert.Equal(t, model.Money{Amount: 5490, Currency: "BRL"}, items[0].AddedPrice)
+		assert.Equal(t, model.Money{Amount: 5990, Currency: "BRL"}, items[0].UnitPrice)
+		assert.Equal(t, 2, *items[0].VariantID)
+		assert.Equal(t, 3, *items[0].Stock)
+		assert.True(t, items[0].Active)
+		assert.Nil(t, items[1].VariantID)
+		assert.Nil(t, items[1].Stock)
+		assert.Equal(t, "", items[1].SKU)
+		assert.False(t, items[1].Active)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestCartRepository_SaveCartItem(t *testing.T) {
+	t.Run("Upserts The Line", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		variantID := 2
+		mock.ExpectBegin()
+		mock.ExpectExec(`INSERT INTO cart_items \(cart_id, product_id, variant_id, quantity, price\) VALUES \(\$1, \$2, \$3, \$4, \$5\) ON CONFLICT \(cart_id, product_id, \(COALESCE\(variant_id, 0\)\)\) DO UPDATE SET quantity = EXCLUDED.quantity`).
+			WithArgs(4, 1, int64(2), 3, "54.90").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`UPDATE carts SET updated_at = NOW\(\) WHERE id = \$1`).
+			WithArgs(4).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		repo := NewCartRepository(db)
+		err = repo.SaveCartItem(model.CartItem{
+			CartID:     4,
+			ProductID:  1,
+			VariantID:  &variantID,
+			Quantity:   3,
+			AddedPrice: model.Money{Amount: 5490, Currency: "BRL"},
+		})
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestCartRepository_DeleteCartItem(t *testing.T) {
+	t.Run("Item Of Another Cart", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(`DELETE FROM cart_items WHERE cart_id = \$1 AND id = \$2`).
+			WithArgs(4, 9).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		repo := NewCartRepository(db)
+		err = repo.DeleteCartItem(4, 9)
+
+		assert.Equal(t, sql.ErrNoRows, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestCartRepository_MergeCarts(t *testing.T) {
+	t.Run("Moves Items And Deletes Source", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(`INSERT INTO cart_items .* SELECT \$2, product_id, variant_id, quantity, price, added_at FROM cart_items WHERE cart_id = \$1 ON CONFLICT .* quantity = cart_items.quantity \+ EXCLUDED.quantity`).
+			WithArgs(9, 4).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectExec(`DELETE FROM carts WHERE id = \$1`).
+			WithArgs(9).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`UPDATE carts SET updated_at = NOW\(\) WHERE id = \$1`).
+			WithArgs(4).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		repo := NewCartRepository(db)
+		assert.NoError(t, repo.MergeCarts(9, 4))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
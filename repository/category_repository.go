@@ -0,0 +1,256 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"go-api/model"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrCategoryCycle is returned when the new parent of a category is the category itself or one of its descendants
+	ErrCategoryCycle = errors.New("new parent is inside the subtree of the category")
+	// ErrCategoryChanged is returned when the category or its new parent was moved, renamed or deleted since they were read
+	ErrCategoryChanged = errors.New("category changed concurrently")
+)
+
+// CategoryRepositoryInterface defines the contract for the category repository
+type CategoryRepositoryInterface interface {
+	GetCategories() ([]model.Category, error)
+	GetCategoryByID(id int) (*model.Category, error)
+	GetCategoryByPath(path string) (*model.Category, error)
+	GetDescendants(path string) ([]model.Category, error)
+	CountChildren(id int) (int, error)
+	CreateCategory(category model.Category, event model.AuditEvent) (int, error)
+	UpdateCategory(category model.Category, oldPath string, event model.AuditEvent) error
+	DeleteCategory(id int, event model.AuditEvent) error
+	SetProductCategories(productID int, categoryIDs []int, event model.AuditEvent) error
+	GetProductCategories(productID int) ([]model.Category, error)
+}
+
+type CategoryRepository struct {
+	connection *sql.DB
+}
+
+// Ensure CategoryRepository implements CategoryRepositoryInterface
+var _ CategoryRepositoryInterface = (*CategoryRepository)(nil)
+
+func NewCategoryRepository(connection *sql.DB) CategoryRepositoryInterface {
+	return &CategoryRepository{
+		connection: connection,
+	}
+}
+
+const categoryColumns = "id, parent_id, name, slug, path"
+
+// rowScanner is implemented by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanCategory(scanner rowScanner) (model.Category, error) {
+	var category model.Category
+	var parentID sql.NullInt64
+	err := scanner.Scan(&category.ID, &parentID, &category.Name, &category.Slug, &category.Path)
+	if err != nil {
+		return model.Category{}, err
+	}
+	if parentID.Valid {
+		id := int(parentID.Int64)
+		category.ParentID = &id
+	}
+	return category, nil
+}
+
+func (cr *CategoryRepository) queryCategories(query string, args ...interface{}) ([]model.Category, error) {
+	rows, err := cr.connection.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var categories []model.Category
+	for rows.Next() {
+		category, err := scanCategory(rows)
+		if err != nil {
+			return nil, err
+		}
+		categories = append(categories, category)
+	}
+	return categories, rows.Err()
+}
+
+func (cr *CategoryRepository) queryCategory(query string, args ...interface{}) (*model.Category, error) {
+	category, err := scanCategory(cr.connection.QueryRow(query, args...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &category, nil
+}
+
+func (cr *CategoryRepository) GetCategories() ([]model.Category, error) {
+	return cr.queryCategories("SELECT " + categoryColumns + " FROM categories ORDER BY path")
+}
+
+func (cr *CategoryRepository) GetCategoryByID(id int) (*model.Category, error) {
+	return cr.queryCategory("SELECT "+categoryColumns+" FROM categories WHERE id = $1", id)
+}
+
+func (cr *CategoryRepository) GetCategoryByPath(path string) (*model.Category, error) {
+	return cr.queryCategory("SELECT "+categoryColumns+" FROM categories WHERE path = $1", path)
+}
+
+// GetDescendants returns every category below the given path, ordered by path
+func (cr *CategoryRepository) GetDescendants(path string) ([]model.Category, error) {
+	return cr.queryCategories("SELECT "+categoryColumns+" FROM categories WHERE path LIKE $1 || '/%' ORDER BY path", path)
+}
+
+func (cr *CategoryRepository) CountChildren(id int) (int, error) {
+	var count int
+	err := cr.connection.QueryRow(`SELECT COUNT(*) FROM categories WHERE parent_id = $1`, id).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (cr *CategoryRepository) CreateCategory(category model.Category, event model.AuditEvent) (int, error) {
+	var id int
+	err := withAuditEvent(cr.connection, &event, func(tx *sql.Tx) error {
+		err := tx.QueryRow(`INSERT INTO categories (parent_id, name, slug, path) VALUES ($1, $2, $3, $4) RETURNING id`,
+			category.ParentID, category.Name, category.Slug, category.Path).Scan(&id)
+		if err != nil {
+			return err
+		}
+		event.EntityID = strconv.Itoa(id)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// UpdateCategory saves the node and rewrites the materialized path of its whole
+// subtree when the path changed (rename of the slug or move to another parent).
+// The node, its parent and the ancestors of the parent stay locked until the
+// commit, so a concurrent move cannot turn the subtree into a cycle
+func (cr *CategoryRepository) UpdateCategory(category model.Category, oldPath string, event model.AuditEvent) error {
+	return withAuditEvent(cr.connection, &event, func(tx *sql.Tx) error {
+		var path string
+		err := tx.QueryRow(`SELECT path FROM categories WHERE id = $1 FOR UPDATE`, category.ID).Scan(&path)
+		if err == sql.ErrNoRows || (err == nil && path != oldPath) {
+			return ErrCategoryChanged
+		}
+		if err != nil {
+			return err
+		}
+
+		if category.Path != oldPath && category.ParentID != nil {
+			if err := lockAncestors(tx, category, oldPath); err != nil {
+				return err
+			}
+		}
+
+		_, err = tx.Exec(`UPDATE categories SET parent_id = $1, name = $2, slug = $3 WHERE id = $4`,
+			category.ParentID, category.Name, category.Slug, category.ID)
+		if err != nil {
+			return err
+		}
+
+		if category.Path != oldPath {
+			_, err = tx.Exec(`UPDATE categories SET path = $1 || substr(path, $2) WHERE path = $3 OR path LIKE $3 || '/%'`,
+				category.Path, len(oldPath)+1, oldPath)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// lockAncestors locks the new parent of the category and every ancestor of it,
+// failing when the category is one of them or the parent no longer sits where
+// the new path expects
+func lockAncestors(tx *sql.Tx, category model.Category, oldPath string) error {
+	var parentPath string
+	err := tx.QueryRow(`SELECT path FROM categories WHERE id = $1 FOR UPDATE`, *category.ParentID).Scan(&parentPath)
+	if err == sql.ErrNoRows {
+		return ErrCategoryChanged
+	}
+	if err != nil {
+		return err
+	}
+	if parentPath == oldPath || strings.HasPrefix(parentPath, oldPath+"/") {
+		return ErrCategoryCycle
+	}
+	if category.Path != parentPath+"/"+category.Slug {
+		return ErrCategoryChanged
+	}
+
+	rows, err := tx.Query(`SELECT id FROM categories WHERE $1 LIKE path || '/%' ORDER BY path FOR UPDATE`, parentPath)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return err
+		}
+		if id == category.ID {
+			return ErrCategoryCycle
+		}
+	}
+	return rows.Err()
+}
+
+func (cr *CategoryRepository) DeleteCategory(id int, event model.AuditEvent) error {
+	return withAuditEvent(cr.connection, &event, func(tx *sql.Tx) error {
+		_, err := tx.Exec(`DELETE FROM categories WHERE id = $1`, id)
+		return err
+	})
+}
+
+// SetProductCategories replaces the categories assigned to a product outside
+// the trash and touches the product, returning sql.ErrNoRows when it is gone
+func (cr *CategoryRepository) SetProductCategories(productID int, categoryIDs []int, event model.AuditEvent) error {
+	return withAuditEvent(cr.connection, &event, func(tx *sql.Tx) error {
+		result, err := tx.Exec(`UPDATE products SET updated_at = NOW(), updated_by = $2 WHERE id = $1 AND deleted_at IS NULL`,
+			productID, event.ActorID)
+		if err != nil {
+			return err
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return sql.ErrNoRows
+		}
+
+		_, err = tx.Exec(`DELETE FROM product_categories WHERE product_id = $1`, productID)
+		if err != nil {
+			return err
+		}
+		for _, categoryID := range categoryIDs {
+			_, err = tx.Exec(`INSERT INTO product_categories (product_id, category_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`,
+				productID, categoryID)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (cr *CategoryRepository) GetProductCategories(productID int) ([]model.Category, error) {
+	return cr.queryCategories(`SELECT c.id, c.parent_id, c.name, c.slug, c.path FROM categories c
+		JOIN product_categories pc ON pc.category_id = c.id
+		WHERE pc.product_id = $1 ORDER BY c.path`, productID)
+}
@@ -0,0 +1,255 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"go-api/model"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+var categoryRowColumns = []string{"id", "parent_id", "name", "slug", "path"}
+
+func TestCategoryRepository_GetCategories(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		rows := sqlmock.NewRows(categoryRowColumns).
+			AddRow(1, nil, "Roupas", "roupas", "roupas").
+			AddRow(2, 1, "Camisetas", "camisetas", "roupas/camisetas")
+
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT id, parent_id, name, slug, path FROM categories ORDER BY path")).
+			WillReturnRows(rows)
+
+		repo := NewCategoryRepository(db)
+		categories, err := repo.GetCategories()
+
+		assert.NoError(t, err)
+		assert.Len(t, categories, 2)
+		assert.Nil(t, categories[0].ParentID)
+		assert.Equal(t, 1, *categories[1].ParentID)
+		assert.Equal(t, "roupas/camisetas", categories[1].Path)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Database Error", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectQuery("SELECT id, parent_id, name, slug, path FROM categories").
+			WillReturnError(errors.New("connection failed"))
+
+		repo := NewCategoryRepository(db)
+		categories, err := repo.GetCategories()
+
+		assert.Error(t, err)
+		assert.Nil(t, categories)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestCategoryRepository_GetCategoryByID(t *testing.T) {
+	t.Run("Not Found", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT id, parent_id, name, slug, path FROM categories WHERE id = $1")).
+			WithArgs(99).
+			WillReturnRows(sqlmock.NewRows(categoryRowColumns))
+
+		repo := NewCategoryRepository(db)
+		category, err := repo.GetCategoryByID(99)
+
+		assert.NoError(t, err)
+		assert.Nil(t, category)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestCategoryRepository_CreateCategory(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		parentID := 1
+		category := model.Category{ParentID: &parentID, Name: "Camisetas", Slug: "camisetas", Path: "roupas/camisetas"}
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO categories (parent_id, name, slug, path) VALUES ($1, $2, $3, $4) RETURNING id")).
+			WithArgs(&parentID, category.Name, category.Slug, category.Path).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+		expectAuditEvent(mock, "", model.AuditEvent{Action: model.AuditActionCreate, EntityType: model.AuditEntityCategory, EntityID: "2"})
+		mock.ExpectCommit()
+
+		repo := NewCategoryRepository(db)
+		id, err := repo.CreateCategory(category, model.AuditEvent{Action: model.AuditActionCreate, EntityType: model.AuditEntityCategory})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, id)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestCategoryRepository_UpdateCategory(t *testing.T) {
+	event := model.AuditEvent{Action: model.AuditActionUpdate, EntityType: model.AuditEntityCategory, EntityID: "2"}
+
+	t.Run("Moves Subtree Paths", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		parentID := 3
+		category := model.Category{ID: 2, ParentID: &parentID, Name: "Camisetas", Slug: "camisetas", Path: "moda/camisetas"}
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT path FROM categories WHERE id = $1 FOR UPDATE")).
+			WithArgs(2).
+			WillReturnRows(sqlmock.NewRows([]string{"path"}).AddRow("roupas/camisetas"))
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT path FROM categories WHERE id = $1 FOR UPDATE")).
+			WithArgs(3).
+			WillReturnRows(sqlmock.NewRows([]string{"path"}).AddRow("moda"))
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM categories WHERE $1 LIKE path || '/%' ORDER BY path FOR UPDATE")).
+			WithArgs("moda").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE categories SET parent_id = $1, name = $2, slug = $3 WHERE id = $4")).
+			WithArgs(&parentID, category.Name, category.Slug, category.ID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE categories SET path = $1 || substr(path, $2) WHERE path = $3 OR path LIKE $3 || '/%'")).
+			WithArgs("moda/camisetas", len("roupas/camisetas")+1, "roupas/camisetas").
+			WillReturnResult(sqlmock.NewResult(0, 3))
+		expectAuditEvent(mock, "", event)
+		mock.ExpectCommit()
+
+		repo := NewCategoryRepository(db)
+		err = repo.UpdateCategory(category, "roupas/camisetas", event)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Parent Moved Under The Category", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		parentID := 3
+		category := model.Category{ID: 2, ParentID: &parentID, Name: "Camisetas", Slug: "camisetas", Path: "moda/camisetas"}
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT path FROM categories WHERE id = $1 FOR UPDATE")).
+			WithArgs(2).
+			WillReturnRows(sqlmock.NewRows([]string{"path"}).AddRow("roupas/camisetas"))
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT path FROM categories WHERE id = $1 FOR UPDATE")).
+			WithArgs(3).
+			WillReturnRows(sqlmock.NewRows([]string{"path"}).AddRow("roupas/camisetas/moda"))
+		mock.ExpectRollback()
+
+		repo := NewCategoryRepository(db)
+		err = repo.UpdateCategory(category, "roupas/camisetas", event)
+
+		assert.ErrorIs(t, err, ErrCategoryCycle)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Category Moved Meanwhile", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		category := model.Category{ID: 2, Name: "Camisetas", Slug: "camisetas", Path: "camisetas"}
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT path FROM categories WHERE id = $1 FOR UPDATE")).
+			WithArgs(2).
+			WillReturnRows(sqlmock.NewRows([]string{"path"}).AddRow("moda/camisetas"))
+		mock.ExpectRollback()
+
+		repo := NewCategoryRepository(db)
+		err = repo.UpdateCategory(category, "roupas/camisetas", event)
+
+		assert.ErrorIs(t, err, ErrCategoryChanged)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Rolls Back On Error", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		category := model.Category{ID: 2, Name: "Camisetas", Slug: "tees", Path: "tees"}
+
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT path FROM categories").
+			WillReturnRows(sqlmock.NewRows([]string{"path"}).AddRow("camisetas"))
+		mock.ExpectExec("UPDATE categories SET parent_id").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("UPDATE categories SET path").
+			WillReturnError(errors.New("update failed"))
+		mock.ExpectRollback()
+
+		repo := NewCategoryRepository(db)
+		err = repo.UpdateCategory(category, "camisetas", event)
+
+		assert.Error(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestCategoryRepository_SetProductCategories(t *testing.T) {
+	actorID := 7
+	event := model.AuditEvent{ActorID: &actorID, Action: model.AuditActionUpdate, EntityType: model.AuditEntityProduct, EntityID: "1"}
+
+	t.Run("Success", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE products SET updated_at = NOW(), updated_by = $2 WHERE id = $1 AND deleted_at IS NULL")).
+			WithArgs(1, actorID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta("DELETE FROM product_categories WHERE product_id = $1")).
+			WithArgs(1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("INSERT INTO product_categories").
+			WithArgs(1, 2).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("INSERT INTO product_categories").
+			WithArgs(1, 3).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		expectAuditEvent(mock, "", event)
+		mock.ExpectCommit()
+
+		repo := NewCategoryRepository(db)
+		err = repo.SetProductCategories(1, []int{2, 3}, event)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Product In Trash", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE products SET updated_at").
+			WithArgs(1, actorID).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		repo := NewCategoryRepository(db)
+		err = repo.SetProductCategories(1, []int{2}, event)
+
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// cursorFetchSize is the number of rows fetched per round trip when
+// streaming a query through a server-side cursor
+const cursorFetchSize = 500
+
+// streamCursor runs the query through a server-side cursor in a read-only
+// transaction, so every row comes from the same snapshot while only one
+// fetch is held in memory. scan is called for each row; an error from it
+// stops the stream and is returned as is
+func streamCursor(ctx context.Context, connection *sql.DB, query string, args []interface{}, scan func(rowScanner) error) error {
+	tx, err := connection.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "DECLARE export_cursor NO SCROLL CURSOR FOR "+query, args...); err != nil {
+		return err
+	}
+
+	fetch := fmt.Sprintf("FETCH %d FROM export_cursor", cursorFetchSize)
+	for {
+		rows, err := tx.QueryContext(ctx, fetch)
+		if err != nil {
+			return err
+		}
+		fetched := 0
+		for rows.Next() {
+			fetched++
+			if err := scan(rows); err != nil {
+				rows.Close()
+				return err
+			}
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		if fetched < cursorFetchSize {
+			break
+		}
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"go-api/model"
+
+	"github.com/lib/pq"
+)
+
+// ImageRepositoryInterface defines the contract for product image metadata;
+// the files themselves live in the blob store
+type ImageRepositoryInterface interface {
+	GetImages(productIDs []int) ([]model.ProductImage, error)
+	GetImageByID(id int) (*model.ProductImage, error)
+	CreateImage(image model.ProductImage) (model.ProductImage, error)
+	SetPrimaryImage(productID, imageID int) error
+	ReorderImages(productID int, imageIDs []int) error
+	DeleteImage(image model.ProductImage) error
+}
+
+type ImageRepository struct {
+	connection *sql.DB
+}
+
+// Ensure ImageRepository implements ImageRepositoryInterface
+var _ ImageRepositoryInterface = (*ImageRepository)(nil)
+
+func NewImageRepository(connection *sql.DB) ImageRepositoryInterface {
+	return &ImageRepository{
+		connection: connection,
+	}
+}
+
+const selectImages = `SELECT id, product_id, storage_key, content_type, width, height, size_bytes, position, is_primary, thumbnails, created_at
+	FROM product_images`
+
+func scanImage(row rowScanner) (model.ProductImage, error) {
+	var image model.ProductImage
+	var thumbnails []byte
+	err := row.Scan(&image.ID, &image.ProductID, &image.Key, &image.ContentType, &image.Width, &image.Height,
+		&image.Size, &image.Position, &image.Primary, &thumbnails, &image.CreatedAt)
+	if err != nil {
+		return model.ProductImage{}, err
+	}
+	if err := json.Unmarshal(thumbnails, &image.Thumbnails); err != nil {
+		return model.ProductImage{}, err
+	}
+	return image, nil
+}
+
+// GetImages returns the images of all the given products in one query, ordered by position
+func (ir *ImageRepository) GetImages(productIDs []int) ([]model.ProductImage, error) {
+	rows, err := ir.connection.Query(selectImages+` WHERE product_id = ANY($1) ORDER BY product_id, position, id`, pq.Array(productIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var images []model.ProductImage
+	for rows.Next() {
+		image, err := scanImage(rows)
+		if err != nil {
+			return nil, err
+		}
+		images = append(images, image)
+	}
+	return images, rows.Err()
+}
+
+func (ir *ImageRepository) GetImageByID(id int) (*model.ProductImage, error) {
+	image, err := scanImage(ir.connection.QueryRow(selectImages+` WHERE id = $1`, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &image, nil
+}
+
+// CreateImage appends the image after the existing ones; the first image of
+// a product becomes its primary image
+func (ir *ImageRepository) CreateImage(image model.ProductImage) (model.ProductImage, error) {
+	thumbnails, err := json.Marshal(image.Thumbnails)
+	if err != nil {
+		return model.ProductImage{}, err
+	}
+
+	err = ir.connection.QueryRow(`INSERT INTO product_images
+			(product_id, storage_key, content_type, width, height, size_bytes, position, is_primary, thumbnails)
+		SELECT $1, $2, $3, $4, $5, $6, COALESCE(MAX(position) + 1, 0), COUNT(*) = 0, $7
+		FROM product_images WHERE product_id = $1
+		RETURNING id, position, is_primary, created_at`,
+		image.ProductID, image.Key, image.ContentType, image.Width, image.Height, image.Size, thumbnails,
+	).Scan(&image.ID, &image.Position, &image.Primary, &image.CreatedAt)
+	if err != nil {
+		return model.ProductImage{}, err
+	}
+	return image, nil
+}
+
+// SetPrimaryImage moves the primary flag to the image in a single transaction
+func (ir *ImageRepository) SetPrimaryImage(productID, imageID int) error {
+	tx, err := ir.connection.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`UPDATE product_images SET is_primary = FALSE WHERE product_id = $1 AND is_primary`, productID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`UPDATE product_images SET is_primary = TRUE WHERE id = $1 AND product_id = $2`, imageID, productID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// ReorderImages sets the position of each image to its index in imageIDs
+func (ir *ImageRepository) ReorderImages(productID int, imageIDs []int) error {
+	_, err := ir.connection.Exec(`UPDATE product_images pi SET position = o.ord - 1
+		FROM unnest($1::int[]) WITH ORDINALITY AS o(id, ord)
+		WHERE pi.id = o.id AND pi.product_id = $2`, pq.Array(imageIDs), productID)
+	return err
+}
+
+// DeleteImage removes the image; when it was the primary one the next image
+// by position takes its place
+func (ir *ImageRepository) DeleteImage(image model.ProductImage) error {
+	tx, err := ir.connection.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM product_images WHERE id = $1`, image.ID); err != nil {
+		return err
+	}
+	if image.Primary {
+		_, err := tx.Exec(`UPDATE product_images SET is_primary = TRUE
+			WHERE id = (SELECT id FROM product_images WHERE product_id = $1 ORDER BY position, id LIMIT 1)`, image.ProductID)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
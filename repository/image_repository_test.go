@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"errors"
+	"go-api/model"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+var imageRowColumns = []string{"id", "product_id", "storage_key", "content_type", "width", "height", "size_bytes", "position", "is_primary", "thumbnails", "created_at"}
+
+func TestImageRepository_GetImages(t *testing.T) {
+	t.Run("Decodes Thumbnails", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		now := time.Now()
+		rows := sqlmock.NewRows(imageRowColumns).
+			AddRow(1, 1, "products/1/a/original.png", "image/png", 1200, 800, 2048, 0, true,
+				[]byte(`[{"name":"small","key":"products/1/a/small.jpg","width":150,"height":100}]`), now).
+			AddRow(2, 1, "products/1/b/original.jpg", "image/jpeg", 640, 480, 1024, 1, false, []byte(`[]`), now)
+		mock.ExpectQuery(`FROM product_images WHERE product_id = ANY\(\$1\) ORDER BY product_id, position, id`).
+			WithArgs(pq.Array([]int{1})).
+			WillReturnRows(rows)
+
+		repo := NewImageRepository(db)
+		images, err := repo.GetImages([]int{1})
+
+		assert.NoError(t, err)
+		assert.Len(t, images, 2)
+		assert.True(t, images[0].Primary)
+		assert.Equal(t, []model.ImageThumbnail{{Name: "small", Key: "products/1/a/small.jpg", Width: 150, Height: 100}}, images[0].Thumbnails)
+		assert.Empty(t, images[1].Thumbnails)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestImageRepository_CreateImage(t *testing.T) {
+	t.Run("Appends After Existing Images", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		now := time.Now()
+		mock.ExpectQuery(`INSERT INTO product_images .* SELECT \$1, \$2, \$3, \$4, \$5, \$6, COALESCE\(MAX\(position\) \+ 1, 0\), COUNT\(\*\) = 0, \$7 FROM product_images WHERE product_id = \$1`).
+			WithArgs(1, "products/1/a/original.png", "image/png", 1200, 800, int64(2048), []byte(`[{"name":"small","key":"products/1/a/small.jpg","width":150,"height":100}]`)).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "position", "is_primary", "created_at"}).AddRow(5, 2, false, now))
+
+		repo := NewImageRepository(db)
+		image, err := repo.CreateImage(model.ProductImage{
+			ProductID:   1,
+			Key:         "products/1/a/original.png",
+			ContentType: "image/png",
+			Width:       1200,
+			Height:      800,
+			Size:        2048,
+			Thumbnails:  []model.ImageThumbnail{{Name: "small", Key: "products/1/a/small.jpg", Width: 150, Height: 100}},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 5, image.ID)
+		assert.Equal(t, 2, image.Position)
+		assert.False(t, image.Primary)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestImageRepository_SetPrimaryImage(t *testing.T) {
+	t.Run("Rolls Back On Error", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(`UPDATE product_images SET is_primary = FALSE WHERE product_id = \$1 AND is_primary`).
+			WithArgs(1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`UPDATE product_images SET is_primary = TRUE WHERE id = \$1 AND product_id = \$2`).
+			WithArgs(3, 1).
+			WillReturnError(errors.New("db error"))
+		mock.ExpectRollback()
+
+		repo := NewImageRepository(db)
+		err = repo.SetPrimaryImage(1, 3)
+
+		assert.Error(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestImageRepository_DeleteImage(t *testing.T) {
+	t.Run("Promotes Next Image When Primary Is Deleted", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(`DELETE FROM product_images WHERE id = \$1`).
+			WithArgs(3).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`UPDATE product_images SET is_primary = TRUE WHERE id = \(SELECT id FROM product_images WHERE product_id = \$1 ORDER BY position, id LIMIT 1\)`).
+			WithArgs(1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		repo := NewImageRepository(db)
+		err = repo.DeleteImage(model.ProductImage{ID: 3, ProductID: 1, Primary: true})
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Keeps Primary When Another Image Is Deleted", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(`DELETE FROM product_images WHERE id = \$1`).
+			WithArgs(4).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		repo := NewImageRepository(db)
+		err = repo.DeleteImage(model.ProductImage{ID: 4, ProductID: 1})
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,169 @@
+package repository
+
+import (
+	"database/sql"
+	"go-api/model"
+)
+
+// MFARepositoryInterface defines the contract for the second factor of the
+// users: the TOTP secret and the recovery codes
+type MFARepositoryInterface interface {
+	GetMFA(userID int) (*model.UserMFA, error)
+	SavePendingMFA(userID int, secretEncrypted string) error
+	ConfirmMFA(userID int, step int64, codeHashes []string, event model.AuditEvent) error
+	UseTOTPStep(userID int, step int64) error
+	UseRecoveryCode(userID int, codeHash string) error
+	ReplaceRecoveryCodes(userID int, codeHashes []string, event model.AuditEvent) error
+	DisableMFA(userID int, event model.AuditEvent) error
+}
+
+type MFARepository struct {
+	connection *sql.DB
+}
+
+// Ensure MFARepository implements MFARepositoryInterface
+var _ MFARepositoryInterface = (*MFARepository)(nil)
+
+func NewMFARepository(connection *sql.DB) MFARepositoryInterface {
+	return &MFARepository{
+		connection: connection,
+	}
+}
+
+// GetMFA returns the second factor of the user, confirmed or not, or nil
+// when the user never enrolled
+func (mr *MFARepository) GetMFA(userID int) (*model.UserMFA, error) {
+	var mfa model.UserMFA
+	var confirmedAt sql.NullTime
+	err := mr.connection.QueryRow(`SELECT user_id, secret_encrypted, confirmed_at, last_used_step FROM user_mfa WHERE user_id = $1`, userID).
+		Scan(&mfa.UserID, &mfa.SecretEncrypted, &confirmedAt, &mfa.LastUsedStep)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if confirmedAt.Valid {
+		mfa.ConfirmedAt = &confirmedAt.Time
+	}
+	return &mfa, nil
+}
+
+// SavePendingMFA stores a new secret waiting for confirmation, in place of
+// an unconfirmed one. It returns sql.ErrNoRows when the user already has a
+// confirmed second factor, which must be disabled first
+func (mr *MFARepository) SavePendingMFA(userID int, secretEncrypted string) error {
+	result, err := mr.connection.Exec(`INSERT INTO user_mfa (user_id, secret_encrypted) VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE SET secret_encrypted = EXCLUDED.secret_encrypted, last_used_step = 0, created_at = NOW()
+		WHERE user_mfa.confirmed_at IS NULL`, userID, secretEncrypted)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// ConfirmMFA turns the pending secret on, using up the time step of the code
+// that confirmed it, and stores the recovery codes. It returns sql.ErrNoRows
+// when nothing is pending or the step was already used
+func (mr *MFARepository) ConfirmMFA(userID int, step int64, codeHashes []string, event model.AuditEvent) error {
+	return withAuditEvent(mr.connection, &event, func(tx *sql.Tx) error {
+		result, err := tx.Exec(`UPDATE user_mfa SET confirmed_at = NOW(), last_used_step = $2
+			WHERE user_id = $1 AND confirmed_at IS NULL AND last_used_step < $2`, userID, step)
+		if err != nil {
+			return err
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return sql.ErrNoRows
+		}
+		return replaceRecoveryCodes(tx, userID, codeHashes)
+	})
+}
+
+// UseTOTPStep records the time step of an accepted code. A single UPDATE
+// takes it, so a code cannot be used twice even by concurrent requests; it
+// returns sql.ErrNoRows when the step is not newer than the last one
+func (mr *MFARepository) UseTOTPStep(userID int, step int64) error {
+	result, err := mr.connection.Exec(`UPDATE user_mfa SET last_used_step = $2
+		WHERE user_id = $1 AND confirmed_at IS NOT NULL AND last_used_step < $2`, userID, step)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// UseRecoveryCode spends a recovery code, returning sql.ErrNoRows when the
+// user has no unused code with the hash
+func (mr *MFARepository) UseRecoveryCode(userID int, codeHash string) error {
+	result, err := mr.connection.Exec(`UPDATE mfa_recovery_codes SET used_at = NOW()
+		WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL`, userID, codeHash)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// ReplaceRecoveryCodes discards the recovery codes of the user, used or not,
+// for new ones
+func (mr *MFARepository) ReplaceRecoveryCodes(userID int, codeHashes []string, event model.AuditEvent) error {
+	return withAuditEvent(mr.connection, &event, func(tx *sql.Tx) error {
+		return replaceRecoveryCodes(tx, userID, codeHashes)
+	})
+}
+
+// DisableMFA removes the second factor and the recovery codes of the user,
+// returning sql.ErrNoRows when it was not on
+func (mr *MFARepository) DisableMFA(userID int, event model.AuditEvent) error {
+	return withAuditEvent(mr.connection, &event, func(tx *sql.Tx) error {
+		result, err := tx.Exec(`DELETE FROM user_mfa WHERE user_id = $1 AND confirmed_at IS NOT NULL`, userID)
+		if err != nil {
+			return err
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return sql.ErrNoRows
+		}
+		_, err = tx.Exec(`DELETE FROM mfa_recovery_codes WHERE user_id = $1`, userID)
+		return err
+	})
+}
+
+// replaceRecoveryCodes swaps the recovery codes of the user within tx
+func replaceRecoveryCodes(tx *sql.Tx, userID int, codeHashes []string) error {
+	if _, err := tx.Exec(`DELETE FROM mfa_recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return err
+	}
+	for _, codeHash := range codeHashes {
+		if _, err := tx.Exec(`INSERT INTO mfa_recovery_codes (user_id, code_hash) VALUES ($1, $2)`, userID, codeHash); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,244 @@
+package repository
+
+import (
+	"database/sql"
+	"go-api/model"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMFARepository_GetMFA(t *testing.T) {
+	t.Run("Confirmed", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		confirmedAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT user_id, secret_encrypted, confirmed_at, last_used_step FROM user_mfa WHERE user_id = $1")).
+			WithArgs(7).
+			WillReturnRows(sqlmock.NewRows([]string{"user_id", "secret_encrypted", "confirmed_at", "last_used_step"}).
+				AddRow(7, "sealed", confirmedAt, 42))
+
+		repo := NewMFARepository(db)
+		mfa, err := repo.GetMFA(7)
+
+		assert.NoError(t, err)
+		assert.Equal(t, &model.UserMFA{UserID: 7, SecretEncrypted: "sealed", ConfirmedAt: &confirmedAt, LastUsedStep: 42}, mfa)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Never Enrolled", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT user_id, secret_encrypted, confirmed_at, last_used_step FROM user_mfa")).
+			WithArgs(7).
+			WillReturnError(sql.ErrNoRows)
+
+		repo := NewMFARepository(db)
+		mfa, err := repo.GetMFA(7)
+
+		assert.NoError(t, err)
+		assert.Nil(t, mfa)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestMFARepository_SavePendingMFA(t *testing.T) {
+	query := regexp.QuoteMeta(`INSERT INTO user_mfa (user_id, secret_encrypted) VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE SET secret_encrypted = EXCLUDED.secret_encrypted, last_used_step = 0, created_at = NOW()
+		WHERE user_mfa.confirmed_at IS NULL`)
+
+	t.Run("Success", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectExec(query).WithArgs(7, "sealed").WillReturnResult(sqlmock.NewResult(0, 1))
+
+		repo := NewMFARepository(db)
+		err = repo.SavePendingMFA(7, "sealed")
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Already Confirmed", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectExec(query).WithArgs(7, "sealed").WillReturnResult(sqlmock.NewResult(0, 0))
+
+		repo := NewMFARepository(db)
+		err = repo.SavePendingMFA(7, "sealed")
+
+		assert.Equal(t, sql.ErrNoRows, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestMFARepository_ConfirmMFA(t *testing.T) {
+	confirm := regexp.QuoteMeta(`UPDATE user_mfa SET confirmed_at = NOW(), last_used_step = $2
+		WHERE user_id = $1 AND confirmed_at IS NULL AND last_used_step < $2`)
+
+	t.Run("Success", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		userID := 7
+		event := model.AuditEvent{ActorID: &userID, Action: model.AuditActionUpdate, EntityType: model.AuditEntityUser, EntityID: "7"}
+
+		mock.ExpectBegin()
+		mock.ExpectExec(confirm).WithArgs(7, int64(42)).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta("DELETE FROM mfa_recovery_codes WHERE user_id = $1")).
+			WithArgs(7).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		for _, hash := range []string{"hash1", "hash2"} {
+			mock.ExpectExec(regexp.QuoteMeta("INSERT INTO mfa_recovery_codes (user_id, code_hash) VALUES ($1, $2)")).
+				WithArgs(7, hash).
+				WillReturnResult(sqlmock.NewResult(1, 1))
+		}
+		expectAuditEvent(mock, "", event)
+		mock.ExpectCommit()
+
+		repo := NewMFARepository(db)
+		err = repo.ConfirmMFA(7, 42, []string{"hash1", "hash2"}, event)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Nothing Pending Or Step Used", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(confirm).WithArgs(7, int64(42)).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		repo := NewMFARepository(db)
+		err = repo.ConfirmMFA(7, 42, []string{"hash1"}, model.AuditEvent{})
+
+		assert.Equal(t, sql.ErrNoRows, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestMFARepository_UseTOTPStep(t *testing.T) {
+	query := regexp.QuoteMeta(`UPDATE user_mfa SET last_used_step = $2
+		WHERE user_id = $1 AND confirmed_at IS NOT NULL AND last_used_step < $2`)
+
+	t.Run("Success", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectExec(query).WithArgs(7, int64(43)).WillReturnResult(sqlmock.NewResult(0, 1))
+
+		repo := NewMFARepository(db)
+		err = repo.UseTOTPStep(7, 43)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Step Already Used", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectExec(query).WithArgs(7, int64(42)).WillReturnResult(sqlmock.NewResult(0, 0))
+
+		repo := NewMFARepository(db)
+		err = repo.UseTOTPStep(7, 42)
+
+		assert.Equal(t, sql.ErrNoRows, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestMFARepository_UseRecoveryCode(t *testing.T) {
+	query := regexp.QuoteMeta(`UPDATE mfa_recovery_codes SET used_at = NOW()
+		WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL`)
+
+	t.Run("Success", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectExec(query).WithArgs(7, "hash").WillReturnResult(sqlmock.NewResult(0, 1))
+
+		repo := NewMFARepository(db)
+		err = repo.UseRecoveryCode(7, "hash")
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Unknown Or Used Code", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectExec(query).WithArgs(7, "hash").WillReturnResult(sqlmock.NewResult(0, 0))
+
+		repo := NewMFARepository(db)
+		err = repo.UseRecoveryCode(7, "hash")
+
+		assert.Equal(t, sql.ErrNoRows, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestMFARepository_DisableMFA(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		userID := 7
+		event := model.AuditEvent{ActorID: &userID, Action: model.AuditActionUpdate, EntityType: model.AuditEntityUser, EntityID: "7"}
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("DELETE FROM user_mfa WHERE user_id = $1 AND confirmed_at IS NOT NULL")).
+			WithArgs(7).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta("DELETE FROM mfa_recovery_codes WHERE user_id = $1")).
+			WithArgs(7).
+			WillReturnResult(sqlmock.NewResult(0, 10))
+		expectAuditEvent(mock, "", event)
+		mock.ExpectCommit()
+
+		repo := NewMFARepository(db)
+		err = repo.DisableMFA(7, event)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Not Enabled", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("DELETE FROM user_mfa WHERE user_id = $1")).
+			WithArgs(7).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		repo := NewMFARepository(db)
+		err = repo.DisableMFA(7, model.AuditEvent{})
+
+		assert.Equal(t, sql.ErrNoRows, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
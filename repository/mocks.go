@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"go-api/model"
 
 	"github.com/stretchr/testify/mock"
@@ -12,8 +13,8 @@ type MockUserRepository struct {
 }
 
 // CreateUser mocks the CreateUser method
-func (m *MockUserRepository) CreateUser(user model.User) (int, error) {
-	args := m.Called(user)
+func (m *MockUserRepository) CreateUser(user model.User, event model.AuditEvent) (int, error) {
+	args := m.Called(user, event)
 	return args.Int(0), args.Error(1)
 }
 
@@ -36,22 +37,74 @@ func (m *MockUserRepository) GetUserByEmail(email string) (*model.User, error) {
 }
 
 // UpdateUser mocks the UpdateUser method
-func (m *MockUserRepository) UpdateUser(user model.User) error {
-	args := m.Called(user)
+func (m *MockUserRepository) UpdateUser(user model.User, event model.AuditEvent) error {
+	args := m.Called(user, event)
 	return args.Error(0)
 }
 
 // DeleteUser mocks the DeleteUser method
-func (m *MockUserRepository) DeleteUser(id int) error {
-	args := m.Called(id)
+func (m *MockUserRepository) DeleteUser(id int, event model.AuditEvent) error {
+	args := m.Called(id, event)
 	return args.Error(0)
 }
 
 // GetUsers mocks the GetUsers method
-func (m *MockUserRepository) GetUsers() ([]model.User, error) {
+func (m *MockUserRepository) GetUsers(filter model.UserFilter) ([]model.User, error) {
+	args := m.Called(filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.User), args.Error(1)
+}
+
+// ExportUsers mocks the ExportUsers method, calling fn for each user returned by the mock
+func (m *MockUserRepository) ExportUsers(ctx context.Context, filter model.UserFilter, fn func(model.User) error) error {
+	args := m.Called(ctx, filter)
+	if users, ok := args.Get(0).([]model.User); ok {
+		for _, user := range users {
+			if err := fn(user); err != nil {
+				return err
+			}
+		}
+	}
+	return args.Error(1)
+}
+
+// GetDeletedUsers mocks the GetDeletedUsers method
+func (m *MockUserRepository) GetDeletedUsers() ([]model.User, error) {
 	args := m.Called()
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]model.User), args.Error(1)
 }
+
+// GetDeletedUserByID mocks the GetDeletedUserByID method
+func (m *MockUserRepository) GetDeletedUserByID(id int) (*model.User, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.User), args.Error(1)
+}
+
+// GetDeletedUserByEmail mocks the GetDeletedUserByEmail method
+func (m *MockUserRepository) GetDeletedUserByEmail(email string) (*model.User, error) {
+	args := m.Called(email)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.User), args.Error(1)
+}
+
+// RestoreUser mocks the RestoreUser method
+func (m *MockUserRepository) RestoreUser(id int, event model.AuditEvent) error {
+	args := m.Called(id, event)
+	return args.Error(0)
+}
+
+// PurgeUser mocks the PurgeUser method
+func (m *MockUserRepository) PurgeUser(id int, event model.AuditEvent) error {
+	args := m.Called(id, event)
+	return args.Error(0)
+}
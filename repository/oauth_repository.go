@@ -0,0 +1,265 @@
+package repository
+
+import (
+	"database/sql"
+	"go-api/model"
+
+	"github.com/lib/pq"
+)
+
+// OAuthRepositoryInterface defines the contract for the OAuth clients,
+// consents, authorization codes and access tokens
+type OAuthRepositoryInterface interface {
+	CreateClient(client model.OAuthClient, event model.AuditEvent) (int, error)
+	GetClients() ([]model.OAuthClient, error)
+	GetClient(clientID string) (*model.OAuthClient, error)
+	RevokeClient(clientID string, event model.AuditEvent) error
+	GetConsent(userID int, clientID string) (*model.OAuthConsent, error)
+	GetConsents(userID int) ([]model.OAuthConsent, error)
+	SaveConsent(consent model.OAuthConsent, event model.AuditEvent) error
+	DeleteConsent(userID int, clientID string, event model.AuditEvent) error
+	CreateCode(code model.OAuthCode) error
+	ConsumeCode(codeHash string) (*model.OAuthCode, error)
+	CreateToken(token model.OAuthToken) (int, error)
+	GetTokenByHash(tokenHash string) (*model.OAuthToken, error)
+	RevokeToken(id int) error
+}
+
+type OAuthRepository struct {
+	connection *sql.DB
+}
+
+// Ensure OAuthRepository implements OAuthRepositoryInterface
+var _ OAuthRepositoryInterface = (*OAuthRepository)(nil)
+
+func NewOAuthRepository(connection *sql.DB) OAuthRepositoryInterface {
+	return &OAuthRepository{
+		connection: connection,
+	}
+}
+
+const selectOAuthClients = `SELECT id, client_id, name, COALESCE(secret_hash, ''), redirect_uris, grant_types, scopes,
+	user_id, created_at, created_by, revoked_at FROM oauth_clients`
+
+// CreateClient registers a client and returns its ID
+func (oa *OAuthRepository) CreateClient(client model.OAuthClient, event model.AuditEvent) (int, error) {
+	secretHash := sql.NullString{String: client.SecretHash, Valid: client.SecretHash != ""}
+	var id int
+	err := withAuditEvent(oa.connection, &event, func(tx *sql.Tx) error {
+		return tx.QueryRow(`INSERT INTO oauth_clients (client_id, name, secret_hash, redirect_uris, grant_types, scopes, user_id, created_by)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id`,
+			client.ClientID, client.Name, secretHash, pq.Array(client.RedirectURIs), pq.Array(client.GrantTypes), pq.Array(client.Scopes),
+			client.UserID, event.ActorID).Scan(&id)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+// GetClients lists the clients, revoked ones included
+func (oa *OAuthRepository) GetClients() ([]model.OAuthClient, error) {
+	rows, err := oa.connection.Query(selectOAuthClients + ` ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	clients := []model.OAuthClient{}
+	for rows.Next() {
+		client, err := scanOAuthClient(rows)
+		if err != nil {
+			return nil, err
+		}
+		clients = append(clients, *client)
+	}
+	return clients, rows.Err()
+}
+
+// GetClient returns the client with the client_id, or nil
+func (oa *OAuthRepository) GetClient(clientID string) (*model.OAuthClient, error) {
+	client, err := scanOAuthClient(oa.connection.QueryRow(selectOAuthClients+` WHERE client_id = $1`, clientID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return client, nil
+}
+
+// RevokeClient turns a client off for good, along with its access tokens
+// and pending codes. It returns sql.ErrNoRows when the client does not
+// exist or was already revoked
+func (oa *OAuthRepository) RevokeClient(clientID string, event model.AuditEvent) error {
+	return withAuditEvent(oa.connection, &event, func(tx *sql.Tx) error {
+		result, err := tx.Exec(`UPDATE oauth_clients SET revoked_at = NOW() WHERE client_id = $1 AND revoked_at IS NULL`, clientID)
+		if err != nil {
+			return err
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return sql.ErrNoRows
+		}
+		if _, err := tx.Exec(`UPDATE oauth_tokens SET revoked_at = NOW() WHERE client_id = $1 AND revoked_at IS NULL`, clientID); err != nil {
+			return err
+		}
+		_, err = tx.Exec(`DELETE FROM oauth_codes WHERE client_id = $1`, clientID)
+		return err
+	})
+}
+
+// GetConsent returns what the user granted the client, or nil
+func (oa *OAuthRepository) GetConsent(userID int, clientID string) (*model.OAuthConsent, error) {
+	consents, err := oa.selectConsents(`WHERE oc.user_id = $1 AND oc.client_id = $2`, userID, clientID)
+	if err != nil || len(consents) == 0 {
+		return nil, err
+	}
+	return &consents[0], nil
+}
+
+// GetConsents lists the clients the user granted scopes to
+func (oa *OAuthRepository) GetConsents(userID int) ([]model.OAuthConsent, error) {
+	return oa.selectConsents(`WHERE oc.user_id = $1 ORDER BY oc.created_at`, userID)
+}
+
+// SaveConsent records the scopes the user granted the client, replacing
+// the ones of an earlier consent
+func (oa *OAuthRepository) SaveConsent(consent model.OAuthConsent, event model.AuditEvent) error {
+	return withAuditEvent(oa.connection, &event, func(tx *sql.Tx) error {
+		_, err := tx.Exec(`INSERT INTO oauth_consents (user_id, client_id, scopes) VALUES ($1, $2, $3)
+			ON CONFLICT (user_id, client_id) DO UPDATE SET scopes = EXCLUDED.scopes, updated_at = NOW()`,
+			consent.UserID, consent.ClientID, pq.Array(consent.Scopes))
+		return err
+	})
+}
+
+// DeleteConsent withdraws the consent of the user to the client and revokes
+// the access tokens it issued for them. It returns sql.ErrNoRows when there
+// is no consent
+func (oa *OAuthRepository) DeleteConsent(userID int, clientID string, event model.AuditEvent) error {
+	return withAuditEvent(oa.connection, &event, func(tx *sql.Tx) error {
+		result, err := tx.Exec(`DELETE FROM oauth_consents WHERE user_id = $1 AND client_id = $2`, userID, clientID)
+		if err != nil {
+			return err
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return sql.ErrNoRows
+		}
+		_, err = tx.Exec(`UPDATE oauth_tokens SET revoked_at = NOW() WHERE user_id = $1 AND client_id = $2 AND revoked_at IS NULL`, userID, clientID)
+		return err
+	})
+}
+
+// CreateCode stores an authorization code, clearing the expired ones
+func (oa *OAuthRepository) CreateCode(code model.OAuthCode) error {
+	if _, err := oa.connection.Exec(`DELETE FROM oauth_codes WHERE expires_at < NOW()`); err != nil {
+		return err
+	}
+	_, err := oa.connection.Exec(`INSERT INTO oauth_codes (code_hash, client_id, user_id, redirect_uri, scopes, code_challenge, mfa, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		code.CodeHash, code.ClientID, code.UserID, code.RedirectURI, pq.Array(code.Scopes), code.CodeChallenge, code.MFA, code.ExpiresAt)
+	return err
+}
+
+// ConsumeCode removes and returns the code with the hash, or nil when there
+// is none. A single DELETE takes it, so a code cannot be exchanged twice
+// even by concurrent requests; expiry is up to the caller
+func (oa *OAuthRepository) ConsumeCode(codeHash string) (*model.OAuthCode, error) {
+	code := model.OAuthCode{CodeHash: codeHash}
+	err := oa.connection.QueryRow(`DELETE FROM oauth_codes WHERE code_hash = $1
+		RETURNING client_id, user_id, redirect_uri, scopes, code_challenge, mfa, expires_at`, codeHash).
+		Scan(&code.ClientID, &code.UserID, &code.RedirectURI, pq.Array(&code.Scopes), &code.CodeChallenge, &code.MFA, &code.ExpiresAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &code, nil
+}
+
+// CreateToken stores an access token and returns its ID
+func (oa *OAuthRepository) CreateToken(token model.OAuthToken) (int, error) {
+	var id int
+	err := oa.connection.QueryRow(`INSERT INTO oauth_tokens (token_hash, client_id, user_id, scopes, mfa, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`,
+		token.TokenHash, token.ClientID, token.UserID, pq.Array(token.Scopes), token.MFA, token.ExpiresAt).Scan(&id)
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// GetTokenByHash returns the access token with the hash, revoked and
+// expired ones included, or nil
+func (oa *OAuthRepository) GetTokenByHash(tokenHash string) (*model.OAuthToken, error) {
+	token := model.OAuthToken{TokenHash: tokenHash}
+	var revokedAt sql.NullTime
+	err := oa.connection.QueryRow(`SELECT id, client_id, user_id, scopes, mfa, created_at, expires_at, revoked_at
+		FROM oauth_tokens WHERE token_hash = $1`, tokenHash).
+		Scan(&token.ID, &token.ClientID, &token.UserID, pq.Array(&token.Scopes), &token.MFA, &token.CreatedAt, &token.ExpiresAt, &revokedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if revokedAt.Valid {
+		token.RevokedAt = &revokedAt.Time
+	}
+	return &token, nil
+}
+
+// RevokeToken turns an access token off; revoking it again does nothing
+func (oa *OAuthRepository) RevokeToken(id int) error {
+	_, err := oa.connection.Exec(`UPDATE oauth_tokens SET revoked_at = NOW() WHERE id = $1 AND revoked_at IS NULL`, id)
+	return err
+}
+
+// --- Helper Functions ---
+
+func (oa *OAuthRepository) selectConsents(where string, args ...interface{}) ([]model.OAuthConsent, error) {
+	rows, err := oa.connection.Query(`SELECT oc.user_id, oc.client_id, c.name, oc.scopes, oc.created_at, oc.updated_at
+		FROM oauth_consents oc JOIN oauth_clients c ON c.client_id = oc.client_id `+where, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	consents := []model.OAuthConsent{}
+	for rows.Next() {
+		var consent model.OAuthConsent
+		err := rows.Scan(&consent.UserID, &consent.ClientID, &consent.ClientName, pq.Array(&consent.Scopes), &consent.CreatedAt, &consent.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		consents = append(consents, consent)
+	}
+	return consents, rows.Err()
+}
+
+func scanOAuthClient(row rowScanner) (*model.OAuthClient, error) {
+	var client model.OAuthClient
+	var userID, createdBy sql.NullInt64
+	var revokedAt sql.NullTime
+	err := row.Scan(&client.ID, &client.ClientID, &client.Name, &client.SecretHash, pq.Array(&client.RedirectURIs), pq.Array(&client.GrantTypes),
+		pq.Array(&client.Scopes), &userID, &client.CreatedAt, &createdBy, &revokedAt)
+	if err != nil {
+		return nil, err
+	}
+	client.UserID = nullableInt(userID)
+	client.CreatedBy = nullableInt(createdBy)
+	if revokedAt.Valid {
+		client.RevokedAt = &revokedAt.Time
+	}
+	return &client, nil
+}
@@ -0,0 +1,316 @@
+package repository
+
+import (
+	"database/sql"
+	"go-api/model"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+var oauthClientColumns = []string{"id", "client_id", "name", "secret_hash", "redirect_uris", "grant_types", "scopes", "user_id", "created_at", "created_by", "revoked_at"}
+
+func TestOAuthRepository_CreateClient(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	actorID := 1
+	event := model.AuditEvent{ActorID: &actorID, Action: model.AuditActionCreate, EntityType: model.AuditEntityOAuthClient, EntityID: "3f9a1c0b7d2e4a5b6c7d8e9f"}
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO oauth_clients (client_id, name, secret_hash, redirect_uris, grant_types, scopes, user_id, created_by)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id`)).
+		WithArgs("3f9a1c0b7d2e4a5b6c7d8e9f", "Agenda", nil, pq.Array([]string{"https://agenda.example.com/callback"}),
+			pq.Array([]string{model.GrantTypeAuthorizationCode}), pq.Array([]string{model.ScopeOrdersRead}), nil, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+	expectAuditEvent(mock, "", event)
+	mock.ExpectCommit()
+
+	repo := NewOAuthRepository(db)
+	id, err := repo.CreateClient(model.OAuthClient{
+		ClientID:     "3f9a1c0b7d2e4a5b6c7d8e9f",
+		Name:         "Agenda",
+		RedirectURIs: []string{"https://agenda.example.com/callback"},
+		GrantTypes:   []string{model.GrantTypeAuthorizationCode},
+		Scopes:       []string{model.ScopeOrdersRead},
+	}, event)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, id)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOAuthRepository_GetClient(t *testing.T) {
+	query := regexp.QuoteMeta(`SELECT id, client_id, name, COALESCE(secret_hash, ''), redirect_uris, grant_types, scopes,
+	user_id, created_at, created_by, revoked_at FROM oauth_clients WHERE client_id = $1`)
+
+	t.Run("Found", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		createdAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+		mock.ExpectQuery(query).
+			WithArgs("3f9a1c0b7d2e4a5b6c7d8e9f").
+			WillReturnRows(sqlmock.NewRows(oauthClientColumns).
+				AddRow(2, "3f9a1c0b7d2e4a5b6c7d8e9f", "Armazém", "hash", "{}", "{client_credentials}", "{products:read,products:write}", 9, createdAt, 1, nil))
+
+		repo := NewOAuthRepository(db)
+		client, err := repo.GetClient("3f9a1c0b7d2e4a5b6c7d8e9f")
+
+		assert.NoError(t, err)
+		userID, createdBy := 9, 1
+		assert.Equal(t, &model.OAuthClient{
+			ID:           2,
+			ClientID:     "3f9a1c0b7d2e4a5b6c7d8e9f",
+			Name:         "Armazém",
+			SecretHash:   "hash",
+			RedirectURIs: []string{},
+			GrantTypes:   []string{model.GrantTypeClientCredentials},
+			Scopes:       []string{model.ScopeProductsRead, model.ScopeProductsWrite},
+			UserID:       &userID,
+			CreatedAt:    createdAt,
+			CreatedBy:    &createdBy,
+		}, client)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectQuery(query).WithArgs("unknown").WillReturnError(sql.ErrNoRows)
+
+		repo := NewOAuthRepository(db)
+		client, err := repo.GetClient("unknown")
+
+		assert.NoError(t, err)
+		assert.Nil(t, client)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestOAuthRepository_RevokeClient(t *testing.T) {
+	event := model.AuditEvent{Action: model.AuditActionRevoke, EntityType: model.AuditEntityOAuthClient, EntityID: "3f9a1c0b7d2e4a5b6c7d8e9f"}
+	revoke := regexp.QuoteMeta(`UPDATE oauth_clients SET revoked_at = NOW() WHERE client_id = $1 AND revoked_at IS NULL`)
+
+	t.Run("Success", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(revoke).WithArgs("3f9a1c0b7d2e4a5b6c7d8e9f").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE oauth_tokens SET revoked_at = NOW() WHERE client_id = $1 AND revoked_at IS NULL`)).
+			WithArgs("3f9a1c0b7d2e4a5b6c7d8e9f").WillReturnResult(sqlmock.NewResult(0, 4))
+		mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM oauth_codes WHERE client_id = $1`)).
+			WithArgs("3f9a1c0b7d2e4a5b6c7d8e9f").WillReturnResult(sqlmock.NewResult(0, 0))
+		expectAuditEvent(mock, "", event)
+		mock.ExpectCommit()
+
+		repo := NewOAuthRepository(db)
+		err = repo.RevokeClient("3f9a1c0b7d2e4a5b6c7d8e9f", event)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Already Revoked", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(revoke).WithArgs("3f9a1c0b7d2e4a5b6c7d8e9f").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		repo := NewOAuthRepository(db)
+		err = repo.RevokeClient("3f9a1c0b7d2e4a5b6c7d8e9f", event)
+
+		assert.Equal(t, sql.ErrNoRows, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestOAuthRepository_SaveConsent(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	event := model.AuditEvent{Action: model.AuditActionGrant, EntityType: model.AuditEntityOAuthClient, EntityID: "3f9a1c0b7d2e4a5b6c7d8e9f"}
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO oauth_consents (user_id, client_id, scopes) VALUES ($1, $2, $3)
+			ON CONFLICT (user_id, client_id) DO UPDATE SET scopes = EXCLUDED.scopes, updated_at = NOW()`)).
+		WithArgs(7, "3f9a1c0b7d2e4a5b6c7d8e9f", pq.Array([]string{model.ScopeOrdersRead})).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectAuditEvent(mock, "", event)
+	mock.ExpectCommit()
+
+	repo := NewOAuthRepository(db)
+	err = repo.SaveConsent(model.OAuthConsent{UserID: 7, ClientID: "3f9a1c0b7d2e4a5b6c7d8e9f", Scopes: []string{model.ScopeOrdersRead}}, event)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOAuthRepository_GetConsents(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	grantedAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT oc.user_id, oc.client_id, c.name, oc.scopes, oc.created_at, oc.updated_at
+		FROM oauth_consents oc JOIN oauth_clients c ON c.client_id = oc.client_id WHERE oc.user_id = $1 ORDER BY oc.created_at`)).
+		WithArgs(7).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "client_id", "name", "scopes", "created_at", "updated_at"}).
+			AddRow(7, "3f9a1c0b7d2e4a5b6c7d8e9f", "Agenda", "{orders:read}", grantedAt, grantedAt))
+
+	repo := NewOAuthRepository(db)
+	consents, err := repo.GetConsents(7)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []model.OAuthConsent{{UserID: 7, ClientID: "3f9a1c0b7d2e4a5b6c7d8e9f", ClientName: "Agenda", Scopes: []string{model.ScopeOrdersRead}, CreatedAt: grantedAt, UpdatedAt: grantedAt}}, consents)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOAuthRepository_DeleteConsent(t *testing.T) {
+	event := model.AuditEvent{Action: model.AuditActionRevoke, EntityType: model.AuditEntityOAuthClient, EntityID: "3f9a1c0b7d2e4a5b6c7d8e9f"}
+	query := regexp.QuoteMeta(`DELETE FROM oauth_consents WHERE user_id = $1 AND client_id = $2`)
+
+	t.Run("Revokes The Tokens", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(query).WithArgs(7, "3f9a1c0b7d2e4a5b6c7d8e9f").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE oauth_tokens SET revoked_at = NOW() WHERE user_id = $1 AND client_id = $2 AND revoked_at IS NULL`)).
+			WithArgs(7, "3f9a1c0b7d2e4a5b6c7d8e9f").WillReturnResult(sqlmock.NewResult(0, 2))
+		expectAuditEvent(mock, "", event)
+		mock.ExpectCommit()
+
+		repo := NewOAuthRepository(db)
+		err = repo.DeleteConsent(7, "3f9a1c0b7d2e4a5b6c7d8e9f", event)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("No Consent", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(query).WithArgs(7, "3f9a1c0b7d2e4a5b6c7d8e9f").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		repo := NewOAuthRepository(db)
+		err = repo.DeleteConsent(7, "3f9a1c0b7d2e4a5b6c7d8e9f", event)
+
+		assert.Equal(t, sql.ErrNoRows, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestOAuthRepository_ConsumeCode(t *testing.T) {
+	query := regexp.QuoteMeta(`DELETE FROM oauth_codes WHERE code_hash = $1
+		RETURNING client_id, user_id, redirect_uri, scopes, code_challenge, mfa, expires_at`)
+
+	t.Run("Found", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		expiresAt := time.Date(2026, 10, 19, 12, 5, 0, 0, time.UTC)
+		mock.ExpectQuery(query).
+			WithArgs("hash").
+			WillReturnRows(sqlmock.NewRows([]string{"client_id", "user_id", "redirect_uri", "scopes", "code_challenge", "mfa", "expires_at"}).
+				AddRow("3f9a1c0b7d2e4a5b6c7d8e9f", 7, "https://agenda.example.com/callback", "{orders:read}", "challenge", true, expiresAt))
+
+		repo := NewOAuthRepository(db)
+		code, err := repo.ConsumeCode("hash")
+
+		assert.NoError(t, err)
+		assert.Equal(t, &model.OAuthCode{
+			CodeHash:      "hash",
+			ClientID:      "3f9a1c0b7d2e4a5b6c7d8e9f",
+			UserID:        7,
+			RedirectURI:   "https://agenda.example.com/callback",
+			Scopes:        []string{model.ScopeOrdersRead},
+			CodeChallenge: "challenge",
+			MFA:           true,
+			ExpiresAt:     expiresAt,
+		}, code)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Already Used", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectQuery(query).WithArgs("hash").WillReturnError(sql.ErrNoRows)
+
+		repo := NewOAuthRepository(db)
+		code, err := repo.ConsumeCode("hash")
+
+		assert.NoError(t, err)
+		assert.Nil(t, code)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestOAuthRepository_CreateToken(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	expiresAt := time.Date(2026, 10, 19, 13, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO oauth_tokens (token_hash, client_id, user_id, scopes, mfa, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`)).
+		WithArgs("hash", "3f9a1c0b7d2e4a5b6c7d8e9f", 7, pq.Array([]string{model.ScopeOrdersRead}), false, expiresAt).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(11))
+
+	repo := NewOAuthRepository(db)
+	id, err := repo.CreateToken(model.OAuthToken{TokenHash: "hash", ClientID: "3f9a1c0b7d2e4a5b6c7d8e9f", UserID: 7, Scopes: []string{model.ScopeOrdersRead}, ExpiresAt: expiresAt})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 11, id)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOAuthRepository_GetTokenByHash(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	createdAt := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	revokedAt := createdAt.Add(10 * time.Minute)
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, client_id, user_id, scopes, mfa, created_at, expires_at, revoked_at
+		FROM oauth_tokens WHERE token_hash = $1`)).
+		WithArgs("hash").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "client_id", "user_id", "scopes", "mfa", "created_at", "expires_at", "revoked_at"}).
+			AddRow(11, "3f9a1c0b7d2e4a5b6c7d8e9f", 7, "{orders:read}", false, createdAt, createdAt.Add(time.Hour), revokedAt))
+
+	repo := NewOAuthRepository(db)
+	token, err := repo.GetTokenByHash("hash")
+
+	assert.NoError(t, err)
+	assert.Equal(t, &model.OAuthToken{
+		ID:        11,
+		TokenHash: "hash",
+		ClientID:  "3f9a1c0b7d2e4a5b6c7d8e9f",
+		UserID:    7,
+		Scopes:    []string{model.ScopeOrdersRead},
+		CreatedAt: createdAt,
+		ExpiresAt: createdAt.Add(time.Hour),
+		RevokedAt: &revokedAt,
+	}, token)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
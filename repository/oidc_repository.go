@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"database/sql"
+	"go-api/model"
+)
+
+// OIDCRepositoryInterface defines the contract for the sign ins with
+// external identity providers: the pending logins and the linked identities
+type OIDCRepositoryInterface interface {
+	CreateLoginState(state model.OIDCLoginState) error
+	ConsumeLoginState(stateHash string) (*model.OIDCLoginState, error)
+	GetIdentity(provider, subject string) (*model.UserIdentity, error)
+	GetIdentities(userID int) ([]model.UserIdentity, error)
+	LinkIdentity(identity model.UserIdentity, event model.AuditEvent) error
+	TouchIdentity(id int, email string) error
+	UnlinkIdentity(userID int, provider string, event model.AuditEvent) error
+}
+
+type OIDCRepository struct {
+	connection *sql.DB
+}
+
+// Ensure OIDCRepository implements OIDCRepositoryInterface
+var _ OIDCRepositoryInterface = (*OIDCRepository)(nil)
+
+func NewOIDCRepository(connection *sql.DB) OIDCRepositoryInterface {
+	return &OIDCRepository{
+		connection: connection,
+	}
+}
+
+const selectIdentities = `SELECT id, user_id, provider, subject, email, created_at, last_login_at FROM user_identities`
+
+// CreateLoginState stores a pending login, clearing the ones abandoned
+func (or *OIDCRepository) CreateLoginState(state model.OIDCLoginState) error {
+	if _, err := or.connection.Exec(`DELETE FROM oidc_login_states WHERE expires_at < NOW()`); err != nil {
+		return err
+	}
+	var linkUserID *int
+	if state.LinkUserID != 0 {
+		linkUserID = &state.LinkUserID
+	}
+	_, err := or.connection.Exec(`INSERT INTO oidc_login_states (state_hash, provider, nonce, code_verifier, link_user_id, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)`, state.StateHash, state.Provider, state.Nonce, state.CodeVerifier, linkUserID, state.ExpiresAt)
+	return err
+}
+
+// ConsumeLoginState removes and returns the pending login with the state
+// hash, or nil when there is none. A single DELETE takes it, so a state
+// cannot be used twice even by concurrent requests; expiry is up to the
+// caller
+func (or *OIDCRepository) ConsumeLoginState(stateHash string) (*model.OIDCLoginState, error) {
+	state := model.OIDCLoginState{StateHash: stateHash}
+	var linkUserID sql.NullInt64
+	err := or.connection.QueryRow(`DELETE FROM oidc_login_states WHERE state_hash = $1
+		RETURNING provider, nonce, code_verifier, link_user_id, expires_at`, stateHash).
+		Scan(&state.Provider, &state.Nonce, &state.CodeVerifier, &linkUserID, &state.ExpiresAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	state.LinkUserID = int(linkUserID.Int64)
+	return &state, nil
+}
+
+// GetIdentity returns the identity with the subject at the provider, or nil
+func (or *OIDCRepository) GetIdentity(provider, subject string) (*model.UserIdentity, error) {
+	identity, err := scanIdentity(or.connection.QueryRow(selectIdentities+` WHERE provider = $1 AND subject = $2`, provider, subject))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return identity, nil
+}
+
+func (or *OIDCRepository) GetIdentities(userID int) ([]model.UserIdentity, error) {
+	rows, err := or.connection.Query(selectIdentities+` WHERE user_id = $1 ORDER BY provider`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	identities := []model.UserIdentity{}
+	for rows.Next() {
+		identity, err := scanIdentity(rows)
+		if err != nil {
+			return nil, err
+		}
+		identities = append(identities, *identity)
+	}
+	return identities, rows.Err()
+}
+
+// LinkIdentity links the identity to its user. It returns sql.ErrNoRows
+// when the identity belongs to another user, or the user already has one
+// at the provider
+func (or *OIDCRepository) LinkIdentity(identity model.UserIdentity, event model.AuditEvent) error {
+	return withAuditEvent(or.connection, &event, func(tx *sql.Tx) error {
+		result, err := tx.Exec(`INSERT INTO user_identities (user_id, provider, subject, email, last_login_at)
+			VALUES ($1, $2, $3, $4, NOW()) ON CONFLICT DO NOTHING`, identity.UserID, identity.Provider, identity.Subject, identity.Email)
+		if err != nil {
+			return err
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return sql.ErrNoRows
+		}
+		return nil
+	})
+}
+
+// TouchIdentity records a sign in with the identity and the email the
+// provider reported
+func (or *OIDCRepository) TouchIdentity(id int, email string) error {
+	_, err := or.connection.Exec(`UPDATE user_identities SET email = $2, last_login_at = NOW() WHERE id = $1`, id, email)
+	return err
+}
+
+// UnlinkIdentity removes the identity of the user at the provider,
+// returning sql.ErrNoRows when there is none
+func (or *OIDCRepository) UnlinkIdentity(userID int, provider string, event model.AuditEvent) error {
+	return withAuditEvent(or.connection, &event, func(tx *sql.Tx) error {
+		result, err := tx.Exec(`DELETE FROM user_identities WHERE user_id = $1 AND provider = $2`, userID, provider)
+		if err != nil {
+			return err
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return sql.ErrNoRows
+		}
+		return nil
+	})
+}
+
+// --- Helper Functions ---
+
+func scanIdentity(row rowScanner) (*model.UserIdentity, error) {
+	var identity model.UserIdentity
+	var lastLoginAt sql.NullTime
+	err := row.Scan(&identity.ID, &identity.UserID, &identity.Provider, &identity.Subject, &identity.Email, &identity.CreatedAt, &lastLoginAt)
+	if err != nil {
+		return nil, err
+	}
+	if lastLoginAt.Valid {
+		identity.LastLoginAt = &lastLoginAt.Time
+	}
+	return &identity, nil
+}
@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"database/sql"
+	"go-api/model"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOIDCRepository_CreateLoginState(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	expiresAt := time.Date(2026, 10, 19, 12, 10, 0, 0, time.UTC)
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM oidc_login_states WHERE expires_at < NOW()")).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO oidc_login_states (state_hash, provider, nonce, code_verifier, link_user_id, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)`)).
+		WithArgs("hash", "corp", "nonce", "verifier", nil, expiresAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	repo := NewOIDCRepository(db)
+	err = repo.CreateLoginState(model.OIDCLoginState{StateHash: "hash", Provider: "corp", Nonce: "nonce", CodeVerifier: "verifier", ExpiresAt: expiresAt})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOIDCRepository_ConsumeLoginState(t *testing.T) {
+	query := regexp.QuoteMeta(`DELETE FROM oidc_login_states WHERE state_hash = $1
+		RETURNING provider, nonce, code_verifier, link_user_id, expires_at`)
+
+	t.Run("Found", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		expiresAt := time.Date(2026, 10, 19, 12, 10, 0, 0, time.UTC)
+		mock.ExpectQuery(query).
+			WithArgs("hash").
+			WillReturnRows(sqlmock.NewRows([]string{"provider", "nonce", "code_verifier", "link_user_id", "expires_at"}).
+				AddRow("corp", "nonce", "verifier", 7, expiresAt))
+
+		repo := NewOIDCRepository(db)
+		state, err := repo.ConsumeLoginState("hash")
+
+		assert.NoError(t, err)
+		assert.Equal(t, &model.OIDCLoginState{StateHash: "hash", Provider: "corp", Nonce: "nonce", CodeVerifier: "verifier", LinkUserID: 7, ExpiresAt: expiresAt}, state)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Already Used", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectQuery(query).WithArgs("hash").WillReturnError(sql.ErrNoRows)
+
+		repo := NewOIDCRepository(db)
+		state, err := repo.ConsumeLoginState("hash")
+
+		assert.NoError(t, err)
+		assert.Nil(t, state)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestOIDCRepository_GetIdentity(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	createdAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, user_id, provider, subject, email, created_at, last_login_at FROM user_identities WHERE provider = $1 AND subject = $2")).
+		WithArgs("corp", "248289761001").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "provider", "subject", "email", "created_at", "last_login_at"}).
+			AddRow(3, 7, "corp", "248289761001", "ana@corp.example", createdAt, nil))
+
+	repo := NewOIDCRepository(db)
+	identity, err := repo.GetIdentity("corp", "248289761001")
+
+	assert.NoError(t, err)
+	assert.Equal(t, &model.UserIdentity{ID: 3, UserID: 7, Provider: "corp", Subject: "248289761001", Email: "ana@corp.example", CreatedAt: createdAt}, identity)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOIDCRepository_LinkIdentity(t *testing.T) {
+	query := regexp.QuoteMeta(`INSERT INTO user_identities (user_id, provider, subject, email, last_login_at)
+			VALUES ($1, $2, $3, $4, NOW()) ON CONFLICT DO NOTHING`)
+	identity := model.UserIdentity{UserID: 7, Provider: "corp", Subject: "248289761001", Email: "ana@corp.example"}
+	event := model.AuditEvent{Action: model.AuditActionLink, EntityType: model.AuditEntityUser, EntityID: "7"}
+
+	t.Run("Success", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(query).WithArgs(7, "corp", "248289761001", "ana@corp.example").WillReturnResult(sqlmock.NewResult(3, 1))
+		expectAuditEvent(mock, "", event)
+		mock.ExpectCommit()
+
+		repo := NewOIDCRepository(db)
+		err = repo.LinkIdentity(identity, event)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Taken", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(query).WithArgs(7, "corp", "248289761001", "ana@corp.example").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		repo := NewOIDCRepository(db)
+		err = repo.LinkIdentity(identity, event)
+
+		assert.Equal(t, sql.ErrNoRows, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestOIDCRepository_UnlinkIdentity(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	event := model.AuditEvent{Action: model.AuditActionUnlink, EntityType: model.AuditEntityUser, EntityID: "7"}
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM user_identities WHERE user_id = $1 AND provider = $2")).
+		WithArgs(7, "corp").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectAuditEvent(mock, "", event)
+	mock.ExpectCommit()
+
+	repo := NewOIDCRepository(db)
+	err = repo.UnlinkIdentity(7, "corp", event)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
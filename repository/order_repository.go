@@ -0,0 +1,393 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"go-api/model"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+var (
+	// ErrStockChanged is returned when a variant no longer has the stock an order takes
+	ErrStockChanged = errors.New("stock changed during checkout")
+	// ErrOrderStatusChanged is returned when the order left the expected status before a transition
+	ErrOrderStatusChanged = errors.New("order status changed concurrently")
+	// ErrPromotionUnavailable is returned when a promotion reached one of its limits or was turned off during checkout
+	ErrPromotionUnavailable = errors.New("promotion limit reached during checkout")
+)
+
+// OrderRepositoryInterface defines the contract for orders, their items and status history
+type OrderRepositoryInterface interface {
+	CreateOrder(order model.Order, cartItemIDs []int) (int, error)
+	GetOrders(filter model.OrderFilter) ([]model.Order, error)
+	GetOrderByID(id int) (*model.Order, error)
+	TransitionOrder(id int, transition model.OrderTransition, restock bool) error
+}
+
+type OrderRepository struct {
+	connection *sql.DB
+}
+
+// Ensure OrderRepository implements OrderRepositoryInterface
+var _ OrderRepositoryInterface = (*OrderRepository)(nil)
+
+func NewOrderRepository(connection *sql.DB) OrderRepositoryInterface {
+	return &OrderRepository{
+		connection: connection,
+	}
+}
+
+const selectOrders = `SELECT id, user_id, status, currency, discount, tax, tax_included, tax_country, tax_state, total, created_at, updated_at FROM orders`
+
+const selectOrderItems = `SELECT oi.id, oi.order_id, oi.product_id, oi.variant_id, oi.product_name, oi.sku, oi.unit_price, o.currency, oi.quantity, oi.discount, oi.tax
+	FROM order_items oi
+	JOIN orders o ON o.id = oi.order_id`
+
+func scanOrder(row rowScanner) (model.Order, error) {
+	var order model.Order
+	var userID sql.NullInt64
+	var taxCountry, taxState sql.NullString
+	var currency, discount, tax, total string
+	err := row.Scan(&order.ID, &userID, &order.Status, &currency, &discount, &tax, &order.TaxIncluded, &taxCountry, &taxState,
+		&total, &order.CreatedAt, &order.UpdatedAt)
+	if err != nil {
+		return model.Order{}, err
+	}
+	if order.Discount, err = model.ParseMoney(discount, currency); err != nil {
+		return model.Order{}, err
+	}
+	if order.Tax, err = model.ParseMoney(tax, currency); err != nil {
+		return model.Order{}, err
+	}
+	if taxCountry.Valid {
+		order.TaxDestination = &model.TaxDestination{Country: taxCountry.String, State: taxState.String}
+	}
+	if order.Total, err = model.ParseMoney(total, currency); err != nil {
+		return model.Order{}, err
+	}
+	order.UserID = nullableInt(userID)
+	order.Items = []model.OrderItem{}
+	return order, nil
+}
+
+// CreateOrder saves the order with its items, taxes and first history entry, takes
+// the ordered quantities from the variant stock, redeems the promotions and
+// removes the purchased items from the cart, all in one transaction.
+// ErrStockChanged means a variant ran out of stock since the order was priced,
+// ErrPromotionUnavailable that a promotion reached one of its limits
+func (or *OrderRepository) CreateOrder(order model.Order, cartItemIDs []int) (int, error) {
+	tx, err := or.connection.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var taxCountry, taxState sql.NullString
+	if order.TaxDestination != nil {
+		taxCountry = sql.NullString{String: order.TaxDestination.Country, Valid: true}
+		taxState = sql.NullString{String: order.TaxDestination.State, Valid: order.TaxDestination.State != ""}
+	}
+
+	var id int
+	err = tx.QueryRow(`INSERT INTO orders (user_id, status, currency, discount, tax, tax_included, tax_country, tax_state, total, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $10) RETURNING id`,
+		order.UserID, order.Status, order.Total.Currency, order.Discount.String(), order.Tax.String(), order.TaxIncluded,
+		taxCountry, taxState, order.Total.String(), order.CreatedAt).Scan(&id)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, item := range order.Items {
+		_, err := tx.Exec(`INSERT INTO order_items (order_id, product_id, variant_id, product_name, sku, unit_price, quantity, discount, tax)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+			id, item.ProductID, item.VariantID, item.ProductName, skuValue(item.SKU), item.UnitPrice.String(), item.Quantity,
+			item.Discount.String(), item.Tax.String())
+		if err != nil {
+			return 0, err
+		}
+		if item.VariantID == nil {
+			continue
+		}
+		result, err := tx.Exec(`UPDATE product_variants SET stock = stock - $2 WHERE id = $1 AND stock >= $2`, item.VariantID, item.Quantity)
+		if err != nil {
+			return 0, err
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return 0, err
+		}
+		if affected == 0 {
+			return 0, fmt.Errorf("%w: variant %d", ErrStockChanged, *item.VariantID)
+		}
+	}
+
+	for _, tax := range order.Taxes {
+		_, err := tx.Exec(`INSERT INTO order_taxes (order_id, name, percent, amount) VALUES ($1, $2, $3, $4)`,
+			id, tax.Name, tax.Percent, tax.Amount.String())
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	if err := redeemPromotions(tx, id, order.UserID, order.Promotions, order.CreatedAt); err != nil {
+		return 0, err
+	}
+
+	for _, transition := range order.History {
+		if err := insertOrderTransition(tx, id, transition); err != nil {
+			return 0, err
+		}
+	}
+
+	if len(cartItemIDs) > 0 {
+		if _, err := tx.Exec(`DELETE FROM cart_items WHERE id = ANY($1)`, pq.Array(cartItemIDs)); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// GetOrders lists the orders matching the filter with their items, newest first
+func (or *OrderRepository) GetOrders(filter model.OrderFilter) ([]model.Order, error) {
+	var conditions []string
+	var args []interface{}
+	if filter.UserID != 0 {
+		args = append(args, filter.UserID)
+		conditions = append(conditions, fmt.Sprintf("user_id = $%d", len(args)))
+	}
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)))
+	}
+	query := selectOrders
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	rows, err := or.connection.Query(query+" ORDER BY id DESC", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	orders := []model.Order{}
+	ids := []int{}
+	for rows.Next() {
+		order, err := scanOrder(rows)
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, order)
+		ids = append(ids, order.ID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(orders) == 0 {
+		return orders, nil
+	}
+
+	if err := or.attachOrderItems(orders, ids); err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+// GetOrderByID returns the order with its items, taxes, promotions and status history
+func (or *OrderRepository) GetOrderByID(id int) (*model.Order, error) {
+	order, err := scanOrder(or.connection.QueryRow(selectOrders+" WHERE id = $1", id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	orders := []model.Order{order}
+	if err := or.attachOrderItems(orders, []int{id}); err != nil {
+		return nil, err
+	}
+	if orders[0].Taxes, err = or.getOrderTaxes(id, order.Total.Currency); err != nil {
+		return nil, err
+	}
+	if orders[0].Promotions, err = or.getOrderPromotions(id, order.Total.Currency); err != nil {
+		return nil, err
+	}
+
+	rows, err := or.connection.Query(`SELECT from_status, to_status, actor_id, note, occurred_at
+		FROM order_status_history WHERE order_id = $1 ORDER BY id`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var transition model.OrderTransition
+		var from sql.NullString
+		var actor sql.NullInt64
+		if err := rows.Scan(&from, &transition.To, &actor, &transition.Note, &transition.OccurredAt); err != nil {
+			return nil, err
+		}
+		transition.From = from.String
+		transition.ActorID = nullableInt(actor)
+		orders[0].History = append(orders[0].History, transition)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return &orders[0], nil
+}
+
+// TransitionOrder moves the order from transition.From to transition.To and
+// records the change; restock returns the ordered quantities to the variant
+// stock and cancelled orders give back their promotion uses.
+// ErrOrderStatusChanged means the order was no longer in transition.From
+func (or *OrderRepository) TransitionOrder(id int, transition model.OrderTransition, restock bool) error {
+	tx, err := or.connection.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`UPDATE orders SET status = $3, updated_at = $4 WHERE id = $1 AND status = $2`,
+		id, transition.From, transition.To, transition.OccurredAt)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrOrderStatusChanged
+	}
+
+	if err := insertOrderTransition(tx, id, transition); err != nil {
+		return err
+	}
+	if restock {
+		_, err := tx.Exec(`UPDATE product_variants v SET stock = v.stock + oi.quantity
+			FROM order_items oi WHERE oi.order_id = $1 AND oi.variant_id = v.id`, id)
+		if err != nil {
+			return err
+		}
+	}
+	if transition.To == model.OrderStatusCancelled {
+		if err := releasePromotions(tx, id, transition.OccurredAt); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// --- Helper Functions ---
+
+// attachOrderItems loads the items of all the given orders in one query
+func (or *OrderRepository) attachOrderItems(orders []model.Order, ids []int) error {
+	index := make(map[int]int, len(orders))
+	for i, order := range orders {
+		index[order.ID] = i
+	}
+
+	rows, err := or.connection.Query(selectOrderItems+" WHERE oi.order_id = ANY($1) ORDER BY oi.order_id, oi.id", pq.Array(ids))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item model.OrderItem
+		var productID, variantID sql.NullInt64
+		var sku sql.NullString
+		var unitPrice, currency, discount, tax string
+		err := rows.Scan(&item.ID, &item.OrderID, &productID, &variantID, &item.ProductName, &sku, &unitPrice, &currency, &item.Quantity, &discount, &tax)
+		if err != nil {
+			return err
+		}
+		if item.UnitPrice, err = model.ParseMoney(unitPrice, currency); err != nil {
+			return err
+		}
+		if item.Discount, err = model.ParseMoney(discount, currency); err != nil {
+			return err
+		}
+		if item.Tax, err = model.ParseMoney(tax, currency); err != nil {
+			return err
+		}
+		item.ProductID = nullableInt(productID)
+		item.VariantID = nullableInt(variantID)
+		item.SKU = sku.String
+		if item.Subtotal, err = item.UnitPrice.Mul(int64(item.Quantity)); err != nil {
+			return err
+		}
+
+		order := &orders[index[item.OrderID]]
+		order.Items = append(order.Items, item)
+	}
+	return rows.Err()
+}
+
+// getOrderTaxes lists the taxes of the order as they were itemized at checkout
+func (or *OrderRepository) getOrderTaxes(orderID int, currency string) ([]model.TaxAmount, error) {
+	rows, err := or.connection.Query(`SELECT name, percent, amount FROM order_taxes WHERE order_id = $1 ORDER BY id`, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var taxes []model.TaxAmount
+	for rows.Next() {
+		var tax model.TaxAmount
+		var amount string
+		if err := rows.Scan(&tax.Name, &tax.Percent, &amount); err != nil {
+			return nil, err
+		}
+		if tax.Amount, err = model.ParseMoney(amount, currency); err != nil {
+			return nil, err
+		}
+		taxes = append(taxes, tax)
+	}
+	return taxes, rows.Err()
+}
+
+// getOrderPromotions lists the promotions redeemed by the order, in the order
+// they were applied
+func (or *OrderRepository) getOrderPromotions(orderID int, currency string) ([]model.AppliedPromotion, error) {
+	rows, err := or.connection.Query(`SELECT r.promotion_id, p.code, p.name, r.discount
+		FROM promotion_redemptions r
+		JOIN promotions p ON p.id = r.promotion_id
+		WHERE r.order_id = $1 ORDER BY r.id`, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var promotions []model.AppliedPromotion
+	for rows.Next() {
+		var applied model.AppliedPromotion
+		var code sql.NullString
+		var discount string
+		if err := rows.Scan(&applied.PromotionID, &code, &applied.Name, &discount); err != nil {
+			return nil, err
+		}
+		if applied.Discount, err = model.ParseMoney(discount, currency); err != nil {
+			return nil, err
+		}
+		applied.Code = code.String
+		promotions = append(promotions, applied)
+	}
+	return promotions, rows.Err()
+}
+
+func insertOrderTransition(tx *sql.Tx, orderID int, transition model.OrderTransition) error {
+	_, err := tx.Exec(`INSERT INTO order_status_history (order_id, from_status, to_status, actor_id, note, occurred_at)
+		VALUES ($1, NULLIF($2, ''), $3, $4, $5, $6)`,
+		orderID, transition.From, transition.To, transition.ActorID, transition.Note, transition.OccurredAt)
+	return err
+}
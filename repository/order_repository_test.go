@@ -0,0 +1,315 @@
+package repository
+
+import (
+	"errors"
+	"go-api/model"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrderRepository_CreateOrder(t *testing.T) {
+	placedAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	userID, productID, variantID := 7, 1, 2
+	order := model.Order{
+		UserID: &userID,
+		Status: model.OrderStatusPending,
+		Items: []model.OrderItem{{
+			ProductID: &productID, VariantID: &variantID, ProductName: "Camiseta", SKU: "CAM-AZUL-M",
+			UnitPrice: model.Money{Amount: 4990, Currency: "BRL"}, Quantity: 2, Discount: model.Money{Currency: "BRL"},
+			Tax: model.Money{Currency: "BRL"},
+		}},
+		Discount:  model.Money{Currency: "BRL"},
+		Tax:       model.Money{Currency: "BRL"},
+		Total:     model.Money{Amount: 9980, Currency: "BRL"},
+		CreatedAt: placedAt,
+		History:   []model.OrderTransition{{To: model.OrderStatusPending, ActorID: &userID, OccurredAt: placedAt}},
+	}
+
+	t.Run("Takes Stock And Empties The Cart", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(`INSERT INTO orders \(user_id, status, currency, discount, tax, tax_included, tax_country, tax_state, total, created_at, updated_at\) VALUES \(\$1, \$2, \$3, \$4, \$5, \$6, \$7, \$8, \$9, \$10, \$10\) RETURNING id`).
+			WithArgs(int64(7), "pending", "BRL", "0.00", "0.00", false, nil, nil, "99.80", placedAt).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(10))
+		mock.ExpectExec(`INSERT INTO order_items`).
+			WithArgs(10, int64(1), int64(2), "Camiseta", "CAM-AZUL-M", "49.90", 2, "0.00", "0.00").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(`UPDATE product_variants SET stock = stock - \$2 WHERE id = \$1 AND stock >= \$2`).
+			WithArgs(int64(2), 2).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`INSERT INTO order_status_history`).
+			WithArgs(10, "", "pending", int64(7), "", placedAt).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(`DELETE FROM cart_items WHERE id = ANY\(\$1\)`).
+			WithArgs(sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		repo := NewOrderRepository(db)
+		id, err := repo.CreateOrder(order, []int{5})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 10, id)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Stock Changed Rolls Back", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(`INSERT INTO orders`).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(10))
+		mock.ExpectExec(`INSERT INTO order_items`).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(`UPDATE product_variants SET stock = stock - \$2`).
+			WithArgs(int64(2), 2).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		repo := NewOrderRepository(db)
+		_, err = repo.CreateOrder(order, []int{5})
+
+		assert.True(t, errors.Is(err, ErrStockChanged))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Records The Taxes", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		tax := model.Money{Amount: 1522, Currency: "BRL"}
+		taxed := order
+		taxed.Items = []model.OrderItem{order.Items[0]}
+		taxed.Items[0].Tax = tax
+		taxed.Tax = tax
+		taxed.TaxIncluded = true
+		taxed.TaxDestination = &model.TaxDestination{Country: "BR", State: "SP"}
+		taxed.Taxes = []model.TaxAmount{{Name: "ICMS", Percent: "18", Amount: tax}}
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(`INSERT INTO orders`).
+			WithArgs(int64(7), "pending", "BRL", "0.00", "15.22", true, "BR", "SP", "99.80", placedAt).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(10))
+		mock.ExpectExec(`INSERT INTO order_items`).
+			WithArgs(10, int64(1), int64(2), "Camiseta", "CAM-AZUL-M", "49.90", 2, "0.00", "15.22").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(`UPDATE product_variants SET stock = stock - \$2`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`INSERT INTO order_taxes \(order_id, name, percent, amount\) VALUES \(\$1, \$2, \$3, \$4\)`).
+			WithArgs(10, "ICMS", "18", "15.22").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(`INSERT INTO order_status_history`).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(`DELETE FROM cart_items`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		repo := NewOrderRepository(db)
+		_, err = repo.CreateOrder(taxed, []int{5})
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	discounted := order
+	discounted.Discount = model.Money{Amount: 998, Currency: "BRL"}
+	discounted.Total = model.Money{Amount: 8982, Currency: "BRL"}
+	discounted.Promotions = []model.AppliedPromotion{{PromotionID: 3, Code: "BEMVINDO10", Name: "Boas-vindas", Discount: discounted.Discount}}
+
+	t.Run("Redeems The Promotions", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(`INSERT INTO orders`).
+			WithArgs(int64(7), "pending", "BRL", "9.98", "0.00", false, nil, nil, "89.82", placedAt).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(10))
+		mock.ExpectExec(`INSERT INTO order_items`).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(`UPDATE product_variants SET stock = stock - \$2`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(`UPDATE promotions SET uses = uses \+ 1 WHERE id = \$1 AND active AND \(usage_limit IS NULL OR uses < usage_limit\) RETURNING per_user_limit`).
+			WithArgs(3).
+			WillReturnRows(sqlmock.NewRows([]string{"per_user_limit"}).AddRow(1))
+		mock.ExpectQuery(`SELECT COUNT\(\*\) FROM promotion_redemptions WHERE promotion_id = \$1 AND user_id = \$2 AND released_at IS NULL`).
+			WithArgs(3, 7).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		mock.ExpectExec(`INSERT INTO promotion_redemptions`).
+			WithArgs(3, 10, int64(7), "9.98", placedAt).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(`INSERT INTO order_status_history`).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(`DELETE FROM cart_items`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		repo := NewOrderRepository(db)
+		id, err := repo.CreateOrder(discounted, []int{5})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 10, id)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Usage Limit Reached Rolls Back", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(`INSERT INTO orders`).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(10))
+		mock.ExpectExec(`INSERT INTO order_items`).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(`UPDATE product_variants SET stock = stock - \$2`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(`UPDATE promotions SET uses = uses \+ 1`).
+			WithArgs(3).
+			WillReturnRows(sqlmock.NewRows([]string{"per_user_limit"}))
+		mock.ExpectRollback()
+
+		repo := NewOrderRepository(db)
+		_, err = repo.CreateOrder(discounted, []int{5})
+
+		assert.True(t, errors.Is(err, ErrPromotionUnavailable))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Per User Limit Reached Rolls Back", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(`INSERT INTO orders`).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(10))
+		mock.ExpectExec(`INSERT INTO order_items`).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(`UPDATE product_variants SET stock = stock - \$2`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(`UPDATE promotions SET uses = uses \+ 1`).
+			WillReturnRows(sqlmock.NewRows([]string{"per_user_limit"}).AddRow(1))
+		mock.ExpectQuery(`SELECT COUNT\(\*\) FROM promotion_redemptions`).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+		mock.ExpectRollback()
+
+		repo := NewOrderRepository(db)
+		_, err = repo.CreateOrder(discounted, []int{5})
+
+		assert.True(t, errors.Is(err, ErrPromotionUnavailable))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestOrderRepository_GetOrderByID(t *testing.T) {
+	t.Run("With Items And History", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		placedAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+		mock.ExpectQuery(`SELECT id, user_id, status, currency, discount, tax, tax_included, tax_country, tax_state, total, created_at, updated_at FROM orders WHERE id = \$1`).
+			WithArgs(10).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "status", "currency", "discount", "tax", "tax_included", "tax_country", "tax_state", "total", "created_at", "updated_at"}).
+				AddRow(10, nil, "paid", "BRL", "9.980", "13.700", true, "BR", "SP", "89.820", placedAt, placedAt))
+		mock.ExpectQuery(`FROM order_items oi JOIN orders o ON o.id = oi.order_id WHERE oi.order_id = ANY\(\$1\)`).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "order_id", "product_id", "variant_id", "product_name", "sku", "unit_price", "currency", "quantity", "discount", "tax"}).
+				AddRow(1, 10, nil, nil, "Camiseta", "CAM-AZUL-M", "49.900", "BRL", 2, "9.980", "13.700"))
+		mock.ExpectQuery(`SELECT name, percent, amount FROM order_taxes WHERE order_id = \$1 ORDER BY id`).
+			WithArgs(10).
+			WillReturnRows(sqlmock.NewRows([]string{"name", "percent", "amount"}).AddRow("ICMS", "18", "13.700"))
+		mock.ExpectQuery(`FROM promotion_redemptions r JOIN promotions p ON p.id = r.promotion_id WHERE r.order_id = \$1`).
+			WithArgs(10).
+			WillReturnRows(sqlmock.NewRows([]string{"promotion_id", "code", "name", "discount"}).
+				AddRow(3, "BEMVINDO10", "Boas-vindas", "9.980"))
+		mock.ExpectQuery(`SELECT from_status, to_status, actor_id, note, occurred_at FROM order_status_history WHERE order_id = \$1 ORDER BY id`).
+			WithArgs(10).
+			WillReturnRows(sqlmock.NewRows([]string{"from_status", "to_status", "actor_id", "note", "occurred_at"}).
+				AddRow(nil, "pending", 7, "", placedAt).
+				AddRow("pending", "paid", nil, "Pagamento confirmado", placedAt))
+
+		repo := NewOrderRepository(db)
+		order, err := repo.GetOrderByID(10)
+
+		assert.NoError(t, err)
+		assert.Nil(t, order.UserID)
+		assert.Equal(t, model.Money{Amount: 8982, Currency: "BRL"}, order.Total)
+		assert.Equal(t, model.Money{Amount: 998, Currency: "BRL"}, order.Items[0].Discount)
+		assert.Equal(t, model.Money{Amount: 1370, Currency: "BRL"}, order.Tax)
+		assert.True(t, order.TaxIncluded)
+		assert.Equal(t, &model.TaxDestination{Country: "BR", State: "SP"}, order.TaxDestination)
+		assert.Equal(t, []model.TaxAmount{{Name: "ICMS", Percent: "18", Amount: order.Tax}}, order.Taxes)
+		assert.Equal(t, model.Money{Amount: 1370, Currency: "BRL"}, order.Items[0].Tax)
+		assert.Equal(t, "BEMVINDO10", order.Promotions[0].Code)
+		assert.Nil(t, order.Items[0].ProductID)
+		assert.Equal(t, model.Money{Amount: 9980, Currency: "BRL"}, order.Items[0].Subtotal)
+		assert.Len(t, order.History, 2)
+		assert.Equal(t, "", order.History[0].From)
+		assert.Equal(t, 7, *order.History[0].ActorID)
+		assert.Nil(t, order.History[1].ActorID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestOrderRepository_TransitionOrder(t *testing.T) {
+	changedAt := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	transition := model.OrderTransition{From: model.OrderStatusPending, To: model.OrderStatusCancelled, OccurredAt: changedAt}
+
+	t.Run("Cancel Restocks And Releases Promotions", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(`UPDATE orders SET status = \$3, updated_at = \$4 WHERE id = \$1 AND status = \$2`).
+			WithArgs(10, "pending", "cancelled", changedAt).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`INSERT INTO order_status_history`).
+			WithArgs(10, "pending", "cancelled", nil, "", changedAt).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(`UPDATE product_variants v SET stock = v.stock \+ oi.quantity FROM order_items oi WHERE oi.order_id = \$1 AND oi.variant_id = v.id`).
+			WithArgs(10).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`UPDATE promotions p SET uses = p.uses - 1 FROM promotion_redemptions r WHERE r.order_id = \$1`).
+			WithArgs(10).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`UPDATE promotion_redemptions SET released_at = \$2 WHERE order_id = \$1 AND released_at IS NULL`).
+			WithArgs(10, changedAt).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		repo := NewOrderRepository(db)
+		err = repo.TransitionOrder(10, transition, true)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Status Changed Concurrently", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(`UPDATE orders SET status = \$3`).
+			WithArgs(10, "pending", "cancelled", changedAt).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		repo := NewOrderRepository(db)
+		err = repo.TransitionOrder(10, transition, true)
+
+		assert.Equal(t, ErrOrderStatusChanged, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"database/sql"
+	"go-api/model"
+	"time"
+)
+
+// OutboxRepositoryInterface defines the contract for delivering the emails of
+// the outbox; the emails are enqueued by the repositories whose changes send
+// them, in the same transaction
+type OutboxRepositoryInterface interface {
+	ClaimDueEmails(limit int, lease time.Duration, maxAttempts int) ([]model.OutboxEmail, error)
+	MarkEmailSent(id int64) error
+	MarkEmailFailed(id int64, reason string, retryAt time.Time) error
+}
+
+type OutboxRepository struct {
+	connection *sql.DB
+}
+
+// Ensure OutboxRepository implements OutboxRepositoryInterface
+var _ OutboxRepositoryInterface = (*OutboxRepository)(nil)
+
+func NewOutboxRepository(connection *sql.DB) OutboxRepositoryInterface {
+	return &OutboxRepository{
+		connection: connection,
+	}
+}
+
+// ClaimDueEmails takes up to limit unsent emails whose next attempt is due
+// and that have attempts left, counting the attempt and pushing the next one
+// lease into the future. Another instance skips the claimed rows, and an
+// email whose sender crashed is retried once the lease is over
+func (ob *OutboxRepository) ClaimDueEmails(limit int, lease time.Duration, maxAttempts int) ([]model.OutboxEmail, error) {
+	rows, err := ob.connection.Query(`UPDATE email_outbox SET attempts = attempts + 1, next_attempt_at = NOW() + make_interval(secs => $2)
+		WHERE id IN (
+			SELECT id FROM email_outbox
+			WHERE sent_at IS NULL AND next_attempt_at <= NOW() AND attempts < $3
+			ORDER BY next_attempt_at, id LIMIT $1
+			FOR UPDATE SKIP LOCKED)
+		RETURNING id, recipient, subject, body, attempts, last_error, created_at, next_attempt_at`,
+		limit, lease.Seconds(), maxAttempts)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	emails := []model.OutboxEmail{}
+	for rows.Next() {
+		var email model.OutboxEmail
+		if err := rows.Scan(&email.ID, &email.Recipient, &email.Subject, &email.Body, &email.Attempts,
+			&email.LastError, &email.CreatedAt, &email.NextAttemptAt); err != nil {
+			return nil, err
+		}
+		emails = append(emails, email)
+	}
+	return emails, rows.Err()
+}
+
+// MarkEmailSent records the delivery and clears the body, which may carry
+// secrets such as reset links
+func (ob *OutboxRepository) MarkEmailSent(id int64) error {
+	_, err := ob.connection.Exec(`UPDATE email_outbox SET sent_at = NOW(), body = '', last_error = '' WHERE id = $1`, id)
+	return err
+}
+
+// MarkEmailFailed records why the delivery failed and when to try again
+func (ob *OutboxRepository) MarkEmailFailed(id int64, reason string, retryAt time.Time) error {
+	_, err := ob.connection.Exec(`UPDATE email_outbox SET last_error = $2, next_attempt_at = $3 WHERE id = $1`, id, reason, retryAt)
+	return err
+}
+
+// enqueueEmail adds the email to the outbox within the transaction of the
+// change that sends it
+func enqueueEmail(tx *sql.Tx, email model.OutboxEmail) error {
+	_, err := tx.Exec(`INSERT INTO email_outbox (recipient, subject, body) VALUES ($1, $2, $3)`,
+		email.Recipient, email.Subject, email.Body)
+	return err
+}
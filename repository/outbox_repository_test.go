@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOutboxRepository_ClaimDueEmails(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		createdAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+		mock.ExpectQuery(`UPDATE email_outbox SET attempts = attempts \+ 1, next_attempt_at = NOW\(\) \+ make_interval\(secs => \$2\)\s+WHERE id IN \(.+FOR UPDATE SKIP LOCKED\)`).
+			WithArgs(10, float64(60), 5).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "recipient", "subject", "body", "attempts", "last_error", "created_at", "next_attempt_at"}).
+				AddRow(3, "ana@example.com", "Redefinição de senha", "link", 1, "", createdAt, createdAt.Add(time.Minute)))
+
+		repo := NewOutboxRepository(db)
+		emails, err := repo.ClaimDueEmails(10, time.Minute, 5)
+
+		assert.NoError(t, err)
+		assert.Len(t, emails, 1)
+		assert.Equal(t, int64(3), emails[0].ID)
+		assert.Equal(t, "link", emails[0].Body)
+		assert.Equal(t, 1, emails[0].Attempts)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestOutboxRepository_MarkEmail(t *testing.T) {
+	t.Run("Sent Clears The Body", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE email_outbox SET sent_at = NOW(), body = '', last_error = '' WHERE id = $1")).
+			WithArgs(int64(3)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		assert.NoError(t, NewOutboxRepository(db).MarkEmailSent(3))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Failed", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		retryAt := time.Date(2026, 3, 1, 12, 2, 0, 0, time.UTC)
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE email_outbox SET last_error = $2, next_attempt_at = $3 WHERE id = $1")).
+			WithArgs(int64(3), "connection refused", retryAt).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		assert.NoError(t, NewOutboxRepository(db).MarkEmailFailed(3, "connection refused", retryAt))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"database/sql"
+	"go-api/model"
+	"strconv"
+)
+
+// PasswordResetRepositoryInterface defines the contract for the password
+// reset tokens
+type PasswordResetRepositoryInterface interface {
+	CreatePasswordResetToken(token model.PasswordResetToken, email model.OutboxEmail) error
+	GetPasswordResetUserID(tokenHash string) (int, error)
+	ResetPassword(tokenHash, password string, event model.AuditEvent) (int, error)
+}
+
+type PasswordResetRepository struct {
+	connection *sql.DB
+}
+
+// Ensure PasswordResetRepository implements PasswordResetRepositoryInterface
+var _ PasswordResetRepositoryInterface = (*PasswordResetRepository)(nil)
+
+func NewPasswordResetRepository(connection *sql.DB) PasswordResetRepositoryInterface {
+	return &PasswordResetRepository{
+		connection: connection,
+	}
+}
+
+// CreatePasswordResetToken stores the token in place of the pending ones of
+// the user, so only the latest email works, and enqueues the email carrying
+// it in the same transaction
+func (pr *PasswordResetRepository) CreatePasswordResetToken(token model.PasswordResetToken, email model.OutboxEmail) error {
+	tx, err := pr.connection.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := invalidatePasswordResetTokens(tx, token.UserID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`INSERT INTO password_reset_tokens (user_id, token_hash, expires_at) VALUES ($1, $2, $3)`,
+		token.UserID, token.TokenHash, token.ExpiresAt); err != nil {
+		return err
+	}
+	if err := enqueueEmail(tx, email); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetPasswordResetUserID returns the user of a token that can still be used,
+// or zero when it is unknown, used or expired
+func (pr *PasswordResetRepository) GetPasswordResetUserID(tokenHash string) (int, error) {
+	var userID int
+	err := pr.connection.QueryRow(`SELECT user_id FROM password_reset_tokens
+		WHERE token_hash = $1 AND used_at IS NULL AND expires_at > NOW()`, tokenHash).Scan(&userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return userID, nil
+}
+
+// ResetPassword uses the token, sets the password of its user and
+// invalidates the other tokens of the user, returning the user ID. The token
+// is taken by a single UPDATE, so two requests with it cannot both succeed.
+// It returns sql.ErrNoRows when the token is unknown, used or expired, or
+// when its user is in the trash. The user is the actor of the event
+func (pr *PasswordResetRepository) ResetPassword(tokenHash, password string, event model.AuditEvent) (int, error) {
+	var userID int
+	err := withAuditEvent(pr.connection, &event, func(tx *sql.Tx) error {
+		err := tx.QueryRow(`UPDATE password_reset_tokens SET used_at = NOW()
+			WHERE token_hash = $1 AND used_at IS NULL AND expires_at > NOW() RETURNING user_id`, tokenHash).Scan(&userID)
+		if err != nil {
+			return err
+		}
+
+		result, err := tx.Exec(`UPDATE users SET password = $1, updated_at = NOW(), updated_by = $2 WHERE id = $2 AND deleted_at IS NULL`, password, userID)
+		if err != nil {
+			return err
+		}
+		if rows, err := result.RowsAffected(); err != nil {
+			return err
+		} else if rows == 0 {
+			return sql.ErrNoRows
+		}
+
+		event.ActorID = &userID
+		event.EntityID = strconv.Itoa(userID)
+		return invalidatePasswordResetTokens(tx, userID)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return userID, nil
+}
+
+// invalidatePasswordResetTokens marks the pending tokens of the user as used;
+// every change of the password calls it
+func invalidatePasswordResetTokens(tx *sql.Tx, userID int) error {
+	_, err := tx.Exec(`UPDATE password_reset_tokens SET used_at = NOW() WHERE user_id = $1 AND used_at IS NULL`, userID)
+	return err
+}
@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"database/sql"
+	"go-api/model"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPasswordResetRepository_CreatePasswordResetToken(t *testing.T) {
+	t.Run("Replaces The Pending Tokens And Enqueues The Email", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		expiresAt := time.Date(2026, 3, 1, 12, 30, 0, 0, time.UTC)
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE password_reset_tokens SET used_at = NOW() WHERE user_id = $1 AND used_at IS NULL")).
+			WithArgs(7).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO password_reset_tokens (user_id, token_hash, expires_at) VALUES ($1, $2, $3)")).
+			WithArgs(7, "hash", expiresAt).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO email_outbox (recipient, subject, body) VALUES ($1, $2, $3)")).
+			WithArgs("ana@example.com", "Redefinição de senha", "link").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		repo := NewPasswordResetRepository(db)
+		err = repo.CreatePasswordResetToken(
+			model.PasswordResetToken{UserID: 7, TokenHash: "hash", ExpiresAt: expiresAt},
+			model.OutboxEmail{Recipient: "ana@example.com", Subject: "Redefinição de senha", Body: "link"})
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestPasswordResetRepository_GetPasswordResetUserID(t *testing.T) {
+	query := regexp.QuoteMeta(`SELECT user_id FROM password_reset_tokens
+		WHERE token_hash = $1 AND used_at IS NULL AND expires_at > NOW()`)
+
+	t.Run("Valid Token", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectQuery(query).WithArgs("hash").WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(7))
+
+		repo := NewPasswordResetRepository(db)
+		userID, err := repo.GetPasswordResetUserID("hash")
+
+		assert.NoError(t, err)
+		assert.Equal(t, 7, userID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Used Or Expired Token", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectQuery(query).WithArgs("hash").WillReturnRows(sqlmock.NewRows([]string{"user_id"}))
+
+		repo := NewPasswordResetRepository(db)
+		userID, err := repo.GetPasswordResetUserID("hash")
+
+		assert.NoError(t, err)
+		assert.Zero(t, userID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestPasswordResetRepository_ResetPassword(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		userID := 7
+		event := model.AuditEvent{Action: model.AuditActionPasswordReset, EntityType: model.AuditEntityUser}
+		recorded := event
+		recorded.ActorID, recorded.EntityID = &userID, "7"
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta("UPDATE password_reset_tokens SET used_at = NOW() WHERE token_hash = $1 AND used_at IS NULL AND expires_at > NOW() RETURNING user_id")).
+			WithArgs("hash").
+			WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(7))
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE users SET password = $1, updated_at = NOW(), updated_by = $2 WHERE id = $2 AND deleted_at IS NULL")).
+			WithArgs("bcrypt", 7).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE password_reset_tokens SET used_at = NOW() WHERE user_id = $1 AND used_at IS NULL")).
+			WithArgs(7).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		expectAuditEvent(mock, "", recorded)
+		mock.ExpectCommit()
+
+		repo := NewPasswordResetRepository(db)
+		id, err := repo.ResetPassword("hash", "bcrypt", event)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 7, id)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Used Or Expired Token", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta("UPDATE password_reset_tokens SET used_at = NOW()")).
+			WithArgs("hash").
+			WillReturnRows(sqlmock.NewRows([]string{"user_id"}))
+		mock.ExpectRollback()
+
+		repo := NewPasswordResetRepository(db)
+		_, err = repo.ResetPassword("hash", "bcrypt", model.AuditEvent{})
+
+		assert.Equal(t, sql.ErrNoRows, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("User In The Trash", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta("UPDATE password_reset_tokens SET used_at = NOW()")).
+			WithArgs("hash").
+			WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(7))
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE users SET password = $1")).
+			WithArgs("bcrypt", 7).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		repo := NewPasswordResetRepository(db)
+		_, err = repo.ResetPassword("hash", "bcrypt", model.AuditEvent{})
+
+		assert.Equal(t, sql.ErrNoRows, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"database/sql"
+	"go-api/model"
+)
+
+// PaymentRepositoryInterface defines the contract for order payments and the
+// webhook events already processed
+type PaymentRepositoryInterface interface {
+	CreatePayment(payment model.Payment) (int, error)
+	GetPaymentByIntentID(provider, intentID string) (*model.Payment, error)
+	GetOrderPayments(orderID int) ([]model.Payment, error)
+	UpdatePaymentStatus(id int, status string) error
+	RecordWebhookEvent(provider, eventID, eventType string) (bool, error)
+	ForgetWebhookEvent(provider, eventID string) error
+}
+
+type PaymentRepository struct {
+	connection *sql.DB
+}
+
+// Ensure PaymentRepository implements PaymentRepositoryInterface
+var _ PaymentRepositoryInterface = (*PaymentRepository)(nil)
+
+func NewPaymentRepository(connection *sql.DB) PaymentRepositoryInterface {
+	return &PaymentRepository{
+		connection: connection,
+	}
+}
+
+const selectPayments = `SELECT id, order_id, provider, intent_id, status, currency, amount, created_at, updated_at FROM payments`
+
+func scanPayment(row rowScanner) (model.Payment, error) {
+	var payment model.Payment
+	var currency, amount string
+	err := row.Scan(&payment.ID, &payment.OrderID, &payment.Provider, &payment.IntentID, &payment.Status,
+		&currency, &amount, &payment.CreatedAt, &payment.UpdatedAt)
+	if err != nil {
+		return model.Payment{}, err
+	}
+	if payment.Amount, err = model.ParseMoney(amount, currency); err != nil {
+		return model.Payment{}, err
+	}
+	return payment, nil
+}
+
+func (pr *PaymentRepository) CreatePayment(payment model.Payment) (int, error) {
+	var id int
+	err := pr.connection.QueryRow(`INSERT INTO payments (order_id, provider, intent_id, status, currency, amount)
+		VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`,
+		payment.OrderID, payment.Provider, payment.IntentID, payment.Status, payment.Amount.Currency, payment.Amount.String()).Scan(&id)
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+func (pr *PaymentRepository) GetPaymentByIntentID(provider, intentID string) (*model.Payment, error) {
+	payment, err := scanPayment(pr.connection.QueryRow(selectPayments+" WHERE provider = $1 AND intent_id = $2", provider, intentID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &payment, nil
+}
+
+// GetOrderPayments lists the payment attempts of the order, oldest first
+func (pr *PaymentRepository) GetOrderPayments(orderID int) ([]model.Payment, error) {
+	rows, err := pr.connection.Query(selectPayments+" WHERE order_id = $1 ORDER BY id", orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	payments := []model.Payment{}
+	for rows.Next() {
+		payment, err := scanPayment(rows)
+		if err != nil {
+			return nil, err
+		}
+		payments = append(payments, payment)
+	}
+	return payments, rows.Err()
+}
+
+func (pr *PaymentRepository) UpdatePaymentStatus(id int, status string) error {
+	_, err := pr.connection.Exec(`UPDATE payments SET status = $2, updated_at = NOW() WHERE id = $1`, id, status)
+	return err
+}
+
+// RecordWebhookEvent marks the event as processed; false means it already was
+func (pr *PaymentRepository) RecordWebhookEvent(provider, eventID, eventType string) (bool, error) {
+	result, err := pr.connection.Exec(`INSERT INTO payment_webhook_events (provider, event_id, event_type)
+		VALUES ($1, $2, $3) ON CONFLICT (provider, event_id) DO NOTHING`, provider, eventID, eventType)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
+
+// ForgetWebhookEvent removes the mark of an event whose processing failed, so
+// the provider can deliver it again
+func (pr *PaymentRepository) ForgetWebhookEvent(provider, eventID string) error {
+	_, err := pr.connection.Exec(`DELETE FROM payment_webhook_events WHERE provider = $1 AND event_id = $2`, provider, eventID)
+	return err
+}
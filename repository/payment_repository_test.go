@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"go-api/model"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPaymentRepository_CreatePayment(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectQuery(`INSERT INTO payments \(order_id, provider, intent_id, status, currency, amount\) VALUES \(\$1, \$2, \$3, \$4, \$5, \$6\) RETURNING id`).
+			WithArgs(10, "fake", "pi_1", "succeeded", "BRL", "99.80").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+
+		repo := NewPaymentRepository(db)
+		id, err := repo.CreatePayment(model.Payment{
+			OrderID:  10,
+			Provider: "fake",
+			IntentID: "pi_1",
+			Status:   model.PaymentStatusSucceeded,
+			Amount:   model.Money{Amount: 9980, Currency: "BRL"},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, id)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestPaymentRepository_GetPaymentByIntentID(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		createdAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+		mock.ExpectQuery(`FROM payments WHERE provider = \$1 AND intent_id = \$2`).
+			WithArgs("fake", "pi_1").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "order_id", "provider", "intent_id", "status", "currency", "amount", "created_at", "updated_at"}).
+				AddRow(3, 10, "fake", "pi_1", "pending", "BRL", "99.800", createdAt, createdAt))
+
+		repo := NewPaymentRepository(db)
+		payment, err := repo.GetPaymentByIntentID("fake", "pi_1")
+
+		assert.NoError(t, err)
+		assert.Equal(t, 10, payment.OrderID)
+		assert.Equal(t, model.Money{Amount: 9980, Currency: "BRL"}, payment.Amount)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestPaymentRepository_RecordWebhookEvent(t *testing.T) {
+	t.Run("New And Repeated Event", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectExec(`INSERT INTO payment_webhook_events \(provider, event_id, event_type\) VALUES \(\$1, \$2, \$3\) ON CONFLICT \(provider, event_id\) DO NOTHING`).
+			WithArgs("fake", "evt_1", "payment.succeeded").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`INSERT INTO payment_webhook_events`).
+			WithArgs("fake", "evt_1", "payment.succeeded").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		repo := NewPaymentRepository(db)
+		first, err := repo.RecordWebhookEvent("fake", "evt_1", "payment.succeeded")
+		assert.NoError(t, err)
+		assert.True(t, first)
+		second, err := repo.RecordWebhookEvent("fake", "evt_1", "payment.succeeded")
+		assert.NoError(t, err)
+		assert.False(t, second)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,229 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"go-api/model"
+	"math/big"
+
+	"github.com/lib/pq"
+)
+
+// PricingRepositoryInterface defines the contract for price lists and exchange rates
+type PricingRepositoryInterface interface {
+	GetPriceLists() ([]model.PriceList, error)
+	GetPriceListByID(id int) (*model.PriceList, error)
+	GetPriceListByCode(code string) (*model.PriceList, error)
+	CreatePriceList(list model.PriceList) (int, error)
+	GetPriceListItems(priceListID int) ([]model.PriceListItem, error)
+	SetPriceListItem(item model.PriceListItem) error
+	DeletePriceListItem(priceListID, productID int) error
+	GetListPrices(currency, market string, productIDs []int) (map[int]model.ListPrice, error)
+	GetExchangeRates() ([]model.ExchangeRate, error)
+	GetExchangeRate(base, quote string) (*model.ExchangeRate, error)
+	UpsertExchangeRates(rates []model.ExchangeRate) error
+}
+
+type PricingRepository struct {
+	connection *sql.DB
+}
+
+// Ensure PricingRepository implements PricingRepositoryInterface
+var _ PricingRepositoryInterface = (*PricingRepository)(nil)
+
+func NewPricingRepository(connection *sql.DB) PricingRepositoryInterface {
+	return &PricingRepository{
+		connection: connection,
+	}
+}
+
+// exchangeRateScale is the number of decimal places stored for exchange rates
+const exchangeRateScale = 10
+
+func scanPriceList(scanner rowScanner) (model.PriceList, error) {
+	var list model.PriceList
+	var market sql.NullString
+	err := scanner.Scan(&list.ID, &list.Code, &list.Name, &list.Currency, &market)
+	if err != nil {
+		return model.PriceList{}, err
+	}
+	list.Market = market.String
+	return list, nil
+}
+
+func (pr *PricingRepository) GetPriceLists() ([]model.PriceList, error) {
+	rows, err := pr.connection.Query(`SELECT id, code, name, currency, market FROM price_lists ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var lists []model.PriceList
+	for rows.Next() {
+		list, err := scanPriceList(rows)
+		if err != nil {
+			return nil, err
+		}
+		lists = append(lists, list)
+	}
+	return lists, rows.Err()
+}
+
+func (pr *PricingRepository) getPriceList(query string, arg interface{}) (*model.PriceList, error) {
+	list, err := scanPriceList(pr.connection.QueryRow(query, arg))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &list, nil
+}
+
+func (pr *PricingRepository) GetPriceListByID(id int) (*model.PriceList, error) {
+	return pr.getPriceList(`SELECT id, code, name, currency, market FROM price_lists WHERE id = $1`, id)
+}
+
+func (pr *PricingRepository) GetPriceListByCode(code string) (*model.PriceList, error) {
+	return pr.getPriceList(`SELECT id, code, name, currency, market FROM price_lists WHERE code = $1`, code)
+}
+
+func (pr *PricingRepository) CreatePriceList(list model.PriceList) (int, error) {
+	var id int
+	market := sql.NullString{String: list.Market, Valid: list.Market != ""}
+	err := pr.connection.QueryRow(`INSERT INTO price_lists (code, name, currency, market) VALUES ($1, $2, $3, $4) RETURNING id`,
+		list.Code, list.Name, list.Currency, market).Scan(&id)
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+func (pr *PricingRepository) GetPriceListItems(priceListID int) ([]model.PriceListItem, error) {
+	rows, err := pr.connection.Query(`SELECT pli.product_id, pli.price, pl.currency FROM price_list_items pli
+		JOIN price_lists pl ON pl.id = pli.price_list_id
+		WHERE pli.price_list_id = $1 ORDER BY pli.product_id`, priceListID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []model.PriceListItem
+	for rows.Next() {
+		var price, currency string
+		item := model.PriceListItem{PriceListID: priceListID}
+		if err := rows.Scan(&item.ProductID, &price, &currency); err != nil {
+			return nil, err
+		}
+		if item.Price, err = model.ParseMoney(price, currency); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+func (pr *PricingRepository) SetPriceListItem(item model.PriceListItem) error {
+	_, err := pr.connection.Exec(`INSERT INTO price_list_items (price_list_id, product_id, price) VALUES ($1, $2, $3)
+		ON CONFLICT (price_list_id, product_id) DO UPDATE SET price = EXCLUDED.price`,
+		item.PriceListID, item.ProductID, item.Price.String())
+	return err
+}
+
+func (pr *PricingRepository) DeletePriceListItem(priceListID, productID int) error {
+	_, err := pr.connection.Exec(`DELETE FROM price_list_items WHERE price_list_id = $1 AND product_id = $2`, priceListID, productID)
+	return err
+}
+
+// GetListPrices returns the explicit prices of the products in the currency,
+// preferring the lists of the given market over the lists without a market
+func (pr *PricingRepository) GetListPrices(currency, market string, productIDs []int) (map[int]model.ListPrice, error) {
+	rows, err := pr.connection.Query(`SELECT DISTINCT ON (pli.product_id) pli.product_id, pli.price, pl.code
+		FROM price_list_items pli
+		JOIN price_lists pl ON pl.id = pli.price_list_id
+		WHERE pl.currency = $1 AND (pl.market = $2 OR pl.market IS NULL) AND pli.product_id = ANY($3)
+		ORDER BY pli.product_id, pl.market NULLS LAST, pl.id`, currency, market, pq.Array(productIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	prices := make(map[int]model.ListPrice)
+	for rows.Next() {
+		var productID int
+		var price, code string
+		if err := rows.Scan(&productID, &price, &code); err != nil {
+			return nil, err
+		}
+		money, err := model.ParseMoney(price, currency)
+		if err != nil {
+			return nil, err
+		}
+		prices[productID] = model.ListPrice{PriceListCode: code, Price: money}
+	}
+	return prices, rows.Err()
+}
+
+func scanExchangeRate(scanner rowScanner) (model.ExchangeRate, error) {
+	var rate model.ExchangeRate
+	var value string
+	err := scanner.Scan(&rate.Base, &rate.Quote, &value, &rate.Source, &rate.UpdatedAt)
+	if err != nil {
+		return model.ExchangeRate{}, err
+	}
+	var ok bool
+	if rate.Rate, ok = new(big.Rat).SetString(value); !ok {
+		return model.ExchangeRate{}, fmt.Errorf("invalid exchange rate %q for %s/%s", value, rate.Base, rate.Quote)
+	}
+	return rate, nil
+}
+
+func (pr *PricingRepository) GetExchangeRates() ([]model.ExchangeRate, error) {
+	rows, err := pr.connection.Query(`SELECT base, quote, rate, source, updated_at FROM exchange_rates ORDER BY base, quote`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rates []model.ExchangeRate
+	for rows.Next() {
+		rate, err := scanExchangeRate(rows)
+		if err != nil {
+			return nil, err
+		}
+		rates = append(rates, rate)
+	}
+	return rates, rows.Err()
+}
+
+func (pr *PricingRepository) GetExchangeRate(base, quote string) (*model.ExchangeRate, error) {
+	rate, err := scanExchangeRate(pr.connection.QueryRow(
+		`SELECT base, quote, rate, source, updated_at FROM exchange_rates WHERE base = $1 AND quote = $2`, base, quote))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &rate, nil
+}
+
+// UpsertExchangeRates saves all the rates in a single transaction
+func (pr *PricingRepository) UpsertExchangeRates(rates []model.ExchangeRate) error {
+	tx, err := pr.connection.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, rate := range rates {
+		_, err = tx.Exec(`INSERT INTO exchange_rates (base, quote, rate, source, updated_at) VALUES ($1, $2, $3, $4, $5)
+			ON CONFLICT (base, quote) DO UPDATE SET rate = EXCLUDED.rate, source = EXCLUDED.source, updated_at = EXCLUDED.updated_at`,
+			rate.Base, rate.Quote, rate.Rate.FloatString(exchangeRateScale), rate.Source, rate.UpdatedAt)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"go-api/model"
+	"math/big"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPricingRepository_GetPriceListByCode(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		rows := sqlmock.NewRows([]string{"id", "code", "name", "currency", "market"}).
+			AddRow(1, "usd-default", "Preços em dólar", "USD", nil)
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT id, code, name, currency, market FROM price_lists WHERE code = $1")).
+			WithArgs("usd-default").
+			WillReturnRows(rows)
+
+		repo := NewPricingRepository(db)
+		list, err := repo.GetPriceListByCode("usd-default")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "USD", list.Currency)
+		assert.Empty(t, list.Market)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT id, code, name, currency, market FROM price_lists WHERE code = $1")).
+			WithArgs("missing").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "code", "name", "currency", "market"}))
+
+		repo := NewPricingRepository(db)
+		list, err := repo.GetPriceListByCode("missing")
+
+		assert.NoError(t, err)
+		assert.Nil(t, list)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestPricingRepository_GetListPrices(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		rows := sqlmock.NewRows([]string{"product_id", "price", "code"}).
+			AddRow(1, "5.490", "usd-us")
+		mock.ExpectQuery("SELECT DISTINCT ON \\(pli.product_id\\) pli.product_id, pli.price, pl.code").
+			WithArgs("USD", "US", pq.Array([]int{1, 2})).
+			WillReturnRows(rows)
+
+		repo := NewPricingRepository(db)
+		prices, err := repo.GetListPrices("USD", "US", []int{1, 2})
+
+		assert.NoError(t, err)
+		assert.Len(t, prices, 1)
+		assert.Equal(t, "usd-us", prices[1].PriceListCode)
+		assert.Equal(t, model.Money{Amount: 549, Currency: "USD"}, prices[1].Price)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestPricingRepository_GetExchangeRate(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		updatedAt := time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+		rows := sqlmock.NewRows([]string{"base", "quote", "rate", "source", "updated_at"}).
+			AddRow("BRL", "USD", "0.1850000000", "manual", updatedAt)
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT base, quote, rate, source, updated_at FROM exchange_rates WHERE base = $1 AND quote = $2")).
+			WithArgs("BRL", "USD").
+			WillReturnRows(rows)
+
+		repo := NewPricingRepository(db)
+		rate, err := repo.GetExchangeRate("BRL", "USD")
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, rate.Rate.Cmp(big.NewRat(185, 1000)))
+		assert.Equal(t, updatedAt, rate.UpdatedAt)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectQuery("SELECT base, quote, rate, source, updated_at FROM exchange_rates").
+			WithArgs("BRL", "JPY").
+			WillReturnRows(sqlmock.NewRows([]string{"base", "quote", "rate", "source", "updated_at"}))
+
+		repo := NewPricingRepository(db)
+		rate, err := repo.GetExchangeRate("BRL", "JPY")
+
+		assert.NoError(t, err)
+		assert.Nil(t, rate)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestPricingRepository_UpsertExchangeRates(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		updatedAt := time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+		mock.ExpectBegin()
+		mock.ExpectExec("INSERT INTO exchange_rates").
+			WithArgs("BRL", "USD", "0.1850000000", "manual", updatedAt).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		repo := NewPricingRepository(db)
+		err = repo.UpsertExchangeRates([]model.ExchangeRate{
+			{Base: "BRL", Quote: "USD", Rate: big.NewRat(185, 1000), Source: "manual", UpdatedAt: updatedAt},
+		})
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -1,16 +1,36 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"go-api/model"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
 )
 
 // ProductRepositoryInterface define o contrato para o repository
 type ProductRepositoryInterface interface {
-	GetProducts() ([]model.Product, error)
-	CreateProduct(product model.Product) (int, error)
+	GetProducts(filter model.ProductFilter, asOf time.Time) ([]model.Product, error)
+	ExportProducts(ctx context.Context, filter model.ProductFilter, asOf time.Time, fn func(model.Product) error) error
+	CreateProduct(product model.Product, event model.AuditEvent) (int, error)
 	GetProductById(id_product int) (*model.Product, error)
+	GetProductByIdAsOf(id_product int, asOf time.Time) (*model.Product, error)
+	GetProductPrices(id_product int) ([]model.ProductPrice, error)
+	CreateProductPrice(price model.ProductPrice, event model.AuditEvent) (int, error)
+	GetProductBySKU(sku string) (*model.Product, error)
+	GetProductsBySKUOrName(skus, names []string) ([]model.Product, error)
+	ImportProducts(batch model.ProductImportBatch, event model.AuditEvent) error
+	DeleteProduct(id_product int, event model.AuditEvent) error
+	GetDeletedProducts() ([]model.Product, error)
+	GetDeletedProductByID(id_product int) (*model.Product, error)
+	RestoreProduct(id_product int, event model.AuditEvent) error
+	PurgeProduct(id_product int, event model.AuditEvent) error
+	SetProductTaxCategory(id_product int, category string, event model.AuditEvent) error
+	GetProductTaxCategories(ids []int) (map[int]string, error)
 }
 
 type ProductRepository struct {
@@ -26,63 +46,414 @@ func NewProductRepository(connection *sql.DB) ProductRepositoryInterface {
 	}
 }
 
-func (pr *ProductRepository) GetProducts() ([]model.Product, error) {
-	query := "SELECT id, product_name, price FROM products ORDER BY id"
-	rows, err := pr.connection.Query(query)
+// categorySubtreeCondition matches products assigned to the root category or
+// to any of its descendants, using the materialized path of the categories
+const categorySubtreeCondition = `p.id IN (
+	SELECT pc.product_id FROM product_categories pc
+	JOIN categories c ON c.id = pc.category_id
+	JOIN categories root ON c.path = root.path OR c.path LIKE root.path || '/%%'
+	WHERE %s)`
+
+// selectResolvedProducts resolves the price in effect at $1 from the price
+// history: a sale wins over the regular price, then the latest effective_from,
+// then the latest entry. Products without history keep the price they were
+// created with
+const selectResolvedProducts = `SELECT ` + productColumns + ` FROM products p
+	` + resolvedPriceJoin
+
+// selectDeletedProducts lists the trash with the price in effect at $1 and
+// the deletion time as an extra column
+const selectDeletedProducts = `SELECT ` + productColumns + `, p.deleted_at FROM products p
+	` + resolvedPriceJoin + `
+	WHERE p.deleted_at IS NOT NULL`
+
+const productColumns = `p.id, p.product_name, p.sku, COALESCE(rp.price, p.price), p.currency, p.tax_category, p.created_at, p.updated_at, p.created_by, p.updated_by`
+
+const resolvedPriceJoin = `LEFT JOIN LATERAL (
+		SELECT pp.price FROM product_prices pp
+		WHERE pp.product_id = p.id AND pp.effective_from <= $1 AND (pp.effective_to IS NULL OR pp.effective_to > $1)
+		ORDER BY pp.kind = 'sale' DESC, pp.effective_from DESC, pp.id DESC
+		LIMIT 1
+	) rp ON TRUE`
+
+func (pr *ProductRepository) GetProducts(filter model.ProductFilter, asOf time.Time) ([]model.Product, error) {
+	query, args := productsQuery(filter, asOf)
+	rows, err := pr.connection.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var productsList []model.Product
-	var productobj model.Product
+	return scanProducts(rows)
+}
+
+// ExportProducts streams the products matching the filter, in id order, to fn
+// through a server-side cursor instead of loading them all
+func (pr *ProductRepository) ExportProducts(ctx context.Context, filter model.ProductFilter, asOf time.Time, fn func(model.Product) error) error {
+	query, args := productsQuery(filter, asOf)
+	return streamCursor(ctx, pr.connection, query, args, func(row rowScanner) error {
+		product, err := scanProduct(row)
+		if err != nil {
+			return err
+		}
+		return fn(product)
+	})
+}
 
+// productsQuery builds the listing query of the filter, shared by the
+// listing and the export
+func productsQuery(filter model.ProductFilter, asOf time.Time) (string, []interface{}) {
+	query := selectResolvedProducts
+	conditions := []string{"p.deleted_at IS NULL"}
+	args := []interface{}{asOf}
+	if filter.CategoryID != 0 {
+		args = append(args, filter.CategoryID)
+		conditions = append(conditions, fmt.Sprintf(categorySubtreeCondition, fmt.Sprintf("root.id = $%d", len(args))))
+	}
+	if filter.CategoryPath != "" {
+		args = append(args, filter.CategoryPath)
+		conditions = append(conditions, fmt.Sprintf(categorySubtreeCondition, fmt.Sprintf("root.path = $%d", len(args))))
+	}
+	if !filter.UpdatedSince.IsZero() {
+		args = append(args, filter.UpdatedSince)
+		conditions = append(conditions, fmt.Sprintf("p.updated_at >= $%d", len(args)))
+	}
+	query += " WHERE " + strings.Join(conditions, " AND ") + " ORDER BY p.id"
+	return query, args
+}
+
+// scanProduct reads the columns of selectResolvedProducts followed by the
+// extra destinations, if any
+func scanProduct(row rowScanner, extra ...interface{}) (model.Product, error) {
+	var product model.Product
+	var sku sql.NullString
+	var price, currency string
+	var createdBy, updatedBy sql.NullInt64
+	dest := append([]interface{}{&product.ID, &product.Name, &sku, &price, &currency, &product.TaxCategory,
+		&product.CreatedAt, &product.UpdatedAt, &createdBy, &updatedBy}, extra...)
+	if err := row.Scan(dest...); err != nil {
+		return model.Product{}, err
+	}
+	product.SKU = sku.String
+	product.CreatedBy = nullableInt(createdBy)
+	product.UpdatedBy = nullableInt(updatedBy)
+
+	var err error
+	product.Price, err = model.ParseMoney(price, currency)
+	if err != nil {
+		return model.Product{}, err
+	}
+	return product, nil
+}
+
+func scanProducts(rows *sql.Rows) ([]model.Product, error) {
+	var productsList []model.Product
 	for rows.Next() {
-		err = rows.Scan(&productobj.ID, &productobj.Name, &productobj.Price)
+		product, err := scanProduct(rows)
 		if err != nil {
 			return nil, err
 		}
-		productsList = append(productsList, productobj)
+		productsList = append(productsList, product)
 	}
-	return productsList, nil
+	return productsList, rows.Err()
+}
+
+// skuValue stores products without a SKU as NULL so the unique constraint ignores them
+func skuValue(sku string) sql.NullString {
+	return sql.NullString{String: sku, Valid: sku != ""}
 }
 
-func (pr *ProductRepository) CreateProduct(product model.Product) (int, error) {
+// CreateProduct inserts the product and opens its price history with the
+// initial price, in a single transaction with the event
+func (pr *ProductRepository) CreateProduct(product model.Product, event model.AuditEvent) (int, error) {
 	var id int
-	query, err := pr.connection.Prepare(`INSERT INTO products (
-		product_name, price
-	) VALUES ($1, $2) RETURNING id`)
+	err := withAuditEvent(pr.connection, &event, func(tx *sql.Tx) error {
+		err := tx.QueryRow(`INSERT INTO products (
+			product_name, price, currency, sku, created_by, updated_by
+		) VALUES ($1, $2, $3, $4, $5, $5) RETURNING id`, product.Name, product.Price.String(), product.Price.Currency, skuValue(product.SKU), event.ActorID).Scan(&id)
+		if err != nil {
+			return err
+		}
+		event.EntityID = strconv.Itoa(id)
+
+		_, err = tx.Exec(`INSERT INTO product_prices (product_id, price, kind, effective_from) VALUES ($1, $2, $3, NOW())`,
+			id, product.Price.String(), model.PriceKindRegular)
+		return err
+	})
 	if err != nil {
-		fmt.Println(err)
 		return 0, err
 	}
-	err = query.QueryRow(product.Name, product.Price).Scan(&id)
+	return id, nil
+}
+
+func (pr *ProductRepository) GetProductById(id_product int) (*model.Product, error) {
+	return pr.GetProductByIdAsOf(id_product, time.Now())
+}
+
+// GetProductByIdAsOf returns the product with the price in effect at asOf
+func (pr *ProductRepository) GetProductByIdAsOf(id_product int, asOf time.Time) (*model.Product, error) {
+	product, err := scanProduct(pr.connection.QueryRow(selectResolvedProducts+" WHERE p.id = $2 AND p.deleted_at IS NULL", asOf, id_product))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &product, nil
+}
+
+// GetProductPrices returns the whole price history of the product, oldest first
+func (pr *ProductRepository) GetProductPrices(id_product int) ([]model.ProductPrice, error) {
+	rows, err := pr.connection.Query(`SELECT pp.id, pp.price, p.currency, pp.kind, pp.effective_from, pp.effective_to, pp.created_at
+		FROM product_prices pp
+		JOIN products p ON p.id = pp.product_id
+		WHERE pp.product_id = $1
+		ORDER BY pp.effective_from, pp.id`, id_product)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var prices []model.ProductPrice
+	for rows.Next() {
+		entry := model.ProductPrice{ProductID: id_product}
+		var price, currency string
+		var effectiveTo sql.NullTime
+		err := rows.Scan(&entry.ID, &price, &currency, &entry.Kind, &entry.EffectiveFrom, &effectiveTo, &entry.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		if entry.Price, err = model.ParseMoney(price, currency); err != nil {
+			return nil, err
+		}
+		if effectiveTo.Valid {
+			entry.EffectiveTo = &effectiveTo.Time
+		}
+		prices = append(prices, entry)
+	}
+	return prices, rows.Err()
+}
+
+// CreateProductPrice appends an entry to the price history; entries are never updated
+func (pr *ProductRepository) CreateProductPrice(price model.ProductPrice, event model.AuditEvent) (int, error) {
+	var id int
+	err := withAuditEvent(pr.connection, &event, func(tx *sql.Tx) error {
+		err := tx.QueryRow(`INSERT INTO product_prices (product_id, price, kind, effective_from, effective_to)
+			VALUES ($1, $2, $3, $4, $5) RETURNING id`,
+			price.ProductID, price.Price.String(), price.Kind, price.EffectiveFrom, price.EffectiveTo).Scan(&id)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(`UPDATE products SET updated_at = NOW(), updated_by = $2 WHERE id = $1`, price.ProductID, event.ActorID)
+		return err
+	})
 	if err != nil {
-		fmt.Println(err)
 		return 0, err
 	}
-	query.Close()
 	return id, nil
 }
 
-func (pr *ProductRepository) GetProductById(id_product int) (*model.Product, error) {
-	query, err := pr.connection.Prepare(`SELECT id, product_name, price FROM products WHERE id = $1`)
+func (pr *ProductRepository) GetProductBySKU(sku string) (*model.Product, error) {
+	product, err := scanProduct(pr.connection.QueryRow(selectResolvedProducts+" WHERE p.sku = $2 AND p.deleted_at IS NULL", time.Now(), sku))
 	if err != nil {
-		fmt.Println(err)
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
 		return nil, err
 	}
-	var product model.Product
-	err = query.QueryRow(id_product).Scan(
-		&product.ID,
-		&product.Name,
-		&product.Price,
-	)
+	return &product, nil
+}
+
+// GetProductsBySKUOrName returns the products, with their current price, whose
+// SKU or name is in the lists; the import uses it to find the rows to update
+func (pr *ProductRepository) GetProductsBySKUOrName(skus, names []string) ([]model.Product, error) {
+	rows, err := pr.connection.Query(selectResolvedProducts+` WHERE (p.sku = ANY($2) OR p.product_name = ANY($3)) AND p.deleted_at IS NULL ORDER BY p.id`,
+		time.Now(), pq.Array(skus), pq.Array(names))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanProducts(rows)
+}
+
+// ImportProducts applies one import batch in a single transaction, with one
+// statement per kind of write however many rows the batch has, and one event
+func (pr *ProductRepository) ImportProducts(batch model.ProductImportBatch, event model.AuditEvent) error {
+	tx, err := pr.connection.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if len(batch.Create) > 0 {
+		names := make([]string, 0, len(batch.Create))
+		prices := make([]string, 0, len(batch.Create))
+		currencies := make([]string, 0, len(batch.Create))
+		skus := make([]sql.NullString, 0, len(batch.Create))
+		for _, product := range batch.Create {
+			names = append(names, product.Name)
+			prices = append(prices, product.Price.String())
+			currencies = append(currencies, product.Price.Currency)
+			skus = append(skus, skuValue(product.SKU))
+		}
+		// The price history of the new products is opened in the same statement
+		_, err := tx.Exec(`WITH created AS (
+				INSERT INTO products (product_name, price, currency, sku, created_by, updated_by)
+				SELECT u.*, $6::int, $6::int FROM unnest($1::text[], $2::numeric[], $3::text[], $4::text[]) AS u
+				RETURNING id, price
+			)
+			INSERT INTO product_prices (product_id, price, kind, effective_from)
+			SELECT id, price, $5, NOW() FROM created`,
+			pq.Array(names), pq.Array(prices), pq.Array(currencies), pq.Array(skus), model.PriceKindRegular, event.ActorID)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(batch.Update) > 0 {
+		ids := make([]int64, 0, len(batch.Update))
+		names := make([]string, 0, len(batch.Update))
+		skus := make([]sql.NullString, 0, len(batch.Update))
+		for _, product := range batch.Update {
+			ids = append(ids, int64(product.ID))
+			names = append(names, product.Name)
+			skus = append(skus, skuValue(product.SKU))
+		}
+		_, err := tx.Exec(`UPDATE products p SET product_name = u.name, sku = COALESCE(u.sku, p.sku), updated_at = NOW(), updated_by = $4
+			FROM unnest($1::int[], $2::text[], $3::text[]) AS u(id, name, sku)
+			WHERE p.id = u.id`, pq.Array(ids), pq.Array(names), pq.Array(skus), event.ActorID)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(batch.Reprice) > 0 {
+		ids := make([]int64, 0, len(batch.Reprice))
+		prices := make([]string, 0, len(batch.Reprice))
+		for _, product := range batch.Reprice {
+			ids = append(ids, int64(product.ID))
+			prices = append(prices, product.Price.String())
+		}
+		_, err := tx.Exec(`INSERT INTO product_prices (product_id, price, kind, effective_from)
+			SELECT id, price, $3, NOW() FROM unnest($1::int[], $2::numeric[]) AS u(id, price)`,
+			pq.Array(ids), pq.Array(prices), model.PriceKindRegular)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(`UPDATE products SET updated_at = NOW(), updated_by = $2 WHERE id = ANY($1)`, pq.Array(ids), event.ActorID)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := appendAuditEvent(tx, event); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// DeleteProduct moves the product to the trash; its prices, variants and
+// images are kept until PurgeProduct
+func (pr *ProductRepository) DeleteProduct(id_product int, event model.AuditEvent) error {
+	return withAuditEvent(pr.connection, &event, func(tx *sql.Tx) error {
+		_, err := tx.Exec(`UPDATE products SET deleted_at = NOW(), updated_at = NOW(), updated_by = $2 WHERE id = $1 AND deleted_at IS NULL`, id_product, event.ActorID)
+		return err
+	})
+}
+
+func scanDeletedProduct(row rowScanner) (model.Product, error) {
+	var deletedAt time.Time
+	product, err := scanProduct(row, &deletedAt)
+	if err != nil {
+		return model.Product{}, err
+	}
+	product.DeletedAt = &deletedAt
+	return product, nil
+}
+
+// GetDeletedProducts lists the trash, most recently deleted first
+func (pr *ProductRepository) GetDeletedProducts() ([]model.Product, error) {
+	rows, err := pr.connection.Query(selectDeletedProducts+" ORDER BY p.deleted_at DESC, p.id", time.Now())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var products []model.Product
+	for rows.Next() {
+		product, err := scanDeletedProduct(rows)
+		if err != nil {
+			return nil, err
+		}
+		products = append(products, product)
+	}
+	return products, rows.Err()
+}
+
+func (pr *ProductRepository) GetDeletedProductByID(id_product int) (*model.Product, error) {
+	product, err := scanDeletedProduct(pr.connection.QueryRow(selectDeletedProducts+" AND p.id = $2", time.Now(), id_product))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
 		}
 		return nil, err
 	}
-	query.Close()
 	return &product, nil
 }
+
+// RestoreProduct takes the product out of the trash
+func (pr *ProductRepository) RestoreProduct(id_product int, event model.AuditEvent) error {
+	return withAuditEvent(pr.connection, &event, func(tx *sql.Tx) error {
+		_, err := tx.Exec(`UPDATE products SET deleted_at = NULL, updated_at = NOW(), updated_by = $2 WHERE id = $1 AND deleted_at IS NOT NULL`, id_product, event.ActorID)
+		return err
+	})
+}
+
+// PurgeProduct deletes a product in the trash permanently, along with its
+// prices, variants, images and category assignments (ON DELETE CASCADE)
+func (pr *ProductRepository) PurgeProduct(id_product int, event model.AuditEvent) error {
+	return withAuditEvent(pr.connection, &event, func(tx *sql.Tx) error {
+		_, err := tx.Exec(`DELETE FROM products WHERE id = $1 AND deleted_at IS NOT NULL`, id_product)
+		return err
+	})
+}
+
+// SetProductTaxCategory assigns the tax category of a product outside the trash
+func (pr *ProductRepository) SetProductTaxCategory(id_product int, category string, event model.AuditEvent) error {
+	return withAuditEvent(pr.connection, &event, func(tx *sql.Tx) error {
+		result, err := tx.Exec(`UPDATE products SET tax_category = $2, updated_at = NOW(), updated_by = $3 WHERE id = $1 AND deleted_at IS NULL`,
+			id_product, category, event.ActorID)
+		if err != nil {
+			return err
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return sql.ErrNoRows
+		}
+		return nil
+	})
+}
+
+// GetProductTaxCategories returns the tax category of each of the products,
+// including the ones in the trash
+func (pr *ProductRepository) GetProductTaxCategories(ids []int) (map[int]string, error) {
+	rows, err := pr.connection.Query(`SELECT id, tax_category FROM products WHERE id = ANY($1)`, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	categories := make(map[int]string, len(ids))
+	for rows.Next() {
+		var id int
+		var category string
+		if err := rows.Scan(&id, &category); err != nil {
+			return nil, err
+		}
+		categories[id] = category
+	}
+	return categories, rows.Err()
+}
@@ -1,15 +1,25 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"go-api/model"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 )
 
+var (
+	productAsOf      = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	productCreatedAt = time.Date(2026, 2, 1, 9, 0, 0, 0, time.UTC)
+
+	productRowColumns = []string{"id", "product_name", "sku", "price", "currency", "tax_category", "created_at", "updated_at", "created_by", "updated_by"}
+)
+
 func TestProductRepository_GetProducts(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		db, mock, err := sqlmock.New()
@@ -17,24 +27,28 @@ func TestProductRepository_GetProducts(t *testing.T) {
 		defer db.Close()
 
 		expectedProducts := []model.Product{
-			{ID: 1, Name: "Product 1", Price: 10.0},
-			{ID: 2, Name: "Product 2", Price: 20.0},
+			{ID: 1, Name: "Product 1", Price: model.Money{Amount: 1000, Currency: "BRL"}},
+			{ID: 2, Name: "Product 2", Price: model.Money{Amount: 2000, Currency: "BRL"}},
 		}
 
-		rows := sqlmock.NewRows([]string{"id", "product_name", "price"}).
-			AddRow(expectedProducts[0].ID, expectedProducts[0].Name, expectedProducts[0].Price).
-			AddRow(expectedProducts[1].ID, expectedProducts[1].Name, expectedProducts[1].Price)
+		rows := sqlmock.NewRows(productRowColumns).
+			AddRow(expectedProducts[0].ID, expectedProducts[0].Name, nil, "10.00", "BRL", "standard", productCreatedAt, productCreatedAt, nil, nil).
+			AddRow(expectedProducts[1].ID, expectedProducts[1].Name, "CAM-01", "20.00", "BRL", "standard", productCreatedAt, productCreatedAt, nil, nil)
 
-		mock.ExpectQuery("SELECT id, product_name, price FROM products").
+		mock.ExpectQuery(`SELECT p.id, p.product_name, p.sku, COALESCE\(rp.price, p.price\), p.currency, p.tax_category, p.created_at, p.updated_at, p.created_by, p.updated_by FROM products p LEFT JOIN LATERAL .* ORDER BY p.id`).
+			WithArgs(productAsOf).
 			WillReturnRows(rows)
 
 		repo := NewProductRepository(db)
-		products, err := repo.GetProducts()
+		products, err := repo.GetProducts(model.ProductFilter{}, productAsOf)
 
 		assert.NoError(t, err)
 		assert.Len(t, products, 2)
 		assert.Equal(t, expectedProducts[0].Name, products[0].Name)
 		assert.Equal(t, expectedProducts[1].Name, products[1].Name)
+		assert.Equal(t, expectedProducts[1].Price, products[1].Price)
+		assert.Empty(t, products[0].SKU)
+		assert.Equal(t, "CAM-01", products[1].SKU)
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 
@@ -43,17 +57,59 @@ func TestProductRepository_GetProducts(t *testing.T) {
 		assert.NoError(t, err)
 		defer db.Close()
 
-		mock.ExpectQuery("SELECT id, product_name, price FROM products").
+		mock.ExpectQuery("SELECT p.id, p.product_name").
 			WillReturnError(errors.New("connection failed"))
 
 		repo := NewProductRepository(db)
-		products, err := repo.GetProducts()
+		products, err := repo.GetProducts(model.ProductFilter{}, productAsOf)
 
 		assert.Error(t, err)
 		assert.Nil(t, products)
 		assert.Contains(t, err.Error(), "connection failed")
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
+	t.Run("Filter By Category Subtree", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		rows := sqlmock.NewRows(productRowColumns).
+			AddRow(1, "Camiseta", nil, "49.90", "BRL", "standard", productCreatedAt, productCreatedAt, nil, nil)
+
+		mock.ExpectQuery(`FROM products p .* WHERE p.deleted_at IS NULL AND p.id IN \(.*root.path = \$2\) ORDER BY p.id`).
+			WithArgs(productAsOf, "roupas").
+			WillReturnRows(rows)
+
+		repo := NewProductRepository(db)
+		products, err := repo.GetProducts(model.ProductFilter{CategoryPath: "roupas"}, productAsOf)
+
+		assert.NoError(t, err)
+		assert.Len(t, products, 1)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Updated Since", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		since := productCreatedAt.Add(time.Hour)
+		rows := sqlmock.NewRows(productRowColumns).
+			AddRow(1, "Camiseta", nil, "49.90", "BRL", "standard", productCreatedAt, since, 7, 7)
+
+		mock.ExpectQuery(`WHERE p.deleted_at IS NULL AND p.updated_at >= \$2 ORDER BY p.id`).
+			WithArgs(productAsOf, since).
+			WillReturnRows(rows)
+
+		repo := NewProductRepository(db)
+		products, err := repo.GetProducts(model.ProductFilter{UpdatedSince: since}, productAsOf)
+
+		assert.NoError(t, err)
+		assert.Len(t, products, 1)
+		assert.Equal(t, since, products[0].UpdatedAt)
+		assert.Equal(t, 7, *products[0].UpdatedBy)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
 }
 
 func TestProductRepository_CreateProduct(t *testing.T) {
@@ -64,43 +120,42 @@ func TestProductRepository_CreateProduct(t *testing.T) {
 
 		product := model.Product{
 			Name:  "New Product",
-			Price: 15.99,
+			Price: model.Money{Amount: 1599, Currency: "BRL"},
 		}
 
 		expectedID := 1
 
-		mock.ExpectPrepare("INSERT INTO products").
-			ExpectQuery().
-			WithArgs(product.Name, product.Price).
+		mock.ExpectBegin()
+		mock.ExpectQuery("INSERT INTO products").
+			WithArgs(product.Name, "15.99", "BRL", sql.NullString{}, nil).
 			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(expectedID))
+		mock.ExpectExec("INSERT INTO product_prices").
+			WithArgs(expectedID, "15.99", model.PriceKindRegular).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		expectAuditEvent(mock, "", model.AuditEvent{Action: model.AuditActionCreate, EntityType: model.AuditEntityProduct, EntityID: "1"})
+		mock.ExpectCommit()
 
 		repo := NewProductRepository(db)
-		id, err := repo.CreateProduct(product)
+		id, err := repo.CreateProduct(product, model.AuditEvent{Action: model.AuditActionCreate, EntityType: model.AuditEntityProduct})
 
 		assert.NoError(t, err)
 		assert.Equal(t, expectedID, id)
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 
-	t.Run("Prepare Statement Error", func(t *testing.T) {
+	t.Run("Begin Error", func(t *testing.T) {
 		db, mock, err := sqlmock.New()
 		assert.NoError(t, err)
 		defer db.Close()
 
-		product := model.Product{
-			Name:  "New Product",
-			Price: 15.99,
-		}
-
-		mock.ExpectPrepare("INSERT INTO products").
-			WillReturnError(errors.New("prepare failed"))
+		mock.ExpectBegin().WillReturnError(errors.New("begin failed"))
 
 		repo := NewProductRepository(db)
-		id, err := repo.CreateProduct(product)
+		id, err := repo.CreateProduct(model.Product{Name: "New Product", Price: model.Money{Amount: 1599, Currency: "BRL"}}, model.AuditEvent{})
 
 		assert.Error(t, err)
 		assert.Equal(t, 0, id)
-		assert.Contains(t, err.Error(), "prepare failed")
+		assert.Contains(t, err.Error(), "begin failed")
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 
@@ -111,16 +166,17 @@ func TestProductRepository_CreateProduct(t *testing.T) {
 
 		product := model.Product{
 			Name:  "New Product",
-			Price: 15.99,
+			Price: model.Money{Amount: 1599, Currency: "BRL"},
 		}
 
-		mock.ExpectPrepare("INSERT INTO products").
-			ExpectQuery().
-			WithArgs(product.Name, product.Price).
+		mock.ExpectBegin()
+		mock.ExpectQuery("INSERT INTO products").
+			WithArgs(product.Name, "15.99", "BRL", sql.NullString{}, nil).
 			WillReturnError(errors.New("insert failed"))
+		mock.ExpectRollback()
 
 		repo := NewProductRepository(db)
-		id, err := repo.CreateProduct(product)
+		id, err := repo.CreateProduct(product, model.AuditEvent{})
 
 		assert.Error(t, err)
 		assert.Equal(t, 0, id)
@@ -138,15 +194,14 @@ func TestProductRepository_GetProductById(t *testing.T) {
 		expectedProduct := model.Product{
 			ID:    1,
 			Name:  "Test Product",
-			Price: 25.50,
+			Price: model.Money{Amount: 2550, Currency: "BRL"},
 		}
 
-		rows := sqlmock.NewRows([]string{"id", "product_name", "price"}).
-			AddRow(expectedProduct.ID, expectedProduct.Name, expectedProduct.Price)
+		rows := sqlmock.NewRows(productRowColumns).
+			AddRow(expectedProduct.ID, expectedProduct.Name, nil, "25.50", "BRL", "standard", productCreatedAt, productCreatedAt, nil, nil)
 
-		mock.ExpectPrepare("SELECT id, product_name, price FROM products WHERE id = \\$1").
-			ExpectQuery().
-			WithArgs(1).
+		mock.ExpectQuery(`FROM products p .* WHERE p.id = \$2`).
+			WithArgs(sqlmock.AnyArg(), 1).
 			WillReturnRows(rows)
 
 		repo := NewProductRepository(db)
@@ -165,9 +220,8 @@ func TestProductRepository_GetProductById(t *testing.T) {
 		assert.NoError(t, err)
 		defer db.Close()
 
-		mock.ExpectPrepare("SELECT id, product_name, price FROM products WHERE id = \\$1").
-			ExpectQuery().
-			WithArgs(999).
+		mock.ExpectQuery(`FROM products p .* WHERE p.id = \$2`).
+			WithArgs(sqlmock.AnyArg(), 999).
 			WillReturnError(sql.ErrNoRows)
 
 		repo := NewProductRepository(db)
@@ -183,9 +237,8 @@ func TestProductRepository_GetProductById(t *testing.T) {
 		assert.NoError(t, err)
 		defer db.Close()
 
-		mock.ExpectPrepare("SELECT id, product_name, price FROM products WHERE id = \\$1").
-			ExpectQuery().
-			WithArgs(1).
+		mock.ExpectQuery(`FROM products p .* WHERE p.id = \$2`).
+			WithArgs(sqlmock.AnyArg(), 1).
 			WillReturnError(errors.New("query failed"))
 
 		repo := NewProductRepository(db)
@@ -197,3 +250,337 @@ func TestProductRepository_GetProductById(t *testing.T) {
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 }
+
+func TestProductRepository_GetProductByIdAsOf(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		rows := sqlmock.NewRows(productRowColumns).
+			AddRow(1, "Test Product", nil, "19.90", "BRL", "standard", productCreatedAt, productCreatedAt, nil, nil)
+		mock.ExpectQuery(`pp.effective_from <= \$1 AND \(pp.effective_to IS NULL OR pp.effective_to > \$1\) ORDER BY pp.kind = 'sale' DESC`).
+			WithArgs(productAsOf, 1).
+			WillReturnRows(rows)
+
+		repo := NewProductRepository(db)
+		product, err := repo.GetProductByIdAsOf(1, productAsOf)
+
+		assert.NoError(t, err)
+		assert.Equal(t, model.Money{Amount: 1990, Currency: "BRL"}, product.Price)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestProductRepository_GetProductPrices(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		saleEnd := productAsOf.Add(48 * time.Hour)
+		rows := sqlmock.NewRows([]string{"id", "price", "currency", "kind", "effective_from", "effective_to", "created_at"}).
+			AddRow(1, "29.990", "BRL", "regular", productAsOf.AddDate(0, -1, 0), nil, productAsOf.AddDate(0, -1, 0)).
+			AddRow(2, "19.990", "BRL", "sale", productAsOf, saleEnd, productAsOf)
+		mock.ExpectQuery(`FROM product_prices pp JOIN products p ON p.id = pp.product_id WHERE pp.product_id = \$1 ORDER BY pp.effective_from, pp.id`).
+			WithArgs(1).
+			WillReturnRows(rows)
+
+		repo := NewProductRepository(db)
+		prices, err := repo.GetProductPrices(1)
+
+		assert.NoError(t, err)
+		assert.Len(t, prices, 2)
+		assert.Nil(t, prices[0].EffectiveTo)
+		assert.Equal(t, saleEnd, *prices[1].EffectiveTo)
+		assert.Equal(t, model.Money{Amount: 1999, Currency: "BRL"}, prices[1].Price)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestProductRepository_CreateProductPrice(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		event := model.AuditEvent{Action: model.AuditActionSchedulePrice, EntityType: model.AuditEntityProduct, EntityID: "1"}
+
+		mock.ExpectBegin()
+		mock.ExpectQuery("INSERT INTO product_prices").
+			WithArgs(1, "24.90", model.PriceKindRegular, productAsOf, nil).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+		mock.ExpectExec(`UPDATE products SET updated_at = NOW\(\), updated_by = \$2 WHERE id = \$1`).
+			WithArgs(1, nil).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		expectAuditEvent(mock, "", event)
+		mock.ExpectCommit()
+
+		repo := NewProductRepository(db)
+		id, err := repo.CreateProductPrice(model.ProductPrice{
+			ProductID:     1,
+			Price:         model.Money{Amount: 2490, Currency: "BRL"},
+			Kind:          model.PriceKindRegular,
+			EffectiveFrom: productAsOf,
+		}, event)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, id)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestProductRepository_GetProductsBySKUOrName(t *testing.T) {
+	t.Run("Matches Both Keys", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		rows := sqlmock.NewRows(productRowColumns).
+			AddRow(1, "Camiseta", "CAM-01", "49.90", "BRL", "standard", productCreatedAt, productCreatedAt, nil, nil).
+			AddRow(2, "Caneca", nil, "19.90", "BRL", "standard", productCreatedAt, productCreatedAt, nil, nil)
+		mock.ExpectQuery(`FROM products p LEFT JOIN LATERAL .* WHERE \(p.sku = ANY\(\$2\) OR p.product_name = ANY\(\$3\)\) AND p.deleted_at IS NULL ORDER BY p.id`).
+			WithArgs(sqlmock.AnyArg(), pq.Array([]string{"CAM-01"}), pq.Array([]string{"Caneca"})).
+			WillReturnRows(rows)
+
+		repo := NewProductRepository(db)
+		products, err := repo.GetProductsBySKUOrName([]string{"CAM-01"}, []string{"Caneca"})
+
+		assert.NoError(t, err)
+		assert.Len(t, products, 2)
+		assert.Equal(t, "CAM-01", products[0].SKU)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestProductRepository_ImportProducts(t *testing.T) {
+	t.Run("Writes Batch In One Transaction", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(`WITH created AS \( INSERT INTO products \(product_name, price, currency, sku, created_by, updated_by\) SELECT u.\*, \$6::int, \$6::int FROM unnest\(.*\) AS u RETURNING id, price \) INSERT INTO product_prices`).
+			WithArgs(pq.Array([]string{"Caneca"}), pq.Array([]string{"19.90"}), pq.Array([]string{"BRL"}),
+				pq.Array([]sql.NullString{{String: "CAN-01", Valid: true}}), model.PriceKindRegular, nil).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`UPDATE products p SET product_name = u.name, sku = COALESCE\(u.sku, p.sku\), updated_at = NOW\(\), updated_by = \$4 FROM unnest`).
+			WithArgs(pq.Array([]int64{1}), pq.Array([]string{"Camiseta"}), pq.Array([]sql.NullString{{}}), nil).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`INSERT INTO product_prices \(product_id, price, kind, effective_from\) SELECT id, price, \$3, NOW\(\) FROM unnest`).
+			WithArgs(pq.Array([]int64{1}), pq.Array([]string{"59.90"}), model.PriceKindRegular).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`UPDATE products SET updated_at = NOW\(\), updated_by = \$2 WHERE id = ANY\(\$1\)`).
+			WithArgs(pq.Array([]int64{1}), nil).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		event := model.AuditEvent{Action: model.AuditActionImport, EntityType: model.AuditEntityProductImport, EntityID: "job-1"}
+		expectAuditEvent(mock, "", event)
+		mock.ExpectCommit()
+
+		camiseta := model.Product{ID: 1, Name: "Camiseta", Price: model.Money{Amount: 5990, Currency: "BRL"}}
+		repo := NewProductRepository(db)
+		err = repo.ImportProducts(model.ProductImportBatch{
+			Create:  []model.Product{{Name: "Caneca", SKU: "CAN-01", Price: model.Money{Amount: 1990, Currency: "BRL"}}},
+			Update:  []model.Product{camiseta},
+			Reprice: []model.Product{camiseta},
+		}, event)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Rolls Back On Error", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(`UPDATE products p`).WillReturnError(errors.New("db error"))
+		mock.ExpectRollback()
+
+		repo := NewProductRepository(db)
+		err = repo.ImportProducts(model.ProductImportBatch{Update: []model.Product{{ID: 1, Name: "Camiseta"}}}, model.AuditEvent{})
+
+		assert.Error(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestProductRepository_ExportProducts(t *testing.T) {
+	t.Run("Fetches Until The Cursor Is Exhausted", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		full := sqlmock.NewRows(productRowColumns)
+		for id := 1; id <= cursorFetchSize; id++ {
+			full.AddRow(id, "Product", nil, "10.00", "BRL", "standard", productCreatedAt, productCreatedAt, nil, nil)
+		}
+		last := sqlmock.NewRows(productRowColumns).
+			AddRow(cursorFetchSize+1, "Camiseta", "CAM-01", "49.90", "BRL", "standard", productCreatedAt, productCreatedAt, nil, nil)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(`DECLARE export_cursor NO SCROLL CURSOR FOR SELECT p.id, p.product_name, p.sku, .* WHERE p.deleted_at IS NULL AND p.id IN \(.*root.id = \$2\) ORDER BY p.id`).
+			WithArgs(productAsOf, 3).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(`FETCH 500 FROM export_cursor`).WillReturnRows(full)
+		mock.ExpectQuery(`FETCH 500 FROM export_cursor`).WillReturnRows(last)
+		mock.ExpectCommit()
+
+		repo := NewProductRepository(db)
+		var exported []model.Product
+		err = repo.ExportProducts(context.Background(), model.ProductFilter{CategoryID: 3}, productAsOf, func(product model.Product) error {
+			exported = append(exported, product)
+			return nil
+		})
+
+		assert.NoError(t, err)
+		assert.Len(t, exported, cursorFetchSize+1)
+		assert.Equal(t, "CAM-01", exported[cursorFetchSize].SKU)
+		assert.Equal(t, model.Money{Amount: 4990, Currency: "BRL"}, exported[cursorFetchSize].Price)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Callback Error Stops The Export", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		rows := sqlmock.NewRows(productRowColumns).
+			AddRow(1, "Camiseta", nil, "49.90", "BRL", "standard", productCreatedAt, productCreatedAt, nil, nil).
+			AddRow(2, "Calça", nil, "99.90", "BRL", "standard", productCreatedAt, productCreatedAt, nil, nil)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(`DECLARE export_cursor`).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(`FETCH 500 FROM export_cursor`).WillReturnRows(rows)
+		mock.ExpectRollback()
+
+		repo := NewProductRepository(db)
+		calls := 0
+		err = repo.ExportProducts(context.Background(), model.ProductFilter{}, productAsOf, func(model.Product) error {
+			calls++
+			return errors.New("client went away")
+		})
+
+		assert.EqualError(t, err, "client went away")
+		assert.Equal(t, 1, calls)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestProductRepository_Trash(t *testing.T) {
+	t.Run("Delete Moves To The Trash", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		event := model.AuditEvent{Action: model.AuditActionDelete, EntityType: model.AuditEntityProduct, EntityID: "1"}
+
+		mock.ExpectBegin()
+		mock.ExpectExec(`UPDATE products SET deleted_at = NOW\(\), updated_at = NOW\(\), updated_by = \$2 WHERE id = \$1 AND deleted_at IS NULL`).
+			WithArgs(1, nil).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		expectAuditEvent(mock, "", event)
+		mock.ExpectCommit()
+
+		repo := NewProductRepository(db)
+		assert.NoError(t, repo.DeleteProduct(1, event))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Get Deleted Product By ID", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		deletedAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+		rows := sqlmock.NewRows(append(productRowColumns, "deleted_at")).
+			AddRow(1, "Camiseta", "CAM-01", "49.90", "BRL", "standard", productCreatedAt, deletedAt, nil, nil, deletedAt)
+
+		mock.ExpectQuery(`SELECT p.id, p.product_name, p.sku, COALESCE\(rp.price, p.price\), p.currency, p.tax_category, p.created_at, p.updated_at, p.created_by, p.updated_by, p.deleted_at FROM products p LEFT JOIN LATERAL .* WHERE p.deleted_at IS NOT NULL AND p.id = \$2`).
+			WithArgs(sqlmock.AnyArg(), 1).
+			WillReturnRows(rows)
+
+		repo := NewProductRepository(db)
+		product, err := repo.GetDeletedProductByID(1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "CAM-01", product.SKU)
+		assert.Equal(t, deletedAt, *product.DeletedAt)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Purge Only Deletes From The Trash", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		event := model.AuditEvent{Action: model.AuditActionPurge, EntityType: model.AuditEntityProduct, EntityID: "1"}
+
+		mock.ExpectBegin()
+		mock.ExpectExec(`DELETE FROM products WHERE id = \$1 AND deleted_at IS NOT NULL`).
+			WithArgs(1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		expectAuditEvent(mock, "", event)
+		mock.ExpectCommit()
+
+		repo := NewProductRepository(db)
+		assert.NoError(t, repo.PurgeProduct(1, event))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestProductRepository_TaxCategory(t *testing.T) {
+	t.Run("Set Tax Category", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		event := model.AuditEvent{Action: model.AuditActionUpdate, EntityType: model.AuditEntityProduct, EntityID: "1"}
+
+		mock.ExpectBegin()
+		mock.ExpectExec(`UPDATE products SET tax_category = \$2, updated_at = NOW\(\), updated_by = \$3 WHERE id = \$1 AND deleted_at IS NULL`).
+			WithArgs(1, "reduced", nil).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		expectAuditEvent(mock, "", event)
+		mock.ExpectCommit()
+
+		repo := NewProductRepository(db)
+		assert.NoError(t, repo.SetProductTaxCategory(1, "reduced", event))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Set Tax Category Of A Product In The Trash", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(`UPDATE products SET tax_category`).
+			WithArgs(1, "reduced", nil).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		repo := NewProductRepository(db)
+		err = repo.SetProductTaxCategory(1, "reduced", model.AuditEvent{})
+		assert.Equal(t, sql.ErrNoRows, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Get Tax Categories", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectQuery(`SELECT id, tax_category FROM products WHERE id = ANY\(\$1\)`).
+			WithArgs(pq.Array([]int{1, 2})).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "tax_category"}).AddRow(1, "standard").AddRow(2, "reduced"))
+
+		repo := NewProductRepository(db)
+		categories, err := repo.GetProductTaxCategories([]int{1, 2})
+
+		assert.NoError(t, err)
+		assert.Equal(t, map[int]string{1: "standard", 2: "reduced"}, categories)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,312 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"go-api/model"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// PromotionRepositoryInterface defines the contract for promotions, discount
+// codes and their redemptions
+type PromotionRepositoryInterface interface {
+	CreatePromotion(promotion model.Promotion) (int, error)
+	GetPromotions() ([]model.Promotion, error)
+	GetPromotionByID(id int) (*model.Promotion, error)
+	GetPromotionByCode(code string) (*model.Promotion, error)
+	GetAutomaticPromotions() ([]model.Promotion, error)
+	SetPromotionActive(id int, active bool) error
+	GetProductCategoryIDs(productIDs []int) (map[int][]int, error)
+	CountUserRedemptions(userID int, promotionIDs []int) (map[int]int, error)
+}
+
+type PromotionRepository struct {
+	connection *sql.DB
+}
+
+// Ensure PromotionRepository implements PromotionRepositoryInterface
+var _ PromotionRepositoryInterface = (*PromotionRepository)(nil)
+
+func NewPromotionRepository(connection *sql.DB) PromotionRepositoryInterface {
+	return &PromotionRepository{
+		connection: connection,
+	}
+}
+
+const selectPromotions = `SELECT p.id, p.code, p.name, p.kind, p.percent, p.currency, p.amount, p.min_total,
+		p.usage_limit, p.per_user_limit, p.uses, p.starts_at, p.ends_at, p.stackable, p.active, p.created_at,
+		COALESCE((SELECT array_agg(product_id ORDER BY product_id) FROM promotion_products WHERE promotion_id = p.id), '{}'),
+		COALESCE((SELECT array_agg(category_id ORDER BY category_id) FROM promotion_categories WHERE promotion_id = p.id), '{}')
+	FROM promotions p`
+
+func scanPromotion(row rowScanner) (model.Promotion, error) {
+	var promotion model.Promotion
+	var code, currency, amount, minTotal sql.NullString
+	var percent, usageLimit, perUserLimit sql.NullInt64
+	var startsAt, endsAt sql.NullTime
+	var productIDs, categoryIDs pq.Int64Array
+	err := row.Scan(&promotion.ID, &code, &promotion.Name, &promotion.Kind, &percent, &currency, &amount, &minTotal,
+		&usageLimit, &perUserLimit, &promotion.Uses, &startsAt, &endsAt, &promotion.Stackable, &promotion.Active,
+		&promotion.CreatedAt, &productIDs, &categoryIDs)
+	if err != nil {
+		return model.Promotion{}, err
+	}
+
+	promotion.Code = code.String
+	promotion.Percent = int(percent.Int64)
+	if amount.Valid {
+		value, err := model.ParseMoney(amount.String, currency.String)
+		if err != nil {
+			return model.Promotion{}, err
+		}
+		promotion.Amount = &value
+	}
+	if minTotal.Valid {
+		value, err := model.ParseMoney(minTotal.String, currency.String)
+		if err != nil {
+			return model.Promotion{}, err
+		}
+		promotion.MinTotal = &value
+	}
+	promotion.UsageLimit = nullableInt(usageLimit)
+	promotion.PerUserLimit = nullableInt(perUserLimit)
+	if startsAt.Valid {
+		promotion.StartsAt = &startsAt.Time
+	}
+	if endsAt.Valid {
+		promotion.EndsAt = &endsAt.Time
+	}
+	promotion.ProductIDs = intSlice(productIDs)
+	promotion.CategoryIDs = intSlice(categoryIDs)
+	return promotion, nil
+}
+
+// CreatePromotion saves the promotion with its eligible products and categories
+func (pr *PromotionRepository) CreatePromotion(promotion model.Promotion) (int, error) {
+	tx, err := pr.connection.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var code, currency, amount, minTotal sql.NullString
+	var percent sql.NullInt64
+	if promotion.Code != "" {
+		code = sql.NullString{String: promotion.Code, Valid: true}
+	}
+	if c := promotion.Currency(); c != "" {
+		currency = sql.NullString{String: c, Valid: true}
+	}
+	if promotion.Percent != 0 {
+		percent = sql.NullInt64{Int64: int64(promotion.Percent), Valid: true}
+	}
+	if promotion.Amount != nil {
+		amount = sql.NullString{String: promotion.Amount.String(), Valid: true}
+	}
+	if promotion.MinTotal != nil {
+		minTotal = sql.NullString{String: promotion.MinTotal.String(), Valid: true}
+	}
+
+	var id int
+	err = tx.QueryRow(`INSERT INTO promotions (code, name, kind, percent, currency, amount, min_total,
+			usage_limit, per_user_limit, starts_at, ends_at, stackable, active)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13) RETURNING id`,
+		code, promotion.Name, promotion.Kind, percent, currency, amount, minTotal,
+		promotion.UsageLimit, promotion.PerUserLimit, promotion.StartsAt, promotion.EndsAt,
+		promotion.Stackable, promotion.Active).Scan(&id)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(promotion.ProductIDs) > 0 {
+		_, err := tx.Exec(`INSERT INTO promotion_products (promotion_id, product_id) SELECT $1, unnest($2::int[])`,
+			id, pq.Array(promotion.ProductIDs))
+		if err != nil {
+			return 0, err
+		}
+	}
+	if len(promotion.CategoryIDs) > 0 {
+		_, err := tx.Exec(`INSERT INTO promotion_categories (promotion_id, category_id) SELECT $1, unnest($2::int[])`,
+			id, pq.Array(promotion.CategoryIDs))
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// GetPromotions lists every promotion, newest first
+func (pr *PromotionRepository) GetPromotions() ([]model.Promotion, error) {
+	return pr.queryPromotions(selectPromotions + " ORDER BY p.id DESC")
+}
+
+func (pr *PromotionRepository) GetPromotionByID(id int) (*model.Promotion, error) {
+	return pr.queryPromotion(selectPromotions+" WHERE p.id = $1", id)
+}
+
+// GetPromotionByCode finds a discount code; codes are stored in upper case
+func (pr *PromotionRepository) GetPromotionByCode(code string) (*model.Promotion, error) {
+	return pr.queryPromotion(selectPromotions+" WHERE p.code = $1", code)
+}
+
+// GetAutomaticPromotions lists the active promotions without a code; the
+// validity window is checked by the caller
+func (pr *PromotionRepository) GetAutomaticPromotions() ([]model.Promotion, error) {
+	return pr.queryPromotions(selectPromotions + " WHERE p.code IS NULL AND p.active ORDER BY p.id")
+}
+
+func (pr *PromotionRepository) SetPromotionActive(id int, active bool) error {
+	result, err := pr.connection.Exec(`UPDATE promotions SET active = $2 WHERE id = $1`, id, active)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// GetProductCategoryIDs returns, for each product, its categories and all
+// their ancestors, so a promotion on a category covers its subcategories
+func (pr *PromotionRepository) GetProductCategoryIDs(productIDs []int) (map[int][]int, error) {
+	rows, err := pr.connection.Query(`SELECT DISTINCT pc.product_id, a.id
+		FROM product_categories pc
+		JOIN categories c ON c.id = pc.category_id
+		JOIN categories a ON c.path = a.path OR c.path LIKE a.path || '/%'
+		WHERE pc.product_id = ANY($1)
+		ORDER BY pc.product_id, a.id`, pq.Array(productIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	categories := make(map[int][]int)
+	for rows.Next() {
+		var productID, categoryID int
+		if err := rows.Scan(&productID, &categoryID); err != nil {
+			return nil, err
+		}
+		categories[productID] = append(categories[productID], categoryID)
+	}
+	return categories, rows.Err()
+}
+
+// CountUserRedemptions counts the redemptions of the promotions by the user,
+// leaving out those of cancelled orders
+func (pr *PromotionRepository) CountUserRedemptions(userID int, promotionIDs []int) (map[int]int, error) {
+	rows, err := pr.connection.Query(`SELECT promotion_id, COUNT(*) FROM promotion_redemptions
+		WHERE user_id = $1 AND promotion_id = ANY($2) AND released_at IS NULL
+		GROUP BY promotion_id`, userID, pq.Array(promotionIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[int]int)
+	for rows.Next() {
+		var promotionID, count int
+		if err := rows.Scan(&promotionID, &count); err != nil {
+			return nil, err
+		}
+		counts[promotionID] = count
+	}
+	return counts, rows.Err()
+}
+
+// --- Helper Functions ---
+
+func (pr *PromotionRepository) queryPromotion(query string, args ...interface{}) (*model.Promotion, error) {
+	promotion, err := scanPromotion(pr.connection.QueryRow(query, args...))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &promotion, nil
+}
+
+func (pr *PromotionRepository) queryPromotions(query string, args ...interface{}) ([]model.Promotion, error) {
+	rows, err := pr.connection.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	promotions := []model.Promotion{}
+	for rows.Next() {
+		promotion, err := scanPromotion(rows)
+		if err != nil {
+			return nil, err
+		}
+		promotions = append(promotions, promotion)
+	}
+	return promotions, rows.Err()
+}
+
+// redeemPromotions takes one use of each promotion applied to the order. The
+// UPDATE locks the promotion row, so concurrent checkouts of the same
+// promotion run one after the other and the limits hold
+func redeemPromotions(tx *sql.Tx, orderID int, userID *int, promotions []model.AppliedPromotion, now time.Time) error {
+	for _, applied := range promotions {
+		var perUserLimit sql.NullInt64
+		err := tx.QueryRow(`UPDATE promotions SET uses = uses + 1
+			WHERE id = $1 AND active AND (usage_limit IS NULL OR uses < usage_limit)
+			RETURNING per_user_limit`, applied.PromotionID).Scan(&perUserLimit)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("%w: %s", ErrPromotionUnavailable, applied.Name)
+		}
+		if err != nil {
+			return err
+		}
+
+		if perUserLimit.Valid && userID != nil {
+			var count int64
+			err := tx.QueryRow(`SELECT COUNT(*) FROM promotion_redemptions
+				WHERE promotion_id = $1 AND user_id = $2 AND released_at IS NULL`, applied.PromotionID, *userID).Scan(&count)
+			if err != nil {
+				return err
+			}
+			if count >= perUserLimit.Int64 {
+				return fmt.Errorf("%w: %s", ErrPromotionUnavailable, applied.Name)
+			}
+		}
+
+		_, err = tx.Exec(`INSERT INTO promotion_redemptions (promotion_id, order_id, user_id, discount, redeemed_at)
+			VALUES ($1, $2, $3, $4, $5)`, applied.PromotionID, orderID, userID, applied.Discount.String(), now)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// releasePromotions gives back the uses taken by a cancelled order
+func releasePromotions(tx *sql.Tx, orderID int, now time.Time) error {
+	_, err := tx.Exec(`UPDATE promotions p SET uses = p.uses - 1
+		FROM promotion_redemptions r
+		WHERE r.order_id = $1 AND r.promotion_id = p.id AND r.released_at IS NULL`, orderID)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(`UPDATE promotion_redemptions SET released_at = $2 WHERE order_id = $1 AND released_at IS NULL`, orderID, now)
+	return err
+}
+
+func intSlice(values pq.Int64Array) []int {
+	ints := make([]int, len(values))
+	for i, value := range values {
+		ints[i] = int(value)
+	}
+	return ints
+}
@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"go-api/model"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPromotionRepository_CreatePromotion(t *testing.T) {
+	t.Run("With Eligible Products And Categories", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		amount := model.Money{Amount: 2000, Currency: "BRL"}
+		limit := 100
+		mock.ExpectBegin()
+		mock.ExpectQuery(`INSERT INTO promotions \(code, name, kind, percent, currency, amount, min_total, usage_limit, per_user_limit, starts_at, ends_at, stackable, active\)`).
+			WithArgs("VERAO20", "Verão", "fixed", nil, "BRL", "20.00", nil, int64(100), nil, nil, nil, false, true).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+		mock.ExpectExec(`INSERT INTO promotion_products \(promotion_id, product_id\) SELECT \$1, unnest\(\$2::int\[\]\)`).
+			WithArgs(4, sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectExec(`INSERT INTO promotion_categories \(promotion_id, category_id\) SELECT \$1, unnest\(\$2::int\[\]\)`).
+			WithArgs(4, sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		repo := NewPromotionRepository(db)
+		id, err := repo.CreatePromotion(model.Promotion{
+			Code:        "VERAO20",
+			Name:        "Verão",
+			Kind:        model.DiscountFixed,
+			Amount:      &amount,
+			ProductIDs:  []int{1, 2},
+			CategoryIDs: []int{3},
+			UsageLimit:  &limit,
+			Active:      true,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 4, id)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestPromotionRepository_GetPromotionByCode(t *testing.T) {
+	columns := []string{"id", "code", "name", "kind", "percent", "currency", "amount", "min_total",
+		"usage_limit", "per_user_limit", "uses", "starts_at", "ends_at", "stackable", "active", "created_at",
+		"product_ids", "category_ids"}
+
+	t.Run("Success", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		createdAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+		mock.ExpectQuery(`FROM promotions p WHERE p.code = \$1`).
+			WithArgs("BEMVINDO10").
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow(3, "BEMVINDO10", "Boas-vindas", "percentage", 10, "BRL", nil, "100.000",
+					nil, 1, 5, nil, nil, false, true, createdAt, "{}", "{2,5}"))
+
+		repo := NewPromotionRepository(db)
+		promotion, err := repo.GetPromotionByCode("BEMVINDO10")
+
+		assert.NoError(t, err)
+		assert.Equal(t, 10, promotion.Percent)
+		assert.Nil(t, promotion.Amount)
+		assert.Equal(t, model.Money{Amount: 10000, Currency: "BRL"}, *promotion.MinTotal)
+		assert.Nil(t, promotion.UsageLimit)
+		assert.Equal(t, 1, *promotion.PerUserLimit)
+		assert.Equal(t, []int{}, promotion.ProductIDs)
+		assert.Equal(t, []int{2, 5}, promotion.CategoryIDs)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectQuery(`FROM promotions p WHERE p.code = \$1`).
+			WithArgs("NADA").
+			WillReturnRows(sqlmock.NewRows(columns))
+
+		repo := NewPromotionRepository(db)
+		promotion, err := repo.GetPromotionByCode("NADA")
+
+		assert.NoError(t, err)
+		assert.Nil(t, promotion)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestPromotionRepository_CountUserRedemptions(t *testing.T) {
+	t.Run("Leaves Out Released Redemptions", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectQuery(`SELECT promotion_id, COUNT\(\*\) FROM promotion_redemptions WHERE user_id = \$1 AND promotion_id = ANY\(\$2\) AND released_at IS NULL`).
+			WithArgs(7, pq.Array([]int{3, 4})).
+			WillReturnRows(sqlmock.NewRows([]string{"promotion_id", "count"}).AddRow(3, 2))
+
+		repo := NewPromotionRepository(db)
+		counts, err := repo.CountUserRedemptions(7, []int{3, 4})
+
+		assert.NoError(t, err)
+		assert.Equal(t, map[int]int{3: 2}, counts)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -1,18 +1,33 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
+	"fmt"
 	"go-api/model"
+	"strconv"
+	"time"
 )
 
 // UserRepositoryInterface defines the contract for the user repository
 type UserRepositoryInterface interface {
-	CreateUser(user model.User) (int, error)
+	CreateUser(user model.User, event model.AuditEvent) (int, error)
 	GetUserByID(id int) (*model.User, error)
 	GetUserByEmail(email string) (*model.User, error)
-	UpdateUser(user model.User) error
-	DeleteUser(id int) error
-	GetUsers() ([]model.User, error)
+	UpdateUser(user model.User, event model.AuditEvent) error
+	DeleteUser(id int, event model.AuditEvent) error
+	GetUsers(filter model.UserFilter) ([]model.User, error)
+	ExportUsers(ctx context.Context, filter model.UserFilter, fn func(model.User) error) error
+	GetDeletedUsers() ([]model.User, error)
+	GetDeletedUserByID(id int) (*model.User, error)
+	GetDeletedUserByEmail(email string) (*model.User, error)
+	RestoreUser(id int, event model.AuditEvent) error
+	PurgeUser(id int, event model.AuditEvent) error
+	QueueVerificationEmail(id int, resendInterval time.Duration, email model.OutboxEmail) (bool, error)
+	QueueSignupNotice(id int, interval time.Duration, email model.OutboxEmail) (bool, error)
+	MarkEmailVerified(id int, email string, event model.AuditEvent) error
+	ConfirmEmailChange(id int, email string, event model.AuditEvent) error
+	UpdatePasswordHash(id int, oldHash, newHash string) error
 }
 
 type UserRepository struct {
@@ -28,9 +43,38 @@ func NewUserRepository(connection *sql.DB) UserRepositoryInterface {
 	}
 }
 
-func (ur *UserRepository) CreateUser(user model.User) (int, error) {
+// userColumns are the columns read by lookups and listings; the password
+// hash is only read by GetUserByEmail, for the login
+const userColumns = `id, name, email, role, email_verified_at, COALESCE(pending_email, ''), created_at, updated_at, created_by, updated_by`
+
+const selectUsers = `SELECT ` + userColumns + ` FROM users`
+
+// scanUser reads userColumns followed by the extra destinations
+func scanUser(row rowScanner, extra ...interface{}) (model.User, error) {
+	var user model.User
+	var createdBy, updatedBy sql.NullInt64
+	var verifiedAt sql.NullTime
+	dest := append([]interface{}{&user.ID, &user.Name, &user.Email, &user.Role, &verifiedAt, &user.PendingEmail,
+		&user.CreatedAt, &user.UpdatedAt, &createdBy, &updatedBy}, extra...)
+	if err := row.Scan(dest...); err != nil {
+		return model.User{}, err
+	}
+	if verifiedAt.Valid {
+		user.EmailVerifiedAt = &verifiedAt.Time
+	}
+	user.CreatedBy = nullableInt(createdBy)
+	user.UpdatedBy = nullableInt(updatedBy)
+	return user, nil
+}
+
+// CreateUser inserts the user and records the event with its new ID
+func (ur *UserRepository) CreateUser(user model.User, event model.AuditEvent) (int, error) {
 	var id int
-	err := ur.connection.QueryRow(`INSERT INTO users (name, email, password) VALUES ($1, $2, $3) RETURNING id`, user.Name, user.Email, user.Password).Scan(&id)
+	err := withAuditEvent(ur.connection, &event, func(tx *sql.Tx) error {
+		err := tx.QueryRow(`INSERT INTO users (name, email, password, created_by, updated_by) VALUES ($1, $2, $3, $4, $4) RETURNING id`, user.Name, user.Email, user.Password, event.ActorID).Scan(&id)
+		event.EntityID = strconv.Itoa(id)
+		return err
+	})
 	if err != nil {
 		return 0, err
 	}
@@ -39,8 +83,7 @@ func (ur *UserRepository) CreateUser(user model.User) (int, error) {
 }
 
 func (ur *UserRepository) GetUserByID(id int) (*model.User, error) {
-	var user model.User
-	err := ur.connection.QueryRow(`SELECT id, name, email FROM users WHERE id = $1`, id).Scan(&user.ID, &user.Name, &user.Email)
+	user, err := scanUser(ur.connection.QueryRow(selectUsers+` WHERE id = $1 AND deleted_at IS NULL`, id))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -53,29 +96,110 @@ func (ur *UserRepository) GetUserByID(id int) (*model.User, error) {
 
 func (ur *UserRepository) GetUserByEmail(email string) (*model.User, error) {
 	var user model.User
-	err := ur.connection.QueryRow(`SELECT id, name, email, password FROM users WHERE email = $1`, email).Scan(&user.ID, &user.Name, &user.Email, &user.Password)
+	var verifiedAt sql.NullTime
+	err := ur.connection.QueryRow(`SELECT id, name, email, password, role, email_verified_at, COALESCE(pending_email, ''),
+		EXISTS (SELECT 1 FROM user_mfa WHERE user_mfa.user_id = users.id AND user_mfa.confirmed_at IS NOT NULL)
+		FROM users WHERE email = $1 AND deleted_at IS NULL`, email).
+		Scan(&user.ID, &user.Name, &user.Email, &user.Password, &user.Role, &verifiedAt, &user.PendingEmail, &user.MFAEnabled)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
 		}
 		return nil, err
 	}
+	if verifiedAt.Valid {
+		user.EmailVerifiedAt = &verifiedAt.Time
+	}
 
 	return &user, nil
 }
 
-func (ur *UserRepository) UpdateUser(user model.User) error {
-	_, err := ur.connection.Exec(`UPDATE users SET name = $1, email = $2, password = $3 WHERE id = $4`, user.Name, user.Email, user.Password, user.ID)
-	return err
+// UpdateUser keeps the current password hash when user.Password is empty; a
+// new password invalidates the pending password reset tokens, and a new email
+// is no longer verified
+func (ur *UserRepository) UpdateUser(user model.User, event model.AuditEvent) error {
+	return withAuditEvent(ur.connection, &event, func(tx *sql.Tx) error {
+		_, err := tx.Exec(`UPDATE users SET name = $1, email = $2, password = COALESCE(NULLIF($3, ''), password),
+			email_verified_at = CASE WHEN email = $2 THEN email_verified_at END, pending_email = NULLIF($6, ''), updated_at = NOW(), updated_by = $5
+			WHERE id = $4 AND deleted_at IS NULL`, user.Name, user.Email, user.Password, user.ID, event.ActorID, user.PendingEmail)
+		if err != nil || user.Password == "" {
+			return err
+		}
+		return invalidatePasswordResetTokens(tx, user.ID)
+	})
+}
+
+// DeleteUser moves the user to the trash; PurgeUser removes it for good
+func (ur *UserRepository) DeleteUser(id int, event model.AuditEvent) error {
+	return withAuditEvent(ur.connection, &event, func(tx *sql.Tx) error {
+		_, err := tx.Exec(`UPDATE users SET deleted_at = NOW(), updated_at = NOW(), updated_by = $2 WHERE id = $1 AND deleted_at IS NULL`, id, event.ActorID)
+		return err
+	})
+}
+
+// GetUsers lists the users outside the trash, optionally only the ones
+// changed since filter.UpdatedSince
+func (ur *UserRepository) GetUsers(filter model.UserFilter) ([]model.User, error) {
+	query, args := usersQuery(filter)
+	rows, err := ur.connection.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []model.User
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+
+	return users, rows.Err()
+}
+
+// ExportUsers streams the users of the filter, in id order, to fn through
+// a server-side cursor. The password hash is never selected
+func (ur *UserRepository) ExportUsers(ctx context.Context, filter model.UserFilter, fn func(model.User) error) error {
+	query, args := usersQuery(filter)
+	return streamCursor(ctx, ur.connection, query, args, func(row rowScanner) error {
+		user, err := scanUser(row)
+		if err != nil {
+			return err
+		}
+		return fn(user)
+	})
 }
 
-func (ur *UserRepository) DeleteUser(id int) error {
-	_, err := ur.connection.Exec(`DELETE FROM users WHERE id = $1`, id)
-	return err
+// usersQuery builds the listing query of the filter, shared by the listing
+// and the export
+func usersQuery(filter model.UserFilter) (string, []interface{}) {
+	query := selectUsers + " WHERE deleted_at IS NULL"
+	var args []interface{}
+	if !filter.UpdatedSince.IsZero() {
+		args = append(args, filter.UpdatedSince)
+		query += " AND updated_at >= $1"
+	}
+	return query + " ORDER BY id", args
 }
 
-func (ur *UserRepository) GetUsers() ([]model.User, error) {
-	rows, err := ur.connection.Query("SELECT id, name, email FROM users ORDER BY id")
+// selectDeletedUsers never selects the password hash, like every listing
+const selectDeletedUsers = `SELECT ` + userColumns + `, deleted_at FROM users WHERE deleted_at IS NOT NULL`
+
+func scanDeletedUser(row rowScanner) (model.User, error) {
+	var deletedAt time.Time
+	user, err := scanUser(row, &deletedAt)
+	if err != nil {
+		return model.User{}, err
+	}
+	user.DeletedAt = &deletedAt
+	return user, nil
+}
+
+// GetDeletedUsers lists the trash, most recently deleted first
+func (ur *UserRepository) GetDeletedUsers() ([]model.User, error) {
+	rows, err := ur.connection.Query(selectDeletedUsers + " ORDER BY deleted_at DESC, id")
 	if err != nil {
 		return nil, err
 	}
@@ -83,13 +207,151 @@ func (ur *UserRepository) GetUsers() ([]model.User, error) {
 
 	var users []model.User
 	for rows.Next() {
-		var user model.User
-		err = rows.Scan(&user.ID, &user.Name, &user.Email)
+		user, err := scanDeletedUser(rows)
 		if err != nil {
 			return nil, err
 		}
 		users = append(users, user)
 	}
+	return users, rows.Err()
+}
+
+func (ur *UserRepository) GetDeletedUserByID(id int) (*model.User, error) {
+	user, err := scanDeletedUser(ur.connection.QueryRow(selectDeletedUsers+" AND id = $1", id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &user, nil
+}
+
+// GetDeletedUserByEmail returns the most recently deleted user with the email
+func (ur *UserRepository) GetDeletedUserByEmail(email string) (*model.User, error) {
+	user, err := scanDeletedUser(ur.connection.QueryRow(selectDeletedUsers+" AND email = $1 ORDER BY deleted_at DESC LIMIT 1", email))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &user, nil
+}
+
+// RestoreUser takes the user out of the trash
+func (ur *UserRepository) RestoreUser(id int, event model.AuditEvent) error {
+	return withAuditEvent(ur.connection, &event, func(tx *sql.Tx) error {
+		_, err := tx.Exec(`UPDATE users SET deleted_at = NULL, updated_at = NOW(), updated_by = $2 WHERE id = $1 AND deleted_at IS NOT NULL`, id, event.ActorID)
+		return err
+	})
+}
+
+// PurgeUser deletes a user in the trash permanently; its audit events stay
+func (ur *UserRepository) PurgeUser(id int, event model.AuditEvent) error {
+	return withAuditEvent(ur.connection, &event, func(tx *sql.Tx) error {
+		_, err := tx.Exec(`DELETE FROM users WHERE id = $1 AND deleted_at IS NOT NULL`, id)
+		return err
+	})
+}
+
+// QueueVerificationEmail enqueues the email carrying a verification link,
+// unless another one was sent to the user less than resendInterval ago, in
+// which case it returns false
+func (ur *UserRepository) QueueVerificationEmail(id int, resendInterval time.Duration, email model.OutboxEmail) (bool, error) {
+	return ur.queueUserEmail("verification_sent_at", id, resendInterval, email)
+}
+
+// QueueSignupNotice enqueues the email telling the user that someone tried
+// to sign up with their email, unless another one was sent less than
+// interval ago, in which case it returns false
+func (ur *UserRepository) QueueSignupNotice(id int, interval time.Duration, email model.OutboxEmail) (bool, error) {
+	return ur.queueUserEmail("signup_notice_sent_at", id, interval, email)
+}
+
+// MarkEmailVerified records that the user owns the email, provided it is
+// still the email of the user; otherwise it returns sql.ErrNoRows
+func (ur *UserRepository) MarkEmailVerified(id int, email string, event model.AuditEvent) error {
+	return withAuditEvent(ur.connection, &event, func(tx *sql.Tx) error {
+		result, err := tx.Exec(`UPDATE users SET email_verified_at = NOW(), updated_at = NOW(), updated_by = $3
+			WHERE id = $1 AND email = $2 AND deleted_at IS NULL`, id, email, event.ActorID)
+		if err != nil {
+			return err
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return sql.ErrNoRows
+		}
+		return nil
+	})
+}
+
+// UpdatePasswordHash swaps the hash of the same password for a newer one,
+// provided the user still has oldHash; otherwise it returns sql.ErrNoRows.
+// The password does not change, so it is neither audited nor an update, and
+// the pending password reset tokens stay valid
+func (ur *UserRepository) UpdatePasswordHash(id int, oldHash, newHash string) error {
+	result, err := ur.connection.Exec(`UPDATE users SET password = $3 WHERE id = $1 AND password = $2`, id, oldHash, newHash)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
 
-	return users, nil
+// ConfirmEmailChange makes the pending email the email of the user, already
+// verified, provided it is still the pending one; otherwise it returns
+// sql.ErrNoRows
+func (ur *UserRepository) ConfirmEmailChange(id int, email string, event model.AuditEvent) error {
+	return withAuditEvent(ur.connection, &event, func(tx *sql.Tx) error {
+		result, err := tx.Exec(`UPDATE users SET email = pending_email, pending_email = NULL, email_verified_at = NOW(), verification_sent_at = NULL,
+			updated_at = NOW(), updated_by = $3
+			WHERE id = $1 AND pending_email = $2 AND deleted_at IS NULL`, id, email, event.ActorID)
+		if err != nil {
+			return err
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return sql.ErrNoRows
+		}
+		return nil
+	})
+}
+
+// queueUserEmail enqueues an email to the user if sentColumn, the last time
+// such an email was sent, is older than interval. The check and the update
+// of sentColumn are a single UPDATE, so concurrent requests cannot both pass
+func (ur *UserRepository) queueUserEmail(sentColumn string, id int, interval time.Duration, email model.OutboxEmail) (bool, error) {
+	tx, err := ur.connection.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(fmt.Sprintf(`UPDATE users SET %[1]s = NOW()
+		WHERE id = $1 AND deleted_at IS NULL AND (%[1]s IS NULL OR %[1]s <= NOW() - make_interval(secs => $2))`, sentColumn),
+		id, interval.Seconds())
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil || affected == 0 {
+		return false, err
+	}
+	if err := enqueueEmail(tx, email); err != nil {
+		return false, err
+	}
+	return true, tx.Commit()
 }
@@ -1,16 +1,20 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"go-api/model"
 	"regexp"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/assert"
 )
 
+var userRowColumns = []string{"id", "name", "email", "role", "email_verified_at", "pending_email", "created_at", "updated_at", "created_by", "updated_by"}
+
 func TestUserRepository_CreateUser(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		db, mock, err := sqlmock.New()
@@ -24,13 +28,21 @@ func TestUserRepository_CreateUser(t *testing.T) {
 		}
 
 		expectedID := 1
+		actor := 9
+		event := model.AuditEvent{ActorID: &actor, Action: model.AuditActionCreate, EntityType: model.AuditEntityUser, RequestID: "req-1"}
 
-		mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO users (name, email, password) VALUES ($1, $2, $3) RETURNING id")).
-			WithArgs(user.Name, user.Email, user.Password).
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO users (name, email, password, created_by, updated_by) VALUES ($1, $2, $3, $4, $4) RETURNING id")).
+			WithArgs(user.Name, user.Email, user.Password, int64(actor)).
 			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(expectedID))
+		// The event gets the ID of the new user
+		recorded := event
+		recorded.EntityID = "1"
+		expectAuditEvent(mock, "", recorded)
+		mock.ExpectCommit()
 
 		repo := NewUserRepository(db)
-		id, err := repo.CreateUser(user)
+		id, err := repo.CreateUser(user, event)
 
 		assert.NoError(t, err)
 		assert.Equal(t, expectedID, id)
@@ -50,10 +62,11 @@ func TestUserRepository_GetUserByID(t *testing.T) {
 			Email: "leandro@example.com",
 		}
 
-		rows := sqlmock.NewRows([]string{"id", "name", "email"}).
-			AddRow(expectedUser.ID, expectedUser.Name, expectedUser.Email)
+		createdAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+		rows := sqlmock.NewRows(userRowColumns).
+			AddRow(expectedUser.ID, expectedUser.Name, expectedUser.Email, model.RoleCustomer, createdAt, "new@example.com", createdAt, createdAt.Add(time.Hour), nil, 7)
 
-		mock.ExpectQuery(regexp.QuoteMeta("SELECT id, name, email FROM users WHERE id = $1")).
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT id, name, email, role, email_verified_at, COALESCE(pending_email, ''), created_at, updated_at, created_by, updated_by FROM users WHERE id = $1 AND deleted_at IS NULL")).
 			WithArgs(1).
 			WillReturnRows(rows)
 
@@ -63,6 +76,12 @@ func TestUserRepository_GetUserByID(t *testing.T) {
 		assert.NoError(t, err)
 		assert.NotNil(t, user)
 		assert.Equal(t, expectedUser.ID, user.ID)
+		assert.Equal(t, createdAt, user.CreatedAt)
+		assert.Equal(t, createdAt.Add(time.Hour), user.UpdatedAt)
+		assert.Equal(t, createdAt, *user.EmailVerifiedAt)
+		assert.Equal(t, "new@example.com", user.PendingEmail)
+		assert.Nil(t, user.CreatedBy)
+		assert.Equal(t, 7, *user.UpdatedBy)
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 
@@ -71,7 +90,7 @@ func TestUserRepository_GetUserByID(t *testing.T) {
 		assert.NoError(t, err)
 		defer db.Close()
 
-		mock.ExpectQuery(regexp.QuoteMeta("SELECT id, name, email FROM users WHERE id = $1")).
+		mock.ExpectQuery(regexp.QuoteMeta("FROM users WHERE id = $1 AND deleted_at IS NULL")).
 			WithArgs(999).
 			WillReturnError(sql.ErrNoRows)
 
@@ -92,9 +111,9 @@ func TestUserRepository_GetUserByEmail(t *testing.T) {
 
 		email := "user@example.com"
 		password := "password123"
-		mock.ExpectQuery(regexp.QuoteMeta("SELECT id, name, email, password FROM users WHERE email = $1")).
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT id, name, email, password, role, email_verified_at, COALESCE(pending_email, ''), EXISTS (SELECT 1 FROM user_mfa WHERE user_mfa.user_id = users.id AND user_mfa.confirmed_at IS NOT NULL) FROM users WHERE email = $1 AND deleted_at IS NULL")).
 			WithArgs(email).
-			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email", "password"}).AddRow(1, "User", email, password))
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email", "password", "role", "email_verified_at", "pending_email", "mfa_enabled"}).AddRow(1, "User", email, password, "customer", nil, "", true))
 
 		repo := NewUserRepository(db)
 		user, err := repo.GetUserByEmail(email)
@@ -102,6 +121,7 @@ func TestUserRepository_GetUserByEmail(t *testing.T) {
 		assert.NotNil(t, user)
 		assert.Equal(t, email, user.Email)
 		assert.Equal(t, password, user.Password)
+		assert.True(t, user.MFAEnabled)
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 
@@ -111,9 +131,9 @@ func TestUserRepository_GetUserByEmail(t *testing.T) {
 		defer db.Close()
 
 		email := "notfound@example.com"
-		mock.ExpectQuery(regexp.QuoteMeta("SELECT id, name, email, password FROM users WHERE email = $1")).
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT id, name, email, password, role, email_verified_at, COALESCE(pending_email, ''), EXISTS (SELECT 1 FROM user_mfa WHERE user_mfa.user_id = users.id AND user_mfa.confirmed_at IS NOT NULL) FROM users WHERE email = $1 AND deleted_at IS NULL")).
 			WithArgs(email).
-			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email", "password"}))
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email", "password", "role", "email_verified_at", "pending_email", "mfa_enabled"}))
 
 		repo := NewUserRepository(db)
 		user, err := repo.GetUserByEmail(email)
@@ -128,7 +148,7 @@ func TestUserRepository_GetUserByEmail(t *testing.T) {
 		defer db.Close()
 
 		email := "user@example.com"
-		mock.ExpectQuery(regexp.QuoteMeta("SELECT id, name, email, password FROM users WHERE email = $1")).
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT id, name, email, password, role, email_verified_at, COALESCE(pending_email, ''), EXISTS (SELECT 1 FROM user_mfa WHERE user_mfa.user_id = users.id AND user_mfa.confirmed_at IS NOT NULL) FROM users WHERE email = $1 AND deleted_at IS NULL")).
 			WithArgs(email).
 			WillReturnError(errors.New("db error"))
 
@@ -154,12 +174,20 @@ func TestUserRepository_UpdateUser(t *testing.T) {
 			Password: "newpassword",
 		}
 
-		mock.ExpectExec(regexp.QuoteMeta("UPDATE users SET name = $1, email = $2, password = $3 WHERE id = $4")).
-			WithArgs(user.Name, user.Email, user.Password, user.ID).
+		event := model.AuditEvent{Action: model.AuditActionUpdate, EntityType: model.AuditEntityUser, EntityID: "1"}
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE users SET name = $1, email = $2, password = COALESCE(NULLIF($3, ''), password), email_verified_at = CASE WHEN email = $2 THEN email_verified_at END, pending_email = NULLIF($6, ''), updated_at = NOW(), updated_by = $5 WHERE id = $4 AND deleted_at IS NULL")).
+			WithArgs(user.Name, user.Email, user.Password, user.ID, nil, "").
 			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE password_reset_tokens SET used_at = NOW() WHERE user_id = $1 AND used_at IS NULL")).
+			WithArgs(user.ID).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		expectAuditEvent(mock, "abc123", event)
+		mock.ExpectCommit()
 
 		repo := NewUserRepository(db)
-		err = repo.UpdateUser(user)
+		err = repo.UpdateUser(user, event)
 
 		assert.NoError(t, err)
 		assert.NoError(t, mock.ExpectationsWereMet())
@@ -172,14 +200,39 @@ func TestUserRepository_DeleteUser(t *testing.T) {
 		assert.NoError(t, err)
 		defer db.Close()
 
-		mock.ExpectExec(regexp.QuoteMeta("DELETE FROM users WHERE id = $1")).
-			WithArgs(1).
+		event := model.AuditEvent{Action: model.AuditActionDelete, EntityType: model.AuditEntityUser, EntityID: "1"}
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE users SET deleted_at = NOW(), updated_at = NOW(), updated_by = $2 WHERE id = $1 AND deleted_at IS NULL")).
+			WithArgs(1, nil).
 			WillReturnResult(sqlmock.NewResult(1, 1))
+		expectAuditEvent(mock, "", event)
+		mock.ExpectCommit()
 
 		repo := NewUserRepository(db)
-		err = repo.DeleteUser(1)
+		err = repo.DeleteUser(1, event)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
 
+	t.Run("Audit Failure Rolls Back", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
 		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE users SET deleted_at = NOW()")).
+			WithArgs(1, nil).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(regexp.QuoteMeta("SELECT pg_advisory_xact_lock($1)")).
+			WillReturnError(errors.New("lock timeout"))
+		mock.ExpectRollback()
+
+		repo := NewUserRepository(db)
+		err = repo.DeleteUser(1, model.AuditEvent{Action: model.AuditActionDelete})
+
+		assert.Error(t, err)
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 }
@@ -195,15 +248,16 @@ func TestUserRepository_GetUsers(t *testing.T) {
 			{ID: 2, Name: "User 2", Email: "user2@example.com"},
 		}
 
-		rows := sqlmock.NewRows([]string{"id", "name", "email"}).
-			AddRow(expectedUsers[0].ID, expectedUsers[0].Name, expectedUsers[0].Email).
-			AddRow(expectedUsers[1].ID, expectedUsers[1].Name, expectedUsers[1].Email)
+		createdAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+		rows := sqlmock.NewRows(userRowColumns).
+			AddRow(expectedUsers[0].ID, expectedUsers[0].Name, expectedUsers[0].Email, model.RoleCustomer, nil, "", createdAt, createdAt, nil, nil).
+			AddRow(expectedUsers[1].ID, expectedUsers[1].Name, expectedUsers[1].Email, model.RoleCustomer, nil, "", createdAt, createdAt, nil, nil)
 
-		mock.ExpectQuery("SELECT id, name, email FROM users ORDER BY id").
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT id, name, email, role, email_verified_at, COALESCE(pending_email, ''), created_at, updated_at, created_by, updated_by FROM users WHERE deleted_at IS NULL ORDER BY id")).
 			WillReturnRows(rows)
 
 		repo := NewUserRepository(db)
-		users, err := repo.GetUsers()
+		users, err := repo.GetUsers(model.UserFilter{})
 
 		assert.NoError(t, err)
 		assert.Len(t, users, 2)
@@ -216,15 +270,287 @@ func TestUserRepository_GetUsers(t *testing.T) {
 		assert.NoError(t, err)
 		defer db.Close()
 
-		mock.ExpectQuery("SELECT id, name, email FROM users ORDER BY id").
+		mock.ExpectQuery(regexp.QuoteMeta("FROM users WHERE deleted_at IS NULL ORDER BY id")).
 			WillReturnError(errors.New("connection failed"))
 
 		repo := NewUserRepository(db)
-		users, err := repo.GetUsers()
+		users, err := repo.GetUsers(model.UserFilter{})
 
 		assert.Error(t, err)
 		assert.Nil(t, users)
 		assert.Contains(t, err.Error(), "connection failed")
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
+
+	t.Run("Updated Since", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		since := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+		mock.ExpectQuery(regexp.QuoteMeta("FROM users WHERE deleted_at IS NULL AND updated_at >= $1 ORDER BY id")).
+			WithArgs(since).
+			WillReturnRows(sqlmock.NewRows(userRowColumns))
+
+		repo := NewUserRepository(db)
+		users, err := repo.GetUsers(model.UserFilter{UpdatedSince: since})
+
+		assert.NoError(t, err)
+		assert.Empty(t, users)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestUserRepository_ExportUsers(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		createdAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+		since := createdAt.Add(-time.Hour)
+		rows := sqlmock.NewRows(userRowColumns).
+			AddRow(1, "User 1", "user1@example.com", model.RoleAdmin, nil, "", createdAt, createdAt, nil, nil).
+			AddRow(2, "User 2", "user2@example.com", model.RoleCustomer, nil, "", createdAt, createdAt.Add(time.Hour), nil, 1)
+
+		mock.ExpectBegin()
+		// The password hash must never be part of the export query
+		mock.ExpectExec(regexp.QuoteMeta("DECLARE export_cursor NO SCROLL CURSOR FOR SELECT id, name, email, role, email_verified_at, COALESCE(pending_email, ''), created_at, updated_at, created_by, updated_by FROM users WHERE deleted_at IS NULL AND updated_at >= $1 ORDER BY id")).
+			WithArgs(since).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(`FETCH 500 FROM export_cursor`).WillReturnRows(rows)
+		mock.ExpectCommit()
+
+		repo := NewUserRepository(db)
+		var users []model.User
+		err = repo.ExportUsers(context.Background(), model.UserFilter{UpdatedSince: since}, func(user model.User) error {
+			users = append(users, user)
+			return nil
+		})
+
+		assert.NoError(t, err)
+		updatedBy := 1
+		assert.Equal(t, []model.User{
+			{ID: 1, Name: "User 1", Email: "user1@example.com", Role: model.RoleAdmin, CreatedAt: createdAt, UpdatedAt: createdAt},
+			{ID: 2, Name: "User 2", Email: "user2@example.com", Role: model.RoleCustomer, CreatedAt: createdAt, UpdatedAt: createdAt.Add(time.Hour), UpdatedBy: &updatedBy},
+		}, users)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Database Error", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(`DECLARE export_cursor`).WillReturnError(errors.New("connection failed"))
+		mock.ExpectRollback()
+
+		repo := NewUserRepository(db)
+		err = repo.ExportUsers(context.Background(), model.UserFilter{}, func(model.User) error { return nil })
+
+		assert.EqualError(t, err, "connection failed")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestUserRepository_Trash(t *testing.T) {
+	t.Run("Get Deleted Users", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		deletedAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+		rows := sqlmock.NewRows(append(userRowColumns, "deleted_at")).
+			AddRow(1, "User 1", "user1@example.com", model.RoleCustomer, nil, "", deletedAt, deletedAt, nil, 7, deletedAt)
+
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT id, name, email, role, email_verified_at, COALESCE(pending_email, ''), created_at, updated_at, created_by, updated_by, deleted_at FROM users WHERE deleted_at IS NOT NULL ORDER BY deleted_at DESC, id")).
+			WillReturnRows(rows)
+
+		repo := NewUserRepository(db)
+		users, err := repo.GetDeletedUsers()
+
+		assert.NoError(t, err)
+		assert.Len(t, users, 1)
+		assert.Equal(t, deletedAt, *users[0].DeletedAt)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Get Deleted User By Email Not Found", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectQuery(regexp.QuoteMeta("FROM users WHERE deleted_at IS NOT NULL AND email = $1 ORDER BY deleted_at DESC LIMIT 1")).
+			WithArgs("user@example.com").
+			WillReturnError(sql.ErrNoRows)
+
+		repo := NewUserRepository(db)
+		user, err := repo.GetDeletedUserByEmail("user@example.com")
+
+		assert.NoError(t, err)
+		assert.Nil(t, user)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Restore And Purge Only Touch The Trash", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		restore := model.AuditEvent{Action: model.AuditActionRestore, EntityType: model.AuditEntityUser, EntityID: "1"}
+		purge := model.AuditEvent{Action: model.AuditActionPurge, EntityType: model.AuditEntityUser, EntityID: "2"}
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE users SET deleted_at = NULL, updated_at = NOW(), updated_by = $2 WHERE id = $1 AND deleted_at IS NOT NULL")).
+			WithArgs(1, nil).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		expectAuditEvent(mock, "", restore)
+		mock.ExpectCommit()
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("DELETE FROM users WHERE id = $1 AND deleted_at IS NOT NULL")).
+			WithArgs(2).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		expectAuditEvent(mock, "abc123", purge)
+		mock.ExpectCommit()
+
+		repo := NewUserRepository(db)
+		assert.NoError(t, repo.RestoreUser(1, restore))
+		assert.NoError(t, repo.PurgeUser(2, purge))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestUserRepository_QueueVerificationEmail(t *testing.T) {
+	email := model.OutboxEmail{Recipient: "ana@example.com", Subject: "Confirme seu email", Body: "link"}
+	query := regexp.QuoteMeta("UPDATE users SET verification_sent_at = NOW() WHERE id = $1 AND deleted_at IS NULL AND (verification_sent_at IS NULL OR verification_sent_at <= NOW() - make_interval(secs => $2))")
+
+	t.Run("Enqueues The Email", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(query).WithArgs(7, float64(60)).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO email_outbox (recipient, subject, body) VALUES ($1, $2, $3)")).
+			WithArgs(email.Recipient, email.Subject, email.Body).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		sent, err := NewUserRepository(db).QueueVerificationEmail(7, time.Minute, email)
+
+		assert.NoError(t, err)
+		assert.True(t, sent)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Sent Too Recently", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(query).WithArgs(7, float64(60)).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		sent, err := NewUserRepository(db).QueueVerificationEmail(7, time.Minute, email)
+
+		assert.NoError(t, err)
+		assert.False(t, sent)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestUserRepository_QueueSignupNotice(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	email := model.OutboxEmail{Recipient: "ana@example.com", Subject: "Tentativa de cadastro com seu email", Body: "aviso"}
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE users SET signup_notice_sent_at = NOW() WHERE id = $1 AND deleted_at IS NULL AND (signup_notice_sent_at IS NULL OR signup_notice_sent_at <= NOW() - make_interval(secs => $2))")).
+		WithArgs(7, float64(3600)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO email_outbox (recipient, subject, body) VALUES ($1, $2, $3)")).
+		WithArgs(email.Recipient, email.Subject, email.Body).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	sent, err := NewUserRepository(db).QueueSignupNotice(7, time.Hour, email)
+
+	assert.NoError(t, err)
+	assert.True(t, sent)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserRepository_MarkEmailVerified(t *testing.T) {
+	t.Run("Email Changed Since The Link", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE users SET email_verified_at = NOW(), updated_at = NOW(), updated_by = $3 WHERE id = $1 AND email = $2 AND deleted_at IS NULL")).
+			WithArgs(7, "old@example.com", nil).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		err = NewUserRepository(db).MarkEmailVerified(7, "old@example.com", model.AuditEvent{})
+
+		assert.Equal(t, sql.ErrNoRows, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestUserRepository_ConfirmEmailChange(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		event := model.AuditEvent{Action: model.AuditActionUpdate, EntityType: model.AuditEntityUser, EntityID: "7"}
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE users SET email = pending_email, pending_email = NULL, email_verified_at = NOW(), verification_sent_at = NULL, updated_at = NOW(), updated_by = $3 WHERE id = $1 AND pending_email = $2 AND deleted_at IS NULL")).
+			WithArgs(7, "new@example.com", nil).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		expectAuditEvent(mock, "", event)
+		mock.ExpectCommit()
+
+		err = NewUserRepository(db).ConfirmEmailChange(7, "new@example.com", event)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestUserRepository_UpdatePasswordHash(t *testing.T) {
+	query := regexp.QuoteMeta("UPDATE users SET password = $3 WHERE id = $1 AND password = $2")
+
+	t.Run("Success", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectExec(query).WithArgs(7, "$2a$10$old", "$argon2id$new").WillReturnResult(sqlmock.NewResult(0, 1))
+
+		repo := NewUserRepository(db)
+		err = repo.UpdatePasswordHash(7, "$2a$10$old", "$argon2id$new")
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Password Changed In The Meantime", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectExec(query).WithArgs(7, "$2a$10$old", "$argon2id$new").WillReturnResult(sqlmock.NewResult(0, 0))
+
+		repo := NewUserRepository(db)
+		err = repo.UpdatePasswordHash(7, "$2a$10$old", "$argon2id$new")
+
+		assert.Equal(t, sql.ErrNoRows, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
 }
@@ -0,0 +1,299 @@
+package repository
+
+import (
+	"database/sql"
+	"go-api/model"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// VariantRepositoryInterface defines the contract for option types and product variants
+type VariantRepositoryInterface interface {
+	GetOptionTypes() ([]model.OptionType, error)
+	GetOptionTypeByID(id int) (*model.OptionType, error)
+	GetOptionTypeByName(name string) (*model.OptionType, error)
+	CreateOptionType(optionType model.OptionType) (int, error)
+	CreateOptionValue(value model.OptionValue) (int, error)
+	GetOptionValuesByIDs(ids []int) ([]model.VariantOption, error)
+	GetVariants(productIDs []int) ([]model.Variant, error)
+	GetVariantByID(id int) (*model.Variant, error)
+	GetVariantBySKU(sku string) (*model.Variant, error)
+	CreateVariant(variant model.Variant) (int, error)
+	UpdateVariant(variant model.Variant) error
+	DeleteVariant(id int) error
+}
+
+type VariantRepository struct {
+	connection *sql.DB
+}
+
+// Ensure VariantRepository implements VariantRepositoryInterface
+var _ VariantRepositoryInterface = (*VariantRepository)(nil)
+
+func NewVariantRepository(connection *sql.DB) VariantRepositoryInterface {
+	return &VariantRepository{
+		connection: connection,
+	}
+}
+
+// queryOptionTypes loads option types with their values, ordered by name and position
+func (vr *VariantRepository) queryOptionTypes(condition string, args ...interface{}) ([]model.OptionType, error) {
+	rows, err := vr.connection.Query(`SELECT ot.id, ot.name, ov.id, ov.value, ov.position
+		FROM option_types ot
+		LEFT JOIN option_values ov ON ov.option_type_id = ot.id
+		`+condition+`
+		ORDER BY ot.name, ov.position, ov.id`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var optionTypes []model.OptionType
+	for rows.Next() {
+		var typeID int
+		var name string
+		var valueID, position sql.NullInt64
+		var value sql.NullString
+		if err := rows.Scan(&typeID, &name, &valueID, &value, &position); err != nil {
+			return nil, err
+		}
+		if len(optionTypes) == 0 || optionTypes[len(optionTypes)-1].ID != typeID {
+			optionTypes = append(optionTypes, model.OptionType{ID: typeID, Name: name, Values: []model.OptionValue{}})
+		}
+		if valueID.Valid {
+			current := &optionTypes[len(optionTypes)-1]
+			current.Values = append(current.Values, model.OptionValue{
+				ID:           int(valueID.Int64),
+				OptionTypeID: typeID,
+				Value:        value.String,
+				Position:     int(position.Int64),
+			})
+		}
+	}
+	return optionTypes, rows.Err()
+}
+
+func (vr *VariantRepository) queryOptionType(condition string, args ...interface{}) (*model.OptionType, error) {
+	optionTypes, err := vr.queryOptionTypes(condition, args...)
+	if err != nil {
+		return nil, err
+	}
+	if len(optionTypes) == 0 {
+		return nil, nil
+	}
+	return &optionTypes[0], nil
+}
+
+func (vr *VariantRepository) GetOptionTypes() ([]model.OptionType, error) {
+	return vr.queryOptionTypes("")
+}
+
+func (vr *VariantRepository) GetOptionTypeByID(id int) (*model.OptionType, error) {
+	return vr.queryOptionType("WHERE ot.id = $1", id)
+}
+
+func (vr *VariantRepository) GetOptionTypeByName(name string) (*model.OptionType, error) {
+	return vr.queryOptionType("WHERE ot.name = $1", name)
+}
+
+// CreateOptionType inserts the option type together with its values
+func (vr *VariantRepository) CreateOptionType(optionType model.OptionType) (int, error) {
+	tx, err := vr.connection.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var id int
+	if err := tx.QueryRow(`INSERT INTO option_types (name) VALUES ($1) RETURNING id`, optionType.Name).Scan(&id); err != nil {
+		return 0, err
+	}
+	for _, value := range optionType.Values {
+		_, err := tx.Exec(`INSERT INTO option_values (option_type_id, value, position) VALUES ($1, $2, $3)`,
+			id, value.Value, value.Position)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+func (vr *VariantRepository) CreateOptionValue(value model.OptionValue) (int, error) {
+	var id int
+	err := vr.connection.QueryRow(`INSERT INTO option_values (option_type_id, value, position) VALUES ($1, $2, $3) RETURNING id`,
+		value.OptionTypeID, value.Value, value.Position).Scan(&id)
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// GetOptionValuesByIDs resolves option value IDs into the option type and value they name
+func (vr *VariantRepository) GetOptionValuesByIDs(ids []int) ([]model.VariantOption, error) {
+	rows, err := vr.connection.Query(`SELECT ot.id, ot.name, ov.id, ov.value
+		FROM option_values ov
+		JOIN option_types ot ON ot.id = ov.option_type_id
+		WHERE ov.id = ANY($1)
+		ORDER BY ot.name`, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var options []model.VariantOption
+	for rows.Next() {
+		var option model.VariantOption
+		if err := rows.Scan(&option.OptionTypeID, &option.OptionType, &option.OptionValueID, &option.Value); err != nil {
+			return nil, err
+		}
+		options = append(options, option)
+	}
+	return options, rows.Err()
+}
+
+// selectVariants returns one row per variant option; variants without options
+// still produce a row thanks to the left joins
+const selectVariants = `SELECT v.id, v.product_id, v.sku, v.price_override, p.currency, v.stock, ot.id, ot.name, ov.id, ov.value
+	FROM product_variants v
+	JOIN products p ON p.id = v.product_id
+	LEFT JOIN variant_option_values vov ON vov.variant_id = v.id
+	LEFT JOIN option_values ov ON ov.id = vov.option_value_id
+	LEFT JOIN option_types ot ON ot.id = ov.option_type_id`
+
+func (vr *VariantRepository) queryVariants(condition string, args ...interface{}) ([]model.Variant, error) {
+	rows, err := vr.connection.Query(selectVariants+" "+condition+" ORDER BY v.product_id, v.id, ot.name", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var variants []model.Variant
+	for rows.Next() {
+		var variant model.Variant
+		var priceOverride sql.NullString
+		var currency string
+		var typeID, valueID sql.NullInt64
+		var typeName, value sql.NullString
+		err := rows.Scan(&variant.ID, &variant.ProductID, &variant.SKU, &priceOverride, &currency, &variant.Stock,
+			&typeID, &typeName, &valueID, &value)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(variants) == 0 || variants[len(variants)-1].ID != variant.ID {
+			if priceOverride.Valid {
+				override, err := model.ParseMoney(priceOverride.String, currency)
+				if err != nil {
+					return nil, err
+				}
+				variant.PriceOverride = &override
+			}
+			variant.Options = []model.VariantOption{}
+			variants = append(variants, variant)
+		}
+		if valueID.Valid {
+			current := &variants[len(variants)-1]
+			current.Options = append(current.Options, model.VariantOption{
+				OptionTypeID:  int(typeID.Int64),
+				OptionType:    typeName.String,
+				OptionValueID: int(valueID.Int64),
+				Value:         value.String,
+			})
+		}
+	}
+	return variants, rows.Err()
+}
+
+func (vr *VariantRepository) queryVariant(condition string, args ...interface{}) (*model.Variant, error) {
+	variants, err := vr.queryVariants(condition, args...)
+	if err != nil {
+		return nil, err
+	}
+	if len(variants) == 0 {
+		return nil, nil
+	}
+	return &variants[0], nil
+}
+
+// GetVariants returns the variants of all the given products in one query
+func (vr *VariantRepository) GetVariants(productIDs []int) ([]model.Variant, error) {
+	return vr.queryVariants("WHERE v.product_id = ANY($1)", pq.Array(productIDs))
+}
+
+func (vr *VariantRepository) GetVariantByID(id int) (*model.Variant, error) {
+	return vr.queryVariant("WHERE v.id = $1", id)
+}
+
+func (vr *VariantRepository) GetVariantBySKU(sku string) (*model.Variant, error) {
+	return vr.queryVariant("WHERE v.sku = $1", sku)
+}
+
+// optionsKey identifies the combination of option values of a variant; it is
+// unique per product so the same combination cannot be created twice
+func optionsKey(options []model.VariantOption) string {
+	ids := make([]int, 0, len(options))
+	for _, option := range options {
+		ids = append(ids, option.OptionValueID)
+	}
+	sort.Ints(ids)
+
+	parts := make([]string, 0, len(ids))
+	for _, id := range ids {
+		parts = append(parts, strconv.Itoa(id))
+	}
+	return strings.Join(parts, ",")
+}
+
+func priceOverrideValue(variant model.Variant) sql.NullString {
+	if variant.PriceOverride == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: variant.PriceOverride.String(), Valid: true}
+}
+
+// CreateVariant inserts the variant and its option values in a single transaction
+func (vr *VariantRepository) CreateVariant(variant model.Variant) (int, error) {
+	tx, err := vr.connection.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var id int
+	err = tx.QueryRow(`INSERT INTO product_variants (product_id, sku, price_override, stock, options_key)
+		VALUES ($1, $2, $3, $4, $5) RETURNING id`,
+		variant.ProductID, variant.SKU, priceOverrideValue(variant), variant.Stock, optionsKey(variant.Options)).Scan(&id)
+	if err != nil {
+		return 0, err
+	}
+	for _, option := range variant.Options {
+		_, err := tx.Exec(`INSERT INTO variant_option_values (variant_id, option_value_id) VALUES ($1, $2)`, id, option.OptionValueID)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// UpdateVariant saves the SKU, price override and stock; the options of a variant never change
+func (vr *VariantRepository) UpdateVariant(variant model.Variant) error {
+	_, err := vr.connection.Exec(`UPDATE product_variants SET sku = $1, price_override = $2, stock = $3 WHERE id = $4`,
+		variant.SKU, priceOverrideValue(variant), variant.Stock, variant.ID)
+	return err
+}
+
+func (vr *VariantRepository) DeleteVariant(id int) error {
+	_, err := vr.connection.Exec(`DELETE FROM product_variants WHERE id = $1`, id)
+	return err
+}
@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"go-api/model"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+var variantRowColumns = []string{"id", "product_id", "sku", "price_override", "currency", "stock", "type_id", "type_name", "value_id", "value"}
+
+func TestVariantRepository_GetOptionTypes(t *testing.T) {
+	t.Run("Groups Values By Type", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		rows := sqlmock.NewRows([]string{"id", "name", "value_id", "value", "position"}).
+			AddRow(2, "color", 21, "Azul", 0).
+			AddRow(2, "color", 22, "Preto", 1).
+			AddRow(3, "material", nil, nil, nil).
+			AddRow(1, "size", 11, "M", 0)
+		mock.ExpectQuery(`FROM option_types ot LEFT JOIN option_values ov ON ov.option_type_id = ot.id ORDER BY ot.name, ov.position, ov.id`).
+			WillReturnRows(rows)
+
+		repo := NewVariantRepository(db)
+		optionTypes, err := repo.GetOptionTypes()
+
+		assert.NoError(t, err)
+		assert.Len(t, optionTypes, 3)
+		assert.Len(t, optionTypes[0].Values, 2)
+		assert.Empty(t, optionTypes[1].Values)
+		assert.Equal(t, "M", optionTypes[2].Values[0].Value)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestVariantRepository_GetVariants(t *testing.T) {
+	t.Run("Groups Options By Variant", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		rows := sqlmock.NewRows(variantRowColumns).
+			AddRow(1, 1, "CAM-AZUL-M", nil, "BRL", 3, 2, "color", 21, "Azul").
+			AddRow(1, 1, "CAM-AZUL-M", nil, "BRL", 3, 1, "size", 11, "M").
+			AddRow(2, 1, "CAM-AZUL-G", "54.900", "BRL", 0, 2, "color", 21, "Azul").
+			AddRow(2, 1, "CAM-AZUL-G", "54.900", "BRL", 0, 1, "size", 12, "G")
+		mock.ExpectQuery(`FROM product_variants v .* WHERE v.product_id = ANY\(\$1\) ORDER BY v.product_id, v.id, ot.name`).
+			WithArgs(pq.Array([]int{1})).
+			WillReturnRows(rows)
+
+		repo := NewVariantRepository(db)
+		variants, err := repo.GetVariants([]int{1})
+
+		assert.NoError(t, err)
+		assert.Len(t, variants, 2)
+		assert.Nil(t, variants[0].PriceOverride)
+		assert.Len(t, variants[0].Options, 2)
+		assert.Equal(t, model.Money{Amount: 5490, Currency: "BRL"}, *variants[1].PriceOverride)
+		assert.Equal(t, "G", variants[1].Options[1].Value)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestVariantRepository_GetVariantBySKU(t *testing.T) {
+	t.Run("Not Found", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectQuery(`FROM product_variants v .* WHERE v.sku = \$1`).
+			WithArgs("MISSING").
+			WillReturnRows(sqlmock.NewRows(variantRowColumns))
+
+		repo := NewVariantRepository(db)
+		variant, err := repo.GetVariantBySKU("MISSING")
+
+		assert.NoError(t, err)
+		assert.Nil(t, variant)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestVariantRepository_CreateVariant(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery("INSERT INTO product_variants").
+			WithArgs(1, "CAM-AZUL-G", "54.90", 5, "12,21").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+		mock.ExpectExec("INSERT INTO variant_option_values").
+			WithArgs(2, 21).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("INSERT INTO variant_option_values").
+			WithArgs(2, 12).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		override := model.Money{Amount: 5490, Currency: "BRL"}
+		repo := NewVariantRepository(db)
+		id, err := repo.CreateVariant(model.Variant{
+			ProductID:     1,
+			SKU:           "CAM-AZUL-G",
+			PriceOverride: &override,
+			Stock:         5,
+			Options: []model.VariantOption{
+				{OptionTypeID: 2, OptionValueID: 21},
+				{OptionTypeID: 1, OptionValueID: 12},
+			},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, id)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestVariantRepository_UpdateVariant(t *testing.T) {
+	t.Run("Clears Price Override", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectExec(`UPDATE product_variants SET sku = \$1, price_override = \$2, stock = \$3 WHERE id = \$4`).
+			WithArgs("CAM-AZUL-G", nil, 2, 2).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		repo := NewVariantRepository(db)
+		err = repo.UpdateVariant(model.Variant{ID: 2, SKU: "CAM-AZUL-G", Stock: 2})
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,321 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"go-api/dto"
+	"go-api/model"
+	"go-api/repository"
+	"log"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrAPIKeyNotFound       = errors.New("api key not found")
+	ErrInvalidAPIKeyScope   = errors.New("invalid api key scope")
+	ErrInvalidAllowedIP     = errors.New("invalid ip or cidr range in allowed_ips")
+	ErrInvalidAPIKeyExpiry  = errors.New("expires_at must be in the future")
+	ErrInvalidAPIKeyOwner   = errors.New("api keys belong to admins or service accounts")
+	ErrAPIKeyAlreadyRevoked = errors.New("api key already revoked")
+)
+
+// apiKeyIDSize is the random bytes of the visible part of a key, written as
+// 12 hex characters after model.APIKeyPrefix
+const apiKeyIDSize = 6
+
+// apiKeyOwnerRoles are the roles whose keys open any route: the routes that
+// take API keys are admin routes
+var apiKeyOwnerRoles = []string{model.RoleAdmin, model.RoleService}
+
+// APIKeyUsecase defines the contract for the API keys and their use
+type APIKeyUsecase interface {
+	CreateAPIKey(ctx context.Context, request dto.CreateAPIKeyRequest) (*dto.APIKeySecretResponse, error)
+	GetAPIKeys(userID int) ([]dto.APIKeyResponse, error)
+	RotateAPIKey(ctx context.Context, id int) (*dto.APIKeySecretResponse, error)
+	RevokeAPIKey(ctx context.Context, id int) error
+	AuthenticateAPIKey(ctx context.Context, key, ip string) (*model.APIKeyPrincipal, error)
+}
+
+type apiKeyUsecaseImpl struct {
+	repository     repository.APIKeyRepositoryInterface
+	userRepository repository.UserRepositoryInterface
+}
+
+// NewAPIKeyUsecase creates a new instance of APIKeyUsecase
+func NewAPIKeyUsecase(repo repository.APIKeyRepositoryInterface, userRepo repository.UserRepositoryInterface) APIKeyUsecase {
+	return &apiKeyUsecaseImpl{
+		repository:     repo,
+		userRepository: userRepo,
+	}
+}
+
+// CreateAPIKey creates a key for an admin or a service account and returns
+// it; only its prefix and hash are kept, so it is not shown again
+func (au *apiKeyUsecaseImpl) CreateAPIKey(ctx context.Context, request dto.CreateAPIKeyRequest) (*dto.APIKeySecretResponse, error) {
+	if err := validateAPIKeyRequest(request); err != nil {
+		return nil, err
+	}
+	user, err := au.userRepository.GetUserByID(request.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+	if !slices.Contains(apiKeyOwnerRoles, user.Role) {
+		return nil, ErrInvalidAPIKeyOwner
+	}
+
+	secret, prefix, err := newAPIKey()
+	if err != nil {
+		return nil, err
+	}
+	key := model.APIKey{
+		UserID:     request.UserID,
+		Name:       strings.TrimSpace(request.Name),
+		Prefix:     prefix,
+		KeyHash:    hashSecretToken(secret),
+		Scopes:     slices.Compact(slices.Sorted(slices.Values(request.Scopes))),
+		AllowedIPs: request.AllowedIPs,
+		ExpiresAt:  request.ExpiresAt,
+	}
+	if key.AllowedIPs == nil {
+		key.AllowedIPs = []string{}
+	}
+	event, err := newAuditEvent(ctx, model.AuditActionCreate, model.AuditEntityAPIKey, "", nil, key)
+	if err != nil {
+		return nil, err
+	}
+	id, err := au.repository.CreateAPIKey(key, event)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := au.repository.GetAPIKeyByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if created == nil {
+		return nil, ErrAPIKeyNotFound
+	}
+	return &dto.APIKeySecretResponse{Key: secret, APIKey: toAPIKeyResponse(*created)}, nil
+}
+
+// GetAPIKeys lists the keys of a user, or of every user when userID is 0
+func (au *apiKeyUsecaseImpl) GetAPIKeys(userID int) ([]dto.APIKeyResponse, error) {
+	keys, err := au.repository.GetAPIKeys(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.APIKeyResponse, 0, len(keys))
+	for _, key := range keys {
+		responses = append(responses, toAPIKeyResponse(key))
+	}
+	return responses, nil
+}
+
+// RotateAPIKey gives a key a new secret, with the same name, scopes,
+// allowlist and expiry; the old secret stops working at once
+func (au *apiKeyUsecaseImpl) RotateAPIKey(ctx context.Context, id int) (*dto.APIKeySecretResponse, error) {
+	key, err := au.activeKey(id)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, prefix, err := newAPIKey()
+	if err != nil {
+		return nil, err
+	}
+	event, err := newAuditEvent(ctx, model.AuditActionRotate, model.AuditEntityAPIKey, strconv.Itoa(id),
+		map[string]string{"prefix": key.Prefix}, map[string]string{"prefix": prefix})
+	if err != nil {
+		return nil, err
+	}
+	if err := au.repository.RotateAPIKey(id, prefix, hashSecretToken(secret), event); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrAPIKeyAlreadyRevoked
+		}
+		return nil, err
+	}
+
+	rotated, err := au.repository.GetAPIKeyByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if rotated == nil {
+		return nil, ErrAPIKeyNotFound
+	}
+	return &dto.APIKeySecretResponse{Key: secret, APIKey: toAPIKeyResponse(*rotated)}, nil
+}
+
+// RevokeAPIKey turns a key off for good
+func (au *apiKeyUsecaseImpl) RevokeAPIKey(ctx context.Context, id int) error {
+	if _, err := au.activeKey(id); err != nil {
+		return err
+	}
+
+	event, err := newAuditEvent(ctx, model.AuditActionRevoke, model.AuditEntityAPIKey, strconv.Itoa(id), nil, nil)
+	if err != nil {
+		return err
+	}
+	if err := au.repository.RevokeAPIKey(id, event); err != nil {
+		if err == sql.ErrNoRows {
+			return ErrAPIKeyAlreadyRevoked
+		}
+		return err
+	}
+	return nil
+}
+
+// AuthenticateAPIKey returns who key authenticates when used from ip, or
+// nil when it is unknown, wrong, revoked, expired, used from outside its
+// allowlist or its owner is gone. The reason is not told to the caller
+func (au *apiKeyUsecaseImpl) AuthenticateAPIKey(ctx context.Context, key, ip string) (*model.APIKeyPrincipal, error) {
+	prefix, ok := apiKeyPrefix(key)
+	if !ok {
+		return nil, nil
+	}
+	stored, err := au.repository.GetAPIKeyByPrefix(prefix)
+	if err != nil {
+		return nil, err
+	}
+	if stored == nil || subtle.ConstantTimeCompare([]byte(hashSecretToken(key)), []byte(stored.KeyHash)) != 1 {
+		return nil, nil
+	}
+	if stored.RevokedAt != nil || (stored.ExpiresAt != nil && !stored.ExpiresAt.After(time.Now())) {
+		return nil, nil
+	}
+	if !ipAllowed(stored.AllowedIPs, ip) {
+		return nil, nil
+	}
+
+	user, err := au.userRepository.GetUserByID(stored.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, nil
+	}
+
+	// Tracking the use is not worth failing the request over
+	if err := au.repository.TouchAPIKey(stored.ID, ip); err != nil {
+		log.Printf("last use of api key %d: %v", stored.ID, err)
+	}
+
+	return &model.APIKeyPrincipal{
+		KeyID:  stored.ID,
+		UserID: user.ID,
+		Email:  user.Email,
+		Role:   user.Role,
+		Scopes: stored.Scopes,
+	}, nil
+}
+
+// --- Helper Functions ---
+
+// activeKey returns the key with the ID, failing when it is missing or
+// already revoked
+func (au *apiKeyUsecaseImpl) activeKey(id int) (*model.APIKey, error) {
+	key, err := au.repository.GetAPIKeyByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if key == nil {
+		return nil, ErrAPIKeyNotFound
+	}
+	if key.RevokedAt != nil {
+		return nil, ErrAPIKeyAlreadyRevoked
+	}
+	return key, nil
+}
+
+func validateAPIKeyRequest(request dto.CreateAPIKeyRequest) error {
+	for _, scope := range request.Scopes {
+		if !slices.Contains(model.APIKeyScopes, scope) {
+			return fmt.Errorf("%w: %q", ErrInvalidAPIKeyScope, scope)
+		}
+	}
+	for _, entry := range request.AllowedIPs {
+		if _, _, err := net.ParseCIDR(entry); err != nil && net.ParseIP(entry) == nil {
+			return fmt.Errorf("%w: %q", ErrInvalidAllowedIP, entry)
+		}
+	}
+	if request.ExpiresAt != nil && !request.ExpiresAt.After(time.Now()) {
+		return ErrInvalidAPIKeyExpiry
+	}
+	return nil
+}
+
+// newAPIKey returns a new key, gak_<12 hex>_<43 base64url>, and its visible
+// prefix, gak_<12 hex>
+func newAPIKey() (string, string, error) {
+	id := make([]byte, apiKeyIDSize)
+	if _, err := rand.Read(id); err != nil {
+		return "", "", err
+	}
+	secret, err := newSecretToken()
+	if err != nil {
+		return "", "", err
+	}
+	prefix := model.APIKeyPrefix + hex.EncodeToString(id)
+	return prefix + "_" + secret, prefix, nil
+}
+
+// apiKeyPrefix returns the visible prefix of a key shaped like newAPIKey's
+func apiKeyPrefix(key string) (string, bool) {
+	n := len(model.APIKeyPrefix) + 2*apiKeyIDSize
+	if len(key) <= n+1 || !strings.HasPrefix(key, model.APIKeyPrefix) || key[n] != '_' {
+		return "", false
+	}
+	return key[:n], true
+}
+
+// ipAllowed tells whether ip matches an entry of the allowlist, an IP or a
+// CIDR range; an empty allowlist accepts any IP
+func ipAllowed(allowlist []string, ip string) bool {
+	if len(allowlist) == 0 {
+		return true
+	}
+	addr := net.ParseIP(ip)
+	if addr == nil {
+		return false
+	}
+	for _, entry := range allowlist {
+		if _, network, err := net.ParseCIDR(entry); err == nil {
+			if network.Contains(addr) {
+				return true
+			}
+		} else if allowed := net.ParseIP(entry); allowed != nil && allowed.Equal(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+func toAPIKeyResponse(key model.APIKey) dto.APIKeyResponse {
+	return dto.APIKeyResponse{
+		ID:         key.ID,
+		UserID:     key.UserID,
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		Scopes:     key.Scopes,
+		AllowedIPs: key.AllowedIPs,
+		ExpiresAt:  key.ExpiresAt,
+		LastUsedAt: key.LastUsedAt,
+		LastUsedIP: key.LastUsedIP,
+		CreatedAt:  key.CreatedAt,
+		CreatedBy:  key.CreatedBy,
+		RotatedAt:  key.RotatedAt,
+		RevokedAt:  key.RevokedAt,
+	}
+}
@@ -0,0 +1,225 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"go-api/dto"
+	"go-api/model"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIKeyUsecase_CreateAPIKey(t *testing.T) {
+	userRepo := &MockUserRepository{
+		GetUserByIDFunc: func(id int) (*model.User, error) {
+			if id == 8 {
+				return &model.User{ID: id, Role: model.RoleCustomer}, nil
+			}
+			return &model.User{ID: id, Email: "armazem@example.com", Role: model.RoleService}, nil
+		},
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		var stored model.APIKey
+		repo := &MockAPIKeyRepository{
+			CreateAPIKeyFunc: func(key model.APIKey, event model.AuditEvent) (int, error) {
+				assert.Equal(t, model.AuditActionCreate, event.Action)
+				assert.Equal(t, model.AuditEntityAPIKey, event.EntityType)
+				stored = key
+				return 4, nil
+			},
+			GetAPIKeyByIDFunc: func(id int) (*model.APIKey, error) {
+				stored.ID = id
+				return &stored, nil
+			},
+		}
+
+		resp, err := NewAPIKeyUsecase(repo, userRepo).CreateAPIKey(context.Background(), dto.CreateAPIKeyRequest{
+			UserID: 9,
+			Name:   " Armazém ",
+			Scopes: []string{model.ScopeProductsWrite, model.ScopeProductsRead, model.ScopeProductsWrite},
+		})
+
+		assert.NoError(t, err)
+		assert.Regexp(t, `^gak_[0-9a-f]{12}_[A-Za-z0-9_-]{43}$`, resp.Key)
+		assert.Equal(t, resp.Key[:16], stored.Prefix)
+		assert.Equal(t, hashSecretToken(resp.Key), stored.KeyHash)
+		assert.Equal(t, "Armazém", stored.Name)
+		assert.Equal(t, []string{model.ScopeProductsRead, model.ScopeProductsWrite}, stored.Scopes)
+		assert.Equal(t, []string{}, stored.AllowedIPs)
+		assert.Equal(t, 4, resp.APIKey.ID)
+		assert.Equal(t, stored.Prefix, resp.APIKey.Prefix)
+	})
+
+	invalid := []struct {
+		name    string
+		request dto.CreateAPIKeyRequest
+		err     error
+	}{
+		{"Unknown Scope", dto.CreateAPIKeyRequest{UserID: 9, Name: "x", Scopes: []string{"users:write"}}, ErrInvalidAPIKeyScope},
+		{"Bad Allowed IP", dto.CreateAPIKeyRequest{UserID: 9, Name: "x", Scopes: []string{model.ScopeOrdersRead}, AllowedIPs: []string{"10.0.0.0/33"}}, ErrInvalidAllowedIP},
+		{"Expiry In The Past", dto.CreateAPIKeyRequest{UserID: 9, Name: "x", Scopes: []string{model.ScopeOrdersRead}, ExpiresAt: ptrTime(time.Now().Add(-time.Hour))}, ErrInvalidAPIKeyExpiry},
+		{"Customer Owner", dto.CreateAPIKeyRequest{UserID: 8, Name: "x", Scopes: []string{model.ScopeOrdersRead}}, ErrInvalidAPIKeyOwner},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &MockAPIKeyRepository{
+				CreateAPIKeyFunc: func(key model.APIKey, event model.AuditEvent) (int, error) {
+					t.Fatal("invalid key created")
+					return 0, nil
+				},
+			}
+
+			_, err := NewAPIKeyUsecase(repo, userRepo).CreateAPIKey(context.Background(), tt.request)
+
+			assert.True(t, errors.Is(err, tt.err), err)
+		})
+	}
+}
+
+func TestAPIKeyUsecase_RotateAndRevoke(t *testing.T) {
+	active := func(id int) (*model.APIKey, error) {
+		return &model.APIKey{ID: id, Prefix: "gak_000000000000"}, nil
+	}
+
+	t.Run("Rotate Returns A New Key", func(t *testing.T) {
+		var prefix, keyHash string
+		repo := &MockAPIKeyRepository{
+			GetAPIKeyByIDFunc: active,
+			RotateAPIKeyFunc: func(id int, newPrefix, newHash string, event model.AuditEvent) error {
+				assert.Equal(t, model.AuditActionRotate, event.Action)
+				assert.Equal(t, "4", event.EntityID)
+				prefix, keyHash = newPrefix, newHash
+				return nil
+			},
+		}
+
+		resp, err := NewAPIKeyUsecase(repo, &MockUserRepository{}).RotateAPIKey(context.Background(), 4)
+
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(resp.Key, prefix+"_"))
+		assert.Equal(t, hashSecretToken(resp.Key), keyHash)
+	})
+
+	t.Run("Revoked Keys Are Not Rotated", func(t *testing.T) {
+		revokedAt := time.Now()
+		repo := &MockAPIKeyRepository{
+			GetAPIKeyByIDFunc: func(id int) (*model.APIKey, error) {
+				return &model.APIKey{ID: id, RevokedAt: &revokedAt}, nil
+			},
+		}
+
+		_, err := NewAPIKeyUsecase(repo, &MockUserRepository{}).RotateAPIKey(context.Background(), 4)
+
+		assert.True(t, errors.Is(err, ErrAPIKeyAlreadyRevoked))
+	})
+
+	t.Run("Revoke", func(t *testing.T) {
+		revoked := false
+		repo := &MockAPIKeyRepository{
+			GetAPIKeyByIDFunc: active,
+			RevokeAPIKeyFunc: func(id int, event model.AuditEvent) error {
+				assert.Equal(t, model.AuditActionRevoke, event.Action)
+				revoked = true
+				return nil
+			},
+		}
+
+		err := NewAPIKeyUsecase(repo, &MockUserRepository{}).RevokeAPIKey(context.Background(), 4)
+
+		assert.NoError(t, err)
+		assert.True(t, revoked)
+	})
+
+	t.Run("Revoke Unknown Key", func(t *testing.T) {
+		err := NewAPIKeyUsecase(&MockAPIKeyRepository{}, &MockUserRepository{}).RevokeAPIKey(context.Background(), 4)
+
+		assert.True(t, errors.Is(err, ErrAPIKeyNotFound))
+	})
+
+	t.Run("Revoke Race", func(t *testing.T) {
+		repo := &MockAPIKeyRepository{
+			GetAPIKeyByIDFunc: active,
+			RevokeAPIKeyFunc: func(id int, event model.AuditEvent) error {
+				return sql.ErrNoRows
+			},
+		}
+
+		err := NewAPIKeyUsecase(repo, &MockUserRepository{}).RevokeAPIKey(context.Background(), 4)
+
+		assert.True(t, errors.Is(err, ErrAPIKeyAlreadyRevoked))
+	})
+}
+
+func TestAPIKeyUsecase_AuthenticateAPIKey(t *testing.T) {
+	secret, prefix, err := newAPIKey()
+	assert.NoError(t, err)
+	userRepo := &MockUserRepository{
+		GetUserByIDFunc: func(id int) (*model.User, error) {
+			return &model.User{ID: id, Email: "armazem@example.com", Role: model.RoleService}, nil
+		},
+	}
+	keyWith := func(change func(*model.APIKey)) *MockAPIKeyRepository {
+		key := &model.APIKey{ID: 4, UserID: 9, Prefix: prefix, KeyHash: hashSecretToken(secret), Scopes: []string{model.ScopeProductsRead}}
+		change(key)
+		return &MockAPIKeyRepository{
+			GetAPIKeyByPrefixFunc: func(p string) (*model.APIKey, error) {
+				if p != prefix {
+					return nil, nil
+				}
+				return key, nil
+			},
+		}
+	}
+
+	t.Run("Valid Key", func(t *testing.T) {
+		var touched string
+		repo := keyWith(func(key *model.APIKey) { key.AllowedIPs = []string{"203.0.113.0/24"} })
+		repo.TouchAPIKeyFunc = func(id int, ip string) error {
+			touched = ip
+			return nil
+		}
+
+		principal, err := NewAPIKeyUsecase(repo, userRepo).AuthenticateAPIKey(context.Background(), secret, "203.0.113.9")
+
+		assert.NoError(t, err)
+		assert.Equal(t, &model.APIKeyPrincipal{KeyID: 4, UserID: 9, Email: "armazem@example.com", Role: model.RoleService, Scopes: []string{model.ScopeProductsRead}}, principal)
+		assert.Equal(t, "203.0.113.9", touched)
+	})
+
+	rejected := []struct {
+		name   string
+		key    string
+		ip     string
+		change func(*model.APIKey)
+	}{
+		{"Wrong Secret", prefix + "_wrong", "203.0.113.9", func(*model.APIKey) {}},
+		{"Not A Key", "not-a-key", "203.0.113.9", func(*model.APIKey) {}},
+		{"Revoked", secret, "203.0.113.9", func(key *model.APIKey) { key.RevokedAt = ptrTime(time.Now()) }},
+		{"Expired", secret, "203.0.113.9", func(key *model.APIKey) { key.ExpiresAt = ptrTime(time.Now().Add(-time.Minute)) }},
+		{"Outside The Allowlist", secret, "198.51.100.7", func(key *model.APIKey) { key.AllowedIPs = []string{"203.0.113.0/24", "192.0.2.1"} }},
+	}
+	for _, tt := range rejected {
+		t.Run(tt.name, func(t *testing.T) {
+			principal, err := NewAPIKeyUsecase(keyWith(tt.change), userRepo).AuthenticateAPIKey(context.Background(), tt.key, tt.ip)
+
+			assert.NoError(t, err)
+			assert.Nil(t, principal)
+		})
+	}
+
+	t.Run("Owner Gone", func(t *testing.T) {
+		principal, err := NewAPIKeyUsecase(keyWith(func(*model.APIKey) {}), &MockUserRepository{}).AuthenticateAPIKey(context.Background(), secret, "203.0.113.9")
+
+		assert.NoError(t, err)
+		assert.Nil(t, principal)
+	})
+}
+
+func ptrTime(t time.Time) *time.Time {
+	return &t
+}
@@ -0,0 +1,108 @@
+package usecase
+
+import (
+	"context"
+	"go-api/internal/audit"
+	"go-api/model"
+	"go-api/repository"
+)
+
+const (
+	// DefaultAuditLimit is the page size of the audit log when none is given
+	DefaultAuditLimit = 100
+	// MaxAuditLimit caps the page size of the audit log
+	MaxAuditLimit = 1000
+)
+
+// AuditUsecase defines the contract for reading and verifying the audit log
+type AuditUsecase interface {
+	GetAuditEvents(filter model.AuditFilter) ([]model.AuditEvent, error)
+	VerifyChain(ctx context.Context) (model.AuditVerification, error)
+}
+
+type auditUsecaseImpl struct {
+	repository repository.AuditRepositoryInterface
+}
+
+// NewAuditUsecase creates a new instance of AuditUsecase
+func NewAuditUsecase(repo repository.AuditRepositoryInterface) AuditUsecase {
+	return &auditUsecaseImpl{
+		repository: repo,
+	}
+}
+
+// GetAuditEvents returns a page of the events matching the filter, newest first
+func (au *auditUsecaseImpl) GetAuditEvents(filter model.AuditFilter) ([]model.AuditEvent, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = DefaultAuditLimit
+	}
+	if filter.Limit > MaxAuditLimit {
+		filter.Limit = MaxAuditLimit
+	}
+
+	events, err := au.repository.GetAuditEvents(filter)
+	if err != nil {
+		return nil, err
+	}
+	if events == nil {
+		events = []model.AuditEvent{}
+	}
+	return events, nil
+}
+
+// VerifyChain recomputes the hash of every event, oldest first, and reports
+// the first one that does not match its content or its predecessor
+func (au *auditUsecaseImpl) VerifyChain(ctx context.Context) (model.AuditVerification, error) {
+	result := model.AuditVerification{Valid: true}
+	err := au.repository.WalkAuditEvents(ctx, func(event model.AuditEvent) error {
+		if !result.Valid {
+			return nil
+		}
+		result.Checked++
+
+		hash, err := audit.Hash(result.LastHash, event)
+		if err != nil {
+			return err
+		}
+		if event.PrevHash != result.LastHash || event.Hash != hash {
+			id := event.ID
+			result.Valid = false
+			result.BrokenAt = &id
+			return nil
+		}
+		result.LastHash = event.Hash
+		return nil
+	})
+	if err != nil {
+		return model.AuditVerification{}, err
+	}
+	return result, nil
+}
+
+// --- Helper Functions ---
+
+// newAuditEvent describes a change made by the request in ctx. The
+// repository records it with the change and fills in the chain fields
+func newAuditEvent(ctx context.Context, action, entityType, entityID string, before, after interface{}) (model.AuditEvent, error) {
+	changes, err := audit.Diff(before, after)
+	if err != nil {
+		return model.AuditEvent{}, err
+	}
+
+	metadata := audit.FromContext(ctx)
+	return model.AuditEvent{
+		ActorID:    metadata.ActorID,
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Changes:    changes,
+		RequestID:  metadata.RequestID,
+		IP:         metadata.IP,
+		UserAgent:  metadata.UserAgent,
+	}, nil
+}
+
+// trashAuditFields records a move to or from the trash
+func trashAuditFields(deleted bool) map[string]bool {
+	return map[string]bool{"deleted": deleted}
+}
@@ -0,0 +1,104 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"go-api/internal/audit"
+	"go-api/model"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// chainedEvents builds a valid chain of n events
+func chainedEvents(t *testing.T, n int) []model.AuditEvent {
+	t.Helper()
+	var events []model.AuditEvent
+	prevHash := ""
+	for i := 1; i <= n; i++ {
+		event := model.AuditEvent{
+			ID:         int64(i),
+			OccurredAt: time.Date(2026, 3, 1, 12, i, 0, 0, time.UTC),
+			Action:     model.AuditActionUpdate,
+			EntityType: model.AuditEntityProduct,
+			EntityID:   "1",
+			Changes:    json.RawMessage(`{"price":{"before":"10.00","after":"12.00"}}`),
+			PrevHash:   prevHash,
+		}
+		hash, err := audit.Hash(prevHash, event)
+		require.NoError(t, err)
+		event.Hash = hash
+		prevHash = hash
+		events = append(events, event)
+	}
+	return events
+}
+
+func walkEvents(events []model.AuditEvent) *MockAuditRepository {
+	return &MockAuditRepository{
+		WalkAuditEventsFunc: func(ctx context.Context, fn func(model.AuditEvent) error) error {
+			for _, event := range events {
+				if err := fn(event); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+}
+
+func TestAuditUsecase_GetAuditEvents(t *testing.T) {
+	t.Run("Default And Capped Limit", func(t *testing.T) {
+		var limits []int
+		mockRepo := &MockAuditRepository{
+			GetAuditEventsFunc: func(filter model.AuditFilter) ([]model.AuditEvent, error) {
+				limits = append(limits, filter.Limit)
+				return nil, nil
+			},
+		}
+		usecase := NewAuditUsecase(mockRepo)
+
+		events, err := usecase.GetAuditEvents(model.AuditFilter{})
+		assert.NoError(t, err)
+		assert.Equal(t, []model.AuditEvent{}, events)
+		_, err = usecase.GetAuditEvents(model.AuditFilter{Limit: 5000})
+		assert.NoError(t, err)
+
+		assert.Equal(t, []int{DefaultAuditLimit, MaxAuditLimit}, limits)
+	})
+}
+
+func TestAuditUsecase_VerifyChain(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		events := chainedEvents(t, 3)
+
+		result, err := NewAuditUsecase(walkEvents(events)).VerifyChain(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, model.AuditVerification{Valid: true, Checked: 3, LastHash: events[2].Hash}, result)
+	})
+
+	t.Run("Edited Event", func(t *testing.T) {
+		events := chainedEvents(t, 3)
+		events[1].Changes = json.RawMessage(`{"price":{"before":"10.00","after":"1.00"}}`)
+
+		result, err := NewAuditUsecase(walkEvents(events)).VerifyChain(context.Background())
+
+		assert.NoError(t, err)
+		assert.False(t, result.Valid)
+		assert.Equal(t, int64(2), *result.BrokenAt)
+	})
+
+	t.Run("Removed Event", func(t *testing.T) {
+		events := chainedEvents(t, 3)
+		events = append(events[:1], events[2])
+
+		result, err := NewAuditUsecase(walkEvents(events)).VerifyChain(context.Background())
+
+		assert.NoError(t, err)
+		assert.False(t, result.Valid)
+		assert.Equal(t, int64(3), *result.BrokenAt)
+	})
+}
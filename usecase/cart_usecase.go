@@ -0,0 +1,373 @@
+package usecase
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"go-api/model"
+	"go-api/repository"
+	"time"
+)
+
+var (
+	ErrCartItemNotFound  = errors.New("cart item not found")
+	ErrInvalidQuantity   = errors.New("quantity must be at least 1")
+	ErrInsufficientStock = errors.New("not enough stock")
+	ErrVariantRequired   = errors.New("product has variants, variant_id is required")
+)
+
+// CartUsecase defines the contract for the shopping cart of a user or of an
+// anonymous session. Every change returns the cart as it is afterwards
+type CartUsecase interface {
+	GetCart(owner model.CartOwner) (*model.Cart, error)
+	AddItem(owner model.CartOwner, input model.CartItemInput) (*model.Cart, error)
+	UpdateItemQuantity(owner model.CartOwner, itemID, quantity int) (*model.Cart, error)
+	RemoveItem(owner model.CartOwner, itemID int) (*model.Cart, error)
+	MergeCart(userID int, token string) error
+	PriceCart(owner model.CartOwner, codes []string, destination *model.TaxDestination) (*model.PricingResult, error)
+}
+
+type cartUsecaseImpl struct {
+	repository        repository.CartRepositoryInterface
+	productRepository repository.ProductRepositoryInterface
+	variantRepository repository.VariantRepositoryInterface
+	promotions        PromotionPricer
+	taxes             TaxPricer
+}
+
+// NewCartUsecase creates a new instance of CartUsecase. promotions and taxes
+// may be nil, in which case carts are priced without discounts or taxes
+func NewCartUsecase(repo repository.CartRepositoryInterface, productRepo repository.ProductRepositoryInterface, variantRepo repository.VariantRepositoryInterface, promotions PromotionPricer, taxes TaxPricer) CartUsecase {
+	return &cartUsecaseImpl{
+		repository:        repo,
+		productRepository: productRepo,
+		variantRepository: variantRepo,
+		promotions:        promotions,
+		taxes:             taxes,
+	}
+}
+
+// GetCart returns the cart of the owner, or an empty cart when there is none yet
+func (cu *cartUsecaseImpl) GetCart(owner model.CartOwner) (*model.Cart, error) {
+	cart, err := cu.findCart(owner)
+	if err != nil {
+		return nil, err
+	}
+	if cart == nil {
+		return &model.Cart{Items: []model.CartItem{}, Subtotals: []model.Money{}}, nil
+	}
+	return cu.loadCart(cart)
+}
+
+// AddItem adds the quantity to the line of the product (or variant), checking
+// the product is active and the variant stock covers the new total. Anonymous
+// owners without a cart get a new one, whose token is returned once
+func (cu *cartUsecaseImpl) AddItem(owner model.CartOwner, input model.CartItemInput) (*model.Cart, error) {
+	if input.Quantity < 1 {
+		return nil, ErrInvalidQuantity
+	}
+
+	product, err := cu.productRepository.GetProductById(input.ProductID)
+	if err != nil {
+		return nil, err
+	}
+	if product == nil {
+		return nil, ErrProductNotFound
+	}
+	price, variant, err := resolveItemPrice(cu.variantRepository, *product, input.VariantID)
+	if err != nil {
+		return nil, err
+	}
+
+	cart, err := cu.findCart(owner)
+	if err != nil {
+		return nil, err
+	}
+	if cart == nil {
+		if cart, err = cu.createCart(owner); err != nil {
+			return nil, err
+		}
+	}
+
+	items, err := cu.repository.GetCartItems(cart.ID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	quantity := input.Quantity
+	for _, item := range items {
+		if item.ProductID == input.ProductID && sameVariant(item.VariantID, input.VariantID) {
+			quantity += item.Quantity
+		}
+	}
+	if variant != nil && quantity > variant.Stock {
+		return nil, fmt.Errorf("%w: %d available", ErrInsufficientStock, variant.Stock)
+	}
+
+	err = cu.repository.SaveCartItem(model.CartItem{
+		CartID:     cart.ID,
+		ProductID:  input.ProductID,
+		VariantID:  input.VariantID,
+		Quantity:   quantity,
+		AddedPrice: price,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return cu.loadCart(cart)
+}
+
+// UpdateItemQuantity replaces the quantity of an item, checking the variant stock
+func (cu *cartUsecaseImpl) UpdateItemQuantity(owner model.CartOwner, itemID, quantity int) (*model.Cart, error) {
+	if quantity < 1 {
+		return nil, ErrInvalidQuantity
+	}
+
+	cart, err := cu.findCart(owner)
+	if err != nil {
+		return nil, err
+	}
+	if cart == nil {
+		return nil, ErrCartItemNotFound
+	}
+	items, err := cu.repository.GetCartItems(cart.ID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	var item *model.CartItem
+	for i := range items {
+		if items[i].ID == itemID {
+			item = &items[i]
+		}
+	}
+	if item == nil {
+		return nil, ErrCartItemNotFound
+	}
+	if item.Stock != nil && quantity > *item.Stock {
+		return nil, fmt.Errorf("%w: %d available", ErrInsufficientStock, *item.Stock)
+	}
+
+	if err := cu.repository.UpdateCartItemQuantity(cart.ID, itemID, quantity); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrCartItemNotFound
+		}
+		return nil, err
+	}
+	return cu.loadCart(cart)
+}
+
+func (cu *cartUsecaseImpl) RemoveItem(owner model.CartOwner, itemID int) (*model.Cart, error) {
+	cart, err := cu.findCart(owner)
+	if err != nil {
+		return nil, err
+	}
+	if cart == nil {
+		return nil, ErrCartItemNotFound
+	}
+
+	if err := cu.repository.DeleteCartItem(cart.ID, itemID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrCartItemNotFound
+		}
+		return nil, err
+	}
+	return cu.loadCart(cart)
+}
+
+// MergeCart moves the anonymous cart of the token into the cart of the user:
+// the anonymous cart becomes the user's when they have none, otherwise the
+// quantities of matching lines add up. Unknown tokens are ignored
+func (cu *cartUsecaseImpl) MergeCart(userID int, token string) error {
+	if token == "" {
+		return nil
+	}
+	anonymous, err := cu.repository.GetCartByTokenHash(hashCartToken(token))
+	if err != nil {
+		return err
+	}
+	if anonymous == nil {
+		return nil
+	}
+
+	cart, err := cu.repository.GetCartByUserID(userID)
+	if err != nil {
+		return err
+	}
+	if cart == nil {
+		return cu.repository.ClaimCart(anonymous.ID, userID)
+	}
+	return cu.repository.MergeCarts(anonymous.ID, cart.ID)
+}
+
+// PriceCart applies the promotions and the discount codes to the available
+// items of the cart, breaking the discount down per item, without redeeming
+// anything. Rejected codes are listed with the reason instead of failing.
+// With a destination, the discounted items are taxed too
+func (cu *cartUsecaseImpl) PriceCart(owner model.CartOwner, codes []string, destination *model.TaxDestination) (*model.PricingResult, error) {
+	cart, err := cu.GetCart(owner)
+	if err != nil {
+		return nil, err
+	}
+
+	var lines []model.PricingLine
+	for _, item := range cart.Items {
+		if item.Available {
+			lines = append(lines, model.PricingLine{
+				ProductID: item.ProductID,
+				VariantID: item.VariantID,
+				Quantity:  item.Quantity,
+				UnitPrice: item.UnitPrice,
+			})
+		}
+	}
+
+	var userID *int
+	if owner.UserID != 0 {
+		userID = &owner.UserID
+	}
+	var pricing *model.PricingResult
+	if cu.promotions != nil {
+		pricing, err = cu.promotions.PriceLines(userID, lines, codes)
+	} else if pricing, err = newPricingResult(lines); err == nil {
+		rejectAllCodes(pricing, codes)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := applyTaxes(cu.taxes, pricing, destination); err != nil {
+		return nil, err
+	}
+	return pricing, nil
+}
+
+// --- Helper Functions ---
+
+// findCart returns the cart of the authenticated user or, for anonymous
+// owners, the cart of the session token
+func (cu *cartUsecaseImpl) findCart(owner model.CartOwner) (*model.Cart, error) {
+	if owner.UserID != 0 {
+		return cu.repository.GetCartByUserID(owner.UserID)
+	}
+	if owner.Token == "" {
+		return nil, nil
+	}
+	return cu.repository.GetCartByTokenHash(hashCartToken(owner.Token))
+}
+
+// createCart creates the cart of the user or, for anonymous owners, a cart
+// under a new random token; a token sent by the client is never reused
+func (cu *cartUsecaseImpl) createCart(owner model.CartOwner) (*model.Cart, error) {
+	if owner.UserID != 0 {
+		userID := owner.UserID
+		return cu.repository.CreateCart(&userID, "")
+	}
+
+	token, err := newCartToken()
+	if err != nil {
+		return nil, err
+	}
+	cart, err := cu.repository.CreateCart(nil, hashCartToken(token))
+	if err != nil {
+		return nil, err
+	}
+	cart.Token = token
+	return cart, nil
+}
+
+// loadCart reads the items of the cart with their current prices and
+// computes the subtotals
+func (cu *cartUsecaseImpl) loadCart(cart *model.Cart) (*model.Cart, error) {
+	items, err := cu.repository.GetCartItems(cart.ID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	cart.Items = items
+	if err := priceCart(cart); err != nil {
+		return nil, err
+	}
+	return cart, nil
+}
+
+// resolveItemPrice returns the unit price of the product or, for products
+// sold through variants, of the chosen variant, which is also returned
+func resolveItemPrice(variantRepo repository.VariantRepositoryInterface, product model.Product, variantID *int) (model.Money, *model.Variant, error) {
+	variants, err := variantRepo.GetVariants([]int{product.ID})
+	if err != nil {
+		return model.Money{}, nil, err
+	}
+	if len(variants) == 0 {
+		if variantID != nil {
+			return model.Money{}, nil, ErrVariantNotFound
+		}
+		return product.Price, nil, nil
+	}
+	if variantID == nil {
+		return model.Money{}, nil, ErrVariantRequired
+	}
+
+	for _, variant := range variants {
+		if variant.ID == *variantID {
+			resolveVariantPrice(&variant, product.Price)
+			return variant.Price, &variant, nil
+		}
+	}
+	return model.Money{}, nil, ErrVariantNotFound
+}
+
+// priceCart fills in the subtotal and flags of every item and sums the
+// available ones per currency, in the order the currencies first appear
+func priceCart(cart *model.Cart) error {
+	cart.Subtotals = []model.Money{}
+	position := make(map[string]int)
+	for i := range cart.Items {
+		item := &cart.Items[i]
+		subtotal, err := item.UnitPrice.Mul(int64(item.Quantity))
+		if err != nil {
+			return err
+		}
+		item.Subtotal = subtotal
+		item.PriceChanged = item.UnitPrice != item.AddedPrice
+		item.Available = item.Active && (item.Stock == nil || item.Quantity <= *item.Stock)
+		if !item.Available {
+			continue
+		}
+
+		index, ok := position[item.Subtotal.Currency]
+		if !ok {
+			position[item.Subtotal.Currency] = len(cart.Subtotals)
+			cart.Subtotals = append(cart.Subtotals, item.Subtotal)
+			continue
+		}
+		sum, err := cart.Subtotals[index].Add(item.Subtotal)
+		if err != nil {
+			return err
+		}
+		cart.Subtotals[index] = sum
+	}
+	return nil
+}
+
+func sameVariant(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+func newCartToken() (string, error) {
+	token := make([]byte, 32)
+	if _, err := rand.Read(token); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(token), nil
+}
+
+// hashCartToken is what the cart table stores in place of the token, so a
+// database leak does not hand out the anonymous carts
+func hashCartToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
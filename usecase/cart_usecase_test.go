@@ -0,0 +1,239 @@
+package usecase
+
+import (
+	"go-api/model"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func cartVariants(productIDs []int) ([]model.Variant, error) {
+	price := model.Money{Amount: 5490, Currency: "BRL"}
+	return []model.Variant{
+		{ID: 2, ProductID: productIDs[0], SKU: "CAM-AZUL-M", Stock: 3},
+		{ID: 3, ProductID: productIDs[0], SKU: "CAM-AZUL-G", Stock: 5, PriceOverride: &price},
+	}, nil
+}
+
+func TestCartUsecase_GetCart(t *testing.T) {
+	t.Run("Empty When There Is No Cart", func(t *testing.T) {
+		usecase := NewCartUsecase(&MockCartRepository{}, &MockProductRepository{}, &MockVariantRepository{}, nil, nil)
+		cart, err := usecase.GetCart(model.CartOwner{Token: "unknown"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, cart.ID)
+		assert.Empty(t, cart.Items)
+		assert.Empty(t, cart.Subtotals)
+	})
+
+	t.Run("Flags Price Changes And Sums Available Items", func(t *testing.T) {
+		mockRepo := &MockCartRepository{
+			GetCartByUserIDFunc: func(userID int) (*model.Cart, error) {
+				return &model.Cart{ID: 4, UserID: &userID}, nil
+			},
+			GetCartItemsFunc: func(cartID int, asOf time.Time) ([]model.CartItem, error) {
+				return []model.CartItem{
+					{ID: 1, ProductID: 1, Quantity: 2, Active: true,
+						AddedPrice: model.Money{Amount: 4990, Currency: "BRL"}, UnitPrice: model.Money{Amount: 3990, Currency: "BRL"}},
+					{ID: 2, ProductID: 2, Quantity: 1, Active: true,
+						AddedPrice: model.Money{Amount: 1000, Currency: "USD"}, UnitPrice: model.Money{Amount: 1000, Currency: "USD"}},
+					{ID: 3, ProductID: 3, Quantity: 1, Active: true,
+						AddedPrice: model.Money{Amount: 1990, Currency: "BRL"}, UnitPrice: model.Money{Amount: 1990, Currency: "BRL"}},
+					{ID: 4, ProductID: 1, VariantID: intPtr(2), Quantity: 4, Active: true, Stock: intPtr(3),
+						AddedPrice: model.Money{Amount: 4990, Currency: "BRL"}, UnitPrice: model.Money{Amount: 4990, Currency: "BRL"}},
+					{ID: 5, ProductID: 5, Quantity: 1, Active: false,
+						AddedPrice: model.Money{Amount: 990, Currency: "BRL"}, UnitPrice: model.Money{Amount: 990, Currency: "BRL"}},
+				}, nil
+			},
+		}
+
+		usecase := NewCartUsecase(mockRepo, &MockProductRepository{}, &MockVariantRepository{}, nil, nil)
+		cart, err := usecase.GetCart(model.CartOwner{UserID: 7})
+
+		assert.NoError(t, err)
+		assert.True(t, cart.Items[0].PriceChanged)
+		assert.Equal(t, model.Money{Amount: 7980, Currency: "BRL"}, cart.Items[0].Subtotal)
+		assert.False(t, cart.Items[1].PriceChanged)
+		assert.False(t, cart.Items[3].Available)
+		assert.False(t, cart.Items[4].Available)
+		assert.Equal(t, []model.Money{{Amount: 9970, Currency: "BRL"}, {Amount: 1000, Currency: "USD"}}, cart.Subtotals)
+	})
+}
+
+func TestCartUsecase_AddItem(t *testing.T) {
+	t.Run("Creates Anonymous Cart", func(t *testing.T) {
+		var tokenHash string
+		var saved model.CartItem
+		mockRepo := &MockCartRepository{
+			CreateCartFunc: func(userID *int, hash string) (*model.Cart, error) {
+				assert.Nil(t, userID)
+				tokenHash = hash
+				return &model.Cart{ID: 4}, nil
+			},
+			SaveCartItemFunc: func(item model.CartItem) error {
+				saved = item
+				return nil
+			},
+		}
+		mockVariants := &MockVariantRepository{GetVariantsFunc: cartVariants}
+		mockProducts := &MockProductRepository{GetProductByIdFunc: variantProduct}
+
+		usecase := NewCartUsecase(mockRepo, mockProducts, mockVariants, nil, nil)
+		cart, err := usecase.AddItem(model.CartOwner{Token: "chosen-by-client"}, model.CartItemInput{ProductID: 1, VariantID: intPtr(3), Quantity: 2})
+
+		assert.NoError(t, err)
+		assert.Len(t, cart.Token, 64)
+		assert.NotEqual(t, "chosen-by-client", cart.Token)
+		assert.Equal(t, hashCartToken(cart.Token), tokenHash)
+		assert.Equal(t, model.CartItem{CartID: 4, ProductID: 1, VariantID: intPtr(3), Quantity: 2,
+			AddedPrice: model.Money{Amount: 5490, Currency: "BRL"}}, saved)
+	})
+
+	t.Run("Adds Up To The Stock", func(t *testing.T) {
+		mockRepo := &MockCartRepository{
+			GetCartByUserIDFunc: func(userID int) (*model.Cart, error) {
+				return &model.Cart{ID: 4}, nil
+			},
+			GetCartItemsFunc: func(cartID int, asOf time.Time) ([]model.CartItem, error) {
+				return []model.CartItem{{ID: 1, ProductID: 1, VariantID: intPtr(2), Quantity: 2}}, nil
+			},
+			SaveCartItemFunc: func(item model.CartItem) error {
+				t.Fatal("item saved above the stock")
+				return nil
+			},
+		}
+		mockVariants := &MockVariantRepository{GetVariantsFunc: cartVariants}
+		mockProducts := &MockProductRepository{GetProductByIdFunc: variantProduct}
+
+		usecase := NewCartUsecase(mockRepo, mockProducts, mockVariants, nil, nil)
+		_, err := usecase.AddItem(model.CartOwner{UserID: 7}, model.CartItemInput{ProductID: 1, VariantID: intPtr(2), Quantity: 2})
+
+		assert.ErrorIs(t, err, ErrInsufficientStock)
+	})
+
+	t.Run("Variant Required", func(t *testing.T) {
+		mockVariants := &MockVariantRepository{GetVariantsFunc: cartVariants}
+		mockProducts := &MockProductRepository{GetProductByIdFunc: variantProduct}
+
+		usecase := NewCartUsecase(&MockCartRepository{}, mockProducts, mockVariants, nil, nil)
+		_, err := usecase.AddItem(model.CartOwner{UserID: 7}, model.CartItemInput{ProductID: 1, Quantity: 1})
+
+		assert.ErrorIs(t, err, ErrVariantRequired)
+	})
+
+	t.Run("Product Not Found", func(t *testing.T) {
+		usecase := NewCartUsecase(&MockCartRepository{}, &MockProductRepository{}, &MockVariantRepository{}, nil, nil)
+		_, err := usecase.AddItem(model.CartOwner{UserID: 7}, model.CartItemInput{ProductID: 99, Quantity: 1})
+
+		assert.ErrorIs(t, err, ErrProductNotFound)
+	})
+}
+
+func TestCartUsecase_UpdateItemQuantity(t *testing.T) {
+	t.Run("Item Not In Cart", func(t *testing.T) {
+		mockRepo := &MockCartRepository{
+			GetCartByTokenHashFunc: func(tokenHash string) (*model.Cart, error) {
+				return &model.Cart{ID: 4}, nil
+			},
+		}
+
+		usecase := NewCartUsecase(mockRepo, &MockProductRepository{}, &MockVariantRepository{}, nil, nil)
+		_, err := usecase.UpdateItemQuantity(model.CartOwner{Token: "anon-token"}, 9, 1)
+
+		assert.ErrorIs(t, err, ErrCartItemNotFound)
+	})
+}
+
+func TestCartUsecase_MergeCart(t *testing.T) {
+	t.Run("Into Existing User Cart", func(t *testing.T) {
+		var merged []int
+		mockRepo := &MockCartRepository{
+			GetCartByTokenHashFunc: func(tokenHash string) (*model.Cart, error) {
+				return &model.Cart{ID: 9}, nil
+			},
+			GetCartByUserIDFunc: func(userID int) (*model.Cart, error) {
+				return &model.Cart{ID: 4, UserID: &userID}, nil
+			},
+			MergeCartsFunc: func(sourceID, targetID int) error {
+				merged = []int{sourceID, targetID}
+				return nil
+			},
+		}
+
+		usecase := NewCartUsecase(mockRepo, &MockProductRepository{}, &MockVariantRepository{}, nil, nil)
+
+		assert.NoError(t, usecase.MergeCart(7, "anon-token"))
+		assert.Equal(t, []int{9, 4}, merged)
+	})
+
+	t.Run("Unknown Token", func(t *testing.T) {
+		usecase := NewCartUsecase(&MockCartRepository{}, &MockProductRepository{}, &MockVariantRepository{}, nil, nil)
+
+		assert.NoError(t, usecase.MergeCart(7, "expired-token"))
+	})
+}
+
+func TestCartUsecase_PriceCart(t *testing.T) {
+	mockRepo := &MockCartRepository{
+		GetCartByUserIDFunc: func(userID int) (*model.Cart, error) {
+			return &model.Cart{ID: 4, UserID: &userID}, nil
+		},
+		GetCartItemsFunc: func(cartID int, asOf time.Time) ([]model.CartItem, error) {
+			return []model.CartItem{
+				{ID: 1, ProductID: 1, VariantID: intPtr(2), Quantity: 2, Active: true, Stock: intPtr(3),
+					AddedPrice: model.Money{Amount: 4990, Currency: "BRL"}, UnitPrice: model.Money{Amount: 4990, Currency: "BRL"}},
+				{ID: 2, ProductID: 5, Quantity: 1, Active: false,
+					AddedPrice: model.Money{Amount: 990, Currency: "BRL"}, UnitPrice: model.Money{Amount: 990, Currency: "BRL"}},
+			}, nil
+		},
+	}
+
+	t.Run("Prices The Available Items", func(t *testing.T) {
+		promotions := NewPromotionUsecase(&MockPromotionRepository{
+			GetPromotionByCodeFunc: promotionCodes(model.Promotion{
+				ID: 3, Code: "BEMVINDO10", Name: "Boas-vindas", Kind: model.DiscountPercentage, Percent: 10, Active: true,
+			}),
+		}, &MockProductRepository{}, &MockCategoryRepository{})
+
+		usecase := NewCartUsecase(mockRepo, &MockProductRepository{}, &MockVariantRepository{}, promotions, nil)
+		pricing, err := usecase.PriceCart(model.CartOwner{UserID: 7}, []string{"BEMVINDO10", "VERAO"}, nil)
+
+		assert.NoError(t, err)
+		assert.Len(t, pricing.Lines, 1)
+		assert.Equal(t, model.Money{Amount: 998, Currency: "BRL"}, pricing.Discount)
+		assert.Equal(t, model.Money{Amount: 8982, Currency: "BRL"}, pricing.Total)
+		assert.Equal(t, []model.RejectedCode{{Code: "VERAO", Reason: "unknown code"}}, pricing.Rejected)
+	})
+
+	t.Run("Without Promotions", func(t *testing.T) {
+		usecase := NewCartUsecase(mockRepo, &MockProductRepository{}, &MockVariantRepository{}, nil, nil)
+		pricing, err := usecase.PriceCart(model.CartOwner{UserID: 7}, []string{"verao"}, nil)
+
+		assert.NoError(t, err)
+		assert.Equal(t, model.Money{Amount: 9980, Currency: "BRL"}, pricing.Total)
+		assert.Equal(t, []model.RejectedCode{{Code: "VERAO", Reason: "discount codes are not accepted"}}, pricing.Rejected)
+	})
+
+	t.Run("Adds The Taxes Not Included In The Prices", func(t *testing.T) {
+		taxes := NewTaxUsecase(newTestTaxTable(t), &MockProductRepository{})
+		usecase := NewCartUsecase(mockRepo, &MockProductRepository{}, &MockVariantRepository{}, nil, taxes)
+		pricing, err := usecase.PriceCart(model.CartOwner{UserID: 7}, nil, &model.TaxDestination{Country: "US", State: "NY"})
+
+		assert.NoError(t, err)
+		assert.False(t, pricing.Tax.Included)
+		assert.Equal(t, []model.TaxAmount{
+			{Name: "Sales tax", Percent: "4", Amount: model.Money{Amount: 399, Currency: "BRL"}},
+			{Name: "MCTD surcharge", Percent: "0.375", Amount: model.Money{Amount: 37, Currency: "BRL"}},
+		}, pricing.Tax.Taxes)
+		assert.Equal(t, model.Money{Amount: 10416, Currency: "BRL"}, pricing.Total)
+	})
+
+	t.Run("Unknown Destination", func(t *testing.T) {
+		taxes := NewTaxUsecase(newTestTaxTable(t), &MockProductRepository{})
+		usecase := NewCartUsecase(mockRepo, &MockProductRepository{}, &MockVariantRepository{}, nil, taxes)
+		_, err := usecase.PriceCart(model.CartOwner{UserID: 7}, nil, &model.TaxDestination{Country: "JP"})
+
+		assert.ErrorIs(t, err, ErrTaxRegionNotFound)
+	})
+}
@@ -0,0 +1,380 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"go-api/model"
+	"go-api/repository"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+var (
+	ErrCategoryNotFound    = errors.New("category not found")
+	ErrCategorySlugTaken   = errors.New("a sibling category already uses this slug")
+	ErrInvalidCategorySlug = errors.New("slug must contain only lowercase letters, digits and hyphens")
+	ErrCategoryCycle       = errors.New("a category cannot be moved under itself or one of its descendants")
+	ErrCategoryHasChildren = errors.New("category has subcategories, move or delete them first")
+	ErrCategoryChanged     = errors.New("category was changed by another request, try again")
+)
+
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// CategoryUsecase defines the contract for the category usecase
+type CategoryUsecase interface {
+	GetCategoryTree() ([]model.Category, error)
+	GetCategoryByID(id int) (*model.Category, error)
+	CreateCategory(ctx context.Context, category model.Category) (model.Category, error)
+	UpdateCategory(ctx context.Context, id int, changes model.Category) (*model.Category, error)
+	MoveCategory(ctx context.Context, id int, parentID *int) (*model.Category, error)
+	DeleteCategory(ctx context.Context, id int) error
+	SetProductCategories(ctx context.Context, productID int, categoryIDs []int) ([]model.Category, error)
+	GetProductCategories(productID int) ([]model.Category, error)
+}
+
+type categoryUsecaseImpl struct {
+	repository        repository.CategoryRepositoryInterface
+	productRepository repository.ProductRepositoryInterface
+}
+
+// NewCategoryUsecase creates a new instance of CategoryUsecase
+func NewCategoryUsecase(repo repository.CategoryRepositoryInterface, productRepo repository.ProductRepositoryInterface) CategoryUsecase {
+	return &categoryUsecaseImpl{
+		repository:        repo,
+		productRepository: productRepo,
+	}
+}
+
+func (cu *categoryUsecaseImpl) GetCategoryTree() ([]model.Category, error) {
+	categories, err := cu.repository.GetCategories()
+	if err != nil {
+		return nil, err
+	}
+	return buildCategoryTree(categories, nil), nil
+}
+
+// GetCategoryByID returns the category with its whole subtree in Children
+func (cu *categoryUsecaseImpl) GetCategoryByID(id int) (*model.Category, error) {
+	category, err := cu.repository.GetCategoryByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if category == nil {
+		return nil, nil
+	}
+
+	descendants, err := cu.repository.GetDescendants(category.Path)
+	if err != nil {
+		return nil, err
+	}
+	category.Children = buildCategoryTree(descendants, &category.ID)
+	return category, nil
+}
+
+func (cu *categoryUsecaseImpl) CreateCategory(ctx context.Context, category model.Category) (model.Category, error) {
+	if category.Slug == "" {
+		category.Slug = slugify(category.Name)
+	}
+	if !slugPattern.MatchString(category.Slug) {
+		return model.Category{}, ErrInvalidCategorySlug
+	}
+
+	parentPath := ""
+	if category.ParentID != nil {
+		parent, err := cu.repository.GetCategoryByID(*category.ParentID)
+		if err != nil {
+			return model.Category{}, err
+		}
+		if parent == nil {
+			return model.Category{}, ErrCategoryNotFound
+		}
+		parentPath = parent.Path
+	}
+	category.Path = joinCategoryPath(parentPath, category.Slug)
+
+	if err := cu.ensurePathAvailable(category.Path); err != nil {
+		return model.Category{}, err
+	}
+
+	event, err := newAuditEvent(ctx, model.AuditActionCreate, model.AuditEntityCategory, "", nil, categoryAuditFields(category))
+	if err != nil {
+		return model.Category{}, err
+	}
+	id, err := cu.repository.CreateCategory(category, event)
+	if err != nil {
+		return model.Category{}, err
+	}
+	category.ID = id
+	return category, nil
+}
+
+// UpdateCategory renames a category; changing the slug moves the paths of the whole subtree
+func (cu *categoryUsecaseImpl) UpdateCategory(ctx context.Context, id int, changes model.Category) (*model.Category, error) {
+	category, err := cu.repository.GetCategoryByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if category == nil {
+		return nil, ErrCategoryNotFound
+	}
+
+	before := *category
+	if changes.Name != "" {
+		category.Name = changes.Name
+	}
+	if changes.Slug != "" && changes.Slug != category.Slug {
+		if !slugPattern.MatchString(changes.Slug) {
+			return nil, ErrInvalidCategorySlug
+		}
+		category.Slug = changes.Slug
+	}
+
+	return cu.save(ctx, before, category, parentPathOf(category.Path))
+}
+
+// MoveCategory re-parents a category together with its subtree; a nil parent
+// moves it to the root. The repository rejects a parent inside the subtree
+// while holding the locks of the move
+func (cu *categoryUsecaseImpl) MoveCategory(ctx context.Context, id int, parentID *int) (*model.Category, error) {
+	category, err := cu.repository.GetCategoryByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if category == nil {
+		return nil, ErrCategoryNotFound
+	}
+
+	parentPath := ""
+	if parentID != nil {
+		parent, err := cu.repository.GetCategoryByID(*parentID)
+		if err != nil {
+			return nil, err
+		}
+		if parent == nil {
+			return nil, ErrCategoryNotFound
+		}
+		parentPath = parent.Path
+	}
+	before := *category
+	category.ParentID = parentID
+
+	return cu.save(ctx, before, category, parentPath)
+}
+
+func (cu *categoryUsecaseImpl) save(ctx context.Context, before model.Category, category *model.Category, parentPath string) (*model.Category, error) {
+	oldPath := category.Path
+	category.Path = joinCategoryPath(parentPath, category.Slug)
+	if category.Path != oldPath {
+		if err := cu.ensurePathAvailable(category.Path); err != nil {
+			return nil, err
+		}
+	}
+
+	event, err := newAuditEvent(ctx, model.AuditActionUpdate, model.AuditEntityCategory, strconv.Itoa(category.ID),
+		categoryAuditFields(before), categoryAuditFields(*category))
+	if err != nil {
+		return nil, err
+	}
+	if err := cu.repository.UpdateCategory(*category, oldPath, event); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrCategoryCycle):
+			return nil, ErrCategoryCycle
+		case errors.Is(err, repository.ErrCategoryChanged):
+			return nil, ErrCategoryChanged
+		}
+		return nil, err
+	}
+	return category, nil
+}
+
+func (cu *categoryUsecaseImpl) DeleteCategory(ctx context.Context, id int) error {
+	category, err := cu.repository.GetCategoryByID(id)
+	if err != nil {
+		return err
+	}
+	if category == nil {
+		return ErrCategoryNotFound
+	}
+
+	children, err := cu.repository.CountChildren(id)
+	if err != nil {
+		return err
+	}
+	if children > 0 {
+		return ErrCategoryHasChildren
+	}
+
+	event, err := newAuditEvent(ctx, model.AuditActionDelete, model.AuditEntityCategory, strconv.Itoa(id), categoryAuditFields(*category), nil)
+	if err != nil {
+		return err
+	}
+	return cu.repository.DeleteCategory(id, event)
+}
+
+func (cu *categoryUsecaseImpl) SetProductCategories(ctx context.Context, productID int, categoryIDs []int) ([]model.Category, error) {
+	product, err := cu.productRepository.GetProductById(productID)
+	if err != nil {
+		return nil, err
+	}
+	if product == nil {
+		return nil, ErrProductNotFound
+	}
+
+	for _, categoryID := range categoryIDs {
+		category, err := cu.repository.GetCategoryByID(categoryID)
+		if err != nil {
+			return nil, err
+		}
+		if category == nil {
+			return nil, ErrCategoryNotFound
+		}
+	}
+
+	current, err := cu.repository.GetProductCategories(productID)
+	if err != nil {
+		return nil, err
+	}
+	event, err := newAuditEvent(ctx, model.AuditActionUpdate, model.AuditEntityProduct, strconv.Itoa(productID),
+		productCategoriesAuditFields(categoryIDsOf(current)), productCategoriesAuditFields(categoryIDs))
+	if err != nil {
+		return nil, err
+	}
+	if err := cu.repository.SetProductCategories(productID, categoryIDs, event); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrProductNotFound
+		}
+		return nil, err
+	}
+	return cu.repository.GetProductCategories(productID)
+}
+
+func (cu *categoryUsecaseImpl) GetProductCategories(productID int) ([]model.Category, error) {
+	product, err := cu.productRepository.GetProductById(productID)
+	if err != nil {
+		return nil, err
+	}
+	if product == nil {
+		return nil, ErrProductNotFound
+	}
+	return cu.repository.GetProductCategories(productID)
+}
+
+func (cu *categoryUsecaseImpl) ensurePathAvailable(path string) error {
+	existing, err := cu.repository.GetCategoryByPath(path)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return ErrCategorySlugTaken
+	}
+	return nil
+}
+
+// --- Helper Functions ---
+
+func categoryAuditFields(category model.Category) map[string]interface{} {
+	return map[string]interface{}{
+		"parent_id": category.ParentID,
+		"name":      category.Name,
+		"slug":      category.Slug,
+		"path":      category.Path,
+	}
+}
+
+// productCategoriesAuditFields records the assigned categories as a sorted,
+// comma separated list so reordering the request is not a change
+func productCategoriesAuditFields(categoryIDs []int) map[string]string {
+	ids := append([]int(nil), categoryIDs...)
+	sort.Ints(ids)
+	values := make([]string, 0, len(ids))
+	for i, id := range ids {
+		if i > 0 && id == ids[i-1] {
+			continue
+		}
+		values = append(values, strconv.Itoa(id))
+	}
+	return map[string]string{"categories": strings.Join(values, ",")}
+}
+
+func categoryIDsOf(categories []model.Category) []int {
+	ids := make([]int, 0, len(categories))
+	for _, category := range categories {
+		ids = append(ids, category.ID)
+	}
+	return ids
+}
+
+// buildCategoryTree nests the flat list of categories under the given root
+// (nil for the top level of the taxonomy)
+func buildCategoryTree(categories []model.Category, rootID *int) []model.Category {
+	byParent := make(map[int][]model.Category)
+	for _, category := range categories {
+		parent := 0
+		if category.ParentID != nil {
+			parent = *category.ParentID
+		}
+		byParent[parent] = append(byParent[parent], category)
+	}
+
+	var attach func(parent int) []model.Category
+	attach = func(parent int) []model.Category {
+		nodes := byParent[parent]
+		for i := range nodes {
+			nodes[i].Children = attach(nodes[i].ID)
+		}
+		return nodes
+	}
+
+	root := 0
+	if rootID != nil {
+		root = *rootID
+	}
+	return attach(root)
+}
+
+func joinCategoryPath(parentPath, slug string) string {
+	if parentPath == "" {
+		return slug
+	}
+	return parentPath + "/" + slug
+}
+
+func parentPathOf(path string) string {
+	if i := strings.LastIndex(path, "/"); i >= 0 {
+		return path[:i]
+	}
+	return ""
+}
+
+var accentReplacer = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ã", "a", "ä", "a",
+	"é", "e", "è", "e", "ê", "e", "ë", "e",
+	"í", "i", "ì", "i", "î", "i", "ï", "i",
+	"ó", "o", "ò", "o", "ô", "o", "õ", "o", "ö", "o",
+	"ú", "u", "ù", "u", "û", "u", "ü", "u",
+	"ç", "c", "ñ", "n",
+)
+
+// slugify derives a URL friendly slug from a category name ("Eletrônicos & TV" -> "eletronicos-tv")
+func slugify(name string) string {
+	name = accentReplacer.Replace(strings.ToLower(name))
+
+	var b strings.Builder
+	hyphen := false
+	for _, r := range name {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			b.WriteRune(r)
+			hyphen = false
+			continue
+		}
+		if !hyphen && b.Len() > 0 {
+			b.WriteByte('-')
+			hyphen = true
+		}
+	}
+	return strings.TrimSuffix(b.String(), "-")
+}
@@ -0,0 +1,283 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"go-api/model"
+	"go-api/repository"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func TestCategoryUsecase_GetCategoryTree(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mockRepo := &MockCategoryRepository{
+			GetCategoriesFunc: func() ([]model.Category, error) {
+				return []model.Category{
+					{ID: 1, Name: "Roupas", Slug: "roupas", Path: "roupas"},
+					{ID: 2, ParentID: intPtr(1), Name: "Camisetas", Slug: "camisetas", Path: "roupas/camisetas"},
+					{ID: 3, ParentID: intPtr(2), Name: "Manga Longa", Slug: "manga-longa", Path: "roupas/camisetas/manga-longa"},
+					{ID: 4, Name: "Eletrônicos", Slug: "eletronicos", Path: "eletronicos"},
+				}, nil
+			},
+		}
+
+		usecase := NewCategoryUsecase(mockRepo, &MockProductRepository{})
+		tree, err := usecase.GetCategoryTree()
+
+		assert.NoError(t, err)
+		assert.Len(t, tree, 2)
+		assert.Equal(t, "roupas", tree[0].Slug)
+		assert.Len(t, tree[0].Children, 1)
+		assert.Equal(t, "manga-longa", tree[0].Children[0].Children[0].Slug)
+		assert.Empty(t, tree[1].Children)
+	})
+}
+
+func TestCategoryUsecase_CreateCategory(t *testing.T) {
+	t.Run("Derives Slug And Path", func(t *testing.T) {
+		var created model.Category
+		var event model.AuditEvent
+		mockRepo := &MockCategoryRepository{
+			GetCategoryByIDFunc: func(id int) (*model.Category, error) {
+				return &model.Category{ID: 1, Slug: "roupas", Path: "roupas"}, nil
+			},
+			CreateCategoryFunc: func(category model.Category, e model.AuditEvent) (int, error) {
+				created, event = category, e
+				return 5, nil
+			},
+		}
+
+		usecase := NewCategoryUsecase(mockRepo, &MockProductRepository{})
+		category, err := usecase.CreateCategory(context.Background(), model.Category{ParentID: intPtr(1), Name: "Calças & Bermudas"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 5, category.ID)
+		assert.Equal(t, "calcas-bermudas", created.Slug)
+		assert.Equal(t, "roupas/calcas-bermudas", created.Path)
+		assert.Equal(t, model.AuditActionCreate, event.Action)
+		assert.Equal(t, model.AuditEntityCategory, event.EntityType)
+		assert.Contains(t, string(event.Changes), `"roupas/calcas-bermudas"`)
+	})
+
+	t.Run("Slug Taken By Sibling", func(t *testing.T) {
+		mockRepo := &MockCategoryRepository{
+			GetCategoryByPathFunc: func(path string) (*model.Category, error) {
+				return &model.Category{ID: 7, Path: path}, nil
+			},
+		}
+
+		usecase := NewCategoryUsecase(mockRepo, &MockProductRepository{})
+		_, err := usecase.CreateCategory(context.Background(), model.Category{Name: "Roupas", Slug: "roupas"})
+
+		assert.ErrorIs(t, err, ErrCategorySlugTaken)
+	})
+
+	t.Run("Invalid Slug", func(t *testing.T) {
+		usecase := NewCategoryUsecase(&MockCategoryRepository{}, &MockProductRepository{})
+		_, err := usecase.CreateCategory(context.Background(), model.Category{Name: "Roupas", Slug: "Roupas Femininas"})
+
+		assert.ErrorIs(t, err, ErrInvalidCategorySlug)
+	})
+
+	t.Run("Parent Not Found", func(t *testing.T) {
+		usecase := NewCategoryUsecase(&MockCategoryRepository{}, &MockProductRepository{})
+		_, err := usecase.CreateCategory(context.Background(), model.Category{ParentID: intPtr(9), Name: "Camisetas"})
+
+		assert.ErrorIs(t, err, ErrCategoryNotFound)
+	})
+}
+
+func TestCategoryUsecase_MoveCategory(t *testing.T) {
+	categories := map[int]*model.Category{
+		1: {ID: 1, Slug: "roupas", Path: "roupas"},
+		2: {ID: 2, ParentID: intPtr(1), Slug: "camisetas", Path: "roupas/camisetas"},
+		3: {ID: 3, Slug: "moda", Path: "moda"},
+	}
+	getByID := func(id int) (*model.Category, error) {
+		category, ok := categories[id]
+		if !ok {
+			return nil, nil
+		}
+		copied := *category
+		return &copied, nil
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		var oldPath string
+		var saved model.Category
+		var event model.AuditEvent
+		mockRepo := &MockCategoryRepository{
+			GetCategoryByIDFunc: getByID,
+			UpdateCategoryFunc: func(category model.Category, previous string, e model.AuditEvent) error {
+				saved, oldPath, event = category, previous, e
+				return nil
+			},
+		}
+
+		usecase := NewCategoryUsecase(mockRepo, &MockProductRepository{})
+		category, err := usecase.MoveCategory(context.Background(), 2, intPtr(3))
+
+		assert.NoError(t, err)
+		assert.Equal(t, "moda/camisetas", category.Path)
+		assert.Equal(t, "roupas/camisetas", oldPath)
+		assert.Equal(t, 3, *saved.ParentID)
+		assert.Equal(t, model.AuditActionUpdate, event.Action)
+		assert.Equal(t, "2", event.EntityID)
+		assert.JSONEq(t, `{
+			"parent_id": {"before": 1, "after": 3},
+			"path": {"before": "roupas/camisetas", "after": "moda/camisetas"}
+		}`, string(event.Changes))
+	})
+
+	t.Run("To Root", func(t *testing.T) {
+		mockRepo := &MockCategoryRepository{GetCategoryByIDFunc: getByID}
+
+		usecase := NewCategoryUsecase(mockRepo, &MockProductRepository{})
+		category, err := usecase.MoveCategory(context.Background(), 2, nil)
+
+		assert.NoError(t, err)
+		assert.Nil(t, category.ParentID)
+		assert.Equal(t, "camisetas", category.Path)
+	})
+
+	t.Run("Under Own Descendant", func(t *testing.T) {
+		mockRepo := &MockCategoryRepository{
+			GetCategoryByIDFunc: getByID,
+			UpdateCategoryFunc: func(category model.Category, oldPath string, event model.AuditEvent) error {
+				return repository.ErrCategoryCycle
+			},
+		}
+
+		usecase := NewCategoryUsecase(mockRepo, &MockProductRepository{})
+		_, err := usecase.MoveCategory(context.Background(), 1, intPtr(2))
+
+		assert.ErrorIs(t, err, ErrCategoryCycle)
+	})
+
+	t.Run("Changed Concurrently", func(t *testing.T) {
+		mockRepo := &MockCategoryRepository{
+			GetCategoryByIDFunc: getByID,
+			UpdateCategoryFunc: func(category model.Category, oldPath string, event model.AuditEvent) error {
+				return repository.ErrCategoryChanged
+			},
+		}
+
+		usecase := NewCategoryUsecase(mockRepo, &MockProductRepository{})
+		_, err := usecase.MoveCategory(context.Background(), 2, intPtr(3))
+
+		assert.ErrorIs(t, err, ErrCategoryChanged)
+	})
+}
+
+func TestCategoryUsecase_DeleteCategory(t *testing.T) {
+	t.Run("Has Children", func(t *testing.T) {
+		mockRepo := &MockCategoryRepository{
+			GetCategoryByIDFunc: func(id int) (*model.Category, error) {
+				return &model.Category{ID: id, Path: "roupas"}, nil
+			},
+			CountChildrenFunc: func(id int) (int, error) {
+				return 2, nil
+			},
+		}
+
+		usecase := NewCategoryUsecase(mockRepo, &MockProductRepository{})
+		err := usecase.DeleteCategory(context.Background(), 1)
+
+		assert.ErrorIs(t, err, ErrCategoryHasChildren)
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		usecase := NewCategoryUsecase(&MockCategoryRepository{}, &MockProductRepository{})
+		err := usecase.DeleteCategory(context.Background(), 1)
+
+		assert.ErrorIs(t, err, ErrCategoryNotFound)
+	})
+}
+
+func TestCategoryUsecase_SetProductCategories(t *testing.T) {
+	t.Run("Product Not Found", func(t *testing.T) {
+		usecase := NewCategoryUsecase(&MockCategoryRepository{}, &MockProductRepository{})
+		_, err := usecase.SetProductCategories(context.Background(), 1, []int{1})
+
+		assert.ErrorIs(t, err, ErrProductNotFound)
+	})
+
+	t.Run("Repository Error", func(t *testing.T) {
+		mockRepo := &MockCategoryRepository{
+			GetCategoryByIDFunc: func(id int) (*model.Category, error) {
+				return &model.Category{ID: id}, nil
+			},
+			SetProductCategoriesFunc: func(productID int, categoryIDs []int, event model.AuditEvent) error {
+				return errors.New("insert failed")
+			},
+		}
+		mockProductRepo := &MockProductRepository{
+			GetProductByIdFunc: func(id_product int) (*model.Product, error) {
+				return &model.Product{ID: id_product}, nil
+			},
+		}
+
+		usecase := NewCategoryUsecase(mockRepo, mockProductRepo)
+		_, err := usecase.SetProductCategories(context.Background(), 1, []int{1, 2})
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "insert failed")
+	})
+
+	t.Run("Records Assignment", func(t *testing.T) {
+		var event model.AuditEvent
+		mockRepo := &MockCategoryRepository{
+			GetCategoryByIDFunc: func(id int) (*model.Category, error) {
+				return &model.Category{ID: id}, nil
+			},
+			GetProductCategoriesFunc: func(productID int) ([]model.Category, error) {
+				return []model.Category{{ID: 4}}, nil
+			},
+			SetProductCategoriesFunc: func(productID int, categoryIDs []int, e model.AuditEvent) error {
+				event = e
+				return nil
+			},
+		}
+		mockProductRepo := &MockProductRepository{
+			GetProductByIdFunc: func(id_product int) (*model.Product, error) {
+				return &model.Product{ID: id_product}, nil
+			},
+		}
+
+		usecase := NewCategoryUsecase(mockRepo, mockProductRepo)
+		_, err := usecase.SetProductCategories(context.Background(), 1, []int{3, 2, 3})
+
+		assert.NoError(t, err)
+		assert.Equal(t, model.AuditEntityProduct, event.EntityType)
+		assert.Equal(t, "1", event.EntityID)
+		assert.JSONEq(t, `{"categories": {"before": "4", "after": "2,3"}}`, string(event.Changes))
+	})
+
+	t.Run("Product Trashed Meanwhile", func(t *testing.T) {
+		mockRepo := &MockCategoryRepository{
+			GetCategoryByIDFunc: func(id int) (*model.Category, error) {
+				return &model.Category{ID: id}, nil
+			},
+			SetProductCategoriesFunc: func(productID int, categoryIDs []int, event model.AuditEvent) error {
+				return sql.ErrNoRows
+			},
+		}
+		mockProductRepo := &MockProductRepository{
+			GetProductByIdFunc: func(id_product int) (*model.Product, error) {
+				return &model.Product{ID: id_product}, nil
+			},
+		}
+
+		usecase := NewCategoryUsecase(mockRepo, mockProductRepo)
+		_, err := usecase.SetProductCategories(context.Background(), 1, []int{1})
+
+		assert.ErrorIs(t, err, ErrProductNotFound)
+	})
+}
@@ -0,0 +1,356 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"go-api/internal/imaging"
+	"go-api/internal/storage"
+	"go-api/model"
+	"go-api/repository"
+)
+
+var (
+	ErrImageNotFound          = errors.New("image not found")
+	ErrImageTooLarge          = errors.New("image is too large")
+	ErrUnsupportedImageType   = errors.New("unsupported image type, use JPEG, PNG or GIF")
+	ErrInvalidImageDimensions = errors.New("invalid image dimensions")
+	ErrInvalidImageOrder      = errors.New("invalid image order")
+)
+
+// ImageLimits bounds what an upload may contain
+type ImageLimits struct {
+	// MaxBytes is the largest accepted file
+	MaxBytes int64
+	// MinSide and MaxSide bound the width and height in pixels
+	MinSide int
+	MaxSide int
+	// MaxPixels guards against images that are small on disk but huge once decoded
+	MaxPixels int
+}
+
+// DefaultImageLimits accepts files up to 10 MB and 8000 pixels per side
+var DefaultImageLimits = ImageLimits{
+	MaxBytes:  10 << 20,
+	MinSide:   50,
+	MaxSide:   8000,
+	MaxPixels: 40_000_000,
+}
+
+// thumbnailSizes are the renditions generated for every upload, by longest side
+var thumbnailSizes = []struct {
+	Name    string
+	MaxSide int
+}{
+	{"small", 150},
+	{"medium", 400},
+	{"large", 800},
+}
+
+const thumbnailQuality = 85
+
+// allowedImageTypes maps the sniffed content types to the file extension of the original
+var allowedImageTypes = map[string]string{
+	"image/jpeg": "jpg",
+	"image/png":  "png",
+	"image/gif":  "gif",
+}
+
+// ImageUsecase defines the contract for product images
+type ImageUsecase interface {
+	GetImages(productID int) ([]model.ProductImage, error)
+	UploadImage(productID int, data []byte) (*model.ProductImage, error)
+	SetPrimaryImage(productID, imageID int) ([]model.ProductImage, error)
+	ReorderImages(productID int, imageIDs []int) ([]model.ProductImage, error)
+	DeleteImage(productID, imageID int) error
+}
+
+type imageUsecaseImpl struct {
+	repository        repository.ImageRepositoryInterface
+	productRepository repository.ProductRepositoryInterface
+	store             storage.BlobStore
+	limits            ImageLimits
+}
+
+// NewImageUsecase creates a new instance of ImageUsecase
+func NewImageUsecase(repo repository.ImageRepositoryInterface, productRepo repository.ProductRepositoryInterface, store storage.BlobStore, limits ImageLimits) ImageUsecase {
+	return &imageUsecaseImpl{
+		repository:        repo,
+		productRepository: productRepo,
+		store:             store,
+		limits:            limits,
+	}
+}
+
+func (iu *imageUsecaseImpl) GetImages(productID int) ([]model.ProductImage, error) {
+	if err := iu.checkProduct(productID); err != nil {
+		return nil, err
+	}
+	return iu.getImages(productID)
+}
+
+// UploadImage validates the upload, renders its thumbnails and stores them
+// with the original. The content type is sniffed from the bytes, the name
+// and type sent by the client are not trusted
+func (iu *imageUsecaseImpl) UploadImage(productID int, data []byte) (*model.ProductImage, error) {
+	if err := iu.checkProduct(productID); err != nil {
+		return nil, err
+	}
+
+	if int64(len(data)) > iu.limits.MaxBytes {
+		return nil, fmt.Errorf("%w: the limit is %d bytes", ErrImageTooLarge, iu.limits.MaxBytes)
+	}
+	info, err := imaging.Inspect(data)
+	if errors.Is(err, imaging.ErrUnknownFormat) {
+		return nil, ErrUnsupportedImageType
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedImageType, err)
+	}
+	extension, ok := allowedImageTypes[info.ContentType]
+	if !ok {
+		return nil, ErrUnsupportedImageType
+	}
+	if err := iu.checkDimensions(info.Width, info.Height); err != nil {
+		return nil, err
+	}
+
+	decoded, err := imaging.Decode(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedImageType, err)
+	}
+
+	prefix, err := imagePrefix(productID)
+	if err != nil {
+		return nil, err
+	}
+	image := model.ProductImage{
+		ProductID:   productID,
+		Key:         prefix + "/original." + extension,
+		ContentType: info.ContentType,
+		Width:       info.Width,
+		Height:      info.Height,
+		Size:        int64(len(data)),
+	}
+
+	blobs := map[string][]byte{image.Key: data}
+	for _, size := range thumbnailSizes {
+		thumbnail := imaging.Thumbnail(decoded, size.MaxSide)
+		encoded, err := imaging.EncodeJPEG(thumbnail, thumbnailQuality)
+		if err != nil {
+			return nil, err
+		}
+		key := prefix + "/" + size.Name + ".jpg"
+		blobs[key] = encoded
+		image.Thumbnails = append(image.Thumbnails, model.ImageThumbnail{
+			Name:   size.Name,
+			Key:    key,
+			Width:  thumbnail.Bounds().Dx(),
+			Height: thumbnail.Bounds().Dy(),
+		})
+	}
+
+	ctx := context.Background()
+	var stored []string
+	for key, blob := range blobs {
+		contentType := "image/jpeg"
+		if key == image.Key {
+			contentType = image.ContentType
+		}
+		if err := iu.store.Put(ctx, key, bytes.NewReader(blob), int64(len(blob)), contentType); err != nil {
+			iu.deleteBlobs(stored)
+			return nil, err
+		}
+		stored = append(stored, key)
+	}
+
+	image, err = iu.repository.CreateImage(image)
+	if err != nil {
+		iu.deleteBlobs(stored)
+		return nil, err
+	}
+	images := []model.ProductImage{image}
+	if err := resolveImageURLs(iu.store, images); err != nil {
+		return nil, err
+	}
+	return &images[0], nil
+}
+
+// SetPrimaryImage makes the image the primary one of the product
+func (iu *imageUsecaseImpl) SetPrimaryImage(productID, imageID int) ([]model.ProductImage, error) {
+	if _, err := iu.findImage(productID, imageID); err != nil {
+		return nil, err
+	}
+	if err := iu.repository.SetPrimaryImage(productID, imageID); err != nil {
+		return nil, err
+	}
+	return iu.getImages(productID)
+}
+
+// ReorderImages takes every image of the product exactly once, in the new order
+func (iu *imageUsecaseImpl) ReorderImages(productID int, imageIDs []int) ([]model.ProductImage, error) {
+	if err := iu.checkProduct(productID); err != nil {
+		return nil, err
+	}
+	images, err := iu.repository.GetImages([]int{productID})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(imageIDs) != len(images) {
+		return nil, fmt.Errorf("%w: expected the %d images of the product", ErrInvalidImageOrder, len(images))
+	}
+	existing := make(map[int]bool, len(images))
+	for _, image := range images {
+		existing[image.ID] = true
+	}
+	seen := make(map[int]bool, len(imageIDs))
+	for _, id := range imageIDs {
+		if !existing[id] || seen[id] {
+			return nil, fmt.Errorf("%w: image %d is unknown or repeated", ErrInvalidImageOrder, id)
+		}
+		seen[id] = true
+	}
+
+	if err := iu.repository.ReorderImages(productID, imageIDs); err != nil {
+		return nil, err
+	}
+	return iu.getImages(productID)
+}
+
+// DeleteImage removes the image and its files. The row goes first so a
+// failure never leaves an image pointing at missing files
+func (iu *imageUsecaseImpl) DeleteImage(productID, imageID int) error {
+	image, err := iu.findImage(productID, imageID)
+	if err != nil {
+		return err
+	}
+	if err := iu.repository.DeleteImage(*image); err != nil {
+		return err
+	}
+	iu.deleteBlobs(imageKeys(*image))
+	return nil
+}
+
+// --- Helper Functions ---
+
+func (iu *imageUsecaseImpl) checkProduct(productID int) error {
+	product, err := iu.productRepository.GetProductById(productID)
+	if err != nil {
+		return err
+	}
+	if product == nil {
+		return ErrProductNotFound
+	}
+	return nil
+}
+
+func (iu *imageUsecaseImpl) findImage(productID, imageID int) (*model.ProductImage, error) {
+	if err := iu.checkProduct(productID); err != nil {
+		return nil, err
+	}
+	image, err := iu.repository.GetImageByID(imageID)
+	if err != nil {
+		return nil, err
+	}
+	if image == nil || image.ProductID != productID {
+		return nil, ErrImageNotFound
+	}
+	return image, nil
+}
+
+func (iu *imageUsecaseImpl) getImages(productID int) ([]model.ProductImage, error) {
+	images, err := iu.repository.GetImages([]int{productID})
+	if err != nil {
+		return nil, err
+	}
+	if err := resolveImageURLs(iu.store, images); err != nil {
+		return nil, err
+	}
+	return images, nil
+}
+
+func (iu *imageUsecaseImpl) checkDimensions(width, height int) error {
+	if width < iu.limits.MinSide || height < iu.limits.MinSide {
+		return fmt.Errorf("%w: images must be at least %dx%d pixels", ErrInvalidImageDimensions, iu.limits.MinSide, iu.limits.MinSide)
+	}
+	if width > iu.limits.MaxSide || height > iu.limits.MaxSide || width*height > iu.limits.MaxPixels {
+		return fmt.Errorf("%w: images must be at most %dx%d pixels and %d pixels in total",
+			ErrInvalidImageDimensions, iu.limits.MaxSide, iu.limits.MaxSide, iu.limits.MaxPixels)
+	}
+	return nil
+}
+
+// deleteBlobs is best effort: an orphaned file is harmless, unlike an image row without its file
+func (iu *imageUsecaseImpl) deleteBlobs(keys []string) {
+	for _, key := range keys {
+		_ = iu.store.Delete(context.Background(), key)
+	}
+}
+
+// imagePrefix returns a fresh key prefix so re-uploads never overwrite files that may be cached
+func imagePrefix(productID int) (string, error) {
+	token := make([]byte, 8)
+	if _, err := rand.Read(token); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("products/%d/%s", productID, hex.EncodeToString(token)), nil
+}
+
+func imageKeys(image model.ProductImage) []string {
+	keys := []string{image.Key}
+	for _, thumbnail := range image.Thumbnails {
+		keys = append(keys, thumbnail.Key)
+	}
+	return keys
+}
+
+// resolveImageURLs fills in the URLs of the images and their thumbnails,
+// signed when the store is private
+func resolveImageURLs(store storage.BlobStore, images []model.ProductImage) error {
+	for i := range images {
+		image := &images[i]
+		url, err := store.URL(image.Key)
+		if err != nil {
+			return err
+		}
+		image.URL = url
+		for j := range image.Thumbnails {
+			thumbnail := &image.Thumbnails[j]
+			if thumbnail.URL, err = store.URL(thumbnail.Key); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// attachImages loads the images of the products in one query
+func attachImages(repo repository.ImageRepositoryInterface, store storage.BlobStore, products []model.Product) error {
+	if len(products) == 0 {
+		return nil
+	}
+
+	productIDs := make([]int, 0, len(products))
+	index := make(map[int]int, len(products))
+	for i, product := range products {
+		productIDs = append(productIDs, product.ID)
+		index[product.ID] = i
+	}
+
+	images, err := repo.GetImages(productIDs)
+	if err != nil {
+		return err
+	}
+	if err := resolveImageURLs(store, images); err != nil {
+		return err
+	}
+	for _, image := range images {
+		product := &products[index[image.ProductID]]
+		product.Images = append(product.Images, image)
+	}
+	return nil
+}
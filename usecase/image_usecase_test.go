@@ -0,0 +1,248 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"go-api/internal/storage"
+	"go-api/model"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testPNG(t *testing.T, width, height int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 100, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+func testStore(t *testing.T) *storage.LocalStore {
+	store, err := storage.NewLocalStore(t.TempDir(), "http://localhost:8000/media", nil, time.Hour)
+	require.NoError(t, err)
+	return store
+}
+
+func imageProductRepo() *MockProductRepository {
+	return &MockProductRepository{
+		GetProductByIdFunc: func(id int) (*model.Product, error) {
+			if id != 1 {
+				return nil, nil
+			}
+			return &model.Product{ID: 1, Name: "Camiseta"}, nil
+		},
+	}
+}
+
+func TestImageUsecase_UploadImage(t *testing.T) {
+	t.Run("Stores Original And Thumbnails", func(t *testing.T) {
+		store := testStore(t)
+		var created model.ProductImage
+		mockRepo := &MockImageRepository{
+			CreateImageFunc: func(image model.ProductImage) (model.ProductImage, error) {
+				image.ID = 7
+				image.Primary = true
+				created = image
+				return image, nil
+			},
+		}
+
+		usecase := NewImageUsecase(mockRepo, imageProductRepo(), store, DefaultImageLimits)
+		result, err := usecase.UploadImage(1, testPNG(t, 1000, 500))
+
+		require.NoError(t, err)
+		assert.Equal(t, 7, result.ID)
+		assert.Equal(t, "image/png", created.ContentType)
+		assert.Equal(t, 1000, created.Width)
+		assert.Regexp(t, `^products/1/[0-9a-f]{16}/original\.png$`, created.Key)
+		assert.Equal(t, "http://localhost:8000/media/"+created.Key, result.URL)
+
+		require.Len(t, result.Thumbnails, 3)
+		assert.Equal(t, model.ImageThumbnail{Name: "small", Key: created.Thumbnails[0].Key, Width: 150, Height: 75, URL: "http://localhost:8000/media/" + created.Thumbnails[0].Key}, result.Thumbnails[0])
+		assert.Equal(t, 800, result.Thumbnails[2].Width)
+
+		for _, key := range imageKeys(created) {
+			reader, err := store.Open(context.Background(), key)
+			require.NoError(t, err, key)
+			reader.Close()
+		}
+	})
+
+	t.Run("Does Not Upscale Thumbnails", func(t *testing.T) {
+		usecase := NewImageUsecase(&MockImageRepository{}, imageProductRepo(), testStore(t), DefaultImageLimits)
+		result, err := usecase.UploadImage(1, testPNG(t, 300, 200))
+
+		require.NoError(t, err)
+		assert.Equal(t, 150, result.Thumbnails[0].Width)
+		assert.Equal(t, 300, result.Thumbnails[1].Width)
+		assert.Equal(t, 300, result.Thumbnails[2].Width)
+	})
+
+	t.Run("Rejects Non Images Regardless Of Name", func(t *testing.T) {
+		usecase := NewImageUsecase(&MockImageRepository{}, imageProductRepo(), testStore(t), DefaultImageLimits)
+		_, err := usecase.UploadImage(1, []byte("<?php echo 'hi'; ?>"))
+
+		assert.True(t, errors.Is(err, ErrUnsupportedImageType))
+	})
+
+	t.Run("Rejects Large Files", func(t *testing.T) {
+		limits := DefaultImageLimits
+		limits.MaxBytes = 100
+		usecase := NewImageUsecase(&MockImageRepository{}, imageProductRepo(), testStore(t), limits)
+		_, err := usecase.UploadImage(1, testPNG(t, 200, 200))
+
+		assert.True(t, errors.Is(err, ErrImageTooLarge))
+	})
+
+	t.Run("Rejects Bad Dimensions", func(t *testing.T) {
+		limits := DefaultImageLimits
+		limits.MaxSide = 500
+		usecase := NewImageUsecase(&MockImageRepository{}, imageProductRepo(), testStore(t), limits)
+
+		_, err := usecase.UploadImage(1, testPNG(t, 20, 20))
+		assert.True(t, errors.Is(err, ErrInvalidImageDimensions))
+
+		_, err = usecase.UploadImage(1, testPNG(t, 600, 100))
+		assert.True(t, errors.Is(err, ErrInvalidImageDimensions))
+	})
+
+	t.Run("Removes Files When Insert Fails", func(t *testing.T) {
+		store := testStore(t)
+		var keys []string
+		mockRepo := &MockImageRepository{
+			CreateImageFunc: func(image model.ProductImage) (model.ProductImage, error) {
+				keys = imageKeys(image)
+				return model.ProductImage{}, errors.New("db error")
+			},
+		}
+
+		usecase := NewImageUsecase(mockRepo, imageProductRepo(), store, DefaultImageLimits)
+		_, err := usecase.UploadImage(1, testPNG(t, 200, 200))
+
+		assert.Error(t, err)
+		require.Len(t, keys, 4)
+		for _, key := range keys {
+			_, err := store.Open(context.Background(), key)
+			assert.True(t, errors.Is(err, storage.ErrNotFound), key)
+		}
+	})
+
+	t.Run("Product Not Found", func(t *testing.T) {
+		usecase := NewImageUsecase(&MockImageRepository{}, imageProductRepo(), testStore(t), DefaultImageLimits)
+		_, err := usecase.UploadImage(2, testPNG(t, 200, 200))
+
+		assert.Equal(t, ErrProductNotFound, err)
+	})
+}
+
+func TestImageUsecase_ReorderImages(t *testing.T) {
+	images := []model.ProductImage{{ID: 1, ProductID: 1, Key: "a.png"}, {ID: 2, ProductID: 1, Key: "b.png"}, {ID: 3, ProductID: 1, Key: "c.png"}}
+	newRepo := func(reordered *[]int) *MockImageRepository {
+		return &MockImageRepository{
+			GetImagesFunc: func(productIDs []int) ([]model.ProductImage, error) {
+				return append([]model.ProductImage(nil), images...), nil
+			},
+			ReorderImagesFunc: func(productID int, imageIDs []int) error {
+				*reordered = imageIDs
+				return nil
+			},
+		}
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		var reordered []int
+		usecase := NewImageUsecase(newRepo(&reordered), imageProductRepo(), testStore(t), DefaultImageLimits)
+		_, err := usecase.ReorderImages(1, []int{3, 1, 2})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []int{3, 1, 2}, reordered)
+	})
+
+	t.Run("Requires Every Image Once", func(t *testing.T) {
+		var reordered []int
+		usecase := NewImageUsecase(newRepo(&reordered), imageProductRepo(), testStore(t), DefaultImageLimits)
+
+		for _, ids := range [][]int{{3, 1}, {3, 1, 1}, {3, 1, 9}} {
+			_, err := usecase.ReorderImages(1, ids)
+			assert.True(t, errors.Is(err, ErrInvalidImageOrder), ids)
+		}
+		assert.Nil(t, reordered)
+	})
+}
+
+func TestImageUsecase_DeleteImage(t *testing.T) {
+	t.Run("Deletes Row And Files", func(t *testing.T) {
+		store := testStore(t)
+		ctx := context.Background()
+		image := model.ProductImage{ID: 4, ProductID: 1, Key: "products/1/x/original.png",
+			Thumbnails: []model.ImageThumbnail{{Name: "small", Key: "products/1/x/small.jpg"}}}
+		for _, key := range imageKeys(image) {
+			require.NoError(t, store.Put(ctx, key, bytes.NewReader([]byte("x")), 1, ""))
+		}
+
+		var deleted *model.ProductImage
+		mockRepo := &MockImageRepository{
+			GetImageByIDFunc: func(id int) (*model.ProductImage, error) { return &image, nil },
+			DeleteImageFunc: func(image model.ProductImage) error {
+				deleted = &image
+				return nil
+			},
+		}
+
+		usecase := NewImageUsecase(mockRepo, imageProductRepo(), store, DefaultImageLimits)
+		err := usecase.DeleteImage(1, 4)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 4, deleted.ID)
+		for _, key := range imageKeys(image) {
+			_, err := store.Open(ctx, key)
+			assert.True(t, errors.Is(err, storage.ErrNotFound), key)
+		}
+	})
+
+	t.Run("Image Of Another Product", func(t *testing.T) {
+		mockRepo := &MockImageRepository{
+			GetImageByIDFunc: func(id int) (*model.ProductImage, error) {
+				return &model.ProductImage{ID: id, ProductID: 2}, nil
+			},
+		}
+
+		usecase := NewImageUsecase(mockRepo, imageProductRepo(), testStore(t), DefaultImageLimits)
+		err := usecase.DeleteImage(1, 4)
+
+		assert.Equal(t, ErrImageNotFound, err)
+	})
+}
+
+func TestProductUsecase_GetProductsWithImages(t *testing.T) {
+	mockRepo := &MockProductRepository{
+		GetProductsFunc: func(filter model.ProductFilter, asOf time.Time) ([]model.Product, error) {
+			return []model.Product{{ID: 1}, {ID: 2}}, nil
+		},
+	}
+	imageRepo := &MockImageRepository{
+		GetImagesFunc: func(productIDs []int) ([]model.ProductImage, error) {
+			assert.Equal(t, []int{1, 2}, productIDs)
+			return []model.ProductImage{{ID: 5, ProductID: 2, Key: "products/2/a/original.png", Primary: true}}, nil
+		},
+	}
+
+	usecase := NewProductUsecase(mockRepo, &MockPricingRepository{}, &MockVariantRepository{}, imageRepo, testStore(t))
+	products, err := usecase.GetProducts(model.ProductFilter{}, model.PriceOptions{})
+
+	assert.NoError(t, err)
+	assert.Empty(t, products[0].Images)
+	require.Len(t, products[1].Images, 1)
+	assert.Equal(t, "http://localhost:8000/media/products/2/a/original.png", products[1].Images[0].URL)
+}
@@ -0,0 +1,404 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"go-api/internal/audit"
+	"go-api/model"
+	"go-api/repository"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	ErrImportJobNotFound = errors.New("import job not found")
+	ErrImportTooLarge    = errors.New("import file is too large")
+	ErrEmptyImport       = errors.New("import file is empty")
+)
+
+const (
+	// ImportBatchSize is the number of rows matched and written per transaction
+	ImportBatchSize = 500
+	// MaxImportBytes caps the size of an import file
+	MaxImportBytes = 50 << 20
+	// importJobRetention is how long finished jobs stay available for polling
+	importJobRetention = 24 * time.Hour
+)
+
+// ImportRow is a row read from an import file. A row that failed validation
+// carries Err and is reported without stopping the import
+type ImportRow struct {
+	Line    int
+	Product model.Product
+	Err     error
+}
+
+// RowDecoder reads the rows of an import file one at a time; Next returns
+// io.EOF after the last row and any other error aborts the job
+type RowDecoder interface {
+	Next() (ImportRow, error)
+}
+
+// ImportOptions describes an import file and how to run it
+type ImportOptions struct {
+	Format string
+	DryRun bool
+	// NewDecoder parses the file; decoding lives with the request DTOs so rows
+	// are validated exactly like a single product creation
+	NewDecoder func(r io.Reader) (RowDecoder, error)
+}
+
+// ImportUsecase defines the contract for bulk product imports
+type ImportUsecase interface {
+	StartProductImport(ctx context.Context, body io.Reader, opts ImportOptions) (model.ImportJob, error)
+	GetImportJob(id string) (model.ImportJob, error)
+}
+
+type importUsecaseImpl struct {
+	productRepository repository.ProductRepositoryInterface
+	batchSize         int
+	maxBytes          int64
+
+	mu   sync.Mutex
+	jobs map[string]*model.ImportJob
+	// wg tracks running jobs so tests can wait for them
+	wg sync.WaitGroup
+}
+
+// NewImportUsecase creates a new instance of ImportUsecase. Jobs are kept in
+// memory, so they can only be polled on the instance that started them
+func NewImportUsecase(productRepo repository.ProductRepositoryInterface) ImportUsecase {
+	return &importUsecaseImpl{
+		productRepository: productRepo,
+		batchSize:         ImportBatchSize,
+		maxBytes:          MaxImportBytes,
+		jobs:              make(map[string]*model.ImportJob),
+	}
+}
+
+// StartProductImport spools the file to disk, so the request can finish,
+// and processes it in the background. The returned job is polled with GetImportJob
+func (iu *importUsecaseImpl) StartProductImport(ctx context.Context, body io.Reader, opts ImportOptions) (model.ImportJob, error) {
+	file, err := os.CreateTemp("", "product-import-*")
+	if err != nil {
+		return model.ImportJob{}, err
+	}
+	size, err := io.Copy(file, io.LimitReader(body, iu.maxBytes+1))
+	if err == nil {
+		_, err = file.Seek(0, io.SeekStart)
+	}
+	if err == nil && size > iu.maxBytes {
+		err = fmt.Errorf("%w: the limit is %d bytes", ErrImportTooLarge, iu.maxBytes)
+	}
+	if err == nil && size == 0 {
+		err = ErrEmptyImport
+	}
+	if err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return model.ImportJob{}, err
+	}
+
+	id, err := newImportJobID()
+	if err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return model.ImportJob{}, err
+	}
+	job := &model.ImportJob{
+		ID:         id,
+		Status:     model.ImportStatusPending,
+		Format:     opts.Format,
+		DryRun:     opts.DryRun,
+		TotalBytes: size,
+		Errors:     []model.ImportRowError{},
+		CreatedAt:  time.Now(),
+	}
+
+	iu.mu.Lock()
+	iu.pruneJobs(job.CreatedAt)
+	iu.jobs[id] = job
+	snapshot := snapshotJob(job)
+	iu.mu.Unlock()
+
+	// The job outlives the request but its writes are still audited as
+	// made by it
+	auditCtx := audit.NewContext(context.Background(), audit.FromContext(ctx))
+	iu.wg.Add(1)
+	go func() {
+		defer iu.wg.Done()
+		defer os.Remove(file.Name())
+		defer file.Close()
+		iu.run(auditCtx, job, file, opts)
+	}()
+	return snapshot, nil
+}
+
+func (iu *importUsecaseImpl) GetImportJob(id string) (model.ImportJob, error) {
+	iu.mu.Lock()
+	defer iu.mu.Unlock()
+	job, ok := iu.jobs[id]
+	if !ok {
+		return model.ImportJob{}, ErrImportJobNotFound
+	}
+	return snapshotJob(job), nil
+}
+
+// --- Helper Functions ---
+
+// importKey identifies the product a row upserts: the SKU when the row has
+// one, the name otherwise
+func importKey(product model.Product) string {
+	if product.SKU != "" {
+		return "sku:" + product.SKU
+	}
+	return "name:" + product.Name
+}
+
+func (iu *importUsecaseImpl) run(ctx context.Context, job *model.ImportJob, file io.Reader, opts ImportOptions) {
+	var read int64
+	counter := &countingReader{reader: file, count: &read}
+
+	iu.update(job, func() { job.Status = model.ImportStatusRunning })
+	decoder, err := opts.NewDecoder(counter)
+	if err != nil {
+		iu.finish(job, err)
+		return
+	}
+
+	seen := make(map[string]int)
+	batch := make([]ImportRow, 0, iu.batchSize)
+	for {
+		row, err := decoder.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			iu.finish(job, err)
+			return
+		}
+
+		if row.Err == nil {
+			row.Product.SKU = normalizeSKU(row.Product.SKU)
+			row.Product.Name = strings.TrimSpace(row.Product.Name)
+			key := importKey(row.Product)
+			if line, ok := seen[key]; ok {
+				row.Err = fmt.Errorf("duplicate of line %d", line)
+			} else {
+				seen[key] = row.Line
+			}
+		}
+
+		iu.update(job, func() {
+			job.Rows++
+			job.ReadBytes = atomic.LoadInt64(&read)
+			if row.Err != nil {
+				job.Failed++
+				job.Errors = append(job.Errors, model.ImportRowError{Line: row.Line, Message: row.Err.Error()})
+			}
+		})
+		if row.Err != nil {
+			continue
+		}
+
+		batch = append(batch, row)
+		if len(batch) == iu.batchSize {
+			iu.flush(ctx, job, batch, opts.DryRun)
+			batch = batch[:0]
+		}
+	}
+	if len(batch) > 0 {
+		iu.flush(ctx, job, batch, opts.DryRun)
+	}
+	iu.finish(job, nil)
+}
+
+// flush matches a batch against the existing products and writes it in one
+// transaction. A failed write rejects the whole batch but not the job
+func (iu *importUsecaseImpl) flush(ctx context.Context, job *model.ImportJob, rows []ImportRow, dryRun bool) {
+	plan, rejected, err := iu.planBatch(rows)
+	if err == nil && !dryRun {
+		var event model.AuditEvent
+		event, err = newAuditEvent(ctx, model.AuditActionImport, model.AuditEntityProductImport, job.ID, nil, importAuditFields(plan.batch))
+		if err == nil {
+			err = iu.productRepository.ImportProducts(plan.batch, event)
+		}
+	}
+
+	iu.update(job, func() {
+		job.Errors = append(job.Errors, rejected...)
+		job.Failed += len(rejected)
+		if err != nil {
+			for _, line := range plan.lines {
+				job.Errors = append(job.Errors, model.ImportRowError{Line: line, Message: "batch not saved: " + err.Error()})
+			}
+			job.Failed += len(plan.lines)
+			return
+		}
+		job.Created += len(plan.batch.Create)
+		job.Updated += len(plan.batch.Update)
+	})
+}
+
+type importPlan struct {
+	batch model.ProductImportBatch
+	// lines of the rows in the batch, to report them if the write fails
+	lines []int
+}
+
+// planBatch decides for each row whether it creates or updates a product
+func (iu *importUsecaseImpl) planBatch(rows []ImportRow) (importPlan, []model.ImportRowError, error) {
+	var plan importPlan
+	var skus, names []string
+	for _, row := range rows {
+		if row.Product.SKU != "" {
+			skus = append(skus, row.Product.SKU)
+		}
+		names = append(names, row.Product.Name)
+	}
+
+	existing, err := iu.productRepository.GetProductsBySKUOrName(skus, names)
+	if err != nil {
+		for _, row := range rows {
+			plan.lines = append(plan.lines, row.Line)
+		}
+		return plan, nil, err
+	}
+	bySKU := make(map[string]model.Product)
+	byName := make(map[string][]model.Product)
+	for _, product := range existing {
+		if product.SKU != "" {
+			bySKU[product.SKU] = product
+		}
+		byName[product.Name] = append(byName[product.Name], product)
+	}
+
+	var rejected []model.ImportRowError
+	for _, row := range rows {
+		product := row.Product
+		match, err := matchImportRow(product, bySKU, byName)
+		if err == nil && match != nil && match.Price.Currency != product.Price.Currency {
+			err = fmt.Errorf("currency %s differs from the product currency %s", product.Price.Currency, match.Price.Currency)
+		}
+		if err != nil {
+			rejected = append(rejected, model.ImportRowError{Line: row.Line, Message: err.Error()})
+			continue
+		}
+
+		plan.lines = append(plan.lines, row.Line)
+		if match == nil {
+			plan.batch.Create = append(plan.batch.Create, product)
+			continue
+		}
+		product.ID = match.ID
+		plan.batch.Update = append(plan.batch.Update, product)
+		if product.Price != match.Price {
+			plan.batch.Reprice = append(plan.batch.Reprice, product)
+		}
+	}
+	return plan, rejected, nil
+}
+
+// matchImportRow finds the product a row updates. A row with a SKU matches
+// that SKU, or else a product of the same name that has no SKU yet; a row
+// without SKU matches by name and must not be ambiguous
+func matchImportRow(product model.Product, bySKU map[string]model.Product, byName map[string][]model.Product) (*model.Product, error) {
+	if product.SKU != "" {
+		if match, ok := bySKU[product.SKU]; ok {
+			return &match, nil
+		}
+	}
+
+	var candidates []model.Product
+	for _, candidate := range byName[product.Name] {
+		if product.SKU == "" || candidate.SKU == "" {
+			candidates = append(candidates, candidate)
+		}
+	}
+	switch len(candidates) {
+	case 0:
+		return nil, nil
+	case 1:
+		return &candidates[0], nil
+	default:
+		return nil, fmt.Errorf("name matches %d products, add a sku to choose one", len(candidates))
+	}
+}
+
+func (iu *importUsecaseImpl) update(job *model.ImportJob, change func()) {
+	iu.mu.Lock()
+	defer iu.mu.Unlock()
+	change()
+}
+
+func (iu *importUsecaseImpl) finish(job *model.ImportJob, err error) {
+	iu.update(job, func() {
+		now := time.Now()
+		job.FinishedAt = &now
+		job.ReadBytes = job.TotalBytes
+		job.Status = model.ImportStatusCompleted
+		if err != nil {
+			job.Status = model.ImportStatusFailed
+			job.Error = err.Error()
+		}
+	})
+}
+
+// pruneJobs forgets jobs that finished long ago; the caller holds the lock
+func (iu *importUsecaseImpl) pruneJobs(now time.Time) {
+	for id, job := range iu.jobs {
+		if job.FinishedAt != nil && now.Sub(*job.FinishedAt) > importJobRetention {
+			delete(iu.jobs, id)
+		}
+	}
+}
+
+// snapshotJob copies the job so callers never share the slice being appended to
+// importAuditFields records a batch by the keys of its rows (see importKey)
+func importAuditFields(batch model.ProductImportBatch) map[string]interface{} {
+	keys := func(products []model.Product) []string {
+		result := make([]string, 0, len(products))
+		for _, product := range products {
+			result = append(result, importKey(product))
+		}
+		return result
+	}
+	return map[string]interface{}{
+		"created":  keys(batch.Create),
+		"updated":  keys(batch.Update),
+		"repriced": keys(batch.Reprice),
+	}
+}
+
+func snapshotJob(job *model.ImportJob) model.ImportJob {
+	snapshot := *job
+	snapshot.Errors = append([]model.ImportRowError{}, job.Errors...)
+	return snapshot
+}
+
+func newImportJobID() (string, error) {
+	id := make([]byte, 12)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(id), nil
+}
+
+// countingReader counts the bytes read so progress can be reported while parsing
+type countingReader struct {
+	reader io.Reader
+	count  *int64
+}
+
+func (cr *countingReader) Read(p []byte) (int, error) {
+	n, err := cr.reader.Read(p)
+	atomic.AddInt64(cr.count, int64(n))
+	return n, err
+}
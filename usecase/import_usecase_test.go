@@ -0,0 +1,202 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-api/model"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sliceDecoder yields fixed rows regardless of the file content
+type sliceDecoder struct {
+	rows []ImportRow
+}
+
+func (d *sliceDecoder) Next() (ImportRow, error) {
+	if len(d.rows) == 0 {
+		return ImportRow{}, io.EOF
+	}
+	row := d.rows[0]
+	d.rows = d.rows[1:]
+	return row, nil
+}
+
+func importOptions(dryRun bool, rows ...ImportRow) ImportOptions {
+	return ImportOptions{
+		Format: "csv",
+		DryRun: dryRun,
+		NewDecoder: func(r io.Reader) (RowDecoder, error) {
+			io.Copy(io.Discard, r)
+			return &sliceDecoder{rows: rows}, nil
+		},
+	}
+}
+
+func importRow(line int, name, sku string, cents int64) ImportRow {
+	return ImportRow{Line: line, Product: model.Product{Name: name, SKU: sku, Price: model.Money{Amount: cents, Currency: "BRL"}}}
+}
+
+func runImport(t *testing.T, repo *MockProductRepository, body string, opts ImportOptions) model.ImportJob {
+	usecase := NewImportUsecase(repo)
+	job, err := usecase.StartProductImport(context.Background(), strings.NewReader(body), opts)
+	require.NoError(t, err)
+
+	usecase.(*importUsecaseImpl).wg.Wait()
+	job, err = usecase.GetImportJob(job.ID)
+	require.NoError(t, err)
+	return job
+}
+
+func TestImportUsecase_StartProductImport(t *testing.T) {
+	existing := []model.Product{
+		{ID: 1, Name: "Camiseta", SKU: "CAM-01", Price: model.Money{Amount: 4990, Currency: "BRL"}},
+		{ID: 2, Name: "Caneca", Price: model.Money{Amount: 1990, Currency: "BRL"}},
+		{ID: 3, Name: "Boné", Price: model.Money{Amount: 2990, Currency: "BRL"}},
+		{ID: 4, Name: "Boné", Price: model.Money{Amount: 3990, Currency: "BRL"}},
+	}
+	rows := []ImportRow{
+		importRow(2, "Camiseta Azul", "cam-01", 5990),
+		importRow(3, "Caneca", "", 1990),
+		importRow(4, "Meia", "MEI-01", 990),
+		importRow(5, "Boné", "", 2990),
+		{Line: 6, Err: errors.New("price is required")},
+		importRow(7, "Outra Meia", "mei-01", 990),
+	}
+
+	t.Run("Upserts By SKU Or Name", func(t *testing.T) {
+		var batches []model.ProductImportBatch
+		repo := &MockProductRepository{
+			GetProductsBySKUOrNameFunc: func(skus, names []string) ([]model.Product, error) {
+				assert.Equal(t, []string{"CAM-01", "MEI-01"}, skus)
+				return existing, nil
+			},
+			ImportProductsFunc: func(batch model.ProductImportBatch, event model.AuditEvent) error {
+				batches = append(batches, batch)
+				return nil
+			},
+		}
+
+		job := runImport(t, repo, "name,price\n...", importOptions(false, rows...))
+
+		assert.Equal(t, model.ImportStatusCompleted, job.Status)
+		assert.Equal(t, 6, job.Rows)
+		assert.Equal(t, 1, job.Created)
+		assert.Equal(t, 2, job.Updated)
+		assert.Equal(t, 3, job.Failed)
+		assert.Equal(t, job.TotalBytes, job.ReadBytes)
+		assert.Equal(t, []model.ImportRowError{
+			{Line: 6, Message: "price is required"},
+			{Line: 7, Message: "duplicate of line 4"},
+			{Line: 5, Message: "name matches 2 products, add a sku to choose one"},
+		}, job.Errors)
+
+		require.Len(t, batches, 1)
+		assert.Equal(t, "MEI-01", batches[0].Create[0].SKU)
+		assert.Equal(t, 1, batches[0].Update[0].ID)
+		assert.Equal(t, "Camiseta Azul", batches[0].Update[0].Name)
+		assert.Equal(t, 2, batches[0].Update[1].ID)
+		require.Len(t, batches[0].Reprice, 1)
+		assert.Equal(t, int64(5990), batches[0].Reprice[0].Price.Amount)
+	})
+
+	t.Run("Dry Run Writes Nothing", func(t *testing.T) {
+		repo := &MockProductRepository{
+			GetProductsBySKUOrNameFunc: func(skus, names []string) ([]model.Product, error) {
+				return existing, nil
+			},
+			ImportProductsFunc: func(batch model.ProductImportBatch, event model.AuditEvent) error {
+				t.Fatal("dry run must not write")
+				return nil
+			},
+		}
+
+		job := runImport(t, repo, "x", importOptions(true, rows...))
+
+		assert.True(t, job.DryRun)
+		assert.Equal(t, 1, job.Created)
+		assert.Equal(t, 2, job.Updated)
+	})
+
+	t.Run("Writes In Batches", func(t *testing.T) {
+		var sizes []int
+		repo := &MockProductRepository{
+			ImportProductsFunc: func(batch model.ProductImportBatch, event model.AuditEvent) error {
+				sizes = append(sizes, len(batch.Create))
+				return nil
+			},
+		}
+
+		var many []ImportRow
+		for i := 0; i < ImportBatchSize+10; i++ {
+			many = append(many, importRow(i+2, fmt.Sprintf("Produto %d", i), "", 100))
+		}
+		job := runImport(t, repo, "x", importOptions(false, many...))
+
+		assert.Equal(t, []int{ImportBatchSize, 10}, sizes)
+		assert.Equal(t, ImportBatchSize+10, job.Created)
+		assert.Zero(t, job.Failed)
+	})
+
+	t.Run("Failed Batch Is Reported Per Row", func(t *testing.T) {
+		repo := &MockProductRepository{
+			ImportProductsFunc: func(batch model.ProductImportBatch, event model.AuditEvent) error {
+				return errors.New("db error")
+			},
+		}
+
+		job := runImport(t, repo, "x", importOptions(false, importRow(2, "Meia", "", 990), importRow(3, "Luva", "", 990)))
+
+		assert.Equal(t, model.ImportStatusCompleted, job.Status)
+		assert.Zero(t, job.Created)
+		assert.Equal(t, 2, job.Failed)
+		assert.Equal(t, "batch not saved: db error", job.Errors[0].Message)
+	})
+
+	t.Run("Rejects Currency Change", func(t *testing.T) {
+		repo := &MockProductRepository{
+			GetProductsBySKUOrNameFunc: func(skus, names []string) ([]model.Product, error) {
+				return existing[:1], nil
+			},
+		}
+		row := importRow(2, "Camiseta", "CAM-01", 990)
+		row.Product.Price.Currency = "USD"
+
+		job := runImport(t, repo, "x", importOptions(false, row))
+
+		assert.Equal(t, 1, job.Failed)
+		assert.Equal(t, "currency USD differs from the product currency BRL", job.Errors[0].Message)
+	})
+
+	t.Run("Decoder Error Fails The Job", func(t *testing.T) {
+		opts := ImportOptions{NewDecoder: func(r io.Reader) (RowDecoder, error) {
+			return nil, errors.New("missing column price")
+		}}
+
+		job := runImport(t, &MockProductRepository{}, "name\n", opts)
+
+		assert.Equal(t, model.ImportStatusFailed, job.Status)
+		assert.Equal(t, "missing column price", job.Error)
+		assert.NotNil(t, job.FinishedAt)
+	})
+
+	t.Run("Empty And Oversized Files", func(t *testing.T) {
+		usecase := NewImportUsecase(&MockProductRepository{})
+		_, err := usecase.StartProductImport(context.Background(), strings.NewReader(""), importOptions(false))
+		assert.Equal(t, ErrEmptyImport, err)
+
+		usecase.(*importUsecaseImpl).maxBytes = 4
+		_, err = usecase.StartProductImport(context.Background(), strings.NewReader("12345"), importOptions(false))
+		assert.True(t, errors.Is(err, ErrImportTooLarge))
+	})
+
+	t.Run("Unknown Job", func(t *testing.T) {
+		_, err := NewImportUsecase(&MockProductRepository{}).GetImportJob("nope")
+		assert.Equal(t, ErrImportJobNotFound, err)
+	})
+}
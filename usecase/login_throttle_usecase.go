@@ -0,0 +1,188 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"go-api/internal/audit"
+	"go-api/internal/throttle"
+	"go-api/model"
+	"go-api/repository"
+	"strings"
+	"time"
+)
+
+var (
+	ErrLoginThrottled  = errors.New("too many failed logins, try again later")
+	ErrNothingToUnlock = errors.New("email or ip is required")
+)
+
+// LoginThrottledError is returned while the account or the IP must wait
+// before trying to log in again; it matches ErrLoginThrottled
+type LoginThrottledError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LoginThrottledError) Error() string {
+	return ErrLoginThrottled.Error()
+}
+
+func (e *LoginThrottledError) Unwrap() error {
+	return ErrLoginThrottled
+}
+
+// LoginThrottlePolicy holds the limits of failed logins per account, which
+// stops guessing the password of one user, and per IP, which stops trying
+// many users from one address
+type LoginThrottlePolicy struct {
+	Account throttle.Policy
+	IP      throttle.Policy
+}
+
+// DefaultLoginThrottlePolicy lets an account fail 3 times in 15 minutes
+// before delays start, and locks it out for 15 minutes after 10 failures. An
+// IP, which may be shared by many users, gets 20 and 100
+var DefaultLoginThrottlePolicy = LoginThrottlePolicy{
+	Account: throttle.Policy{
+		Window:          15 * time.Minute,
+		FreeFailures:    3,
+		BaseDelay:       time.Second,
+		MaxDelay:        time.Minute,
+		LockoutFailures: 10,
+		LockoutDuration: 15 * time.Minute,
+	},
+	IP: throttle.Policy{
+		Window:          15 * time.Minute,
+		FreeFailures:    20,
+		BaseDelay:       time.Second,
+		MaxDelay:        time.Minute,
+		LockoutFailures: 100,
+		LockoutDuration: 15 * time.Minute,
+	},
+}
+
+// LoginThrottleUsecase defines the contract for throttling failed logins.
+// The IP is the one of the request, read from ctx
+type LoginThrottleUsecase interface {
+	Check(ctx context.Context, email string) error
+	Fail(ctx context.Context, email string) error
+	Succeed(ctx context.Context, email string) error
+	Unlock(ctx context.Context, email, ip string) error
+}
+
+type loginThrottleUsecaseImpl struct {
+	accounts *throttle.Limiter
+	ips      *throttle.Limiter
+	audit    repository.AuditRepositoryInterface
+}
+
+// NewLoginThrottleUsecase creates a new instance of LoginThrottleUsecase
+// counting failures in store and recording lockouts in the audit log
+func NewLoginThrottleUsecase(store throttle.Store, auditRepo repository.AuditRepositoryInterface, policy LoginThrottlePolicy) LoginThrottleUsecase {
+	return &loginThrottleUsecaseImpl{
+		accounts: throttle.NewLimiter(store, policy.Account),
+		ips:      throttle.NewLimiter(store, policy.IP),
+		audit:    auditRepo,
+	}
+}
+
+// Check returns a *LoginThrottledError while the account or the IP of the
+// request is blocked
+func (lu *loginThrottleUsecaseImpl) Check(ctx context.Context, email string) error {
+	var wait time.Duration
+	for _, limit := range lu.limits(ctx, email) {
+		keyWait, err := limit.limiter.Wait(ctx, limit.key)
+		if err != nil {
+			return err
+		}
+		wait = max(wait, keyWait)
+	}
+	if wait > 0 {
+		return &LoginThrottledError{RetryAfter: wait}
+	}
+	return nil
+}
+
+// Fail counts a failed login against the account and the IP, recording the
+// lockouts it causes
+func (lu *loginThrottleUsecaseImpl) Fail(ctx context.Context, email string) error {
+	for _, limit := range lu.limits(ctx, email) {
+		outcome, err := limit.limiter.Fail(ctx, limit.key)
+		if err != nil {
+			return err
+		}
+		if !outcome.Locked {
+			continue
+		}
+		event, err := newAuditEvent(ctx, model.AuditActionLockout, model.AuditEntityLogin, limit.key, nil,
+			map[string]interface{}{"failures": outcome.Failures, "locked_until": time.Now().Add(outcome.Wait).UTC()})
+		if err != nil {
+			return err
+		}
+		if err := lu.audit.RecordAuditEvent(event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Succeed forgets the failures of the account. Those of the IP stay, or one
+// valid account would let an address keep guessing the others
+func (lu *loginThrottleUsecaseImpl) Succeed(ctx context.Context, email string) error {
+	return lu.accounts.Reset(ctx, accountKey(email))
+}
+
+// Unlock lifts the lockout and forgets the failures of the account of email
+// and of ip, whichever are given
+func (lu *loginThrottleUsecaseImpl) Unlock(ctx context.Context, email, ip string) error {
+	var limits []loginLimit
+	if strings.TrimSpace(email) != "" {
+		limits = append(limits, loginLimit{lu.accounts, accountKey(email)})
+	}
+	if ip = strings.TrimSpace(ip); ip != "" {
+		limits = append(limits, loginLimit{lu.ips, ipKey(ip)})
+	}
+	if len(limits) == 0 {
+		return ErrNothingToUnlock
+	}
+
+	for _, limit := range limits {
+		if err := limit.limiter.Reset(ctx, limit.key); err != nil {
+			return err
+		}
+		event, err := newAuditEvent(ctx, model.AuditActionUnlock, model.AuditEntityLogin, limit.key, nil, nil)
+		if err != nil {
+			return err
+		}
+		if err := lu.audit.RecordAuditEvent(event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// --- Helper Functions ---
+
+// loginLimit is a key and the limiter that counts it
+type loginLimit struct {
+	limiter *throttle.Limiter
+	key     string
+}
+
+// limits returns the account of email and, when known, the IP of the request
+func (lu *loginThrottleUsecaseImpl) limits(ctx context.Context, email string) []loginLimit {
+	limits := []loginLimit{{lu.accounts, accountKey(email)}}
+	if ip := audit.FromContext(ctx).IP; ip != "" {
+		limits = append(limits, loginLimit{lu.ips, ipKey(ip)})
+	}
+	return limits
+}
+
+// accountKey ignores case and spaces around the email, so variations of it
+// share one counter
+func accountKey(email string) string {
+	return "account:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+func ipKey(ip string) string {
+	return "ip:" + ip
+}
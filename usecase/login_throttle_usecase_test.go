@@ -0,0 +1,84 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"go-api/dto"
+	"go-api/internal/audit"
+	"go-api/internal/throttle"
+	"go-api/model"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestLoginThrottleUsecase(t *testing.T) {
+	policy := LoginThrottlePolicy{
+		Account: throttle.Policy{Window: time.Hour, FreeFailures: 1, BaseDelay: time.Minute, MaxDelay: time.Hour, LockoutFailures: 3, LockoutDuration: time.Hour},
+		IP:      throttle.Policy{Window: time.Hour, FreeFailures: 10, BaseDelay: time.Minute, MaxDelay: time.Hour},
+	}
+	ctx := audit.NewContext(context.Background(), audit.Metadata{IP: "203.0.113.9"})
+
+	t.Run("Login Waits After Failures", func(t *testing.T) {
+		hash, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.DefaultCost)
+		mockRepo := &MockUserRepository{
+			GetUserByEmailFunc: func(email string) (*model.User, error) {
+				return &model.User{ID: 1, Email: email, Password: string(hash)}, nil
+			},
+		}
+		throttleUsecase := NewLoginThrottleUsecase(throttle.NewMemoryStore(), &MockAuditRepository{}, policy)
+		usecase := NewUserUsecase(mockRepo, testHasher, DefaultUserPolicy, nil, throttleUsecase)
+
+		wrong := dto.LoginRequest{Email: "ana@example.com", Password: "wrong"}
+		_, err := usecase.Login(ctx, wrong)
+		assert.True(t, errors.Is(err, ErrInvalidCredentials))
+		_, err = usecase.Login(ctx, wrong)
+		assert.True(t, errors.Is(err, ErrInvalidCredentials))
+
+		// Even the right password waits now
+		_, err = usecase.Login(ctx, dto.LoginRequest{Email: " ANA@example.com", Password: "password123"})
+		var throttled *LoginThrottledError
+		assert.True(t, errors.As(err, &throttled))
+		assert.True(t, errors.Is(err, ErrLoginThrottled))
+		assert.InDelta(t, time.Minute.Seconds(), throttled.RetryAfter.Seconds(), 5)
+
+		// Another account from the same IP still gets in
+		resp, err := usecase.Login(ctx, dto.LoginRequest{Email: "bia@example.com", Password: "password123"})
+		assert.NoError(t, err)
+		assert.NotEmpty(t, resp.Token)
+	})
+
+	t.Run("Records Lockout And Unlock", func(t *testing.T) {
+		var events []model.AuditEvent
+		auditRepo := &MockAuditRepository{
+			RecordAuditEventFunc: func(event model.AuditEvent) error {
+				events = append(events, event)
+				return nil
+			},
+		}
+		usecase := NewLoginThrottleUsecase(throttle.NewMemoryStore(), auditRepo, policy)
+
+		for i := 0; i < 3; i++ {
+			assert.NoError(t, usecase.Fail(ctx, "ana@example.com"))
+		}
+		assert.Len(t, events, 1)
+		assert.Equal(t, model.AuditActionLockout, events[0].Action)
+		assert.Equal(t, model.AuditEntityLogin, events[0].EntityType)
+		assert.Equal(t, "account:ana@example.com", events[0].EntityID)
+		assert.Equal(t, "203.0.113.9", events[0].IP)
+
+		assert.NoError(t, usecase.Unlock(context.Background(), "ana@example.com", ""))
+		assert.Equal(t, model.AuditActionUnlock, events[1].Action)
+		assert.NoError(t, usecase.Check(ctx, "ana@example.com"))
+	})
+
+	t.Run("Unlock Needs A Target", func(t *testing.T) {
+		usecase := NewLoginThrottleUsecase(throttle.NewMemoryStore(), &MockAuditRepository{}, policy)
+
+		err := usecase.Unlock(context.Background(), " ", "")
+
+		assert.True(t, errors.Is(err, ErrNothingToUnlock))
+	})
+}
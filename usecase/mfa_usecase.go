@@ -0,0 +1,346 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/base32"
+	"errors"
+	"go-api/dto"
+	"go-api/internal/secretbox"
+	"go-api/internal/totp"
+	"go-api/internal/util"
+	"go-api/model"
+	"go-api/repository"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidMFAToken   = errors.New("invalid or expired mfa token")
+	ErrInvalidMFACode    = errors.New("invalid two-factor authentication code")
+	ErrMFAAlreadyEnabled = errors.New("two-factor authentication is already enabled")
+	ErrMFANotEnabled     = errors.New("two-factor authentication is not enabled")
+	ErrMFANotPending     = errors.New("no two-factor authentication enrollment to confirm")
+)
+
+// mfaChallengeTTL is how long the challenge returned by a login waits for
+// the code
+const mfaChallengeTTL = 5 * time.Minute
+
+// recoveryCodeSize is the random bytes of a recovery code, 80 bits written
+// as 16 base32 characters
+const recoveryCodeSize = 10
+
+// MFAPolicy holds how the TOTP second factor works
+type MFAPolicy struct {
+	// Issuer names the service in the authenticator apps
+	Issuer string
+	// RecoveryCodes is how many recovery codes a user gets
+	RecoveryCodes int
+	// Skew is how many time steps before and after the current one are still
+	// accepted, for clocks running a little off
+	Skew int
+}
+
+// DefaultMFAPolicy gives 10 recovery codes and accepts the codes of the
+// previous and the next 30 seconds
+var DefaultMFAPolicy = MFAPolicy{
+	Issuer:        "go-api",
+	RecoveryCodes: 10,
+	Skew:          1,
+}
+
+// MFAUsecase defines the contract for two-factor authentication
+type MFAUsecase interface {
+	Enroll(ctx context.Context, userID int) (*dto.MFAEnrollResponse, error)
+	Confirm(ctx context.Context, userID int, code string) (*dto.MFARecoveryCodesResponse, error)
+	RegenerateRecoveryCodes(ctx context.Context, userID int, code string) (*dto.MFARecoveryCodesResponse, error)
+	Disable(ctx context.Context, userID int, code string) error
+	Verify(ctx context.Context, request dto.MFAVerifyRequest) (*dto.LoginResponse, error)
+}
+
+type mfaUsecaseImpl struct {
+	repository     repository.MFARepositoryInterface
+	userRepository repository.UserRepositoryInterface
+	box            *secretbox.Box
+	policy         MFAPolicy
+	carts          CartMerger
+	throttle       LoginThrottleUsecase
+}
+
+// NewMFAUsecase creates a new instance of MFAUsecase. The secrets are
+// encrypted with box; a throttle counts wrong codes as failed logins
+func NewMFAUsecase(repo repository.MFARepositoryInterface, userRepo repository.UserRepositoryInterface, box *secretbox.Box, policy MFAPolicy, carts CartMerger, throttle LoginThrottleUsecase) MFAUsecase {
+	return &mfaUsecaseImpl{
+		repository:     repo,
+		userRepository: userRepo,
+		box:            box,
+		policy:         policy,
+		carts:          carts,
+		throttle:       throttle,
+	}
+}
+
+// Enroll creates a new secret for the user, replacing one not confirmed
+// yet. It only takes effect once confirmed with a code
+func (mu *mfaUsecaseImpl) Enroll(ctx context.Context, userID int) (*dto.MFAEnrollResponse, error) {
+	user, err := mu.userRepository.GetUserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	sealed, err := mu.box.Seal(secret)
+	if err != nil {
+		return nil, err
+	}
+	if err := mu.repository.SavePendingMFA(userID, sealed); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrMFAAlreadyEnabled
+		}
+		return nil, err
+	}
+
+	return &dto.MFAEnrollResponse{
+		Secret:     totp.EncodeSecret(secret),
+		OTPAuthURI: totp.ProvisioningURI(mu.policy.Issuer, user.Email, secret),
+	}, nil
+}
+
+// Confirm turns two-factor authentication on with a code of the pending
+// secret, and returns the recovery codes
+func (mu *mfaUsecaseImpl) Confirm(ctx context.Context, userID int, code string) (*dto.MFARecoveryCodesResponse, error) {
+	user, mfa, err := mu.load(userID)
+	if err != nil {
+		return nil, err
+	}
+	if mfa == nil {
+		return nil, ErrMFANotPending
+	}
+	if mfa.ConfirmedAt != nil {
+		return nil, ErrMFAAlreadyEnabled
+	}
+
+	codes, hashes, err := newRecoveryCodes(mu.policy.RecoveryCodes)
+	if err != nil {
+		return nil, err
+	}
+	event, err := newAuditEvent(ctx, model.AuditActionMFAEnable, model.AuditEntityUser, strconv.Itoa(userID), nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	err = mu.guard(ctx, user.Email, func() error {
+		step, err := mu.validate(mfa, code)
+		if err != nil {
+			return err
+		}
+		if err := mu.repository.ConfirmMFA(userID, step, hashes, event); err != nil {
+			if err == sql.ErrNoRows {
+				return ErrInvalidMFACode
+			}
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &dto.MFARecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// RegenerateRecoveryCodes replaces the recovery codes of the user, used or
+// not, once a code proves the second factor
+func (mu *mfaUsecaseImpl) RegenerateRecoveryCodes(ctx context.Context, userID int, code string) (*dto.MFARecoveryCodesResponse, error) {
+	user, mfa, err := mu.loadEnabled(userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := mu.guard(ctx, user.Email, func() error { return mu.useCode(mfa, code) }); err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := newRecoveryCodes(mu.policy.RecoveryCodes)
+	if err != nil {
+		return nil, err
+	}
+	event, err := newAuditEvent(ctx, model.AuditActionMFARecoveryCodes, model.AuditEntityUser, strconv.Itoa(userID), nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := mu.repository.ReplaceRecoveryCodes(userID, hashes, event); err != nil {
+		return nil, err
+	}
+	return &dto.MFARecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// Disable turns two-factor authentication off. A stolen session alone is
+// not enough: it takes a code or a recovery code
+func (mu *mfaUsecaseImpl) Disable(ctx context.Context, userID int, code string) error {
+	user, mfa, err := mu.loadEnabled(userID)
+	if err != nil {
+		return err
+	}
+	if err := mu.guard(ctx, user.Email, func() error { return mu.useCode(mfa, code) }); err != nil {
+		return err
+	}
+
+	event, err := newAuditEvent(ctx, model.AuditActionMFADisable, model.AuditEntityUser, strconv.Itoa(userID), nil, nil)
+	if err != nil {
+		return err
+	}
+	if err := mu.repository.DisableMFA(userID, event); err != nil {
+		if err == sql.ErrNoRows {
+			return ErrMFANotEnabled
+		}
+		return err
+	}
+	return nil
+}
+
+// Verify finishes a login with two-factor authentication: the challenge of
+// the password step and a code, or a recovery code, give the access token
+func (mu *mfaUsecaseImpl) Verify(ctx context.Context, request dto.MFAVerifyRequest) (*dto.LoginResponse, error) {
+	userID, err := util.ParseChallengeToken(request.MFAToken)
+	if err != nil {
+		return nil, ErrInvalidMFAToken
+	}
+	// A challenge of a user gone, or who turned 2FA off since, is void
+	user, mfa, err := mu.loadEnabled(userID)
+	if errors.Is(err, ErrUserNotFound) || errors.Is(err, ErrMFANotEnabled) {
+		return nil, ErrInvalidMFAToken
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := mu.guard(ctx, user.Email, func() error { return mu.useCode(mfa, request.Code) }); err != nil {
+		return nil, err
+	}
+
+	token, err := util.GenerateToken(user.Email, user.ID, user.Role, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if mu.carts != nil && request.CartToken != "" {
+		if err := mu.carts.MergeCart(user.ID, request.CartToken); err != nil {
+			return nil, err
+		}
+	}
+
+	return &dto.LoginResponse{Token: token}, nil
+}
+
+// --- Helper Functions ---
+
+// load returns the user and their second factor, nil when never enrolled
+func (mu *mfaUsecaseImpl) load(userID int) (*model.User, *model.UserMFA, error) {
+	user, err := mu.userRepository.GetUserByID(userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if user == nil {
+		return nil, nil, ErrUserNotFound
+	}
+	mfa, err := mu.repository.GetMFA(userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return user, mfa, nil
+}
+
+// loadEnabled is load for a user with two-factor authentication on
+func (mu *mfaUsecaseImpl) loadEnabled(userID int) (*model.User, *model.UserMFA, error) {
+	user, mfa, err := mu.load(userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if mfa == nil || mfa.ConfirmedAt == nil {
+		return nil, nil, ErrMFANotEnabled
+	}
+	return user, mfa, nil
+}
+
+// guard runs check, which returns ErrInvalidMFACode for a wrong code, under
+// the login throttle of email: wrong codes count as failed logins, so a
+// six-digit code cannot be guessed by brute force
+func (mu *mfaUsecaseImpl) guard(ctx context.Context, email string, check func() error) error {
+	if mu.throttle == nil {
+		return check()
+	}
+	if err := mu.throttle.Check(ctx, email); err != nil {
+		return err
+	}
+	if err := check(); err != nil {
+		if errors.Is(err, ErrInvalidMFACode) {
+			if err := mu.throttle.Fail(ctx, email); err != nil {
+				return err
+			}
+		}
+		return err
+	}
+	return mu.throttle.Succeed(ctx, email)
+}
+
+// validate checks a code of the authenticator app and returns its time step
+func (mu *mfaUsecaseImpl) validate(mfa *model.UserMFA, code string) (int64, error) {
+	secret, err := mu.box.Open(mfa.SecretEncrypted)
+	if err != nil {
+		return 0, err
+	}
+	step, ok := totp.Validate(secret, strings.TrimSpace(code), time.Now(), mu.policy.Skew)
+	if !ok {
+		return 0, ErrInvalidMFACode
+	}
+	return step, nil
+}
+
+// useCode spends a code of the authenticator app, whose time step then no
+// longer works, or else a recovery code
+func (mu *mfaUsecaseImpl) useCode(mfa *model.UserMFA, code string) error {
+	step, err := mu.validate(mfa, code)
+	switch {
+	case err == nil:
+		err = mu.repository.UseTOTPStep(mfa.UserID, step)
+	case errors.Is(err, ErrInvalidMFACode):
+		err = mu.repository.UseRecoveryCode(mfa.UserID, hashSecretToken(normalizeRecoveryCode(code)))
+	}
+	if err == sql.ErrNoRows {
+		return ErrInvalidMFACode
+	}
+	return err
+}
+
+// newRecoveryCodes returns n recovery codes, formatted as XXXX-XXXX-XXXX-XXXX,
+// and the hashes the database stores in their place
+func newRecoveryCodes(n int) ([]string, []string, error) {
+	codes := make([]string, n)
+	hashes := make([]string, n)
+	for i := range codes {
+		raw := make([]byte, recoveryCodeSize)
+		if _, err := rand.Read(raw); err != nil {
+			return nil, nil, err
+		}
+		code := base32.StdEncoding.EncodeToString(raw)
+		codes[i] = code[0:4] + "-" + code[4:8] + "-" + code[8:12] + "-" + code[12:16]
+		hashes[i] = hashSecretToken(code)
+	}
+	return codes, hashes, nil
+}
+
+// normalizeRecoveryCode ignores case, dashes and spaces, as typed by a user
+func normalizeRecoveryCode(code string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' {
+			return -1
+		}
+		return r
+	}, strings.ToUpper(code))
+}
@@ -0,0 +1,249 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"go-api/dto"
+	"go-api/internal/secretbox"
+	"go-api/internal/throttle"
+	"go-api/internal/totp"
+	"go-api/internal/util"
+	"go-api/model"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMFAUsecase(t *testing.T) {
+	box, err := secretbox.New("test key")
+	assert.NoError(t, err)
+	secret := []byte("12345678901234567890")
+	sealed, err := box.Seal(secret)
+	assert.NoError(t, err)
+	confirmedAt := time.Now()
+
+	userRepo := &MockUserRepository{
+		GetUserByIDFunc: func(id int) (*model.User, error) {
+			return &model.User{ID: id, Email: "ana@example.com", Role: "admin"}, nil
+		},
+	}
+	enabled := func(userID int) (*model.UserMFA, error) {
+		return &model.UserMFA{UserID: userID, SecretEncrypted: sealed, ConfirmedAt: &confirmedAt}, nil
+	}
+
+	t.Run("Enroll Stores An Encrypted Secret", func(t *testing.T) {
+		var stored string
+		mfaRepo := &MockMFARepository{
+			SavePendingMFAFunc: func(userID int, secretEncrypted string) error {
+				stored = secretEncrypted
+				return nil
+			},
+		}
+
+		usecase := NewMFAUsecase(mfaRepo, userRepo, box, DefaultMFAPolicy, nil, nil)
+		resp, err := usecase.Enroll(context.Background(), 7)
+
+		assert.NoError(t, err)
+		opened, err := box.Open(stored)
+		assert.NoError(t, err)
+		assert.Equal(t, totp.EncodeSecret(opened), resp.Secret)
+		uri, err := url.Parse(resp.OTPAuthURI)
+		assert.NoError(t, err)
+		assert.Equal(t, "otpauth", uri.Scheme)
+		assert.Equal(t, resp.Secret, uri.Query().Get("secret"))
+		assert.Equal(t, "go-api", uri.Query().Get("issuer"))
+	})
+
+	t.Run("Enroll Twice Fails Once Confirmed", func(t *testing.T) {
+		mfaRepo := &MockMFARepository{
+			SavePendingMFAFunc: func(userID int, secretEncrypted string) error {
+				return sql.ErrNoRows
+			},
+		}
+
+		_, err := NewMFAUsecase(mfaRepo, userRepo, box, DefaultMFAPolicy, nil, nil).Enroll(context.Background(), 7)
+
+		assert.True(t, errors.Is(err, ErrMFAAlreadyEnabled))
+	})
+
+	t.Run("Confirm Returns The Recovery Codes", func(t *testing.T) {
+		var confirmedStep int64
+		var hashes []string
+		mfaRepo := &MockMFARepository{
+			GetMFAFunc: func(userID int) (*model.UserMFA, error) {
+				return &model.UserMFA{UserID: userID, SecretEncrypted: sealed}, nil
+			},
+			ConfirmMFAFunc: func(userID int, step int64, codeHashes []string, event model.AuditEvent) error {
+				assert.Equal(t, model.AuditActionMFAEnable, event.Action)
+				assert.Equal(t, "7", event.EntityID)
+				confirmedStep, hashes = step, codeHashes
+				return nil
+			},
+		}
+
+		step := totp.Step(time.Now())
+		usecase := NewMFAUsecase(mfaRepo, userRepo, box, DefaultMFAPolicy, nil, nil)
+		resp, err := usecase.Confirm(context.Background(), 7, totp.Code(secret, step))
+
+		assert.NoError(t, err)
+		assert.Equal(t, step, confirmedStep)
+		assert.Len(t, resp.RecoveryCodes, 10)
+		assert.Regexp(t, `^[A-Z2-7]{4}-[A-Z2-7]{4}-[A-Z2-7]{4}-[A-Z2-7]{4}$`, resp.RecoveryCodes[0])
+		assert.Equal(t, hashSecretToken(normalizeRecoveryCode(resp.RecoveryCodes[0])), hashes[0])
+	})
+
+	t.Run("Confirm Rejects A Wrong Code", func(t *testing.T) {
+		mfaRepo := &MockMFARepository{
+			GetMFAFunc: func(userID int) (*model.UserMFA, error) {
+				return &model.UserMFA{UserID: userID, SecretEncrypted: sealed}, nil
+			},
+			ConfirmMFAFunc: func(userID int, step int64, codeHashes []string, event model.AuditEvent) error {
+				t.Fatal("confirmed with a wrong code")
+				return nil
+			},
+		}
+
+		_, err := NewMFAUsecase(mfaRepo, userRepo, box, DefaultMFAPolicy, nil, nil).Confirm(context.Background(), 7, "000000")
+
+		assert.True(t, errors.Is(err, ErrInvalidMFACode))
+	})
+
+	t.Run("Verify Exchanges The Challenge For A Token", func(t *testing.T) {
+		mfaRepo := &MockMFARepository{GetMFAFunc: enabled}
+		challenge, err := util.GenerateChallengeToken(7, time.Minute)
+		assert.NoError(t, err)
+
+		usecase := NewMFAUsecase(mfaRepo, userRepo, box, DefaultMFAPolicy, nil, nil)
+		resp, err := usecase.Verify(context.Background(), dto.MFAVerifyRequest{MFAToken: challenge, Code: totp.Code(secret, totp.Step(time.Now()))})
+
+		assert.NoError(t, err)
+		claims, err := util.ParseToken(resp.Token)
+		assert.NoError(t, err)
+		assert.Equal(t, 7, claims.UserID)
+		assert.True(t, claims.MFA)
+	})
+
+	t.Run("Verify Accepts A Recovery Code Once", func(t *testing.T) {
+		used := map[string]bool{}
+		mfaRepo := &MockMFARepository{
+			GetMFAFunc: enabled,
+			UseRecoveryCodeFunc: func(userID int, codeHash string) error {
+				if codeHash != hashSecretToken("ABCDEFGHIJKLMNOP") || used[codeHash] {
+					return sql.ErrNoRows
+				}
+				used[codeHash] = true
+				return nil
+			},
+		}
+		challenge, _ := util.GenerateChallengeToken(7, time.Minute)
+		usecase := NewMFAUsecase(mfaRepo, userRepo, box, DefaultMFAPolicy, nil, nil)
+
+		_, err := usecase.Verify(context.Background(), dto.MFAVerifyRequest{MFAToken: challenge, Code: "abcd-efgh-ijkl-mnop"})
+		assert.NoError(t, err)
+
+		_, err = usecase.Verify(context.Background(), dto.MFAVerifyRequest{MFAToken: challenge, Code: "ABCD-EFGH-IJKL-MNOP"})
+		assert.True(t, errors.Is(err, ErrInvalidMFACode))
+	})
+
+	t.Run("Verify Rejects A Replayed Code", func(t *testing.T) {
+		mfaRepo := &MockMFARepository{
+			GetMFAFunc: enabled,
+			UseTOTPStepFunc: func(userID int, step int64) error {
+				return sql.ErrNoRows
+			},
+		}
+		challenge, _ := util.GenerateChallengeToken(7, time.Minute)
+
+		_, err := NewMFAUsecase(mfaRepo, userRepo, box, DefaultMFAPolicy, nil, nil).
+			Verify(context.Background(), dto.MFAVerifyRequest{MFAToken: challenge, Code: totp.Code(secret, totp.Step(time.Now()))})
+
+		assert.True(t, errors.Is(err, ErrInvalidMFACode))
+	})
+
+	t.Run("Verify Rejects Access Tokens And Disabled 2FA", func(t *testing.T) {
+		usecase := NewMFAUsecase(&MockMFARepository{}, userRepo, box, DefaultMFAPolicy, nil, nil)
+
+		token, _ := util.GenerateToken("ana@example.com", 7, "admin", false)
+		_, err := usecase.Verify(context.Background(), dto.MFAVerifyRequest{MFAToken: token, Code: "123456"})
+		assert.True(t, errors.Is(err, ErrInvalidMFAToken))
+
+		challenge, _ := util.GenerateChallengeToken(7, time.Minute)
+		_, err = usecase.Verify(context.Background(), dto.MFAVerifyRequest{MFAToken: challenge, Code: "123456"})
+		assert.True(t, errors.Is(err, ErrInvalidMFAToken))
+	})
+
+	t.Run("Wrong Codes Are Throttled", func(t *testing.T) {
+		policy := LoginThrottlePolicy{
+			Account: throttle.Policy{Window: time.Hour, FreeFailures: 1, BaseDelay: time.Minute, MaxDelay: time.Hour},
+			IP:      throttle.Policy{Window: time.Hour, FreeFailures: 10, BaseDelay: time.Minute, MaxDelay: time.Hour},
+		}
+		throttleUsecase := NewLoginThrottleUsecase(throttle.NewMemoryStore(), &MockAuditRepository{}, policy)
+		mfaRepo := &MockMFARepository{
+			GetMFAFunc: enabled,
+			UseRecoveryCodeFunc: func(userID int, codeHash string) error {
+				return sql.ErrNoRows
+			},
+		}
+		challenge, _ := util.GenerateChallengeToken(7, time.Minute)
+		usecase := NewMFAUsecase(mfaRepo, userRepo, box, DefaultMFAPolicy, nil, throttleUsecase)
+
+		for i := 0; i < 2; i++ {
+			_, err := usecase.Verify(context.Background(), dto.MFAVerifyRequest{MFAToken: challenge, Code: "000000"})
+			assert.True(t, errors.Is(err, ErrInvalidMFACode))
+		}
+		_, err := usecase.Verify(context.Background(), dto.MFAVerifyRequest{MFAToken: challenge, Code: totp.Code(secret, totp.Step(time.Now()))})
+		assert.True(t, errors.Is(err, ErrLoginThrottled))
+	})
+
+	t.Run("Disable Needs A Code", func(t *testing.T) {
+		disabled := false
+		mfaRepo := &MockMFARepository{
+			GetMFAFunc: enabled,
+			UseRecoveryCodeFunc: func(userID int, codeHash string) error {
+				return sql.ErrNoRows
+			},
+			DisableMFAFunc: func(userID int, event model.AuditEvent) error {
+				assert.Equal(t, model.AuditActionMFADisable, event.Action)
+				disabled = true
+				return nil
+			},
+		}
+		usecase := NewMFAUsecase(mfaRepo, userRepo, box, DefaultMFAPolicy, nil, nil)
+
+		err := usecase.Disable(context.Background(), 7, "000000")
+		assert.True(t, errors.Is(err, ErrInvalidMFACode))
+		assert.False(t, disabled)
+
+		err = usecase.Disable(context.Background(), 7, totp.Code(secret, totp.Step(time.Now())))
+		assert.NoError(t, err)
+		assert.True(t, disabled)
+	})
+
+	t.Run("Disable Without 2FA", func(t *testing.T) {
+		err := NewMFAUsecase(&MockMFARepository{}, userRepo, box, DefaultMFAPolicy, nil, nil).Disable(context.Background(), 7, "123456")
+
+		assert.True(t, errors.Is(err, ErrMFANotEnabled))
+	})
+
+	t.Run("Regenerate Replaces The Recovery Codes", func(t *testing.T) {
+		var hashes []string
+		mfaRepo := &MockMFARepository{
+			GetMFAFunc: enabled,
+			ReplaceRecoveryCodesFunc: func(userID int, codeHashes []string, event model.AuditEvent) error {
+				assert.Equal(t, model.AuditActionMFARecoveryCodes, event.Action)
+				hashes = codeHashes
+				return nil
+			},
+		}
+
+		resp, err := NewMFAUsecase(mfaRepo, userRepo, box, DefaultMFAPolicy, nil, nil).
+			RegenerateRecoveryCodes(context.Background(), 7, totp.Code(secret, totp.Step(time.Now())))
+
+		assert.NoError(t, err)
+		assert.Len(t, resp.RecoveryCodes, 10)
+		assert.Len(t, hashes, 10)
+	})
+}
@@ -1,26 +1,49 @@
 package usecase
 
 import (
+	"context"
 	"go-api/model"
+	"time"
 )
 
 // MockProductRepository é um mock do ProductRepository para testes do usecase
 type MockProductRepository struct {
-	GetProductsFunc    func() ([]model.Product, error)
-	CreateProductFunc  func(product model.Product) (int, error)
-	GetProductByIdFunc func(id_product int) (*model.Product, error)
+	GetProductsFunc             func(filter model.ProductFilter, asOf time.Time) ([]model.Product, error)
+	ExportProductsFunc          func(ctx context.Context, filter model.ProductFilter, asOf time.Time, fn func(model.Product) error) error
+	CreateProductFunc           func(product model.Product, event model.AuditEvent) (int, error)
+	GetProductByIdFunc          func(id_product int) (*model.Product, error)
+	GetProductByIdAsOfFunc      func(id_product int, asOf time.Time) (*model.Product, error)
+	GetProductPricesFunc        func(id_product int) ([]model.ProductPrice, error)
+	CreateProductPriceFunc      func(price model.ProductPrice, event model.AuditEvent) (int, error)
+	GetProductBySKUFunc         func(sku string) (*model.Product, error)
+	GetProductsBySKUOrNameFunc  func(skus, names []string) ([]model.Product, error)
+	ImportProductsFunc          func(batch model.ProductImportBatch, event model.AuditEvent) error
+	DeleteProductFunc           func(id_product int, event model.AuditEvent) error
+	GetDeletedProductsFunc      func() ([]model.Product, error)
+	GetDeletedProductByIDFunc   func(id_product int) (*model.Product, error)
+	RestoreProductFunc          func(id_product int, event model.AuditEvent) error
+	PurgeProductFunc            func(id_product int, event model.AuditEvent) error
+	SetProductTaxCategoryFunc   func(id_product int, category string, event model.AuditEvent) error
+	GetProductTaxCategoriesFunc func(ids []int) (map[int]string, error)
 }
 
-func (m *MockProductRepository) GetProducts() ([]model.Product, error) {
+func (m *MockProductRepository) GetProducts(filter model.ProductFilter, asOf time.Time) ([]model.Product, error) {
 	if m.GetProductsFunc != nil {
-		return m.GetProductsFunc()
+		return m.GetProductsFunc(filter, asOf)
 	}
 	return nil, nil
 }
 
-func (m *MockProductRepository) CreateProduct(product model.Product) (int, error) {
+func (m *MockProductRepository) ExportProducts(ctx context.Context, filter model.ProductFilter, asOf time.Time, fn func(model.Product) error) error {
+	if m.ExportProductsFunc != nil {
+		return m.ExportProductsFunc(ctx, filter, asOf, fn)
+	}
+	return nil
+}
+
+func (m *MockProductRepository) CreateProduct(product model.Product, event model.AuditEvent) (int, error) {
 	if m.CreateProductFunc != nil {
-		return m.CreateProductFunc(product)
+		return m.CreateProductFunc(product, event)
 	}
 	return 0, nil
 }
@@ -32,19 +55,122 @@ func (m *MockProductRepository) GetProductById(id_product int) (*model.Product,
 	return nil, nil
 }
 
+// GetProductByIdAsOf falls back to GetProductByIdFunc so tests only need one of them
+func (m *MockProductRepository) GetProductByIdAsOf(id_product int, asOf time.Time) (*model.Product, error) {
+	if m.GetProductByIdAsOfFunc != nil {
+		return m.GetProductByIdAsOfFunc(id_product, asOf)
+	}
+	return m.GetProductById(id_product)
+}
+
+func (m *MockProductRepository) GetProductPrices(id_product int) ([]model.ProductPrice, error) {
+	if m.GetProductPricesFunc != nil {
+		return m.GetProductPricesFunc(id_product)
+	}
+	return nil, nil
+}
+
+func (m *MockProductRepository) CreateProductPrice(price model.ProductPrice, event model.AuditEvent) (int, error) {
+	if m.CreateProductPriceFunc != nil {
+		return m.CreateProductPriceFunc(price, event)
+	}
+	return 0, nil
+}
+
+func (m *MockProductRepository) GetProductBySKU(sku string) (*model.Product, error) {
+	if m.GetProductBySKUFunc != nil {
+		return m.GetProductBySKUFunc(sku)
+	}
+	return nil, nil
+}
+
+func (m *MockProductRepository) GetProductsBySKUOrName(skus, names []string) ([]model.Product, error) {
+	if m.GetProductsBySKUOrNameFunc != nil {
+		return m.GetProductsBySKUOrNameFunc(skus, names)
+	}
+	return nil, nil
+}
+
+func (m *MockProductRepository) ImportProducts(batch model.ProductImportBatch, event model.AuditEvent) error {
+	if m.ImportProductsFunc != nil {
+		return m.ImportProductsFunc(batch, event)
+	}
+	return nil
+}
+
+func (m *MockProductRepository) DeleteProduct(id_product int, event model.AuditEvent) error {
+	if m.DeleteProductFunc != nil {
+		return m.DeleteProductFunc(id_product, event)
+	}
+	return nil
+}
+
+func (m *MockProductRepository) GetDeletedProducts() ([]model.Product, error) {
+	if m.GetDeletedProductsFunc != nil {
+		return m.GetDeletedProductsFunc()
+	}
+	return nil, nil
+}
+
+func (m *MockProductRepository) GetDeletedProductByID(id_product int) (*model.Product, error) {
+	if m.GetDeletedProductByIDFunc != nil {
+		return m.GetDeletedProductByIDFunc(id_product)
+	}
+	return nil, nil
+}
+
+func (m *MockProductRepository) RestoreProduct(id_product int, event model.AuditEvent) error {
+	if m.RestoreProductFunc != nil {
+		return m.RestoreProductFunc(id_product, event)
+	}
+	return nil
+}
+
+func (m *MockProductRepository) PurgeProduct(id_product int, event model.AuditEvent) error {
+	if m.PurgeProductFunc != nil {
+		return m.PurgeProductFunc(id_product, event)
+	}
+	return nil
+}
+
+func (m *MockProductRepository) SetProductTaxCategory(id_product int, category string, event model.AuditEvent) error {
+	if m.SetProductTaxCategoryFunc != nil {
+		return m.SetProductTaxCategoryFunc(id_product, category, event)
+	}
+	return nil
+}
+
+func (m *MockProductRepository) GetProductTaxCategories(ids []int) (map[int]string, error) {
+	if m.GetProductTaxCategoriesFunc != nil {
+		return m.GetProductTaxCategoriesFunc(ids)
+	}
+	return map[int]string{}, nil
+}
+
 // MockUserRepository é um mock do UserRepository para testes do usecase
 type MockUserRepository struct {
-	CreateUserFunc     func(user model.User) (int, error)
-	GetUserByIDFunc    func(id int) (*model.User, error)
-	GetUserByEmailFunc func(email string) (*model.User, error)
-	UpdateUserFunc     func(user model.User) error
-	DeleteUserFunc     func(id int) error
-	GetUsersFunc       func() ([]model.User, error)
+	CreateUserFunc             func(user model.User, event model.AuditEvent) (int, error)
+	GetUserByIDFunc            func(id int) (*model.User, error)
+	GetUserByEmailFunc         func(email string) (*model.User, error)
+	UpdateUserFunc             func(user model.User, event model.AuditEvent) error
+	DeleteUserFunc             func(id int, event model.AuditEvent) error
+	GetUsersFunc               func(filter model.UserFilter) ([]model.User, error)
+	ExportUsersFunc            func(ctx context.Context, filter model.UserFilter, fn func(model.User) error) error
+	GetDeletedUsersFunc        func() ([]model.User, error)
+	GetDeletedUserByIDFunc     func(id int) (*model.User, error)
+	GetDeletedUserByEmailFunc  func(email string) (*model.User, error)
+	RestoreUserFunc            func(id int, event model.AuditEvent) error
+	PurgeUserFunc              func(id int, event model.AuditEvent) error
+	QueueVerificationEmailFunc func(id int, resendInterval time.Duration, email model.OutboxEmail) (bool, error)
+	QueueSignupNoticeFunc      func(id int, interval time.Duration, email model.OutboxEmail) (bool, error)
+	MarkEmailVerifiedFunc      func(id int, email string, event model.AuditEvent) error
+	ConfirmEmailChangeFunc     func(id int, email string, event model.AuditEvent) error
+	UpdatePasswordHashFunc     func(id int, oldHash, newHash string) error
 }
 
-func (m *MockUserRepository) CreateUser(user model.User) (int, error) {
+func (m *MockUserRepository) CreateUser(user model.User, event model.AuditEvent) (int, error) {
 	if m.CreateUserFunc != nil {
-		return m.CreateUserFunc(user)
+		return m.CreateUserFunc(user, event)
 	}
 	return 0, nil
 }
@@ -63,23 +189,1032 @@ func (m *MockUserRepository) GetUserByEmail(email string) (*model.User, error) {
 	return nil, nil
 }
 
-func (m *MockUserRepository) UpdateUser(user model.User) error {
+func (m *MockUserRepository) UpdateUser(user model.User, event model.AuditEvent) error {
 	if m.UpdateUserFunc != nil {
-		return m.UpdateUserFunc(user)
+		return m.UpdateUserFunc(user, event)
 	}
 	return nil
 }
 
-func (m *MockUserRepository) DeleteUser(id int) error {
+func (m *MockUserRepository) DeleteUser(id int, event model.AuditEvent) error {
 	if m.DeleteUserFunc != nil {
-		return m.DeleteUserFunc(id)
+		return m.DeleteUserFunc(id, event)
 	}
 	return nil
 }
 
-func (m *MockUserRepository) GetUsers() ([]model.User, error) {
+func (m *MockUserRepository) GetUsers(filter model.UserFilter) ([]model.User, error) {
 	if m.GetUsersFunc != nil {
-		return m.GetUsersFunc()
+		return m.GetUsersFunc(filter)
+	}
+	return nil, nil
+}
+
+func (m *MockUserRepository) ExportUsers(ctx context.Context, filter model.UserFilter, fn func(model.User) error) error {
+	if m.ExportUsersFunc != nil {
+		return m.ExportUsersFunc(ctx, filter, fn)
+	}
+	return nil
+}
+
+func (m *MockUserRepository) GetDeletedUsers() ([]model.User, error) {
+	if m.GetDeletedUsersFunc != nil {
+		return m.GetDeletedUsersFunc()
+	}
+	return nil, nil
+}
+
+func (m *MockUserRepository) GetDeletedUserByID(id int) (*model.User, error) {
+	if m.GetDeletedUserByIDFunc != nil {
+		return m.GetDeletedUserByIDFunc(id)
+	}
+	return nil, nil
+}
+
+func (m *MockUserRepository) GetDeletedUserByEmail(email string) (*model.User, error) {
+	if m.GetDeletedUserByEmailFunc != nil {
+		return m.GetDeletedUserByEmailFunc(email)
+	}
+	return nil, nil
+}
+
+func (m *MockUserRepository) RestoreUser(id int, event model.AuditEvent) error {
+	if m.RestoreUserFunc != nil {
+		return m.RestoreUserFunc(id, event)
+	}
+	return nil
+}
+
+func (m *MockUserRepository) PurgeUser(id int, event model.AuditEvent) error {
+	if m.PurgeUserFunc != nil {
+		return m.PurgeUserFunc(id, event)
+	}
+	return nil
+}
+
+func (m *MockUserRepository) QueueVerificationEmail(id int, resendInterval time.Duration, email model.OutboxEmail) (bool, error) {
+	if m.QueueVerificationEmailFunc != nil {
+		return m.QueueVerificationEmailFunc(id, resendInterval, email)
+	}
+	return true, nil
+}
+
+func (m *MockUserRepository) QueueSignupNotice(id int, interval time.Duration, email model.OutboxEmail) (bool, error) {
+	if m.QueueSignupNoticeFunc != nil {
+		return m.QueueSignupNoticeFunc(id, interval, email)
+	}
+	return true, nil
+}
+
+func (m *MockUserRepository) MarkEmailVerified(id int, email string, event model.AuditEvent) error {
+	if m.MarkEmailVerifiedFunc != nil {
+		return m.MarkEmailVerifiedFunc(id, email, event)
+	}
+	return nil
+}
+
+func (m *MockUserRepository) ConfirmEmailChange(id int, email string, event model.AuditEvent) error {
+	if m.ConfirmEmailChangeFunc != nil {
+		return m.ConfirmEmailChangeFunc(id, email, event)
+	}
+	return nil
+}
+
+func (m *MockUserRepository) UpdatePasswordHash(id int, oldHash, newHash string) error {
+	if m.UpdatePasswordHashFunc != nil {
+		return m.UpdatePasswordHashFunc(id, oldHash, newHash)
+	}
+	return nil
+}
+
+// MockCategoryRepository é um mock do CategoryRepository para testes do usecase
+type MockCategoryRepository struct {
+	GetCategoriesFunc        func() ([]model.Category, error)
+	GetCategoryByIDFunc      func(id int) (*model.Category, error)
+	GetCategoryByPathFunc    func(path string) (*model.Category, error)
+	GetDescendantsFunc       func(path string) ([]model.Category, error)
+	CountChildrenFunc        func(id int) (int, error)
+	CreateCategoryFunc       func(category model.Category, event model.AuditEvent) (int, error)
+	UpdateCategoryFunc       func(category model.Category, oldPath string, event model.AuditEvent) error
+	DeleteCategoryFunc       func(id int, event model.AuditEvent) error
+	SetProductCategoriesFunc func(productID int, categoryIDs []int, event model.AuditEvent) error
+	GetProductCategoriesFunc func(productID int) ([]model.Category, error)
+}
+
+func (m *MockCategoryRepository) GetCategories() ([]model.Category, error) {
+	if m.GetCategoriesFunc != nil {
+		return m.GetCategoriesFunc()
+	}
+	return nil, nil
+}
+
+func (m *MockCategoryRepository) GetCategoryByID(id int) (*model.Category, error) {
+	if m.GetCategoryByIDFunc != nil {
+		return m.GetCategoryByIDFunc(id)
+	}
+	return nil, nil
+}
+
+func (m *MockCategoryRepository) GetCategoryByPath(path string) (*model.Category, error) {
+	if m.GetCategoryByPathFunc != nil {
+		return m.GetCategoryByPathFunc(path)
+	}
+	return nil, nil
+}
+
+func (m *MockCategoryRepository) GetDescendants(path string) ([]model.Category, error) {
+	if m.GetDescendantsFunc != nil {
+		return m.GetDescendantsFunc(path)
+	}
+	return nil, nil
+}
+
+func (m *MockCategoryRepository) CountChildren(id int) (int, error) {
+	if m.CountChildrenFunc != nil {
+		return m.CountChildrenFunc(id)
+	}
+	return 0, nil
+}
+
+func (m *MockCategoryRepository) CreateCategory(category model.Category, event model.AuditEvent) (int, error) {
+	if m.CreateCategoryFunc != nil {
+		return m.CreateCategoryFunc(category, event)
+	}
+	return 0, nil
+}
+
+func (m *MockCategoryRepository) UpdateCategory(category model.Category, oldPath string, event model.AuditEvent) error {
+	if m.UpdateCategoryFunc != nil {
+		return m.UpdateCategoryFunc(category, oldPath, event)
+	}
+	return nil
+}
+
+func (m *MockCategoryRepository) DeleteCategory(id int, event model.AuditEvent) error {
+	if m.DeleteCategoryFunc != nil {
+		return m.DeleteCategoryFunc(id, event)
+	}
+	return nil
+}
+
+func (m *MockCategoryRepository) SetProductCategories(productID int, categoryIDs []int, event model.AuditEvent) error {
+	if m.SetProductCategoriesFunc != nil {
+		return m.SetProductCategoriesFunc(productID, categoryIDs, event)
+	}
+	return nil
+}
+
+func (m *MockCategoryRepository) GetProductCategories(productID int) ([]model.Category, error) {
+	if m.GetProductCategoriesFunc != nil {
+		return m.GetProductCategoriesFunc(productID)
+	}
+	return nil, nil
+}
+
+// MockPricingRepository é um mock do PricingRepository para testes do usecase
+type MockPricingRepository struct {
+	GetPriceListsFunc       func() ([]model.PriceList, error)
+	GetPriceListByIDFunc    func(id int) (*model.PriceList, error)
+	GetPriceListByCodeFunc  func(code string) (*model.PriceList, error)
+	CreatePriceListFunc     func(list model.PriceList) (int, error)
+	GetPriceListItemsFunc   func(priceListID int) ([]model.PriceListItem, error)
+	SetPriceListItemFunc    func(item model.PriceListItem) error
+	DeletePriceListItemFunc func(priceListID, productID int) error
+	GetListPricesFunc       func(currency, market string, productIDs []int) (map[int]model.ListPrice, error)
+	GetExchangeRatesFunc    func() ([]model.ExchangeRate, error)
+	GetExchangeRateFunc     func(base, quote string) (*model.ExchangeRate, error)
+	UpsertExchangeRatesFunc func(rates []model.ExchangeRate) error
+}
+
+func (m *MockPricingRepository) GetPriceLists() ([]model.PriceList, error) {
+	if m.GetPriceListsFunc != nil {
+		return m.GetPriceListsFunc()
+	}
+	return nil, nil
+}
+
+func (m *MockPricingRepository) GetPriceListByID(id int) (*model.PriceList, error) {
+	if m.GetPriceListByIDFunc != nil {
+		return m.GetPriceListByIDFunc(id)
+	}
+	return nil, nil
+}
+
+func (m *MockPricingRepository) GetPriceListByCode(code string) (*model.PriceList, error) {
+	if m.GetPriceListByCodeFunc != nil {
+		return m.GetPriceListByCodeFunc(code)
+	}
+	return nil, nil
+}
+
+func (m *MockPricingRepository) CreatePriceList(list model.PriceList) (int, error) {
+	if m.CreatePriceListFunc != nil {
+		return m.CreatePriceListFunc(list)
+	}
+	return 0, nil
+}
+
+func (m *MockPricingRepository) GetPriceListItems(priceListID int) ([]model.PriceListItem, error) {
+	if m.GetPriceListItemsFunc != nil {
+		return m.GetPriceListItemsFunc(priceListID)
+	}
+	return nil, nil
+}
+
+func (m *MockPricingRepository) SetPriceListItem(item model.PriceListItem) error {
+	if m.SetPriceListItemFunc != nil {
+		return m.SetPriceListItemFunc(item)
+	}
+	return nil
+}
+
+func (m *MockPricingRepository) DeletePriceListItem(priceListID, productID int) error {
+	if m.DeletePriceListItemFunc != nil {
+		return m.DeletePriceListItemFunc(priceListID, productID)
+	}
+	return nil
+}
+
+func (m *MockPricingRepository) GetListPrices(currency, market string, productIDs []int) (map[int]model.ListPrice, error) {
+	if m.GetListPricesFunc != nil {
+		return m.GetListPricesFunc(currency, market, productIDs)
+	}
+	return nil, nil
+}
+
+func (m *MockPricingRepository) GetExchangeRates() ([]model.ExchangeRate, error) {
+	if m.GetExchangeRatesFunc != nil {
+		return m.GetExchangeRatesFunc()
+	}
+	return nil, nil
+}
+
+func (m *MockPricingRepository) GetExchangeRate(base, quote string) (*model.ExchangeRate, error) {
+	if m.GetExchangeRateFunc != nil {
+		return m.GetExchangeRateFunc(base, quote)
+	}
+	return nil, nil
+}
+
+func (m *MockPricingRepository) UpsertExchangeRates(rates []model.ExchangeRate) error {
+	if m.UpsertExchangeRatesFunc != nil {
+		return m.UpsertExchangeRatesFunc(rates)
+	}
+	return nil
+}
+
+// MockVariantRepository é um mock do VariantRepository para testes do usecase
+type MockVariantRepository struct {
+	GetOptionTypesFunc       func() ([]model.OptionType, error)
+	GetOptionTypeByIDFunc    func(id int) (*model.OptionType, error)
+	GetOptionTypeByNameFunc  func(name string) (*model.OptionType, error)
+	CreateOptionTypeFunc     func(optionType model.OptionType) (int, error)
+	CreateOptionValueFunc    func(value model.OptionValue) (int, error)
+	GetOptionValuesByIDsFunc func(ids []int) ([]model.VariantOption, error)
+	GetVariantsFunc          func(productIDs []int) ([]model.Variant, error)
+	GetVariantByIDFunc       func(id int) (*model.Variant, error)
+	GetVariantBySKUFunc      func(sku string) (*model.Variant, error)
+	CreateVariantFunc        func(variant model.Variant) (int, error)
+	UpdateVariantFunc        func(variant model.Variant) error
+	DeleteVariantFunc        func(id int) error
+}
+
+func (m *MockVariantRepository) GetOptionTypes() ([]model.OptionType, error) {
+	if m.GetOptionTypesFunc != nil {
+		return m.GetOptionTypesFunc()
+	}
+	return nil, nil
+}
+
+func (m *MockVariantRepository) GetOptionTypeByID(id int) (*model.OptionType, error) {
+	if m.GetOptionTypeByIDFunc != nil {
+		return m.GetOptionTypeByIDFunc(id)
+	}
+	return nil, nil
+}
+
+func (m *MockVariantRepository) GetOptionTypeByName(name string) (*model.OptionType, error) {
+	if m.GetOptionTypeByNameFunc != nil {
+		return m.GetOptionTypeByNameFunc(name)
+	}
+	return nil, nil
+}
+
+func (m *MockVariantRepository) CreateOptionType(optionType model.OptionType) (int, error) {
+	if m.CreateOptionTypeFunc != nil {
+		return m.CreateOptionTypeFunc(optionType)
+	}
+	return 0, nil
+}
+
+func (m *MockVariantRepository) CreateOptionValue(value model.OptionValue) (int, error) {
+	if m.CreateOptionValueFunc != nil {
+		return m.CreateOptionValueFunc(value)
+	}
+	return 0, nil
+}
+
+func (m *MockVariantRepository) GetOptionValuesByIDs(ids []int) ([]model.VariantOption, error) {
+	if m.GetOptionValuesByIDsFunc != nil {
+		return m.GetOptionValuesByIDsFunc(ids)
+	}
+	return nil, nil
+}
+
+func (m *MockVariantRepository) GetVariants(productIDs []int) ([]model.Variant, error) {
+	if m.GetVariantsFunc != nil {
+		return m.GetVariantsFunc(productIDs)
+	}
+	return nil, nil
+}
+
+func (m *MockVariantRepository) GetVariantByID(id int) (*model.Variant, error) {
+	if m.GetVariantByIDFunc != nil {
+		return m.GetVariantByIDFunc(id)
+	}
+	return nil, nil
+}
+
+func (m *MockVariantRepository) GetVariantBySKU(sku string) (*model.Variant, error) {
+	if m.GetVariantBySKUFunc != nil {
+		return m.GetVariantBySKUFunc(sku)
+	}
+	return nil, nil
+}
+
+func (m *MockVariantRepository) CreateVariant(variant model.Variant) (int, error) {
+	if m.CreateVariantFunc != nil {
+		return m.CreateVariantFunc(variant)
+	}
+	return 0, nil
+}
+
+func (m *MockVariantRepository) UpdateVariant(variant model.Variant) error {
+	if m.UpdateVariantFunc != nil {
+		return m.UpdateVariantFunc(variant)
+	}
+	return nil
+}
+
+func (m *MockVariantRepository) DeleteVariant(id int) error {
+	if m.DeleteVariantFunc != nil {
+		return m.DeleteVariantFunc(id)
+	}
+	return nil
+}
+
+// MockImageRepository é um mock do ImageRepository para testes do usecase
+type MockImageRepository struct {
+	GetImagesFunc       func(productIDs []int) ([]model.ProductImage, error)
+	GetImageByIDFunc    func(id int) (*model.ProductImage, error)
+	CreateImageFunc     func(image model.ProductImage) (model.ProductImage, error)
+	SetPrimaryImageFunc func(productID, imageID int) error
+	ReorderImagesFunc   func(productID int, imageIDs []int) error
+	DeleteImageFunc     func(image model.ProductImage) error
+}
+
+func (m *MockImageRepository) GetImages(productIDs []int) ([]model.ProductImage, error) {
+	if m.GetImagesFunc != nil {
+		return m.GetImagesFunc(productIDs)
 	}
 	return nil, nil
 }
+
+func (m *MockImageRepository) GetImageByID(id int) (*model.ProductImage, error) {
+	if m.GetImageByIDFunc != nil {
+		return m.GetImageByIDFunc(id)
+	}
+	return nil, nil
+}
+
+func (m *MockImageRepository) CreateImage(image model.ProductImage) (model.ProductImage, error) {
+	if m.CreateImageFunc != nil {
+		return m.CreateImageFunc(image)
+	}
+	return image, nil
+}
+
+func (m *MockImageRepository) SetPrimaryImage(productID, imageID int) error {
+	if m.SetPrimaryImageFunc != nil {
+		return m.SetPrimaryImageFunc(productID, imageID)
+	}
+	return nil
+}
+
+func (m *MockImageRepository) ReorderImages(productID int, imageIDs []int) error {
+	if m.ReorderImagesFunc != nil {
+		return m.ReorderImagesFunc(productID, imageIDs)
+	}
+	return nil
+}
+
+func (m *MockImageRepository) DeleteImage(image model.ProductImage) error {
+	if m.DeleteImageFunc != nil {
+		return m.DeleteImageFunc(image)
+	}
+	return nil
+}
+
+// MockAuditRepository é um mock do AuditRepository para testes do usecase
+type MockAuditRepository struct {
+	GetAuditEventsFunc   func(filter model.AuditFilter) ([]model.AuditEvent, error)
+	WalkAuditEventsFunc  func(ctx context.Context, fn func(model.AuditEvent) error) error
+	RecordAuditEventFunc func(event model.AuditEvent) error
+}
+
+func (m *MockAuditRepository) GetAuditEvents(filter model.AuditFilter) ([]model.AuditEvent, error) {
+	if m.GetAuditEventsFunc != nil {
+		return m.GetAuditEventsFunc(filter)
+	}
+	return nil, nil
+}
+
+func (m *MockAuditRepository) WalkAuditEvents(ctx context.Context, fn func(model.AuditEvent) error) error {
+	if m.WalkAuditEventsFunc != nil {
+		return m.WalkAuditEventsFunc(ctx, fn)
+	}
+	return nil
+}
+
+func (m *MockAuditRepository) RecordAuditEvent(event model.AuditEvent) error {
+	if m.RecordAuditEventFunc != nil {
+		return m.RecordAuditEventFunc(event)
+	}
+	return nil
+}
+
+// MockCartRepository é um mock do CartRepository para testes do usecase
+type MockCartRepository struct {
+	GetCartByUserIDFunc        func(userID int) (*model.Cart, error)
+	GetCartByTokenHashFunc     func(tokenHash string) (*model.Cart, error)
+	CreateCartFunc             func(userID *int, tokenHash string) (*model.Cart, error)
+	GetCartItemsFunc           func(cartID int, asOf time.Time) ([]model.CartItem, error)
+	SaveCartItemFunc           func(item model.CartItem) error
+	UpdateCartItemQuantityFunc func(cartID, itemID, quantity int) error
+	DeleteCartItemFunc         func(cartID, itemID int) error
+	ClaimCartFunc              func(cartID, userID int) error
+	MergeCartsFunc             func(sourceID, targetID int) error
+}
+
+func (m *MockCartRepository) GetCartByUserID(userID int) (*model.Cart, error) {
+	if m.GetCartByUserIDFunc != nil {
+		return m.GetCartByUserIDFunc(userID)
+	}
+	return nil, nil
+}
+
+func (m *MockCartRepository) GetCartByTokenHash(tokenHash string) (*model.Cart, error) {
+	if m.GetCartByTokenHashFunc != nil {
+		return m.GetCartByTokenHashFunc(tokenHash)
+	}
+	return nil, nil
+}
+
+func (m *MockCartRepository) CreateCart(userID *int, tokenHash string) (*model.Cart, error) {
+	if m.CreateCartFunc != nil {
+		return m.CreateCartFunc(userID, tokenHash)
+	}
+	return &model.Cart{UserID: userID}, nil
+}
+
+func (m *MockCartRepository) GetCartItems(cartID int, asOf time.Time) ([]model.CartItem, error) {
+	if m.GetCartItemsFunc != nil {
+		return m.GetCartItemsFunc(cartID, asOf)
+	}
+	return []model.CartItem{}, nil
+}
+
+func (m *MockCartRepository) SaveCartItem(item model.CartItem) error {
+	if m.SaveCartItemFunc != nil {
+		return m.SaveCartItemFunc(item)
+	}
+	return nil
+}
+
+func (m *MockCartRepository) UpdateCartItemQuantity(cartID, itemID, quantity int) error {
+	if m.UpdateCartItemQuantityFunc != nil {
+		return m.UpdateCartItemQuantityFunc(cartID, itemID, quantity)
+	}
+	return nil
+}
+
+func (m *MockCartRepository) DeleteCartItem(cartID, itemID int) error {
+	if m.DeleteCartItemFunc != nil {
+		return m.DeleteCartItemFunc(cartID, itemID)
+	}
+	return nil
+}
+
+func (m *MockCartRepository) ClaimCart(cartID, userID int) error {
+	if m.ClaimCartFunc != nil {
+		return m.ClaimCartFunc(cartID, userID)
+	}
+	return nil
+}
+
+func (m *MockCartRepository) MergeCarts(sourceID, targetID int) error {
+	if m.MergeCartsFunc != nil {
+		return m.MergeCartsFunc(sourceID, targetID)
+	}
+	return nil
+}
+
+// MockOrderRepository é um mock do OrderRepository para testes do usecase
+type MockOrderRepository struct {
+	CreateOrderFunc     func(order model.Order, cartItemIDs []int) (int, error)
+	GetOrdersFunc       func(filter model.OrderFilter) ([]model.Order, error)
+	GetOrderByIDFunc    func(id int) (*model.Order, error)
+	TransitionOrderFunc func(id int, transition model.OrderTransition, restock bool) error
+}
+
+func (m *MockOrderRepository) CreateOrder(order model.Order, cartItemIDs []int) (int, error) {
+	if m.CreateOrderFunc != nil {
+		return m.CreateOrderFunc(order, cartItemIDs)
+	}
+	return 1, nil
+}
+
+func (m *MockOrderRepository) GetOrders(filter model.OrderFilter) ([]model.Order, error) {
+	if m.GetOrdersFunc != nil {
+		return m.GetOrdersFunc(filter)
+	}
+	return []model.Order{}, nil
+}
+
+func (m *MockOrderRepository) GetOrderByID(id int) (*model.Order, error) {
+	if m.GetOrderByIDFunc != nil {
+		return m.GetOrderByIDFunc(id)
+	}
+	return nil, nil
+}
+
+func (m *MockOrderRepository) TransitionOrder(id int, transition model.OrderTransition, restock bool) error {
+	if m.TransitionOrderFunc != nil {
+		return m.TransitionOrderFunc(id, transition, restock)
+	}
+	return nil
+}
+
+// MockPaymentRepository é um mock do PaymentRepository para testes do usecase
+type MockPaymentRepository struct {
+	CreatePaymentFunc        func(payment model.Payment) (int, error)
+	GetPaymentByIntentIDFunc func(provider, intentID string) (*model.Payment, error)
+	GetOrderPaymentsFunc     func(orderID int) ([]model.Payment, error)
+	UpdatePaymentStatusFunc  func(id int, status string) error
+	RecordWebhookEventFunc   func(provider, eventID, eventType string) (bool, error)
+	ForgetWebhookEventFunc   func(provider, eventID string) error
+}
+
+func (m *MockPaymentRepository) CreatePayment(payment model.Payment) (int, error) {
+	if m.CreatePaymentFunc != nil {
+		return m.CreatePaymentFunc(payment)
+	}
+	return 1, nil
+}
+
+func (m *MockPaymentRepository) GetPaymentByIntentID(provider, intentID string) (*model.Payment, error) {
+	if m.GetPaymentByIntentIDFunc != nil {
+		return m.GetPaymentByIntentIDFunc(provider, intentID)
+	}
+	return nil, nil
+}
+
+func (m *MockPaymentRepository) GetOrderPayments(orderID int) ([]model.Payment, error) {
+	if m.GetOrderPaymentsFunc != nil {
+		return m.GetOrderPaymentsFunc(orderID)
+	}
+	return []model.Payment{}, nil
+}
+
+func (m *MockPaymentRepository) UpdatePaymentStatus(id int, status string) error {
+	if m.UpdatePaymentStatusFunc != nil {
+		return m.UpdatePaymentStatusFunc(id, status)
+	}
+	return nil
+}
+
+func (m *MockPaymentRepository) RecordWebhookEvent(provider, eventID, eventType string) (bool, error) {
+	if m.RecordWebhookEventFunc != nil {
+		return m.RecordWebhookEventFunc(provider, eventID, eventType)
+	}
+	return true, nil
+}
+
+func (m *MockPaymentRepository) ForgetWebhookEvent(provider, eventID string) error {
+	if m.ForgetWebhookEventFunc != nil {
+		return m.ForgetWebhookEventFunc(provider, eventID)
+	}
+	return nil
+}
+
+// MockPromotionRepository é um mock do PromotionRepository para testes do usecase
+type MockPromotionRepository struct {
+	CreatePromotionFunc        func(promotion model.Promotion) (int, error)
+	GetPromotionsFunc          func() ([]model.Promotion, error)
+	GetPromotionByIDFunc       func(id int) (*model.Promotion, error)
+	GetPromotionByCodeFunc     func(code string) (*model.Promotion, error)
+	GetAutomaticPromotionsFunc func() ([]model.Promotion, error)
+	SetPromotionActiveFunc     func(id int, active bool) error
+	GetProductCategoryIDsFunc  func(productIDs []int) (map[int][]int, error)
+	CountUserRedemptionsFunc   func(userID int, promotionIDs []int) (map[int]int, error)
+}
+
+func (m *MockPromotionRepository) CreatePromotion(promotion model.Promotion) (int, error) {
+	if m.CreatePromotionFunc != nil {
+		return m.CreatePromotionFunc(promotion)
+	}
+	return 1, nil
+}
+
+func (m *MockPromotionRepository) GetPromotions() ([]model.Promotion, error) {
+	if m.GetPromotionsFunc != nil {
+		return m.GetPromotionsFunc()
+	}
+	return []model.Promotion{}, nil
+}
+
+func (m *MockPromotionRepository) GetPromotionByID(id int) (*model.Promotion, error) {
+	if m.GetPromotionByIDFunc != nil {
+		return m.GetPromotionByIDFunc(id)
+	}
+	return nil, nil
+}
+
+func (m *MockPromotionRepository) GetPromotionByCode(code string) (*model.Promotion, error) {
+	if m.GetPromotionByCodeFunc != nil {
+		return m.GetPromotionByCodeFunc(code)
+	}
+	return nil, nil
+}
+
+func (m *MockPromotionRepository) GetAutomaticPromotions() ([]model.Promotion, error) {
+	if m.GetAutomaticPromotionsFunc != nil {
+		return m.GetAutomaticPromotionsFunc()
+	}
+	return []model.Promotion{}, nil
+}
+
+func (m *MockPromotionRepository) SetPromotionActive(id int, active bool) error {
+	if m.SetPromotionActiveFunc != nil {
+		return m.SetPromotionActiveFunc(id, active)
+	}
+	return nil
+}
+
+func (m *MockPromotionRepository) GetProductCategoryIDs(productIDs []int) (map[int][]int, error) {
+	if m.GetProductCategoryIDsFunc != nil {
+		return m.GetProductCategoryIDsFunc(productIDs)
+	}
+	return map[int][]int{}, nil
+}
+
+func (m *MockPromotionRepository) CountUserRedemptions(userID int, promotionIDs []int) (map[int]int, error) {
+	if m.CountUserRedemptionsFunc != nil {
+		return m.CountUserRedemptionsFunc(userID, promotionIDs)
+	}
+	return map[int]int{}, nil
+}
+
+// MockPasswordResetRepository é um mock do PasswordResetRepository para testes do usecase
+type MockPasswordResetRepository struct {
+	CreatePasswordResetTokenFunc func(token model.PasswordResetToken, email model.OutboxEmail) error
+	GetPasswordResetUserIDFunc   func(tokenHash string) (int, error)
+	ResetPasswordFunc            func(tokenHash, password string, event model.AuditEvent) (int, error)
+}
+
+func (m *MockPasswordResetRepository) CreatePasswordResetToken(token model.PasswordResetToken, email model.OutboxEmail) error {
+	if m.CreatePasswordResetTokenFunc != nil {
+		return m.CreatePasswordResetTokenFunc(token, email)
+	}
+	return nil
+}
+
+func (m *MockPasswordResetRepository) GetPasswordResetUserID(tokenHash string) (int, error) {
+	if m.GetPasswordResetUserIDFunc != nil {
+		return m.GetPasswordResetUserIDFunc(tokenHash)
+	}
+	return 0, nil
+}
+
+func (m *MockPasswordResetRepository) ResetPassword(tokenHash, password string, event model.AuditEvent) (int, error) {
+	if m.ResetPasswordFunc != nil {
+		return m.ResetPasswordFunc(tokenHash, password, event)
+	}
+	return 0, nil
+}
+
+// MockOutboxRepository é um mock do OutboxRepository para testes do usecase
+type MockOutboxRepository struct {
+	ClaimDueEmailsFunc  func(limit int, lease time.Duration, maxAttempts int) ([]model.OutboxEmail, error)
+	MarkEmailSentFunc   func(id int64) error
+	MarkEmailFailedFunc func(id int64, reason string, retryAt time.Time) error
+}
+
+func (m *MockOutboxRepository) ClaimDueEmails(limit int, lease time.Duration, maxAttempts int) ([]model.OutboxEmail, error) {
+	if m.ClaimDueEmailsFunc != nil {
+		return m.ClaimDueEmailsFunc(limit, lease, maxAttempts)
+	}
+	return []model.OutboxEmail{}, nil
+}
+
+func (m *MockOutboxRepository) MarkEmailSent(id int64) error {
+	if m.MarkEmailSentFunc != nil {
+		return m.MarkEmailSentFunc(id)
+	}
+	return nil
+}
+
+func (m *MockOutboxRepository) MarkEmailFailed(id int64, reason string, retryAt time.Time) error {
+	if m.MarkEmailFailedFunc != nil {
+		return m.MarkEmailFailedFunc(id, reason, retryAt)
+	}
+	return nil
+}
+
+// MockMFARepository é um mock do MFARepository para testes do usecase
+type MockMFARepository struct {
+	GetMFAFunc               func(userID int) (*model.UserMFA, error)
+	SavePendingMFAFunc       func(userID int, secretEncrypted string) error
+	ConfirmMFAFunc           func(userID int, step int64, codeHashes []string, event model.AuditEvent) error
+	UseTOTPStepFunc          func(userID int, step int64) error
+	UseRecoveryCodeFunc      func(userID int, codeHash string) error
+	ReplaceRecoveryCodesFunc func(userID int, codeHashes []string, event model.AuditEvent) error
+	DisableMFAFunc           func(userID int, event model.AuditEvent) error
+}
+
+func (m *MockMFARepository) GetMFA(userID int) (*model.UserMFA, error) {
+	if m.GetMFAFunc != nil {
+		return m.GetMFAFunc(userID)
+	}
+	return nil, nil
+}
+
+func (m *MockMFARepository) SavePendingMFA(userID int, secretEncrypted string) error {
+	if m.SavePendingMFAFunc != nil {
+		return m.SavePendingMFAFunc(userID, secretEncrypted)
+	}
+	return nil
+}
+
+func (m *MockMFARepository) ConfirmMFA(userID int, step int64, codeHashes []string, event model.AuditEvent) error {
+	if m.ConfirmMFAFunc != nil {
+		return m.ConfirmMFAFunc(userID, step, codeHashes, event)
+	}
+	return nil
+}
+
+func (m *MockMFARepository) UseTOTPStep(userID int, step int64) error {
+	if m.UseTOTPStepFunc != nil {
+		return m.UseTOTPStepFunc(userID, step)
+	}
+	return nil
+}
+
+func (m *MockMFARepository) UseRecoveryCode(userID int, codeHash string) error {
+	if m.UseRecoveryCodeFunc != nil {
+		return m.UseRecoveryCodeFunc(userID, codeHash)
+	}
+	return nil
+}
+
+func (m *MockMFARepository) ReplaceRecoveryCodes(userID int, codeHashes []string, event model.AuditEvent) error {
+	if m.ReplaceRecoveryCodesFunc != nil {
+		return m.ReplaceRecoveryCodesFunc(userID, codeHashes, event)
+	}
+	return nil
+}
+
+func (m *MockMFARepository) DisableMFA(userID int, event model.AuditEvent) error {
+	if m.DisableMFAFunc != nil {
+		return m.DisableMFAFunc(userID, event)
+	}
+	return nil
+}
+
+// MockAPIKeyRepository é um mock do APIKeyRepository para testes do usecase
+type MockAPIKeyRepository struct {
+	CreateAPIKeyFunc      func(key model.APIKey, event model.AuditEvent) (int, error)
+	GetAPIKeysFunc        func(userID int) ([]model.APIKey, error)
+	GetAPIKeyByIDFunc     func(id int) (*model.APIKey, error)
+	GetAPIKeyByPrefixFunc func(prefix string) (*model.APIKey, error)
+	RotateAPIKeyFunc      func(id int, prefix, keyHash string, event model.AuditEvent) error
+	RevokeAPIKeyFunc      func(id int, event model.AuditEvent) error
+	TouchAPIKeyFunc       func(id int, ip string) error
+}
+
+func (m *MockAPIKeyRepository) CreateAPIKey(key model.APIKey, event model.AuditEvent) (int, error) {
+	if m.CreateAPIKeyFunc != nil {
+		return m.CreateAPIKeyFunc(key, event)
+	}
+	return 0, nil
+}
+
+func (m *MockAPIKeyRepository) GetAPIKeys(userID int) ([]model.APIKey, error) {
+	if m.GetAPIKeysFunc != nil {
+		return m.GetAPIKeysFunc(userID)
+	}
+	return nil, nil
+}
+
+func (m *MockAPIKeyRepository) GetAPIKeyByID(id int) (*model.APIKey, error) {
+	if m.GetAPIKeyByIDFunc != nil {
+		return m.GetAPIKeyByIDFunc(id)
+	}
+	return nil, nil
+}
+
+func (m *MockAPIKeyRepository) GetAPIKeyByPrefix(prefix string) (*model.APIKey, error) {
+	if m.GetAPIKeyByPrefixFunc != nil {
+		return m.GetAPIKeyByPrefixFunc(prefix)
+	}
+	return nil, nil
+}
+
+func (m *MockAPIKeyRepository) RotateAPIKey(id int, prefix, keyHash string, event model.AuditEvent) error {
+	if m.RotateAPIKeyFunc != nil {
+		return m.RotateAPIKeyFunc(id, prefix, keyHash, event)
+	}
+	return nil
+}
+
+func (m *MockAPIKeyRepository) RevokeAPIKey(id int, event model.AuditEvent) error {
+	if m.RevokeAPIKeyFunc != nil {
+		return m.RevokeAPIKeyFunc(id, event)
+	}
+	return nil
+}
+
+func (m *MockAPIKeyRepository) TouchAPIKey(id int, ip string) error {
+	if m.TouchAPIKeyFunc != nil {
+		return m.TouchAPIKeyFunc(id, ip)
+	}
+	return nil
+}
+
+// MockOIDCRepository é um mock do OIDCRepository para testes do usecase
+type MockOIDCRepository struct {
+	CreateLoginStateFunc  func(state model.OIDCLoginState) error
+	ConsumeLoginStateFunc func(stateHash string) (*model.OIDCLoginState, error)
+	GetIdentityFunc       func(provider, subject string) (*model.UserIdentity, error)
+	GetIdentitiesFunc     func(userID int) ([]model.UserIdentity, error)
+	LinkIdentityFunc      func(identity model.UserIdentity, event model.AuditEvent) error
+	TouchIdentityFunc     func(id int, email string) error
+	UnlinkIdentityFunc    func(userID int, provider string, event model.AuditEvent) error
+}
+
+func (m *MockOIDCRepository) CreateLoginState(state model.OIDCLoginState) error {
+	if m.CreateLoginStateFunc != nil {
+		return m.CreateLoginStateFunc(state)
+	}
+	return nil
+}
+
+func (m *MockOIDCRepository) ConsumeLoginState(stateHash string) (*model.OIDCLoginState, error) {
+	if m.ConsumeLoginStateFunc != nil {
+		return m.ConsumeLoginStateFunc(stateHash)
+	}
+	return nil, nil
+}
+
+func (m *MockOIDCRepository) GetIdentity(provider, subject string) (*model.UserIdentity, error) {
+	if m.GetIdentityFunc != nil {
+		return m.GetIdentityFunc(provider, subject)
+	}
+	return nil, nil
+}
+
+func (m *MockOIDCRepository) GetIdentities(userID int) ([]model.UserIdentity, error) {
+	if m.GetIdentitiesFunc != nil {
+		return m.GetIdentitiesFunc(userID)
+	}
+	return nil, nil
+}
+
+func (m *MockOIDCRepository) LinkIdentity(identity model.UserIdentity, event model.AuditEvent) error {
+	if m.LinkIdentityFunc != nil {
+		return m.LinkIdentityFunc(identity, event)
+	}
+	return nil
+}
+
+func (m *MockOIDCRepository) TouchIdentity(id int, email string) error {
+	if m.TouchIdentityFunc != nil {
+		return m.TouchIdentityFunc(id, email)
+	}
+	return nil
+}
+
+func (m *MockOIDCRepository) UnlinkIdentity(userID int, provider string, event model.AuditEvent) error {
+	if m.UnlinkIdentityFunc != nil {
+		return m.UnlinkIdentityFunc(userID, provider, event)
+	}
+	return nil
+}
+
+// MockOAuthRepository é um mock do OAuthRepository para testes do usecase
+type MockOAuthRepository struct {
+	CreateClientFunc   func(client model.OAuthClient, event model.AuditEvent) (int, error)
+	GetClientsFunc     func() ([]model.OAuthClient, error)
+	GetClientFunc      func(clientID string) (*model.OAuthClient, error)
+	RevokeClientFunc   func(clientID string, event model.AuditEvent) error
+	GetConsentFunc     func(userID int, clientID string) (*model.OAuthConsent, error)
+	GetConsentsFunc    func(userID int) ([]model.OAuthConsent, error)
+	SaveConsentFunc    func(consent model.OAuthConsent, event model.AuditEvent) error
+	DeleteConsentFunc  func(userID int, clientID string, event model.AuditEvent) error
+	CreateCodeFunc     func(code model.OAuthCode) error
+	ConsumeCodeFunc    func(codeHash string) (*model.OAuthCode, error)
+	CreateTokenFunc    func(token model.OAuthToken) (int, error)
+	GetTokenByHashFunc func(tokenHash string) (*model.OAuthToken, error)
+	RevokeTokenFunc    func(id int) error
+}
+
+func (m *MockOAuthRepository) CreateClient(client model.OAuthClient, event model.AuditEvent) (int, error) {
+	if m.CreateClientFunc != nil {
+		return m.CreateClientFunc(client, event)
+	}
+	return 0, nil
+}
+
+func (m *MockOAuthRepository) GetClients() ([]model.OAuthClient, error) {
+	if m.GetClientsFunc != nil {
+		return m.GetClientsFunc()
+	}
+	return nil, nil
+}
+
+func (m *MockOAuthRepository) GetClient(clientID string) (*model.OAuthClient, error) {
+	if m.GetClientFunc != nil {
+		return m.GetClientFunc(clientID)
+	}
+	return nil, nil
+}
+
+func (m *MockOAuthRepository) RevokeClient(clientID string, event model.AuditEvent) error {
+	if m.RevokeClientFunc != nil {
+		return m.RevokeClientFunc(clientID, event)
+	}
+	return nil
+}
+
+func (m *MockOAuthRepository) GetConsent(userID int, clientID string) (*model.OAuthConsent, error) {
+	if m.GetConsentFunc != nil {
+		return m.GetConsentFunc(userID, clientID)
+	}
+	return nil, nil
+}
+
+func (m *MockOAuthRepository) GetConsents(userID int) ([]model.OAuthConsent, error) {
+	if m.GetConsentsFunc != nil {
+		return m.GetConsentsFunc(userID)
+	}
+	return nil, nil
+}
+
+func (m *MockOAuthRepository) SaveConsent(consent model.OAuthConsent, event model.AuditEvent) error {
+	if m.SaveConsentFunc != nil {
+		return m.SaveConsentFunc(consent, event)
+	}
+	return nil
+}
+
+func (m *MockOAuthRepository) DeleteConsent(userID int, clientID string, event model.AuditEvent) error {
+	if m.DeleteConsentFunc != nil {
+		return m.DeleteConsentFunc(userID, clientID, event)
+	}
+	return nil
+}
+
+func (m *MockOAuthRepository) CreateCode(code model.OAuthCode) error {
+	if m.CreateCodeFunc != nil {
+		return m.CreateCodeFunc(code)
+	}
+	return nil
+}
+
+func (m *MockOAuthRepository) ConsumeCode(codeHash string) (*model.OAuthCode, error) {
+	if m.ConsumeCodeFunc != nil {
+		return m.ConsumeCodeFunc(codeHash)
+	}
+	return nil, nil
+}
+
+func (m *MockOAuthRepository) CreateToken(token model.OAuthToken) (int, error) {
+	if m.CreateTokenFunc != nil {
+		return m.CreateTokenFunc(token)
+	}
+	return 0, nil
+}
+
+func (m *MockOAuthRepository) GetTokenByHash(tokenHash string) (*model.OAuthToken, error) {
+	if m.GetTokenByHashFunc != nil {
+		return m.GetTokenByHashFunc(tokenHash)
+	}
+	return nil, nil
+}
+
+func (m *MockOAuthRepository) RevokeToken(id int) error {
+	if m.RevokeTokenFunc != nil {
+		return m.RevokeTokenFunc(id)
+	}
+	return nil
+}
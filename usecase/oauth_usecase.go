@@ -0,0 +1,707 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"go-api/dto"
+	"go-api/internal/oidc"
+	"go-api/internal/util"
+	"go-api/model"
+	"go-api/repository"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrOAuthClientNotFound     = errors.New("oauth client not found")
+	ErrOAuthClientRevoked      = errors.New("oauth client already revoked")
+	ErrInvalidOAuthGrantType   = errors.New("invalid grant type")
+	ErrInvalidOAuthScope       = errors.New("invalid oauth scope")
+	ErrInvalidRedirectURI      = errors.New("redirect uris must be absolute https urls, or http on localhost, without fragment; authorization_code needs one")
+	ErrInvalidOAuthClientOwner = errors.New("client_credentials needs a confidential client acting as an admin or a service account")
+	ErrOAuthConsentNotFound    = errors.New("no consent given to this client")
+	ErrOAuthLoginRequired      = errors.New("log in, or send email and password, to approve")
+)
+
+// OAuthError is an error of the OAuth endpoints, carrying the error code
+// of RFC 6749 the clients expect
+type OAuthError struct {
+	Code        string
+	Description string
+}
+
+func (e *OAuthError) Error() string {
+	return e.Code + ": " + e.Description
+}
+
+var (
+	ErrOAuthInvalidClient = &OAuthError{Code: "invalid_client", Description: "unknown or revoked client, or wrong credentials"}
+	ErrOAuthInvalidGrant  = &OAuthError{Code: "invalid_grant", Description: "invalid, used or expired authorization code, or wrong code_verifier"}
+)
+
+// oauthClientIDSize is the random bytes of a client_id, written as 24 hex
+// characters
+const oauthClientIDSize = 12
+
+// OAuthPolicy holds how long the grants last
+type OAuthPolicy struct {
+	// CodeTTL is how long an authorization code waits for the exchange
+	CodeTTL time.Duration
+	// AccessTokenTTL is how long an access token works
+	AccessTokenTTL time.Duration
+}
+
+// DefaultOAuthPolicy gives a minute to exchange a code, and access tokens
+// of an hour
+var DefaultOAuthPolicy = OAuthPolicy{
+	CodeTTL:        time.Minute,
+	AccessTokenTTL: time.Hour,
+}
+
+// PasswordLogin checks the password of a user; UserUsecase implements it
+type PasswordLogin interface {
+	Login(ctx context.Context, login dto.LoginRequest) (*dto.LoginResponse, error)
+}
+
+// OAuthUsecase defines the contract for the OAuth2 authorization server:
+// the clients, the consent of the users, the grants and the access tokens
+type OAuthUsecase interface {
+	CreateClient(ctx context.Context, request dto.CreateOAuthClientRequest) (*dto.OAuthClientSecretResponse, error)
+	GetClients() ([]dto.OAuthClientResponse, error)
+	RevokeClient(ctx context.Context, clientID string) error
+	GetAuthorization(userID int, request dto.OAuthAuthorizeRequest) (*dto.OAuthAuthorizationResponse, error)
+	Authorize(ctx context.Context, userID int, mfa bool, request dto.OAuthApproveRequest) (*dto.OAuthAuthorizeResponse, error)
+	Token(ctx context.Context, request dto.OAuthTokenRequest) (*dto.OAuthTokenResponse, error)
+	Introspect(ctx context.Context, request dto.OAuthTokenActionRequest) (*dto.OAuthIntrospectionResponse, error)
+	Revoke(ctx context.Context, request dto.OAuthTokenActionRequest) error
+	AuthenticateAccessToken(ctx context.Context, token string) (*model.OAuthPrincipal, error)
+	GetConsents(userID int) ([]dto.OAuthConsentResponse, error)
+	RevokeConsent(ctx context.Context, userID int, clientID string) error
+}
+
+type oauthUsecaseImpl struct {
+	repository     repository.OAuthRepositoryInterface
+	userRepository repository.UserRepositoryInterface
+	logins         PasswordLogin
+	policy         OAuthPolicy
+}
+
+// NewOAuthUsecase creates a new instance of OAuthUsecase. Users approving
+// an authorization without a token sign in through logins
+func NewOAuthUsecase(repo repository.OAuthRepositoryInterface, userRepo repository.UserRepositoryInterface, logins PasswordLogin, policy OAuthPolicy) OAuthUsecase {
+	return &oauthUsecaseImpl{
+		repository:     repo,
+		userRepository: userRepo,
+		logins:         logins,
+		policy:         policy,
+	}
+}
+
+// CreateClient registers a client and returns it with its secret, which is
+// not shown again; public clients get none
+func (ou *oauthUsecaseImpl) CreateClient(ctx context.Context, request dto.CreateOAuthClientRequest) (*dto.OAuthClientSecretResponse, error) {
+	if err := validateOAuthClientRequest(request); err != nil {
+		return nil, err
+	}
+	clientCredentials := slices.Contains(request.GrantTypes, model.GrantTypeClientCredentials)
+	if clientCredentials {
+		user, err := ou.userRepository.GetUserByID(*request.UserID)
+		if err != nil {
+			return nil, err
+		}
+		if user == nil {
+			return nil, ErrUserNotFound
+		}
+		if !slices.Contains(apiKeyOwnerRoles, user.Role) {
+			return nil, ErrInvalidOAuthClientOwner
+		}
+	}
+
+	clientID, err := newOAuthClientID()
+	if err != nil {
+		return nil, err
+	}
+	client := model.OAuthClient{
+		ClientID:     clientID,
+		Name:         strings.TrimSpace(request.Name),
+		RedirectURIs: request.RedirectURIs,
+		GrantTypes:   slices.Compact(slices.Sorted(slices.Values(request.GrantTypes))),
+		Scopes:       slices.Compact(slices.Sorted(slices.Values(request.Scopes))),
+	}
+	if client.RedirectURIs == nil {
+		client.RedirectURIs = []string{}
+	}
+	if clientCredentials {
+		client.UserID = request.UserID
+	}
+	var secret string
+	if !request.Public {
+		if secret, err = newSecretToken(); err != nil {
+			return nil, err
+		}
+		client.SecretHash = hashSecretToken(secret)
+	}
+	event, err := newAuditEvent(ctx, model.AuditActionCreate, model.AuditEntityOAuthClient, clientID, nil, client)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := ou.repository.CreateClient(client, event); err != nil {
+		return nil, err
+	}
+
+	created, err := ou.repository.GetClient(clientID)
+	if err != nil {
+		return nil, err
+	}
+	if created == nil {
+		return nil, ErrOAuthClientNotFound
+	}
+	return &dto.OAuthClientSecretResponse{ClientSecret: secret, Client: toOAuthClientResponse(*created)}, nil
+}
+
+// GetClients lists the clients, revoked ones included
+func (ou *oauthUsecaseImpl) GetClients() ([]dto.OAuthClientResponse, error) {
+	clients, err := ou.repository.GetClients()
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.OAuthClientResponse, 0, len(clients))
+	for _, client := range clients {
+		responses = append(responses, toOAuthClientResponse(client))
+	}
+	return responses, nil
+}
+
+// RevokeClient turns a client off for good, with its access tokens
+func (ou *oauthUsecaseImpl) RevokeClient(ctx context.Context, clientID string) error {
+	client, err := ou.repository.GetClient(clientID)
+	if err != nil {
+		return err
+	}
+	if client == nil {
+		return ErrOAuthClientNotFound
+	}
+	if client.RevokedAt != nil {
+		return ErrOAuthClientRevoked
+	}
+
+	event, err := newAuditEvent(ctx, model.AuditActionRevoke, model.AuditEntityOAuthClient, clientID, trashAuditFields(false), trashAuditFields(true))
+	if err != nil {
+		return err
+	}
+	if err := ou.repository.RevokeClient(clientID, event); err != nil {
+		if err == sql.ErrNoRows {
+			return ErrOAuthClientRevoked
+		}
+		return err
+	}
+	return nil
+}
+
+// GetAuthorization checks an authorization request and tells the consent
+// page what the client asks for, and whether the user already granted it
+func (ou *oauthUsecaseImpl) GetAuthorization(userID int, request dto.OAuthAuthorizeRequest) (*dto.OAuthAuthorizationResponse, error) {
+	client, scopes, err := ou.checkAuthorization(request)
+	if err != nil {
+		return nil, err
+	}
+
+	consentRequired := true
+	if userID != 0 {
+		consent, err := ou.repository.GetConsent(userID, client.ClientID)
+		if err != nil {
+			return nil, err
+		}
+		consentRequired = consent == nil || !containsAll(consent.Scopes, scopes)
+	}
+	return &dto.OAuthAuthorizationResponse{
+		ClientID:        client.ClientID,
+		ClientName:      client.Name,
+		Scopes:          scopes,
+		ConsentRequired: consentRequired,
+	}, nil
+}
+
+// Authorize answers an authorization request for the user of userID or,
+// when 0, the one signing in with the email and password of the request,
+// through the login with its throttling and second factor. Approving
+// records the consent and returns the redirect URI with a code; refusing
+// returns it with access_denied
+func (ou *oauthUsecaseImpl) Authorize(ctx context.Context, userID int, mfa bool, request dto.OAuthApproveRequest) (*dto.OAuthAuthorizeResponse, error) {
+	client, scopes, err := ou.checkAuthorization(request.OAuthAuthorizeRequest)
+	if err != nil {
+		return nil, err
+	}
+	if !request.Approve {
+		return &dto.OAuthAuthorizeResponse{RedirectURI: redirectWith(request.RedirectURI, "error", "access_denied", "state", request.State)}, nil
+	}
+
+	if userID == 0 {
+		if request.Email == "" || request.Password == "" {
+			return nil, ErrOAuthLoginRequired
+		}
+		login, err := ou.logins.Login(ctx, dto.LoginRequest{Email: request.Email, Password: request.Password})
+		if err != nil {
+			return nil, err
+		}
+		if login.MFARequired {
+			return &dto.OAuthAuthorizeResponse{MFARequired: true, MFAToken: login.MFAToken}, nil
+		}
+		claims, err := util.ParseToken(login.Token)
+		if err != nil {
+			return nil, err
+		}
+		userID, mfa = claims.UserID, claims.MFA
+	}
+
+	if err := ou.grant(ctx, userID, client.ClientID, scopes); err != nil {
+		return nil, err
+	}
+	code, err := newSecretToken()
+	if err != nil {
+		return nil, err
+	}
+	err = ou.repository.CreateCode(model.OAuthCode{
+		CodeHash:      hashSecretToken(code),
+		ClientID:      client.ClientID,
+		UserID:        userID,
+		RedirectURI:   request.RedirectURI,
+		Scopes:        scopes,
+		CodeChallenge: request.CodeChallenge,
+		MFA:           mfa,
+		ExpiresAt:     time.Now().Add(ou.policy.CodeTTL),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &dto.OAuthAuthorizeResponse{RedirectURI: redirectWith(request.RedirectURI, "code", code, "state", request.State)}, nil
+}
+
+// Token issues an access token to an authenticated client, for an
+// authorization code and its PKCE verifier or, with client credentials,
+// as the account of the client
+func (ou *oauthUsecaseImpl) Token(ctx context.Context, request dto.OAuthTokenRequest) (*dto.OAuthTokenResponse, error) {
+	client, err := ou.authenticateClient(request.ClientID, request.ClientSecret)
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Contains(model.OAuthGrantTypes, request.GrantType) {
+		return nil, &OAuthError{Code: "unsupported_grant_type", Description: "grant_type must be authorization_code or client_credentials"}
+	}
+	if !slices.Contains(client.GrantTypes, request.GrantType) {
+		return nil, &OAuthError{Code: "unauthorized_client", Description: "the client may not use " + request.GrantType}
+	}
+
+	if request.GrantType == model.GrantTypeClientCredentials {
+		scopes, err := requestedScopes(request.Scope, client.Scopes)
+		if err != nil {
+			return nil, err
+		}
+		// Like API keys, the account of the client cannot answer a second factor
+		return ou.issueToken(client.ClientID, *client.UserID, scopes, true)
+	}
+
+	code, err := ou.exchangeCode(client, request)
+	if err != nil {
+		return nil, err
+	}
+	return ou.issueToken(client.ClientID, code.UserID, code.Scopes, code.MFA)
+}
+
+// Introspect tells an authenticated client whether one of its access
+// tokens is active. Tokens of other clients are reported inactive
+func (ou *oauthUsecaseImpl) Introspect(ctx context.Context, request dto.OAuthTokenActionRequest) (*dto.OAuthIntrospectionResponse, error) {
+	client, err := ou.authenticateClient(request.ClientID, request.ClientSecret)
+	if err != nil {
+		return nil, err
+	}
+	token, user, err := ou.activeToken(request.Token)
+	if err != nil {
+		return nil, err
+	}
+	if token == nil || token.ClientID != client.ClientID {
+		return &dto.OAuthIntrospectionResponse{Active: false}, nil
+	}
+
+	return &dto.OAuthIntrospectionResponse{
+		Active:    true,
+		Scope:     strings.Join(token.Scopes, " "),
+		ClientID:  token.ClientID,
+		Username:  user.Email,
+		TokenType: "Bearer",
+		Exp:       token.ExpiresAt.Unix(),
+		Iat:       token.CreatedAt.Unix(),
+		Sub:       strconv.Itoa(user.ID),
+	}, nil
+}
+
+// Revoke turns off an access token of the authenticated client. Unknown
+// tokens, or those of other clients, are ignored, as RFC 7009 asks
+func (ou *oauthUsecaseImpl) Revoke(ctx context.Context, request dto.OAuthTokenActionRequest) error {
+	client, err := ou.authenticateClient(request.ClientID, request.ClientSecret)
+	if err != nil {
+		return err
+	}
+	if !strings.HasPrefix(request.Token, model.AccessTokenPrefix) {
+		return nil
+	}
+	token, err := ou.repository.GetTokenByHash(hashSecretToken(request.Token))
+	if err != nil {
+		return err
+	}
+	if token == nil || token.ClientID != client.ClientID || token.RevokedAt != nil {
+		return nil
+	}
+	return ou.repository.RevokeToken(token.ID)
+}
+
+// AuthenticateAccessToken returns who an active access token acts as, or
+// nil when it is unknown, revoked or expired, or its user is gone
+func (ou *oauthUsecaseImpl) AuthenticateAccessToken(ctx context.Context, token string) (*model.OAuthPrincipal, error) {
+	stored, user, err := ou.activeToken(token)
+	if err != nil || stored == nil {
+		return nil, err
+	}
+
+	return &model.OAuthPrincipal{
+		TokenID:  stored.ID,
+		ClientID: stored.ClientID,
+		UserID:   user.ID,
+		Email:    user.Email,
+		Role:     user.Role,
+		Scopes:   stored.Scopes,
+		MFA:      stored.MFA,
+	}, nil
+}
+
+// GetConsents lists the clients the user granted access to
+func (ou *oauthUsecaseImpl) GetConsents(userID int) ([]dto.OAuthConsentResponse, error) {
+	consents, err := ou.repository.GetConsents(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.OAuthConsentResponse, 0, len(consents))
+	for _, consent := range consents {
+		responses = append(responses, toOAuthConsentResponse(consent))
+	}
+	return responses, nil
+}
+
+// RevokeConsent withdraws the access the user granted the client, revoking
+// its access tokens; the next authorization asks again
+func (ou *oauthUsecaseImpl) RevokeConsent(ctx context.Context, userID int, clientID string) error {
+	consent, err := ou.repository.GetConsent(userID, clientID)
+	if err != nil {
+		return err
+	}
+	if consent == nil {
+		return ErrOAuthConsentNotFound
+	}
+
+	event, err := newAuditEvent(ctx, model.AuditActionRevoke, model.AuditEntityOAuthClient, clientID, consentAuditFields(consent.Scopes), consentAuditFields(nil))
+	if err != nil {
+		return err
+	}
+	event.ActorID = &userID
+	if err := ou.repository.DeleteConsent(userID, clientID, event); err != nil {
+		if err == sql.ErrNoRows {
+			return ErrOAuthConsentNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// --- Helper Functions ---
+
+// checkAuthorization validates an authorization request and returns the
+// client and the scopes asked for. The consent page must not send the user
+// to an unchecked redirect URI, so every error is shown there
+func (ou *oauthUsecaseImpl) checkAuthorization(request dto.OAuthAuthorizeRequest) (*model.OAuthClient, []string, error) {
+	client, err := ou.repository.GetClient(request.ClientID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if client == nil || client.RevokedAt != nil {
+		return nil, nil, ErrOAuthInvalidClient
+	}
+	if !slices.Contains(client.GrantTypes, model.GrantTypeAuthorizationCode) {
+		return nil, nil, &OAuthError{Code: "unauthorized_client", Description: "the client may not use authorization_code"}
+	}
+	if !slices.Contains(client.RedirectURIs, request.RedirectURI) {
+		return nil, nil, &OAuthError{Code: "invalid_request", Description: "redirect_uri is not registered for the client"}
+	}
+	if request.ResponseType != "code" {
+		return nil, nil, &OAuthError{Code: "unsupported_response_type", Description: "response_type must be code"}
+	}
+	if request.CodeChallengeMethod != "S256" || !validPKCEValue(request.CodeChallenge, 43) {
+		return nil, nil, &OAuthError{Code: "invalid_request", Description: "a code_challenge with code_challenge_method S256 is required"}
+	}
+	scopes, err := requestedScopes(request.Scope, client.Scopes)
+	if err != nil {
+		return nil, nil, err
+	}
+	return client, scopes, nil
+}
+
+// grant records the consent of the user to the scopes, adding them to the
+// ones granted before
+func (ou *oauthUsecaseImpl) grant(ctx context.Context, userID int, clientID string, scopes []string) error {
+	consent, err := ou.repository.GetConsent(userID, clientID)
+	if err != nil {
+		return err
+	}
+	var granted []string
+	if consent != nil {
+		if containsAll(consent.Scopes, scopes) {
+			return nil
+		}
+		granted = consent.Scopes
+	}
+
+	merged := slices.Compact(slices.Sorted(slices.Values(append(slices.Clone(granted), scopes...))))
+	event, err := newAuditEvent(ctx, model.AuditActionGrant, model.AuditEntityOAuthClient, clientID, consentAuditFields(granted), consentAuditFields(merged))
+	if err != nil {
+		return err
+	}
+	// The user approving is the actor, signed in with a token or not
+	event.ActorID = &userID
+	return ou.repository.SaveConsent(model.OAuthConsent{UserID: userID, ClientID: clientID, Scopes: merged}, event)
+}
+
+// authenticateClient checks the client_id and the secret of a confidential
+// client; a public client must send no secret
+func (ou *oauthUsecaseImpl) authenticateClient(clientID, secret string) (*model.OAuthClient, error) {
+	if clientID == "" {
+		return nil, ErrOAuthInvalidClient
+	}
+	client, err := ou.repository.GetClient(clientID)
+	if err != nil {
+		return nil, err
+	}
+	if client == nil || client.RevokedAt != nil {
+		return nil, ErrOAuthInvalidClient
+	}
+	if client.Public() {
+		if secret != "" {
+			return nil, ErrOAuthInvalidClient
+		}
+		return client, nil
+	}
+	if subtle.ConstantTimeCompare([]byte(hashSecretToken(secret)), []byte(client.SecretHash)) != 1 {
+		return nil, ErrOAuthInvalidClient
+	}
+	return client, nil
+}
+
+// exchangeCode takes the authorization code of the request, which must have
+// been issued to the client for the same redirect URI and match the PKCE
+// verifier. A code is gone after the first attempt, right or wrong
+func (ou *oauthUsecaseImpl) exchangeCode(client *model.OAuthClient, request dto.OAuthTokenRequest) (*model.OAuthCode, error) {
+	if request.Code == "" || request.RedirectURI == "" || !validPKCEValue(request.CodeVerifier, 128) {
+		return nil, &OAuthError{Code: "invalid_request", Description: "code, redirect_uri and a code_verifier of 43 to 128 characters are required"}
+	}
+	code, err := ou.repository.ConsumeCode(hashSecretToken(request.Code))
+	if err != nil {
+		return nil, err
+	}
+	if code == nil || code.ClientID != client.ClientID || code.RedirectURI != request.RedirectURI || time.Now().After(code.ExpiresAt) {
+		return nil, ErrOAuthInvalidGrant
+	}
+	if subtle.ConstantTimeCompare([]byte(oidc.CodeChallenge(request.CodeVerifier)), []byte(code.CodeChallenge)) != 1 {
+		return nil, ErrOAuthInvalidGrant
+	}
+	return code, nil
+}
+
+func (ou *oauthUsecaseImpl) issueToken(clientID string, userID int, scopes []string, mfa bool) (*dto.OAuthTokenResponse, error) {
+	secret, err := newSecretToken()
+	if err != nil {
+		return nil, err
+	}
+	token := model.AccessTokenPrefix + secret
+	_, err = ou.repository.CreateToken(model.OAuthToken{
+		TokenHash: hashSecretToken(token),
+		ClientID:  clientID,
+		UserID:    userID,
+		Scopes:    scopes,
+		MFA:       mfa,
+		ExpiresAt: time.Now().Add(ou.policy.AccessTokenTTL),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.OAuthTokenResponse{
+		AccessToken: token,
+		TokenType:   "Bearer",
+		ExpiresIn:   int(ou.policy.AccessTokenTTL.Seconds()),
+		Scope:       strings.Join(scopes, " "),
+	}, nil
+}
+
+// activeToken returns the stored access token and its user, or nil when
+// the token is unknown, revoked or expired, or its user is gone
+func (ou *oauthUsecaseImpl) activeToken(token string) (*model.OAuthToken, *model.User, error) {
+	if !strings.HasPrefix(token, model.AccessTokenPrefix) {
+		return nil, nil, nil
+	}
+	stored, err := ou.repository.GetTokenByHash(hashSecretToken(token))
+	if err != nil {
+		return nil, nil, err
+	}
+	if stored == nil || stored.RevokedAt != nil || !stored.ExpiresAt.After(time.Now()) {
+		return nil, nil, nil
+	}
+	user, err := ou.userRepository.GetUserByID(stored.UserID)
+	if err != nil || user == nil {
+		return nil, nil, err
+	}
+	return stored, user, nil
+}
+
+func validateOAuthClientRequest(request dto.CreateOAuthClientRequest) error {
+	for _, grantType := range request.GrantTypes {
+		if !slices.Contains(model.OAuthGrantTypes, grantType) {
+			return fmt.Errorf("%w: %q", ErrInvalidOAuthGrantType, grantType)
+		}
+	}
+	for _, scope := range request.Scopes {
+		if !slices.Contains(model.APIKeyScopes, scope) {
+			return fmt.Errorf("%w: %q", ErrInvalidOAuthScope, scope)
+		}
+	}
+	if slices.Contains(request.GrantTypes, model.GrantTypeAuthorizationCode) && len(request.RedirectURIs) == 0 {
+		return ErrInvalidRedirectURI
+	}
+	for _, redirectURI := range request.RedirectURIs {
+		if !validRedirectURI(redirectURI) {
+			return fmt.Errorf("%w: %q", ErrInvalidRedirectURI, redirectURI)
+		}
+	}
+	if slices.Contains(request.GrantTypes, model.GrantTypeClientCredentials) && (request.Public || request.UserID == nil) {
+		return ErrInvalidOAuthClientOwner
+	}
+	return nil
+}
+
+// validRedirectURI accepts absolute https URLs without fragment, and http
+// ones on the loopback, for native apps
+func validRedirectURI(raw string) bool {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" || u.Fragment != "" || u.User != nil {
+		return false
+	}
+	switch u.Scheme {
+	case "https":
+		return true
+	case "http":
+		host := u.Hostname()
+		return host == "localhost" || host == "127.0.0.1" || host == "::1"
+	default:
+		return false
+	}
+}
+
+// requestedScopes parses the space separated scopes of a request, which
+// must be among the allowed ones; none asks for all of them
+func requestedScopes(scope string, allowed []string) ([]string, error) {
+	scopes := strings.Fields(scope)
+	if len(scopes) == 0 {
+		return slices.Clone(allowed), nil
+	}
+	for _, s := range scopes {
+		if !slices.Contains(allowed, s) {
+			return nil, &OAuthError{Code: "invalid_scope", Description: fmt.Sprintf("scope %q is not available to the client", s)}
+		}
+	}
+	return slices.Compact(slices.Sorted(slices.Values(scopes))), nil
+}
+
+// validPKCEValue checks a code challenge or verifier: 43 characters or
+// more, up to max, of the unreserved set of RFC 7636
+func validPKCEValue(value string, max int) bool {
+	if len(value) < 43 || len(value) > max {
+		return false
+	}
+	for _, c := range value {
+		if !(c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || strings.ContainsRune("-._~", c)) {
+			return false
+		}
+	}
+	return true
+}
+
+// redirectWith adds the pairs of names and values to the query of the
+// redirect URI, leaving out empty values
+func redirectWith(redirectURI string, pairs ...string) string {
+	u, err := url.Parse(redirectURI)
+	if err != nil {
+		return redirectURI
+	}
+	query := u.Query()
+	for i := 0; i+1 < len(pairs); i += 2 {
+		if pairs[i+1] != "" {
+			query.Set(pairs[i], pairs[i+1])
+		}
+	}
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+func containsAll(set, values []string) bool {
+	for _, value := range values {
+		if !slices.Contains(set, value) {
+			return false
+		}
+	}
+	return true
+}
+
+func newOAuthClientID() (string, error) {
+	id := make([]byte, oauthClientIDSize)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(id), nil
+}
+
+// consentAuditFields records the scopes a user granted a client
+func consentAuditFields(scopes []string) map[string]interface{} {
+	return map[string]interface{}{"scopes": scopes}
+}
+
+func toOAuthClientResponse(client model.OAuthClient) dto.OAuthClientResponse {
+	return dto.OAuthClientResponse{
+		ClientID:     client.ClientID,
+		Name:         client.Name,
+		Public:       client.Public(),
+		GrantTypes:   client.GrantTypes,
+		RedirectURIs: client.RedirectURIs,
+		Scopes:       client.Scopes,
+		UserID:       client.UserID,
+		CreatedAt:    client.CreatedAt,
+		CreatedBy:    client.CreatedBy,
+		RevokedAt:    client.RevokedAt,
+	}
+}
+
+func toOAuthConsentResponse(consent model.OAuthConsent) dto.OAuthConsentResponse {
+	return dto.OAuthConsentResponse{
+		ClientID:   consent.ClientID,
+		ClientName: consent.ClientName,
+		Scopes:     consent.Scopes,
+		CreatedAt:  consent.CreatedAt,
+		UpdatedAt:  consent.UpdatedAt,
+	}
+}
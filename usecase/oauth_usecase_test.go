@@ -0,0 +1,521 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"go-api/dto"
+	"go-api/internal/oidc"
+	"go-api/internal/util"
+	"go-api/model"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakePasswordLogin signs in the user of its token, or returns its error
+type fakePasswordLogin struct {
+	response *dto.LoginResponse
+	err      error
+}
+
+func (f *fakePasswordLogin) Login(ctx context.Context, login dto.LoginRequest) (*dto.LoginResponse, error) {
+	return f.response, f.err
+}
+
+// oauthStore keeps the clients, consents, codes and tokens of the mock
+// repository like the tables
+func oauthStore(repo *MockOAuthRepository, clients ...model.OAuthClient) {
+	consents := map[int]model.OAuthConsent{}
+	codes := map[string]model.OAuthCode{}
+	tokens := map[string]model.OAuthToken{}
+	repo.GetClientFunc = func(clientID string) (*model.OAuthClient, error) {
+		for _, client := range clients {
+			if client.ClientID == clientID {
+				return &client, nil
+			}
+		}
+		return nil, nil
+	}
+	repo.GetConsentFunc = func(userID int, clientID string) (*model.OAuthConsent, error) {
+		consent, ok := consents[userID]
+		if !ok || consent.ClientID != clientID {
+			return nil, nil
+		}
+		return &consent, nil
+	}
+	repo.SaveConsentFunc = func(consent model.OAuthConsent, event model.AuditEvent) error {
+		consents[consent.UserID] = consent
+		return nil
+	}
+	repo.CreateCodeFunc = func(code model.OAuthCode) error {
+		codes[code.CodeHash] = code
+		return nil
+	}
+	repo.ConsumeCodeFunc = func(codeHash string) (*model.OAuthCode, error) {
+		code, ok := codes[codeHash]
+		if !ok {
+			return nil, nil
+		}
+		delete(codes, codeHash)
+		return &code, nil
+	}
+	repo.CreateTokenFunc = func(token model.OAuthToken) (int, error) {
+		token.ID = len(tokens) + 1
+		token.CreatedAt = time.Now()
+		tokens[token.TokenHash] = token
+		return token.ID, nil
+	}
+	repo.GetTokenByHashFunc = func(tokenHash string) (*model.OAuthToken, error) {
+		token, ok := tokens[tokenHash]
+		if !ok {
+			return nil, nil
+		}
+		return &token, nil
+	}
+	repo.RevokeTokenFunc = func(id int) error {
+		for hash, token := range tokens {
+			if token.ID == id {
+				token.RevokedAt = ptrTime(time.Now())
+				tokens[hash] = token
+			}
+		}
+		return nil
+	}
+}
+
+var (
+	oauthVerifier = "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+	oauthWebApp   = model.OAuthClient{
+		ClientID:     "webapp",
+		Name:         "Loja Parceira",
+		SecretHash:   hashSecretToken("webapp-secret"),
+		RedirectURIs: []string{"https://partner.example.com/callback"},
+		GrantTypes:   []string{model.GrantTypeAuthorizationCode},
+		Scopes:       []string{model.ScopeOrdersRead, model.ScopeOrdersWrite},
+	}
+	oauthMobileApp = model.OAuthClient{
+		ClientID:     "mobile",
+		Name:         "App",
+		RedirectURIs: []string{"http://127.0.0.1:8123/cb"},
+		GrantTypes:   []string{model.GrantTypeAuthorizationCode},
+		Scopes:       []string{model.ScopeOrdersRead},
+	}
+	oauthWorker = model.OAuthClient{
+		ClientID:   "worker",
+		Name:       "Armazém",
+		SecretHash: hashSecretToken("worker-secret"),
+		GrantTypes: []string{model.GrantTypeClientCredentials},
+		Scopes:     []string{model.ScopeProductsRead},
+		UserID:     func() *int { id := 9; return &id }(),
+	}
+)
+
+func oauthAuthorizeRequest(client model.OAuthClient) dto.OAuthAuthorizeRequest {
+	return dto.OAuthAuthorizeRequest{
+		ResponseType:        "code",
+		ClientID:            client.ClientID,
+		RedirectURI:         client.RedirectURIs[0],
+		Scope:               model.ScopeOrdersRead,
+		State:               "xyz",
+		CodeChallenge:       oidc.CodeChallenge(oauthVerifier),
+		CodeChallengeMethod: "S256",
+	}
+}
+
+func oauthUserRepository() *MockUserRepository {
+	return &MockUserRepository{
+		GetUserByIDFunc: func(id int) (*model.User, error) {
+			role := model.RoleCustomer
+			if id == 9 {
+				role = model.RoleService
+			}
+			return &model.User{ID: id, Email: "user@example.com", Role: role}, nil
+		},
+	}
+}
+
+func TestOAuthUsecase_CreateClient(t *testing.T) {
+	t.Run("Confidential Client", func(t *testing.T) {
+		var stored model.OAuthClient
+		var event model.AuditEvent
+		repo := &MockOAuthRepository{
+			CreateClientFunc: func(client model.OAuthClient, e model.AuditEvent) (int, error) {
+				stored, event = client, e
+				return 1, nil
+			},
+			GetClientFunc: func(clientID string) (*model.OAuthClient, error) {
+				return &stored, nil
+			},
+		}
+
+		resp, err := NewOAuthUsecase(repo, oauthUserRepository(), nil, DefaultOAuthPolicy).CreateClient(context.Background(), dto.CreateOAuthClientRequest{
+			Name:         "Loja Parceira",
+			GrantTypes:   []string{model.GrantTypeAuthorizationCode},
+			RedirectURIs: []string{"https://partner.example.com/callback"},
+			Scopes:       []string{model.ScopeOrdersRead},
+		})
+
+		assert.NoError(t, err)
+		assert.Len(t, stored.ClientID, 24)
+		assert.Equal(t, hashSecretToken(resp.ClientSecret), stored.SecretHash)
+		assert.False(t, resp.Client.Public)
+		assert.Equal(t, model.AuditEntityOAuthClient, event.EntityType)
+		assert.Equal(t, stored.ClientID, event.EntityID)
+		assert.NotContains(t, string(event.Changes), stored.SecretHash)
+	})
+
+	t.Run("Public Client Gets No Secret", func(t *testing.T) {
+		var stored model.OAuthClient
+		repo := &MockOAuthRepository{
+			CreateClientFunc: func(client model.OAuthClient, e model.AuditEvent) (int, error) {
+				stored = client
+				return 1, nil
+			},
+			GetClientFunc: func(clientID string) (*model.OAuthClient, error) {
+				return &stored, nil
+			},
+		}
+
+		resp, err := NewOAuthUsecase(repo, oauthUserRepository(), nil, DefaultOAuthPolicy).CreateClient(context.Background(), dto.CreateOAuthClientRequest{
+			Name:         "App",
+			Public:       true,
+			GrantTypes:   []string{model.GrantTypeAuthorizationCode},
+			RedirectURIs: []string{"http://localhost:8123/cb"},
+		})
+
+		assert.NoError(t, err)
+		assert.Empty(t, resp.ClientSecret)
+		assert.True(t, resp.Client.Public)
+	})
+
+	owner := 4
+	service := 9
+	invalid := []struct {
+		name    string
+		request dto.CreateOAuthClientRequest
+		err     error
+	}{
+		{"Unknown Grant", dto.CreateOAuthClientRequest{Name: "x", GrantTypes: []string{"password"}}, ErrInvalidOAuthGrantType},
+		{"Unknown Scope", dto.CreateOAuthClientRequest{Name: "x", GrantTypes: []string{model.GrantTypeClientCredentials}, Scopes: []string{"root"}, UserID: &service}, ErrInvalidOAuthScope},
+		{"No Redirect URI", dto.CreateOAuthClientRequest{Name: "x", GrantTypes: []string{model.GrantTypeAuthorizationCode}}, ErrInvalidRedirectURI},
+		{"Plain HTTP Redirect", dto.CreateOAuthClientRequest{Name: "x", GrantTypes: []string{model.GrantTypeAuthorizationCode}, RedirectURIs: []string{"http://partner.example.com/cb"}}, ErrInvalidRedirectURI},
+		{"Redirect With Fragment", dto.CreateOAuthClientRequest{Name: "x", GrantTypes: []string{model.GrantTypeAuthorizationCode}, RedirectURIs: []string{"https://partner.example.com/cb#x"}}, ErrInvalidRedirectURI},
+		{"Public Client Credentials", dto.CreateOAuthClientRequest{Name: "x", Public: true, GrantTypes: []string{model.GrantTypeClientCredentials}, UserID: &service}, ErrInvalidOAuthClientOwner},
+		{"Customer Behind Client Credentials", dto.CreateOAuthClientRequest{Name: "x", GrantTypes: []string{model.GrantTypeClientCredentials}, UserID: &owner}, ErrInvalidOAuthClientOwner},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewOAuthUsecase(&MockOAuthRepository{}, oauthUserRepository(), nil, DefaultOAuthPolicy).CreateClient(context.Background(), tt.request)
+
+			assert.True(t, errors.Is(err, tt.err), "got %v", err)
+		})
+	}
+}
+
+func TestOAuthUsecase_Authorize(t *testing.T) {
+	t.Run("Rejects An Unregistered Redirect URI", func(t *testing.T) {
+		repo := &MockOAuthRepository{}
+		oauthStore(repo, oauthWebApp)
+		request := oauthAuthorizeRequest(oauthWebApp)
+		request.RedirectURI = "https://evil.example.com/callback"
+
+		_, err := NewOAuthUsecase(repo, oauthUserRepository(), nil, DefaultOAuthPolicy).GetAuthorization(4, request)
+
+		var oauthErr *OAuthError
+		assert.True(t, errors.As(err, &oauthErr))
+		assert.Equal(t, "invalid_request", oauthErr.Code)
+	})
+
+	t.Run("Requires PKCE", func(t *testing.T) {
+		repo := &MockOAuthRepository{}
+		oauthStore(repo, oauthWebApp)
+		request := oauthAuthorizeRequest(oauthWebApp)
+		request.CodeChallengeMethod = "plain"
+
+		_, err := NewOAuthUsecase(repo, oauthUserRepository(), nil, DefaultOAuthPolicy).GetAuthorization(4, request)
+
+		var oauthErr *OAuthError
+		assert.True(t, errors.As(err, &oauthErr))
+		assert.Equal(t, "invalid_request", oauthErr.Code)
+	})
+
+	t.Run("Denied", func(t *testing.T) {
+		repo := &MockOAuthRepository{}
+		oauthStore(repo, oauthWebApp)
+
+		resp, err := NewOAuthUsecase(repo, oauthUserRepository(), nil, DefaultOAuthPolicy).Authorize(context.Background(), 4, false, dto.OAuthApproveRequest{OAuthAuthorizeRequest: oauthAuthorizeRequest(oauthWebApp)})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "https://partner.example.com/callback?error=access_denied&state=xyz", resp.RedirectURI)
+	})
+
+	t.Run("Records The Consent", func(t *testing.T) {
+		repo := &MockOAuthRepository{}
+		oauthStore(repo, oauthWebApp)
+		var event model.AuditEvent
+		save := repo.SaveConsentFunc
+		repo.SaveConsentFunc = func(consent model.OAuthConsent, e model.AuditEvent) error {
+			event = e
+			return save(consent, e)
+		}
+		usecase := NewOAuthUsecase(repo, oauthUserRepository(), nil, DefaultOAuthPolicy)
+
+		before, err := usecase.GetAuthorization(4, oauthAuthorizeRequest(oauthWebApp))
+		assert.NoError(t, err)
+		assert.True(t, before.ConsentRequired)
+		_, err = usecase.Authorize(context.Background(), 4, false, dto.OAuthApproveRequest{OAuthAuthorizeRequest: oauthAuthorizeRequest(oauthWebApp), Approve: true})
+		assert.NoError(t, err)
+		after, err := usecase.GetAuthorization(4, oauthAuthorizeRequest(oauthWebApp))
+
+		assert.NoError(t, err)
+		assert.False(t, after.ConsentRequired)
+		assert.Equal(t, model.AuditActionGrant, event.Action)
+		assert.Equal(t, 4, *event.ActorID)
+	})
+
+	t.Run("Signs In With The Password", func(t *testing.T) {
+		repo := &MockOAuthRepository{}
+		oauthStore(repo, oauthWebApp)
+		var code model.OAuthCode
+		repo.CreateCodeFunc = func(c model.OAuthCode) error {
+			code = c
+			return nil
+		}
+		token, err := util.GenerateToken("user@example.com", 4, model.RoleCustomer, true)
+		assert.NoError(t, err)
+
+		resp, err := NewOAuthUsecase(repo, oauthUserRepository(), &fakePasswordLogin{response: &dto.LoginResponse{Token: token}}, DefaultOAuthPolicy).Authorize(context.Background(), 0, false, dto.OAuthApproveRequest{
+			OAuthAuthorizeRequest: oauthAuthorizeRequest(oauthWebApp),
+			Approve:               true,
+			Email:                 "user@example.com",
+			Password:              "secret",
+		})
+
+		assert.NoError(t, err)
+		redirect, err := url.Parse(resp.RedirectURI)
+		assert.NoError(t, err)
+		assert.Equal(t, "xyz", redirect.Query().Get("state"))
+		assert.Equal(t, hashSecretToken(redirect.Query().Get("code")), code.CodeHash)
+		assert.Equal(t, 4, code.UserID)
+		assert.True(t, code.MFA)
+		assert.Equal(t, []string{model.ScopeOrdersRead}, code.Scopes)
+	})
+
+	t.Run("Passes On The Second Factor Challenge", func(t *testing.T) {
+		repo := &MockOAuthRepository{}
+		oauthStore(repo, oauthWebApp)
+
+		resp, err := NewOAuthUsecase(repo, oauthUserRepository(), &fakePasswordLogin{response: &dto.LoginResponse{MFARequired: true, MFAToken: "challenge"}}, DefaultOAuthPolicy).Authorize(context.Background(), 0, false, dto.OAuthApproveRequest{
+			OAuthAuthorizeRequest: oauthAuthorizeRequest(oauthWebApp),
+			Approve:               true,
+			Email:                 "user@example.com",
+			Password:              "secret",
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, &dto.OAuthAuthorizeResponse{MFARequired: true, MFAToken: "challenge"}, resp)
+	})
+
+	t.Run("Wrong Password", func(t *testing.T) {
+		repo := &MockOAuthRepository{}
+		oauthStore(repo, oauthWebApp)
+
+		_, err := NewOAuthUsecase(repo, oauthUserRepository(), &fakePasswordLogin{err: ErrInvalidCredentials}, DefaultOAuthPolicy).Authorize(context.Background(), 0, false, dto.OAuthApproveRequest{
+			OAuthAuthorizeRequest: oauthAuthorizeRequest(oauthWebApp),
+			Approve:               true,
+			Email:                 "user@example.com",
+			Password:              "wrong",
+		})
+
+		assert.ErrorIs(t, err, ErrInvalidCredentials)
+	})
+
+	t.Run("No Session Nor Password", func(t *testing.T) {
+		repo := &MockOAuthRepository{}
+		oauthStore(repo, oauthWebApp)
+
+		_, err := NewOAuthUsecase(repo, oauthUserRepository(), &fakePasswordLogin{}, DefaultOAuthPolicy).Authorize(context.Background(), 0, false, dto.OAuthApproveRequest{OAuthAuthorizeRequest: oauthAuthorizeRequest(oauthWebApp), Approve: true})
+
+		assert.Equal(t, ErrOAuthLoginRequired, err)
+	})
+}
+
+func TestOAuthUsecase_Token(t *testing.T) {
+	// authorize returns a fresh code for user 4
+	authorize := func(t *testing.T, usecase OAuthUsecase, client model.OAuthClient) string {
+		resp, err := usecase.Authorize(context.Background(), 4, true, dto.OAuthApproveRequest{OAuthAuthorizeRequest: oauthAuthorizeRequest(client), Approve: true})
+		assert.NoError(t, err)
+		redirect, err := url.Parse(resp.RedirectURI)
+		assert.NoError(t, err)
+		return redirect.Query().Get("code")
+	}
+	exchange := func(client model.OAuthClient, secret, code string) dto.OAuthTokenRequest {
+		return dto.OAuthTokenRequest{
+			GrantType:    model.GrantTypeAuthorizationCode,
+			Code:         code,
+			RedirectURI:  client.RedirectURIs[0],
+			CodeVerifier: oauthVerifier,
+			ClientID:     client.ClientID,
+			ClientSecret: secret,
+		}
+	}
+
+	t.Run("Exchanges A Code Once", func(t *testing.T) {
+		repo := &MockOAuthRepository{}
+		oauthStore(repo, oauthWebApp)
+		usecase := NewOAuthUsecase(repo, oauthUserRepository(), nil, DefaultOAuthPolicy)
+		code := authorize(t, usecase, oauthWebApp)
+
+		resp, err := usecase.Token(context.Background(), exchange(oauthWebApp, "webapp-secret", code))
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Bearer", resp.TokenType)
+		assert.Equal(t, 3600, resp.ExpiresIn)
+		assert.Equal(t, model.ScopeOrdersRead, resp.Scope)
+		principal, err := usecase.AuthenticateAccessToken(context.Background(), resp.AccessToken)
+		assert.NoError(t, err)
+		assert.Equal(t, "webapp", principal.ClientID)
+		assert.Equal(t, 4, principal.UserID)
+		assert.True(t, principal.MFA)
+		assert.Equal(t, []string{model.ScopeOrdersRead}, principal.Scopes)
+
+		_, err = usecase.Token(context.Background(), exchange(oauthWebApp, "webapp-secret", code))
+		assert.Equal(t, ErrOAuthInvalidGrant, err)
+	})
+
+	t.Run("Public Client", func(t *testing.T) {
+		repo := &MockOAuthRepository{}
+		oauthStore(repo, oauthMobileApp)
+		usecase := NewOAuthUsecase(repo, oauthUserRepository(), nil, DefaultOAuthPolicy)
+
+		_, err := usecase.Token(context.Background(), exchange(oauthMobileApp, "", authorize(t, usecase, oauthMobileApp)))
+
+		assert.NoError(t, err)
+	})
+
+	rejected := []struct {
+		name   string
+		change func(*dto.OAuthTokenRequest)
+		err    string
+	}{
+		{"Wrong Secret", func(r *dto.OAuthTokenRequest) { r.ClientSecret = "wrong" }, "invalid_client"},
+		{"Wrong Verifier", func(r *dto.OAuthTokenRequest) { r.CodeVerifier = oauthVerifier[1:] + "A" }, "invalid_grant"},
+		{"Short Verifier", func(r *dto.OAuthTokenRequest) { r.CodeVerifier = "short" }, "invalid_request"},
+		{"Wrong Redirect URI", func(r *dto.OAuthTokenRequest) { r.RedirectURI = "https://partner.example.com/other" }, "invalid_grant"},
+		{"Grant Of Another Client", func(r *dto.OAuthTokenRequest) { r.GrantType = model.GrantTypeClientCredentials }, "unauthorized_client"},
+		{"Unknown Grant", func(r *dto.OAuthTokenRequest) { r.GrantType = "password" }, "unsupported_grant_type"},
+	}
+	for _, tt := range rejected {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &MockOAuthRepository{}
+			oauthStore(repo, oauthWebApp)
+			usecase := NewOAuthUsecase(repo, oauthUserRepository(), nil, DefaultOAuthPolicy)
+			request := exchange(oauthWebApp, "webapp-secret", authorize(t, usecase, oauthWebApp))
+			tt.change(&request)
+
+			_, err := usecase.Token(context.Background(), request)
+
+			var oauthErr *OAuthError
+			assert.True(t, errors.As(err, &oauthErr), "got %v", err)
+			assert.Equal(t, tt.err, oauthErr.Code)
+		})
+	}
+
+	t.Run("Expired Code", func(t *testing.T) {
+		repo := &MockOAuthRepository{}
+		oauthStore(repo, oauthWebApp)
+		usecase := NewOAuthUsecase(repo, oauthUserRepository(), nil, OAuthPolicy{CodeTTL: -time.Second, AccessTokenTTL: time.Hour})
+
+		_, err := usecase.Token(context.Background(), exchange(oauthWebApp, "webapp-secret", authorize(t, usecase, oauthWebApp)))
+
+		assert.Equal(t, ErrOAuthInvalidGrant, err)
+	})
+
+	t.Run("Client Credentials", func(t *testing.T) {
+		repo := &MockOAuthRepository{}
+		oauthStore(repo, oauthWorker)
+		usecase := NewOAuthUsecase(repo, oauthUserRepository(), nil, DefaultOAuthPolicy)
+
+		resp, err := usecase.Token(context.Background(), dto.OAuthTokenRequest{GrantType: model.GrantTypeClientCredentials, ClientID: "worker", ClientSecret: "worker-secret"})
+
+		assert.NoError(t, err)
+		principal, err := usecase.AuthenticateAccessToken(context.Background(), resp.AccessToken)
+		assert.NoError(t, err)
+		assert.Equal(t, 9, principal.UserID)
+		assert.Equal(t, model.RoleService, principal.Role)
+		assert.Equal(t, []string{model.ScopeProductsRead}, principal.Scopes)
+
+		_, err = usecase.Token(context.Background(), dto.OAuthTokenRequest{GrantType: model.GrantTypeClientCredentials, Scope: model.ScopeOrdersWrite, ClientID: "worker", ClientSecret: "worker-secret"})
+		var oauthErr *OAuthError
+		assert.True(t, errors.As(err, &oauthErr))
+		assert.Equal(t, "invalid_scope", oauthErr.Code)
+	})
+}
+
+func TestOAuthUsecase_IntrospectAndRevoke(t *testing.T) {
+	repo := &MockOAuthRepository{}
+	oauthStore(repo, oauthWorker, oauthWebApp)
+	usecase := NewOAuthUsecase(repo, oauthUserRepository(), nil, DefaultOAuthPolicy)
+	issued, err := usecase.Token(context.Background(), dto.OAuthTokenRequest{GrantType: model.GrantTypeClientCredentials, ClientID: "worker", ClientSecret: "worker-secret"})
+	assert.NoError(t, err)
+	worker := dto.OAuthTokenActionRequest{Token: issued.AccessToken, ClientID: "worker", ClientSecret: "worker-secret"}
+	other := dto.OAuthTokenActionRequest{Token: issued.AccessToken, ClientID: "webapp", ClientSecret: "webapp-secret"}
+
+	active, err := usecase.Introspect(context.Background(), worker)
+	assert.NoError(t, err)
+	assert.True(t, active.Active)
+	assert.Equal(t, "9", active.Sub)
+	assert.Equal(t, "worker", active.ClientID)
+	assert.Equal(t, model.ScopeProductsRead, active.Scope)
+
+	hidden, err := usecase.Introspect(context.Background(), other)
+	assert.NoError(t, err)
+	assert.False(t, hidden.Active)
+
+	// Another client cannot revoke the token, and is not told so
+	assert.NoError(t, usecase.Revoke(context.Background(), other))
+	active, err = usecase.Introspect(context.Background(), worker)
+	assert.NoError(t, err)
+	assert.True(t, active.Active)
+
+	assert.NoError(t, usecase.Revoke(context.Background(), worker))
+	revoked, err := usecase.Introspect(context.Background(), worker)
+	assert.NoError(t, err)
+	assert.False(t, revoked.Active)
+	principal, err := usecase.AuthenticateAccessToken(context.Background(), issued.AccessToken)
+	assert.NoError(t, err)
+	assert.Nil(t, principal)
+
+	assert.NoError(t, usecase.Revoke(context.Background(), dto.OAuthTokenActionRequest{Token: "gat_unknown", ClientID: "worker", ClientSecret: "worker-secret"}))
+	_, err = usecase.Introspect(context.Background(), dto.OAuthTokenActionRequest{Token: issued.AccessToken, ClientID: "worker", ClientSecret: "wrong"})
+	assert.Equal(t, ErrOAuthInvalidClient, err)
+}
+
+func TestOAuthUsecase_RevokeConsent(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		var event model.AuditEvent
+		repo := &MockOAuthRepository{
+			GetConsentFunc: func(userID int, clientID string) (*model.OAuthConsent, error) {
+				return &model.OAuthConsent{UserID: userID, ClientID: clientID, Scopes: []string{model.ScopeOrdersRead}}, nil
+			},
+			DeleteConsentFunc: func(userID int, clientID string, e model.AuditEvent) error {
+				event = e
+				return nil
+			},
+		}
+
+		err := NewOAuthUsecase(repo, oauthUserRepository(), nil, DefaultOAuthPolicy).RevokeConsent(context.Background(), 4, "webapp")
+
+		assert.NoError(t, err)
+		assert.Equal(t, model.AuditActionRevoke, event.Action)
+		assert.Equal(t, "webapp", event.EntityID)
+	})
+
+	t.Run("No Consent", func(t *testing.T) {
+		err := NewOAuthUsecase(&MockOAuthRepository{}, oauthUserRepository(), nil, DefaultOAuthPolicy).RevokeConsent(context.Background(), 4, "webapp")
+
+		assert.Equal(t, ErrOAuthConsentNotFound, err)
+	})
+}
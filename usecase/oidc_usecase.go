@@ -0,0 +1,321 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"go-api/dto"
+	"go-api/internal/oidc"
+	"go-api/internal/util"
+	"go-api/model"
+	"go-api/repository"
+	"log"
+	"slices"
+	"strconv"
+	"time"
+)
+
+var (
+	ErrUnknownOIDCProvider = errors.New("unknown identity provider")
+	ErrInvalidOIDCState    = errors.New("invalid or expired sign in state")
+	ErrOIDCSignInFailed    = errors.New("sign in at the identity provider failed")
+	ErrOIDCAccountNotFound = errors.New("no account is linked to this identity")
+	ErrOIDCIdentityTaken   = errors.New("identity already linked to an account, or the account already has one of this provider")
+	ErrOIDCNotLinked       = errors.New("no identity of this provider is linked to the account")
+)
+
+// OIDCProvider is an OpenID Connect provider users sign in with;
+// *oidc.Provider implements it
+type OIDCProvider interface {
+	AuthCodeURL(ctx context.Context, state, nonce, codeChallenge string) (string, error)
+	Exchange(ctx context.Context, code, codeVerifier, nonce string) (*oidc.IDToken, error)
+}
+
+// OIDCPolicy holds how signing in with identity providers works
+type OIDCPolicy struct {
+	// StateTTL is how long a sign in started waits for the user to come back
+	StateTTL time.Duration
+	// LinkByEmail are the providers trusted to link an identity on first
+	// sign in to the user with the same verified email. Only list providers
+	// that own the emails they verify
+	LinkByEmail []string
+	// MFARequiredRoles are the roles told to enroll in two-factor
+	// authentication when the provider did not report it either
+	MFARequiredRoles []string
+}
+
+// DefaultOIDCPolicy gives 10 minutes to sign in and links identities only
+// explicitly
+var DefaultOIDCPolicy = OIDCPolicy{
+	StateTTL: 10 * time.Minute,
+}
+
+// OIDCUsecase defines the contract for signing in with identity providers
+type OIDCUsecase interface {
+	Authorize(ctx context.Context, provider string, linkUserID int) (*dto.OIDCAuthorizeResponse, error)
+	Callback(ctx context.Context, provider string, userID int, request dto.OIDCCallbackRequest) (*dto.LoginResponse, error)
+	GetIdentities(userID int) ([]dto.UserIdentityResponse, error)
+	Unlink(ctx context.Context, userID int, provider string) error
+}
+
+type oidcUsecaseImpl struct {
+	repository     repository.OIDCRepositoryInterface
+	userRepository repository.UserRepositoryInterface
+	mfaRepository  repository.MFARepositoryInterface
+	providers      map[string]OIDCProvider
+	policy         OIDCPolicy
+	carts          CartMerger
+}
+
+// NewOIDCUsecase creates a new instance of OIDCUsecase for the providers,
+// keyed by the name used in the routes
+func NewOIDCUsecase(repo repository.OIDCRepositoryInterface, userRepo repository.UserRepositoryInterface, mfaRepo repository.MFARepositoryInterface, providers map[string]OIDCProvider, policy OIDCPolicy, carts CartMerger) OIDCUsecase {
+	return &oidcUsecaseImpl{
+		repository:     repo,
+		userRepository: userRepo,
+		mfaRepository:  mfaRepo,
+		providers:      providers,
+		policy:         policy,
+		carts:          carts,
+	}
+}
+
+// Authorize starts a sign in at the provider, or the linking of an identity
+// to the account of linkUserID when not 0. Only the hash of the state is
+// kept, along with the nonce and the PKCE verifier
+func (ou *oidcUsecaseImpl) Authorize(ctx context.Context, provider string, linkUserID int) (*dto.OIDCAuthorizeResponse, error) {
+	idp, ok := ou.providers[provider]
+	if !ok {
+		return nil, ErrUnknownOIDCProvider
+	}
+
+	state, err := newSecretToken()
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := newSecretToken()
+	if err != nil {
+		return nil, err
+	}
+	verifier, err := oidc.NewCodeVerifier()
+	if err != nil {
+		return nil, err
+	}
+	authURL, err := idp.AuthCodeURL(ctx, state, nonce, oidc.CodeChallenge(verifier))
+	if err != nil {
+		log.Printf("oidc provider %s: %v", provider, err)
+		return nil, ErrOIDCSignInFailed
+	}
+
+	err = ou.repository.CreateLoginState(model.OIDCLoginState{
+		StateHash:    hashSecretToken(state),
+		Provider:     provider,
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		LinkUserID:   linkUserID,
+		ExpiresAt:    time.Now().Add(ou.policy.StateTTL),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &dto.OIDCAuthorizeResponse{AuthorizationURL: authURL, State: state}, nil
+}
+
+// Callback finishes a sign in with the code the provider sent back. The
+// identity signs in the user it is linked to; an unknown one is linked on
+// the way when the state was started to link it, by userID, or when the
+// provider may link by email. The answer is that of a password login
+func (ou *oidcUsecaseImpl) Callback(ctx context.Context, provider string, userID int, request dto.OIDCCallbackRequest) (*dto.LoginResponse, error) {
+	idp, ok := ou.providers[provider]
+	if !ok {
+		return nil, ErrUnknownOIDCProvider
+	}
+	// The state goes even when the provider reports an error, so it cannot
+	// be tried again
+	state, err := ou.repository.ConsumeLoginState(hashSecretToken(request.State))
+	if err != nil {
+		return nil, err
+	}
+	if state == nil || state.Provider != provider || time.Now().After(state.ExpiresAt) {
+		return nil, ErrInvalidOIDCState
+	}
+	// A link finishes in the session that started it, so nobody can have
+	// their identity linked to someone else's account
+	if state.LinkUserID != 0 && state.LinkUserID != userID {
+		return nil, ErrInvalidOIDCState
+	}
+	if request.Error != "" || request.Code == "" {
+		return nil, ErrOIDCSignInFailed
+	}
+
+	token, err := idp.Exchange(ctx, request.Code, state.CodeVerifier, state.Nonce)
+	if err != nil {
+		log.Printf("oidc provider %s: %v", provider, err)
+		return nil, ErrOIDCSignInFailed
+	}
+
+	user, err := ou.resolveUser(ctx, provider, state.LinkUserID, token)
+	if err != nil {
+		return nil, err
+	}
+	// Service accounts only use API keys
+	if user.Role == model.RoleService {
+		return nil, ErrOIDCAccountNotFound
+	}
+	return ou.signIn(user, token, request.CartToken)
+}
+
+// GetIdentities lists the identities linked to the user
+func (ou *oidcUsecaseImpl) GetIdentities(userID int) ([]dto.UserIdentityResponse, error) {
+	identities, err := ou.repository.GetIdentities(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.UserIdentityResponse, 0, len(identities))
+	for _, identity := range identities {
+		responses = append(responses, toUserIdentityResponse(identity))
+	}
+	return responses, nil
+}
+
+// Unlink removes the identity of the provider from the user. Users always
+// keep their password, so they cannot lock themselves out
+func (ou *oidcUsecaseImpl) Unlink(ctx context.Context, userID int, provider string) error {
+	if _, ok := ou.providers[provider]; !ok {
+		return ErrUnknownOIDCProvider
+	}
+	event, err := newAuditEvent(ctx, model.AuditActionUnlink, model.AuditEntityUser, strconv.Itoa(userID), map[string]interface{}{"provider": provider}, nil)
+	if err != nil {
+		return err
+	}
+	if err := ou.repository.UnlinkIdentity(userID, provider, event); err != nil {
+		if err == sql.ErrNoRows {
+			return ErrOIDCNotLinked
+		}
+		return err
+	}
+	return nil
+}
+
+// --- Helper Functions ---
+
+// resolveUser returns the user the identity of the token belongs to,
+// linking it first to linkUserID or, when the provider is trusted to, to
+// the user with the same verified email
+func (ou *oidcUsecaseImpl) resolveUser(ctx context.Context, provider string, linkUserID int, token *oidc.IDToken) (*model.User, error) {
+	identity, err := ou.repository.GetIdentity(provider, token.Subject)
+	if err != nil {
+		return nil, err
+	}
+	if identity != nil {
+		if linkUserID != 0 && identity.UserID != linkUserID {
+			return nil, ErrOIDCIdentityTaken
+		}
+		if err := ou.repository.TouchIdentity(identity.ID, token.Email); err != nil {
+			log.Printf("touch identity %d: %v", identity.ID, err)
+		}
+		return ou.getUser(identity.UserID)
+	}
+
+	var user *model.User
+	switch {
+	case linkUserID != 0:
+		if user, err = ou.getUser(linkUserID); err != nil {
+			return nil, err
+		}
+	case slices.Contains(ou.policy.LinkByEmail, provider) && token.EmailVerified && token.Email != "":
+		// Both sides must have verified the email, or whoever registered it
+		// first on one side would take over the account on the other
+		if user, err = ou.userRepository.GetUserByEmail(token.Email); err != nil {
+			return nil, err
+		}
+		if user == nil || user.EmailVerifiedAt == nil || user.Role == model.RoleService {
+			return nil, ErrOIDCAccountNotFound
+		}
+	default:
+		return nil, ErrOIDCAccountNotFound
+	}
+
+	if err := ou.link(ctx, user.ID, provider, token); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// link records the identity of the token as the user's
+func (ou *oidcUsecaseImpl) link(ctx context.Context, userID int, provider string, token *oidc.IDToken) error {
+	identity := model.UserIdentity{
+		UserID:   userID,
+		Provider: provider,
+		Subject:  token.Subject,
+		Email:    token.Email,
+	}
+	event, err := newAuditEvent(ctx, model.AuditActionLink, model.AuditEntityUser, strconv.Itoa(userID), nil, identity)
+	if err != nil {
+		return err
+	}
+	if err := ou.repository.LinkIdentity(identity, event); err != nil {
+		if err == sql.ErrNoRows {
+			return ErrOIDCIdentityTaken
+		}
+		return err
+	}
+	return nil
+}
+
+func (ou *oidcUsecaseImpl) getUser(id int) (*model.User, error) {
+	user, err := ou.userRepository.GetUserByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrOIDCAccountNotFound
+	}
+	return user, nil
+}
+
+// signIn issues our token. A user with two-factor authentication on gets
+// the MFA challenge of a password login instead, unless the provider
+// reports a login with more than one factor
+func (ou *oidcUsecaseImpl) signIn(user *model.User, token *oidc.IDToken, cartToken string) (*dto.LoginResponse, error) {
+	mfa := token.MFA()
+	if !mfa && ou.mfaRepository != nil {
+		userMFA, err := ou.mfaRepository.GetMFA(user.ID)
+		if err != nil {
+			return nil, err
+		}
+		if userMFA != nil && userMFA.ConfirmedAt != nil {
+			challenge, err := util.GenerateChallengeToken(user.ID, mfaChallengeTTL)
+			if err != nil {
+				return nil, err
+			}
+			return &dto.LoginResponse{MFARequired: true, MFAToken: challenge}, nil
+		}
+	}
+
+	accessToken, err := util.GenerateToken(user.Email, user.ID, user.Role, mfa)
+	if err != nil {
+		return nil, err
+	}
+
+	if ou.carts != nil && cartToken != "" {
+		if err := ou.carts.MergeCart(user.ID, cartToken); err != nil {
+			return nil, err
+		}
+	}
+
+	return &dto.LoginResponse{
+		Token:                 accessToken,
+		MFAEnrollmentRequired: !mfa && slices.Contains(ou.policy.MFARequiredRoles, user.Role),
+	}, nil
+}
+
+func toUserIdentityResponse(identity model.UserIdentity) dto.UserIdentityResponse {
+	return dto.UserIdentityResponse{
+		Provider:    identity.Provider,
+		Email:       identity.Email,
+		CreatedAt:   identity.CreatedAt,
+		LastLoginAt: identity.LastLoginAt,
+	}
+}
@@ -0,0 +1,461 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"go-api/dto"
+	"go-api/internal/oidc"
+	"go-api/internal/oidc/oidctest"
+	"go-api/internal/util"
+	"go-api/model"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeOIDCProvider signs in whoever its token names, for a code "good-code"
+type fakeOIDCProvider struct {
+	token *oidc.IDToken
+}
+
+func (f *fakeOIDCProvider) AuthCodeURL(ctx context.Context, state, nonce, codeChallenge string) (string, error) {
+	return "https://idp.example.com/authorize?state=" + state + "&code_challenge=" + codeChallenge, nil
+}
+
+func (f *fakeOIDCProvider) Exchange(ctx context.Context, code, codeVerifier, nonce string) (*oidc.IDToken, error) {
+	if code != "good-code" {
+		return nil, errors.New("invalid_grant")
+	}
+	return f.token, nil
+}
+
+// stateStore keeps the login states of the mock repository like the table
+func stateStore(repo *MockOIDCRepository) {
+	states := map[string]model.OIDCLoginState{}
+	repo.CreateLoginStateFunc = func(state model.OIDCLoginState) error {
+		states[state.StateHash] = state
+		return nil
+	}
+	repo.ConsumeLoginStateFunc = func(stateHash string) (*model.OIDCLoginState, error) {
+		state, ok := states[stateHash]
+		if !ok {
+			return nil, nil
+		}
+		delete(states, stateHash)
+		return &state, nil
+	}
+}
+
+func TestOIDCUsecase_Authorize(t *testing.T) {
+	provider := &fakeOIDCProvider{}
+
+	t.Run("Stores The Hash Of The State", func(t *testing.T) {
+		var stored model.OIDCLoginState
+		repo := &MockOIDCRepository{
+			CreateLoginStateFunc: func(state model.OIDCLoginState) error {
+				stored = state
+				return nil
+			},
+		}
+
+		usecase := NewOIDCUsecase(repo, &MockUserRepository{}, nil, map[string]OIDCProvider{"corp": provider}, DefaultOIDCPolicy, nil)
+		resp, err := usecase.Authorize(context.Background(), "corp", 0)
+
+		assert.NoError(t, err)
+		assert.Equal(t, hashSecretToken(resp.State), stored.StateHash)
+		assert.Equal(t, "corp", stored.Provider)
+		assert.NotEmpty(t, stored.Nonce)
+		assert.Zero(t, stored.LinkUserID)
+		assert.WithinDuration(t, time.Now().Add(10*time.Minute), stored.ExpiresAt, time.Minute)
+		authURL, err := url.Parse(resp.AuthorizationURL)
+		assert.NoError(t, err)
+		assert.Equal(t, resp.State, authURL.Query().Get("state"))
+		assert.Equal(t, oidc.CodeChallenge(stored.CodeVerifier), authURL.Query().Get("code_challenge"))
+	})
+
+	t.Run("Unknown Provider", func(t *testing.T) {
+		usecase := NewOIDCUsecase(&MockOIDCRepository{}, &MockUserRepository{}, nil, map[string]OIDCProvider{"corp": provider}, DefaultOIDCPolicy, nil)
+		resp, err := usecase.Authorize(context.Background(), "other", 0)
+
+		assert.Nil(t, resp)
+		assert.Equal(t, ErrUnknownOIDCProvider, err)
+	})
+}
+
+func TestOIDCUsecase_Callback(t *testing.T) {
+	verifiedAt := time.Now().Add(-time.Hour)
+	ana := &model.User{ID: 7, Email: "ana@corp.example", Role: model.RoleCustomer, EmailVerifiedAt: &verifiedAt}
+	userRepo := &MockUserRepository{
+		GetUserByIDFunc: func(id int) (*model.User, error) {
+			if id == ana.ID {
+				return ana, nil
+			}
+			return nil, nil
+		},
+		GetUserByEmailFunc: func(email string) (*model.User, error) {
+			if email == ana.Email {
+				return ana, nil
+			}
+			return nil, nil
+		},
+	}
+	token := &oidc.IDToken{Subject: "248289761001", Email: "ana@corp.example", EmailVerified: true}
+
+	// start runs Authorize and returns the state to call back with
+	start := func(t *testing.T, usecase OIDCUsecase, linkUserID int) string {
+		resp, err := usecase.Authorize(context.Background(), "corp", linkUserID)
+		assert.NoError(t, err)
+		return resp.State
+	}
+
+	t.Run("Signs In A Linked Identity", func(t *testing.T) {
+		var touched int
+		repo := &MockOIDCRepository{
+			GetIdentityFunc: func(provider, subject string) (*model.UserIdentity, error) {
+				assert.Equal(t, "corp", provider)
+				assert.Equal(t, "248289761001", subject)
+				return &model.UserIdentity{ID: 3, UserID: 7, Provider: provider, Subject: subject}, nil
+			},
+			TouchIdentityFunc: func(id int, email string) error {
+				touched = id
+				return nil
+			},
+		}
+		stateStore(repo)
+
+		usecase := NewOIDCUsecase(repo, userRepo, &MockMFARepository{}, map[string]OIDCProvider{"corp": &fakeOIDCProvider{token: token}}, DefaultOIDCPolicy, nil)
+		resp, err := usecase.Callback(context.Background(), "corp", 0, dto.OIDCCallbackRequest{Code: "good-code", State: start(t, usecase, 0)})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, touched)
+		claims, err := util.ParseToken(resp.Token)
+		assert.NoError(t, err)
+		assert.Equal(t, 7, claims.UserID)
+		assert.False(t, claims.MFA)
+	})
+
+	t.Run("State Works Once", func(t *testing.T) {
+		repo := &MockOIDCRepository{
+			GetIdentityFunc: func(provider, subject string) (*model.UserIdentity, error) {
+				return &model.UserIdentity{ID: 3, UserID: 7}, nil
+			},
+		}
+		stateStore(repo)
+
+		usecase := NewOIDCUsecase(repo, userRepo, nil, map[string]OIDCProvider{"corp": &fakeOIDCProvider{token: token}}, DefaultOIDCPolicy, nil)
+		state := start(t, usecase, 0)
+		_, err := usecase.Callback(context.Background(), "corp", 0, dto.OIDCCallbackRequest{Code: "good-code", State: state})
+		assert.NoError(t, err)
+		resp, err := usecase.Callback(context.Background(), "corp", 0, dto.OIDCCallbackRequest{Code: "good-code", State: state})
+
+		assert.Nil(t, resp)
+		assert.Equal(t, ErrInvalidOIDCState, err)
+	})
+
+	t.Run("Rejects A Bad State", func(t *testing.T) {
+		expired := &model.OIDCLoginState{Provider: "corp", ExpiresAt: time.Now().Add(-time.Second)}
+		otherProvider := &model.OIDCLoginState{Provider: "other", ExpiresAt: time.Now().Add(time.Minute)}
+		linkOfAnother := &model.OIDCLoginState{Provider: "corp", LinkUserID: 8, ExpiresAt: time.Now().Add(time.Minute)}
+
+		for name, state := range map[string]*model.OIDCLoginState{"Unknown": nil, "Expired": expired, "Other Provider": otherProvider, "Link Of Another User": linkOfAnother} {
+			t.Run(name, func(t *testing.T) {
+				repo := &MockOIDCRepository{
+					ConsumeLoginStateFunc: func(stateHash string) (*model.OIDCLoginState, error) {
+						return state, nil
+					},
+				}
+
+				usecase := NewOIDCUsecase(repo, userRepo, nil, map[string]OIDCProvider{"corp": &fakeOIDCProvider{token: token}}, DefaultOIDCPolicy, nil)
+				resp, err := usecase.Callback(context.Background(), "corp", 7, dto.OIDCCallbackRequest{Code: "good-code", State: "state"})
+
+				assert.Nil(t, resp)
+				assert.Equal(t, ErrInvalidOIDCState, err)
+			})
+		}
+	})
+
+	t.Run("Provider Error", func(t *testing.T) {
+		repo := &MockOIDCRepository{}
+		stateStore(repo)
+
+		usecase := NewOIDCUsecase(repo, userRepo, nil, map[string]OIDCProvider{"corp": &fakeOIDCProvider{token: token}}, DefaultOIDCPolicy, nil)
+		for _, request := range []dto.OIDCCallbackRequest{{Error: "access_denied"}, {Code: "bad-code"}} {
+			request.State = start(t, usecase, 0)
+			resp, err := usecase.Callback(context.Background(), "corp", 0, request)
+
+			assert.Nil(t, resp)
+			assert.Equal(t, ErrOIDCSignInFailed, err)
+		}
+	})
+
+	t.Run("Unknown Identity Is Not Linked By Default", func(t *testing.T) {
+		repo := &MockOIDCRepository{
+			LinkIdentityFunc: func(identity model.UserIdentity, event model.AuditEvent) error {
+				t.Fatal("should not link")
+				return nil
+			},
+		}
+		stateStore(repo)
+
+		usecase := NewOIDCUsecase(repo, userRepo, nil, map[string]OIDCProvider{"corp": &fakeOIDCProvider{token: token}}, DefaultOIDCPolicy, nil)
+		resp, err := usecase.Callback(context.Background(), "corp", 0, dto.OIDCCallbackRequest{Code: "good-code", State: start(t, usecase, 0)})
+
+		assert.Nil(t, resp)
+		assert.Equal(t, ErrOIDCAccountNotFound, err)
+	})
+
+	t.Run("Links By Verified Email", func(t *testing.T) {
+		var linked model.UserIdentity
+		var event model.AuditEvent
+		repo := &MockOIDCRepository{
+			LinkIdentityFunc: func(identity model.UserIdentity, e model.AuditEvent) error {
+				linked, event = identity, e
+				return nil
+			},
+		}
+		stateStore(repo)
+		policy := DefaultOIDCPolicy
+		policy.LinkByEmail = []string{"corp"}
+
+		usecase := NewOIDCUsecase(repo, userRepo, nil, map[string]OIDCProvider{"corp": &fakeOIDCProvider{token: token}}, policy, nil)
+		resp, err := usecase.Callback(context.Background(), "corp", 0, dto.OIDCCallbackRequest{Code: "good-code", State: start(t, usecase, 0)})
+
+		assert.NoError(t, err)
+		assert.NotEmpty(t, resp.Token)
+		assert.Equal(t, model.UserIdentity{UserID: 7, Provider: "corp", Subject: "248289761001", Email: "ana@corp.example"}, linked)
+		assert.Equal(t, model.AuditActionLink, event.Action)
+		assert.Equal(t, "7", event.EntityID)
+	})
+
+	t.Run("Does Not Link Unverified Emails", func(t *testing.T) {
+		unverifiedToken := *token
+		unverifiedToken.EmailVerified = false
+		bob := &model.User{ID: 8, Email: "bob@corp.example", Role: model.RoleCustomer}
+		bobToken := &oidc.IDToken{Subject: "1", Email: bob.Email, EmailVerified: true}
+		users := &MockUserRepository{
+			GetUserByEmailFunc: func(email string) (*model.User, error) {
+				return map[string]*model.User{ana.Email: ana, bob.Email: bob}[email], nil
+			},
+		}
+		policy := DefaultOIDCPolicy
+		policy.LinkByEmail = []string{"corp"}
+
+		for name, idToken := range map[string]*oidc.IDToken{"At The Provider": &unverifiedToken, "Here": bobToken} {
+			t.Run(name, func(t *testing.T) {
+				repo := &MockOIDCRepository{}
+				stateStore(repo)
+
+				usecase := NewOIDCUsecase(repo, users, nil, map[string]OIDCProvider{"corp": &fakeOIDCProvider{token: idToken}}, policy, nil)
+				resp, err := usecase.Callback(context.Background(), "corp", 0, dto.OIDCCallbackRequest{Code: "good-code", State: start(t, usecase, 0)})
+
+				assert.Nil(t, resp)
+				assert.Equal(t, ErrOIDCAccountNotFound, err)
+			})
+		}
+	})
+
+	t.Run("Links Explicitly", func(t *testing.T) {
+		var linked model.UserIdentity
+		repo := &MockOIDCRepository{
+			LinkIdentityFunc: func(identity model.UserIdentity, event model.AuditEvent) error {
+				linked = identity
+				return nil
+			},
+		}
+		stateStore(repo)
+		otherEmail := *token
+		otherEmail.Email = "ana.silva@corp.example"
+
+		usecase := NewOIDCUsecase(repo, userRepo, nil, map[string]OIDCProvider{"corp": &fakeOIDCProvider{token: &otherEmail}}, DefaultOIDCPolicy, nil)
+		resp, err := usecase.Callback(context.Background(), "corp", 7, dto.OIDCCallbackRequest{Code: "good-code", State: start(t, usecase, 7)})
+
+		assert.NoError(t, err)
+		assert.NotEmpty(t, resp.Token)
+		assert.Equal(t, 7, linked.UserID)
+		assert.Equal(t, "ana.silva@corp.example", linked.Email)
+	})
+
+	t.Run("Identity Linked To Another Account", func(t *testing.T) {
+		repo := &MockOIDCRepository{
+			GetIdentityFunc: func(provider, subject string) (*model.UserIdentity, error) {
+				return &model.UserIdentity{ID: 3, UserID: 8}, nil
+			},
+		}
+		stateStore(repo)
+
+		usecase := NewOIDCUsecase(repo, userRepo, nil, map[string]OIDCProvider{"corp": &fakeOIDCProvider{token: token}}, DefaultOIDCPolicy, nil)
+		resp, err := usecase.Callback(context.Background(), "corp", 7, dto.OIDCCallbackRequest{Code: "good-code", State: start(t, usecase, 7)})
+
+		assert.Nil(t, resp)
+		assert.Equal(t, ErrOIDCIdentityTaken, err)
+	})
+
+	t.Run("Account Already Has An Identity Of The Provider", func(t *testing.T) {
+		repo := &MockOIDCRepository{
+			LinkIdentityFunc: func(identity model.UserIdentity, event model.AuditEvent) error {
+				return sql.ErrNoRows
+			},
+		}
+		stateStore(repo)
+
+		usecase := NewOIDCUsecase(repo, userRepo, nil, map[string]OIDCProvider{"corp": &fakeOIDCProvider{token: token}}, DefaultOIDCPolicy, nil)
+		resp, err := usecase.Callback(context.Background(), "corp", 7, dto.OIDCCallbackRequest{Code: "good-code", State: start(t, usecase, 7)})
+
+		assert.Nil(t, resp)
+		assert.Equal(t, ErrOIDCIdentityTaken, err)
+	})
+
+	t.Run("Two-Factor Authentication", func(t *testing.T) {
+		confirmedAt := time.Now()
+		mfaRepo := &MockMFARepository{
+			GetMFAFunc: func(userID int) (*model.UserMFA, error) {
+				return &model.UserMFA{UserID: userID, ConfirmedAt: &confirmedAt}, nil
+			},
+		}
+		repo := &MockOIDCRepository{
+			GetIdentityFunc: func(provider, subject string) (*model.UserIdentity, error) {
+				return &model.UserIdentity{ID: 3, UserID: 7}, nil
+			},
+		}
+		stateStore(repo)
+
+		t.Run("Asks For A Code", func(t *testing.T) {
+			usecase := NewOIDCUsecase(repo, userRepo, mfaRepo, map[string]OIDCProvider{"corp": &fakeOIDCProvider{token: token}}, DefaultOIDCPolicy, nil)
+			resp, err := usecase.Callback(context.Background(), "corp", 0, dto.OIDCCallbackRequest{Code: "good-code", State: start(t, usecase, 0)})
+
+			assert.NoError(t, err)
+			assert.Empty(t, resp.Token)
+			assert.True(t, resp.MFARequired)
+			userID, err := util.ParseChallengeToken(resp.MFAToken)
+			assert.NoError(t, err)
+			assert.Equal(t, 7, userID)
+		})
+
+		t.Run("Done At The Provider", func(t *testing.T) {
+			mfaToken := *token
+			mfaToken.AMR = []string{"pwd", "otp", "mfa"}
+
+			usecase := NewOIDCUsecase(repo, userRepo, mfaRepo, map[string]OIDCProvider{"corp": &fakeOIDCProvider{token: &mfaToken}}, DefaultOIDCPolicy, nil)
+			resp, err := usecase.Callback(context.Background(), "corp", 0, dto.OIDCCallbackRequest{Code: "good-code", State: start(t, usecase, 0)})
+
+			assert.NoError(t, err)
+			claims, err := util.ParseToken(resp.Token)
+			assert.NoError(t, err)
+			assert.True(t, claims.MFA)
+		})
+	})
+
+	t.Run("Service Accounts Cannot Sign In", func(t *testing.T) {
+		service := &model.User{ID: 9, Email: "bot@corp.example", Role: model.RoleService}
+		repo := &MockOIDCRepository{
+			GetIdentityFunc: func(provider, subject string) (*model.UserIdentity, error) {
+				return &model.UserIdentity{ID: 4, UserID: 9}, nil
+			},
+		}
+		stateStore(repo)
+		users := &MockUserRepository{
+			GetUserByIDFunc: func(id int) (*model.User, error) {
+				return service, nil
+			},
+		}
+
+		usecase := NewOIDCUsecase(repo, users, nil, map[string]OIDCProvider{"corp": &fakeOIDCProvider{token: token}}, DefaultOIDCPolicy, nil)
+		resp, err := usecase.Callback(context.Background(), "corp", 0, dto.OIDCCallbackRequest{Code: "good-code", State: start(t, usecase, 0)})
+
+		assert.Nil(t, resp)
+		assert.Equal(t, ErrOIDCAccountNotFound, err)
+	})
+}
+
+// TestOIDCUsecase_MockProvider runs the whole sign in against a local
+// provider, through discovery, PKCE and the verification of the ID token
+func TestOIDCUsecase_MockProvider(t *testing.T) {
+	server := oidctest.NewServer("go-api", "client-secret")
+	defer server.Close()
+	provider, err := oidc.New(&oidc.Config{
+		Issuer:       server.Issuer(),
+		ClientID:     "go-api",
+		ClientSecret: "client-secret",
+		RedirectURL:  "https://shop.example.com/auth/callback",
+		Timeout:      5 * time.Second,
+	})
+	assert.NoError(t, err)
+
+	verifiedAt := time.Now()
+	userRepo := &MockUserRepository{
+		GetUserByEmailFunc: func(email string) (*model.User, error) {
+			return &model.User{ID: 7, Email: email, Role: model.RoleCustomer, EmailVerifiedAt: &verifiedAt}, nil
+		},
+	}
+	var identities []model.UserIdentity
+	repo := &MockOIDCRepository{
+		GetIdentityFunc: func(provider, subject string) (*model.UserIdentity, error) {
+			for _, identity := range identities {
+				if identity.Provider == provider && identity.Subject == subject {
+					return &identity, nil
+				}
+			}
+			return nil, nil
+		},
+		LinkIdentityFunc: func(identity model.UserIdentity, event model.AuditEvent) error {
+			identities = append(identities, identity)
+			return nil
+		},
+	}
+	stateStore(repo)
+	policy := DefaultOIDCPolicy
+	policy.LinkByEmail = []string{"corp"}
+	usecase := NewOIDCUsecase(repo, userRepo, nil, map[string]OIDCProvider{"corp": provider}, policy, nil)
+
+	authorize, err := usecase.Authorize(context.Background(), "corp", 0)
+	assert.NoError(t, err)
+	code, state, err := server.Login(authorize.AuthorizationURL, oidctest.Claims{Subject: "248289761001", Email: "ana@corp.example", EmailVerified: true})
+	assert.NoError(t, err)
+	assert.Equal(t, authorize.State, state)
+
+	resp, err := usecase.Callback(context.Background(), "corp", 0, dto.OIDCCallbackRequest{Code: code, State: state})
+
+	assert.NoError(t, err)
+	claims, err := util.ParseToken(resp.Token)
+	assert.NoError(t, err)
+	assert.Equal(t, 7, claims.UserID)
+	assert.Equal(t, []model.UserIdentity{{UserID: 7, Provider: "corp", Subject: "248289761001", Email: "ana@corp.example"}}, identities)
+}
+
+func TestOIDCUsecase_Unlink(t *testing.T) {
+	providers := map[string]OIDCProvider{"corp": &fakeOIDCProvider{}}
+
+	t.Run("Success", func(t *testing.T) {
+		var event model.AuditEvent
+		repo := &MockOIDCRepository{
+			UnlinkIdentityFunc: func(userID int, provider string, e model.AuditEvent) error {
+				assert.Equal(t, 7, userID)
+				assert.Equal(t, "corp", provider)
+				event = e
+				return nil
+			},
+		}
+
+		usecase := NewOIDCUsecase(repo, &MockUserRepository{}, nil, providers, DefaultOIDCPolicy, nil)
+		err := usecase.Unlink(context.Background(), 7, "corp")
+
+		assert.NoError(t, err)
+		assert.Equal(t, model.AuditActionUnlink, event.Action)
+	})
+
+	t.Run("Not Linked", func(t *testing.T) {
+		repo := &MockOIDCRepository{
+			UnlinkIdentityFunc: func(userID int, provider string, event model.AuditEvent) error {
+				return sql.ErrNoRows
+			},
+		}
+
+		usecase := NewOIDCUsecase(repo, &MockUserRepository{}, nil, providers, DefaultOIDCPolicy, nil)
+		err := usecase.Unlink(context.Background(), 7, "corp")
+
+		assert.Equal(t, ErrOIDCNotLinked, err)
+	})
+}
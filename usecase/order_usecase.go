@@ -0,0 +1,365 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-api/internal/audit"
+	"go-api/model"
+	"go-api/repository"
+	"time"
+)
+
+var (
+	ErrOrderNotFound       = errors.New("order not found")
+	ErrEmptyOrder          = errors.New("nothing to order")
+	ErrCartItemUnavailable = errors.New("cart item is no longer available")
+	ErrMixedCurrencies     = errors.New("all items of an order must share the same currency")
+	ErrInvalidOrderStatus  = errors.New("invalid order status")
+	ErrInvalidTransition   = errors.New("order status transition not allowed")
+)
+
+// OrderUsecase defines the contract for checkout and the order lifecycle
+type OrderUsecase interface {
+	Checkout(ctx context.Context, userID int, items []model.CartItemInput, codes []string, destination *model.TaxDestination) (*model.Order, error)
+	GetUserOrders(userID int) ([]model.Order, error)
+	GetUserOrder(userID, orderID int) (*model.Order, error)
+	CancelUserOrder(ctx context.Context, userID, orderID int) (*model.Order, error)
+	GetOrders(filter model.OrderFilter) ([]model.Order, error)
+	GetOrder(orderID int) (*model.Order, error)
+	TransitionOrder(ctx context.Context, orderID int, status, note string) (*model.Order, error)
+	RecordPaymentStatus(ctx context.Context, orderID int, status, note string) (*model.Order, error)
+}
+
+// PromotionPricer applies the promotions and discount codes to priced lines;
+// it is implemented by PromotionUsecase
+type PromotionPricer interface {
+	PriceLines(userID *int, lines []model.PricingLine, codes []string) (*model.PricingResult, error)
+}
+
+// TaxPricer computes the taxes of priced lines delivered to a destination;
+// it is implemented by TaxUsecase
+type TaxPricer interface {
+	TaxLines(destination model.TaxDestination, lines []model.PricedLine) (*model.TaxBreakdown, error)
+}
+
+type orderUsecaseImpl struct {
+	repository        repository.OrderRepositoryInterface
+	cartRepository    repository.CartRepositoryInterface
+	productRepository repository.ProductRepositoryInterface
+	variantRepository repository.VariantRepositoryInterface
+	promotions        PromotionPricer
+	taxes             TaxPricer
+}
+
+// NewOrderUsecase creates a new instance of OrderUsecase. promotions may be
+// nil, in which case orders are placed at full price and codes are refused;
+// taxes may be nil, in which case orders are placed without taxes
+func NewOrderUsecase(repo repository.OrderRepositoryInterface, cartRepo repository.CartRepositoryInterface, productRepo repository.ProductRepositoryInterface, variantRepo repository.VariantRepositoryInterface, promotions PromotionPricer, taxes TaxPricer) OrderUsecase {
+	return &orderUsecaseImpl{
+		repository:        repo,
+		cartRepository:    cartRepo,
+		productRepository: productRepo,
+		variantRepository: variantRepo,
+		promotions:        promotions,
+		taxes:             taxes,
+	}
+}
+
+// Checkout places a pending order for the given items or, when there are
+// none, for the whole cart of the user, which is emptied. Prices are the ones
+// in effect now, the promotions and codes are applied and redeemed, and the
+// variant stock is taken in the same transaction. Any rejected code fails the
+// checkout. With a destination, the discounted items are taxed with the rates
+// in effect now
+func (ou *orderUsecaseImpl) Checkout(ctx context.Context, userID int, inputs []model.CartItemInput, codes []string, destination *model.TaxDestination) (*model.Order, error) {
+	var items []model.OrderItem
+	var cartItemIDs []int
+	var err error
+	if len(inputs) == 0 {
+		items, cartItemIDs, err = ou.cartOrderItems(userID)
+	} else {
+		items, err = ou.inputOrderItems(inputs)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	pricing, err := ou.priceOrderItems(userID, items, codes)
+	if err != nil {
+		return nil, err
+	}
+	if len(pricing.Rejected) > 0 {
+		rejected := pricing.Rejected[0]
+		return nil, fmt.Errorf("%w: %s: %s", ErrCodeRejected, rejected.Code, rejected.Reason)
+	}
+	if err := applyTaxes(ou.taxes, pricing, destination); err != nil {
+		return nil, err
+	}
+	for i := range items {
+		items[i].Discount = pricing.Lines[i].Discount
+		items[i].Tax = model.Money{Currency: pricing.Total.Currency}
+		if pricing.Tax != nil {
+			items[i].Tax = pricing.Tax.Lines[i].Tax
+		}
+	}
+
+	now := time.Now().UTC()
+	order := model.Order{
+		UserID:     &userID,
+		Status:     model.OrderStatusPending,
+		Items:      items,
+		Discount:   pricing.Discount,
+		Tax:        model.Money{Currency: pricing.Total.Currency},
+		Total:      pricing.Total,
+		Promotions: pricing.Applied,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+		History: []model.OrderTransition{{
+			To:         model.OrderStatusPending,
+			ActorID:    audit.FromContext(ctx).ActorID,
+			OccurredAt: now,
+		}},
+	}
+	if pricing.Tax != nil {
+		order.Tax = pricing.Tax.Total
+		order.TaxIncluded = pricing.Tax.Included
+		order.TaxDestination = &pricing.Tax.Destination
+		order.Taxes = pricing.Tax.Taxes
+	}
+	id, err := ou.repository.CreateOrder(order, cartItemIDs)
+	if err != nil {
+		if errors.Is(err, repository.ErrStockChanged) {
+			return nil, fmt.Errorf("%w: %v", ErrInsufficientStock, err)
+		}
+		if errors.Is(err, repository.ErrPromotionUnavailable) {
+			return nil, fmt.Errorf("%w: %v", ErrPromotionUnavailable, err)
+		}
+		return nil, err
+	}
+	return ou.repository.GetOrderByID(id)
+}
+
+func (ou *orderUsecaseImpl) GetUserOrders(userID int) ([]model.Order, error) {
+	return ou.repository.GetOrders(model.OrderFilter{UserID: userID})
+}
+
+// GetUserOrder returns the order only when it belongs to the user
+func (ou *orderUsecaseImpl) GetUserOrder(userID, orderID int) (*model.Order, error) {
+	order, err := ou.repository.GetOrderByID(orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order == nil || order.UserID == nil || *order.UserID != userID {
+		return nil, ErrOrderNotFound
+	}
+	return order, nil
+}
+
+// CancelUserOrder lets a customer cancel their own order while it is pending
+func (ou *orderUsecaseImpl) CancelUserOrder(ctx context.Context, userID, orderID int) (*model.Order, error) {
+	order, err := ou.GetUserOrder(userID, orderID)
+	if err != nil {
+		return nil, err
+	}
+	return ou.transition(ctx, order, model.OrderStatusCancelled, "cancelled by the customer")
+}
+
+func (ou *orderUsecaseImpl) GetOrders(filter model.OrderFilter) ([]model.Order, error) {
+	if filter.Status != "" && !model.IsOrderStatus(filter.Status) {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidOrderStatus, filter.Status)
+	}
+	return ou.repository.GetOrders(filter)
+}
+
+func (ou *orderUsecaseImpl) GetOrder(orderID int) (*model.Order, error) {
+	order, err := ou.repository.GetOrderByID(orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order == nil {
+		return nil, ErrOrderNotFound
+	}
+	return order, nil
+}
+
+// TransitionOrder moves the order to a fulfilment status (shipped, delivered
+// or cancelled), if the state machine allows it. Paid and refunded follow the
+// payments, through RecordPaymentStatus
+func (ou *orderUsecaseImpl) TransitionOrder(ctx context.Context, orderID int, status, note string) (*model.Order, error) {
+	if !model.IsOrderStatus(status) {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidOrderStatus, status)
+	}
+	if !model.IsManualOrderStatus(status) {
+		return nil, fmt.Errorf("%w: %s follows the payments of the order", ErrInvalidOrderStatus, status)
+	}
+	order, err := ou.GetOrder(orderID)
+	if err != nil {
+		return nil, err
+	}
+	return ou.transition(ctx, order, status, note)
+}
+
+// RecordPaymentStatus moves the order to paid or refunded once its payment
+// was captured or given back; it is called by PaymentUsecase
+func (ou *orderUsecaseImpl) RecordPaymentStatus(ctx context.Context, orderID int, status, note string) (*model.Order, error) {
+	if status != model.OrderStatusPaid && status != model.OrderStatusRefunded {
+		return nil, fmt.Errorf("%w: %q does not follow a payment", ErrInvalidOrderStatus, status)
+	}
+	order, err := ou.GetOrder(orderID)
+	if err != nil {
+		return nil, err
+	}
+	return ou.transition(ctx, order, status, note)
+}
+
+// --- Helper Functions ---
+
+// transition records the status change. Orders cancelled, or refunded before
+// shipping, give their quantities back to the variant stock
+func (ou *orderUsecaseImpl) transition(ctx context.Context, order *model.Order, status, note string) (*model.Order, error) {
+	if !model.CanTransitionOrder(order.Status, status) {
+		return nil, fmt.Errorf("%w: %s to %s", ErrInvalidTransition, order.Status, status)
+	}
+
+	restock := status == model.OrderStatusCancelled ||
+		(status == model.OrderStatusRefunded && order.Status == model.OrderStatusPaid)
+	err := ou.repository.TransitionOrder(order.ID, model.OrderTransition{
+		From:       order.Status,
+		To:         status,
+		ActorID:    audit.FromContext(ctx).ActorID,
+		Note:       note,
+		OccurredAt: time.Now().UTC(),
+	}, restock)
+	if err != nil {
+		if errors.Is(err, repository.ErrOrderStatusChanged) {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidTransition, err)
+		}
+		return nil, err
+	}
+	return ou.repository.GetOrderByID(order.ID)
+}
+
+// priceOrderItems runs the order items through the promotions; without a
+// promotion engine they keep their full price and every code is rejected
+func (ou *orderUsecaseImpl) priceOrderItems(userID int, items []model.OrderItem, codes []string) (*model.PricingResult, error) {
+	lines := make([]model.PricingLine, 0, len(items))
+	for _, item := range items {
+		lines = append(lines, model.PricingLine{
+			ProductID: *item.ProductID,
+			VariantID: item.VariantID,
+			Quantity:  item.Quantity,
+			UnitPrice: item.UnitPrice,
+		})
+	}
+	if ou.promotions != nil {
+		return ou.promotions.PriceLines(&userID, lines, codes)
+	}
+
+	pricing, err := newPricingResult(lines)
+	if err != nil {
+		return nil, err
+	}
+	rejectAllCodes(pricing, codes)
+	return pricing, nil
+}
+
+// cartOrderItems turns the cart of the user into order items; every item must
+// still be available
+func (ou *orderUsecaseImpl) cartOrderItems(userID int) ([]model.OrderItem, []int, error) {
+	cart, err := ou.cartRepository.GetCartByUserID(userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if cart == nil {
+		return nil, nil, fmt.Errorf("%w: the cart is empty", ErrEmptyOrder)
+	}
+	if cart.Items, err = ou.cartRepository.GetCartItems(cart.ID, time.Now()); err != nil {
+		return nil, nil, err
+	}
+	if len(cart.Items) == 0 {
+		return nil, nil, fmt.Errorf("%w: the cart is empty", ErrEmptyOrder)
+	}
+	if err := priceCart(cart); err != nil {
+		return nil, nil, err
+	}
+
+	items := make([]model.OrderItem, 0, len(cart.Items))
+	ids := make([]int, 0, len(cart.Items))
+	for _, item := range cart.Items {
+		if !item.Available {
+			return nil, nil, fmt.Errorf("%w: %s", ErrCartItemUnavailable, item.ProductName)
+		}
+		productID := item.ProductID
+		items = append(items, model.OrderItem{
+			ProductID:   &productID,
+			VariantID:   item.VariantID,
+			ProductName: item.ProductName,
+			SKU:         item.SKU,
+			UnitPrice:   item.UnitPrice,
+			Quantity:    item.Quantity,
+			Subtotal:    item.Subtotal,
+		})
+		ids = append(ids, item.ID)
+	}
+	return items, ids, nil
+}
+
+// inputOrderItems prices the requested items like the cart does, adding up
+// repeated products and variants before checking the stock
+func (ou *orderUsecaseImpl) inputOrderItems(inputs []model.CartItemInput) ([]model.OrderItem, error) {
+	var merged []model.CartItemInput
+	for _, input := range inputs {
+		if input.Quantity < 1 {
+			return nil, ErrInvalidQuantity
+		}
+		found := false
+		for i := range merged {
+			if merged[i].ProductID == input.ProductID && sameVariant(merged[i].VariantID, input.VariantID) {
+				merged[i].Quantity += input.Quantity
+				found = true
+			}
+		}
+		if !found {
+			merged = append(merged, input)
+		}
+	}
+
+	items := make([]model.OrderItem, 0, len(merged))
+	for _, input := range merged {
+		product, err := ou.productRepository.GetProductById(input.ProductID)
+		if err != nil {
+			return nil, err
+		}
+		if product == nil {
+			return nil, fmt.Errorf("%w: %d", ErrProductNotFound, input.ProductID)
+		}
+		price, variant, err := resolveItemPrice(ou.variantRepository, *product, input.VariantID)
+		if err != nil {
+			return nil, err
+		}
+
+		subtotal, err := price.Mul(int64(input.Quantity))
+		if err != nil {
+			return nil, err
+		}
+
+		item := model.OrderItem{
+			ProductID:   &product.ID,
+			ProductName: product.Name,
+			SKU:         product.SKU,
+			UnitPrice:   price,
+			Quantity:    input.Quantity,
+			Subtotal:    subtotal,
+		}
+		if variant != nil {
+			if input.Quantity > variant.Stock {
+				return nil, fmt.Errorf("%w: %d available for %s", ErrInsufficientStock, variant.Stock, variant.SKU)
+			}
+			item.VariantID = &variant.ID
+			item.SKU = variant.SKU
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
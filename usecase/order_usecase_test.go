@@ -0,0 +1,367 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-api/internal/audit"
+	"go-api/model"
+	"go-api/repository"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrderUsecase_Checkout(t *testing.T) {
+	t.Run("From The Cart", func(t *testing.T) {
+		var created model.Order
+		var cartItemIDs []int
+		mockRepo := &MockOrderRepository{
+			CreateOrderFunc: func(order model.Order, ids []int) (int, error) {
+				created, cartItemIDs = order, ids
+				return 10, nil
+			},
+			GetOrderByIDFunc: func(id int) (*model.Order, error) {
+				created.ID = id
+				return &created, nil
+			},
+		}
+		mockCartRepo := &MockCartRepository{
+			GetCartByUserIDFunc: func(userID int) (*model.Cart, error) {
+				return &model.Cart{ID: 4, UserID: &userID}, nil
+			},
+			GetCartItemsFunc: func(cartID int, asOf time.Time) ([]model.CartItem, error) {
+				return []model.CartItem{
+					{ID: 5, ProductID: 1, VariantID: intPtr(2), ProductName: "Camiseta", SKU: "CAM-AZUL-M", Quantity: 2, Active: true, Stock: intPtr(3),
+						AddedPrice: model.Money{Amount: 4990, Currency: "BRL"}, UnitPrice: model.Money{Amount: 3990, Currency: "BRL"}},
+					{ID: 6, ProductID: 3, ProductName: "Caneca", Quantity: 1, Active: true,
+						AddedPrice: model.Money{Amount: 1990, Currency: "BRL"}, UnitPrice: model.Money{Amount: 1990, Currency: "BRL"}},
+				}, nil
+			},
+		}
+
+		ctx := audit.NewContext(context.Background(), audit.Metadata{ActorID: intPtr(7)})
+		usecase := NewOrderUsecase(mockRepo, mockCartRepo, &MockProductRepository{}, &MockVariantRepository{}, nil, nil)
+		order, err := usecase.Checkout(ctx, 7, nil, nil, nil)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 10, order.ID)
+		assert.Equal(t, []int{5, 6}, cartItemIDs)
+		assert.Equal(t, 7, *created.UserID)
+		assert.Equal(t, model.OrderStatusPending, created.Status)
+		assert.Equal(t, model.Money{Amount: 3990, Currency: "BRL"}, created.Items[0].UnitPrice)
+		assert.Equal(t, model.Money{Amount: 9970, Currency: "BRL"}, created.Total)
+		assert.Len(t, created.History, 1)
+		assert.Equal(t, 7, *created.History[0].ActorID)
+	})
+
+	t.Run("Records The Taxes Of The Destination", func(t *testing.T) {
+		var created model.Order
+		mockRepo := &MockOrderRepository{
+			CreateOrderFunc: func(order model.Order, ids []int) (int, error) {
+				created = order
+				return 10, nil
+			},
+			GetOrderByIDFunc: func(id int) (*model.Order, error) {
+				return &created, nil
+			},
+		}
+		mockCartRepo := &MockCartRepository{
+			GetCartByUserIDFunc: func(userID int) (*model.Cart, error) {
+				return &model.Cart{ID: 4, UserID: &userID}, nil
+			},
+			GetCartItemsFunc: func(cartID int, asOf time.Time) ([]model.CartItem, error) {
+				return []model.CartItem{
+					{ID: 5, ProductID: 1, ProductName: "Camiseta", Quantity: 2, Active: true,
+						AddedPrice: model.Money{Amount: 3990, Currency: "BRL"}, UnitPrice: model.Money{Amount: 3990, Currency: "BRL"}},
+					{ID: 6, ProductID: 3, ProductName: "Arroz", Quantity: 1, Active: true,
+						AddedPrice: model.Money{Amount: 1990, Currency: "BRL"}, UnitPrice: model.Money{Amount: 1990, Currency: "BRL"}},
+				}, nil
+			},
+		}
+		taxes := NewTaxUsecase(newTestTaxTable(t), &MockProductRepository{
+			GetProductTaxCategoriesFunc: func(ids []int) (map[int]string, error) {
+				return map[int]string{1: "standard", 3: "reduced"}, nil
+			},
+		})
+
+		usecase := NewOrderUsecase(mockRepo, mockCartRepo, &MockProductRepository{}, &MockVariantRepository{}, nil, taxes)
+		_, err := usecase.Checkout(context.Background(), 7, nil, nil, &model.TaxDestination{Country: "br", State: "sp"})
+
+		assert.NoError(t, err)
+		assert.True(t, created.TaxIncluded)
+		assert.Equal(t, &model.TaxDestination{Country: "BR", State: "SP"}, created.TaxDestination)
+		assert.Equal(t, model.Money{Amount: 1217, Currency: "BRL"}, created.Items[0].Tax)
+		assert.Equal(t, model.Money{Amount: 130, Currency: "BRL"}, created.Items[1].Tax)
+		assert.Equal(t, model.Money{Amount: 1347, Currency: "BRL"}, created.Tax)
+		assert.Equal(t, model.Money{Amount: 9970, Currency: "BRL"}, created.Total)
+	})
+
+	t.Run("Empty Cart", func(t *testing.T) {
+		usecase := NewOrderUsecase(&MockOrderRepository{}, &MockCartRepository{}, &MockProductRepository{}, &MockVariantRepository{}, nil, nil)
+		_, err := usecase.Checkout(context.Background(), 7, nil, nil, nil)
+
+		assert.True(t, errors.Is(err, ErrEmptyOrder))
+	})
+
+	t.Run("Unavailable Cart Item", func(t *testing.T) {
+		mockCartRepo := &MockCartRepository{
+			GetCartByUserIDFunc: func(userID int) (*model.Cart, error) {
+				return &model.Cart{ID: 4}, nil
+			},
+			GetCartItemsFunc: func(cartID int, asOf time.Time) ([]model.CartItem, error) {
+				return []model.CartItem{{ID: 5, ProductID: 1, ProductName: "Camiseta", Quantity: 1, Active: false,
+					UnitPrice: model.Money{Amount: 4990, Currency: "BRL"}}}, nil
+			},
+		}
+
+		usecase := NewOrderUsecase(&MockOrderRepository{}, mockCartRepo, &MockProductRepository{}, &MockVariantRepository{}, nil, nil)
+		_, err := usecase.Checkout(context.Background(), 7, nil, nil, nil)
+
+		assert.True(t, errors.Is(err, ErrCartItemUnavailable))
+	})
+
+	t.Run("From Items Adds Up Repeated Variants", func(t *testing.T) {
+		var created model.Order
+		mockRepo := &MockOrderRepository{
+			CreateOrderFunc: func(order model.Order, ids []int) (int, error) {
+				created = order
+				assert.Empty(t, ids)
+				return 10, nil
+			},
+		}
+
+		usecase := NewOrderUsecase(mockRepo, &MockCartRepository{},
+			&MockProductRepository{GetProductByIdFunc: variantProduct},
+			&MockVariantRepository{GetVariantsFunc: cartVariants}, nil, nil)
+		_, err := usecase.Checkout(context.Background(), 7, []model.CartItemInput{
+			{ProductID: 1, VariantID: intPtr(3), Quantity: 2},
+			{ProductID: 1, VariantID: intPtr(3), Quantity: 1},
+		}, nil, nil)
+
+		assert.NoError(t, err)
+		assert.Len(t, created.Items, 1)
+		assert.Equal(t, 3, created.Items[0].Quantity)
+		assert.Equal(t, "CAM-AZUL-G", created.Items[0].SKU)
+		assert.Equal(t, "Camiseta", created.Items[0].ProductName)
+		assert.Equal(t, model.Money{Amount: 16470, Currency: "BRL"}, created.Total)
+	})
+
+	t.Run("Not Enough Stock", func(t *testing.T) {
+		usecase := NewOrderUsecase(&MockOrderRepository{}, &MockCartRepository{},
+			&MockProductRepository{GetProductByIdFunc: variantProduct},
+			&MockVariantRepository{GetVariantsFunc: cartVariants}, nil, nil)
+		_, err := usecase.Checkout(context.Background(), 7, []model.CartItemInput{{ProductID: 1, VariantID: intPtr(2), Quantity: 4}}, nil, nil)
+
+		assert.True(t, errors.Is(err, ErrInsufficientStock))
+	})
+
+	t.Run("Mixed Currencies", func(t *testing.T) {
+		mockProductRepo := &MockProductRepository{
+			GetProductByIdFunc: func(id int) (*model.Product, error) {
+				currency := "BRL"
+				if id == 2 {
+					currency = "USD"
+				}
+				return &model.Product{ID: id, Name: "Caneca", Price: model.Money{Amount: 1000, Currency: currency}}, nil
+			},
+		}
+
+		usecase := NewOrderUsecase(&MockOrderRepository{}, &MockCartRepository{}, mockProductRepo, &MockVariantRepository{}, nil, nil)
+		_, err := usecase.Checkout(context.Background(), 7, []model.CartItemInput{
+			{ProductID: 1, Quantity: 1},
+			{ProductID: 2, Quantity: 1},
+		}, nil, nil)
+
+		assert.True(t, errors.Is(err, ErrMixedCurrencies))
+	})
+
+	t.Run("Stock Taken Meanwhile", func(t *testing.T) {
+		mockRepo := &MockOrderRepository{
+			CreateOrderFunc: func(order model.Order, ids []int) (int, error) {
+				return 0, fmt.Errorf("%w: variant 2", repository.ErrStockChanged)
+			},
+		}
+
+		usecase := NewOrderUsecase(mockRepo, &MockCartRepository{},
+			&MockProductRepository{GetProductByIdFunc: variantProduct},
+			&MockVariantRepository{GetVariantsFunc: cartVariants}, nil, nil)
+		_, err := usecase.Checkout(context.Background(), 7, []model.CartItemInput{{ProductID: 1, VariantID: intPtr(2), Quantity: 1}}, nil, nil)
+
+		assert.True(t, errors.Is(err, ErrInsufficientStock))
+	})
+
+	t.Run("Applies The Discount Codes", func(t *testing.T) {
+		var created model.Order
+		mockRepo := &MockOrderRepository{
+			CreateOrderFunc: func(order model.Order, ids []int) (int, error) {
+				created = order
+				return 10, nil
+			},
+			GetOrderByIDFunc: func(id int) (*model.Order, error) { return &created, nil },
+		}
+		promotions := NewPromotionUsecase(&MockPromotionRepository{
+			GetPromotionByCodeFunc: promotionCodes(model.Promotion{
+				ID: 3, Code: "BEMVINDO10", Name: "Boas-vindas", Kind: model.DiscountPercentage, Percent: 10, Active: true,
+			}),
+		}, &MockProductRepository{}, &MockCategoryRepository{})
+
+		usecase := NewOrderUsecase(mockRepo, &MockCartRepository{},
+			&MockProductRepository{GetProductByIdFunc: variantProduct},
+			&MockVariantRepository{GetVariantsFunc: cartVariants}, promotions, nil)
+		_, err := usecase.Checkout(context.Background(), 7, []model.CartItemInput{{ProductID: 1, VariantID: intPtr(3), Quantity: 2}}, []string{"bemvindo10"}, nil)
+
+		assert.NoError(t, err)
+		assert.Equal(t, model.Money{Amount: 1098, Currency: "BRL"}, created.Items[0].Discount)
+		assert.Equal(t, model.Money{Amount: 1098, Currency: "BRL"}, created.Discount)
+		assert.Equal(t, model.Money{Amount: 9882, Currency: "BRL"}, created.Total)
+		assert.Equal(t, []model.AppliedPromotion{{PromotionID: 3, Code: "BEMVINDO10", Name: "Boas-vindas", Discount: model.Money{Amount: 1098, Currency: "BRL"}}}, created.Promotions)
+	})
+
+	t.Run("Rejected Code Fails The Checkout", func(t *testing.T) {
+		expired := time.Now().Add(-time.Hour)
+		promotions := NewPromotionUsecase(&MockPromotionRepository{
+			GetPromotionByCodeFunc: promotionCodes(model.Promotion{
+				ID: 4, Code: "VERAO", Name: "Verão", Kind: model.DiscountPercentage, Percent: 10, EndsAt: &expired, Active: true,
+			}),
+		}, &MockProductRepository{}, &MockCategoryRepository{})
+
+		usecase := NewOrderUsecase(&MockOrderRepository{}, &MockCartRepository{},
+			&MockProductRepository{GetProductByIdFunc: variantProduct},
+			&MockVariantRepository{GetVariantsFunc: cartVariants}, promotions, nil)
+		_, err := usecase.Checkout(context.Background(), 7, []model.CartItemInput{{ProductID: 1, VariantID: intPtr(3), Quantity: 1}}, []string{"verao"}, nil)
+
+		assert.True(t, errors.Is(err, ErrCodeRejected))
+		assert.Contains(t, err.Error(), "VERAO: the code has expired")
+	})
+
+	t.Run("Codes Refused Without Promotions", func(t *testing.T) {
+		usecase := NewOrderUsecase(&MockOrderRepository{}, &MockCartRepository{},
+			&MockProductRepository{GetProductByIdFunc: variantProduct},
+			&MockVariantRepository{GetVariantsFunc: cartVariants}, nil, nil)
+		_, err := usecase.Checkout(context.Background(), 7, []model.CartItemInput{{ProductID: 1, VariantID: intPtr(3), Quantity: 1}}, []string{"VERAO"}, nil)
+
+		assert.True(t, errors.Is(err, ErrCodeRejected))
+	})
+
+	t.Run("Promotion Used Up Meanwhile", func(t *testing.T) {
+		mockRepo := &MockOrderRepository{
+			CreateOrderFunc: func(order model.Order, ids []int) (int, error) {
+				return 0, fmt.Errorf("%w: Boas-vindas", repository.ErrPromotionUnavailable)
+			},
+		}
+
+		usecase := NewOrderUsecase(mockRepo, &MockCartRepository{},
+			&MockProductRepository{GetProductByIdFunc: variantProduct},
+			&MockVariantRepository{GetVariantsFunc: cartVariants}, nil, nil)
+		_, err := usecase.Checkout(context.Background(), 7, []model.CartItemInput{{ProductID: 1, VariantID: intPtr(3), Quantity: 1}}, nil, nil)
+
+		assert.True(t, errors.Is(err, ErrPromotionUnavailable))
+	})
+}
+
+func TestOrderUsecase_TransitionOrder(t *testing.T) {
+	orderIn := func(status string) *MockOrderRepository {
+		return &MockOrderRepository{
+			GetOrderByIDFunc: func(id int) (*model.Order, error) {
+				return &model.Order{ID: id, UserID: intPtr(7), Status: status}, nil
+			},
+		}
+	}
+
+	t.Run("Refund Before Shipping Restocks", func(t *testing.T) {
+		mockRepo := orderIn(model.OrderStatusPaid)
+		var recorded model.OrderTransition
+		var restocked bool
+		mockRepo.TransitionOrderFunc = func(id int, transition model.OrderTransition, restock bool) error {
+			recorded, restocked = transition, restock
+			return nil
+		}
+
+		ctx := audit.NewContext(context.Background(), audit.Metadata{ActorID: intPtr(1)})
+		usecase := NewOrderUsecase(mockRepo, &MockCartRepository{}, &MockProductRepository{}, &MockVariantRepository{}, nil, nil)
+		_, err := usecase.RecordPaymentStatus(ctx, 10, model.OrderStatusRefunded, "Cliente desistiu")
+
+		assert.NoError(t, err)
+		assert.True(t, restocked)
+		assert.Equal(t, model.OrderStatusPaid, recorded.From)
+		assert.Equal(t, model.OrderStatusRefunded, recorded.To)
+		assert.Equal(t, 1, *recorded.ActorID)
+		assert.Equal(t, "Cliente desistiu", recorded.Note)
+	})
+
+	t.Run("Refund After Delivery Keeps Stock", func(t *testing.T) {
+		mockRepo := orderIn(model.OrderStatusDelivered)
+		mockRepo.TransitionOrderFunc = func(id int, transition model.OrderTransition, restock bool) error {
+			assert.False(t, restock)
+			return nil
+		}
+
+		usecase := NewOrderUsecase(mockRepo, &MockCartRepository{}, &MockProductRepository{}, &MockVariantRepository{}, nil, nil)
+		_, err := usecase.RecordPaymentStatus(context.Background(), 10, model.OrderStatusRefunded, "")
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("Paid And Refunded Follow The Payments", func(t *testing.T) {
+		mockRepo := orderIn(model.OrderStatusPaid)
+		mockRepo.TransitionOrderFunc = func(id int, transition model.OrderTransition, restock bool) error {
+			t.Fatal("the order must not change without a payment")
+			return nil
+		}
+
+		usecase := NewOrderUsecase(mockRepo, &MockCartRepository{}, &MockProductRepository{}, &MockVariantRepository{}, nil, nil)
+		for _, status := range []string{model.OrderStatusPaid, model.OrderStatusRefunded} {
+			_, err := usecase.TransitionOrder(context.Background(), 10, status, "")
+			assert.True(t, errors.Is(err, ErrInvalidOrderStatus))
+		}
+		_, err := usecase.RecordPaymentStatus(context.Background(), 10, model.OrderStatusShipped, "")
+		assert.True(t, errors.Is(err, ErrInvalidOrderStatus))
+	})
+
+	t.Run("Invalid Transition", func(t *testing.T) {
+		usecase := NewOrderUsecase(orderIn(model.OrderStatusPending), &MockCartRepository{}, &MockProductRepository{}, &MockVariantRepository{}, nil, nil)
+		_, err := usecase.TransitionOrder(context.Background(), 10, model.OrderStatusShipped, "")
+
+		assert.True(t, errors.Is(err, ErrInvalidTransition))
+	})
+
+	t.Run("Unknown Status", func(t *testing.T) {
+		usecase := NewOrderUsecase(orderIn(model.OrderStatusPending), &MockCartRepository{}, &MockProductRepository{}, &MockVariantRepository{}, nil, nil)
+		_, err := usecase.TransitionOrder(context.Background(), 10, "lost", "")
+
+		assert.True(t, errors.Is(err, ErrInvalidOrderStatus))
+	})
+
+	t.Run("Changed Concurrently", func(t *testing.T) {
+		mockRepo := orderIn(model.OrderStatusPaid)
+		mockRepo.TransitionOrderFunc = func(id int, transition model.OrderTransition, restock bool) error {
+			return repository.ErrOrderStatusChanged
+		}
+
+		usecase := NewOrderUsecase(mockRepo, &MockCartRepository{}, &MockProductRepository{}, &MockVariantRepository{}, nil, nil)
+		_, err := usecase.TransitionOrder(context.Background(), 10, model.OrderStatusShipped, "")
+
+		assert.True(t, errors.Is(err, ErrInvalidTransition))
+	})
+}
+
+func TestOrderUsecase_CancelUserOrder(t *testing.T) {
+	t.Run("Order Of Another User", func(t *testing.T) {
+		mockRepo := &MockOrderRepository{
+			GetOrderByIDFunc: func(id int) (*model.Order, error) {
+				return &model.Order{ID: id, UserID: intPtr(8), Status: model.OrderStatusPending}, nil
+			},
+			TransitionOrderFunc: func(id int, transition model.OrderTransition, restock bool) error {
+				t.Fatal("the order of another user must not change")
+				return nil
+			},
+		}
+
+		usecase := NewOrderUsecase(mockRepo, &MockCartRepository{}, &MockProductRepository{}, &MockVariantRepository{}, nil, nil)
+		_, err := usecase.CancelUserOrder(context.Background(), 7, 10)
+
+		assert.Equal(t, ErrOrderNotFound, err)
+	})
+}
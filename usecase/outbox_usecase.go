@@ -0,0 +1,106 @@
+package usecase
+
+import (
+	"context"
+	"go-api/internal/mail"
+	"go-api/repository"
+	"log"
+	"time"
+)
+
+// OutboxPolicy holds how the emails of the outbox are delivered
+type OutboxPolicy struct {
+	// BatchSize is how many emails a delivery round claims
+	BatchSize int
+	// MaxAttempts is how many times an email is tried before it is left
+	// failed in the outbox
+	MaxAttempts int
+	// Lease is how long a claimed email waits before another round may try
+	// it again, should its sender stop midway
+	Lease time.Duration
+	// RetryBackoff is the wait after the first failure, doubled after each
+	// other one
+	RetryBackoff time.Duration
+}
+
+// DefaultOutboxPolicy keeps retrying an email for about two hours
+var DefaultOutboxPolicy = OutboxPolicy{
+	BatchSize:    50,
+	MaxAttempts:  8,
+	Lease:        2 * time.Minute,
+	RetryBackoff: time.Minute,
+}
+
+// OutboxUsecase defines the contract for delivering the emails of the outbox
+type OutboxUsecase interface {
+	DeliverDueEmails(ctx context.Context) (int, error)
+}
+
+type outboxUsecaseImpl struct {
+	repository repository.OutboxRepositoryInterface
+	mailer     mail.Mailer
+	policy     OutboxPolicy
+}
+
+// NewOutboxUsecase creates a new instance of OutboxUsecase sending through mailer
+func NewOutboxUsecase(repo repository.OutboxRepositoryInterface, mailer mail.Mailer, policy OutboxPolicy) OutboxUsecase {
+	return &outboxUsecaseImpl{
+		repository: repo,
+		mailer:     mailer,
+		policy:     policy,
+	}
+}
+
+// DeliverDueEmails sends a batch of the due emails and returns how many were
+// sent. A failed email is rescheduled with backoff and does not stop the
+// others; only errors of the outbox itself are returned
+func (ou *outboxUsecaseImpl) DeliverDueEmails(ctx context.Context) (int, error) {
+	emails, err := ou.repository.ClaimDueEmails(ou.policy.BatchSize, ou.policy.Lease, ou.policy.MaxAttempts)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, email := range emails {
+		err := ou.mailer.Send(ctx, mail.Message{To: email.Recipient, Subject: email.Subject, Body: email.Body})
+		if err != nil {
+			retryAt := time.Now().Add(ou.retryDelay(email.Attempts))
+			if err := ou.repository.MarkEmailFailed(email.ID, err.Error(), retryAt); err != nil {
+				return sent, err
+			}
+			continue
+		}
+		if err := ou.repository.MarkEmailSent(email.ID); err != nil {
+			return sent, err
+		}
+		sent++
+	}
+	return sent, nil
+}
+
+// RunOutbox delivers the due emails every interval until ctx is done
+func RunOutbox(ctx context.Context, outbox OutboxUsecase, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if _, err := outbox.DeliverDueEmails(ctx); err != nil {
+			log.Printf("outbox: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// --- Helper Functions ---
+
+// retryDelay doubles the backoff for each attempt already made
+func (ou *outboxUsecaseImpl) retryDelay(attempts int) time.Duration {
+	delay := ou.policy.RetryBackoff
+	for i := 1; i < attempts && delay < 24*time.Hour; i++ {
+		delay *= 2
+	}
+	return delay
+}
@@ -0,0 +1,74 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"go-api/internal/mail"
+	"go-api/model"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOutboxUsecase_DeliverDueEmails(t *testing.T) {
+	emails := []model.OutboxEmail{
+		{ID: 1, Recipient: "ana@example.com", Subject: "Redefinição de senha", Body: "link", Attempts: 1},
+		{ID: 2, Recipient: "bia@example.com", Subject: "Redefinição de senha", Body: "link", Attempts: 3},
+	}
+
+	t.Run("Sends The Claimed Emails", func(t *testing.T) {
+		var sent []int64
+		mockRepo := &MockOutboxRepository{
+			ClaimDueEmailsFunc: func(limit int, lease time.Duration, maxAttempts int) ([]model.OutboxEmail, error) {
+				assert.Equal(t, DefaultOutboxPolicy.BatchSize, limit)
+				assert.Equal(t, DefaultOutboxPolicy.MaxAttempts, maxAttempts)
+				return emails, nil
+			},
+			MarkEmailSentFunc: func(id int64) error {
+				sent = append(sent, id)
+				return nil
+			},
+		}
+		mailer := mail.NewMemoryMailer()
+
+		usecase := NewOutboxUsecase(mockRepo, mailer, DefaultOutboxPolicy)
+		count, err := usecase.DeliverDueEmails(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, count)
+		assert.Equal(t, []int64{1, 2}, sent)
+		assert.Equal(t, []mail.Message{
+			{To: "ana@example.com", Subject: "Redefinição de senha", Body: "link"},
+			{To: "bia@example.com", Subject: "Redefinição de senha", Body: "link"},
+		}, mailer.Messages())
+	})
+
+	t.Run("Reschedules Failures With Backoff", func(t *testing.T) {
+		retries := make(map[int64]time.Time)
+		mockRepo := &MockOutboxRepository{
+			ClaimDueEmailsFunc: func(limit int, lease time.Duration, maxAttempts int) ([]model.OutboxEmail, error) {
+				return emails, nil
+			},
+			MarkEmailSentFunc: func(id int64) error {
+				t.Fatal("no email should be marked sent")
+				return nil
+			},
+			MarkEmailFailedFunc: func(id int64, reason string, retryAt time.Time) error {
+				assert.Equal(t, "connection refused", reason)
+				retries[id] = retryAt
+				return nil
+			},
+		}
+		mailer := mail.NewMemoryMailer()
+		mailer.Fail(errors.New("connection refused"))
+
+		usecase := NewOutboxUsecase(mockRepo, mailer, DefaultOutboxPolicy)
+		count, err := usecase.DeliverDueEmails(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, count)
+		assert.WithinDuration(t, time.Now().Add(time.Minute), retries[1], 5*time.Second)
+		assert.WithinDuration(t, time.Now().Add(4*time.Minute), retries[2], 5*time.Second)
+	})
+}
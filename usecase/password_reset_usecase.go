@@ -0,0 +1,297 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"go-api/internal/audit"
+	"go-api/internal/password"
+	"go-api/internal/throttle"
+	"go-api/model"
+	"go-api/repository"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidResetToken      = errors.New("invalid or expired password reset token")
+	ErrPasswordResetThrottled = errors.New("too many password reset requests, try again later")
+)
+
+// PasswordResetThrottledError is returned while the email or the IP must
+// wait before asking for another reset email; it matches
+// ErrPasswordResetThrottled
+type PasswordResetThrottledError struct {
+	RetryAfter time.Duration
+}
+
+func (e *PasswordResetThrottledError) Error() string {
+	return ErrPasswordResetThrottled.Error()
+}
+
+func (e *PasswordResetThrottledError) Unwrap() error {
+	return ErrPasswordResetThrottled
+}
+
+// PasswordResetPolicy holds the rules of the password reset emails
+type PasswordResetPolicy struct {
+	// TokenTTL is how long the link of the email stays valid
+	TokenTTL time.Duration
+	// ResetURL is the page of the front end that asks for the new password;
+	// the email links to it with the token in the "token" query parameter
+	ResetURL string
+	// Password holds the strength rules of the new password
+	Password password.Policy
+	// Email and IP limit the reset requests per email, whether or not it has
+	// an account, and per IP of the request. Their counters are apart from
+	// those of the logins, so asking for resets never locks anyone out
+	Email throttle.Policy
+	IP    throttle.Policy
+	// ResponseTime is the least time a forgot password request takes, so
+	// unknown emails answer as late as those that get an email
+	ResponseTime time.Duration
+}
+
+// DefaultPasswordResetPolicy keeps the links valid for 30 minutes, applies
+// the default password strength rules and lets an email ask for 3 reset
+// emails an hour, and an IP for 20, before delays start
+var DefaultPasswordResetPolicy = PasswordResetPolicy{
+	TokenTTL: 30 * time.Minute,
+	ResetURL: "http://localhost:3000/reset-password",
+	Password: password.DefaultPolicy,
+	Email: throttle.Policy{
+		Window:       time.Hour,
+		FreeFailures: 3,
+		BaseDelay:    time.Minute,
+		MaxDelay:     15 * time.Minute,
+	},
+	IP: throttle.Policy{
+		Window:       time.Hour,
+		FreeFailures: 20,
+		BaseDelay:    time.Second,
+		MaxDelay:     15 * time.Minute,
+	},
+	ResponseTime: 500 * time.Millisecond,
+}
+
+// PasswordResetUsecase defines the contract for users who forgot the password
+type PasswordResetUsecase interface {
+	ForgotPassword(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, token, password string) error
+}
+
+type passwordResetUsecaseImpl struct {
+	repository     repository.PasswordResetRepositoryInterface
+	userRepository repository.UserRepositoryInterface
+	passwords      password.Hasher
+	policy         PasswordResetPolicy
+	emails         *throttle.Limiter
+	ips            *throttle.Limiter
+}
+
+// NewPasswordResetUsecase creates a new instance of PasswordResetUsecase
+// counting the reset requests in store
+func NewPasswordResetUsecase(repo repository.PasswordResetRepositoryInterface, userRepo repository.UserRepositoryInterface, passwords password.Hasher, policy PasswordResetPolicy, store throttle.Store) PasswordResetUsecase {
+	return &passwordResetUsecaseImpl{
+		repository:     repo,
+		userRepository: userRepo,
+		passwords:      passwords,
+		policy:         policy,
+		emails:         throttle.NewLimiter(store, policy.Email),
+		ips:            throttle.NewLimiter(store, policy.IP),
+	}
+}
+
+// ForgotPassword emails a reset link to the user with the email. Each
+// request counts against the email and the IP, and returns a
+// *PasswordResetThrottledError while either is blocked. Unknown emails, and
+// users in the trash, succeed without sending anything and every request
+// takes at least ResponseTime, so the caller cannot tell which emails have
+// an account
+func (pu *passwordResetUsecaseImpl) ForgotPassword(ctx context.Context, email string) error {
+	email = strings.TrimSpace(email)
+	limits := pu.limits(ctx, email)
+	var wait time.Duration
+	for _, limit := range limits {
+		keyWait, err := limit.limiter.Wait(ctx, limit.key)
+		if err != nil {
+			return err
+		}
+		wait = max(wait, keyWait)
+	}
+	if wait > 0 {
+		return &PasswordResetThrottledError{RetryAfter: wait}
+	}
+	for _, limit := range limits {
+		if _, err := limit.limiter.Fail(ctx, limit.key); err != nil {
+			return err
+		}
+	}
+
+	deadline := time.Now().Add(pu.policy.ResponseTime)
+	err := pu.sendResetLink(email)
+	if waitErr := sleepUntil(ctx, deadline); err == nil {
+		err = waitErr
+	}
+	return err
+}
+
+// sendResetLink queues the reset email to the user with the email, if any
+func (pu *passwordResetUsecaseImpl) sendResetLink(email string) error {
+	user, err := pu.userRepository.GetUserByEmail(email)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return nil
+	}
+
+	token, err := newSecretToken()
+	if err != nil {
+		return err
+	}
+	resetToken := model.PasswordResetToken{
+		UserID:    user.ID,
+		TokenHash: hashSecretToken(token),
+		ExpiresAt: time.Now().Add(pu.policy.TokenTTL),
+	}
+	message, err := pu.resetEmail(*user, token)
+	if err != nil {
+		return err
+	}
+	return pu.repository.CreatePasswordResetToken(resetToken, message)
+}
+
+// ResetPassword sets the password of the user of the token, which then stops
+// working along with every other token of the user. The password is checked
+// against the strength rules first, which do not use up the token
+func (pu *passwordResetUsecaseImpl) ResetPassword(ctx context.Context, token, newPassword string) error {
+	tokenHash := hashSecretToken(token)
+	userID, err := pu.repository.GetPasswordResetUserID(tokenHash)
+	if err != nil {
+		return err
+	}
+	if userID == 0 {
+		return ErrInvalidResetToken
+	}
+	user, err := pu.userRepository.GetUserByID(userID)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return ErrInvalidResetToken
+	}
+	if err := pu.policy.Password.Validate(newPassword, user.Email, user.Name); err != nil {
+		return err
+	}
+
+	hashedPassword, err := pu.passwords.Hash(newPassword)
+	if err != nil {
+		return err
+	}
+
+	// The repository fills in the user, who is also the actor
+	event, err := newAuditEvent(ctx, model.AuditActionPasswordReset, model.AuditEntityUser, "", nil,
+		map[string]interface{}{"password": hashedPassword})
+	if err != nil {
+		return err
+	}
+	if _, err := pu.repository.ResetPassword(tokenHash, hashedPassword, event); err != nil {
+		if err == sql.ErrNoRows {
+			return ErrInvalidResetToken
+		}
+		return err
+	}
+	// The user got back in, so the email may ask for resets again
+	if err := pu.emails.Reset(ctx, resetEmailKey(user.Email)); err != nil {
+		log.Printf("reset requests of user %d: %v", user.ID, err)
+	}
+	return nil
+}
+
+// --- Helper Functions ---
+
+// limits returns the email and, when known, the IP of the request, in keys
+// of their own so they never add to the failed logins
+func (pu *passwordResetUsecaseImpl) limits(ctx context.Context, email string) []loginLimit {
+	limits := []loginLimit{{pu.emails, resetEmailKey(email)}}
+	if ip := audit.FromContext(ctx).IP; ip != "" {
+		limits = append(limits, loginLimit{pu.ips, "reset:" + ipKey(ip)})
+	}
+	return limits
+}
+
+// resetEmailKey counts the reset requests of an email apart from its
+// failed logins
+func resetEmailKey(email string) string {
+	return "reset:" + accountKey(email)
+}
+
+// sleepUntil waits for deadline, or until ctx is done
+func sleepUntil(ctx context.Context, deadline time.Time) error {
+	timer := time.NewTimer(time.Until(deadline))
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (pu *passwordResetUsecaseImpl) resetEmail(user model.User, token string) (model.OutboxEmail, error) {
+	link, err := url.Parse(pu.policy.ResetURL)
+	if err != nil {
+		return model.OutboxEmail{}, fmt.Errorf("invalid password reset URL: %w", err)
+	}
+	query := link.Query()
+	query.Set("token", token)
+	link.RawQuery = query.Encode()
+
+	body := fmt.Sprintf(`Olá, %s!
+
+Recebemos um pedido para redefinir a senha da sua conta. Para escolher uma nova senha, acesse:
+
+%s
+
+O link vale por %s e só pode ser usado uma vez. Se você não fez o pedido, ignore este email: sua senha continua a mesma.
+`, user.Name, link.String(), formatTTL(pu.policy.TokenTTL))
+	return model.OutboxEmail{Recipient: user.Email, Subject: "Redefinição de senha", Body: body}, nil
+}
+
+// formatTTL writes the validity of a link in minutes or hours
+func formatTTL(ttl time.Duration) string {
+	if ttl >= time.Hour && ttl%time.Hour == 0 {
+		if ttl == time.Hour {
+			return "1 hora"
+		}
+		return fmt.Sprintf("%d horas", int(ttl/time.Hour))
+	}
+	if ttl <= time.Minute {
+		return "1 minuto"
+	}
+	return fmt.Sprintf("%d minutos", int(ttl/time.Minute))
+}
+
+// newSecretToken returns 256 random bits, URL safe, for links sent by email
+func newSecretToken() (string, error) {
+	token := make([]byte, 32)
+	if _, err := rand.Read(token); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(token), nil
+}
+
+// hashSecretToken is what the database stores in place of a token, so a leak
+// does not hand out working links
+func hashSecretToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,179 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"go-api/internal/password"
+	"go-api/internal/throttle"
+	"go-api/model"
+	"net/url"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var resetLinkPattern = regexp.MustCompile(`https://loja.example/redefinir\?\S+`)
+
+func TestPasswordResetUsecase_ForgotPassword(t *testing.T) {
+	policy := PasswordResetPolicy{
+		TokenTTL: 30 * time.Minute,
+		ResetURL: "https://loja.example/redefinir?lang=pt",
+		Email:    throttle.Policy{Window: time.Hour, FreeFailures: 2, BaseDelay: time.Minute, MaxDelay: time.Hour},
+		IP:       throttle.Policy{Window: time.Hour, FreeFailures: 10, BaseDelay: time.Minute, MaxDelay: time.Hour},
+	}
+
+	t.Run("Emails A Link With The Token", func(t *testing.T) {
+		var stored model.PasswordResetToken
+		var sent model.OutboxEmail
+		mockRepo := &MockPasswordResetRepository{
+			CreatePasswordResetTokenFunc: func(token model.PasswordResetToken, email model.OutboxEmail) error {
+				stored, sent = token, email
+				return nil
+			},
+		}
+		mockUserRepo := &MockUserRepository{
+			GetUserByEmailFunc: func(email string) (*model.User, error) {
+				assert.Equal(t, "ana@example.com", email)
+				return &model.User{ID: 7, Name: "Ana", Email: email}, nil
+			},
+		}
+
+		usecase := NewPasswordResetUsecase(mockRepo, mockUserRepo, testHasher, policy, throttle.NewMemoryStore())
+		err := usecase.ForgotPassword(context.Background(), " ana@example.com ")
+
+		assert.NoError(t, err)
+		assert.Equal(t, 7, stored.UserID)
+		assert.WithinDuration(t, time.Now().Add(30*time.Minute), stored.ExpiresAt, time.Minute)
+		assert.Equal(t, "ana@example.com", sent.Recipient)
+		assert.Contains(t, sent.Body, "30 minutos")
+
+		link, err := url.Parse(resetLinkPattern.FindString(sent.Body))
+		assert.NoError(t, err)
+		assert.Equal(t, "pt", link.Query().Get("lang"))
+		token := link.Query().Get("token")
+		assert.Len(t, token, 43)
+		assert.Equal(t, hashSecretToken(token), stored.TokenHash)
+	})
+
+	t.Run("Unknown Email Sends Nothing As Late As A Known One", func(t *testing.T) {
+		mockRepo := &MockPasswordResetRepository{
+			CreatePasswordResetTokenFunc: func(token model.PasswordResetToken, email model.OutboxEmail) error {
+				t.Fatal("no token should be created")
+				return nil
+			},
+		}
+
+		slow := policy
+		slow.ResponseTime = 50 * time.Millisecond
+		usecase := NewPasswordResetUsecase(mockRepo, &MockUserRepository{}, testHasher, slow, throttle.NewMemoryStore())
+		start := time.Now()
+		err := usecase.ForgotPassword(context.Background(), "nobody@example.com")
+
+		assert.NoError(t, err)
+		assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+	})
+
+	t.Run("Too Many Requests Are Throttled Apart From The Logins", func(t *testing.T) {
+		var sent int
+		mockRepo := &MockPasswordResetRepository{
+			CreatePasswordResetTokenFunc: func(token model.PasswordResetToken, email model.OutboxEmail) error {
+				sent++
+				return nil
+			},
+		}
+		mockUserRepo := &MockUserRepository{
+			GetUserByEmailFunc: func(email string) (*model.User, error) {
+				return &model.User{ID: 7, Name: "Ana", Email: email}, nil
+			},
+		}
+
+		store := throttle.NewMemoryStore()
+		usecase := NewPasswordResetUsecase(mockRepo, mockUserRepo, testHasher, policy, store)
+		for i := 0; i < 3; i++ {
+			assert.NoError(t, usecase.ForgotPassword(context.Background(), "ana@example.com"))
+		}
+		err := usecase.ForgotPassword(context.Background(), "ANA@example.com")
+
+		var throttled *PasswordResetThrottledError
+		assert.True(t, errors.As(err, &throttled))
+		assert.Equal(t, 3, sent)
+
+		logins := NewLoginThrottleUsecase(store, &MockAuditRepository{}, DefaultLoginThrottlePolicy)
+		assert.NoError(t, logins.Check(context.Background(), "ana@example.com"))
+	})
+}
+
+func TestPasswordResetUsecase_ResetPassword(t *testing.T) {
+	userRepo := &MockUserRepository{
+		GetUserByIDFunc: func(id int) (*model.User, error) {
+			return &model.User{ID: id, Name: "Ana Souza", Email: "ana.souza@example.com"}, nil
+		},
+	}
+	validToken := func(tokenHash string) (int, error) {
+		assert.Equal(t, hashSecretToken("token"), tokenHash)
+		return 7, nil
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		mockRepo := &MockPasswordResetRepository{
+			GetPasswordResetUserIDFunc: validToken,
+			ResetPasswordFunc: func(tokenHash, hash string, event model.AuditEvent) (int, error) {
+				assert.Equal(t, hashSecretToken("token"), tokenHash)
+				ok, err := password.Verify("correct horse battery", hash)
+				assert.NoError(t, err)
+				assert.True(t, ok)
+				assert.Equal(t, model.AuditActionPasswordReset, event.Action)
+
+				var changes map[string]map[string]interface{}
+				assert.NoError(t, json.Unmarshal(event.Changes, &changes))
+				assert.Equal(t, "[REDACTED]", changes["password"]["after"])
+				return 7, nil
+			},
+		}
+
+		usecase := NewPasswordResetUsecase(mockRepo, userRepo, testHasher, DefaultPasswordResetPolicy, throttle.NewMemoryStore())
+		err := usecase.ResetPassword(context.Background(), "token", "correct horse battery")
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("Weak Password Keeps The Token", func(t *testing.T) {
+		mockRepo := &MockPasswordResetRepository{
+			GetPasswordResetUserIDFunc: validToken,
+			ResetPasswordFunc: func(tokenHash, hash string, event model.AuditEvent) (int, error) {
+				t.Fatal("token used by a weak password")
+				return 0, nil
+			},
+		}
+
+		usecase := NewPasswordResetUsecase(mockRepo, userRepo, testHasher, DefaultPasswordResetPolicy, throttle.NewMemoryStore())
+		err := usecase.ResetPassword(context.Background(), "token", "souza2026!")
+
+		assert.True(t, errors.Is(err, password.ErrWeak))
+	})
+
+	t.Run("Invalid Token", func(t *testing.T) {
+		usecase := NewPasswordResetUsecase(&MockPasswordResetRepository{}, userRepo, testHasher, DefaultPasswordResetPolicy, throttle.NewMemoryStore())
+		err := usecase.ResetPassword(context.Background(), "used", "correct horse battery")
+
+		assert.True(t, errors.Is(err, ErrInvalidResetToken))
+	})
+
+	t.Run("Token Used In The Meantime", func(t *testing.T) {
+		mockRepo := &MockPasswordResetRepository{
+			GetPasswordResetUserIDFunc: validToken,
+			ResetPasswordFunc: func(tokenHash, hash string, event model.AuditEvent) (int, error) {
+				return 0, sql.ErrNoRows
+			},
+		}
+
+		usecase := NewPasswordResetUsecase(mockRepo, userRepo, testHasher, DefaultPasswordResetPolicy, throttle.NewMemoryStore())
+		err := usecase.ResetPassword(context.Background(), "token", "correct horse battery")
+
+		assert.True(t, errors.Is(err, ErrInvalidResetToken))
+	})
+}
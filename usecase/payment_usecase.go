@@ -0,0 +1,332 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-api/internal/payment"
+	"go-api/model"
+	"go-api/repository"
+)
+
+var (
+	ErrPaymentNotFound    = errors.New("payment not found")
+	ErrOrderNotPayable    = errors.New("only pending orders can be paid")
+	ErrPaymentDeclined    = errors.New("payment declined")
+	ErrPaymentUnavailable = errors.New("payment provider unavailable, try again")
+	ErrInvalidWebhook     = errors.New("invalid payment webhook")
+	ErrNothingToRefund    = errors.New("order has no captured payment to refund")
+)
+
+// PaymentUsecase defines the contract for paying and refunding orders through
+// the payment gateway and for the webhooks it sends
+type PaymentUsecase interface {
+	PayOrder(ctx context.Context, userID, orderID int) (*model.Payment, error)
+	GetOrderPayments(orderID int) ([]model.Payment, error)
+	RefundOrder(ctx context.Context, orderID int) (*model.Order, error)
+	HandleWebhook(ctx context.Context, payload []byte, signature string) (bool, error)
+}
+
+type paymentUsecaseImpl struct {
+	repository   repository.PaymentRepositoryInterface
+	orderUsecase OrderUsecase
+	gateway      payment.PaymentGateway
+}
+
+// NewPaymentUsecase creates a new instance of PaymentUsecase
+func NewPaymentUsecase(repo repository.PaymentRepositoryInterface, orderUsecase OrderUsecase, gateway payment.PaymentGateway) PaymentUsecase {
+	return &paymentUsecaseImpl{
+		repository:   repo,
+		orderUsecase: orderUsecase,
+		gateway:      gateway,
+	}
+}
+
+// PayOrder charges the total of a pending order of the user and marks it paid.
+// A call that timed out can be repeated: the same intent is captured again
+// instead of charging twice. Declined attempts are kept as failed payments
+func (pu *paymentUsecaseImpl) PayOrder(ctx context.Context, userID, orderID int) (*model.Payment, error) {
+	order, err := pu.orderUsecase.GetUserOrder(userID, orderID)
+	if err != nil {
+		return nil, err
+	}
+	payments, err := pu.repository.GetOrderPayments(order.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var attempt *model.Payment
+	for i := range payments {
+		switch payments[i].Status {
+		case model.PaymentStatusSucceeded:
+			// Paid before, but the order was not updated: only that is left to do
+			refunded, err := pu.settleCapture(ctx, payments[i])
+			if err != nil {
+				return nil, err
+			}
+			if !refunded {
+				return &payments[i], nil
+			}
+		case model.PaymentStatusPending:
+			attempt = &payments[i]
+		}
+	}
+	if order.Status != model.OrderStatusPending {
+		return nil, fmt.Errorf("%w: the order is %s", ErrOrderNotPayable, order.Status)
+	}
+
+	if attempt == nil {
+		if attempt, err = pu.createAttempt(ctx, *order, len(payments)+1); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := pu.gateway.Capture(ctx, attempt.IntentID); err != nil {
+		if errors.Is(err, payment.ErrDeclined) {
+			if err := pu.repository.UpdatePaymentStatus(attempt.ID, model.PaymentStatusFailed); err != nil {
+				return nil, err
+			}
+		}
+		return nil, paymentGatewayError(err)
+	}
+	if err := pu.repository.UpdatePaymentStatus(attempt.ID, model.PaymentStatusSucceeded); err != nil {
+		return nil, err
+	}
+	refunded, err := pu.settleCapture(ctx, *attempt)
+	if err != nil {
+		return nil, err
+	}
+	if refunded {
+		return nil, fmt.Errorf("%w: the order changed during the payment, which was refunded", ErrOrderNotPayable)
+	}
+	return pu.repository.GetPaymentByIntentID(attempt.Provider, attempt.IntentID)
+}
+
+func (pu *paymentUsecaseImpl) GetOrderPayments(orderID int) ([]model.Payment, error) {
+	if _, err := pu.orderUsecase.GetOrder(orderID); err != nil {
+		return nil, err
+	}
+	return pu.repository.GetOrderPayments(orderID)
+}
+
+// RefundOrder gives back the captured payment of the order and marks it refunded
+func (pu *paymentUsecaseImpl) RefundOrder(ctx context.Context, orderID int) (*model.Order, error) {
+	order, err := pu.orderUsecase.GetOrder(orderID)
+	if err != nil {
+		return nil, err
+	}
+	if !model.CanTransitionOrder(order.Status, model.OrderStatusRefunded) {
+		return nil, fmt.Errorf("%w: %s to %s", ErrInvalidTransition, order.Status, model.OrderStatusRefunded)
+	}
+	payments, err := pu.repository.GetOrderPayments(orderID)
+	if err != nil {
+		return nil, err
+	}
+	var captured *model.Payment
+	for i := range payments {
+		if payments[i].Status == model.PaymentStatusSucceeded {
+			captured = &payments[i]
+		}
+	}
+	if captured == nil {
+		return nil, ErrNothingToRefund
+	}
+
+	if _, err := pu.gateway.Refund(ctx, captured.IntentID); err != nil {
+		return nil, paymentGatewayError(err)
+	}
+	if err := pu.repository.UpdatePaymentStatus(captured.ID, model.PaymentStatusRefunded); err != nil {
+		return nil, err
+	}
+	return pu.orderUsecase.RecordPaymentStatus(ctx, orderID, model.OrderStatusRefunded, paymentNote(captured.IntentID, "refunded"))
+}
+
+// HandleWebhook verifies a webhook of the gateway and applies its event. Each
+// event is applied once: false means it was already received. Events whose
+// processing fails are forgotten, so the provider can deliver them again
+func (pu *paymentUsecaseImpl) HandleWebhook(ctx context.Context, body []byte, signature string) (bool, error) {
+	event, err := pu.gateway.VerifyWebhook(body, signature)
+	if err != nil {
+		return false, fmt.Errorf("%w: %v", ErrInvalidWebhook, err)
+	}
+
+	provider := pu.gateway.Name()
+	fresh, err := pu.repository.RecordWebhookEvent(provider, event.ID, event.Type)
+	if err != nil {
+		return false, err
+	}
+	if !fresh {
+		return false, nil
+	}
+
+	if err := pu.applyEvent(ctx, provider, *event); err != nil {
+		if forgetErr := pu.repository.ForgetWebhookEvent(provider, event.ID); forgetErr != nil {
+			return false, errors.Join(err, forgetErr)
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// --- Helper Functions ---
+
+// createAttempt creates the intent of a new payment attempt and records it.
+// The idempotency key only changes between attempts, so retrying after a
+// timeout gets the same intent back
+func (pu *paymentUsecaseImpl) createAttempt(ctx context.Context, order model.Order, number int) (*model.Payment, error) {
+	intent, err := pu.gateway.CreateIntent(ctx, payment.IntentRequest{
+		OrderID:        order.ID,
+		Amount:         order.Total.Amount,
+		Currency:       order.Total.Currency,
+		IdempotencyKey: fmt.Sprintf("order-%d-%d", order.ID, number),
+	})
+	if intent == nil {
+		if err == nil {
+			return nil, fmt.Errorf("%w: gateway returned no intent", ErrPaymentUnavailable)
+		}
+		return nil, paymentGatewayError(err)
+	}
+
+	attempt := model.Payment{
+		OrderID:  order.ID,
+		Provider: pu.gateway.Name(),
+		IntentID: intent.ID,
+		Status:   model.PaymentStatusPending,
+		Amount:   order.Total,
+	}
+	if err != nil {
+		attempt.Status = model.PaymentStatusFailed
+	}
+	id, createErr := pu.repository.CreatePayment(attempt)
+	if createErr != nil {
+		return nil, createErr
+	}
+	if err != nil {
+		return nil, paymentGatewayError(err)
+	}
+	attempt.ID = id
+	return &attempt, nil
+}
+
+// applyEvent brings the payment and its order to the state the event reports,
+// leaving alone what is already there
+func (pu *paymentUsecaseImpl) applyEvent(ctx context.Context, provider string, event payment.Event) error {
+	record, err := pu.repository.GetPaymentByIntentID(provider, event.IntentID)
+	if err != nil {
+		return err
+	}
+	if record == nil {
+		return fmt.Errorf("%w: intent %s", ErrPaymentNotFound, event.IntentID)
+	}
+
+	switch event.Type {
+	case payment.EventPaymentSucceeded:
+		if record.Status == model.PaymentStatusRefunded {
+			return nil
+		}
+		if record.Status == model.PaymentStatusPending || record.Status == model.PaymentStatusFailed {
+			if err := pu.repository.UpdatePaymentStatus(record.ID, model.PaymentStatusSucceeded); err != nil {
+				return err
+			}
+		}
+		_, err := pu.settleCapture(ctx, *record)
+		return err
+	case payment.EventPaymentFailed:
+		if record.Status == model.PaymentStatusPending {
+			return pu.repository.UpdatePaymentStatus(record.ID, model.PaymentStatusFailed)
+		}
+	case payment.EventPaymentRefunded:
+		if record.Status != model.PaymentStatusRefunded {
+			if err := pu.repository.UpdatePaymentStatus(record.ID, model.PaymentStatusRefunded); err != nil {
+				return err
+			}
+		}
+		// The refund of a charge the order never took leaves the order alone
+		paidByOther, err := pu.paidByOther(record.OrderID, record.ID)
+		if err != nil || paidByOther {
+			return err
+		}
+		return pu.advanceOrder(ctx, record.OrderID, []string{model.OrderStatusPaid, model.OrderStatusDelivered}, model.OrderStatusRefunded, paymentNote(record.IntentID, "refunded"))
+	}
+	return nil
+}
+
+// settleCapture marks the order of a captured payment paid. A charge the
+// order cannot take, because it was cancelled or refunded in the meantime or
+// another payment paid it, is refunded instead, so the customer is not
+// charged for nothing; true tells that it was
+func (pu *paymentUsecaseImpl) settleCapture(ctx context.Context, captured model.Payment) (bool, error) {
+	if err := pu.advanceOrder(ctx, captured.OrderID, []string{model.OrderStatusPending}, model.OrderStatusPaid, paymentNote(captured.IntentID, "succeeded")); err != nil {
+		return false, err
+	}
+	order, err := pu.orderUsecase.GetOrder(captured.OrderID)
+	if err != nil {
+		return false, err
+	}
+
+	switch order.Status {
+	case model.OrderStatusCancelled, model.OrderStatusRefunded:
+	case model.OrderStatusPending:
+		return false, nil
+	default:
+		paidByOther, err := pu.paidByOther(order.ID, captured.ID)
+		if err != nil || !paidByOther {
+			return false, err
+		}
+	}
+
+	if _, err := pu.gateway.Refund(ctx, captured.IntentID); err != nil {
+		return false, paymentGatewayError(err)
+	}
+	return true, pu.repository.UpdatePaymentStatus(captured.ID, model.PaymentStatusRefunded)
+}
+
+// paidByOther reports whether a payment of the order other than paymentID
+// was captured
+func (pu *paymentUsecaseImpl) paidByOther(orderID, paymentID int) (bool, error) {
+	payments, err := pu.repository.GetOrderPayments(orderID)
+	if err != nil {
+		return false, err
+	}
+	for _, p := range payments {
+		if p.ID != paymentID && p.Status == model.PaymentStatusSucceeded {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// advanceOrder moves the order to status when it is in one of from; orders
+// elsewhere, including those another request just moved, are left as they are
+func (pu *paymentUsecaseImpl) advanceOrder(ctx context.Context, orderID int, from []string, status, note string) error {
+	order, err := pu.orderUsecase.GetOrder(orderID)
+	if err != nil {
+		return err
+	}
+	for _, current := range from {
+		if order.Status != current {
+			continue
+		}
+		_, err := pu.orderUsecase.RecordPaymentStatus(ctx, orderID, status, note)
+		if errors.Is(err, ErrInvalidTransition) {
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+func paymentGatewayError(err error) error {
+	switch {
+	case errors.Is(err, payment.ErrDeclined):
+		return ErrPaymentDeclined
+	case errors.Is(err, payment.ErrTimeout), errors.Is(err, context.DeadlineExceeded):
+		return fmt.Errorf("%w: %v", ErrPaymentUnavailable, err)
+	default:
+		return err
+	}
+}
+
+func paymentNote(intentID, outcome string) string {
+	return fmt.Sprintf("payment %s %s", intentID, outcome)
+}
@@ -0,0 +1,328 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"go-api/internal/payment"
+	"go-api/model"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// paymentStore keeps one order and its payments in memory, behind the order
+// and payment repository mocks
+type paymentStore struct {
+	order       model.Order
+	payments    []model.Payment
+	events      map[string]bool
+	transitions []model.OrderTransition
+}
+
+func newPaymentStore(status string) *paymentStore {
+	return &paymentStore{
+		order:  model.Order{ID: 10, UserID: intPtr(7), Status: status, Total: model.Money{Amount: 9980, Currency: "BRL"}},
+		events: make(map[string]bool),
+	}
+}
+
+func (s *paymentStore) usecase(gateway payment.PaymentGateway) PaymentUsecase {
+	orderRepo := &MockOrderRepository{
+		GetOrderByIDFunc: func(id int) (*model.Order, error) {
+			if id != s.order.ID {
+				return nil, nil
+			}
+			order := s.order
+			return &order, nil
+		},
+		TransitionOrderFunc: func(id int, transition model.OrderTransition, restock bool) error {
+			s.order.Status = transition.To
+			s.transitions = append(s.transitions, transition)
+			return nil
+		},
+	}
+	paymentRepo := &MockPaymentRepository{
+		CreatePaymentFunc: func(p model.Payment) (int, error) {
+			p.ID = len(s.payments) + 1
+			s.payments = append(s.payments, p)
+			return p.ID, nil
+		},
+		GetPaymentByIntentIDFunc: func(provider, intentID string) (*model.Payment, error) {
+			for _, p := range s.payments {
+				if p.Provider == provider && p.IntentID == intentID {
+					return &p, nil
+				}
+			}
+			return nil, nil
+		},
+		GetOrderPaymentsFunc: func(orderID int) ([]model.Payment, error) {
+			return append([]model.Payment{}, s.payments...), nil
+		},
+		UpdatePaymentStatusFunc: func(id int, status string) error {
+			s.payments[id-1].Status = status
+			return nil
+		},
+		RecordWebhookEventFunc: func(provider, eventID, eventType string) (bool, error) {
+			if s.events[eventID] {
+				return false, nil
+			}
+			s.events[eventID] = true
+			return true, nil
+		},
+		ForgetWebhookEventFunc: func(provider, eventID string) error {
+			delete(s.events, eventID)
+			return nil
+		},
+	}
+	orders := NewOrderUsecase(orderRepo, &MockCartRepository{}, &MockProductRepository{}, &MockVariantRepository{}, nil, nil)
+	return NewPaymentUsecase(paymentRepo, orders, gateway)
+}
+
+// intentlessGateway answers CreateIntent with no intent and no error
+type intentlessGateway struct {
+	*payment.FakeGateway
+}
+
+func (g intentlessGateway) CreateIntent(ctx context.Context, request payment.IntentRequest) (*payment.Intent, error) {
+	return nil, nil
+}
+
+func TestPaymentUsecase_PayOrder(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Success Marks The Order Paid", func(t *testing.T) {
+		store := newPaymentStore(model.OrderStatusPending)
+		usecase := store.usecase(payment.NewFakeGateway([]byte("secret")))
+
+		paid, err := usecase.PayOrder(ctx, 7, 10)
+
+		require.NoError(t, err)
+		assert.Equal(t, model.PaymentStatusSucceeded, paid.Status)
+		assert.Equal(t, model.Money{Amount: 9980, Currency: "BRL"}, paid.Amount)
+		assert.Equal(t, model.OrderStatusPaid, store.order.Status)
+
+		again, err := usecase.PayOrder(ctx, 7, 10)
+		require.NoError(t, err)
+		assert.Equal(t, paid.IntentID, again.IntentID)
+		assert.Len(t, store.payments, 1)
+		assert.Len(t, store.transitions, 1)
+	})
+
+	t.Run("Declined Is Kept And Can Be Retried", func(t *testing.T) {
+		store := newPaymentStore(model.OrderStatusPending)
+		gateway := payment.NewFakeGateway([]byte("secret"))
+		gateway.Script(payment.OutcomeDecline)
+		usecase := store.usecase(gateway)
+
+		_, err := usecase.PayOrder(ctx, 7, 10)
+		assert.True(t, errors.Is(err, ErrPaymentDeclined))
+		assert.Equal(t, model.PaymentStatusFailed, store.payments[0].Status)
+		assert.Equal(t, model.OrderStatusPending, store.order.Status)
+
+		_, err = usecase.PayOrder(ctx, 7, 10)
+		require.NoError(t, err)
+		assert.Len(t, store.payments, 2)
+		assert.NotEqual(t, store.payments[0].IntentID, store.payments[1].IntentID)
+		assert.Equal(t, model.OrderStatusPaid, store.order.Status)
+	})
+
+	t.Run("Capture Timeout Reuses The Intent", func(t *testing.T) {
+		store := newPaymentStore(model.OrderStatusPending)
+		gateway := payment.NewFakeGateway([]byte("secret"))
+		gateway.Script(payment.OutcomeSucceed, payment.OutcomeTimeout)
+		usecase := store.usecase(gateway)
+
+		_, err := usecase.PayOrder(ctx, 7, 10)
+		assert.True(t, errors.Is(err, ErrPaymentUnavailable))
+		assert.Equal(t, model.PaymentStatusPending, store.payments[0].Status)
+
+		_, err = usecase.PayOrder(ctx, 7, 10)
+		require.NoError(t, err)
+		assert.Len(t, store.payments, 1)
+		assert.Equal(t, model.PaymentStatusSucceeded, store.payments[0].Status)
+	})
+
+	t.Run("Gateway Without An Intent", func(t *testing.T) {
+		store := newPaymentStore(model.OrderStatusPending)
+		usecase := store.usecase(intentlessGateway{payment.NewFakeGateway([]byte("secret"))})
+
+		paid, err := usecase.PayOrder(ctx, 7, 10)
+
+		assert.True(t, errors.Is(err, ErrPaymentUnavailable))
+		assert.Nil(t, paid)
+		assert.Empty(t, store.payments)
+		assert.Equal(t, model.OrderStatusPending, store.order.Status)
+	})
+
+	t.Run("Order Of Another User", func(t *testing.T) {
+		store := newPaymentStore(model.OrderStatusPending)
+		_, err := store.usecase(payment.NewFakeGateway([]byte("secret"))).PayOrder(ctx, 8, 10)
+
+		assert.Equal(t, ErrOrderNotFound, err)
+	})
+
+	t.Run("Cancelled Order", func(t *testing.T) {
+		store := newPaymentStore(model.OrderStatusCancelled)
+		_, err := store.usecase(payment.NewFakeGateway([]byte("secret"))).PayOrder(ctx, 7, 10)
+
+		assert.True(t, errors.Is(err, ErrOrderNotPayable))
+	})
+}
+
+func TestPaymentUsecase_HandleWebhook(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Applied Once", func(t *testing.T) {
+		store := newPaymentStore(model.OrderStatusPending)
+		store.payments = []model.Payment{{ID: 1, OrderID: 10, Provider: "fake", IntentID: "pi_1", Status: model.PaymentStatusPending}}
+		gateway := payment.NewFakeGateway([]byte("secret"))
+		usecase := store.usecase(gateway)
+
+		body, signature, err := gateway.Webhook(payment.EventPaymentSucceeded, "pi_1")
+		require.NoError(t, err)
+
+		applied, err := usecase.HandleWebhook(ctx, body, signature)
+		require.NoError(t, err)
+		assert.True(t, applied)
+		assert.Equal(t, model.PaymentStatusSucceeded, store.payments[0].Status)
+		assert.Equal(t, model.OrderStatusPaid, store.order.Status)
+		assert.Nil(t, store.transitions[0].ActorID)
+
+		applied, err = usecase.HandleWebhook(ctx, body, signature)
+		require.NoError(t, err)
+		assert.False(t, applied)
+		assert.Len(t, store.transitions, 1)
+	})
+
+	t.Run("Another Event With The Same Outcome Is Harmless", func(t *testing.T) {
+		store := newPaymentStore(model.OrderStatusPaid)
+		store.payments = []model.Payment{{ID: 1, OrderID: 10, Provider: "fake", IntentID: "pi_1", Status: model.PaymentStatusSucceeded}}
+		gateway := payment.NewFakeGateway([]byte("secret"))
+
+		body, signature, err := gateway.Webhook(payment.EventPaymentSucceeded, "pi_1")
+		require.NoError(t, err)
+		applied, err := store.usecase(gateway).HandleWebhook(ctx, body, signature)
+
+		require.NoError(t, err)
+		assert.True(t, applied)
+		assert.Empty(t, store.transitions)
+	})
+
+	t.Run("Refund Restocks The Order", func(t *testing.T) {
+		store := newPaymentStore(model.OrderStatusPaid)
+		store.payments = []model.Payment{{ID: 1, OrderID: 10, Provider: "fake", IntentID: "pi_1", Status: model.PaymentStatusSucceeded}}
+		gateway := payment.NewFakeGateway([]byte("secret"))
+
+		body, signature, err := gateway.Webhook(payment.EventPaymentRefunded, "pi_1")
+		require.NoError(t, err)
+		_, err = store.usecase(gateway).HandleWebhook(ctx, body, signature)
+
+		require.NoError(t, err)
+		assert.Equal(t, model.PaymentStatusRefunded, store.payments[0].Status)
+		assert.Equal(t, model.OrderStatusRefunded, store.order.Status)
+	})
+
+	t.Run("Capture For A Cancelled Order Is Refunded", func(t *testing.T) {
+		store := newPaymentStore(model.OrderStatusCancelled)
+		gateway := payment.NewFakeGateway([]byte("secret"))
+		intent, err := gateway.CreateIntent(ctx, payment.IntentRequest{OrderID: 10, Amount: 9980, Currency: "BRL", IdempotencyKey: "order-10-1"})
+		require.NoError(t, err)
+		_, err = gateway.Capture(ctx, intent.ID)
+		require.NoError(t, err)
+		store.payments = []model.Payment{{ID: 1, OrderID: 10, Provider: "fake", IntentID: intent.ID, Status: model.PaymentStatusPending}}
+		usecase := store.usecase(gateway)
+
+		body, signature, err := gateway.Webhook(payment.EventPaymentSucceeded, intent.ID)
+		require.NoError(t, err)
+		_, err = usecase.HandleWebhook(ctx, body, signature)
+
+		require.NoError(t, err)
+		assert.Equal(t, model.PaymentStatusRefunded, store.payments[0].Status)
+		assert.Equal(t, model.OrderStatusCancelled, store.order.Status)
+
+		// The refund the gateway reports back leaves the order alone
+		body, signature, err = gateway.Webhook(payment.EventPaymentRefunded, intent.ID)
+		require.NoError(t, err)
+		_, err = usecase.HandleWebhook(ctx, body, signature)
+
+		require.NoError(t, err)
+		assert.Equal(t, model.OrderStatusCancelled, store.order.Status)
+	})
+
+	t.Run("Second Capture Of A Paid Order Is Refunded", func(t *testing.T) {
+		store := newPaymentStore(model.OrderStatusPaid)
+		gateway := payment.NewFakeGateway([]byte("secret"))
+		intent, err := gateway.CreateIntent(ctx, payment.IntentRequest{OrderID: 10, Amount: 9980, Currency: "BRL", IdempotencyKey: "order-10-2"})
+		require.NoError(t, err)
+		_, err = gateway.Capture(ctx, intent.ID)
+		require.NoError(t, err)
+		store.payments = []model.Payment{
+			{ID: 1, OrderID: 10, Provider: "fake", IntentID: "pi_1", Status: model.PaymentStatusSucceeded},
+			{ID: 2, OrderID: 10, Provider: "fake", IntentID: intent.ID, Status: model.PaymentStatusPending},
+		}
+		usecase := store.usecase(gateway)
+
+		body, signature, err := gateway.Webhook(payment.EventPaymentSucceeded, intent.ID)
+		require.NoError(t, err)
+		_, err = usecase.HandleWebhook(ctx, body, signature)
+		require.NoError(t, err)
+		body, signature, err = gateway.Webhook(payment.EventPaymentRefunded, intent.ID)
+		require.NoError(t, err)
+		_, err = usecase.HandleWebhook(ctx, body, signature)
+
+		require.NoError(t, err)
+		assert.Equal(t, model.PaymentStatusSucceeded, store.payments[0].Status)
+		assert.Equal(t, model.PaymentStatusRefunded, store.payments[1].Status)
+		assert.Equal(t, model.OrderStatusPaid, store.order.Status)
+	})
+
+	t.Run("Unknown Intent Is Forgotten For Redelivery", func(t *testing.T) {
+		store := newPaymentStore(model.OrderStatusPending)
+		gateway := payment.NewFakeGateway([]byte("secret"))
+
+		body, signature, err := gateway.Webhook(payment.EventPaymentSucceeded, "pi_unknown")
+		require.NoError(t, err)
+		_, err = store.usecase(gateway).HandleWebhook(ctx, body, signature)
+
+		assert.True(t, errors.Is(err, ErrPaymentNotFound))
+		assert.Empty(t, store.events)
+	})
+
+	t.Run("Invalid Signature", func(t *testing.T) {
+		store := newPaymentStore(model.OrderStatusPending)
+		gateway := payment.NewFakeGateway([]byte("secret"))
+
+		body, _, err := gateway.Webhook(payment.EventPaymentSucceeded, "pi_1")
+		require.NoError(t, err)
+		_, signature, _ := payment.NewFakeGateway([]byte("other")).Webhook(payment.EventPaymentSucceeded, "pi_1")
+		_, err = store.usecase(gateway).HandleWebhook(ctx, body, signature)
+
+		assert.True(t, errors.Is(err, ErrInvalidWebhook))
+		assert.Empty(t, store.events)
+	})
+}
+
+func TestPaymentUsecase_RefundOrder(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Refunds The Captured Payment", func(t *testing.T) {
+		store := newPaymentStore(model.OrderStatusPending)
+		usecase := store.usecase(payment.NewFakeGateway([]byte("secret")))
+		_, err := usecase.PayOrder(ctx, 7, 10)
+		require.NoError(t, err)
+
+		order, err := usecase.RefundOrder(ctx, 10)
+
+		require.NoError(t, err)
+		assert.Equal(t, model.OrderStatusRefunded, order.Status)
+		assert.Equal(t, model.PaymentStatusRefunded, store.payments[0].Status)
+	})
+
+	t.Run("Nothing Captured", func(t *testing.T) {
+		store := newPaymentStore(model.OrderStatusPaid)
+		_, err := store.usecase(payment.NewFakeGateway([]byte("secret"))).RefundOrder(ctx, 10)
+
+		assert.Equal(t, ErrNothingToRefund, err)
+	})
+}
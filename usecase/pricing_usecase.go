@@ -0,0 +1,331 @@
+package usecase
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"go-api/model"
+	"go-api/repository"
+	"io"
+	"math/big"
+	"strings"
+	"time"
+)
+
+var (
+	ErrPriceListNotFound    = errors.New("price list not found")
+	ErrPriceListCodeTaken   = errors.New("price list code already exists")
+	ErrExchangeRateNotFound = errors.New("exchange rate not found")
+	ErrInvalidExchangeRate  = errors.New("exchange rate must be a positive decimal")
+)
+
+// ConversionRounding is the rounding applied to prices converted through exchange rates
+const ConversionRounding = model.RoundHalfUp
+
+// PricingUsecase defines the contract for price lists and exchange rates
+type PricingUsecase interface {
+	GetPriceLists() ([]model.PriceList, error)
+	CreatePriceList(list model.PriceList) (model.PriceList, error)
+	GetPriceListItems(priceListID int) ([]model.PriceListItem, error)
+	SetPriceListItem(priceListID, productID int, amount string) (model.PriceListItem, error)
+	DeletePriceListItem(priceListID, productID int) error
+	GetExchangeRates() ([]model.ExchangeRate, error)
+	SetExchangeRates(rates []model.ExchangeRate) ([]model.ExchangeRate, error)
+	ImportExchangeRates(reader io.Reader, source string) (int, error)
+}
+
+type pricingUsecaseImpl struct {
+	repository        repository.PricingRepositoryInterface
+	productRepository repository.ProductRepositoryInterface
+}
+
+// NewPricingUsecase creates a new instance of PricingUsecase
+func NewPricingUsecase(repo repository.PricingRepositoryInterface, productRepo repository.ProductRepositoryInterface) PricingUsecase {
+	return &pricingUsecaseImpl{
+		repository:        repo,
+		productRepository: productRepo,
+	}
+}
+
+func (pu *pricingUsecaseImpl) GetPriceLists() ([]model.PriceList, error) {
+	return pu.repository.GetPriceLists()
+}
+
+func (pu *pricingUsecaseImpl) CreatePriceList(list model.PriceList) (model.PriceList, error) {
+	list.Currency = strings.ToUpper(list.Currency)
+	if _, err := model.CurrencyExponent(list.Currency); err != nil {
+		return model.PriceList{}, err
+	}
+
+	existing, err := pu.repository.GetPriceListByCode(list.Code)
+	if err != nil {
+		return model.PriceList{}, err
+	}
+	if existing != nil {
+		return model.PriceList{}, ErrPriceListCodeTaken
+	}
+
+	id, err := pu.repository.CreatePriceList(list)
+	if err != nil {
+		return model.PriceList{}, err
+	}
+	list.ID = id
+	return list, nil
+}
+
+func (pu *pricingUsecaseImpl) GetPriceListItems(priceListID int) ([]model.PriceListItem, error) {
+	list, err := pu.repository.GetPriceListByID(priceListID)
+	if err != nil {
+		return nil, err
+	}
+	if list == nil {
+		return nil, ErrPriceListNotFound
+	}
+	return pu.repository.GetPriceListItems(priceListID)
+}
+
+// SetPriceListItem sets the explicit price of a product, in the currency of the list
+func (pu *pricingUsecaseImpl) SetPriceListItem(priceListID, productID int, amount string) (model.PriceListItem, error) {
+	list, err := pu.repository.GetPriceListByID(priceListID)
+	if err != nil {
+		return model.PriceListItem{}, err
+	}
+	if list == nil {
+		return model.PriceListItem{}, ErrPriceListNotFound
+	}
+
+	product, err := pu.productRepository.GetProductById(productID)
+	if err != nil {
+		return model.PriceListItem{}, err
+	}
+	if product == nil {
+		return model.PriceListItem{}, ErrProductNotFound
+	}
+
+	price, err := model.ParseMoney(amount, list.Currency)
+	if err != nil {
+		return model.PriceListItem{}, err
+	}
+	if price.IsNegative() {
+		return model.PriceListItem{}, fmt.Errorf("%w: price must not be negative", model.ErrInvalidAmount)
+	}
+
+	item := model.PriceListItem{PriceListID: priceListID, ProductID: productID, Price: price}
+	if err := pu.repository.SetPriceListItem(item); err != nil {
+		return model.PriceListItem{}, err
+	}
+	return item, nil
+}
+
+func (pu *pricingUsecaseImpl) DeletePriceListItem(priceListID, productID int) error {
+	list, err := pu.repository.GetPriceListByID(priceListID)
+	if err != nil {
+		return err
+	}
+	if list == nil {
+		return ErrPriceListNotFound
+	}
+	return pu.repository.DeletePriceListItem(priceListID, productID)
+}
+
+func (pu *pricingUsecaseImpl) GetExchangeRates() ([]model.ExchangeRate, error) {
+	return pu.repository.GetExchangeRates()
+}
+
+// SetExchangeRates validates and saves the rates, stamping them with the current time
+func (pu *pricingUsecaseImpl) SetExchangeRates(rates []model.ExchangeRate) ([]model.ExchangeRate, error) {
+	now := time.Now().UTC()
+	for i := range rates {
+		if err := normalizeExchangeRate(&rates[i]); err != nil {
+			return nil, err
+		}
+		if rates[i].UpdatedAt.IsZero() {
+			rates[i].UpdatedAt = now
+		}
+		if rates[i].Source == "" {
+			rates[i].Source = "manual"
+		}
+	}
+
+	if err := pu.repository.UpsertExchangeRates(rates); err != nil {
+		return nil, err
+	}
+	return rates, nil
+}
+
+// ImportExchangeRates loads a CSV file with the columns base,quote,rate and an
+// optional RFC 3339 updated_at. A header line is skipped. Nothing is saved when
+// any line is invalid.
+func (pu *pricingUsecaseImpl) ImportExchangeRates(reader io.Reader, source string) (int, error) {
+	csvReader := csv.NewReader(reader)
+	csvReader.FieldsPerRecord = -1
+	csvReader.TrimLeadingSpace = true
+
+	var rates []model.ExchangeRate
+	for line := 1; ; line++ {
+		record, err := csvReader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return 0, err
+		}
+		if line == 1 && strings.EqualFold(record[0], "base") {
+			continue
+		}
+		if len(record) < 3 {
+			return 0, fmt.Errorf("line %d: expected base,quote,rate[,updated_at]", line)
+		}
+
+		rate := model.ExchangeRate{Base: record[0], Quote: record[1], Source: source}
+		var ok bool
+		if rate.Rate, ok = new(big.Rat).SetString(record[2]); !ok {
+			return 0, fmt.Errorf("line %d: %w", line, ErrInvalidExchangeRate)
+		}
+		if len(record) > 3 && record[3] != "" {
+			if rate.UpdatedAt, err = time.Parse(time.RFC3339, record[3]); err != nil {
+				return 0, fmt.Errorf("line %d: invalid updated_at: %w", line, err)
+			}
+		}
+		if err := normalizeExchangeRate(&rate); err != nil {
+			return 0, fmt.Errorf("line %d: %w", line, err)
+		}
+		rates = append(rates, rate)
+	}
+
+	if _, err := pu.SetExchangeRates(rates); err != nil {
+		return 0, err
+	}
+	return len(rates), nil
+}
+
+// --- Helper Functions ---
+
+func normalizeExchangeRate(rate *model.ExchangeRate) error {
+	rate.Base = strings.ToUpper(strings.TrimSpace(rate.Base))
+	rate.Quote = strings.ToUpper(strings.TrimSpace(rate.Quote))
+	if _, err := model.CurrencyExponent(rate.Base); err != nil {
+		return err
+	}
+	if _, err := model.CurrencyExponent(rate.Quote); err != nil {
+		return err
+	}
+	if rate.Base == rate.Quote {
+		return fmt.Errorf("%w: base and quote currencies must differ", ErrInvalidExchangeRate)
+	}
+	if rate.Rate == nil || rate.Rate.Sign() <= 0 {
+		return ErrInvalidExchangeRate
+	}
+	return nil
+}
+
+// applyPricing resolves the price of each product in the requested currency:
+// an explicit price list entry wins, otherwise the base price is converted
+// through the exchange rate table (directly or through the inverse rate).
+// Variants inherit the resolved product price; their overrides are always
+// converted through the exchange rate
+func applyPricing(repo repository.PricingRepositoryInterface, products []model.Product, opts model.PriceOptions) error {
+	if opts.Currency == "" || len(products) == 0 {
+		return nil
+	}
+
+	productIDs := make([]int, 0, len(products))
+	for _, product := range products {
+		productIDs = append(productIDs, product.ID)
+	}
+	listPrices, err := repo.GetListPrices(opts.Currency, opts.Market, productIDs)
+	if err != nil {
+		return err
+	}
+
+	rates := make(map[string]*model.ExchangeRate)
+	rateFor := func(base string) (*model.ExchangeRate, error) {
+		if rate, ok := rates[base]; ok {
+			return rate, nil
+		}
+		rate, err := findExchangeRate(repo, base, opts.Currency)
+		if err != nil {
+			return nil, err
+		}
+		rates[base] = rate
+		return rate, nil
+	}
+
+	for i := range products {
+		product := &products[i]
+		original := product.Price
+
+		if listPrice, ok := listPrices[product.ID]; ok {
+			product.Price = listPrice.Price
+			product.Conversion = &model.PriceConversion{
+				Source:        model.PriceSourcePriceList,
+				PriceListCode: listPrice.PriceListCode,
+				Original:      original,
+			}
+		} else if original.Currency != opts.Currency {
+			rate, err := rateFor(original.Currency)
+			if err != nil {
+				return err
+			}
+			if product.Price, err = convertMoney(original, rate); err != nil {
+				return err
+			}
+			updatedAt := rate.UpdatedAt
+			product.Conversion = &model.PriceConversion{
+				Source:        model.PriceSourceExchangeRate,
+				Original:      original,
+				Rate:          rate.Rate,
+				RateTimestamp: &updatedAt,
+				Rounding:      ConversionRounding,
+			}
+		}
+
+		for j := range product.Variants {
+			variant := &product.Variants[j]
+			if variant.PriceOverride == nil {
+				variant.Price = product.Price
+				continue
+			}
+			if variant.PriceOverride.Currency == opts.Currency {
+				continue
+			}
+			rate, err := rateFor(variant.PriceOverride.Currency)
+			if err != nil {
+				return err
+			}
+			if variant.Price, err = convertMoney(*variant.PriceOverride, rate); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func convertMoney(amount model.Money, rate *model.ExchangeRate) (model.Money, error) {
+	return model.MoneyFromRat(new(big.Rat).Mul(amount.Rat(), rate.Rate), rate.Quote, ConversionRounding)
+}
+
+func findExchangeRate(repo repository.PricingRepositoryInterface, base, quote string) (*model.ExchangeRate, error) {
+	rate, err := repo.GetExchangeRate(base, quote)
+	if err != nil {
+		return nil, err
+	}
+	if rate != nil {
+		return rate, nil
+	}
+
+	inverse, err := repo.GetExchangeRate(quote, base)
+	if err != nil {
+		return nil, err
+	}
+	if inverse == nil {
+		return nil, fmt.Errorf("%w: %s to %s", ErrExchangeRateNotFound, base, quote)
+	}
+	return &model.ExchangeRate{
+		Base:      base,
+		Quote:     quote,
+		Rate:      new(big.Rat).Inv(inverse.Rate),
+		Source:    inverse.Source,
+		UpdatedAt: inverse.UpdatedAt,
+	}, nil
+}
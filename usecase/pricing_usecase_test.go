@@ -0,0 +1,195 @@
+package usecase
+
+import (
+	"errors"
+	"go-api/model"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProductUsecase_GetProductsWithPricing(t *testing.T) {
+	products := func() ([]model.Product, error) {
+		return []model.Product{
+			{ID: 1, Name: "Camiseta", Price: model.Money{Amount: 2999, Currency: "BRL"}},
+			{ID: 2, Name: "Caneca", Price: model.Money{Amount: 1000, Currency: "BRL"}},
+		}, nil
+	}
+	updatedAt := time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	t.Run("Price List Wins Over Conversion", func(t *testing.T) {
+		mockRepo := &MockProductRepository{
+			GetProductsFunc: func(filter model.ProductFilter, asOf time.Time) ([]model.Product, error) { return products() },
+		}
+		mockPricing := &MockPricingRepository{
+			GetListPricesFunc: func(currency, market string, productIDs []int) (map[int]model.ListPrice, error) {
+				assert.Equal(t, "USD", currency)
+				assert.Equal(t, "US", market)
+				return map[int]model.ListPrice{
+					1: {PriceListCode: "usd-us", Price: model.Money{Amount: 599, Currency: "USD"}},
+				}, nil
+			},
+			GetExchangeRateFunc: func(base, quote string) (*model.ExchangeRate, error) {
+				return &model.ExchangeRate{Base: base, Quote: quote, Rate: big.NewRat(185, 1000), UpdatedAt: updatedAt}, nil
+			},
+		}
+
+		usecase := NewProductUsecase(mockRepo, mockPricing, &MockVariantRepository{}, &MockImageRepository{}, nil)
+		result, err := usecase.GetProducts(model.ProductFilter{}, model.PriceOptions{Currency: "USD", Market: "US"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, model.Money{Amount: 599, Currency: "USD"}, result[0].Price)
+		assert.Equal(t, model.PriceSourcePriceList, result[0].Conversion.Source)
+		assert.Equal(t, "usd-us", result[0].Conversion.PriceListCode)
+
+		// 10.00 BRL * 0.185 = 1.85 USD
+		assert.Equal(t, model.Money{Amount: 185, Currency: "USD"}, result[1].Price)
+		assert.Equal(t, model.PriceSourceExchangeRate, result[1].Conversion.Source)
+		assert.Equal(t, model.Money{Amount: 1000, Currency: "BRL"}, result[1].Conversion.Original)
+		assert.Equal(t, updatedAt, *result[1].Conversion.RateTimestamp)
+		assert.Equal(t, model.RoundHalfUp, result[1].Conversion.Rounding)
+	})
+
+	t.Run("Uses Inverse Rate", func(t *testing.T) {
+		mockRepo := &MockProductRepository{
+			GetProductsFunc: func(filter model.ProductFilter, asOf time.Time) ([]model.Product, error) { return products() },
+		}
+		mockPricing := &MockPricingRepository{
+			GetExchangeRateFunc: func(base, quote string) (*model.ExchangeRate, error) {
+				if base == "USD" && quote == "BRL" {
+					return &model.ExchangeRate{Base: base, Quote: quote, Rate: big.NewRat(5, 1), UpdatedAt: updatedAt}, nil
+				}
+				return nil, nil
+			},
+		}
+
+		usecase := NewProductUsecase(mockRepo, mockPricing, &MockVariantRepository{}, &MockImageRepository{}, nil)
+		result, err := usecase.GetProducts(model.ProductFilter{}, model.PriceOptions{Currency: "USD"})
+
+		assert.NoError(t, err)
+		// 29.99 / 5 = 5.998 -> 6.00
+		assert.Equal(t, model.Money{Amount: 600, Currency: "USD"}, result[0].Price)
+		assert.Equal(t, 0, result[0].Conversion.Rate.Cmp(big.NewRat(1, 5)))
+	})
+
+	t.Run("Missing Exchange Rate", func(t *testing.T) {
+		mockRepo := &MockProductRepository{
+			GetProductsFunc: func(filter model.ProductFilter, asOf time.Time) ([]model.Product, error) { return products() },
+		}
+
+		usecase := NewProductUsecase(mockRepo, &MockPricingRepository{}, &MockVariantRepository{}, &MockImageRepository{}, nil)
+		result, err := usecase.GetProducts(model.ProductFilter{}, model.PriceOptions{Currency: "JPY"})
+
+		assert.ErrorIs(t, err, ErrExchangeRateNotFound)
+		assert.Nil(t, result)
+	})
+
+	t.Run("Same Currency Is Untouched", func(t *testing.T) {
+		mockRepo := &MockProductRepository{
+			GetProductsFunc: func(filter model.ProductFilter, asOf time.Time) ([]model.Product, error) { return products() },
+		}
+
+		usecase := NewProductUsecase(mockRepo, &MockPricingRepository{}, &MockVariantRepository{}, &MockImageRepository{}, nil)
+		result, err := usecase.GetProducts(model.ProductFilter{}, model.PriceOptions{Currency: "BRL"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, model.Money{Amount: 2999, Currency: "BRL"}, result[0].Price)
+		assert.Nil(t, result[0].Conversion)
+	})
+}
+
+func TestPricingUsecase_CreatePriceList(t *testing.T) {
+	t.Run("Code Taken", func(t *testing.T) {
+		mockRepo := &MockPricingRepository{
+			GetPriceListByCodeFunc: func(code string) (*model.PriceList, error) {
+				return &model.PriceList{ID: 1, Code: code}, nil
+			},
+		}
+
+		usecase := NewPricingUsecase(mockRepo, &MockProductRepository{})
+		_, err := usecase.CreatePriceList(model.PriceList{Code: "usd-default", Currency: "usd"})
+
+		assert.ErrorIs(t, err, ErrPriceListCodeTaken)
+	})
+
+	t.Run("Unknown Currency", func(t *testing.T) {
+		usecase := NewPricingUsecase(&MockPricingRepository{}, &MockProductRepository{})
+		_, err := usecase.CreatePriceList(model.PriceList{Code: "xyz", Currency: "XYZ"})
+
+		assert.ErrorIs(t, err, model.ErrUnknownCurrency)
+	})
+}
+
+func TestPricingUsecase_SetPriceListItem(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		var saved model.PriceListItem
+		mockRepo := &MockPricingRepository{
+			GetPriceListByIDFunc: func(id int) (*model.PriceList, error) {
+				return &model.PriceList{ID: id, Currency: "USD"}, nil
+			},
+			SetPriceListItemFunc: func(item model.PriceListItem) error {
+				saved = item
+				return nil
+			},
+		}
+		mockProductRepo := &MockProductRepository{
+			GetProductByIdFunc: func(id int) (*model.Product, error) { return &model.Product{ID: id}, nil },
+		}
+
+		usecase := NewPricingUsecase(mockRepo, mockProductRepo)
+		item, err := usecase.SetPriceListItem(1, 2, "5.49")
+
+		assert.NoError(t, err)
+		assert.Equal(t, model.Money{Amount: 549, Currency: "USD"}, item.Price)
+		assert.Equal(t, item, saved)
+	})
+
+	t.Run("Price List Not Found", func(t *testing.T) {
+		usecase := NewPricingUsecase(&MockPricingRepository{}, &MockProductRepository{})
+		_, err := usecase.SetPriceListItem(9, 2, "5.49")
+
+		assert.ErrorIs(t, err, ErrPriceListNotFound)
+	})
+}
+
+func TestPricingUsecase_ImportExchangeRates(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		var saved []model.ExchangeRate
+		mockRepo := &MockPricingRepository{
+			UpsertExchangeRatesFunc: func(rates []model.ExchangeRate) error {
+				saved = rates
+				return nil
+			},
+		}
+
+		csv := "base,quote,rate,updated_at\nbrl,usd,0.185,2026-01-02T00:00:00Z\nBRL,EUR,0.17\n"
+		usecase := NewPricingUsecase(mockRepo, &MockProductRepository{})
+		imported, err := usecase.ImportExchangeRates(strings.NewReader(csv), "rates.csv")
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, imported)
+		assert.Equal(t, "USD", saved[0].Quote)
+		assert.Equal(t, "rates.csv", saved[0].Source)
+		assert.Equal(t, time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC), saved[0].UpdatedAt)
+		assert.False(t, saved[1].UpdatedAt.IsZero())
+	})
+
+	t.Run("Invalid Line Saves Nothing", func(t *testing.T) {
+		mockRepo := &MockPricingRepository{
+			UpsertExchangeRatesFunc: func(rates []model.ExchangeRate) error {
+				return errors.New("should not be called")
+			},
+		}
+
+		csv := "BRL,USD,0.185\nBRL,EUR,-1\n"
+		usecase := NewPricingUsecase(mockRepo, &MockProductRepository{})
+		imported, err := usecase.ImportExchangeRates(strings.NewReader(csv), "rates.csv")
+
+		assert.ErrorIs(t, err, ErrInvalidExchangeRate)
+		assert.Contains(t, err.Error(), "line 2")
+		assert.Equal(t, 0, imported)
+	})
+}
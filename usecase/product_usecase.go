@@ -1,44 +1,390 @@
 package usecase
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"go-api/internal/storage"
 	"go-api/model"
 	"go-api/repository"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrProductNotFound      = errors.New("product not found")
+	ErrInvalidPriceSchedule = errors.New("invalid price schedule")
+	ErrPriceChangeInPast    = errors.New("price changes cannot start in the past")
+	ErrProductSKUTaken      = errors.New("sku already used by another product")
 )
 
 type ProductUsecase interface {
-	GetProducts() ([]model.Product, error)
-	CreateProduct(product model.Product) (model.Product, error)
-	GetProductById(id_product int) (*model.Product, error)
+	GetProducts(filter model.ProductFilter, opts model.PriceOptions) ([]model.Product, error)
+	ExportProducts(ctx context.Context, filter model.ProductFilter, opts model.PriceOptions, fn func(model.Product) error) error
+	CreateProduct(ctx context.Context, product model.Product) (model.Product, error)
+	GetProductById(id_product int, opts model.PriceOptions) (*model.Product, error)
+	GetPriceHistory(id_product int) ([]model.ProductPrice, error)
+	SchedulePrice(ctx context.Context, id_product int, change model.PriceChange) (model.ProductPrice, error)
+	DeleteProduct(ctx context.Context, id_product int) error
+	GetDeletedProducts() ([]model.Product, error)
+	RestoreProduct(ctx context.Context, id_product int) (*model.Product, error)
+	PurgeProduct(ctx context.Context, id_product int) error
 }
 
 type productUsecaseImpl struct {
 	//repository
-	repository repository.ProductRepositoryInterface
+	repository        repository.ProductRepositoryInterface
+	pricingRepository repository.PricingRepositoryInterface
+	variantRepository repository.VariantRepositoryInterface
+	imageRepository   repository.ImageRepositoryInterface
+	blobStore         storage.BlobStore
 }
 
-func NewProductUsecase(repo repository.ProductRepositoryInterface) ProductUsecase {
+func NewProductUsecase(repo repository.ProductRepositoryInterface, pricingRepo repository.PricingRepositoryInterface, variantRepo repository.VariantRepositoryInterface, imageRepo repository.ImageRepositoryInterface, store storage.BlobStore) ProductUsecase {
 	return &productUsecaseImpl{
-		repository: repo,
+		repository:        repo,
+		pricingRepository: pricingRepo,
+		variantRepository: variantRepo,
+		imageRepository:   imageRepo,
+		blobStore:         store,
+	}
+}
+
+func (pu *productUsecaseImpl) GetProducts(filter model.ProductFilter, opts model.PriceOptions) ([]model.Product, error) {
+	products, err := pu.repository.GetProducts(filter, asOf(opts))
+	if err != nil {
+		return nil, err
+	}
+	if err := attachVariants(pu.variantRepository, products); err != nil {
+		return nil, err
+	}
+	if err := attachImages(pu.imageRepository, pu.blobStore, products); err != nil {
+		return nil, err
+	}
+	if err := applyPricing(pu.pricingRepository, products, opts); err != nil {
+		return nil, err
 	}
+	return products, nil
 }
 
-func (pu *productUsecaseImpl) GetProducts() ([]model.Product, error) {
-	return pu.repository.GetProducts()
+// ExportProducts streams the products matching the filter to fn with the
+// price in effect at opts.AsOf, in the product currency. Variants and images
+// are left out so no row needs more than the cursor fetch
+func (pu *productUsecaseImpl) ExportProducts(ctx context.Context, filter model.ProductFilter, opts model.PriceOptions, fn func(model.Product) error) error {
+	return pu.repository.ExportProducts(ctx, filter, asOf(opts), fn)
 }
 
-func (pu *productUsecaseImpl) CreateProduct(product model.Product) (model.Product, error) {
-	productId, err := pu.repository.CreateProduct(product)
+func (pu *productUsecaseImpl) CreateProduct(ctx context.Context, product model.Product) (model.Product, error) {
+	product.SKU = normalizeSKU(product.SKU)
+	if product.SKU != "" {
+		existing, err := pu.repository.GetProductBySKU(product.SKU)
+		if err != nil {
+			return model.Product{}, err
+		}
+		if existing != nil {
+			return model.Product{}, ErrProductSKUTaken
+		}
+	}
+
+	// The repository fills in the ID of the new product
+	event, err := newAuditEvent(ctx, model.AuditActionCreate, model.AuditEntityProduct, "", nil, productAuditFields(product))
 	if err != nil {
 		return model.Product{}, err
 	}
+	productId, err := pu.repository.CreateProduct(product, event)
+	if err != nil {
+		return model.Product{}, err
+	}
+	now := time.Now()
 	product.ID = productId
+	product.CreatedAt, product.UpdatedAt = now, now
+	product.CreatedBy, product.UpdatedBy = event.ActorID, event.ActorID
 	return product, nil
 }
 
-func (pu *productUsecaseImpl) GetProductById(id_product int) (*model.Product, error) {
+func (pu *productUsecaseImpl) GetProductById(id_product int, opts model.PriceOptions) (*model.Product, error) {
+	product, err := pu.repository.GetProductByIdAsOf(id_product, asOf(opts))
+	if err != nil {
+		return nil, err
+	}
+	if product == nil {
+		return nil, nil
+	}
+
+	products := []model.Product{*product}
+	if err := attachVariants(pu.variantRepository, products); err != nil {
+		return nil, err
+	}
+	if err := attachImages(pu.imageRepository, pu.blobStore, products); err != nil {
+		return nil, err
+	}
+	if err := applyPricing(pu.pricingRepository, products, opts); err != nil {
+		return nil, err
+	}
+	return &products[0], nil
+}
+
+// GetPriceHistory returns the price timeline of the product, each entry with
+// its status relative to now
+func (pu *productUsecaseImpl) GetPriceHistory(id_product int) ([]model.ProductPrice, error) {
 	product, err := pu.repository.GetProductById(id_product)
 	if err != nil {
 		return nil, err
 	}
-	return product, nil
+	if product == nil {
+		return nil, ErrProductNotFound
+	}
+
+	prices, err := pu.repository.GetProductPrices(id_product)
+	if err != nil {
+		return nil, err
+	}
+	setPriceStatuses(prices, time.Now())
+	return prices, nil
+}
+
+// SchedulePrice appends a price change to the history. Regular prices last
+// until superseded by a later regular price; sale prices need an end and
+// override the regular price within their window
+func (pu *productUsecaseImpl) SchedulePrice(ctx context.Context, id_product int, change model.PriceChange) (model.ProductPrice, error) {
+	product, err := pu.repository.GetProductById(id_product)
+	if err != nil {
+		return model.ProductPrice{}, err
+	}
+	if product == nil {
+		return model.ProductPrice{}, ErrProductNotFound
+	}
+
+	now := time.Now()
+	entry := model.ProductPrice{
+		ProductID:     id_product,
+		Kind:          change.Kind,
+		EffectiveFrom: change.EffectiveFrom,
+		EffectiveTo:   change.EffectiveTo,
+	}
+	if entry.Kind == "" {
+		entry.Kind = model.PriceKindRegular
+	}
+	if entry.EffectiveFrom.IsZero() {
+		entry.EffectiveFrom = now
+	} else if entry.EffectiveFrom.Before(now) {
+		return model.ProductPrice{}, ErrPriceChangeInPast
+	}
+
+	switch entry.Kind {
+	case model.PriceKindRegular:
+		if entry.EffectiveTo != nil {
+			return model.ProductPrice{}, fmt.Errorf("%w: regular prices last until superseded and take no effective_to", ErrInvalidPriceSchedule)
+		}
+	case model.PriceKindSale:
+		if entry.EffectiveTo == nil || !entry.EffectiveTo.After(entry.EffectiveFrom) {
+			return model.ProductPrice{}, fmt.Errorf("%w: sale prices need an effective_to after effective_from", ErrInvalidPriceSchedule)
+		}
+	default:
+		return model.ProductPrice{}, fmt.Errorf("%w: unknown kind %q", ErrInvalidPriceSchedule, entry.Kind)
+	}
+
+	entry.Price, err = model.ParseMoney(change.Amount, product.Price.Currency)
+	if err != nil {
+		return model.ProductPrice{}, err
+	}
+	if entry.Price.IsNegative() {
+		return model.ProductPrice{}, fmt.Errorf("%w: price must not be negative", model.ErrInvalidAmount)
+	}
+
+	history, err := pu.repository.GetProductPrices(id_product)
+	if err != nil {
+		return model.ProductPrice{}, err
+	}
+
+	event, err := newAuditEvent(ctx, model.AuditActionSchedulePrice, model.AuditEntityProduct, strconv.Itoa(id_product), nil, priceAuditFields(entry))
+	if err != nil {
+		return model.ProductPrice{}, err
+	}
+	entry.ID, err = pu.repository.CreateProductPrice(entry, event)
+	if err != nil {
+		return model.ProductPrice{}, err
+	}
+	entry.CreatedAt = now
+
+	// The status of the new entry depends on the rest of the timeline
+	history = append(history, entry)
+	setPriceStatuses(history, now)
+	return history[len(history)-1], nil
+}
+
+// DeleteProduct moves the product to the trash. It disappears from every
+// listing and lookup but keeps its prices, variants and images until purged
+func (pu *productUsecaseImpl) DeleteProduct(ctx context.Context, id_product int) error {
+	product, err := pu.repository.GetProductById(id_product)
+	if err != nil {
+		return err
+	}
+	if product == nil {
+		return ErrProductNotFound
+	}
+
+	event, err := newAuditEvent(ctx, model.AuditActionDelete, model.AuditEntityProduct, strconv.Itoa(id_product), trashAuditFields(false), trashAuditFields(true))
+	if err != nil {
+		return err
+	}
+	return pu.repository.DeleteProduct(id_product, event)
+}
+
+func (pu *productUsecaseImpl) GetDeletedProducts() ([]model.Product, error) {
+	return pu.repository.GetDeletedProducts()
+}
+
+// RestoreProduct takes the product out of the trash, unless its SKU was
+// taken by another product in the meantime
+func (pu *productUsecaseImpl) RestoreProduct(ctx context.Context, id_product int) (*model.Product, error) {
+	product, err := pu.repository.GetDeletedProductByID(id_product)
+	if err != nil {
+		return nil, err
+	}
+	if product == nil {
+		return nil, ErrProductNotFound
+	}
+	if product.SKU != "" {
+		existing, err := pu.repository.GetProductBySKU(product.SKU)
+		if err != nil {
+			return nil, err
+		}
+		if existing != nil {
+			return nil, ErrProductSKUTaken
+		}
+	}
+
+	event, err := newAuditEvent(ctx, model.AuditActionRestore, model.AuditEntityProduct, strconv.Itoa(id_product), trashAuditFields(true), trashAuditFields(false))
+	if err != nil {
+		return nil, err
+	}
+	if err := pu.repository.RestoreProduct(id_product, event); err != nil {
+		return nil, err
+	}
+	return pu.GetProductById(id_product, model.PriceOptions{})
+}
+
+// PurgeProduct deletes a product in the trash permanently with everything
+// attached to it, including the image files
+func (pu *productUsecaseImpl) PurgeProduct(ctx context.Context, id_product int) error {
+	product, err := pu.repository.GetDeletedProductByID(id_product)
+	if err != nil {
+		return err
+	}
+	if product == nil {
+		return ErrProductNotFound
+	}
+
+	images, err := pu.imageRepository.GetImages([]int{id_product})
+	if err != nil {
+		return err
+	}
+	// The event keeps the last state of the product, nothing else will
+	event, err := newAuditEvent(ctx, model.AuditActionPurge, model.AuditEntityProduct, strconv.Itoa(id_product), productAuditFields(*product), nil)
+	if err != nil {
+		return err
+	}
+	if err := pu.repository.PurgeProduct(id_product, event); err != nil {
+		return err
+	}
+	// Best effort, like deleting a single image: an orphaned file is harmless
+	for _, image := range images {
+		for _, key := range imageKeys(image) {
+			_ = pu.blobStore.Delete(context.Background(), key)
+		}
+	}
+	return nil
+}
+
+// --- Helper Functions ---
+
+// productAuditFields is what the audit log records of a product
+func productAuditFields(product model.Product) map[string]interface{} {
+	return map[string]interface{}{
+		"product_name": product.Name,
+		"sku":          product.SKU,
+		"price":        product.Price.String(),
+		"currency":     product.Price.Currency,
+	}
+}
+
+func priceAuditFields(entry model.ProductPrice) map[string]interface{} {
+	return map[string]interface{}{
+		"price":          entry.Price.String(),
+		"kind":           entry.Kind,
+		"effective_from": entry.EffectiveFrom,
+		"effective_to":   entry.EffectiveTo,
+	}
+}
+
+// normalizeSKU applies the same normalization as variant SKUs
+func normalizeSKU(sku string) string {
+	return strings.ToUpper(strings.TrimSpace(sku))
+}
+
+func asOf(opts model.PriceOptions) time.Time {
+	if opts.AsOf.IsZero() {
+		return time.Now()
+	}
+	return opts.AsOf
+}
+
+func priceInEffect(entry model.ProductPrice, at time.Time) bool {
+	return !entry.EffectiveFrom.After(at) && (entry.EffectiveTo == nil || entry.EffectiveTo.After(at))
+}
+
+// resolvePrice picks the entry in effect at the instant with the same
+// precedence used by the repository: sale first, then the latest
+// effective_from, then the latest entry
+func resolvePrice(entries []model.ProductPrice, at time.Time) *model.ProductPrice {
+	var best *model.ProductPrice
+	for i := range entries {
+		entry := &entries[i]
+		if !priceInEffect(*entry, at) {
+			continue
+		}
+		if best == nil || pricePrecedes(*entry, *best) {
+			best = entry
+		}
+	}
+	return best
+}
+
+func pricePrecedes(a, b model.ProductPrice) bool {
+	if (a.Kind == model.PriceKindSale) != (b.Kind == model.PriceKindSale) {
+		return a.Kind == model.PriceKindSale
+	}
+	if !a.EffectiveFrom.Equal(b.EffectiveFrom) {
+		return a.EffectiveFrom.After(b.EffectiveFrom)
+	}
+	return a.ID > b.ID
+}
+
+func setPriceStatuses(entries []model.ProductPrice, now time.Time) {
+	var regular []model.ProductPrice
+	for _, entry := range entries {
+		if entry.Kind == model.PriceKindRegular {
+			regular = append(regular, entry)
+		}
+	}
+	current := resolvePrice(entries, now)
+	currentRegular := resolvePrice(regular, now)
+
+	for i := range entries {
+		entry := &entries[i]
+		switch {
+		case entry.EffectiveFrom.After(now):
+			entry.Status = model.PriceStatusScheduled
+		case entry.EffectiveTo != nil && !entry.EffectiveTo.After(now):
+			entry.Status = model.PriceStatusExpired
+		case current != nil && entry.ID == current.ID:
+			entry.Status = model.PriceStatusActive
+		case currentRegular != nil && entry.ID == currentRegular.ID:
+			entry.Status = model.PriceStatusOverridden
+		default:
+			entry.Status = model.PriceStatusSuperseded
+		}
+	}
 }
@@ -1,28 +1,33 @@
 package usecase
 
 import (
+	"context"
 	"errors"
+	"go-api/internal/storage"
 	"go-api/model"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestProductUsecase_GetProducts(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		expectedProducts := []model.Product{
-			{ID: 1, Name: "Product 1", Price: 10.0},
-			{ID: 2, Name: "Product 2", Price: 20.0},
+			{ID: 1, Name: "Product 1", Price: model.Money{Amount: 1000, Currency: "BRL"}},
+			{ID: 2, Name: "Product 2", Price: model.Money{Amount: 2000, Currency: "BRL"}},
 		}
 
 		mockRepo := &MockProductRepository{
-			GetProductsFunc: func() ([]model.Product, error) {
+			GetProductsFunc: func(filter model.ProductFilter, asOf time.Time) ([]model.Product, error) {
 				return expectedProducts, nil
 			},
 		}
 
-		usecase := NewProductUsecase(mockRepo)
-		products, err := usecase.GetProducts()
+		usecase := NewProductUsecase(mockRepo, &MockPricingRepository{}, &MockVariantRepository{}, &MockImageRepository{}, nil)
+		products, err := usecase.GetProducts(model.ProductFilter{}, model.PriceOptions{})
 
 		assert.NoError(t, err)
 		assert.Len(t, products, 2)
@@ -32,13 +37,13 @@ func TestProductUsecase_GetProducts(t *testing.T) {
 
 	t.Run("Repository Error", func(t *testing.T) {
 		mockRepo := &MockProductRepository{
-			GetProductsFunc: func() ([]model.Product, error) {
+			GetProductsFunc: func(filter model.ProductFilter, asOf time.Time) ([]model.Product, error) {
 				return nil, errors.New("database connection failed")
 			},
 		}
 
-		usecase := NewProductUsecase(mockRepo)
-		products, err := usecase.GetProducts()
+		usecase := NewProductUsecase(mockRepo, &MockPricingRepository{}, &MockVariantRepository{}, &MockImageRepository{}, nil)
+		products, err := usecase.GetProducts(model.ProductFilter{}, model.PriceOptions{})
 
 		assert.Error(t, err)
 		assert.Nil(t, products)
@@ -50,17 +55,17 @@ func TestProductUsecase_CreateProduct(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		productToCreate := model.Product{
 			Name:  "New Product",
-			Price: 15.99,
+			Price: model.Money{Amount: 1599, Currency: "BRL"},
 		}
 
 		mockRepo := &MockProductRepository{
-			CreateProductFunc: func(product model.Product) (int, error) {
+			CreateProductFunc: func(product model.Product, event model.AuditEvent) (int, error) {
 				return 1, nil
 			},
 		}
 
-		usecase := NewProductUsecase(mockRepo)
-		createdProduct, err := usecase.CreateProduct(productToCreate)
+		usecase := NewProductUsecase(mockRepo, &MockPricingRepository{}, &MockVariantRepository{}, &MockImageRepository{}, nil)
+		createdProduct, err := usecase.CreateProduct(context.Background(), productToCreate)
 
 		assert.NoError(t, err)
 		assert.Equal(t, 1, createdProduct.ID)
@@ -68,20 +73,38 @@ func TestProductUsecase_CreateProduct(t *testing.T) {
 		assert.Equal(t, productToCreate.Price, createdProduct.Price)
 	})
 
+	t.Run("SKU Taken", func(t *testing.T) {
+		mockRepo := &MockProductRepository{
+			GetProductBySKUFunc: func(sku string) (*model.Product, error) {
+				assert.Equal(t, "CAM-01", sku)
+				return &model.Product{ID: 7, SKU: sku}, nil
+			},
+			CreateProductFunc: func(product model.Product, event model.AuditEvent) (int, error) {
+				t.Fatal("product must not be created")
+				return 0, nil
+			},
+		}
+
+		usecase := NewProductUsecase(mockRepo, &MockPricingRepository{}, &MockVariantRepository{}, &MockImageRepository{}, nil)
+		_, err := usecase.CreateProduct(context.Background(), model.Product{Name: "Camiseta", SKU: " cam-01 ", Price: model.Money{Amount: 4990, Currency: "BRL"}})
+
+		assert.Equal(t, ErrProductSKUTaken, err)
+	})
+
 	t.Run("Repository Error", func(t *testing.T) {
 		productToCreate := model.Product{
 			Name:  "New Product",
-			Price: 15.99,
+			Price: model.Money{Amount: 1599, Currency: "BRL"},
 		}
 
 		mockRepo := &MockProductRepository{
-			CreateProductFunc: func(product model.Product) (int, error) {
+			CreateProductFunc: func(product model.Product, event model.AuditEvent) (int, error) {
 				return 0, errors.New("insert failed")
 			},
 		}
 
-		usecase := NewProductUsecase(mockRepo)
-		createdProduct, err := usecase.CreateProduct(productToCreate)
+		usecase := NewProductUsecase(mockRepo, &MockPricingRepository{}, &MockVariantRepository{}, &MockImageRepository{}, nil)
+		createdProduct, err := usecase.CreateProduct(context.Background(), productToCreate)
 
 		assert.Error(t, err)
 		assert.Equal(t, model.Product{}, createdProduct)
@@ -94,7 +117,7 @@ func TestProductUsecase_GetProductById(t *testing.T) {
 		expectedProduct := &model.Product{
 			ID:    1,
 			Name:  "Test Product",
-			Price: 25.50,
+			Price: model.Money{Amount: 2550, Currency: "BRL"},
 		}
 
 		mockRepo := &MockProductRepository{
@@ -103,8 +126,8 @@ func TestProductUsecase_GetProductById(t *testing.T) {
 			},
 		}
 
-		usecase := NewProductUsecase(mockRepo)
-		product, err := usecase.GetProductById(1)
+		usecase := NewProductUsecase(mockRepo, &MockPricingRepository{}, &MockVariantRepository{}, &MockImageRepository{}, nil)
+		product, err := usecase.GetProductById(1, model.PriceOptions{})
 
 		assert.NoError(t, err)
 		assert.NotNil(t, product)
@@ -120,8 +143,8 @@ func TestProductUsecase_GetProductById(t *testing.T) {
 			},
 		}
 
-		usecase := NewProductUsecase(mockRepo)
-		product, err := usecase.GetProductById(999)
+		usecase := NewProductUsecase(mockRepo, &MockPricingRepository{}, &MockVariantRepository{}, &MockImageRepository{}, nil)
+		product, err := usecase.GetProductById(999, model.PriceOptions{})
 
 		assert.NoError(t, err)
 		assert.Nil(t, product)
@@ -134,11 +157,208 @@ func TestProductUsecase_GetProductById(t *testing.T) {
 			},
 		}
 
-		usecase := NewProductUsecase(mockRepo)
-		product, err := usecase.GetProductById(1)
+		usecase := NewProductUsecase(mockRepo, &MockPricingRepository{}, &MockVariantRepository{}, &MockImageRepository{}, nil)
+		product, err := usecase.GetProductById(1, model.PriceOptions{})
 
 		assert.Error(t, err)
 		assert.Nil(t, product)
 		assert.Contains(t, err.Error(), "query failed")
 	})
 }
+
+func TestProductUsecase_GetProductByIdAsOf(t *testing.T) {
+	t.Run("Passes As Of To Repository", func(t *testing.T) {
+		asOf := time.Date(2025, 12, 24, 0, 0, 0, 0, time.UTC)
+		mockRepo := &MockProductRepository{
+			GetProductByIdAsOfFunc: func(id_product int, at time.Time) (*model.Product, error) {
+				assert.Equal(t, asOf, at)
+				return &model.Product{ID: id_product, Price: model.Money{Amount: 1990, Currency: "BRL"}}, nil
+			},
+		}
+
+		usecase := NewProductUsecase(mockRepo, &MockPricingRepository{}, &MockVariantRepository{}, &MockImageRepository{}, nil)
+		product, err := usecase.GetProductById(1, model.PriceOptions{AsOf: asOf})
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1990), product.Price.Amount)
+	})
+}
+
+func TestProductUsecase_GetPriceHistory(t *testing.T) {
+	t.Run("Computes Statuses", func(t *testing.T) {
+		now := time.Now()
+		saleEnd := now.Add(24 * time.Hour)
+		expiredEnd := now.Add(-24 * time.Hour)
+		mockRepo := &MockProductRepository{
+			GetProductByIdFunc: func(id_product int) (*model.Product, error) {
+				return &model.Product{ID: id_product}, nil
+			},
+			GetProductPricesFunc: func(id_product int) ([]model.ProductPrice, error) {
+				return []model.ProductPrice{
+					{ID: 1, Kind: model.PriceKindRegular, EffectiveFrom: now.AddDate(0, -2, 0)},
+					{ID: 2, Kind: model.PriceKindSale, EffectiveFrom: now.AddDate(0, -1, 0), EffectiveTo: &expiredEnd},
+					{ID: 3, Kind: model.PriceKindRegular, EffectiveFrom: now.AddDate(0, 0, -7)},
+					{ID: 4, Kind: model.PriceKindSale, EffectiveFrom: now.Add(-time.Hour), EffectiveTo: &saleEnd},
+					{ID: 5, Kind: model.PriceKindRegular, EffectiveFrom: now.AddDate(0, 1, 0)},
+				}, nil
+			},
+		}
+
+		usecase := NewProductUsecase(mockRepo, &MockPricingRepository{}, &MockVariantRepository{}, &MockImageRepository{}, nil)
+		prices, err := usecase.GetPriceHistory(1)
+
+		assert.NoError(t, err)
+		statuses := make([]string, 0, len(prices))
+		for _, price := range prices {
+			statuses = append(statuses, price.Status)
+		}
+		assert.Equal(t, []string{
+			model.PriceStatusSuperseded,
+			model.PriceStatusExpired,
+			model.PriceStatusOverridden,
+			model.PriceStatusActive,
+			model.PriceStatusScheduled,
+		}, statuses)
+	})
+
+	t.Run("Product Not Found", func(t *testing.T) {
+		usecase := NewProductUsecase(&MockProductRepository{}, &MockPricingRepository{}, &MockVariantRepository{}, &MockImageRepository{}, nil)
+		prices, err := usecase.GetPriceHistory(99)
+
+		assert.ErrorIs(t, err, ErrProductNotFound)
+		assert.Nil(t, prices)
+	})
+}
+
+func TestProductUsecase_SchedulePrice(t *testing.T) {
+	product := func(id_product int) (*model.Product, error) {
+		return &model.Product{ID: id_product, Price: model.Money{Amount: 2999, Currency: "BRL"}}, nil
+	}
+
+	t.Run("Sale Price", func(t *testing.T) {
+		from := time.Now().Add(time.Hour)
+		to := from.Add(48 * time.Hour)
+		var saved model.ProductPrice
+		mockRepo := &MockProductRepository{
+			GetProductByIdFunc: product,
+			CreateProductPriceFunc: func(price model.ProductPrice, event model.AuditEvent) (int, error) {
+				saved = price
+				return 7, nil
+			},
+		}
+
+		usecase := NewProductUsecase(mockRepo, &MockPricingRepository{}, &MockVariantRepository{}, &MockImageRepository{}, nil)
+		entry, err := usecase.SchedulePrice(context.Background(), 1, model.PriceChange{Amount: "19.90", Kind: model.PriceKindSale, EffectiveFrom: from, EffectiveTo: &to})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 7, entry.ID)
+		assert.Equal(t, model.Money{Amount: 1990, Currency: "BRL"}, saved.Price)
+		assert.Equal(t, model.PriceStatusScheduled, entry.Status)
+	})
+
+	t.Run("Regular Price Defaults To Now", func(t *testing.T) {
+		mockRepo := &MockProductRepository{GetProductByIdFunc: product}
+
+		usecase := NewProductUsecase(mockRepo, &MockPricingRepository{}, &MockVariantRepository{}, &MockImageRepository{}, nil)
+		entry, err := usecase.SchedulePrice(context.Background(), 1, model.PriceChange{Amount: "24.90"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, model.PriceKindRegular, entry.Kind)
+		assert.WithinDuration(t, time.Now(), entry.EffectiveFrom, time.Second)
+		assert.Equal(t, model.PriceStatusActive, entry.Status)
+	})
+
+	t.Run("Sale Without End", func(t *testing.T) {
+		mockRepo := &MockProductRepository{GetProductByIdFunc: product}
+
+		usecase := NewProductUsecase(mockRepo, &MockPricingRepository{}, &MockVariantRepository{}, &MockImageRepository{}, nil)
+		_, err := usecase.SchedulePrice(context.Background(), 1, model.PriceChange{Amount: "19.90", Kind: model.PriceKindSale})
+
+		assert.ErrorIs(t, err, ErrInvalidPriceSchedule)
+	})
+
+	t.Run("Starts In The Past", func(t *testing.T) {
+		mockRepo := &MockProductRepository{GetProductByIdFunc: product}
+
+		usecase := NewProductUsecase(mockRepo, &MockPricingRepository{}, &MockVariantRepository{}, &MockImageRepository{}, nil)
+		_, err := usecase.SchedulePrice(context.Background(), 1, model.PriceChange{Amount: "19.90", EffectiveFrom: time.Now().AddDate(0, 0, -1)})
+
+		assert.ErrorIs(t, err, ErrPriceChangeInPast)
+	})
+}
+
+func TestProductUsecase_Trash(t *testing.T) {
+	deletedAt := time.Now()
+	deletedProduct := &model.Product{ID: 1, Name: "Camiseta", SKU: "CAM-01", Price: model.Money{Amount: 4990, Currency: "BRL"}, DeletedAt: &deletedAt}
+
+	t.Run("Delete Missing Product", func(t *testing.T) {
+		mockRepo := &MockProductRepository{
+			DeleteProductFunc: func(id int, event model.AuditEvent) error {
+				t.Fatal("missing products must not be deleted")
+				return nil
+			},
+		}
+
+		err := NewProductUsecase(mockRepo, &MockPricingRepository{}, &MockVariantRepository{}, &MockImageRepository{}, nil).DeleteProduct(context.Background(), 99)
+
+		assert.ErrorIs(t, err, ErrProductNotFound)
+	})
+
+	t.Run("Restore With The SKU Taken", func(t *testing.T) {
+		mockRepo := &MockProductRepository{
+			GetDeletedProductByIDFunc: func(id int) (*model.Product, error) {
+				return deletedProduct, nil
+			},
+			GetProductBySKUFunc: func(sku string) (*model.Product, error) {
+				return &model.Product{ID: 2, SKU: sku}, nil
+			},
+			RestoreProductFunc: func(id int, event model.AuditEvent) error {
+				t.Fatal("the product must stay in the trash")
+				return nil
+			},
+		}
+
+		_, err := NewProductUsecase(mockRepo, &MockPricingRepository{}, &MockVariantRepository{}, &MockImageRepository{}, nil).RestoreProduct(context.Background(), 1)
+
+		assert.ErrorIs(t, err, ErrProductSKUTaken)
+	})
+
+	t.Run("Purge Removes The Image Files", func(t *testing.T) {
+		store := testStore(t)
+		keys := []string{"products/1/abc/original.png", "products/1/abc/small.jpg"}
+		for _, key := range keys {
+			require.NoError(t, store.Put(context.Background(), key, strings.NewReader("data"), 4, "image/png"))
+		}
+
+		purged := 0
+		mockRepo := &MockProductRepository{
+			GetDeletedProductByIDFunc: func(id int) (*model.Product, error) {
+				return deletedProduct, nil
+			},
+			PurgeProductFunc: func(id int, event model.AuditEvent) error {
+				purged = id
+				return nil
+			},
+		}
+		imageRepo := &MockImageRepository{
+			GetImagesFunc: func(productIDs []int) ([]model.ProductImage, error) {
+				return []model.ProductImage{{ID: 1, ProductID: 1, Key: keys[0], Thumbnails: []model.ImageThumbnail{{Name: "small", Key: keys[1]}}}}, nil
+			},
+		}
+
+		err := NewProductUsecase(mockRepo, &MockPricingRepository{}, &MockVariantRepository{}, imageRepo, store).PurgeProduct(context.Background(), 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, purged)
+		for _, key := range keys {
+			_, err := store.Open(context.Background(), key)
+			assert.ErrorIs(t, err, storage.ErrNotFound)
+		}
+	})
+
+	t.Run("Purge Outside The Trash", func(t *testing.T) {
+		err := NewProductUsecase(&MockProductRepository{}, &MockPricingRepository{}, &MockVariantRepository{}, &MockImageRepository{}, nil).PurgeProduct(context.Background(), 1)
+
+		assert.ErrorIs(t, err, ErrProductNotFound)
+	})
+}
@@ -0,0 +1,609 @@
+package usecase
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"go-api/model"
+	"go-api/repository"
+	"math/big"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	ErrPromotionNotFound    = errors.New("promotion not found")
+	ErrInvalidPromotion     = errors.New("invalid promotion")
+	ErrPromotionCodeTaken   = errors.New("promotion code already exists")
+	ErrCodeRejected         = errors.New("discount code rejected")
+	ErrPromotionUnavailable = errors.New("promotion is no longer available")
+)
+
+// Reasons given for rejecting a discount code
+const (
+	rejectUnknown      = "unknown code"
+	rejectRepeated     = "the code was given more than once"
+	rejectInactive     = "the code is not active"
+	rejectNotStarted   = "the code is not valid yet"
+	rejectExpired      = "the code has expired"
+	rejectUsageLimit   = "the code has reached its usage limit"
+	rejectSignIn       = "sign in to use this code"
+	rejectPerUserLimit = "you have already used this code as many times as allowed"
+	rejectNoEligible   = "no item is eligible for the code"
+	rejectNoDiscount   = "the code gives no further discount"
+	rejectDisabled     = "discount codes are not accepted"
+)
+
+var promotionCodePattern = regexp.MustCompile(`^[A-Z0-9_-]{1,32}$`)
+
+// PromotionUsecase defines the contract for discount codes, automatic
+// promotions and the pricing pipeline that applies them
+type PromotionUsecase interface {
+	CreatePromotion(input model.PromotionInput) (*model.Promotion, error)
+	GetPromotions() ([]model.Promotion, error)
+	GetPromotion(id int) (*model.Promotion, error)
+	SetPromotionActive(id int, active bool) (*model.Promotion, error)
+	PriceLines(userID *int, lines []model.PricingLine, codes []string) (*model.PricingResult, error)
+}
+
+type promotionUsecaseImpl struct {
+	repository         repository.PromotionRepositoryInterface
+	productRepository  repository.ProductRepositoryInterface
+	categoryRepository repository.CategoryRepositoryInterface
+}
+
+// NewPromotionUsecase creates a new instance of PromotionUsecase
+func NewPromotionUsecase(repo repository.PromotionRepositoryInterface, productRepo repository.ProductRepositoryInterface, categoryRepo repository.CategoryRepositoryInterface) PromotionUsecase {
+	return &promotionUsecaseImpl{
+		repository:         repo,
+		productRepository:  productRepo,
+		categoryRepository: categoryRepo,
+	}
+}
+
+// CreatePromotion validates and saves a discount code or, without a code, an
+// automatic promotion. Promotions start active
+func (pu *promotionUsecaseImpl) CreatePromotion(input model.PromotionInput) (*model.Promotion, error) {
+	promotion, err := newPromotion(input)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, productID := range promotion.ProductIDs {
+		product, err := pu.productRepository.GetProductById(productID)
+		if err != nil {
+			return nil, err
+		}
+		if product == nil {
+			return nil, fmt.Errorf("%w: %d", ErrProductNotFound, productID)
+		}
+	}
+	for _, categoryID := range promotion.CategoryIDs {
+		category, err := pu.categoryRepository.GetCategoryByID(categoryID)
+		if err != nil {
+			return nil, err
+		}
+		if category == nil {
+			return nil, fmt.Errorf("%w: %d", ErrCategoryNotFound, categoryID)
+		}
+	}
+
+	if promotion.Code != "" {
+		existing, err := pu.repository.GetPromotionByCode(promotion.Code)
+		if err != nil {
+			return nil, err
+		}
+		if existing != nil {
+			return nil, ErrPromotionCodeTaken
+		}
+	}
+
+	id, err := pu.repository.CreatePromotion(promotion)
+	if err != nil {
+		return nil, err
+	}
+	return pu.repository.GetPromotionByID(id)
+}
+
+func (pu *promotionUsecaseImpl) GetPromotions() ([]model.Promotion, error) {
+	return pu.repository.GetPromotions()
+}
+
+func (pu *promotionUsecaseImpl) GetPromotion(id int) (*model.Promotion, error) {
+	promotion, err := pu.repository.GetPromotionByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if promotion == nil {
+		return nil, ErrPromotionNotFound
+	}
+	return promotion, nil
+}
+
+// SetPromotionActive turns a promotion on or off; orders already placed keep
+// their discount
+func (pu *promotionUsecaseImpl) SetPromotionActive(id int, active bool) (*model.Promotion, error) {
+	if err := pu.repository.SetPromotionActive(id, active); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrPromotionNotFound
+		}
+		return nil, err
+	}
+	return pu.GetPromotion(id)
+}
+
+// PriceLines runs the pricing pipeline: the automatic promotions and the
+// discount codes that qualify are combined following their stacking rules,
+// keeping the combination with the largest discount, and the discount of each
+// one is broken down per line. Codes that cannot be applied are listed in
+// Rejected with the reason; automatic promotions that do not qualify are left out
+func (pu *promotionUsecaseImpl) PriceLines(userID *int, lines []model.PricingLine, codes []string) (*model.PricingResult, error) {
+	result, err := newPricingResult(lines)
+	if err != nil || len(lines) == 0 {
+		return result, err
+	}
+
+	promotions, err := pu.repository.GetAutomaticPromotions()
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool)
+	for _, code := range codes {
+		code = strings.ToUpper(strings.TrimSpace(code))
+		if code == "" {
+			continue
+		}
+		if seen[code] {
+			result.Rejected = append(result.Rejected, model.RejectedCode{Code: code, Reason: rejectRepeated})
+			continue
+		}
+		seen[code] = true
+
+		var promotion *model.Promotion
+		if promotionCodePattern.MatchString(code) {
+			if promotion, err = pu.repository.GetPromotionByCode(code); err != nil {
+				return nil, err
+			}
+		}
+		if promotion == nil {
+			result.Rejected = append(result.Rejected, model.RejectedCode{Code: code, Reason: rejectUnknown})
+			continue
+		}
+		promotions = append(promotions, *promotion)
+	}
+	if len(promotions) == 0 {
+		return result, nil
+	}
+
+	categories, err := pu.lineCategories(lines, promotions)
+	if err != nil {
+		return nil, err
+	}
+	redemptions, err := pu.userRedemptions(userID, promotions)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	var candidates []model.Promotion
+	for _, promotion := range promotions {
+		reason := promotionRejection(promotion, result, categories, userID, redemptions, now)
+		if reason == "" {
+			candidates = append(candidates, promotion)
+		} else if promotion.Code != "" {
+			result.Rejected = append(result.Rejected, model.RejectedCode{Code: promotion.Code, Reason: reason})
+		}
+	}
+
+	if err := applyBestPromotions(result, candidates, categories); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// --- Helper Functions ---
+
+// newPromotion normalizes and validates the input of a new promotion
+func newPromotion(input model.PromotionInput) (model.Promotion, error) {
+	promotion := model.Promotion{
+		Code:         strings.ToUpper(strings.TrimSpace(input.Code)),
+		Name:         strings.TrimSpace(input.Name),
+		Kind:         input.Kind,
+		Percent:      input.Percent,
+		ProductIDs:   uniqueIDs(input.ProductIDs),
+		CategoryIDs:  uniqueIDs(input.CategoryIDs),
+		UsageLimit:   input.UsageLimit,
+		PerUserLimit: input.PerUserLimit,
+		StartsAt:     input.StartsAt,
+		EndsAt:       input.EndsAt,
+		Stackable:    input.Stackable,
+		Active:       true,
+	}
+
+	if promotion.Code != "" && !promotionCodePattern.MatchString(promotion.Code) {
+		return model.Promotion{}, fmt.Errorf("%w: codes have up to 32 letters, digits, - or _", ErrInvalidPromotion)
+	}
+	if promotion.Name == "" {
+		return model.Promotion{}, fmt.Errorf("%w: name is required", ErrInvalidPromotion)
+	}
+
+	currency := strings.ToUpper(strings.TrimSpace(input.Currency))
+	if (input.Amount != "" || input.MinTotal != "") && currency == "" {
+		return model.Promotion{}, fmt.Errorf("%w: currency is required with amount or min_total", ErrInvalidPromotion)
+	}
+	if currency != "" {
+		if _, err := model.CurrencyExponent(currency); err != nil {
+			return model.Promotion{}, err
+		}
+	}
+
+	switch promotion.Kind {
+	case model.DiscountPercentage:
+		if promotion.Percent < 1 || promotion.Percent > 100 {
+			return model.Promotion{}, fmt.Errorf("%w: percent must be between 1 and 100", ErrInvalidPromotion)
+		}
+		if input.Amount != "" {
+			return model.Promotion{}, fmt.Errorf("%w: percentage promotions take no amount", ErrInvalidPromotion)
+		}
+	case model.DiscountFixed:
+		if promotion.Percent != 0 {
+			return model.Promotion{}, fmt.Errorf("%w: fixed promotions take no percent", ErrInvalidPromotion)
+		}
+		if input.Amount == "" {
+			return model.Promotion{}, fmt.Errorf("%w: amount is required", ErrInvalidPromotion)
+		}
+		amount, err := model.ParseMoney(input.Amount, currency)
+		if err != nil {
+			return model.Promotion{}, err
+		}
+		if amount.IsNegative() || amount.IsZero() {
+			return model.Promotion{}, fmt.Errorf("%w: amount must be positive", ErrInvalidPromotion)
+		}
+		promotion.Amount = &amount
+	default:
+		return model.Promotion{}, fmt.Errorf("%w: kind must be %s or %s", ErrInvalidPromotion, model.DiscountPercentage, model.DiscountFixed)
+	}
+
+	if input.MinTotal != "" {
+		minTotal, err := model.ParseMoney(input.MinTotal, currency)
+		if err != nil {
+			return model.Promotion{}, err
+		}
+		if minTotal.IsNegative() || minTotal.IsZero() {
+			return model.Promotion{}, fmt.Errorf("%w: min_total must be positive", ErrInvalidPromotion)
+		}
+		promotion.MinTotal = &minTotal
+	}
+	if promotion.UsageLimit != nil && *promotion.UsageLimit < 1 {
+		return model.Promotion{}, fmt.Errorf("%w: usage_limit must be at least 1", ErrInvalidPromotion)
+	}
+	if promotion.PerUserLimit != nil && *promotion.PerUserLimit < 1 {
+		return model.Promotion{}, fmt.Errorf("%w: per_user_limit must be at least 1", ErrInvalidPromotion)
+	}
+	if promotion.StartsAt != nil && promotion.EndsAt != nil && !promotion.EndsAt.After(*promotion.StartsAt) {
+		return model.Promotion{}, fmt.Errorf("%w: ends_at must be after starts_at", ErrInvalidPromotion)
+	}
+	return promotion, nil
+}
+
+// newPricingResult prices the lines without any discount; every line must
+// share the same currency
+func newPricingResult(lines []model.PricingLine) (*model.PricingResult, error) {
+	result := &model.PricingResult{
+		Lines:    make([]model.PricedLine, 0, len(lines)),
+		Applied:  []model.AppliedPromotion{},
+		Rejected: []model.RejectedCode{},
+	}
+	if len(lines) == 0 {
+		return result, nil
+	}
+
+	currency := lines[0].UnitPrice.Currency
+	result.Subtotal = model.Money{Currency: currency}
+	for _, line := range lines {
+		if line.UnitPrice.Currency != currency {
+			return nil, ErrMixedCurrencies
+		}
+		subtotal, err := line.UnitPrice.Mul(int64(line.Quantity))
+		if err != nil {
+			return nil, err
+		}
+		if result.Subtotal, err = result.Subtotal.Add(subtotal); err != nil {
+			return nil, err
+		}
+		result.Lines = append(result.Lines, model.PricedLine{
+			ProductID:   line.ProductID,
+			VariantID:   line.VariantID,
+			Quantity:    line.Quantity,
+			Subtotal:    subtotal,
+			Discount:    model.Money{Currency: currency},
+			Total:       subtotal,
+			Adjustments: []model.Adjustment{},
+		})
+	}
+	result.Discount = model.Money{Currency: currency}
+	result.Total = result.Subtotal
+	return result, nil
+}
+
+// rejectAllCodes rejects every code given, for when there is no promotion engine
+func rejectAllCodes(result *model.PricingResult, codes []string) {
+	for _, code := range codes {
+		if code = strings.ToUpper(strings.TrimSpace(code)); code != "" {
+			result.Rejected = append(result.Rejected, model.RejectedCode{Code: code, Reason: rejectDisabled})
+		}
+	}
+}
+
+// lineCategories loads the categories of the products in the lines, only
+// when a promotion is restricted to categories
+func (pu *promotionUsecaseImpl) lineCategories(lines []model.PricingLine, promotions []model.Promotion) (map[int][]int, error) {
+	for _, promotion := range promotions {
+		if len(promotion.CategoryIDs) == 0 {
+			continue
+		}
+		productIDs := make([]int, 0, len(lines))
+		for _, line := range lines {
+			productIDs = append(productIDs, line.ProductID)
+		}
+		return pu.repository.GetProductCategoryIDs(uniqueIDs(productIDs))
+	}
+	return nil, nil
+}
+
+// userRedemptions counts the redemptions of the user for the promotions with
+// a per user limit
+func (pu *promotionUsecaseImpl) userRedemptions(userID *int, promotions []model.Promotion) (map[int]int, error) {
+	if userID == nil {
+		return nil, nil
+	}
+	var ids []int
+	for _, promotion := range promotions {
+		if promotion.PerUserLimit != nil {
+			ids = append(ids, promotion.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	return pu.repository.CountUserRedemptions(*userID, ids)
+}
+
+// promotionRejection returns why the promotion does not apply to the lines,
+// or an empty string when it does
+func promotionRejection(promotion model.Promotion, result *model.PricingResult, categories map[int][]int, userID *int, redemptions map[int]int, now time.Time) string {
+	switch {
+	case !promotion.Active:
+		return rejectInactive
+	case promotion.StartsAt != nil && now.Before(*promotion.StartsAt):
+		return rejectNotStarted
+	case promotion.EndsAt != nil && !now.Before(*promotion.EndsAt):
+		return rejectExpired
+	case promotion.UsageLimit != nil && promotion.Uses >= *promotion.UsageLimit:
+		return rejectUsageLimit
+	case promotion.PerUserLimit != nil && userID == nil:
+		return rejectSignIn
+	case promotion.PerUserLimit != nil && redemptions[promotion.ID] >= *promotion.PerUserLimit:
+		return rejectPerUserLimit
+	}
+
+	if currency := promotion.Currency(); currency != "" && currency != result.Subtotal.Currency {
+		return fmt.Sprintf("the code only applies to prices in %s", currency)
+	}
+	if promotion.MinTotal != nil && result.Subtotal.Amount < promotion.MinTotal.Amount {
+		return fmt.Sprintf("the order must total at least %s %s", promotion.MinTotal.String(), promotion.MinTotal.Currency)
+	}
+	if len(eligibleLines(promotion, result.Lines, categories)) == 0 {
+		return rejectNoEligible
+	}
+	return ""
+}
+
+// eligibleLines returns the indexes of the lines the promotion covers
+func eligibleLines(promotion model.Promotion, lines []model.PricedLine, categories map[int][]int) []int {
+	var eligible []int
+	for i, line := range lines {
+		if len(promotion.ProductIDs) == 0 && len(promotion.CategoryIDs) == 0 ||
+			containsID(promotion.ProductIDs, line.ProductID) ||
+			sharesID(promotion.CategoryIDs, categories[line.ProductID]) {
+			eligible = append(eligible, i)
+		}
+	}
+	return eligible
+}
+
+// applyBestPromotions picks, among the stackable promotions together and each
+// other promotion alone, the combination with the largest discount (the
+// earliest one on ties) and applies it. Codes left out are rejected
+func applyBestPromotions(result *model.PricingResult, candidates []model.Promotion, categories map[int][]int) error {
+	var options [][]model.Promotion
+	var stackable []model.Promotion
+	for _, promotion := range candidates {
+		if promotion.Stackable {
+			stackable = append(stackable, promotion)
+		}
+	}
+	if len(stackable) > 0 {
+		options = append(options, stackable)
+	}
+	for _, promotion := range candidates {
+		if !promotion.Stackable {
+			options = append(options, []model.Promotion{promotion})
+		}
+	}
+	if len(options) == 0 {
+		return nil
+	}
+
+	var best []model.Promotion
+	var bestDiscounts [][]int64
+	bestTotal := int64(-1)
+	for _, option := range options {
+		discounts, total, err := promotionDiscounts(option, result.Lines, categories)
+		if err != nil {
+			return err
+		}
+		if total > bestTotal {
+			best, bestDiscounts, bestTotal = option, discounts, total
+		}
+	}
+
+	chosen := make(map[int]bool)
+	var names []string
+	for i, promotion := range best {
+		var discount int64
+		for line, amount := range bestDiscounts[i] {
+			if amount == 0 {
+				continue
+			}
+			discount += amount
+			priced := &result.Lines[line]
+			priced.Discount.Amount += amount
+			priced.Total.Amount -= amount
+			priced.Adjustments = append(priced.Adjustments, model.Adjustment{
+				PromotionID: promotion.ID,
+				Code:        promotion.Code,
+				Name:        promotion.Name,
+				Amount:      model.Money{Amount: amount, Currency: result.Subtotal.Currency},
+			})
+		}
+		if discount == 0 {
+			if promotion.Code != "" {
+				result.Rejected = append(result.Rejected, model.RejectedCode{Code: promotion.Code, Reason: rejectNoDiscount})
+			}
+			continue
+		}
+		chosen[promotion.ID] = true
+		names = append(names, promotion.Name)
+		result.Applied = append(result.Applied, model.AppliedPromotion{
+			PromotionID: promotion.ID,
+			Code:        promotion.Code,
+			Name:        promotion.Name,
+			Discount:    model.Money{Amount: discount, Currency: result.Subtotal.Currency},
+		})
+		result.Discount.Amount += discount
+	}
+	result.Total.Amount -= result.Discount.Amount
+
+	reason := rejectNoDiscount
+	if len(names) > 0 {
+		reason = "the code cannot be combined with " + strings.Join(names, ", ")
+	}
+	for _, promotion := range candidates {
+		if promotion.Code != "" && !chosen[promotion.ID] && !inPromotions(best, promotion.ID) {
+			result.Rejected = append(result.Rejected, model.RejectedCode{Code: promotion.Code, Reason: reason})
+		}
+	}
+	return nil
+}
+
+// promotionDiscounts applies the promotions one after the other, each on
+// what the previous ones left of every line, and returns the discount of each
+// promotion per line with their sum
+func promotionDiscounts(promotions []model.Promotion, lines []model.PricedLine, categories map[int][]int) ([][]int64, int64, error) {
+	remaining := make([]int64, len(lines))
+	for i, line := range lines {
+		remaining[i] = line.Subtotal.Amount
+	}
+
+	discounts := make([][]int64, len(promotions))
+	var total int64
+	for p, promotion := range promotions {
+		discounts[p] = make([]int64, len(lines))
+		eligible := eligibleLines(promotion, lines, categories)
+		switch promotion.Kind {
+		case model.DiscountPercentage:
+			factor := big.NewRat(int64(promotion.Percent), 100)
+			for _, i := range eligible {
+				amount, err := model.Money{Amount: remaining[i], Currency: lines[i].Subtotal.Currency}.MulRat(factor, model.RoundHalfUp)
+				if err != nil {
+					return nil, 0, err
+				}
+				discounts[p][i] = minInt64(amount.Amount, remaining[i])
+			}
+		case model.DiscountFixed:
+			spreadAmount(promotion.Amount.Amount, eligible, remaining, discounts[p])
+		}
+		for i, amount := range discounts[p] {
+			remaining[i] -= amount
+			total += amount
+		}
+	}
+	return discounts, total, nil
+}
+
+// spreadAmount splits a fixed discount over the eligible lines in proportion
+// to what is left of each, never beyond it; the minor units lost to rounding
+// go to the first lines that can still take them
+func spreadAmount(amount int64, eligible []int, remaining, discounts []int64) {
+	var base int64
+	for _, i := range eligible {
+		base += remaining[i]
+	}
+	amount = minInt64(amount, base)
+	if amount <= 0 {
+		return
+	}
+
+	spread := int64(0)
+	for _, i := range eligible {
+		share := new(big.Int).Mul(big.NewInt(amount), big.NewInt(remaining[i]))
+		share.Quo(share, big.NewInt(base))
+		discounts[i] = share.Int64()
+		spread += discounts[i]
+	}
+	for _, i := range eligible {
+		if spread == amount {
+			break
+		}
+		if discounts[i] < remaining[i] {
+			discounts[i]++
+			spread++
+		}
+	}
+}
+
+func uniqueIDs(ids []int) []int {
+	unique := []int{}
+	for _, id := range ids {
+		if !containsID(unique, id) {
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
+
+func containsID(ids []int, id int) bool {
+	for _, candidate := range ids {
+		if candidate == id {
+			return true
+		}
+	}
+	return false
+}
+
+func sharesID(a, b []int) bool {
+	for _, id := range a {
+		if containsID(b, id) {
+			return true
+		}
+	}
+	return false
+}
+
+func inPromotions(promotions []model.Promotion, id int) bool {
+	for _, promotion := range promotions {
+		if promotion.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+func minInt64(a, b int64) int64 {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,309 @@
+package usecase
+
+import (
+	"errors"
+	"go-api/model"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// promotionLines are two Camisetas at 49.90 and a Caneca at 20.00, 119.80 in all
+func promotionLines() []model.PricingLine {
+	return []model.PricingLine{
+		{ProductID: 1, VariantID: intPtr(2), Quantity: 2, UnitPrice: model.Money{Amount: 4990, Currency: "BRL"}},
+		{ProductID: 2, Quantity: 1, UnitPrice: model.Money{Amount: 2000, Currency: "BRL"}},
+	}
+}
+
+func brl(amount int64) *model.Money {
+	return &model.Money{Amount: amount, Currency: "BRL"}
+}
+
+// promotionCodes serves the given promotions by code
+func promotionCodes(promotions ...model.Promotion) func(code string) (*model.Promotion, error) {
+	return func(code string) (*model.Promotion, error) {
+		for _, promotion := range promotions {
+			if promotion.Code == code {
+				return &promotion, nil
+			}
+		}
+		return nil, nil
+	}
+}
+
+func TestPromotionUsecase_PriceLines(t *testing.T) {
+	userID := 7
+	welcome := model.Promotion{ID: 3, Code: "BEMVINDO10", Name: "Boas-vindas", Kind: model.DiscountPercentage, Percent: 10, Active: true}
+
+	t.Run("Percentage Code With Breakdown", func(t *testing.T) {
+		mockRepo := &MockPromotionRepository{GetPromotionByCodeFunc: promotionCodes(welcome)}
+
+		usecase := NewPromotionUsecase(mockRepo, &MockProductRepository{}, &MockCategoryRepository{})
+		result, err := usecase.PriceLines(&userID, promotionLines(), []string{" bemvindo10 "})
+
+		assert.NoError(t, err)
+		assert.Empty(t, result.Rejected)
+		assert.Equal(t, *brl(11980), result.Subtotal)
+		assert.Equal(t, *brl(1198), result.Discount)
+		assert.Equal(t, *brl(10782), result.Total)
+		assert.Equal(t, []model.AppliedPromotion{{PromotionID: 3, Code: "BEMVINDO10", Name: "Boas-vindas", Discount: *brl(1198)}}, result.Applied)
+		assert.Equal(t, *brl(998), result.Lines[0].Discount)
+		assert.Equal(t, *brl(8982), result.Lines[0].Total)
+		assert.Equal(t, []model.Adjustment{{PromotionID: 3, Code: "BEMVINDO10", Name: "Boas-vindas", Amount: *brl(200)}}, result.Lines[1].Adjustments)
+	})
+
+	t.Run("Fixed Amount Spread Over The Items", func(t *testing.T) {
+		fixed := model.Promotion{ID: 4, Code: "DEZ", Name: "Dez reais", Kind: model.DiscountFixed, Amount: brl(1000), Active: true}
+		mockRepo := &MockPromotionRepository{GetPromotionByCodeFunc: promotionCodes(fixed)}
+
+		usecase := NewPromotionUsecase(mockRepo, &MockProductRepository{}, &MockCategoryRepository{})
+		result, err := usecase.PriceLines(&userID, promotionLines(), []string{"DEZ"})
+
+		// 10.00 * 99.80 / 119.80 = 8.33 and 10.00 * 20.00 / 119.80 = 1.66, the cent left goes to the first item
+		assert.NoError(t, err)
+		assert.Equal(t, *brl(834), result.Lines[0].Discount)
+		assert.Equal(t, *brl(166), result.Lines[1].Discount)
+		assert.Equal(t, *brl(1000), result.Discount)
+	})
+
+	t.Run("Fixed Amount Never Exceeds The Eligible Items", func(t *testing.T) {
+		fixed := model.Promotion{ID: 4, Code: "CANECA", Name: "Caneca grátis", Kind: model.DiscountFixed, Amount: brl(5000), ProductIDs: []int{2}, Active: true}
+		mockRepo := &MockPromotionRepository{GetPromotionByCodeFunc: promotionCodes(fixed)}
+
+		usecase := NewPromotionUsecase(mockRepo, &MockProductRepository{}, &MockCategoryRepository{})
+		result, err := usecase.PriceLines(&userID, promotionLines(), []string{"CANECA"})
+
+		assert.NoError(t, err)
+		assert.True(t, result.Lines[0].Discount.IsZero())
+		assert.Empty(t, result.Lines[0].Adjustments)
+		assert.Equal(t, *brl(2000), result.Lines[1].Discount)
+		assert.Equal(t, *brl(9980), result.Total)
+	})
+
+	t.Run("Category Covers Its Subcategories", func(t *testing.T) {
+		kitchen := model.Promotion{ID: 5, Name: "Semana da cozinha", Kind: model.DiscountPercentage, Percent: 50, CategoryIDs: []int{8}, Active: true}
+		mockRepo := &MockPromotionRepository{
+			GetAutomaticPromotionsFunc: func() ([]model.Promotion, error) { return []model.Promotion{kitchen}, nil },
+			GetProductCategoryIDsFunc: func(productIDs []int) (map[int][]int, error) {
+				assert.Equal(t, []int{1, 2}, productIDs)
+				return map[int][]int{1: {3}, 2: {8, 9}}, nil
+			},
+		}
+
+		usecase := NewPromotionUsecase(mockRepo, &MockProductRepository{}, &MockCategoryRepository{})
+		result, err := usecase.PriceLines(nil, promotionLines(), nil)
+
+		assert.NoError(t, err)
+		assert.True(t, result.Lines[0].Discount.IsZero())
+		assert.Equal(t, *brl(1000), result.Lines[1].Discount)
+		assert.Equal(t, "Semana da cozinha", result.Applied[0].Name)
+	})
+
+	t.Run("Automatic Promotion That Does Not Qualify Is Left Out", func(t *testing.T) {
+		big := model.Promotion{ID: 6, Name: "Frete", Kind: model.DiscountFixed, Amount: brl(1500), MinTotal: brl(20000), Active: true}
+		mockRepo := &MockPromotionRepository{
+			GetAutomaticPromotionsFunc: func() ([]model.Promotion, error) { return []model.Promotion{big}, nil },
+		}
+
+		usecase := NewPromotionUsecase(mockRepo, &MockProductRepository{}, &MockCategoryRepository{})
+		result, err := usecase.PriceLines(nil, promotionLines(), nil)
+
+		assert.NoError(t, err)
+		assert.Empty(t, result.Applied)
+		assert.Empty(t, result.Rejected)
+		assert.Equal(t, *brl(11980), result.Total)
+	})
+
+	t.Run("Rejected Codes Say Why", func(t *testing.T) {
+		past := time.Now().Add(-time.Hour)
+		future := time.Now().Add(time.Hour)
+		once := 1
+		promotions := []model.Promotion{
+			{ID: 10, Code: "OFF", Name: "Off", Kind: model.DiscountPercentage, Percent: 10},
+			{ID: 11, Code: "SOON", Name: "Soon", Kind: model.DiscountPercentage, Percent: 10, StartsAt: &future, Active: true},
+			{ID: 12, Code: "OLD", Name: "Old", Kind: model.DiscountPercentage, Percent: 10, EndsAt: &past, Active: true},
+			{ID: 13, Code: "GONE", Name: "Gone", Kind: model.DiscountPercentage, Percent: 10, UsageLimit: &once, Uses: 1, Active: true},
+			{ID: 14, Code: "ONCE", Name: "Once", Kind: model.DiscountPercentage, Percent: 10, PerUserLimit: &once, Active: true},
+			{ID: 15, Code: "USD", Name: "Dollar", Kind: model.DiscountFixed, Amount: &model.Money{Amount: 500, Currency: "USD"}, Active: true},
+			{ID: 16, Code: "BIG", Name: "Big", Kind: model.DiscountPercentage, Percent: 10, MinTotal: brl(20000), Active: true},
+			{ID: 17, Code: "OTHER", Name: "Other", Kind: model.DiscountPercentage, Percent: 10, ProductIDs: []int{99}, Active: true},
+		}
+		mockRepo := &MockPromotionRepository{
+			GetPromotionByCodeFunc: promotionCodes(promotions...),
+			CountUserRedemptionsFunc: func(userID int, promotionIDs []int) (map[int]int, error) {
+				assert.Equal(t, []int{14}, promotionIDs)
+				return map[int]int{14: 1}, nil
+			},
+		}
+
+		usecase := NewPromotionUsecase(mockRepo, &MockProductRepository{}, &MockCategoryRepository{})
+		result, err := usecase.PriceLines(&userID, promotionLines(),
+			[]string{"NOPE", "OFF", "SOON", "OLD", "GONE", "ONCE", "USD", "BIG", "OTHER", "off", "not a code!"})
+
+		assert.NoError(t, err)
+		assert.Empty(t, result.Applied)
+		assert.Equal(t, []model.RejectedCode{
+			{Code: "NOPE", Reason: "unknown code"},
+			{Code: "OFF", Reason: "the code was given more than once"},
+			{Code: "NOT A CODE!", Reason: "unknown code"},
+			{Code: "OFF", Reason: "the code is not active"},
+			{Code: "SOON", Reason: "the code is not valid yet"},
+			{Code: "OLD", Reason: "the code has expired"},
+			{Code: "GONE", Reason: "the code has reached its usage limit"},
+			{Code: "ONCE", Reason: "you have already used this code as many times as allowed"},
+			{Code: "USD", Reason: "the code only applies to prices in USD"},
+			{Code: "BIG", Reason: "the order must total at least 200.00 BRL"},
+			{Code: "OTHER", Reason: "no item is eligible for the code"},
+		}, result.Rejected)
+	})
+
+	t.Run("Per User Code Needs Sign In", func(t *testing.T) {
+		once := 1
+		mockRepo := &MockPromotionRepository{GetPromotionByCodeFunc: promotionCodes(
+			model.Promotion{ID: 14, Code: "ONCE", Name: "Once", Kind: model.DiscountPercentage, Percent: 10, PerUserLimit: &once, Active: true},
+		)}
+
+		usecase := NewPromotionUsecase(mockRepo, &MockProductRepository{}, &MockCategoryRepository{})
+		result, err := usecase.PriceLines(nil, promotionLines(), []string{"ONCE"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []model.RejectedCode{{Code: "ONCE", Reason: "sign in to use this code"}}, result.Rejected)
+	})
+
+	t.Run("Stackable Promotions Apply One After The Other", func(t *testing.T) {
+		automatic := model.Promotion{ID: 1, Name: "Outono", Kind: model.DiscountPercentage, Percent: 50, Stackable: true, Active: true}
+		code := model.Promotion{ID: 2, Code: "MAIS10", Name: "Mais 10", Kind: model.DiscountPercentage, Percent: 10, Stackable: true, Active: true}
+		mockRepo := &MockPromotionRepository{
+			GetAutomaticPromotionsFunc: func() ([]model.Promotion, error) { return []model.Promotion{automatic}, nil },
+			GetPromotionByCodeFunc:     promotionCodes(code),
+		}
+
+		usecase := NewPromotionUsecase(mockRepo, &MockProductRepository{}, &MockCategoryRepository{})
+		result, err := usecase.PriceLines(&userID, promotionLines(), []string{"MAIS10"})
+
+		// 50% of 119.80 = 59.90, then 10% of what is left: 4.99 + 1.00
+		assert.NoError(t, err)
+		assert.Len(t, result.Applied, 2)
+		assert.Equal(t, *brl(5990), result.Applied[0].Discount)
+		assert.Equal(t, *brl(599), result.Applied[1].Discount)
+		assert.Equal(t, *brl(5391), result.Total)
+		assert.Len(t, result.Lines[0].Adjustments, 2)
+	})
+
+	t.Run("Best Of Non Stackable Promotions Wins", func(t *testing.T) {
+		automatic := model.Promotion{ID: 1, Name: "Outono", Kind: model.DiscountPercentage, Percent: 5, Stackable: true, Active: true}
+		mockRepo := &MockPromotionRepository{
+			GetAutomaticPromotionsFunc: func() ([]model.Promotion, error) { return []model.Promotion{automatic}, nil },
+			GetPromotionByCodeFunc:     promotionCodes(welcome),
+		}
+
+		usecase := NewPromotionUsecase(mockRepo, &MockProductRepository{}, &MockCategoryRepository{})
+		result, err := usecase.PriceLines(&userID, promotionLines(), []string{"BEMVINDO10"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []model.AppliedPromotion{{PromotionID: 3, Code: "BEMVINDO10", Name: "Boas-vindas", Discount: *brl(1198)}}, result.Applied)
+		assert.Empty(t, result.Rejected)
+	})
+
+	t.Run("Code Worse Than The Automatic Promotions Is Rejected", func(t *testing.T) {
+		automatic := model.Promotion{ID: 1, Name: "Outono", Kind: model.DiscountPercentage, Percent: 20, Stackable: true, Active: true}
+		mockRepo := &MockPromotionRepository{
+			GetAutomaticPromotionsFunc: func() ([]model.Promotion, error) { return []model.Promotion{automatic}, nil },
+			GetPromotionByCodeFunc:     promotionCodes(welcome),
+		}
+
+		usecase := NewPromotionUsecase(mockRepo, &MockProductRepository{}, &MockCategoryRepository{})
+		result, err := usecase.PriceLines(&userID, promotionLines(), []string{"BEMVINDO10"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Outono", result.Applied[0].Name)
+		assert.Equal(t, []model.RejectedCode{{Code: "BEMVINDO10", Reason: "the code cannot be combined with Outono"}}, result.Rejected)
+	})
+
+	t.Run("Mixed Currencies", func(t *testing.T) {
+		lines := append(promotionLines(), model.PricingLine{ProductID: 3, Quantity: 1, UnitPrice: model.Money{Amount: 100, Currency: "USD"}})
+
+		usecase := NewPromotionUsecase(&MockPromotionRepository{}, &MockProductRepository{}, &MockCategoryRepository{})
+		_, err := usecase.PriceLines(&userID, lines, nil)
+
+		assert.Equal(t, ErrMixedCurrencies, err)
+	})
+}
+
+func TestPromotionUsecase_CreatePromotion(t *testing.T) {
+	t.Run("Normalizes The Code", func(t *testing.T) {
+		var saved model.Promotion
+		mockRepo := &MockPromotionRepository{
+			CreatePromotionFunc: func(promotion model.Promotion) (int, error) {
+				saved = promotion
+				return 4, nil
+			},
+			GetPromotionByIDFunc: func(id int) (*model.Promotion, error) {
+				saved.ID = id
+				return &saved, nil
+			},
+		}
+		mockProducts := &MockProductRepository{GetProductByIdFunc: variantProduct}
+
+		usecase := NewPromotionUsecase(mockRepo, mockProducts, &MockCategoryRepository{})
+		promotion, err := usecase.CreatePromotion(model.PromotionInput{
+			Code: " verao20 ", Name: "Verão", Kind: model.DiscountFixed, Amount: "20", MinTotal: "100.00", Currency: "brl",
+			ProductIDs: []int{1, 1},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 4, promotion.ID)
+		assert.Equal(t, "VERAO20", saved.Code)
+		assert.Equal(t, brl(2000), saved.Amount)
+		assert.Equal(t, brl(10000), saved.MinTotal)
+		assert.Equal(t, []int{1}, saved.ProductIDs)
+		assert.True(t, saved.Active)
+	})
+
+	t.Run("Code Taken", func(t *testing.T) {
+		mockRepo := &MockPromotionRepository{
+			GetPromotionByCodeFunc: func(code string) (*model.Promotion, error) { return &model.Promotion{ID: 1, Code: code}, nil },
+		}
+
+		usecase := NewPromotionUsecase(mockRepo, &MockProductRepository{}, &MockCategoryRepository{})
+		_, err := usecase.CreatePromotion(model.PromotionInput{Code: "VERAO", Name: "Verão", Kind: model.DiscountPercentage, Percent: 10})
+
+		assert.Equal(t, ErrPromotionCodeTaken, err)
+	})
+
+	t.Run("Category Not Found", func(t *testing.T) {
+		usecase := NewPromotionUsecase(&MockPromotionRepository{}, &MockProductRepository{}, &MockCategoryRepository{})
+		_, err := usecase.CreatePromotion(model.PromotionInput{Name: "Cozinha", Kind: model.DiscountPercentage, Percent: 10, CategoryIDs: []int{8}})
+
+		assert.True(t, errors.Is(err, ErrCategoryNotFound))
+	})
+
+	past := time.Now().Add(-time.Hour)
+	now := time.Now()
+	zero := 0
+	invalid := []struct {
+		name  string
+		input model.PromotionInput
+	}{
+		{"Bad Code", model.PromotionInput{Code: "TEN %", Name: "Dez", Kind: model.DiscountPercentage, Percent: 10}},
+		{"No Name", model.PromotionInput{Kind: model.DiscountPercentage, Percent: 10}},
+		{"Unknown Kind", model.PromotionInput{Name: "Dez", Kind: "bogo"}},
+		{"Percent Above 100", model.PromotionInput{Name: "Dez", Kind: model.DiscountPercentage, Percent: 101}},
+		{"Percentage With Amount", model.PromotionInput{Name: "Dez", Kind: model.DiscountPercentage, Percent: 10, Amount: "5", Currency: "BRL"}},
+		{"Fixed Without Amount", model.PromotionInput{Name: "Dez", Kind: model.DiscountFixed}},
+		{"Amount Without Currency", model.PromotionInput{Name: "Dez", Kind: model.DiscountFixed, Amount: "10"}},
+		{"Zero Amount", model.PromotionInput{Name: "Dez", Kind: model.DiscountFixed, Amount: "0", Currency: "BRL"}},
+		{"Zero Usage Limit", model.PromotionInput{Name: "Dez", Kind: model.DiscountPercentage, Percent: 10, UsageLimit: &zero}},
+		{"Ends Before Start", model.PromotionInput{Name: "Dez", Kind: model.DiscountPercentage, Percent: 10, StartsAt: &now, EndsAt: &past}},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			usecase := NewPromotionUsecase(&MockPromotionRepository{}, &MockProductRepository{}, &MockCategoryRepository{})
+			_, err := usecase.CreatePromotion(tt.input)
+
+			assert.True(t, errors.Is(err, ErrInvalidPromotion), "got %v", err)
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"go-api/internal/tax"
+	"go-api/model"
+	"go-api/repository"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidTaxDestination = errors.New("destination country must be an ISO 3166-1 alpha-2 code")
+	ErrTaxRegionNotFound     = errors.New("no tax rates for the destination")
+	ErrInvalidTaxCategory    = errors.New("unknown tax category")
+)
+
+var countryCodePattern = regexp.MustCompile(`^[A-Z]{2}$`)
+
+// TaxUsecase defines the contract for the tax categories of the products, the
+// rate tables and the tax of carts and orders
+type TaxUsecase interface {
+	GetTaxCategories() []string
+	GetTaxRegion(destination model.TaxDestination, at time.Time) (*model.TaxRegion, error)
+	SetProductTaxCategory(ctx context.Context, productID int, category string) (*model.Product, error)
+	TaxLines(destination model.TaxDestination, lines []model.PricedLine) (*model.TaxBreakdown, error)
+}
+
+type taxUsecaseImpl struct {
+	table             *tax.Table
+	productRepository repository.ProductRepositoryInterface
+}
+
+// NewTaxUsecase creates a new instance of TaxUsecase with the rate table
+func NewTaxUsecase(table *tax.Table, productRepo repository.ProductRepositoryInterface) TaxUsecase {
+	return &taxUsecaseImpl{
+		table:             table,
+		productRepository: productRepo,
+	}
+}
+
+func (tu *taxUsecaseImpl) GetTaxCategories() []string {
+	return tu.table.Categories
+}
+
+// GetTaxRegion returns the rates in effect at the destination at the given time
+func (tu *taxUsecaseImpl) GetTaxRegion(destination model.TaxDestination, at time.Time) (*model.TaxRegion, error) {
+	destination, err := normalizeTaxDestination(destination)
+	if err != nil {
+		return nil, err
+	}
+	region, err := tu.table.Region(destination, at)
+	if errors.Is(err, tax.ErrRegionNotFound) {
+		return nil, fmt.Errorf("%w: %s", ErrTaxRegionNotFound, destination.Country)
+	}
+	return region, err
+}
+
+// SetProductTaxCategory assigns one of the categories of the rate table to
+// the product, recording the change in the audit log
+func (tu *taxUsecaseImpl) SetProductTaxCategory(ctx context.Context, productID int, category string) (*model.Product, error) {
+	category = strings.ToLower(strings.TrimSpace(category))
+	if !tu.table.HasCategory(category) {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidTaxCategory, category)
+	}
+
+	product, err := tu.productRepository.GetProductById(productID)
+	if err != nil {
+		return nil, err
+	}
+	if product == nil {
+		return nil, ErrProductNotFound
+	}
+	if product.TaxCategory == category {
+		return product, nil
+	}
+
+	event, err := newAuditEvent(ctx, model.AuditActionUpdate, model.AuditEntityProduct, strconv.Itoa(productID),
+		taxCategoryAuditFields(product.TaxCategory), taxCategoryAuditFields(category))
+	if err != nil {
+		return nil, err
+	}
+	if err := tu.productRepository.SetProductTaxCategory(productID, category, event); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrProductNotFound
+		}
+		return nil, err
+	}
+	return tu.productRepository.GetProductById(productID)
+}
+
+// TaxLines taxes the lines, after their discounts, by the category of their
+// products with the rates in effect now at the destination
+func (tu *taxUsecaseImpl) TaxLines(destination model.TaxDestination, lines []model.PricedLine) (*model.TaxBreakdown, error) {
+	destination, err := normalizeTaxDestination(destination)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]int, 0, len(lines))
+	for _, line := range lines {
+		ids = append(ids, line.ProductID)
+	}
+	categories, err := tu.productRepository.GetProductTaxCategories(uniqueIDs(ids))
+	if err != nil {
+		return nil, err
+	}
+
+	taxLines := make([]tax.Line, 0, len(lines))
+	for _, line := range lines {
+		taxLines = append(taxLines, tax.Line{Category: categories[line.ProductID], Amount: line.Total})
+	}
+	breakdown, err := tu.table.Calculate(destination, time.Now(), taxLines)
+	if errors.Is(err, tax.ErrRegionNotFound) {
+		return nil, fmt.Errorf("%w: %s", ErrTaxRegionNotFound, destination.Country)
+	}
+	return breakdown, err
+}
+
+// --- Helper Functions ---
+
+// applyTaxes adds the taxes of the priced lines to the pricing; the taxes not
+// included in the prices add to the total. Nothing is taxed without a tax
+// engine, a destination or lines
+func applyTaxes(taxes TaxPricer, pricing *model.PricingResult, destination *model.TaxDestination) error {
+	if taxes == nil || destination == nil || len(pricing.Lines) == 0 {
+		return nil
+	}
+	breakdown, err := taxes.TaxLines(*destination, pricing.Lines)
+	if err != nil {
+		return err
+	}
+	pricing.Tax = breakdown
+	if !breakdown.Included {
+		if pricing.Total, err = pricing.Total.Add(breakdown.Total); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func normalizeTaxDestination(destination model.TaxDestination) (model.TaxDestination, error) {
+	destination.Country = strings.ToUpper(strings.TrimSpace(destination.Country))
+	destination.State = strings.ToUpper(strings.TrimSpace(destination.State))
+	if !countryCodePattern.MatchString(destination.Country) {
+		return model.TaxDestination{}, ErrInvalidTaxDestination
+	}
+	return destination, nil
+}
+
+func taxCategoryAuditFields(category string) map[string]interface{} {
+	return map[string]interface{}{"tax_category": category}
+}
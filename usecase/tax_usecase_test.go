@@ -0,0 +1,150 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"go-api/internal/tax"
+	"go-api/model"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testTaxTable = `{
+  "version": "test",
+  "categories": ["standard", "reduced", "exempt"],
+  "countries": [
+    {
+      "code": "BR",
+      "prices_include_tax": true,
+      "rates": [],
+      "states": {
+        "SP": [
+          {"name": "ICMS", "category": "standard", "percent": "18", "effective_from": "2023-01-01T00:00:00-03:00"},
+          {"name": "ICMS", "category": "reduced", "percent": "7", "effective_from": "2023-01-01T00:00:00-03:00"}
+        ]
+      }
+    },
+    {
+      "code": "US",
+      "prices_include_tax": false,
+      "rounding": "total",
+      "rates": [],
+      "states": {
+        "NY": [
+          {"name": "Sales tax", "category": "standard", "percent": "4", "effective_from": "2005-06-01T00:00:00-04:00"},
+          {"name": "MCTD surcharge", "category": "standard", "percent": "0.375", "effective_from": "2005-06-01T00:00:00-04:00"}
+        ]
+      }
+    }
+  ]
+}`
+
+func newTestTaxTable(t *testing.T) *tax.Table {
+	t.Helper()
+	table, err := tax.Load(strings.NewReader(testTaxTable))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return table
+}
+
+func TestTaxUsecase_GetTaxRegion(t *testing.T) {
+	usecase := NewTaxUsecase(newTestTaxTable(t), &MockProductRepository{})
+
+	t.Run("Normalizes The Destination", func(t *testing.T) {
+		region, err := usecase.GetTaxRegion(model.TaxDestination{Country: " br", State: "sp"}, time.Now())
+
+		assert.NoError(t, err)
+		assert.Equal(t, model.TaxDestination{Country: "BR", State: "SP"}, region.Destination)
+		assert.True(t, region.PricesIncludeTax)
+		assert.Len(t, region.Rates, 2)
+	})
+
+	t.Run("Invalid Country", func(t *testing.T) {
+		_, err := usecase.GetTaxRegion(model.TaxDestination{Country: "Brasil"}, time.Now())
+
+		assert.ErrorIs(t, err, ErrInvalidTaxDestination)
+	})
+
+	t.Run("Region Not Found", func(t *testing.T) {
+		_, err := usecase.GetTaxRegion(model.TaxDestination{Country: "JP"}, time.Now())
+
+		assert.ErrorIs(t, err, ErrTaxRegionNotFound)
+	})
+}
+
+func TestTaxUsecase_SetProductTaxCategory(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		category := "standard"
+		mockRepo := &MockProductRepository{
+			GetProductByIdFunc: func(id_product int) (*model.Product, error) {
+				return &model.Product{ID: id_product, Name: "Arroz", TaxCategory: category}, nil
+			},
+			SetProductTaxCategoryFunc: func(id_product int, newCategory string, event model.AuditEvent) error {
+				assert.Equal(t, 1, id_product)
+				assert.Equal(t, "reduced", newCategory)
+				assert.Equal(t, model.AuditActionUpdate, event.Action)
+				assert.JSONEq(t, `{"tax_category": {"before": "standard", "after": "reduced"}}`, string(event.Changes))
+				category = newCategory
+				return nil
+			},
+		}
+
+		usecase := NewTaxUsecase(newTestTaxTable(t), mockRepo)
+		product, err := usecase.SetProductTaxCategory(context.Background(), 1, " Reduced ")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "reduced", product.TaxCategory)
+	})
+
+	t.Run("Unknown Category", func(t *testing.T) {
+		usecase := NewTaxUsecase(newTestTaxTable(t), &MockProductRepository{})
+		_, err := usecase.SetProductTaxCategory(context.Background(), 1, "luxury")
+
+		assert.ErrorIs(t, err, ErrInvalidTaxCategory)
+	})
+
+	t.Run("Product Not Found", func(t *testing.T) {
+		mockRepo := &MockProductRepository{
+			GetProductByIdFunc: func(id_product int) (*model.Product, error) {
+				return &model.Product{ID: id_product, TaxCategory: "standard"}, nil
+			},
+			SetProductTaxCategoryFunc: func(id_product int, category string, event model.AuditEvent) error {
+				return sql.ErrNoRows
+			},
+		}
+
+		usecase := NewTaxUsecase(newTestTaxTable(t), mockRepo)
+		_, err := usecase.SetProductTaxCategory(context.Background(), 1, "exempt")
+
+		assert.True(t, errors.Is(err, ErrProductNotFound))
+	})
+}
+
+func TestTaxUsecase_TaxLines(t *testing.T) {
+	mockRepo := &MockProductRepository{
+		GetProductTaxCategoriesFunc: func(ids []int) (map[int]string, error) {
+			assert.ElementsMatch(t, []int{1, 2}, ids)
+			return map[int]string{1: "standard", 2: "reduced"}, nil
+		},
+	}
+	usecase := NewTaxUsecase(newTestTaxTable(t), mockRepo)
+	lines := []model.PricedLine{
+		{ProductID: 1, Quantity: 1, Total: model.Money{Amount: 11800, Currency: "BRL"}},
+		{ProductID: 2, Quantity: 1, Total: model.Money{Amount: 1070, Currency: "BRL"}},
+	}
+
+	breakdown, err := usecase.TaxLines(model.TaxDestination{Country: "BR", State: "SP"}, lines)
+
+	assert.NoError(t, err)
+	assert.True(t, breakdown.Included)
+	assert.Len(t, breakdown.Lines, 2)
+	assert.Equal(t, model.Money{Amount: 10000, Currency: "BRL"}, breakdown.Lines[0].Net)
+	assert.Equal(t, model.Money{Amount: 1800, Currency: "BRL"}, breakdown.Lines[0].Tax)
+	assert.Equal(t, model.Money{Amount: 70, Currency: "BRL"}, breakdown.Lines[1].Tax)
+	assert.Equal(t, model.Money{Amount: 1870, Currency: "BRL"}, breakdown.Total)
+}
@@ -1,66 +1,217 @@
 package usecase
 
 import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
 	"errors"
+	"fmt"
 	"go-api/dto"
+	"go-api/internal/audit"
+	"go-api/internal/password"
+	"go-api/internal/throttle"
 	"go-api/internal/util"
 	"go-api/model"
 	"go-api/repository"
+	"log"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	ErrUserNotFound       = errors.New("user not found")
+	ErrInvalidCredentials = errors.New("invalid credentials")
+	ErrEmailTaken         = errors.New("user with this email already exists")
+	ErrEmailReserved      = errors.New("email belongs to a deleted user and cannot be reused yet")
+	ErrWeakPassword       = password.ErrWeak
 
-	"golang.org/x/crypto/bcrypt"
+	ErrInvalidVerificationToken = errors.New("invalid or expired email verification token")
+	ErrVerificationRateLimited  = errors.New("too many verification email requests, try again later")
+	ErrEmailNotVerified         = errors.New("email not verified")
 )
 
+// UserPolicy holds the rules applied to user accounts
+type UserPolicy struct {
+	// EmailReuseAfter is how long the email of a user in the trash stays
+	// reserved; zero keeps it reserved until the user is purged
+	EmailReuseAfter time.Duration
+	// Verification has users confirm their emails; nil trusts every email
+	Verification *EmailVerification
+	// HideTakenEmails answers a sign up with an email in use just like one
+	// that worked, and emails the owner of the account instead, so sign up
+	// does not reveal which emails are registered
+	HideTakenEmails bool
+	// Password holds the strength rules of new passwords
+	Password password.Policy
+	// MFARequiredRoles are the roles told at login to enroll in two-factor
+	// authentication; the routes that demand it are guarded by the middleware
+	MFARequiredRoles []string
+}
+
+// signupNoticeInterval is the least time between two emails telling a user
+// that someone tried to sign up with their email
+const signupNoticeInterval = time.Hour
+
+// DefaultUserPolicy keeps the emails of deleted users until the purge and
+// applies the default password strength rules
+var DefaultUserPolicy = UserPolicy{
+	Password: password.DefaultPolicy,
+}
+
+// EmailVerification holds how users prove they own their email, on sign up
+// and when they change it
+type EmailVerification struct {
+	// Secret signs the links, so the server keeps no token to check them
+	Secret []byte
+	// URL is where the link of the email points, with the token in the
+	// "token" query parameter
+	URL string
+	// TTL is how long a link stays valid
+	TTL time.Duration
+	// ResendInterval is the least time between two verification emails
+	// asked for by the same user; requests within it send nothing but answer
+	// as if they did, which would otherwise tell registered emails apart
+	ResendInterval time.Duration
+	// ResendLimiter counts the requests for new emails per IP, whether or
+	// not the email has an account; nil does not limit them
+	ResendLimiter *throttle.Limiter
+	// AllowUnverifiedLogin lets users log in before verifying their email
+	AllowUnverifiedLogin bool
+}
+
+// DefaultEmailVerification keeps the links valid for a day and lets
+// unverified users, such as the ones from before verification, log in
+var DefaultEmailVerification = EmailVerification{
+	URL:                  "http://localhost:8000/auth/email/verify",
+	TTL:                  24 * time.Hour,
+	ResendInterval:       time.Minute,
+	AllowUnverifiedLogin: true,
+}
+
+// DefaultVerificationResendLimit lets an IP ask for 10 verification emails
+// an hour before delays start
+var DefaultVerificationResendLimit = throttle.Policy{
+	Window:       time.Hour,
+	FreeFailures: 10,
+	BaseDelay:    time.Second,
+	MaxDelay:     15 * time.Minute,
+}
+
+// VerificationRateLimitedError is returned while the IP of the request must
+// wait before asking for another verification email; it matches
+// ErrVerificationRateLimited
+type VerificationRateLimitedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *VerificationRateLimitedError) Error() string {
+	return ErrVerificationRateLimited.Error()
+}
+
+func (e *VerificationRateLimitedError) Unwrap() error {
+	return ErrVerificationRateLimited
+}
+
 // UserUsecase defines the contract for the user usecase
 type UserUsecase interface {
-	CreateUser(user dto.CreateUserRequest) (*dto.UserResponse, error)
+	CreateUser(ctx context.Context, user dto.CreateUserRequest) (*dto.UserResponse, error)
 	GetUserByID(id int) (*dto.UserResponse, error)
-	UpdateUser(id int, user dto.UpdateUserRequest) error
-	DeleteUser(id int) error
-	GetUsers() ([]dto.UserResponse, error)
-	Login(login dto.LoginRequest) (*dto.LoginResponse, error)
+	UpdateUser(ctx context.Context, id int, user dto.UpdateUserRequest) error
+	DeleteUser(ctx context.Context, id int) error
+	GetUsers(filter model.UserFilter) ([]dto.UserResponse, error)
+	ExportUsers(ctx context.Context, filter model.UserFilter, fn func(dto.UserResponse) error) error
+	GetDeletedUsers() ([]dto.UserResponse, error)
+	RestoreUser(ctx context.Context, id int) (*dto.UserResponse, error)
+	PurgeUser(ctx context.Context, id int) error
+	Login(ctx context.Context, login dto.LoginRequest) (*dto.LoginResponse, error)
+	VerifyEmail(ctx context.Context, token string) (*dto.UserResponse, error)
+	ResendVerification(ctx context.Context, email string) error
+}
+
+// CartMerger moves the anonymous cart of a session into the cart of the user
+// who just logged in
+type CartMerger interface {
+	MergeCart(userID int, token string) error
 }
 
 type userUsecaseImpl struct {
 	repository repository.UserRepositoryInterface
+	passwords  password.Hasher
+	policy     UserPolicy
+	carts      CartMerger
+	throttle   LoginThrottleUsecase
+	// dummyHash is compared against when the email has no account, so a
+	// failed login takes as long whether or not the email is registered
+	dummyHash func() (string, error)
 }
 
-// NewUserUsecase creates a new instance of UserUsecase
-func NewUserUsecase(repo repository.UserRepositoryInterface) UserUsecase {
+// NewUserUsecase creates a new instance of UserUsecase; carts may be nil when
+// logins should not merge anonymous carts, and throttle when failed logins
+// should not be limited
+func NewUserUsecase(repo repository.UserRepositoryInterface, passwords password.Hasher, policy UserPolicy, carts CartMerger, throttle LoginThrottleUsecase) UserUsecase {
 	return &userUsecaseImpl{
 		repository: repo,
+		passwords:  passwords,
+		policy:     policy,
+		carts:      carts,
+		throttle:   throttle,
+		dummyHash: sync.OnceValues(func() (string, error) {
+			return passwords.Hash("dummy password")
+		}),
 	}
 }
 
-func (uu *userUsecaseImpl) CreateUser(user dto.CreateUserRequest) (*dto.UserResponse, error) {
-	existingUser, err := uu.repository.GetUserByEmail(user.Email)
-	if err != nil {
+// CreateUser signs up a user. With HideTakenEmails it returns no user, and
+// no error, whether or not the email was available
+func (uu *userUsecaseImpl) CreateUser(ctx context.Context, user dto.CreateUserRequest) (*dto.UserResponse, error) {
+	if err := uu.policy.Password.Validate(user.Password, user.Email, user.Name); err != nil {
 		return nil, err
 	}
-	if existingUser != nil {
-		return nil, errors.New("user with this email already exists")
+	// Hashed before the check, so a taken email answers no faster
+	hashedPassword, err := uu.passwords.Hash(user.Password)
+	if err != nil {
+		return nil, err
 	}
 
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(user.Password), bcrypt.DefaultCost)
-	if err != nil {
+	if err := uu.checkEmailAvailable(user.Email); err != nil {
+		if uu.policy.HideTakenEmails && (errors.Is(err, ErrEmailTaken) || errors.Is(err, ErrEmailReserved)) {
+			return nil, uu.sendSignupNotice(user.Email)
+		}
 		return nil, err
 	}
 
 	newUser := model.User{
 		Name:     user.Name,
 		Email:    user.Email,
-		Password: string(hashedPassword),
+		Password: hashedPassword,
 	}
 
-	id, err := uu.repository.CreateUser(newUser)
+	// The repository fills in the ID of the new user
+	event, err := newAuditEvent(ctx, model.AuditActionCreate, model.AuditEntityUser, "", nil, userAuditFields(newUser))
+	if err != nil {
+		return nil, err
+	}
+	id, err := uu.repository.CreateUser(newUser, event)
 	if err != nil {
 		return nil, err
 	}
 
-	return &dto.UserResponse{
-		ID:    id,
-		Name:  user.Name,
-		Email: user.Email,
-	}, nil
+	now := time.Now()
+	newUser.ID = id
+	newUser.CreatedAt, newUser.UpdatedAt = now, now
+	newUser.CreatedBy, newUser.UpdatedBy = event.ActorID, event.ActorID
+	uu.queueVerification(newUser, newUser.Email)
+	if uu.policy.HideTakenEmails {
+		return nil, nil
+	}
+	response := toUserResponse(newUser)
+	return &response, nil
 }
 
 func (uu *userUsecaseImpl) GetUserByID(id int) (*dto.UserResponse, error) {
@@ -72,79 +223,563 @@ func (uu *userUsecaseImpl) GetUserByID(id int) (*dto.UserResponse, error) {
 		return nil, nil
 	}
 
-	return &dto.UserResponse{
-		ID:    user.ID,
-		Name:  user.Name,
-		Email: user.Email,
-	}, nil
+	response := toUserResponse(*user)
+	return &response, nil
 }
 
-func (uu *userUsecaseImpl) UpdateUser(id int, user dto.UpdateUserRequest) error {
+// UpdateUser changes the given fields; an empty password keeps the current one.
+// With verification on, a new email stays pending until confirmed by its link
+func (uu *userUsecaseImpl) UpdateUser(ctx context.Context, id int, user dto.UpdateUserRequest) error {
 	existingUser, err := uu.repository.GetUserByID(id)
 	if err != nil {
 		return err
 	}
 	if existingUser == nil {
-		return errors.New("user not found")
+		return ErrUserNotFound
 	}
+	before := userAuditFields(*existingUser)
 
 	if user.Name != "" {
 		existingUser.Name = user.Name
 	}
-	if user.Email != "" {
-		existingUser.Email = user.Email
+	newEmail := ""
+	if user.Email == existingUser.Email {
+		existingUser.PendingEmail = ""
+	} else if user.Email != "" {
+		if err := uu.checkEmailAvailable(user.Email); err != nil {
+			return err
+		}
+		if uu.policy.Verification != nil {
+			existingUser.PendingEmail, newEmail = user.Email, user.Email
+		} else {
+			existingUser.Email = user.Email
+		}
 	}
 	if user.Password != "" {
-		hashedPassword, err := bcrypt.GenerateFromPassword([]byte(user.Password), bcrypt.DefaultCost)
+		for _, email := range []string{existingUser.Email, existingUser.PendingEmail} {
+			if err := uu.policy.Password.Validate(user.Password, email, existingUser.Name); err != nil {
+				return err
+			}
+		}
+		hashedPassword, err := uu.passwords.Hash(user.Password)
 		if err != nil {
 			return err
 		}
-		existingUser.Password = string(hashedPassword)
+		existingUser.Password = hashedPassword
 	}
 
-	return uu.repository.UpdateUser(*existingUser)
+	event, err := newAuditEvent(ctx, model.AuditActionUpdate, model.AuditEntityUser, strconv.Itoa(id), before, userAuditFields(*existingUser))
+	if err != nil {
+		return err
+	}
+	if err := uu.repository.UpdateUser(*existingUser, event); err != nil {
+		return err
+	}
+	if newEmail != "" {
+		uu.queueVerification(*existingUser, newEmail)
+	}
+	return nil
 }
 
-func (uu *userUsecaseImpl) DeleteUser(id int) error {
-	return uu.repository.DeleteUser(id)
+// DeleteUser moves the user to the trash, from where it can be restored or purged
+func (uu *userUsecaseImpl) DeleteUser(ctx context.Context, id int) error {
+	user, err := uu.repository.GetUserByID(id)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return ErrUserNotFound
+	}
+
+	event, err := newAuditEvent(ctx, model.AuditActionDelete, model.AuditEntityUser, strconv.Itoa(id), trashAuditFields(false), trashAuditFields(true))
+	if err != nil {
+		return err
+	}
+	return uu.repository.DeleteUser(id, event)
 }
 
-func (uu *userUsecaseImpl) GetUsers() ([]dto.UserResponse, error) {
-	users, err := uu.repository.GetUsers()
+// GetUsers lists the users, only the ones changed since filter.UpdatedSince when set
+func (uu *userUsecaseImpl) GetUsers(filter model.UserFilter) ([]dto.UserResponse, error) {
+	users, err := uu.repository.GetUsers(filter)
 	if err != nil {
 		return nil, err
 	}
 
 	var userResponses []dto.UserResponse
 	for _, user := range users {
-		userResponses = append(userResponses, dto.UserResponse{
-			ID:    user.ID,
-			Name:  user.Name,
-			Email: user.Email,
-		})
+		userResponses = append(userResponses, toUserResponse(user))
 	}
 
 	return userResponses, nil
 }
 
-func (uu *userUsecaseImpl) Login(login dto.LoginRequest) (*dto.LoginResponse, error) {
-	user, err := uu.repository.GetUserByEmail(login.Email)
+// ExportUsers streams the users of the filter to fn as the response DTO,
+// which has no field for the password hash, with their role
+func (uu *userUsecaseImpl) ExportUsers(ctx context.Context, filter model.UserFilter, fn func(dto.UserResponse) error) error {
+	return uu.repository.ExportUsers(ctx, filter, func(user model.User) error {
+		response := toUserResponse(user)
+		response.Role = user.Role
+		return fn(response)
+	})
+}
+
+func (uu *userUsecaseImpl) GetDeletedUsers() ([]dto.UserResponse, error) {
+	users, err := uu.repository.GetDeletedUsers()
+	if err != nil {
+		return nil, err
+	}
+
+	userResponses := make([]dto.UserResponse, 0, len(users))
+	for _, user := range users {
+		userResponses = append(userResponses, toDeletedUserResponse(user))
+	}
+	return userResponses, nil
+}
+
+// RestoreUser takes the user out of the trash, unless its email was taken
+// by another user in the meantime
+func (uu *userUsecaseImpl) RestoreUser(ctx context.Context, id int) (*dto.UserResponse, error) {
+	user, err := uu.repository.GetDeletedUserByID(id)
 	if err != nil {
 		return nil, err
 	}
 	if user == nil {
-		return nil, errors.New("invalid credentials")
+		return nil, ErrUserNotFound
 	}
 
-	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(login.Password))
+	existingUser, err := uu.repository.GetUserByEmail(user.Email)
 	if err != nil {
-		return nil, errors.New("invalid credentials")
+		return nil, err
+	}
+	if existingUser != nil {
+		return nil, ErrEmailTaken
 	}
 
-	token, err := util.GenerateToken(user.Email, user.ID)
+	event, err := newAuditEvent(ctx, model.AuditActionRestore, model.AuditEntityUser, strconv.Itoa(id), trashAuditFields(true), trashAuditFields(false))
 	if err != nil {
 		return nil, err
 	}
+	if err := uu.repository.RestoreUser(id, event); err != nil {
+		return nil, err
+	}
+	user.UpdatedAt, user.UpdatedBy = time.Now(), event.ActorID
+	response := toUserResponse(*user)
+	response.Role = user.Role
+	return &response, nil
+}
+
+// PurgeUser deletes a user in the trash permanently, releasing its email
+func (uu *userUsecaseImpl) PurgeUser(ctx context.Context, id int) error {
+	user, err := uu.repository.GetDeletedUserByID(id)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return ErrUserNotFound
+	}
+
+	// The event keeps the last state of the user, nothing else will
+	event, err := newAuditEvent(ctx, model.AuditActionPurge, model.AuditEntityUser, strconv.Itoa(id), userAuditFields(*user), nil)
+	if err != nil {
+		return err
+	}
+	return uu.repository.PurgeUser(id, event)
+}
+
+// Login checks the credentials. With a throttle, a blocked account or IP
+// gets a *LoginThrottledError before the password is even compared
+func (uu *userUsecaseImpl) Login(ctx context.Context, login dto.LoginRequest) (*dto.LoginResponse, error) {
+	if uu.throttle != nil {
+		if err := uu.throttle.Check(ctx, login.Email); err != nil {
+			return nil, err
+		}
+	}
+
+	user, err := uu.repository.GetUserByEmail(login.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unknown emails pay for a comparison too, so timing does not tell them apart
+	hash, err := uu.dummyHash()
+	if err != nil {
+		return nil, err
+	}
+	// Service accounts only use API keys, so they are treated as unknown
+	if user != nil && user.Role == model.RoleService {
+		user = nil
+	}
+	if user != nil {
+		hash = user.Password
+	}
+	match, err := uu.passwords.Verify(login.Password, hash)
+	if err != nil && user != nil {
+		log.Printf("password hash of user %d: %v", user.ID, err)
+	}
+	if user == nil || !match {
+		return nil, uu.loginFailed(ctx, login.Email)
+	}
+	uu.rehashPassword(user, login.Password)
+	if v := uu.policy.Verification; v != nil && !v.AllowUnverifiedLogin && user.EmailVerifiedAt == nil {
+		return nil, ErrEmailNotVerified
+	}
+
+	// With two-factor authentication the password only earns a challenge;
+	// the failures stay counted until the code is right too
+	if user.MFAEnabled {
+		challenge, err := util.GenerateChallengeToken(user.ID, mfaChallengeTTL)
+		if err != nil {
+			return nil, err
+		}
+		return &dto.LoginResponse{MFARequired: true, MFAToken: challenge}, nil
+	}
+	if uu.throttle != nil {
+		if err := uu.throttle.Succeed(ctx, login.Email); err != nil {
+			return nil, err
+		}
+	}
+
+	token, err := util.GenerateToken(user.Email, user.ID, user.Role, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if uu.carts != nil && login.CartToken != "" {
+		if err := uu.carts.MergeCart(user.ID, login.CartToken); err != nil {
+			return nil, err
+		}
+	}
+
+	return &dto.LoginResponse{
+		Token:                 token,
+		MFAEnrollmentRequired: slices.Contains(uu.policy.MFARequiredRoles, user.Role),
+	}, nil
+}
+
+// VerifyEmail confirms the email of a verification link: the email of the
+// user, or the pending one, which then replaces it. A link of an email
+// already verified succeeds again; one of an email the user no longer has
+// is invalid
+func (uu *userUsecaseImpl) VerifyEmail(ctx context.Context, token string) (*dto.UserResponse, error) {
+	if uu.policy.Verification == nil {
+		return nil, ErrInvalidVerificationToken
+	}
+	id, email, ok := parseVerificationToken(uu.policy.Verification.Secret, token, time.Now())
+	if !ok {
+		return nil, ErrInvalidVerificationToken
+	}
+	user, err := uu.repository.GetUserByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrInvalidVerificationToken
+	}
+
+	before := verificationAuditFields(*user)
+	verified := *user
+	now := time.Now()
+	verified.EmailVerifiedAt = &now
+	switch email {
+	case user.Email:
+		if user.EmailVerifiedAt != nil {
+			response := toUserResponse(*user)
+			return &response, nil
+		}
+		event, err := newAuditEvent(ctx, model.AuditActionUpdate, model.AuditEntityUser, strconv.Itoa(id), before, verificationAuditFields(verified))
+		if err != nil {
+			return nil, err
+		}
+		err = uu.repository.MarkEmailVerified(id, email, event)
+		if err != nil {
+			return nil, verificationError(err)
+		}
+		verified.UpdatedBy = event.ActorID
+	case user.PendingEmail:
+		// The email was free when asked for, but may have been taken since
+		if err := uu.checkEmailAvailable(email); err != nil {
+			return nil, err
+		}
+		verified.Email, verified.PendingEmail = email, ""
+		event, err := newAuditEvent(ctx, model.AuditActionUpdate, model.AuditEntityUser, strconv.Itoa(id), before, verificationAuditFields(verified))
+		if err != nil {
+			return nil, err
+		}
+		err = uu.repository.ConfirmEmailChange(id, email, event)
+		if err != nil {
+			return nil, verificationError(err)
+		}
+		verified.UpdatedBy = event.ActorID
+	default:
+		return nil, ErrInvalidVerificationToken
+	}
+
+	verified.UpdatedAt = now
+	response := toUserResponse(verified)
+	return &response, nil
+}
+
+// ResendVerification emails a new link to the pending email of the user with
+// the email, or to the email itself while unverified. Unknown and verified
+// emails, and users sent a link less than ResendInterval ago, succeed without
+// sending anything, so the caller cannot tell which emails have an account.
+// Only the IP of the request is limited, with a
+// *VerificationRateLimitedError
+func (uu *userUsecaseImpl) ResendVerification(ctx context.Context, email string) error {
+	if uu.policy.Verification == nil {
+		return nil
+	}
+	if err := uu.limitResend(ctx); err != nil {
+		return err
+	}
+	user, err := uu.repository.GetUserByEmail(strings.TrimSpace(email))
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return nil
+	}
+
+	recipient := user.PendingEmail
+	if recipient == "" {
+		if user.EmailVerifiedAt != nil {
+			return nil
+		}
+		recipient = user.Email
+	}
+	return uu.sendVerification(*user, recipient, uu.policy.Verification.ResendInterval)
+}
+
+// --- Helper Functions ---
+
+// checkEmailAvailable rejects emails of active users and, per the policy,
+// of users still in the trash
+func (uu *userUsecaseImpl) checkEmailAvailable(email string) error {
+	existingUser, err := uu.repository.GetUserByEmail(email)
+	if err != nil {
+		return err
+	}
+	if existingUser != nil {
+		return ErrEmailTaken
+	}
+
+	deletedUser, err := uu.repository.GetDeletedUserByEmail(email)
+	if err != nil {
+		return err
+	}
+	if deletedUser != nil && (uu.policy.EmailReuseAfter <= 0 || time.Since(*deletedUser.DeletedAt) < uu.policy.EmailReuseAfter) {
+		return ErrEmailReserved
+	}
+	return nil
+}
+
+// userAuditFields is what the audit log records of a user. The password is
+// only present when set, so a new one shows up in the diff, redacted
+func userAuditFields(user model.User) map[string]interface{} {
+	fields := map[string]interface{}{
+		"name":  user.Name,
+		"email": user.Email,
+	}
+	if user.Role != "" {
+		fields["role"] = user.Role
+	}
+	if user.Password != "" {
+		fields["password"] = user.Password
+	}
+	if user.PendingEmail != "" {
+		fields["pending_email"] = user.PendingEmail
+	}
+	return fields
+}
+
+// loginFailed counts the failure with the throttle, when there is one, and
+// returns ErrInvalidCredentials
+func (uu *userUsecaseImpl) loginFailed(ctx context.Context, email string) error {
+	if uu.throttle != nil {
+		if err := uu.throttle.Fail(ctx, email); err != nil {
+			return err
+		}
+	}
+	return ErrInvalidCredentials
+}
+
+// rehashPassword replaces a hash of an older algorithm or older parameters
+// with one of the current hasher, now that the password is known. A failure
+// only postpones it to the next login
+func (uu *userUsecaseImpl) rehashPassword(user *model.User, plain string) {
+	if !uu.passwords.NeedsRehash(user.Password) {
+		return
+	}
+	hash, err := uu.passwords.Hash(plain)
+	if err == nil {
+		err = uu.repository.UpdatePasswordHash(user.ID, user.Password, hash)
+	}
+	if err != nil && err != sql.ErrNoRows {
+		log.Printf("rehash of the password of user %d: %v", user.ID, err)
+	}
+}
+
+// sendSignupNotice tells the owner of email that someone tried to sign up
+// with it, at most once per signupNoticeInterval. A user in the trash is not
+// told anything
+func (uu *userUsecaseImpl) sendSignupNotice(email string) error {
+	user, err := uu.repository.GetUserByEmail(email)
+	if err != nil || user == nil {
+		return err
+	}
+
+	body := fmt.Sprintf(`Olá, %s!
+
+Alguém tentou criar uma conta com este email, que já está cadastrado. Se foi você, entre com a sua senha ou, se não se lembra dela, use a opção "Esqueci minha senha".
+
+Se não foi você, ignore esta mensagem: nada mudou na sua conta.
+`, user.Name)
+	_, err = uu.repository.QueueSignupNotice(user.ID, signupNoticeInterval,
+		model.OutboxEmail{Recipient: user.Email, Subject: "Tentativa de cadastro com seu email", Body: body})
+	return err
+}
+
+// verificationAuditFields is what the audit log records when a user verifies
+// an email
+func verificationAuditFields(user model.User) map[string]interface{} {
+	fields := map[string]interface{}{
+		"email":          user.Email,
+		"email_verified": user.EmailVerifiedAt != nil,
+	}
+	if user.PendingEmail != "" {
+		fields["pending_email"] = user.PendingEmail
+	}
+	return fields
+}
+
+// queueVerification sends the first link for a new email. The user is already
+// saved by then, so a failure is only logged; the user can ask for another
+// link
+func (uu *userUsecaseImpl) queueVerification(user model.User, email string) {
+	if uu.policy.Verification == nil {
+		return
+	}
+	if err := uu.sendVerification(user, email, 0); err != nil {
+		log.Printf("email verification of user %d: %v", user.ID, err)
+	}
+}
+
+// limitResend counts a request for a verification email against the IP of
+// the request, failing while the IP is blocked
+func (uu *userUsecaseImpl) limitResend(ctx context.Context) error {
+	limiter := uu.policy.Verification.ResendLimiter
+	ip := audit.FromContext(ctx).IP
+	if limiter == nil || ip == "" {
+		return nil
+	}
+	key := "resend:" + ipKey(ip)
+	wait, err := limiter.Wait(ctx, key)
+	if err != nil {
+		return err
+	}
+	if wait > 0 {
+		return &VerificationRateLimitedError{RetryAfter: wait}
+	}
+	_, err = limiter.Fail(ctx, key)
+	return err
+}
+
+// sendVerification enqueues a link for email, unless another one was sent to
+// the user less than resendInterval ago, in which case it does nothing
+func (uu *userUsecaseImpl) sendVerification(user model.User, email string, resendInterval time.Duration) error {
+	v := uu.policy.Verification
+	link, err := url.Parse(v.URL)
+	if err != nil {
+		return fmt.Errorf("invalid email verification URL: %w", err)
+	}
+	query := link.Query()
+	query.Set("token", signVerificationToken(v.Secret, user.ID, email, time.Now().Add(v.TTL)))
+	link.RawQuery = query.Encode()
+
+	body := fmt.Sprintf(`Olá, %s!
+
+Para confirmar que este email é seu, acesse:
+
+%s
+
+O link vale por %s. Se você não criou uma conta nem pediu a troca de email, ignore esta mensagem.
+`, user.Name, link.String(), formatTTL(v.TTL))
+	_, err = uu.repository.QueueVerificationEmail(user.ID, resendInterval,
+		model.OutboxEmail{Recipient: email, Subject: "Confirme seu email", Body: body})
+	return err
+}
+
+// verificationError tells apart a link the user outdated in the meantime
+func verificationError(err error) error {
+	if err == sql.ErrNoRows {
+		return ErrInvalidVerificationToken
+	}
+	return err
+}
+
+// signVerificationToken writes the user, the email and the expiry in the
+// token, followed by their HMAC-SHA256, both base64url encoded
+func signVerificationToken(secret []byte, userID int, email string, expiresAt time.Time) string {
+	payload := fmt.Sprintf("%d\n%s\n%d", userID, email, expiresAt.Unix())
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString([]byte(payload)) + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// parseVerificationToken returns the user and the email of a token signed
+// with secret that has not expired by now
+func parseVerificationToken(secret []byte, token string, now time.Time) (int, string, bool) {
+	encodedPayload, encodedSignature, found := strings.Cut(token, ".")
+	if !found {
+		return 0, "", false
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil {
+		return 0, "", false
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(encodedSignature)
+	if err != nil {
+		return 0, "", false
+	}
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(payload)
+	if !hmac.Equal(signature, mac.Sum(nil)) {
+		return 0, "", false
+	}
+
+	parts := strings.Split(string(payload), "\n")
+	if len(parts) != 3 {
+		return 0, "", false
+	}
+	userID, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, "", false
+	}
+	expiresAt, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil || now.Unix() >= expiresAt {
+		return 0, "", false
+	}
+	return userID, parts[1], true
+}
+
+// toUserResponse leaves out the role, which only exports and the trash show
+func toUserResponse(user model.User) dto.UserResponse {
+	return dto.UserResponse{
+		ID:              user.ID,
+		Name:            user.Name,
+		Email:           user.Email,
+		EmailVerifiedAt: user.EmailVerifiedAt,
+		PendingEmail:    user.PendingEmail,
+		CreatedAt:       user.CreatedAt,
+		UpdatedAt:       user.UpdatedAt,
+		CreatedBy:       user.CreatedBy,
+		UpdatedBy:       user.UpdatedBy,
+	}
+}
 
-	return &dto.LoginResponse{Token: token}, nil
+func toDeletedUserResponse(user model.User) dto.UserResponse {
+	response := toUserResponse(user)
+	response.Role = user.Role
+	response.DeletedAt = user.DeletedAt
+	return response
 }
@@ -1,34 +1,46 @@
 package usecase
 
 import (
+	"context"
 	"errors"
 	"go-api/dto"
+	"go-api/internal/audit"
+	"go-api/internal/password"
+	"go-api/internal/throttle"
+	"go-api/internal/util"
 	"go-api/model"
+	"net/url"
+	"regexp"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"golang.org/x/crypto/bcrypt"
 )
 
+// testHasher is cheap enough to run in every test
+var testHasher, _ = password.NewArgon2idHasher(password.Argon2idParams{Memory: 64, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32})
+
 func TestUserUsecase_CreateUser(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		createUserRequest := dto.CreateUserRequest{
 			Name:     "Leandro",
 			Email:    "leandro@example.com",
-			Password: "password123",
+			Password: "correct horse battery",
 		}
 
 		mockRepo := &MockUserRepository{
 			GetUserByEmailFunc: func(email string) (*model.User, error) {
 				return nil, nil
 			},
-			CreateUserFunc: func(user model.User) (int, error) {
+			CreateUserFunc: func(user model.User, event model.AuditEvent) (int, error) {
 				return 1, nil
 			},
 		}
 
-		usecase := NewUserUsecase(mockRepo)
-		userResponse, err := usecase.CreateUser(createUserRequest)
+		usecase := NewUserUsecase(mockRepo, testHasher, DefaultUserPolicy, nil, nil)
+		userResponse, err := usecase.CreateUser(context.Background(), createUserRequest)
 
 		assert.NoError(t, err)
 		assert.NotNil(t, userResponse)
@@ -41,7 +53,7 @@ func TestUserUsecase_CreateUser(t *testing.T) {
 		createUserRequest := dto.CreateUserRequest{
 			Name:     "Leandro",
 			Email:    "leandro@example.com",
-			Password: "password123",
+			Password: "correct horse battery",
 		}
 
 		mockRepo := &MockUserRepository{
@@ -50,13 +62,83 @@ func TestUserUsecase_CreateUser(t *testing.T) {
 			},
 		}
 
-		usecase := NewUserUsecase(mockRepo)
-		userResponse, err := usecase.CreateUser(createUserRequest)
+		usecase := NewUserUsecase(mockRepo, testHasher, DefaultUserPolicy, nil, nil)
+		userResponse, err := usecase.CreateUser(context.Background(), createUserRequest)
 
 		assert.Error(t, err)
 		assert.Nil(t, userResponse)
 		assert.Equal(t, "user with this email already exists", err.Error())
 	})
+
+	t.Run("Hidden Taken Email Notifies The Owner", func(t *testing.T) {
+		var notice model.OutboxEmail
+		mockRepo := &MockUserRepository{
+			GetUserByEmailFunc: func(email string) (*model.User, error) {
+				return &model.User{ID: 7, Name: "Ana", Email: email}, nil
+			},
+			CreateUserFunc: func(user model.User, event model.AuditEvent) (int, error) {
+				t.Fatal("no user should be created")
+				return 0, nil
+			},
+			QueueSignupNoticeFunc: func(id int, interval time.Duration, email model.OutboxEmail) (bool, error) {
+				assert.Equal(t, 7, id)
+				assert.Equal(t, time.Hour, interval)
+				notice = email
+				return true, nil
+			},
+		}
+
+		usecase := NewUserUsecase(mockRepo, testHasher, UserPolicy{HideTakenEmails: true}, nil, nil)
+		userResponse, err := usecase.CreateUser(context.Background(), dto.CreateUserRequest{Name: "Eve", Email: "ana@example.com", Password: "correct horse battery"})
+
+		assert.NoError(t, err)
+		assert.Nil(t, userResponse)
+		assert.Equal(t, "ana@example.com", notice.Recipient)
+		assert.Contains(t, notice.Body, "Olá, Ana!")
+	})
+
+	t.Run("Hidden Sign Up Looks The Same", func(t *testing.T) {
+		created := false
+		mockRepo := &MockUserRepository{
+			CreateUserFunc: func(user model.User, event model.AuditEvent) (int, error) {
+				created = true
+				return 8, nil
+			},
+		}
+
+		usecase := NewUserUsecase(mockRepo, testHasher, UserPolicy{HideTakenEmails: true}, nil, nil)
+		userResponse, err := usecase.CreateUser(context.Background(), dto.CreateUserRequest{Name: "Bia", Email: "bia@example.com", Password: "correct horse battery"})
+
+		assert.NoError(t, err)
+		assert.Nil(t, userResponse)
+		assert.True(t, created)
+	})
+}
+
+func TestUserUsecase_PasswordStrength(t *testing.T) {
+	mockRepo := &MockUserRepository{
+		GetUserByIDFunc: func(id int) (*model.User, error) {
+			return &model.User{ID: id, Name: "Ana Lima", Email: "ana@example.com", PendingEmail: "souza.ana@example.com"}, nil
+		},
+		CreateUserFunc: func(user model.User, event model.AuditEvent) (int, error) {
+			t.Fatal("user created with a weak password")
+			return 0, nil
+		},
+		UpdateUserFunc: func(user model.User, event model.AuditEvent) error {
+			t.Fatal("user updated with a weak password")
+			return nil
+		},
+	}
+	usecase := NewUserUsecase(mockRepo, testHasher, DefaultUserPolicy, nil, nil)
+
+	_, err := usecase.CreateUser(context.Background(), dto.CreateUserRequest{Name: "Ana", Email: "ana@example.com", Password: "qwerty123"})
+	assert.True(t, errors.Is(err, ErrWeakPassword))
+
+	_, err = usecase.CreateUser(context.Background(), dto.CreateUserRequest{Name: "Ana", Email: "ana@example.com", Password: "short"})
+	assert.True(t, errors.Is(err, ErrWeakPassword))
+
+	err = usecase.UpdateUser(context.Background(), 1, dto.UpdateUserRequest{Password: "souza.ana!1"})
+	assert.True(t, errors.Is(err, ErrWeakPassword), "the pending email counts too")
 }
 
 func TestUserUsecase_GetUserByID(t *testing.T) {
@@ -73,7 +155,7 @@ func TestUserUsecase_GetUserByID(t *testing.T) {
 			},
 		}
 
-		usecase := NewUserUsecase(mockRepo)
+		usecase := NewUserUsecase(mockRepo, testHasher, DefaultUserPolicy, nil, nil)
 		userResponse, err := usecase.GetUserByID(1)
 
 		assert.NoError(t, err)
@@ -88,7 +170,7 @@ func TestUserUsecase_GetUserByID(t *testing.T) {
 			},
 		}
 
-		usecase := NewUserUsecase(mockRepo)
+		usecase := NewUserUsecase(mockRepo, testHasher, DefaultUserPolicy, nil, nil)
 		userResponse, err := usecase.GetUserByID(1)
 
 		assert.NoError(t, err)
@@ -114,17 +196,52 @@ func TestUserUsecase_UpdateUser(t *testing.T) {
 			GetUserByIDFunc: func(id int) (*model.User, error) {
 				return existingUser, nil
 			},
-			UpdateUserFunc: func(user model.User) error {
+			UpdateUserFunc: func(user model.User, event model.AuditEvent) error {
 				return nil
 			},
 		}
 
-		usecase := NewUserUsecase(mockRepo)
-		err := usecase.UpdateUser(1, updateUserRequest)
+		usecase := NewUserUsecase(mockRepo, testHasher, DefaultUserPolicy, nil, nil)
+		err := usecase.UpdateUser(context.Background(), 1, updateUserRequest)
 
 		assert.NoError(t, err)
 	})
 
+	t.Run("Records Audit Event", func(t *testing.T) {
+		actor := 9
+		ctx := audit.NewContext(context.Background(), audit.Metadata{ActorID: &actor, RequestID: "req-1", IP: "10.0.0.1", UserAgent: "curl/8.0"})
+
+		var recorded model.AuditEvent
+		var saved model.User
+		mockRepo := &MockUserRepository{
+			GetUserByIDFunc: func(id int) (*model.User, error) {
+				// Lookups by ID never load the password hash
+				return &model.User{ID: 1, Name: "Leandro", Email: "leandro@example.com"}, nil
+			},
+			UpdateUserFunc: func(user model.User, event model.AuditEvent) error {
+				saved, recorded = user, event
+				return nil
+			},
+		}
+
+		err := NewUserUsecase(mockRepo, testHasher, DefaultUserPolicy, nil, nil).UpdateUser(ctx, 1, dto.UpdateUserRequest{Name: "Leandro Updated", Password: "correct horse battery"})
+
+		assert.NoError(t, err)
+		assert.NotEmpty(t, saved.Password)
+		assert.Equal(t, &actor, recorded.ActorID)
+		assert.Equal(t, model.AuditActionUpdate, recorded.Action)
+		assert.Equal(t, model.AuditEntityUser, recorded.EntityType)
+		assert.Equal(t, "1", recorded.EntityID)
+		assert.Equal(t, "req-1", recorded.RequestID)
+		assert.Equal(t, "10.0.0.1", recorded.IP)
+		assert.Equal(t, "curl/8.0", recorded.UserAgent)
+		assert.JSONEq(t, `{
+			"name": {"before": "Leandro", "after": "Leandro Updated"},
+			"password": {"after": "[REDACTED]"}
+		}`, string(recorded.Changes))
+		assert.NotContains(t, string(recorded.Changes), saved.Password)
+	})
+
 	t.Run("User Not Found", func(t *testing.T) {
 		updateUserRequest := dto.UpdateUserRequest{
 			Name: "Leandro Updated",
@@ -136,8 +253,8 @@ func TestUserUsecase_UpdateUser(t *testing.T) {
 			},
 		}
 
-		usecase := NewUserUsecase(mockRepo)
-		err := usecase.UpdateUser(1, updateUserRequest)
+		usecase := NewUserUsecase(mockRepo, testHasher, DefaultUserPolicy, nil, nil)
+		err := usecase.UpdateUser(context.Background(), 1, updateUserRequest)
 
 		assert.Error(t, err)
 		assert.Equal(t, "user not found", err.Error())
@@ -146,33 +263,147 @@ func TestUserUsecase_UpdateUser(t *testing.T) {
 
 func TestUserUsecase_DeleteUser(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
+		deleted := 0
 		mockRepo := &MockUserRepository{
-			DeleteUserFunc: func(id int) error {
+			GetUserByIDFunc: func(id int) (*model.User, error) {
+				return &model.User{ID: id}, nil
+			},
+			DeleteUserFunc: func(id int, event model.AuditEvent) error {
+				deleted = id
 				return nil
 			},
 		}
 
-		usecase := NewUserUsecase(mockRepo)
-		err := usecase.DeleteUser(1)
+		usecase := NewUserUsecase(mockRepo, testHasher, DefaultUserPolicy, nil, nil)
+		err := usecase.DeleteUser(context.Background(), 1)
 
 		assert.NoError(t, err)
+		assert.Equal(t, 1, deleted)
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		mockRepo := &MockUserRepository{
+			DeleteUserFunc: func(id int, event model.AuditEvent) error {
+				t.Fatal("missing users must not be deleted")
+				return nil
+			},
+		}
+
+		usecase := NewUserUsecase(mockRepo, testHasher, DefaultUserPolicy, nil, nil)
+		err := usecase.DeleteUser(context.Background(), 99)
+
+		assert.ErrorIs(t, err, ErrUserNotFound)
 	})
 
 	t.Run("Repository Error", func(t *testing.T) {
 		mockRepo := &MockUserRepository{
-			DeleteUserFunc: func(id int) error {
+			GetUserByIDFunc: func(id int) (*model.User, error) {
+				return &model.User{ID: id}, nil
+			},
+			DeleteUserFunc: func(id int, event model.AuditEvent) error {
 				return errors.New("delete failed")
 			},
 		}
 
-		usecase := NewUserUsecase(mockRepo)
-		err := usecase.DeleteUser(1)
+		usecase := NewUserUsecase(mockRepo, testHasher, DefaultUserPolicy, nil, nil)
+		err := usecase.DeleteUser(context.Background(), 1)
 
 		assert.Error(t, err)
 		assert.Equal(t, "delete failed", err.Error())
 	})
 }
 
+func TestUserUsecase_EmailReusePolicy(t *testing.T) {
+	request := dto.CreateUserRequest{Name: "Leandro", Email: "leandro@example.com", Password: "correct horse battery"}
+	deletedAt := time.Now().Add(-48 * time.Hour)
+	mockRepo := func() *MockUserRepository {
+		return &MockUserRepository{
+			GetDeletedUserByEmailFunc: func(email string) (*model.User, error) {
+				return &model.User{ID: 7, Email: email, DeletedAt: &deletedAt}, nil
+			},
+			CreateUserFunc: func(user model.User, event model.AuditEvent) (int, error) {
+				return 8, nil
+			},
+		}
+	}
+
+	t.Run("Reserved Until Purge By Default", func(t *testing.T) {
+		_, err := NewUserUsecase(mockRepo(), testHasher, DefaultUserPolicy, nil, nil).CreateUser(context.Background(), request)
+
+		assert.ErrorIs(t, err, ErrEmailReserved)
+	})
+
+	t.Run("Reserved Within The Reuse Period", func(t *testing.T) {
+		_, err := NewUserUsecase(mockRepo(), testHasher, UserPolicy{EmailReuseAfter: 72 * time.Hour}, nil, nil).CreateUser(context.Background(), request)
+
+		assert.ErrorIs(t, err, ErrEmailReserved)
+	})
+
+	t.Run("Reusable After The Reuse Period", func(t *testing.T) {
+		user, err := NewUserUsecase(mockRepo(), testHasher, UserPolicy{EmailReuseAfter: 24 * time.Hour}, nil, nil).CreateUser(context.Background(), request)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 8, user.ID)
+	})
+}
+
+func TestUserUsecase_Trash(t *testing.T) {
+	deletedAt := time.Now()
+	deletedUser := &model.User{ID: 7, Name: "Leandro", Email: "leandro@example.com", Role: model.RoleCustomer, DeletedAt: &deletedAt}
+
+	t.Run("Restore", func(t *testing.T) {
+		restored := 0
+		mockRepo := &MockUserRepository{
+			GetDeletedUserByIDFunc: func(id int) (*model.User, error) {
+				return deletedUser, nil
+			},
+			RestoreUserFunc: func(id int, event model.AuditEvent) error {
+				restored = id
+				return nil
+			},
+		}
+
+		user, err := NewUserUsecase(mockRepo, testHasher, DefaultUserPolicy, nil, nil).RestoreUser(context.Background(), 7)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 7, restored)
+		assert.Equal(t, "leandro@example.com", user.Email)
+		assert.Nil(t, user.DeletedAt)
+	})
+
+	t.Run("Restore With The Email Taken", func(t *testing.T) {
+		mockRepo := &MockUserRepository{
+			GetDeletedUserByIDFunc: func(id int) (*model.User, error) {
+				return deletedUser, nil
+			},
+			GetUserByEmailFunc: func(email string) (*model.User, error) {
+				return &model.User{ID: 8, Email: email}, nil
+			},
+			RestoreUserFunc: func(id int, event model.AuditEvent) error {
+				t.Fatal("the user must stay in the trash")
+				return nil
+			},
+		}
+
+		_, err := NewUserUsecase(mockRepo, testHasher, DefaultUserPolicy, nil, nil).RestoreUser(context.Background(), 7)
+
+		assert.ErrorIs(t, err, ErrEmailTaken)
+	})
+
+	t.Run("Purge Outside The Trash", func(t *testing.T) {
+		mockRepo := &MockUserRepository{
+			PurgeUserFunc: func(id int, event model.AuditEvent) error {
+				t.Fatal("only users in the trash can be purged")
+				return nil
+			},
+		}
+
+		err := NewUserUsecase(mockRepo, testHasher, DefaultUserPolicy, nil, nil).PurgeUser(context.Background(), 1)
+
+		assert.ErrorIs(t, err, ErrUserNotFound)
+	})
+}
+
 func TestUserUsecase_GetUsers(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		expectedUsers := []model.User{
@@ -181,13 +412,13 @@ func TestUserUsecase_GetUsers(t *testing.T) {
 		}
 
 		mockRepo := &MockUserRepository{
-			GetUsersFunc: func() ([]model.User, error) {
+			GetUsersFunc: func(filter model.UserFilter) ([]model.User, error) {
 				return expectedUsers, nil
 			},
 		}
 
-		usecase := NewUserUsecase(mockRepo)
-		userResponses, err := usecase.GetUsers()
+		usecase := NewUserUsecase(mockRepo, testHasher, DefaultUserPolicy, nil, nil)
+		userResponses, err := usecase.GetUsers(model.UserFilter{})
 
 		assert.NoError(t, err)
 		assert.Len(t, userResponses, 2)
@@ -195,6 +426,29 @@ func TestUserUsecase_GetUsers(t *testing.T) {
 	})
 }
 
+func TestUserUsecase_ExportUsers(t *testing.T) {
+	t.Run("Maps Users Without The Password", func(t *testing.T) {
+		createdAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+		since := createdAt.Add(-time.Hour)
+		mockRepo := &MockUserRepository{
+			ExportUsersFunc: func(ctx context.Context, filter model.UserFilter, fn func(model.User) error) error {
+				assert.Equal(t, since, filter.UpdatedSince)
+				return fn(model.User{ID: 1, Name: "Leandro", Email: "leandro@example.com", Password: "$2a$10$hash", Role: model.RoleAdmin, CreatedAt: createdAt, UpdatedAt: createdAt})
+			},
+		}
+
+		usecase := NewUserUsecase(mockRepo, testHasher, DefaultUserPolicy, nil, nil)
+		var exported []dto.UserResponse
+		err := usecase.ExportUsers(context.Background(), model.UserFilter{UpdatedSince: since}, func(user dto.UserResponse) error {
+			exported = append(exported, user)
+			return nil
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []dto.UserResponse{{ID: 1, Name: "Leandro", Email: "leandro@example.com", Role: model.RoleAdmin, CreatedAt: createdAt, UpdatedAt: createdAt}}, exported)
+	})
+}
+
 func TestUserUsecase_Login(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		password := "password123"
@@ -204,28 +458,153 @@ func TestUserUsecase_Login(t *testing.T) {
 				return &model.User{ID: 1, Email: email, Password: string(hash)}, nil
 			},
 		}
-		usecase := NewUserUsecase(mockRepo)
+		usecase := NewUserUsecase(mockRepo, testHasher, DefaultUserPolicy, nil, nil)
 		loginReq := dto.LoginRequest{Email: "user@example.com", Password: password}
-		resp, err := usecase.Login(loginReq)
+		resp, err := usecase.Login(context.Background(), loginReq)
 		assert.NoError(t, err)
 		assert.NotNil(t, resp)
 		assert.NotEmpty(t, resp.Token)
 	})
 
+	t.Run("Merges Anonymous Cart", func(t *testing.T) {
+		password := "password123"
+		hash, _ := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+		mockRepo := &MockUserRepository{
+			GetUserByEmailFunc: func(email string) (*model.User, error) {
+				return &model.User{ID: 1, Email: email, Password: string(hash)}, nil
+			},
+		}
+		var claimed []int
+		carts := NewCartUsecase(&MockCartRepository{
+			GetCartByTokenHashFunc: func(tokenHash string) (*model.Cart, error) {
+				assert.Equal(t, hashCartToken("anon-token"), tokenHash)
+				return &model.Cart{ID: 9}, nil
+			},
+			ClaimCartFunc: func(cartID, userID int) error {
+				claimed = []int{cartID, userID}
+				return nil
+			},
+		}, nil, nil, nil, nil)
+
+		usecase := NewUserUsecase(mockRepo, testHasher, DefaultUserPolicy, carts, nil)
+		_, err := usecase.Login(context.Background(), dto.LoginRequest{Email: "user@example.com", Password: password, CartToken: "anon-token"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []int{9, 1}, claimed)
+	})
+
+	t.Run("Two-Factor Users Get A Challenge", func(t *testing.T) {
+		password := "password123"
+		hash, _ := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+		mockRepo := &MockUserRepository{
+			GetUserByEmailFunc: func(email string) (*model.User, error) {
+				return &model.User{ID: 1, Email: email, Password: string(hash), MFAEnabled: true}, nil
+			},
+		}
+		usecase := NewUserUsecase(mockRepo, testHasher, DefaultUserPolicy, nil, nil)
+		resp, err := usecase.Login(context.Background(), dto.LoginRequest{Email: "user@example.com", Password: password})
+
+		assert.NoError(t, err)
+		assert.Empty(t, resp.Token)
+		assert.True(t, resp.MFARequired)
+		userID, err := util.ParseChallengeToken(resp.MFAToken)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, userID)
+	})
+
+	t.Run("Tells Roles That Require Two-Factor To Enroll", func(t *testing.T) {
+		password := "password123"
+		hash, _ := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+		mockRepo := &MockUserRepository{
+			GetUserByEmailFunc: func(email string) (*model.User, error) {
+				return &model.User{ID: 1, Email: email, Password: string(hash), Role: "admin"}, nil
+			},
+		}
+		usecase := NewUserUsecase(mockRepo, testHasher, UserPolicy{MFARequiredRoles: []string{"admin"}}, nil, nil)
+		resp, err := usecase.Login(context.Background(), dto.LoginRequest{Email: "user@example.com", Password: password})
+
+		assert.NoError(t, err)
+		assert.NotEmpty(t, resp.Token)
+		assert.True(t, resp.MFAEnrollmentRequired)
+	})
+
+	t.Run("Unknown Email Compares A Dummy Hash", func(t *testing.T) {
+		usecase := NewUserUsecase(&MockUserRepository{}, testHasher, DefaultUserPolicy, nil, nil).(*userUsecaseImpl)
+		hash, err := usecase.dummyHash()
+		assert.NoError(t, err)
+		assert.False(t, testHasher.NeedsRehash(hash), "the dummy hash costs as much as a real one")
+	})
+
+	t.Run("Rehashes Legacy Hashes", func(t *testing.T) {
+		password := "password123"
+		legacy, _ := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+		var oldHash, newHash string
+		mockRepo := &MockUserRepository{
+			GetUserByEmailFunc: func(email string) (*model.User, error) {
+				return &model.User{ID: 1, Email: email, Password: string(legacy)}, nil
+			},
+			UpdatePasswordHashFunc: func(id int, old, new string) error {
+				oldHash, newHash = old, new
+				return nil
+			},
+		}
+
+		usecase := NewUserUsecase(mockRepo, testHasher, DefaultUserPolicy, nil, nil)
+		_, err := usecase.Login(context.Background(), dto.LoginRequest{Email: "user@example.com", Password: password})
+
+		assert.NoError(t, err)
+		assert.Equal(t, string(legacy), oldHash)
+		assert.True(t, strings.HasPrefix(newHash, "$argon2id$"))
+		ok, err := testHasher.Verify(password, newHash)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+	})
+
+	t.Run("Current Hashes Are Kept", func(t *testing.T) {
+		hash, _ := testHasher.Hash("password123")
+		mockRepo := &MockUserRepository{
+			GetUserByEmailFunc: func(email string) (*model.User, error) {
+				return &model.User{ID: 1, Email: email, Password: hash}, nil
+			},
+			UpdatePasswordHashFunc: func(id int, old, new string) error {
+				t.Fatal("current hash rehashed")
+				return nil
+			},
+		}
+
+		usecase := NewUserUsecase(mockRepo, testHasher, DefaultUserPolicy, nil, nil)
+		_, err := usecase.Login(context.Background(), dto.LoginRequest{Email: "user@example.com", Password: "password123"})
+
+		assert.NoError(t, err)
+	})
+
 	t.Run("Invalid Credentials - User Not Found", func(t *testing.T) {
 		mockRepo := &MockUserRepository{
 			GetUserByEmailFunc: func(email string) (*model.User, error) {
 				return nil, nil
 			},
 		}
-		usecase := NewUserUsecase(mockRepo)
+		usecase := NewUserUsecase(mockRepo, testHasher, DefaultUserPolicy, nil, nil)
 		loginReq := dto.LoginRequest{Email: "notfound@example.com", Password: "password123"}
-		resp, err := usecase.Login(loginReq)
+		resp, err := usecase.Login(context.Background(), loginReq)
 		assert.Error(t, err)
 		assert.Nil(t, resp)
 		assert.Equal(t, "invalid credentials", err.Error())
 	})
 
+	t.Run("Invalid Credentials - Service Account", func(t *testing.T) {
+		hash, _ := testHasher.Hash("password123")
+		mockRepo := &MockUserRepository{
+			GetUserByEmailFunc: func(email string) (*model.User, error) {
+				return &model.User{ID: 9, Email: email, Password: hash, Role: model.RoleService}, nil
+			},
+		}
+		usecase := NewUserUsecase(mockRepo, testHasher, DefaultUserPolicy, nil, nil)
+		resp, err := usecase.Login(context.Background(), dto.LoginRequest{Email: "armazem@example.com", Password: "password123"})
+		assert.True(t, errors.Is(err, ErrInvalidCredentials))
+		assert.Nil(t, resp)
+	})
+
 	t.Run("Invalid Credentials - Wrong Password", func(t *testing.T) {
 		password := "password123"
 		hash, _ := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
@@ -234,9 +613,9 @@ func TestUserUsecase_Login(t *testing.T) {
 				return &model.User{ID: 1, Email: email, Password: string(hash)}, nil
 			},
 		}
-		usecase := NewUserUsecase(mockRepo)
+		usecase := NewUserUsecase(mockRepo, testHasher, DefaultUserPolicy, nil, nil)
 		loginReq := dto.LoginRequest{Email: "user@example.com", Password: "wrongpassword"}
-		resp, err := usecase.Login(loginReq)
+		resp, err := usecase.Login(context.Background(), loginReq)
 		assert.Error(t, err)
 		assert.Nil(t, resp)
 		assert.Equal(t, "invalid credentials", err.Error())
@@ -248,11 +627,194 @@ func TestUserUsecase_Login(t *testing.T) {
 				return nil, errors.New("db error")
 			},
 		}
-		usecase := NewUserUsecase(mockRepo)
+		usecase := NewUserUsecase(mockRepo, testHasher, DefaultUserPolicy, nil, nil)
 		loginReq := dto.LoginRequest{Email: "user@example.com", Password: "password123"}
-		resp, err := usecase.Login(loginReq)
+		resp, err := usecase.Login(context.Background(), loginReq)
 		assert.Error(t, err)
 		assert.Nil(t, resp)
 		assert.Equal(t, "db error", err.Error())
 	})
 }
+
+func TestUserUsecase_EmailVerification(t *testing.T) {
+	verification := &EmailVerification{
+		Secret:         []byte("secret"),
+		URL:            "https://loja.example/verificar",
+		TTL:            time.Hour,
+		ResendInterval: time.Minute,
+	}
+	policy := UserPolicy{Verification: verification}
+	linkPattern := regexp.MustCompile(`https://loja.example/verificar\?token=\S+`)
+
+	t.Run("Sign Up Sends A Signed Link", func(t *testing.T) {
+		var sent model.OutboxEmail
+		mockRepo := &MockUserRepository{
+			CreateUserFunc: func(user model.User, event model.AuditEvent) (int, error) {
+				return 7, nil
+			},
+			QueueVerificationEmailFunc: func(id int, resendInterval time.Duration, email model.OutboxEmail) (bool, error) {
+				assert.Equal(t, 7, id)
+				assert.Zero(t, resendInterval)
+				sent = email
+				return true, nil
+			},
+		}
+
+		usecase := NewUserUsecase(mockRepo, testHasher, policy, nil, nil)
+		_, err := usecase.CreateUser(context.Background(), dto.CreateUserRequest{Name: "Ana", Email: "ana@example.com", Password: "correct horse battery"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "ana@example.com", sent.Recipient)
+		assert.Contains(t, sent.Body, "1 hora")
+		link, err := url.Parse(linkPattern.FindString(sent.Body))
+		assert.NoError(t, err)
+		id, email, ok := parseVerificationToken(verification.Secret, link.Query().Get("token"), time.Now())
+		assert.True(t, ok)
+		assert.Equal(t, 7, id)
+		assert.Equal(t, "ana@example.com", email)
+	})
+
+	t.Run("Email Change Stays Pending", func(t *testing.T) {
+		var updated model.User
+		var sentTo string
+		mockRepo := &MockUserRepository{
+			GetUserByIDFunc: func(id int) (*model.User, error) {
+				return &model.User{ID: id, Name: "Ana", Email: "ana@example.com"}, nil
+			},
+			UpdateUserFunc: func(user model.User, event model.AuditEvent) error {
+				updated = user
+				return nil
+			},
+			QueueVerificationEmailFunc: func(id int, resendInterval time.Duration, email model.OutboxEmail) (bool, error) {
+				sentTo = email.Recipient
+				return true, nil
+			},
+		}
+
+		usecase := NewUserUsecase(mockRepo, testHasher, policy, nil, nil)
+		err := usecase.UpdateUser(context.Background(), 7, dto.UpdateUserRequest{Email: "nova@example.com"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "ana@example.com", updated.Email)
+		assert.Equal(t, "nova@example.com", updated.PendingEmail)
+		assert.Equal(t, "nova@example.com", sentTo)
+	})
+
+	t.Run("Verify Confirms The Pending Email", func(t *testing.T) {
+		token := signVerificationToken(verification.Secret, 7, "nova@example.com", time.Now().Add(time.Hour))
+		mockRepo := &MockUserRepository{
+			GetUserByIDFunc: func(id int) (*model.User, error) {
+				return &model.User{ID: id, Name: "Ana", Email: "ana@example.com", PendingEmail: "nova@example.com"}, nil
+			},
+			ConfirmEmailChangeFunc: func(id int, email string, event model.AuditEvent) error {
+				assert.Equal(t, 7, id)
+				assert.Equal(t, "nova@example.com", email)
+				return nil
+			},
+		}
+
+		usecase := NewUserUsecase(mockRepo, testHasher, policy, nil, nil)
+		user, err := usecase.VerifyEmail(context.Background(), token)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "nova@example.com", user.Email)
+		assert.Empty(t, user.PendingEmail)
+		assert.NotNil(t, user.EmailVerifiedAt)
+	})
+
+	t.Run("Verify Rejects Tampered And Expired Tokens", func(t *testing.T) {
+		usecase := NewUserUsecase(&MockUserRepository{}, testHasher, policy, nil, nil)
+
+		other := signVerificationToken([]byte("other"), 7, "ana@example.com", time.Now().Add(time.Hour))
+		_, err := usecase.VerifyEmail(context.Background(), other)
+		assert.True(t, errors.Is(err, ErrInvalidVerificationToken))
+
+		expired := signVerificationToken(verification.Secret, 7, "ana@example.com", time.Now().Add(-time.Minute))
+		_, err = usecase.VerifyEmail(context.Background(), expired)
+		assert.True(t, errors.Is(err, ErrInvalidVerificationToken))
+	})
+
+	t.Run("Verify Rejects An Outdated Email", func(t *testing.T) {
+		token := signVerificationToken(verification.Secret, 7, "velha@example.com", time.Now().Add(time.Hour))
+		mockRepo := &MockUserRepository{
+			GetUserByIDFunc: func(id int) (*model.User, error) {
+				return &model.User{ID: id, Email: "ana@example.com"}, nil
+			},
+		}
+
+		_, err := NewUserUsecase(mockRepo, testHasher, policy, nil, nil).VerifyEmail(context.Background(), token)
+
+		assert.True(t, errors.Is(err, ErrInvalidVerificationToken))
+	})
+
+	t.Run("Resend Within The Interval Answers Like A Send", func(t *testing.T) {
+		mockRepo := &MockUserRepository{
+			GetUserByEmailFunc: func(email string) (*model.User, error) {
+				return &model.User{ID: 7, Email: email}, nil
+			},
+			QueueVerificationEmailFunc: func(id int, resendInterval time.Duration, email model.OutboxEmail) (bool, error) {
+				assert.Equal(t, time.Minute, resendInterval)
+				return false, nil
+			},
+		}
+
+		err := NewUserUsecase(mockRepo, testHasher, policy, nil, nil).ResendVerification(context.Background(), "ana@example.com")
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("Resend Is Rate Limited Per IP", func(t *testing.T) {
+		limited := policy
+		verification := *policy.Verification
+		verification.ResendLimiter = throttle.NewLimiter(throttle.NewMemoryStore(),
+			throttle.Policy{Window: time.Hour, FreeFailures: 1, BaseDelay: time.Minute, MaxDelay: time.Hour})
+		limited.Verification = &verification
+		usecase := NewUserUsecase(&MockUserRepository{}, testHasher, limited, nil, nil)
+		ctx := audit.NewContext(context.Background(), audit.Metadata{IP: "203.0.113.7"})
+
+		for _, email := range []string{"nobody@example.com", "other@example.com"} {
+			assert.NoError(t, usecase.ResendVerification(ctx, email))
+		}
+		err := usecase.ResendVerification(ctx, "third@example.com")
+
+		var rateLimited *VerificationRateLimitedError
+		assert.True(t, errors.As(err, &rateLimited))
+		assert.NoError(t, usecase.ResendVerification(context.Background(), "third@example.com"))
+	})
+
+	t.Run("Resend Skips Verified Emails", func(t *testing.T) {
+		verifiedAt := time.Now()
+		mockRepo := &MockUserRepository{
+			GetUserByEmailFunc: func(email string) (*model.User, error) {
+				return &model.User{ID: 7, Email: email, EmailVerifiedAt: &verifiedAt}, nil
+			},
+			QueueVerificationEmailFunc: func(id int, resendInterval time.Duration, email model.OutboxEmail) (bool, error) {
+				t.Fatal("no email should be sent")
+				return false, nil
+			},
+		}
+
+		err := NewUserUsecase(mockRepo, testHasher, policy, nil, nil).ResendVerification(context.Background(), "ana@example.com")
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("Login Of Unverified Users", func(t *testing.T) {
+		hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.DefaultCost)
+		mockRepo := &MockUserRepository{
+			GetUserByEmailFunc: func(email string) (*model.User, error) {
+				return &model.User{ID: 7, Email: email, Password: string(hashedPassword)}, nil
+			},
+		}
+		login := dto.LoginRequest{Email: "ana@example.com", Password: "password123"}
+
+		required := *verification
+		_, err := NewUserUsecase(mockRepo, testHasher, UserPolicy{Verification: &required}, nil, nil).Login(context.Background(), login)
+		assert.True(t, errors.Is(err, ErrEmailNotVerified))
+
+		allowed := *verification
+		allowed.AllowUnverifiedLogin = true
+		_, err = NewUserUsecase(mockRepo, testHasher, UserPolicy{Verification: &allowed}, nil, nil).Login(context.Background(), login)
+		assert.NoError(t, err)
+	})
+}
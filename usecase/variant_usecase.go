@@ -0,0 +1,366 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"go-api/model"
+	"go-api/repository"
+	"strings"
+)
+
+var (
+	ErrOptionTypeNotFound    = errors.New("option type not found")
+	ErrOptionTypeNameTaken   = errors.New("option type name already exists")
+	ErrOptionValueTaken      = errors.New("option value already exists for this option type")
+	ErrOptionValueNotFound   = errors.New("option value not found")
+	ErrVariantNotFound       = errors.New("variant not found")
+	ErrSKUTaken              = errors.New("sku already used by another variant")
+	ErrInvalidVariantOptions = errors.New("invalid variant options")
+	ErrDuplicateVariant      = errors.New("product already has a variant with these options")
+	ErrInvalidStock          = errors.New("stock must not be negative")
+	ErrInvalidSKU            = errors.New("sku must not be empty")
+)
+
+// VariantUsecase defines the contract for option types and product variants
+type VariantUsecase interface {
+	GetOptionTypes() ([]model.OptionType, error)
+	CreateOptionType(name string, values []string) (*model.OptionType, error)
+	AddOptionValue(optionTypeID int, value string) (model.OptionValue, error)
+	GetVariants(productID int) ([]model.Variant, error)
+	CreateVariant(productID int, input model.VariantInput) (*model.Variant, error)
+	UpdateVariant(productID, variantID int, input model.VariantInput) (*model.Variant, error)
+	DeleteVariant(productID, variantID int) error
+}
+
+type variantUsecaseImpl struct {
+	repository        repository.VariantRepositoryInterface
+	productRepository repository.ProductRepositoryInterface
+}
+
+// NewVariantUsecase creates a new instance of VariantUsecase
+func NewVariantUsecase(repo repository.VariantRepositoryInterface, productRepo repository.ProductRepositoryInterface) VariantUsecase {
+	return &variantUsecaseImpl{
+		repository:        repo,
+		productRepository: productRepo,
+	}
+}
+
+func (vu *variantUsecaseImpl) GetOptionTypes() ([]model.OptionType, error) {
+	return vu.repository.GetOptionTypes()
+}
+
+// CreateOptionType creates an option type with its values in the given order
+func (vu *variantUsecaseImpl) CreateOptionType(name string, values []string) (*model.OptionType, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	existing, err := vu.repository.GetOptionTypeByName(name)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, ErrOptionTypeNameTaken
+	}
+
+	optionType := model.OptionType{Name: name}
+	seen := make(map[string]bool)
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if seen[strings.ToLower(value)] {
+			return nil, fmt.Errorf("%w: %q", ErrOptionValueTaken, value)
+		}
+		seen[strings.ToLower(value)] = true
+		optionType.Values = append(optionType.Values, model.OptionValue{Value: value, Position: len(optionType.Values)})
+	}
+
+	id, err := vu.repository.CreateOptionType(optionType)
+	if err != nil {
+		return nil, err
+	}
+	return vu.repository.GetOptionTypeByID(id)
+}
+
+// AddOptionValue appends a value at the end of the option type
+func (vu *variantUsecaseImpl) AddOptionValue(optionTypeID int, value string) (model.OptionValue, error) {
+	optionType, err := vu.repository.GetOptionTypeByID(optionTypeID)
+	if err != nil {
+		return model.OptionValue{}, err
+	}
+	if optionType == nil {
+		return model.OptionValue{}, ErrOptionTypeNotFound
+	}
+
+	value = strings.TrimSpace(value)
+	for _, existing := range optionType.Values {
+		if strings.EqualFold(existing.Value, value) {
+			return model.OptionValue{}, ErrOptionValueTaken
+		}
+	}
+
+	optionValue := model.OptionValue{OptionTypeID: optionTypeID, Value: value, Position: len(optionType.Values)}
+	optionValue.ID, err = vu.repository.CreateOptionValue(optionValue)
+	if err != nil {
+		return model.OptionValue{}, err
+	}
+	return optionValue, nil
+}
+
+func (vu *variantUsecaseImpl) GetVariants(productID int) ([]model.Variant, error) {
+	product, err := vu.productRepository.GetProductById(productID)
+	if err != nil {
+		return nil, err
+	}
+	if product == nil {
+		return nil, ErrProductNotFound
+	}
+
+	products := []model.Product{*product}
+	if err := attachVariants(vu.repository, products); err != nil {
+		return nil, err
+	}
+	return products[0].Variants, nil
+}
+
+// CreateVariant adds a variant to the product. A variant takes one value of
+// each option type, every variant of a product uses the same option types and
+// no two variants share the same combination
+func (vu *variantUsecaseImpl) CreateVariant(productID int, input model.VariantInput) (*model.Variant, error) {
+	product, err := vu.productRepository.GetProductById(productID)
+	if err != nil {
+		return nil, err
+	}
+	if product == nil {
+		return nil, ErrProductNotFound
+	}
+
+	variant := model.Variant{ProductID: productID}
+	if err := vu.applyVariantInput(&variant, product, input); err != nil {
+		return nil, err
+	}
+
+	requested := make(map[int]bool, len(input.OptionValueIDs))
+	for _, id := range input.OptionValueIDs {
+		if requested[id] {
+			return nil, fmt.Errorf("%w: option value %d repeated", ErrInvalidVariantOptions, id)
+		}
+		requested[id] = true
+	}
+
+	variant.Options, err = vu.repository.GetOptionValuesByIDs(input.OptionValueIDs)
+	if err != nil {
+		return nil, err
+	}
+	if len(variant.Options) != len(input.OptionValueIDs) {
+		return nil, ErrOptionValueNotFound
+	}
+
+	siblings, err := vu.repository.GetVariants([]int{productID})
+	if err != nil {
+		return nil, err
+	}
+	if err := validateVariantOptions(variant.Options, siblings); err != nil {
+		return nil, err
+	}
+
+	id, err := vu.repository.CreateVariant(variant)
+	if err != nil {
+		return nil, err
+	}
+	return vu.getVariant(product, id)
+}
+
+// UpdateVariant changes the SKU, price override and stock of a variant
+func (vu *variantUsecaseImpl) UpdateVariant(productID, variantID int, input model.VariantInput) (*model.Variant, error) {
+	product, variant, err := vu.findVariant(productID, variantID)
+	if err != nil {
+		return nil, err
+	}
+	if err := vu.applyVariantInput(variant, product, input); err != nil {
+		return nil, err
+	}
+
+	if err := vu.repository.UpdateVariant(*variant); err != nil {
+		return nil, err
+	}
+	return vu.getVariant(product, variantID)
+}
+
+func (vu *variantUsecaseImpl) DeleteVariant(productID, variantID int) error {
+	if _, _, err := vu.findVariant(productID, variantID); err != nil {
+		return err
+	}
+	return vu.repository.DeleteVariant(variantID)
+}
+
+// --- Helper Functions ---
+
+func (vu *variantUsecaseImpl) findVariant(productID, variantID int) (*model.Product, *model.Variant, error) {
+	product, err := vu.productRepository.GetProductById(productID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if product == nil {
+		return nil, nil, ErrProductNotFound
+	}
+
+	variant, err := vu.repository.GetVariantByID(variantID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if variant == nil || variant.ProductID != productID {
+		return nil, nil, ErrVariantNotFound
+	}
+	return product, variant, nil
+}
+
+func (vu *variantUsecaseImpl) getVariant(product *model.Product, variantID int) (*model.Variant, error) {
+	variant, err := vu.repository.GetVariantByID(variantID)
+	if err != nil {
+		return nil, err
+	}
+	if variant == nil {
+		return nil, ErrVariantNotFound
+	}
+	resolveVariantPrice(variant, product.Price)
+	return variant, nil
+}
+
+// applyVariantInput validates the SKU, price override and stock and copies them into the variant
+func (vu *variantUsecaseImpl) applyVariantInput(variant *model.Variant, product *model.Product, input model.VariantInput) error {
+	sku := normalizeSKU(input.SKU)
+	if sku == "" {
+		return ErrInvalidSKU
+	}
+	if sku != variant.SKU {
+		existing, err := vu.repository.GetVariantBySKU(sku)
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			return ErrSKUTaken
+		}
+		variant.SKU = sku
+	}
+
+	if input.Stock < 0 {
+		return ErrInvalidStock
+	}
+	variant.Stock = input.Stock
+
+	variant.PriceOverride = nil
+	if input.PriceOverride != nil {
+		override, err := model.ParseMoney(*input.PriceOverride, product.Price.Currency)
+		if err != nil {
+			return err
+		}
+		if override.IsNegative() {
+			return fmt.Errorf("%w: price must not be negative", model.ErrInvalidAmount)
+		}
+		variant.PriceOverride = &override
+	}
+	return nil
+}
+
+// validateVariantOptions checks that the options take one value per option
+// type, match the option types of the existing variants and form a new combination
+func validateVariantOptions(options []model.VariantOption, siblings []model.Variant) error {
+	types := make(map[int]bool)
+	for _, option := range options {
+		if types[option.OptionTypeID] {
+			return fmt.Errorf("%w: more than one value for %q", ErrInvalidVariantOptions, option.OptionType)
+		}
+		types[option.OptionTypeID] = true
+	}
+
+	key := variantKey(options)
+	for _, sibling := range siblings {
+		if len(sibling.Options) != len(options) {
+			return fmt.Errorf("%w: the variants of this product use the options %s", ErrInvalidVariantOptions, optionTypeNames(sibling.Options))
+		}
+		for _, option := range sibling.Options {
+			if !types[option.OptionTypeID] {
+				return fmt.Errorf("%w: the variants of this product use the options %s", ErrInvalidVariantOptions, optionTypeNames(sibling.Options))
+			}
+		}
+		if variantKey(sibling.Options) == key {
+			return ErrDuplicateVariant
+		}
+	}
+	return nil
+}
+
+func variantKey(options []model.VariantOption) string {
+	values := make(map[int]int, len(options))
+	for _, option := range options {
+		values[option.OptionTypeID] = option.OptionValueID
+	}
+	return fmt.Sprint(values)
+}
+
+func optionTypeNames(options []model.VariantOption) string {
+	names := make([]string, 0, len(options))
+	for _, option := range options {
+		names = append(names, option.OptionType)
+	}
+	return strings.Join(names, ", ")
+}
+
+// attachVariants loads the variants of the products and builds their variant
+// matrix. Variant prices are resolved from the product price as it is now, so
+// it must run before the product price is converted
+func attachVariants(repo repository.VariantRepositoryInterface, products []model.Product) error {
+	if len(products) == 0 {
+		return nil
+	}
+
+	productIDs := make([]int, 0, len(products))
+	index := make(map[int]int, len(products))
+	for i, product := range products {
+		productIDs = append(productIDs, product.ID)
+		index[product.ID] = i
+	}
+
+	variants, err := repo.GetVariants(productIDs)
+	if err != nil {
+		return err
+	}
+	for _, variant := range variants {
+		product := &products[index[variant.ProductID]]
+		resolveVariantPrice(&variant, product.Price)
+		product.Variants = append(product.Variants, variant)
+	}
+
+	for i := range products {
+		products[i].Options = variantMatrix(products[i].Variants)
+	}
+	return nil
+}
+
+func resolveVariantPrice(variant *model.Variant, productPrice model.Money) {
+	if variant.PriceOverride != nil {
+		variant.Price = *variant.PriceOverride
+		return
+	}
+	variant.Price = productPrice
+}
+
+// variantMatrix lists the option types used by the variants with their values,
+// in the order they first appear
+func variantMatrix(variants []model.Variant) []model.ProductOption {
+	var matrix []model.ProductOption
+	position := make(map[string]int)
+	seen := make(map[string]bool)
+	for _, variant := range variants {
+		for _, option := range variant.Options {
+			i, ok := position[option.OptionType]
+			if !ok {
+				i = len(matrix)
+				position[option.OptionType] = i
+				matrix = append(matrix, model.ProductOption{Name: option.OptionType})
+			}
+			if key := option.OptionType + "\x00" + option.Value; !seen[key] {
+				seen[key] = true
+				matrix[i].Values = append(matrix[i].Values, option.Value)
+			}
+		}
+	}
+	return matrix
+}
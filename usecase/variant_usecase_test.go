@@ -0,0 +1,244 @@
+package usecase
+
+import (
+	"go-api/model"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	sizeM     = model.VariantOption{OptionTypeID: 1, OptionType: "size", OptionValueID: 11, Value: "M"}
+	sizeG     = model.VariantOption{OptionTypeID: 1, OptionType: "size", OptionValueID: 12, Value: "G"}
+	colorAzul = model.VariantOption{OptionTypeID: 2, OptionType: "color", OptionValueID: 21, Value: "Azul"}
+)
+
+func variantProduct(id int) (*model.Product, error) {
+	return &model.Product{ID: id, Name: "Camiseta", Price: model.Money{Amount: 4990, Currency: "BRL"}}, nil
+}
+
+func TestVariantUsecase_CreateOptionType(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		var created model.OptionType
+		mockRepo := &MockVariantRepository{
+			CreateOptionTypeFunc: func(optionType model.OptionType) (int, error) {
+				created = optionType
+				return 1, nil
+			},
+			GetOptionTypeByIDFunc: func(id int) (*model.OptionType, error) {
+				created.ID = id
+				return &created, nil
+			},
+		}
+
+		usecase := NewVariantUsecase(mockRepo, &MockProductRepository{})
+		optionType, err := usecase.CreateOptionType(" Size ", []string{"P", "M", "G"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "size", optionType.Name)
+		assert.Len(t, optionType.Values, 3)
+		assert.Equal(t, 2, optionType.Values[2].Position)
+	})
+
+	t.Run("Repeated Value", func(t *testing.T) {
+		usecase := NewVariantUsecase(&MockVariantRepository{}, &MockProductRepository{})
+		_, err := usecase.CreateOptionType("size", []string{"M", "m"})
+
+		assert.ErrorIs(t, err, ErrOptionValueTaken)
+	})
+
+	t.Run("Name Taken", func(t *testing.T) {
+		mockRepo := &MockVariantRepository{
+			GetOptionTypeByNameFunc: func(name string) (*model.OptionType, error) {
+				return &model.OptionType{ID: 1, Name: name}, nil
+			},
+		}
+
+		usecase := NewVariantUsecase(mockRepo, &MockProductRepository{})
+		_, err := usecase.CreateOptionType("size", nil)
+
+		assert.ErrorIs(t, err, ErrOptionTypeNameTaken)
+	})
+}
+
+func TestVariantUsecase_CreateVariant(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		var created model.Variant
+		mockRepo := &MockVariantRepository{
+			GetOptionValuesByIDsFunc: func(ids []int) ([]model.VariantOption, error) {
+				return []model.VariantOption{colorAzul, sizeG}, nil
+			},
+			GetVariantsFunc: func(productIDs []int) ([]model.Variant, error) {
+				return []model.Variant{{ID: 1, ProductID: 1, SKU: "CAM-AZUL-M", Options: []model.VariantOption{colorAzul, sizeM}}}, nil
+			},
+			CreateVariantFunc: func(variant model.Variant) (int, error) {
+				created = variant
+				return 2, nil
+			},
+			GetVariantByIDFunc: func(id int) (*model.Variant, error) {
+				created.ID = id
+				return &created, nil
+			},
+		}
+		mockProductRepo := &MockProductRepository{GetProductByIdFunc: variantProduct}
+
+		override := "54.90"
+		usecase := NewVariantUsecase(mockRepo, mockProductRepo)
+		variant, err := usecase.CreateVariant(1, model.VariantInput{SKU: "cam-azul-g", PriceOverride: &override, Stock: 5, OptionValueIDs: []int{21, 12}})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, variant.ID)
+		assert.Equal(t, "CAM-AZUL-G", created.SKU)
+		assert.Equal(t, model.Money{Amount: 5490, Currency: "BRL"}, variant.Price)
+	})
+
+	t.Run("Duplicate Combination", func(t *testing.T) {
+		mockRepo := &MockVariantRepository{
+			GetOptionValuesByIDsFunc: func(ids []int) ([]model.VariantOption, error) {
+				return []model.VariantOption{colorAzul, sizeM}, nil
+			},
+			GetVariantsFunc: func(productIDs []int) ([]model.Variant, error) {
+				return []model.Variant{{ID: 1, ProductID: 1, Options: []model.VariantOption{sizeM, colorAzul}}}, nil
+			},
+		}
+		mockProductRepo := &MockProductRepository{GetProductByIdFunc: variantProduct}
+
+		usecase := NewVariantUsecase(mockRepo, mockProductRepo)
+		_, err := usecase.CreateVariant(1, model.VariantInput{SKU: "CAM-2", OptionValueIDs: []int{11, 21}})
+
+		assert.ErrorIs(t, err, ErrDuplicateVariant)
+	})
+
+	t.Run("Different Option Types", func(t *testing.T) {
+		mockRepo := &MockVariantRepository{
+			GetOptionValuesByIDsFunc: func(ids []int) ([]model.VariantOption, error) {
+				return []model.VariantOption{sizeG}, nil
+			},
+			GetVariantsFunc: func(productIDs []int) ([]model.Variant, error) {
+				return []model.Variant{{ID: 1, ProductID: 1, Options: []model.VariantOption{colorAzul, sizeM}}}, nil
+			},
+		}
+		mockProductRepo := &MockProductRepository{GetProductByIdFunc: variantProduct}
+
+		usecase := NewVariantUsecase(mockRepo, mockProductRepo)
+		_, err := usecase.CreateVariant(1, model.VariantInput{SKU: "CAM-G", OptionValueIDs: []int{12}})
+
+		assert.ErrorIs(t, err, ErrInvalidVariantOptions)
+	})
+
+	t.Run("Two Values Of The Same Type", func(t *testing.T) {
+		mockRepo := &MockVariantRepository{
+			GetOptionValuesByIDsFunc: func(ids []int) ([]model.VariantOption, error) {
+				return []model.VariantOption{sizeM, sizeG}, nil
+			},
+		}
+		mockProductRepo := &MockProductRepository{GetProductByIdFunc: variantProduct}
+
+		usecase := NewVariantUsecase(mockRepo, mockProductRepo)
+		_, err := usecase.CreateVariant(1, model.VariantInput{SKU: "CAM-MG", OptionValueIDs: []int{11, 12}})
+
+		assert.ErrorIs(t, err, ErrInvalidVariantOptions)
+	})
+
+	t.Run("SKU Taken", func(t *testing.T) {
+		mockRepo := &MockVariantRepository{
+			GetVariantBySKUFunc: func(sku string) (*model.Variant, error) {
+				return &model.Variant{ID: 9, SKU: sku}, nil
+			},
+		}
+		mockProductRepo := &MockProductRepository{GetProductByIdFunc: variantProduct}
+
+		usecase := NewVariantUsecase(mockRepo, mockProductRepo)
+		_, err := usecase.CreateVariant(1, model.VariantInput{SKU: "CAM-AZUL-M", OptionValueIDs: []int{11}})
+
+		assert.ErrorIs(t, err, ErrSKUTaken)
+	})
+
+	t.Run("Unknown Option Value", func(t *testing.T) {
+		mockRepo := &MockVariantRepository{
+			GetOptionValuesByIDsFunc: func(ids []int) ([]model.VariantOption, error) {
+				return []model.VariantOption{sizeM}, nil
+			},
+		}
+		mockProductRepo := &MockProductRepository{GetProductByIdFunc: variantProduct}
+
+		usecase := NewVariantUsecase(mockRepo, mockProductRepo)
+		_, err := usecase.CreateVariant(1, model.VariantInput{SKU: "CAM-M", OptionValueIDs: []int{11, 99}})
+
+		assert.ErrorIs(t, err, ErrOptionValueNotFound)
+	})
+}
+
+func TestVariantUsecase_UpdateVariant(t *testing.T) {
+	t.Run("Variant Of Another Product", func(t *testing.T) {
+		mockRepo := &MockVariantRepository{
+			GetVariantByIDFunc: func(id int) (*model.Variant, error) {
+				return &model.Variant{ID: id, ProductID: 2, SKU: "OTHER"}, nil
+			},
+		}
+		mockProductRepo := &MockProductRepository{GetProductByIdFunc: variantProduct}
+
+		usecase := NewVariantUsecase(mockRepo, mockProductRepo)
+		_, err := usecase.UpdateVariant(1, 5, model.VariantInput{SKU: "OTHER", Stock: 1})
+
+		assert.ErrorIs(t, err, ErrVariantNotFound)
+	})
+
+	t.Run("Negative Stock", func(t *testing.T) {
+		mockRepo := &MockVariantRepository{
+			GetVariantByIDFunc: func(id int) (*model.Variant, error) {
+				return &model.Variant{ID: id, ProductID: 1, SKU: "CAM-AZUL-M"}, nil
+			},
+		}
+		mockProductRepo := &MockProductRepository{GetProductByIdFunc: variantProduct}
+
+		usecase := NewVariantUsecase(mockRepo, mockProductRepo)
+		_, err := usecase.UpdateVariant(1, 5, model.VariantInput{SKU: "CAM-AZUL-M", Stock: -1})
+
+		assert.ErrorIs(t, err, ErrInvalidStock)
+	})
+}
+
+func TestProductUsecase_GetProductByIdWithVariants(t *testing.T) {
+	override := model.Money{Amount: 5490, Currency: "BRL"}
+	variantRepo := &MockVariantRepository{
+		GetVariantsFunc: func(productIDs []int) ([]model.Variant, error) {
+			return []model.Variant{
+				{ID: 1, ProductID: 1, SKU: "CAM-AZUL-M", Stock: 3, Options: []model.VariantOption{colorAzul, sizeM}},
+				{ID: 2, ProductID: 1, SKU: "CAM-AZUL-G", Stock: 0, PriceOverride: &override, Options: []model.VariantOption{colorAzul, sizeG}},
+			}, nil
+		},
+	}
+	productRepo := &MockProductRepository{GetProductByIdFunc: variantProduct}
+
+	t.Run("Builds Variant Matrix", func(t *testing.T) {
+		usecase := NewProductUsecase(productRepo, &MockPricingRepository{}, variantRepo, &MockImageRepository{}, nil)
+		product, err := usecase.GetProductById(1, model.PriceOptions{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []model.ProductOption{
+			{Name: "color", Values: []string{"Azul"}},
+			{Name: "size", Values: []string{"M", "G"}},
+		}, product.Options)
+		assert.Equal(t, model.Money{Amount: 4990, Currency: "BRL"}, product.Variants[0].Price)
+		assert.Equal(t, override, product.Variants[1].Price)
+	})
+
+	t.Run("Converts Variant Prices", func(t *testing.T) {
+		pricingRepo := &MockPricingRepository{
+			GetExchangeRateFunc: func(base, quote string) (*model.ExchangeRate, error) {
+				return &model.ExchangeRate{Base: base, Quote: quote, Rate: big.NewRat(1, 5), UpdatedAt: time.Now()}, nil
+			},
+		}
+
+		usecase := NewProductUsecase(productRepo, pricingRepo, variantRepo, &MockImageRepository{}, nil)
+		product, err := usecase.GetProductById(1, model.PriceOptions{Currency: "USD"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, model.Money{Amount: 998, Currency: "USD"}, product.Price)
+		assert.Equal(t, model.Money{Amount: 998, Currency: "USD"}, product.Variants[0].Price)
+		assert.Equal(t, model.Money{Amount: 1098, Currency: "USD"}, product.Variants[1].Price)
+	})
+}